@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	httpClient "github.com/k0ns0l/driftwatch/internal/http"
+	"github.com/k0ns0l/driftwatch/internal/security"
+	"github.com/spf13/cobra"
+)
+
+// acceptCmd represents the accept command
+var acceptCmd = &cobra.Command{
+	Use:   "accept <id>",
+	Short: "Accept the live response of an endpoint as its committed baseline",
+	Long: `Fetch an endpoint and overwrite its baseline_file with the live response body.
+
+Endpoints with baseline_strategy: file are compared against the response body
+committed in their baseline_file rather than against a previous run, so drift is
+reported until the file is updated. Accepting a change rewrites the file, and
+committing it makes the change a reviewed part of the API contract.
+
+The changes being accepted are listed before the file is written.
+
+Examples:
+  driftwatch accept users-api
+  driftwatch accept users-api --dry-run   # Show the changes without writing the file`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "dry-run", err)
+		}
+
+		endpointConfig, err := cfg.GetEndpoint(args[0])
+		if err != nil {
+			return err
+		}
+		if endpointConfig.BaselineFile == "" {
+			return fmt.Errorf("endpoint '%s' has no baseline_file configured", endpointConfig.ID)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+
+		client := httpClient.NewClient(httpClient.ClientConfig{
+			Timeout:       cfg.Global.Timeout,
+			RetryCount:    cfg.Global.RetryCount,
+			RetryDelay:    cfg.Global.RetryDelay,
+			MaxRetryDelay: cfg.Global.MaxRetryDelay,
+			UserAgent:     cfg.Global.UserAgent,
+		})
+
+		live, err := performEndpointRequest(context.Background(), cfg, client, *endpointConfig)
+		if err != nil {
+			return fmt.Errorf("failed to fetch endpoint '%s': %w", endpointConfig.ID, err)
+		}
+		if live.StatusCode < 200 || live.StatusCode >= 300 {
+			return fmt.Errorf("endpoint '%s' returned status %d", endpointConfig.ID, live.StatusCode)
+		}
+
+		current, err := security.SafeReadFile(endpointConfig.BaselineFile, cwd)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Printf("Creating baseline file %s\n", endpointConfig.BaselineFile)
+		case err != nil:
+			return fmt.Errorf("failed to read baseline file: %w", err)
+		default:
+			diffResult, err := compareWithGolden(config.ApplySensitivity(*endpointConfig, cfg.Global.Sensitivity), current, live)
+			if err != nil {
+				return err
+			}
+
+			changes := convertDriftToCIChanges(diffResult, false)
+			if len(changes) == 0 {
+				fmt.Printf("✓ %s already matches baseline file %s\n", endpointConfig.ID, endpointConfig.BaselineFile)
+				return nil
+			}
+
+			fmt.Printf("Accepting %d changes to %s:\n", len(changes), endpointConfig.BaselineFile)
+			renderDiff(os.Stdout, changes, colorEnabled(os.Stdout))
+		}
+
+		if dryRun {
+			fmt.Println("Dry run: baseline file not written")
+			return nil
+		}
+
+		if err := writeGoldenFile(endpointConfig.BaselineFile, live.Body, cwd); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Baseline file %s updated from %s %s\n", endpointConfig.BaselineFile, endpointConfig.Method, endpointConfig.URL)
+		if endpointConfig.BaselineStrategy != config.BaselineStrategyFile {
+			fmt.Printf("Note: endpoint '%s' only compares against this file with baseline_strategy: file\n", endpointConfig.ID)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(acceptCmd)
+
+	acceptCmd.Flags().Bool("dry-run", false, "show the changes that would be accepted without writing the baseline file")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// fingerprintPattern matches drift fingerprints as computed by storage.Drift.ComputeFingerprint
+var fingerprintPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// ackFingerprintCmd acknowledges drifts by fingerprint
+var ackFingerprintCmd = &cobra.Command{
+	Use:   "ack-fingerprint <fingerprint>",
+	Short: "Acknowledge every drift with a fingerprint",
+	Long: `Acknowledge every drift with the given fingerprint. A fingerprint identifies an
+exact change: the same endpoint, field path, drift type and before and after
+values. Fingerprints are shown in drift reports.
+
+With --forever, future drifts with the fingerprint are also recorded as
+acknowledged and not alerted on, which silences a known recurring drift.
+
+Examples:
+  driftwatch ack-fingerprint 3f2a9c01d4e5b678
+  driftwatch ack-fingerprint 3f2a9c01d4e5b678 --forever`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fingerprint := strings.ToLower(args[0])
+
+		forever, err := cmd.Flags().GetBool("forever")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "forever", err)
+		}
+
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		acknowledged, err := acknowledgeFingerprint(db, fingerprint, forever)
+		if err != nil {
+			return err
+		}
+
+		if acknowledged == 0 {
+			fmt.Printf("No unacknowledged drifts with fingerprint %s\n", fingerprint)
+		} else {
+			fmt.Printf("✓ Acknowledged %d drift(s) with fingerprint %s\n", acknowledged, fingerprint)
+		}
+		if forever {
+			fmt.Println("  Future drifts with this fingerprint will be acknowledged and not alerted on")
+		}
+		return nil
+	},
+}
+
+// acknowledgeFingerprint acknowledges the drifts with a fingerprint and, with
+// forever, suppresses future ones. It returns the number of drifts acknowledged.
+func acknowledgeFingerprint(db storage.Storage, fingerprint string, forever bool) (int64, error) {
+	if !fingerprintPattern.MatchString(fingerprint) {
+		return 0, fmt.Errorf("invalid fingerprint: %s (expected 16 hexadecimal digits)", fingerprint)
+	}
+
+	acknowledged, err := db.AcknowledgeFingerprint(fingerprint)
+	if err != nil {
+		return 0, err
+	}
+
+	if forever {
+		if err := db.SuppressFingerprint(fingerprint); err != nil {
+			return acknowledged, err
+		}
+	}
+
+	return acknowledged, nil
+}
+
+func init() {
+	rootCmd.AddCommand(ackFingerprintCmd)
+
+	ackFingerprintCmd.Flags().Bool("forever", false, "also acknowledge future drifts with the fingerprint and do not alert on them")
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcknowledgeFingerprint(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	drift := &storage.Drift{EndpointID: "users", DriftType: "field_removed", Severity: "high", FieldPath: "$.email"}
+	require.NoError(t, db.SaveDrift(drift))
+
+	_, err = acknowledgeFingerprint(db, "not-a-fingerprint", false)
+	assert.ErrorContains(t, err, "invalid fingerprint")
+
+	acknowledged, err := acknowledgeFingerprint(db, drift.Fingerprint, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), acknowledged)
+
+	suppressed, err := db.IsFingerprintSuppressed(drift.Fingerprint)
+	require.NoError(t, err)
+	assert.False(t, suppressed)
+
+	acknowledged, err = acknowledgeFingerprint(db, drift.Fingerprint, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), acknowledged)
+
+	suppressed, err = db.IsFingerprintSuppressed(drift.Fingerprint)
+	require.NoError(t, err)
+	assert.True(t, suppressed)
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/alerting"
-	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -81,7 +80,7 @@ func runAlertTest(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create storage instance
-	storage, err := storage.NewStorage(cfg.Global.DatabaseURL)
+	storage, err := openStorage(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create storage: %w", err)
 	}
@@ -132,7 +131,7 @@ func runAlertHistory(cmd *cobra.Command, args []string) error {
 
 // initializeAlertManager creates storage and alert manager instances
 func initializeAlertManager() (alerting.AlertManager, error) {
-	storage, err := storage.NewStorage(cfg.Global.DatabaseURL)
+	storage, err := openStorage(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage: %w", err)
 	}
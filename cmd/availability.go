@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// availabilityBuckets is how many intervals health --history splits its
+// period into
+const availabilityBuckets = 24
+
+// availabilityLevels are the glyphs of an availability strip, from buckets
+// where every check failed to buckets where every check succeeded
+var availabilityLevels = []rune("▁▂▃▄▅▆▇")
+
+// availabilityNoData marks buckets without checks in an availability strip
+const availabilityNoData = '·'
+
+// AvailabilityHistory is the availability of an endpoint over a period, split
+// into equal intervals oldest first
+type AvailabilityHistory struct {
+	Period       string               `json:"period" yaml:"period"`
+	Availability float64              `json:"availability" yaml:"availability"` // Percentage of successful checks over the period
+	Checks       int                  `json:"checks" yaml:"checks"`
+	Buckets      []AvailabilityBucket `json:"buckets" yaml:"buckets"`
+}
+
+// AvailabilityBucket counts the checks of an endpoint during one interval
+type AvailabilityBucket struct {
+	Start     time.Time `json:"start" yaml:"start"`
+	Checks    int       `json:"checks" yaml:"checks"`
+	Successes int       `json:"successes" yaml:"successes"`
+}
+
+// addAvailabilityHistory attaches to each endpoint of a status report the
+// availability of its checks over the period ending at now
+func addAvailabilityHistory(db storage.Storage, report *StatusReport, period time.Duration, now time.Time) error {
+	for i := range report.Endpoints {
+		runs, err := db.GetMonitoringHistory(report.Endpoints[i].ID, period)
+		if err != nil {
+			return fmt.Errorf("failed to get monitoring history for %s: %w", report.Endpoints[i].ID, err)
+		}
+		report.Endpoints[i].History = buildAvailabilityHistory(runs, period, now)
+	}
+	return nil
+}
+
+// buildAvailabilityHistory buckets the runs of the period ending at now by
+// when they were checked
+func buildAvailabilityHistory(runs []*storage.MonitoringRun, period time.Duration, now time.Time) *AvailabilityHistory {
+	start := now.Add(-period)
+	width := period / availabilityBuckets
+
+	history := &AvailabilityHistory{
+		Period:  formatPeriod(period),
+		Buckets: make([]AvailabilityBucket, availabilityBuckets),
+	}
+	for i := range history.Buckets {
+		history.Buckets[i].Start = start.Add(time.Duration(i) * width)
+	}
+
+	successes := 0
+	for _, run := range runs {
+		for _, checkedAt := range checkTimes(run) {
+			if checkedAt.Before(start) || checkedAt.After(now) {
+				continue
+			}
+
+			index := int(checkedAt.Sub(start) / width)
+			if index >= availabilityBuckets {
+				index = availabilityBuckets - 1
+			}
+
+			history.Buckets[index].Checks++
+			history.Checks++
+			if run.Succeeded() {
+				history.Buckets[index].Successes++
+				successes++
+			}
+		}
+	}
+
+	if history.Checks > 0 {
+		history.Availability = float64(successes) / float64(history.Checks) * 100
+	}
+	return history
+}
+
+// checkTimes returns when the checks of a run were made. The checks of runs
+// merged by compaction are spread evenly between the first and the last.
+func checkTimes(run *storage.MonitoringRun) []time.Time {
+	checks := run.Checks()
+	var step time.Duration
+	if checks > 1 && run.LastTimestamp.After(run.Timestamp) {
+		step = run.LastTimestamp.Sub(run.Timestamp) / time.Duration(checks-1)
+	}
+
+	times := make([]time.Time, checks)
+	for i := range times {
+		times[i] = run.Timestamp.Add(time.Duration(i) * step)
+	}
+	return times
+}
+
+// availabilityStrip renders the buckets of a history as one glyph each, higher
+// the more of its checks succeeded
+func availabilityStrip(history *AvailabilityHistory) string {
+	var strip strings.Builder
+	for _, bucket := range history.Buckets {
+		if bucket.Checks == 0 {
+			strip.WriteRune(availabilityNoData)
+			continue
+		}
+
+		level := bucket.Successes * (len(availabilityLevels) - 1) / bucket.Checks
+		strip.WriteRune(availabilityLevels[level])
+	}
+	return strip.String()
+}
+
+// outputAvailabilityTable outputs the availability strips of a status report
+func outputAvailabilityTable(report *StatusReport) {
+	var period string
+	for _, ep := range report.Endpoints {
+		if ep.History != nil {
+			period = ep.History.Period
+			break
+		}
+	}
+	if period == "" {
+		return
+	}
+
+	fmt.Printf("\nAVAILABILITY (last %s, oldest first)\n", period)
+	fmt.Println(strings.Repeat("-", 85))
+	for _, ep := range report.Endpoints {
+		if ep.History == nil {
+			continue
+		}
+
+		availability := "N/A"
+		if ep.History.Checks > 0 {
+			availability = fmt.Sprintf("%.1f%%", ep.History.Availability)
+		}
+		fmt.Printf("%-20s %s %7s  (%d checks)\n",
+			ep.ID,
+			availabilityStrip(ep.History),
+			availability,
+			ep.History.Checks)
+	}
+}
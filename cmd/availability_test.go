@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAvailabilityHistory(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	run := func(ago time.Duration, status int) *storage.MonitoringRun {
+		return &storage.MonitoringRun{EndpointID: "api", Timestamp: now.Add(-ago), ResponseStatus: status}
+	}
+
+	// Newest first, as stored history is returned
+	runs := []*storage.MonitoringRun{
+		run(10*time.Minute, 200),
+		run(20*time.Minute, 503),
+		run(90*time.Minute, 0),
+		run(23*time.Hour+30*time.Minute, 200),
+		run(25*time.Hour, 200), // Outside the period
+	}
+
+	history := buildAvailabilityHistory(runs, 24*time.Hour, now)
+	require.Len(t, history.Buckets, availabilityBuckets)
+	assert.Equal(t, "1 day", history.Period)
+	assert.Equal(t, 4, history.Checks)
+	assert.InDelta(t, 50, history.Availability, 0.001)
+
+	assert.Equal(t, AvailabilityBucket{Start: now.Add(-24 * time.Hour), Checks: 1, Successes: 1}, history.Buckets[0])
+	assert.Equal(t, AvailabilityBucket{Start: now.Add(-2 * time.Hour), Checks: 1}, history.Buckets[22])
+	assert.Equal(t, AvailabilityBucket{Start: now.Add(-time.Hour), Checks: 2, Successes: 1}, history.Buckets[23])
+
+	assert.Equal(t, "▇"+strings.Repeat("·", 21)+"▁▄", availabilityStrip(history))
+}
+
+func TestBuildAvailabilityHistoryCompactedRuns(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Three checks merged into one run, from five to three hours ago
+	runs := []*storage.MonitoringRun{{
+		EndpointID:     "api",
+		Timestamp:      now.Add(-5 * time.Hour),
+		LastTimestamp:  now.Add(-3 * time.Hour),
+		CheckCount:     3,
+		ResponseStatus: 200,
+	}}
+
+	history := buildAvailabilityHistory(runs, 24*time.Hour, now)
+	assert.Equal(t, 3, history.Checks)
+	assert.InDelta(t, 100, history.Availability, 0.001)
+	for _, index := range []int{19, 20, 21} {
+		assert.Equal(t, 1, history.Buckets[index].Checks, "bucket %d", index)
+	}
+}
+
+func TestAddAvailabilityHistory(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	require.NoError(t, db.SaveEndpoint(&storage.Endpoint{ID: "api", URL: "https://api.example.com", Method: "GET"}))
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{EndpointID: "api", Timestamp: now.Add(-time.Hour), ResponseStatus: 200}))
+
+	report := generateStatusReport(db, []string{"api"}, false)
+	require.NoError(t, addAvailabilityHistory(db, report, 24*time.Hour, now))
+
+	require.Len(t, report.Endpoints, 1)
+	require.NotNil(t, report.Endpoints[0].History)
+	assert.Equal(t, 1, report.Endpoints[0].History.Checks)
+	assert.InDelta(t, 100, report.Endpoints[0].History.Availability, 0.001)
+}
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -35,7 +34,7 @@ Examples:
 		logger := GetLogger()
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -58,6 +58,7 @@ type baselineCaptureOptions struct {
 	includeHeaders bool
 	includeBody    bool
 	overwrite      bool
+	caBundleFile   string // global.ca_bundle_file, for endpoints without a ca_file
 }
 
 func parseBaselineCaptureFlags(cmd *cobra.Command) (*baselineCaptureOptions, error) {
@@ -108,6 +109,23 @@ func captureEndpointBaseline(endpointConfig config.EndpointConfig, client httpCl
 	ctx, cancel := context.WithTimeout(context.Background(), reqTimeout)
 	defer cancel()
 
+	ctx, err = httpClient.WithProxy(ctx, endpointConfig.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+
+	caFile := endpointConfig.CAFile
+	if caFile == "" {
+		caFile = opts.caBundleFile
+	}
+	ctx = httpClient.WithCABundle(ctx, caFile)
+	ctx = httpClient.WithPhaseTimeouts(ctx, endpointConfig.ConnectTimeout, endpointConfig.ReadTimeout)
+
+	ctx, err = httpClient.WithStreamReadLimit(ctx, endpointConfig.StreamReadLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream read limit: %w", err)
+	}
+
 	// Perform request
 	startTime := time.Now()
 	resp, err := client.Do(req.WithContext(ctx))
@@ -120,11 +138,17 @@ func captureEndpointBaseline(endpointConfig config.EndpointConfig, client httpCl
 		StatusCode:   resp.StatusCode,
 		ResponseTime: resp.ResponseTime,
 		Timestamp:    startTime,
+		Timing:       convertTiming(resp.Timing),
+		Truncated:    resp.Truncated,
 	}
 
-	// Include headers if requested
+	// Include headers, along with the protocol and trailers, if requested
 	if opts.includeHeaders {
 		baselineResponse.Headers = convertHeaders(resp.Headers)
+		baselineResponse.Protocol = resp.Protocol
+		if len(resp.Trailers) > 0 {
+			baselineResponse.Trailers = convertHeaders(resp.Trailers)
+		}
 	}
 
 	// Include body if requested
@@ -205,6 +229,8 @@ func runBaselineCapture(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("configuration not loaded")
 	}
 
+	opts.caBundleFile = cfg.Global.CABundleFile
+
 	// Filter endpoints if specified
 	if len(opts.endpointIDs) > 0 {
 		if err := filterEndpoints(cfg, opts.endpointIDs); err != nil {
@@ -214,18 +240,20 @@ func runBaselineCapture(cmd *cobra.Command, _ []string) error {
 
 	// Create HTTP client
 	client := httpClient.NewClient(httpClient.ClientConfig{
-		Timeout:    opts.timeout,
-		RetryCount: cfg.Global.RetryCount,
-		RetryDelay: cfg.Global.RetryDelay,
-		UserAgent:  cfg.Global.UserAgent,
+		Timeout:       opts.timeout,
+		RetryCount:    cfg.Global.RetryCount,
+		RetryDelay:    cfg.Global.RetryDelay,
+		MaxRetryDelay: cfg.Global.MaxRetryDelay,
+		UserAgent:     cfg.Global.UserAgent,
 	})
 
 	// Capture baseline data
-	fmt.Printf("Capturing baseline data for %d endpoints...\n", len(cfg.Endpoints))
+	endpoints := cfg.ExpandedEndpoints()
+	fmt.Printf("Capturing baseline data for %d endpoints...\n", len(endpoints))
 
 	baselineData := make(map[string]*drift.Response)
 
-	for _, endpointConfig := range cfg.Endpoints {
+	for _, endpointConfig := range endpoints {
 		if !endpointConfig.Enabled {
 			fmt.Printf("Skipping disabled endpoint: %s\n", endpointConfig.ID)
 			continue
@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +21,7 @@ import (
 	"github.com/k0ns0l/driftwatch/internal/monitor"
 	"github.com/k0ns0l/driftwatch/internal/security"
 	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/k0ns0l/driftwatch/internal/validator"
 	"github.com/spf13/cobra"
 )
 
@@ -45,14 +52,44 @@ Exit Codes:
   4 - Network error
   5 - Validation error
 
+Without a baseline file, each response is compared against a stored run chosen by
+the endpoint's baseline_strategy: previous (the latest successful run, default),
+fixed (the run pinned by baseline_run_id), n_ago (the successful run
+baseline_runs_ago successful runs back), majority (the latest run with the most
+common response shape among the last baseline_runs successful runs) or file (the
+response body committed in baseline_file, updated with driftwatch accept). Failed
+checks are never used as baselines.
+
+Responses of endpoints with a spec_file are also validated against the OpenAPI
+operation for their method and path. Validation errors are reported with each
+endpoint and only affect the exit code with --fail-on-validation.
+
+--fail-on takes a severity, failing on any change of that severity or above, or
+comma-separated severity:count thresholds such as high:3,critical:1, failing once
+any is reached. Counts are of changes of exactly that severity.
+
+--format sarif reports each change as a SARIF 2.1.0 result for code scanning,
+such as GitHub's: the change type is the rule, the severity sets the level
+(critical and high are errors, medium warnings, low notes) and the changed field
+is the location, along with the endpoint's definition in the config file.
+Code scanning links results only to files in the repository, so run it from the
+repository root with the config inside it.
+
 Examples:
   driftwatch ci                        # Run CI check with default settings
   driftwatch ci --format json         # Output results in JSON format
   driftwatch ci --format junit        # Output results in JUnit XML format
+  driftwatch ci --format ndjson       # One JSON line per endpoint plus a summary line
+  driftwatch ci --format diff         # Human-readable diff, colorized on a terminal
+  driftwatch ci --format sarif -o drift.sarif  # SARIF 2.1.0 for code scanning
   driftwatch ci --fail-on high        # Fail on high severity changes or above
+  driftwatch ci --fail-on high:3,critical:1  # Fail on 3 high or any critical changes
+  driftwatch ci --fail-on-validation  # Fail when responses violate the endpoint's OpenAPI spec
   driftwatch ci --timeout 60s         # Set timeout for the entire operation
   driftwatch ci --no-storage          # Run without persistent storage
-  driftwatch ci --endpoints api1,api2 # Check specific endpoints only`,
+  driftwatch ci --endpoints api1,api2 # Check specific endpoints only
+  driftwatch ci --explain             # Include why each change was classified as it was
+  driftwatch ci --baseline-from-git origin/main:baseline.json  # Use baseline from a git ref`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runCIMode(cmd, args)
 	},
@@ -71,8 +108,12 @@ type CIResult struct {
 	HighChanges      int                `json:"high_changes"`
 	MediumChanges    int                `json:"medium_changes"`
 	LowChanges       int                `json:"low_changes"`
+	ValidationErrors int                `json:"validation_errors"`
 	ExitCode         int                `json:"exit_code"`
 	Success          bool               `json:"success"`
+
+	// ComparisonCache reports comparison cache hits and misses when the cache is enabled
+	ComparisonCache *drift.CacheMetrics `json:"comparison_cache,omitempty"`
 }
 
 // CIEndpointResult represents the result for a single endpoint
@@ -84,8 +125,10 @@ type CIEndpointResult struct {
 	Method           string                    `json:"method"`
 	Error            string                    `json:"error,omitempty"`
 	ResponseTime     time.Duration             `json:"response_time,omitempty"`
+	VolatileFields   []string                  `json:"volatile_fields,omitempty"` // Fields that varied between samples and were ignored
 	StatusCode       int                       `json:"status_code,omitempty"`
 	BreakingChanges  int                       `json:"breaking_changes"`
+	Samples          int                       `json:"samples,omitempty"`
 	Success          bool                      `json:"success"`
 }
 
@@ -97,7 +140,11 @@ type CIChange struct {
 	Description string `json:"description"`
 	OldValue    string `json:"old_value,omitempty"`
 	NewValue    string `json:"new_value,omitempty"`
+	Mitigation  string `json:"mitigation,omitempty"` // How clients can cope with a breaking change
 	Breaking    bool   `json:"breaking"`
+
+	// Explanation is set with --explain for changes to body fields
+	Explanation *drift.ChangeExplanation `json:"explanation,omitempty"`
 }
 
 // JUnitTestSuite represents a JUnit XML test suite
@@ -120,6 +167,7 @@ type JUnitTestCase struct {
 	Failure   *JUnitFailure `xml:"failure,omitempty"`
 	Error     *JUnitError   `xml:"error,omitempty"`
 	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
 }
 
 // JUnitFailure represents a JUnit XML failure
@@ -140,15 +188,18 @@ func init() {
 	rootCmd.AddCommand(ciCmd)
 
 	// CI command flags
-	ciCmd.Flags().StringP("format", "f", "json", "output format (json, junit, summary)")
-	ciCmd.Flags().String("fail-on", "high", "minimum severity to fail on (low, medium, high, critical)")
+	ciCmd.Flags().StringP("format", "f", "json", "output format (json, ndjson, junit, sarif, summary, diff)")
+	ciCmd.Flags().String("fail-on", "high", "minimum severity to fail on (low, medium, high, critical), or counts such as high:3,critical:1")
 	ciCmd.Flags().Duration("timeout", 5*time.Minute, "timeout for the entire CI operation")
 	ciCmd.Flags().Bool("no-storage", false, "run without persistent storage (in-memory only)")
 	ciCmd.Flags().StringSlice("endpoints", []string{}, "specific endpoints to check (comma-separated)")
 	ciCmd.Flags().Bool("fail-on-breaking", true, "fail if any breaking changes are detected")
+	ciCmd.Flags().Bool("fail-on-validation", false, "fail if any response violates its endpoint's OpenAPI spec")
 	ciCmd.Flags().Bool("include-performance", false, "include performance changes in results")
 	ciCmd.Flags().String("baseline-file", "", "JSON file containing baseline responses for comparison")
+	ciCmd.Flags().String("baseline-from-git", "", "load baseline responses from a git object (ref:path)")
 	ciCmd.Flags().String("output-file", "", "write results to file instead of stdout")
+	ciCmd.Flags().Bool("explain", false, "include the reasoning, confidence and heuristics behind each change's classification")
 }
 
 // runCIMode executes the CI/CD mode
@@ -170,7 +221,7 @@ func runCIMode(cmd *cobra.Command, _ []string) error {
 	}
 	defer db.Close()
 
-	baselineData, err := loadCIBaseline(ciOptions.BaselineFile)
+	baselineData, err := loadCIBaseline(ciOptions.BaselineFile, ciOptions.BaselineFromGit)
 	if err != nil {
 		exitWithCode(ExitCodeConfigError, fmt.Sprintf("failed to load baseline data: %v", err))
 		return nil
@@ -181,11 +232,11 @@ func runCIMode(cmd *cobra.Command, _ []string) error {
 		return nil
 	}
 
-	result := performCICheck(ctx, cfg, db, client, baselineData, ciOptions.IncludePerformance)
+	result := performCICheck(ctx, cfg, db, client, baselineData, ciOptions.IncludePerformance, ciOptions.Explain)
 
 	finalizeCIResult(result, startTime, ciOptions)
 
-	if err := outputCIResults(result, ciOptions.OutputFormat, ciOptions.OutputFile); err != nil {
+	if err := outputCIResults(cfg, result, ciOptions.OutputFormat, ciOptions.OutputFile); err != nil {
 		exitWithCode(ExitCodeGeneralError, fmt.Sprintf("failed to output results: %v", err))
 		return nil
 	}
@@ -199,11 +250,14 @@ type CIOptions struct {
 	OutputFormat       string
 	FailOnSeverity     string
 	BaselineFile       string
+	BaselineFromGit    string
 	OutputFile         string
 	Timeout            time.Duration
 	NoStorage          bool
 	FailOnBreaking     bool
+	FailOnValidation   bool
 	IncludePerformance bool
+	Explain            bool
 	EndpointIDs        []string
 }
 
@@ -230,22 +284,39 @@ func parseCIFlags(cmd *cobra.Command) (*CIOptions, error) {
 	if options.FailOnBreaking, err = cmd.Flags().GetBool("fail-on-breaking"); err != nil {
 		return nil, fmt.Errorf("failed to get fail-on-breaking flag: %w", err)
 	}
+	if options.FailOnValidation, err = cmd.Flags().GetBool("fail-on-validation"); err != nil {
+		return nil, fmt.Errorf("failed to get fail-on-validation flag: %w", err)
+	}
 	if options.IncludePerformance, err = cmd.Flags().GetBool("include-performance"); err != nil {
 		return nil, fmt.Errorf("failed to get include-performance flag: %w", err)
 	}
 	if options.BaselineFile, err = cmd.Flags().GetString("baseline-file"); err != nil {
 		return nil, fmt.Errorf("failed to get baseline-file flag: %w", err)
 	}
+	if options.BaselineFromGit, err = cmd.Flags().GetString("baseline-from-git"); err != nil {
+		return nil, fmt.Errorf("failed to get baseline-from-git flag: %w", err)
+	}
 	if options.OutputFile, err = cmd.Flags().GetString("output-file"); err != nil {
 		return nil, fmt.Errorf("failed to get output-file flag: %w", err)
 	}
+	if options.Explain, err = cmd.Flags().GetBool("explain"); err != nil {
+		return nil, fmt.Errorf("failed to get explain flag: %w", err)
+	}
 
 	return options, nil
 }
 
 // validateCIOptions validates CI command options
 func validateCIOptions(options *CIOptions) error {
-	validFormats := []string{"json", "junit", "summary"}
+	if options.BaselineFile != "" && options.BaselineFromGit != "" {
+		return fmt.Errorf("--baseline-file and --baseline-from-git cannot be used together")
+	}
+
+	if _, err := parseFailOn(options.FailOnSeverity); err != nil {
+		return err
+	}
+
+	validFormats := []string{"json", "ndjson", "junit", "sarif", "summary", "diff"}
 	for _, validFormat := range validFormats {
 		if strings.ToLower(options.OutputFormat) == validFormat {
 			return nil
@@ -271,7 +342,7 @@ func initializeCIEnvironment(options *CIOptions) (*config.Config, context.Contex
 	if options.NoStorage {
 		db, err = storage.NewInMemoryStorage()
 	} else {
-		db, err = storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err = openStorage(cfg)
 	}
 
 	if err != nil {
@@ -280,17 +351,21 @@ func initializeCIEnvironment(options *CIOptions) (*config.Config, context.Contex
 	}
 
 	client := httpClient.NewClient(httpClient.ClientConfig{
-		Timeout:    cfg.Global.Timeout,
-		RetryCount: cfg.Global.RetryCount,
-		RetryDelay: cfg.Global.RetryDelay,
-		UserAgent:  cfg.Global.UserAgent,
+		Timeout:       cfg.Global.Timeout,
+		RetryCount:    cfg.Global.RetryCount,
+		RetryDelay:    cfg.Global.RetryDelay,
+		MaxRetryDelay: cfg.Global.MaxRetryDelay,
+		UserAgent:     cfg.Global.UserAgent,
 	})
 
 	return cfg, ctx, db, client, nil
 }
 
 // loadCIBaseline loads baseline data if provided
-func loadCIBaseline(baselineFile string) (map[string]*drift.Response, error) {
+func loadCIBaseline(baselineFile, baselineFromGit string) (map[string]*drift.Response, error) {
+	if baselineFromGit != "" {
+		return loadBaselineFromGit(baselineFromGit)
+	}
 	if baselineFile == "" {
 		return nil, nil
 	}
@@ -310,55 +385,252 @@ func finalizeCIResult(result *CIResult, startTime time.Time, options *CIOptions)
 	result.Duration = time.Since(startTime)
 	result.Timestamp = startTime
 
-	exitCode := determineExitCode(result, options.FailOnSeverity, options.FailOnBreaking)
+	exitCode := determineExitCode(result, options.FailOnSeverity, options.FailOnBreaking, options.FailOnValidation)
 	result.ExitCode = exitCode
 	result.Success = exitCode == ExitCodeSuccess
 	result.Summary = generateCISummary(result)
 }
 
 // performCICheck performs the actual CI check
-func performCICheck(ctx context.Context, cfg *config.Config, db storage.Storage, client httpClient.Client, baselineData map[string]*drift.Response, includePerformance bool) *CIResult {
+func performCICheck(ctx context.Context, cfg *config.Config, db storage.Storage, client httpClient.Client, baselineData map[string]*drift.Response, includePerformance, explain bool) *CIResult {
+	endpoints := cfg.ExpandedEndpoints()
 	result := &CIResult{
-		Endpoints: make([]CIEndpointResult, 0, len(cfg.Endpoints)),
+		Endpoints: make([]CIEndpointResult, 0, len(endpoints)),
 	}
 
-	diffEngine := drift.NewDiffEngine()
+	// One cache serves all endpoints, so endpoints returning the same bodies as
+	// their baselines under the same options are compared once
+	var cache *drift.ComparisonCache
+	if cfg.Global.ComparisonCacheSize > 0 {
+		cache = drift.NewComparisonCache(cfg.Global.ComparisonCacheSize)
+	}
 
-	for _, endpointConfig := range cfg.Endpoints {
+	for _, endpointConfig := range endpoints {
 		if !endpointConfig.Enabled {
 			continue
 		}
+		endpointConfig = config.ApplySensitivity(endpointConfig, cfg.Global.Sensitivity)
+
+		diffOptions, err := diffOptionsForEndpoint(endpointConfig)
+		if err != nil {
+			result.Endpoints = append(result.Endpoints, CIEndpointResult{
+				ID:     endpointConfig.ID,
+				URL:    endpointConfig.URL,
+				Method: endpointConfig.Method,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		diffOptions.Explain = explain
+		diffOptions.Cache = cache
 
-		endpointResult := checkSingleEndpoint(ctx, cfg, db, client, diffEngine, endpointConfig, baselineData, includePerformance)
+		endpointResult := checkSingleEndpoint(ctx, cfg, db, client, diffOptions, endpointConfig, baselineData, includePerformance)
 		result.Endpoints = append(result.Endpoints, endpointResult)
 	}
 
+	if cache != nil {
+		metrics := cache.GetMetrics()
+		result.ComparisonCache = &metrics
+	}
+
 	calculateCITotals(result)
 	return result
 }
 
+// diffOptionsForEndpoint builds drift comparison options from endpoint configuration.
+// Required fields come from the endpoint's validation settings and, when a spec
+// file is configured, from the required properties of the success response schema,
+// which also provides the enum values of constrained fields. Only the configured
+// required fields are asserted to be present in every response. Sensitivity
+// presets are not applied here; callers apply them to the endpoint first.
+func diffOptionsForEndpoint(endpointConfig config.EndpointConfig) (drift.DiffOptions, error) {
+	options := drift.DiffOptions{
+		CompareRoot:     endpointConfig.CompareRoot,
+		IgnoreFields:    append([]string{}, endpointConfig.Validation.IgnoreFields...),
+		RequiredFields:  append([]string{}, endpointConfig.Validation.RequiredFields...),
+		AssertedFields:  append([]string{}, endpointConfig.Validation.RequiredFields...),
+		VolatileCookies: append([]string{}, endpointConfig.Validation.VolatileCookies...),
+
+		EmbeddedJSONFields:  append([]string{}, endpointConfig.Validation.EmbeddedJSONFields...),
+		NullAsMissing:       endpointConfig.Validation.TreatsNullAsMissing(),
+		ShapeOnly:           endpointConfig.Validation.ComparesShapeOnly(),
+		UnorderedArrays:     append([]string{}, endpointConfig.Validation.UnorderedArrays...),
+		TrackedFields:       append([]string{}, endpointConfig.Validation.TrackedFields...),
+		HeaderPatterns:      endpointConfig.Validation.HeaderPatterns,
+		IgnoreValuePatterns: append([]string{}, endpointConfig.Validation.IgnoreValuePatterns...),
+		BodyStatusCodes:     append([]int{}, endpointConfig.Validation.BodyStatusCodes...),
+		StatusMatch:         endpointConfig.Validation.StatusMatch,
+		NumericTolerance:    endpointConfig.Validation.NumericTolerance,
+		AuthConfigured:      endpointConfig.Auth != nil && endpointConfig.Auth.Type != config.AuthTypeNone,
+
+		ArraySummaryThreshold: endpointConfig.Validation.ArraySummaryThreshold,
+
+		VersionField:          endpointConfig.Validation.VersionField,
+		VersionChangeSeverity: drift.Severity(endpointConfig.Validation.VersionChangeSeverity),
+
+		PerformanceMode:       string(endpointConfig.PerformanceMode),
+		ResponseTimeThreshold: endpointConfig.PerformanceThreshold,
+		ZScoreThreshold:       endpointConfig.PerformanceZScore,
+	}
+
+	if endpointConfig.SpecFile == "" {
+		return options, nil
+	}
+
+	swagger, err := validator.NewValidator().LoadSpec(endpointConfig.SpecFile)
+	if err != nil {
+		return options, fmt.Errorf("failed to load spec for endpoint %s: %w", endpointConfig.ID, err)
+	}
+
+	parsedURL, err := url.Parse(endpointConfig.URL)
+	if err != nil {
+		return options, fmt.Errorf("invalid URL for endpoint %s: %w", endpointConfig.ID, err)
+	}
+
+	operation := validator.FindOperation(swagger, endpointConfig.Method, parsedURL.Path)
+	options.ResponseSchema = validator.ResponseSchema(operation)
+	options.EnumValues = validator.EnumResponseValues(operation)
+
+	return options, nil
+}
+
 // checkSingleEndpoint performs CI check for a single endpoint
-func checkSingleEndpoint(ctx context.Context, cfg *config.Config, db storage.Storage, client httpClient.Client, diffEngine drift.DiffEngine, endpointConfig config.EndpointConfig, baselineData map[string]*drift.Response, includePerformance bool) CIEndpointResult {
+// When the endpoint takes several samples, fields that vary between the samples
+// are ignored for this check and the last sample is compared against the baseline.
+func checkSingleEndpoint(ctx context.Context, cfg *config.Config, db storage.Storage, client httpClient.Client, diffOptions drift.DiffOptions, endpointConfig config.EndpointConfig, baselineData map[string]*drift.Response, includePerformance bool) CIEndpointResult {
 	endpointResult := CIEndpointResult{
 		ID:     endpointConfig.ID,
 		URL:    endpointConfig.URL,
 		Method: endpointConfig.Method,
 	}
 
-	currentResponse, err := performEndpointRequest(ctx, cfg, client, endpointConfig)
+	samples, err := collectEndpointSamples(ctx, cfg, client, endpointConfig)
 	if err != nil {
 		endpointResult.Error = err.Error()
 		return endpointResult
 	}
+	currentResponse := samples[len(samples)-1]
 
 	endpointResult.Success = true
 	endpointResult.StatusCode = currentResponse.StatusCode
 	endpointResult.ResponseTime = currentResponse.ResponseTime
+	endpointResult.Samples = len(samples)
+
+	if endpointConfig.SpecFile != "" {
+		validationErrors, err := validateCIResponse(endpointConfig, currentResponse)
+		if err != nil {
+			endpointResult.Error = err.Error()
+			return endpointResult
+		}
+		endpointResult.ValidationErrors = validationErrors
+	}
 
+	volatileFields, err := drift.FindVaryingFields(samples, diffOptions.Cache)
+	if err != nil {
+		endpointResult.Error = err.Error()
+		return endpointResult
+	}
+	endpointResult.VolatileFields = volatileFields
+	diffOptions.IgnoreFields = append(append([]string{}, diffOptions.IgnoreFields...), volatileFields...)
+
+	if endpointConfig.PerformanceMode == config.PerformanceModeZScore {
+		diffOptions.ResponseTimeHistory, err = responseTimeHistory(db, endpointConfig)
+		if err != nil {
+			endpointResult.Error = err.Error()
+			return endpointResult
+		}
+	}
+
+	diffEngine := drift.NewDiffEngineWithOptions(diffOptions)
 	performDriftComparison(&endpointResult, diffEngine, db, endpointConfig, currentResponse, baselineData, includePerformance)
 	return endpointResult
 }
 
+// validateCIResponse validates a response against the operation of the endpoint's
+// spec file matching its method and path. Strict mode also rejects fields and
+// status codes the spec does not define.
+func validateCIResponse(endpointConfig config.EndpointConfig, response *drift.Response) ([]monitor.ValidationError, error) {
+	specValidator := validator.NewValidator()
+	if endpointConfig.Validation.StrictMode {
+		specValidator.SetValidationMode(validator.ValidationModeStrict)
+	}
+	if err := specValidator.SetHeaderPatterns(endpointConfig.Validation.HeaderPatterns); err != nil {
+		return nil, fmt.Errorf("invalid header patterns for endpoint %s: %w", endpointConfig.ID, err)
+	}
+
+	swagger, err := specValidator.LoadSpec(endpointConfig.SpecFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec for endpoint %s: %w", endpointConfig.ID, err)
+	}
+
+	parsedURL, err := url.Parse(endpointConfig.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL for endpoint %s: %w", endpointConfig.ID, err)
+	}
+
+	operation := validator.FindOperation(swagger, endpointConfig.Method, parsedURL.Path)
+	if operation == nil {
+		return []monitor.ValidationError{{
+			Field:   "operation",
+			Message: fmt.Sprintf("%s %s is not defined in %s", endpointConfig.Method, parsedURL.Path, endpointConfig.SpecFile),
+			Type:    "undefined_operation",
+		}}, nil
+	}
+
+	headers := make(http.Header, len(response.Headers))
+	for key, value := range response.Headers {
+		headers.Set(key, value)
+	}
+
+	validation, err := specValidator.ValidateResponse(&validator.Response{
+		StatusCode: response.StatusCode,
+		Headers:    headers,
+		Body:       response.Body,
+	}, operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate response for endpoint %s: %w", endpointConfig.ID, err)
+	}
+
+	var validationErrors []monitor.ValidationError
+	for _, validationError := range validation.Errors {
+		validationErrors = append(validationErrors, monitor.ValidationError{
+			Field:   validationError.Field,
+			Message: validationError.Message,
+			Type:    validationError.Type,
+		})
+	}
+
+	return validationErrors, nil
+}
+
+// collectEndpointSamples requests an endpoint as many times as its samples setting
+// asks for, pausing between requests
+func collectEndpointSamples(ctx context.Context, cfg *config.Config, client httpClient.Client, endpointConfig config.EndpointConfig) ([]*drift.Response, error) {
+	sampleCount := endpointConfig.Samples
+	if sampleCount < 1 {
+		sampleCount = 1
+	}
+
+	samples := make([]*drift.Response, 0, sampleCount)
+	for i := 0; i < sampleCount; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("sampling cancelled: %v", ctx.Err())
+			case <-time.After(config.SampleDelay):
+			}
+		}
+
+		response, err := performEndpointRequest(ctx, cfg, client, endpointConfig)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, response)
+	}
+
+	return samples, nil
+}
+
 // performEndpointRequest executes HTTP request for an endpoint
 func performEndpointRequest(ctx context.Context, cfg *config.Config, client httpClient.Client, endpointConfig config.EndpointConfig) (*drift.Response, error) {
 	req, err := httpClient.NewRequest(endpointConfig.Method, endpointConfig.URL, nil, endpointConfig.Headers)
@@ -374,19 +646,44 @@ func performEndpointRequest(ctx context.Context, cfg *config.Config, client http
 	reqCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	reqCtx, err = httpClient.WithProxy(reqCtx, endpointConfig.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %v", err)
+	}
+
+	caFile := endpointConfig.CAFile
+	if caFile == "" {
+		caFile = cfg.Global.CABundleFile
+	}
+	reqCtx = httpClient.WithCABundle(reqCtx, caFile)
+	reqCtx = httpClient.WithPhaseTimeouts(reqCtx, endpointConfig.ConnectTimeout, endpointConfig.ReadTimeout)
+
+	reqCtx, err = httpClient.WithStreamReadLimit(reqCtx, endpointConfig.StreamReadLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stream read limit: %v", err)
+	}
+
 	startTime := time.Now()
 	resp, err := client.Do(req.WithContext(reqCtx))
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %v", err)
 	}
 
-	return &drift.Response{
+	response := &drift.Response{
 		StatusCode:   resp.StatusCode,
 		Headers:      convertHeaders(resp.Headers),
 		Body:         resp.Body,
 		ResponseTime: resp.ResponseTime,
 		Timestamp:    startTime,
-	}, nil
+		Protocol:     resp.Protocol,
+		Timing:       convertTiming(resp.Timing),
+		Truncated:    resp.Truncated,
+	}
+	if len(resp.Trailers) > 0 {
+		response.Trailers = convertHeaders(resp.Trailers)
+	}
+
+	return response, nil
 }
 
 // performDriftComparison compares current response with baseline or previous response
@@ -397,8 +694,20 @@ func performDriftComparison(endpointResult *CIEndpointResult, diffEngine drift.D
 		if baselineResp, exists := baselineData[endpointConfig.ID]; exists {
 			baseline = baselineResp
 		}
+	} else if endpointConfig.BaselineStrategy == config.BaselineStrategyFile {
+		var err error
+		baseline, err = getBaselineFromFile(endpointConfig, currentResponse)
+		if err != nil {
+			endpointResult.Error = fmt.Sprintf("failed to load baseline: %v", err)
+			return
+		}
 	} else {
-		baseline = getBaselineFromStorage(db, endpointConfig.ID)
+		var err error
+		baseline, err = getBaselineFromStorage(db, endpointConfig)
+		if err != nil {
+			endpointResult.Error = fmt.Sprintf("failed to load baseline: %v", err)
+			return
+		}
 	}
 
 	if baseline != nil {
@@ -406,21 +715,205 @@ func performDriftComparison(endpointResult *CIEndpointResult, diffEngine drift.D
 	}
 }
 
-// getBaselineFromStorage retrieves baseline response from storage
-func getBaselineFromStorage(db storage.Storage, endpointID string) *drift.Response {
-	previousRuns, err := db.GetMonitoringHistory(endpointID, 24*time.Hour)
-	if err != nil || len(previousRuns) == 0 {
-		return nil
+// getBaselineFromFile reads the committed response body of an endpoint with the
+// file baseline strategy. The file only holds a body, so the status, headers and
+// protocol are taken from the current response and only the bodies differ.
+// Response times are left out, as a committed file has none to compare.
+func getBaselineFromFile(endpointConfig config.EndpointConfig, current *drift.Response) (*drift.Response, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	body, err := security.SafeReadFile(endpointConfig.BaselineFile, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file (create it with driftwatch accept %s): %w", endpointConfig.ID, err)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("baseline file %s does not contain valid JSON", endpointConfig.BaselineFile)
 	}
 
-	lastRun := previousRuns[0]
 	return &drift.Response{
-		StatusCode:   lastRun.ResponseStatus,
-		Headers:      lastRun.ResponseHeaders,
-		Body:         []byte(lastRun.ResponseBody),
-		ResponseTime: time.Duration(lastRun.ResponseTimeMs) * time.Millisecond,
-		Timestamp:    lastRun.Timestamp,
+		StatusCode: current.StatusCode,
+		Headers:    current.Headers,
+		Body:       body,
+		Protocol:   current.Protocol,
+		Trailers:   current.Trailers,
+	}, nil
+}
+
+// baselineHistoryWindow is the minimum history searched for a stored baseline
+const baselineHistoryWindow = 24 * time.Hour
+
+// defaultMajorityRuns is how many recent runs the majority strategy votes among
+// when baseline_runs is not set
+const defaultMajorityRuns = 10
+
+// getBaselineFromStorage retrieves the stored run selected by the endpoint's
+// baseline strategy. The previous strategy uses the most recent successful run,
+// fixed the pinned run, and n_ago the successful run baseline_runs_ago successful
+// runs back, failing while there is not enough history yet. majority uses the
+// latest successful run with the most common response shape among the last
+// baseline_runs. It returns nil without an error when no successful run has
+// been stored.
+func getBaselineFromStorage(db storage.Storage, endpointConfig config.EndpointConfig) (*drift.Response, error) {
+	var baselineRun *storage.MonitoringRun
+
+	switch endpointConfig.BaselineStrategy {
+	case config.BaselineStrategyFixed:
+		run, err := db.GetMonitoringRun(endpointConfig.BaselineRunID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pinned baseline run: %w", err)
+		}
+		if run.EndpointID != endpointConfig.ID {
+			return nil, fmt.Errorf("pinned baseline run %d belongs to endpoint %s", run.ID, run.EndpointID)
+		}
+		if !run.Succeeded() || run.FailureCategory != "" {
+			return nil, fmt.Errorf("pinned baseline run %d did not succeed", run.ID)
+		}
+		baselineRun = run
+
+	case config.BaselineStrategyNAgo:
+		runsAgo := endpointConfig.BaselineRunsAgo
+		window := baselineHistoryWindow
+		if needed := time.Duration(runsAgo+1) * endpointConfig.Interval; needed > window {
+			window = needed
+		}
+
+		previousRuns, err := db.GetMonitoringHistory(endpointConfig.ID, window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get monitoring history: %w", err)
+		}
+		previousRuns = successfulRuns(previousRuns)
+		if len(previousRuns) == 0 {
+			return nil, nil
+		}
+		if runsAgo < 1 {
+			runsAgo = 1
+		}
+		if runsAgo > len(previousRuns) {
+			return nil, fmt.Errorf("baseline needs %d successful runs but only %d are stored for endpoint %s", runsAgo, len(previousRuns), endpointConfig.ID)
+		}
+		baselineRun = previousRuns[runsAgo-1]
+
+	case config.BaselineStrategyMajority:
+		runs := endpointConfig.BaselineRuns
+		if runs <= 0 {
+			runs = defaultMajorityRuns
+		}
+		window := baselineHistoryWindow
+		if needed := time.Duration(runs) * endpointConfig.Interval; needed > window {
+			window = needed
+		}
+
+		previousRuns, err := db.GetMonitoringHistory(endpointConfig.ID, window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get monitoring history: %w", err)
+		}
+		previousRuns = successfulRuns(previousRuns)
+		if len(previousRuns) == 0 {
+			return nil, nil
+		}
+		if len(previousRuns) > runs {
+			previousRuns = previousRuns[:runs]
+		}
+		baselineRun = majorityShapeRun(previousRuns)
+
+	default:
+		previousRuns, err := db.GetMonitoringHistory(endpointConfig.ID, baselineHistoryWindow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get monitoring history: %w", err)
+		}
+		previousRuns = successfulRuns(previousRuns)
+		if len(previousRuns) == 0 {
+			return nil, nil
+		}
+		baselineRun = previousRuns[0]
 	}
+
+	return runResponse(baselineRun), nil
+}
+
+// majorityShapeRun returns the most recent of the runs, given newest first,
+// whose status and response shape are the most common among them. Ties go to
+// the shape seen most recently.
+func majorityShapeRun(runs []*storage.MonitoringRun) *storage.MonitoringRun {
+	counts := make(map[string]int)
+	latest := make(map[string]*storage.MonitoringRun)
+	var order []string
+	for _, run := range runs {
+		shape := fmt.Sprintf("%d:%s", run.ResponseStatus, drift.ShapeFingerprint([]byte(run.ResponseBody)))
+		if _, seen := latest[shape]; !seen {
+			latest[shape] = run
+			order = append(order, shape)
+		}
+		counts[shape]++
+	}
+
+	majority := order[0]
+	for _, shape := range order[1:] {
+		if counts[shape] > counts[majority] {
+			majority = shape
+		}
+	}
+	return latest[majority]
+}
+
+// runResponse converts a stored monitoring run for drift analysis
+func runResponse(run *storage.MonitoringRun) *drift.Response {
+	return &drift.Response{
+		StatusCode:   run.ResponseStatus,
+		Headers:      run.ResponseHeaders,
+		Body:         []byte(run.ResponseBody),
+		ResponseTime: time.Duration(run.ResponseTimeMs) * time.Millisecond,
+		Timestamp:    run.Timestamp,
+		Protocol:     run.Protocol,
+		Trailers:     run.ResponseTrailers,
+		Timing:       runTiming(run),
+		Truncated:    run.ResponseTruncated,
+	}
+}
+
+// performanceHistoryRuns is the number of recent successful runs the zscore
+// performance mode computes response time statistics from
+const performanceHistoryRuns = 20
+
+// responseTimeHistory returns the response times of the most recent successful
+// runs of an endpoint, up to performanceHistoryRuns, newest first
+func responseTimeHistory(db storage.Storage, endpointConfig config.EndpointConfig) ([]time.Duration, error) {
+	window := baselineHistoryWindow
+	if needed := performanceHistoryRuns * endpointConfig.Interval; needed > window {
+		window = needed
+	}
+
+	runs, err := db.GetMonitoringHistory(endpointConfig.ID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitoring history: %w", err)
+	}
+
+	var history []time.Duration
+	for _, run := range successfulRuns(runs) {
+		if len(history) == performanceHistoryRuns {
+			break
+		}
+		if run.ResponseTimeMs > 0 {
+			history = append(history, time.Duration(run.ResponseTimeMs)*time.Millisecond)
+		}
+	}
+
+	return history, nil
+}
+
+// successfulRuns returns the runs that received a 2xx response and recorded no
+// failure. Failed checks are stored as runs too but cannot serve as baselines.
+func successfulRuns(runs []*storage.MonitoringRun) []*storage.MonitoringRun {
+	successful := make([]*storage.MonitoringRun, 0, len(runs))
+	for _, run := range runs {
+		if run.Succeeded() && run.FailureCategory == "" {
+			successful = append(successful, run)
+		}
+	}
+	return successful
 }
 
 // compareDriftResults performs drift comparison and updates endpoint result
@@ -441,6 +934,7 @@ func compareDriftResults(endpointResult *CIEndpointResult, diffEngine drift.Diff
 func calculateCITotals(result *CIResult) {
 	result.EndpointsChecked = len(result.Endpoints)
 	for _, ep := range result.Endpoints {
+		result.ValidationErrors += len(ep.ValidationErrors)
 		for _, change := range ep.Changes {
 			result.TotalChanges++
 			if change.Breaking {
@@ -464,6 +958,11 @@ func calculateCITotals(result *CIResult) {
 func convertDriftToCIChanges(diffResult *drift.DiffResult, includePerformance bool) []CIChange {
 	var changes []CIChange
 
+	mitigations := make(map[string]string, len(diffResult.BreakingChanges))
+	for _, breaking := range diffResult.BreakingChanges {
+		mitigations[string(breaking.Type)+" "+breaking.Path] = breaking.Mitigation
+	}
+
 	// Convert structural changes
 	for _, change := range diffResult.StructuralChanges {
 		ciChange := CIChange{
@@ -472,6 +971,10 @@ func convertDriftToCIChanges(diffResult *drift.DiffResult, includePerformance bo
 			Severity:    string(change.Severity),
 			Breaking:    change.Breaking,
 			Description: change.Description,
+			Explanation: change.Explanation,
+		}
+		if change.Breaking {
+			ciChange.Mitigation = mitigations[string(change.Type)+" "+change.Path]
 		}
 
 		if change.OldValue != nil {
@@ -494,6 +997,7 @@ func convertDriftToCIChanges(diffResult *drift.DiffResult, includePerformance bo
 			Description: change.Description,
 			OldValue:    fmt.Sprintf("%v", change.OldValue),
 			NewValue:    fmt.Sprintf("%v", change.NewValue),
+			Explanation: change.Explanation,
 		}
 
 		changes = append(changes, ciChange)
@@ -516,7 +1020,7 @@ func convertDriftToCIChanges(diffResult *drift.DiffResult, includePerformance bo
 }
 
 // determineExitCode determines the appropriate exit code based on results
-func determineExitCode(result *CIResult, failOnSeverity string, failOnBreaking bool) int {
+func determineExitCode(result *CIResult, failOnSeverity string, failOnBreaking, failOnValidation bool) int {
 	if failOnBreaking && result.BreakingChanges > 0 {
 		return ExitCodeBreakingChanges
 	}
@@ -525,6 +1029,10 @@ func determineExitCode(result *CIResult, failOnSeverity string, failOnBreaking b
 		return ExitCodeGeneralError
 	}
 
+	if failOnValidation && result.ValidationErrors > 0 {
+		return ExitCodeValidationError
+	}
+
 	return checkSeverityThreshold(result, failOnSeverity)
 }
 
@@ -540,20 +1048,89 @@ func hasEndpointErrors(result *CIResult) bool {
 
 // checkSeverityThreshold checks if changes exceed the severity threshold
 func checkSeverityThreshold(result *CIResult, failOnSeverity string) int {
-	severityCheckers := map[string]func(*CIResult) bool{
-		"critical": func(r *CIResult) bool { return r.CriticalChanges > 0 },
-		"high":     func(r *CIResult) bool { return r.CriticalChanges > 0 || r.HighChanges > 0 },
-		"medium":   func(r *CIResult) bool { return r.CriticalChanges > 0 || r.HighChanges > 0 || r.MediumChanges > 0 },
-		"low":      func(r *CIResult) bool { return r.TotalChanges > 0 },
+	thresholds, err := parseFailOn(failOnSeverity)
+	if err != nil {
+		return ExitCodeSuccess
 	}
 
-	if checker, exists := severityCheckers[strings.ToLower(failOnSeverity)]; exists && checker(result) {
-		return ExitCodeBreakingChanges
+	for _, threshold := range thresholds {
+		if threshold.exceeded(result) {
+			return ExitCodeBreakingChanges
+		}
 	}
 
 	return ExitCodeSuccess
 }
 
+// severityThreshold is one term of --fail-on. A bare severity such as "high"
+// fails on any change of that severity or above; a severity with a count such
+// as "high:3" fails once there are that many changes of exactly that severity.
+type severityThreshold struct {
+	Severity string
+	Count    int // 0 for a bare severity
+}
+
+// severityRanks orders the severities --fail-on accepts
+var severityRanks = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// parseFailOn parses a --fail-on value: a comma-separated list of thresholds,
+// any of which fails the check, such as "high:3,critical:1"
+func parseFailOn(value string) ([]severityThreshold, error) {
+	var thresholds []severityThreshold
+	for _, term := range strings.Split(value, ",") {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			continue
+		}
+
+		severity, count, hasCount := strings.Cut(term, ":")
+		severity = strings.TrimSpace(severity)
+		if _, ok := severityRanks[severity]; !ok {
+			return nil, fmt.Errorf("invalid --fail-on severity %q (supported: low, medium, high, critical)", severity)
+		}
+
+		threshold := severityThreshold{Severity: severity}
+		if hasCount {
+			n, err := strconv.Atoi(strings.TrimSpace(count))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid --fail-on count %q for %s: must be a positive number", count, severity)
+			}
+			threshold.Count = n
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	if len(thresholds) == 0 {
+		return nil, fmt.Errorf("--fail-on requires at least one severity")
+	}
+	return thresholds, nil
+}
+
+// exceeded reports whether the changes of a CI result reach the threshold
+func (t severityThreshold) exceeded(result *CIResult) bool {
+	counts := map[string]int{
+		"low":      result.LowChanges,
+		"medium":   result.MediumChanges,
+		"high":     result.HighChanges,
+		"critical": result.CriticalChanges,
+	}
+
+	if t.Count > 0 {
+		return counts[t.Severity] >= t.Count
+	}
+
+	// Low also counts changes without a recognized severity, as it always has
+	if t.Severity == "low" {
+		return result.TotalChanges > 0
+	}
+	for severity, rank := range severityRanks {
+		if rank >= severityRanks[t.Severity] && counts[severity] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // generateCISummary generates a human-readable summary
 func generateCISummary(result *CIResult) string {
 	if result.Success {
@@ -580,12 +1157,15 @@ func generateCISummary(result *CIResult) string {
 	if errorCount > 0 {
 		issues = append(issues, fmt.Sprintf("%d endpoint errors", errorCount))
 	}
+	if result.ValidationErrors > 0 {
+		issues = append(issues, fmt.Sprintf("%d validation errors", result.ValidationErrors))
+	}
 
 	return fmt.Sprintf("❌ CI check failed: %s", strings.Join(issues, ", "))
 }
 
 // outputCIResults outputs the CI results in the specified format
-func outputCIResults(result *CIResult, format, outputFile string) error {
+func outputCIResults(cfg *config.Config, result *CIResult, format, outputFile string) error {
 	var output []byte
 	var err error
 
@@ -598,8 +1178,14 @@ func outputCIResults(result *CIResult, format, outputFile string) error {
 		if err == nil {
 			output = append([]byte(xml.Header), output...)
 		}
+	case "ndjson":
+		output, err = marshalCINDJSON(result)
+	case "sarif":
+		output, err = marshalCISARIF(cfg, result)
 	case "summary":
 		output = []byte(result.Summary + "\n")
+	case "diff":
+		output = renderCIDiff(result, outputFile == "" && colorEnabled(os.Stdout))
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
@@ -622,6 +1208,68 @@ func outputCIResults(result *CIResult, format, outputFile string) error {
 	return err
 }
 
+// renderCIDiff renders the changes of every endpoint as a unified diff
+// followed by the summary line
+func renderCIDiff(result *CIResult, color bool) []byte {
+	var buf bytes.Buffer
+	p := diffPainter{color: color}
+
+	for _, endpoint := range result.Endpoints {
+		fmt.Fprintf(&buf, "%s %s %s\n", p.paint(ansiCyan, "==="), endpoint.ID, p.paint(ansiCyan, "==="))
+		switch {
+		case endpoint.Error != "":
+			fmt.Fprintln(&buf, p.paint(ansiRed, "error: "+endpoint.Error))
+		case len(endpoint.Changes) == 0:
+			fmt.Fprintln(&buf, "no changes")
+		default:
+			renderDiff(&buf, endpoint.Changes, color)
+		}
+		fmt.Fprintln(&buf)
+	}
+
+	fmt.Fprintln(&buf, result.Summary)
+	return buf.Bytes()
+}
+
+// ciNDJSONEndpoint is an endpoint result line in ndjson output
+type ciNDJSONEndpoint struct {
+	Type string `json:"type"`
+	CIEndpointResult
+}
+
+// ciNDJSONSummary is the final line in ndjson output. It carries the overall
+// result without the per-endpoint details, which are emitted as separate lines.
+type ciNDJSONSummary struct {
+	Type      string             `json:"type"`
+	Endpoints []CIEndpointResult `json:"endpoints,omitempty"`
+	*CIResult
+}
+
+// marshalCINDJSON renders CI results as newline-delimited JSON: one line per
+// endpoint result followed by a summary line. Each line has a "type" field of
+// either "endpoint" or "summary".
+func marshalCINDJSON(result *CIResult) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, ep := range result.Endpoints {
+		line, err := json.Marshal(ciNDJSONEndpoint{Type: "endpoint", CIEndpointResult: ep})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	line, err := json.Marshal(ciNDJSONSummary{Type: "summary", CIResult: result})
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(line)
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
 // convertToJUnit converts CI results to JUnit XML format
 func convertToJUnit(result *CIResult) *JUnitTestSuite {
 	suite := &JUnitTestSuite{
@@ -653,12 +1301,26 @@ func convertToJUnit(result *CIResult) *JUnitTestSuite {
 				Type:    "BreakingChanges",
 				Content: formatChangesForJUnit(ep.Changes),
 			}
+		} else if len(ep.ValidationErrors) > 0 {
+			suite.Failures++
+			testCase.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%d validation errors", len(ep.ValidationErrors)),
+				Type:    "ValidationErrors",
+				Content: formatValidationErrorsForJUnit(ep.ValidationErrors),
+			}
 		}
 
 		// Add system output with endpoint details
 		systemOut := fmt.Sprintf("Endpoint: %s\nURL: %s %s\nStatus: %d\nResponse Time: %v\n",
 			ep.ID, ep.Method, ep.URL, ep.StatusCode, ep.ResponseTime)
 
+		if ep.Samples > 1 {
+			systemOut += fmt.Sprintf("Samples: %d\n", ep.Samples)
+			if len(ep.VolatileFields) > 0 {
+				systemOut += fmt.Sprintf("Ignored volatile fields: %s\n", strings.Join(ep.VolatileFields, ", "))
+			}
+		}
+
 		if len(ep.Changes) > 0 {
 			systemOut += fmt.Sprintf("Changes detected: %d\n", len(ep.Changes))
 			for _, change := range ep.Changes {
@@ -667,13 +1329,21 @@ func convertToJUnit(result *CIResult) *JUnitTestSuite {
 		}
 
 		testCase.SystemOut = systemOut
+		if testCase.Failure != nil || testCase.Error != nil {
+			testCase.SystemErr = formatJUnitSummary(ep)
+		}
 		suite.TestCases = append(suite.TestCases, testCase)
 	}
 
 	return suite
 }
 
-// formatChangesForJUnit formats changes for JUnit XML output
+// junitValueLimit caps the length of before and after values in JUnit output
+const junitValueLimit = 200
+
+// formatChangesForJUnit formats changes for JUnit XML output, with the values
+// before and after each change and the mitigation of breaking changes. The XML
+// encoder escapes markup and replaces characters XML does not allow.
 func formatChangesForJUnit(changes []CIChange) string {
 	if len(changes) == 0 {
 		return "No changes detected"
@@ -685,12 +1355,63 @@ func formatChangesForJUnit(changes []CIChange) string {
 		if change.Breaking {
 			line += " [BREAKING]"
 		}
+		if change.OldValue != "" {
+			line += "\n  before: " + truncateString(change.OldValue, junitValueLimit)
+		}
+		if change.NewValue != "" {
+			line += "\n  after: " + truncateString(change.NewValue, junitValueLimit)
+		}
+		if change.Mitigation != "" {
+			line += "\n  mitigation: " + change.Mitigation
+		}
+		if change.Explanation != nil {
+			line += "\n  why: " + formatExplanation(change.Explanation)
+		}
 		lines = append(lines, line)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// formatJUnitSummary returns a summary of an endpoint's result as key: value
+// lines for the system-err element of a failing test case
+func formatJUnitSummary(ep CIEndpointResult) string {
+	severities := map[string]int{}
+	for _, change := range ep.Changes {
+		severities[change.Severity]++
+	}
+
+	lines := []string{
+		"endpoint: " + ep.ID,
+		fmt.Sprintf("request: %s %s", ep.Method, ep.URL),
+		fmt.Sprintf("status_code: %d", ep.StatusCode),
+	}
+	if ep.Error != "" {
+		lines = append(lines, "error: "+ep.Error)
+	}
+	lines = append(lines,
+		fmt.Sprintf("changes: %d", len(ep.Changes)),
+		fmt.Sprintf("breaking_changes: %d", ep.BreakingChanges),
+		fmt.Sprintf("critical: %d", severities["critical"]),
+		fmt.Sprintf("high: %d", severities["high"]),
+		fmt.Sprintf("medium: %d", severities["medium"]),
+		fmt.Sprintf("low: %d", severities["low"]),
+		fmt.Sprintf("validation_errors: %d", len(ep.ValidationErrors)),
+	)
+
+	return strings.Join(lines, "\n")
+}
+
+// formatValidationErrorsForJUnit formats validation errors for JUnit XML output
+func formatValidationErrorsForJUnit(validationErrors []monitor.ValidationError) string {
+	lines := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		lines = append(lines, fmt.Sprintf("%s at %s: %s", validationError.Type, validationError.Field, validationError.Message))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // loadBaselineData loads baseline response data from a JSON file
 func loadBaselineData(filename string) (map[string]*drift.Response, error) {
 	// Use current working directory as allowed directory for baseline files
@@ -703,6 +1424,56 @@ func loadBaselineData(filename string) (map[string]*drift.Response, error) {
 		return nil, fmt.Errorf("failed to read baseline file: %w", err)
 	}
 
+	return parseBaselineData(data)
+}
+
+// loadBaselineFromGit loads baseline response data from a git object given as ref:path
+func loadBaselineFromGit(spec string) (map[string]*drift.Response, error) {
+	ref, path, err := parseGitBaselineSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G204 - ref and path are validated by parseGitBaselineSpec and passed as a single argument
+	gitCmd := exec.Command("git", "show", ref+":"+path)
+	var stderr strings.Builder
+	gitCmd.Stderr = &stderr
+
+	data, err := gitCmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to read baseline from git %s: %s", spec, msg)
+		}
+		return nil, fmt.Errorf("failed to read baseline from git %s: %w", spec, err)
+	}
+
+	return parseBaselineData(data)
+}
+
+// parseGitBaselineSpec splits and validates a ref:path baseline specification
+func parseGitBaselineSpec(spec string) (string, string, error) {
+	ref, path, found := strings.Cut(spec, ":")
+	if !found || ref == "" || path == "" {
+		return "", "", fmt.Errorf("invalid git baseline %q: expected format ref:path", spec)
+	}
+
+	if strings.HasPrefix(ref, "-") {
+		return "", "", fmt.Errorf("invalid git ref %q: must not start with '-'", ref)
+	}
+	if strings.ContainsAny(ref, " \t\n\r") || strings.Contains(ref, "..") {
+		return "", "", fmt.Errorf("invalid git ref %q", ref)
+	}
+
+	cleaned := filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "/") || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", "", fmt.Errorf("invalid baseline path %q: must be relative to the repository root", path)
+	}
+
+	return ref, cleaned, nil
+}
+
+// parseBaselineData parses baseline response data from JSON
+func parseBaselineData(data []byte) (map[string]*drift.Response, error) {
 	var baseline map[string]*drift.Response
 	if err := json.Unmarshal(data, &baseline); err != nil {
 		return nil, fmt.Errorf("failed to parse baseline JSON: %w", err)
@@ -716,12 +1487,35 @@ func convertHeaders(headers map[string][]string) map[string]string {
 	result := make(map[string]string)
 	for key, values := range headers {
 		if len(values) > 0 {
-			result[key] = values[0]
+			result[key] = drift.HeaderValue(key, values)
 		}
 	}
 	return result
 }
 
+// convertTiming converts the timing breakdown of an HTTP response for drift
+// analysis. Responses without a recorded first byte have no breakdown.
+func convertTiming(timing httpClient.Timing) *drift.Timing {
+	if timing.TTFB == 0 {
+		return nil
+	}
+	return &drift.Timing{DNS: timing.DNS, Connect: timing.Connect, TLS: timing.TLS, TTFB: timing.TTFB}
+}
+
+// runTiming returns the timing breakdown recorded on a monitoring run, or nil
+// for runs recorded before timings were captured
+func runTiming(run *storage.MonitoringRun) *drift.Timing {
+	if run.TTFBMs == 0 {
+		return nil
+	}
+	return &drift.Timing{
+		DNS:     time.Duration(run.DNSTimeMs) * time.Millisecond,
+		Connect: time.Duration(run.ConnectTimeMs) * time.Millisecond,
+		TLS:     time.Duration(run.TLSTimeMs) * time.Millisecond,
+		TTFB:    time.Duration(run.TTFBMs) * time.Millisecond,
+	}
+}
+
 // exitWithCode prints an error message and exits with the specified code
 func exitWithCode(code int, message string) {
 	fmt.Fprintf(os.Stderr, "Error: %s\n", message)
@@ -180,7 +180,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 
 		// Perform CI check
 		ctx := context.Background()
-		result := performCICheck(ctx, cfg, db, mockClient, baselineData, false)
+		result := performCICheck(ctx, cfg, db, mockClient, baselineData, false, false)
 
 		// Verify no changes detected
 		assert.Equal(t, 2, result.EndpointsChecked)
@@ -188,7 +188,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 		assert.Equal(t, 0, result.BreakingChanges)
 
 		// Verify exit code
-		exitCode := determineExitCode(result, "high", true)
+		exitCode := determineExitCode(result, "high", true, false)
 		assert.Equal(t, ExitCodeSuccess, exitCode)
 	})
 
@@ -244,7 +244,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 
 		// Perform CI check
 		ctx := context.Background()
-		result := performCICheck(ctx, cfg, db, mockClient, baselineData, false)
+		result := performCICheck(ctx, cfg, db, mockClient, baselineData, false, false)
 
 		// Verify changes detected
 		assert.Equal(t, 1, result.EndpointsChecked)
@@ -258,7 +258,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 		assert.Greater(t, len(endpoint.Changes), 0)
 
 		// Verify exit code indicates breaking changes
-		exitCode := determineExitCode(result, "high", true)
+		exitCode := determineExitCode(result, "high", true, false)
 		assert.NotEqual(t, ExitCodeSuccess, exitCode)
 	})
 
@@ -317,7 +317,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 			defer os.Remove(tmpFile.Name())
 			tmpFile.Close()
 
-			err = outputCIResults(result, "json", tmpFile.Name())
+			err = outputCIResults(&config.Config{}, result, "json", tmpFile.Name())
 			require.NoError(t, err)
 
 			// Verify JSON structure
@@ -342,7 +342,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 			defer os.Remove(tmpFile.Name())
 			tmpFile.Close()
 
-			err = outputCIResults(result, "junit", tmpFile.Name())
+			err = outputCIResults(&config.Config{}, result, "junit", tmpFile.Name())
 			require.NoError(t, err)
 
 			// Verify XML structure
@@ -362,7 +362,7 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 			defer os.Remove(tmpFile.Name())
 			tmpFile.Close()
 
-			err = outputCIResults(result, "summary", tmpFile.Name())
+			err = outputCIResults(&config.Config{}, result, "summary", tmpFile.Name())
 			require.NoError(t, err)
 
 			// Verify summary content
@@ -382,11 +382,12 @@ func TestCIIntegrationWorkflow(t *testing.T) {
 // TestCIExitCodes tests various exit code scenarios
 func TestCIExitCodes(t *testing.T) {
 	tests := []struct {
-		name           string
-		result         *CIResult
-		failOnSeverity string
-		failOnBreaking bool
-		expectedCode   int
+		name             string
+		result           *CIResult
+		failOnSeverity   string
+		failOnBreaking   bool
+		failOnValidation bool
+		expectedCode     int
 	}{
 		{
 			name: "success_no_changes",
@@ -462,6 +463,39 @@ func TestCIExitCodes(t *testing.T) {
 			failOnBreaking: false,
 			expectedCode:   ExitCodeSuccess,
 		},
+		{
+			name: "high_count_below_threshold_should_pass",
+			result: &CIResult{
+				EndpointsChecked: 1,
+				TotalChanges:     2,
+				HighChanges:      2,
+				Endpoints:        []CIEndpointResult{{Success: true}},
+			},
+			failOnSeverity: "high:3,critical:1",
+			expectedCode:   ExitCodeSuccess,
+		},
+		{
+			name: "high_count_reaches_threshold",
+			result: &CIResult{
+				EndpointsChecked: 1,
+				TotalChanges:     3,
+				HighChanges:      3,
+				Endpoints:        []CIEndpointResult{{Success: true}},
+			},
+			failOnSeverity: "high:3,critical:1",
+			expectedCode:   ExitCodeBreakingChanges,
+		},
+		{
+			name: "any_critical_with_count_thresholds",
+			result: &CIResult{
+				EndpointsChecked: 1,
+				TotalChanges:     1,
+				CriticalChanges:  1,
+				Endpoints:        []CIEndpointResult{{Success: true}},
+			},
+			failOnSeverity: "high:3,critical:1",
+			expectedCode:   ExitCodeBreakingChanges,
+		},
 		{
 			name: "endpoint_errors",
 			result: &CIResult{
@@ -480,7 +514,7 @@ func TestCIExitCodes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			code := determineExitCode(tt.result, tt.failOnSeverity, tt.failOnBreaking)
+			code := determineExitCode(tt.result, tt.failOnSeverity, tt.failOnBreaking, tt.failOnValidation)
 			assert.Equal(t, tt.expectedCode, code, "Exit code mismatch for test case: %s", tt.name)
 		})
 	}
@@ -537,7 +571,7 @@ func TestCIPerformanceMode(t *testing.T) {
 
 	// Test with performance monitoring enabled
 	ctx := context.Background()
-	result := performCICheck(ctx, cfg, db, mockClient, baselineData, true)
+	result := performCICheck(ctx, cfg, db, mockClient, baselineData, true, false)
 
 	assert.Equal(t, 1, result.EndpointsChecked)
 	assert.Greater(t, result.TotalChanges, 0) // Should detect performance change
@@ -5,7 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"fmt"
+	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +18,7 @@ import (
 	"github.com/k0ns0l/driftwatch/internal/drift"
 	httpClient "github.com/k0ns0l/driftwatch/internal/http"
 	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/k0ns0l/driftwatch/internal/validator"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,9 +33,12 @@ func TestCICommandDirectValidation(t *testing.T) {
 	cmd.Flags().Bool("no-storage", false, "run without persistent storage")
 	cmd.Flags().StringSlice("endpoints", []string{}, "specific endpoints to check")
 	cmd.Flags().Bool("fail-on-breaking", true, "fail if any breaking changes are detected")
+	cmd.Flags().Bool("fail-on-validation", false, "fail if any response violates its endpoint's OpenAPI spec")
 	cmd.Flags().Bool("include-performance", false, "include performance changes in results")
 	cmd.Flags().String("baseline-file", "", "JSON file containing baseline responses")
+	cmd.Flags().String("baseline-from-git", "", "load baseline responses from a git object")
 	cmd.Flags().String("output-file", "", "write results to file instead of stdout")
+	cmd.Flags().Bool("explain", false, "include classification reasoning")
 
 	// Set up mock configuration
 	originalCfg := cfg
@@ -153,7 +161,7 @@ func TestPerformCICheck(t *testing.T) {
 
 	// Test CI check without baseline
 	ctx := context.Background()
-	result := performCICheck(ctx, cfg, db, mockClient, nil, false)
+	result := performCICheck(ctx, cfg, db, mockClient, nil, false, false)
 	assert.Equal(t, 1, result.EndpointsChecked)
 	assert.Equal(t, 0, result.TotalChanges)
 	assert.Equal(t, 0, result.BreakingChanges)
@@ -166,6 +174,79 @@ func TestPerformCICheck(t *testing.T) {
 	assert.Empty(t, endpoint.Error)
 }
 
+func TestPerformCICheckWithValidation(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "users-api.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte(`swagger: "2.0"
+info:
+  title: Users API
+  version: "1.0"
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: string
+      responses:
+        "200":
+          description: OK
+          schema:
+            type: object
+            required: [id, name]
+            properties:
+              id:
+                type: integer
+              name:
+                type: string
+`), 0o644))
+
+	cfg := &config.Config{
+		Global: config.GlobalConfig{Timeout: 30 * time.Second},
+		Endpoints: []config.EndpointConfig{
+			{
+				ID:       "user",
+				URL:      "https://api.example.com/users/1",
+				Method:   "GET",
+				SpecFile: specFile,
+				Enabled:  true,
+			},
+		},
+	}
+
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mockClient := &MockHTTPClient{
+		responses: map[string]*httpClient.Response{
+			"GET https://api.example.com/users/1": {
+				StatusCode: 200,
+				Headers:    map[string][]string{"Content-Type": {"application/json"}},
+				Body:       []byte(`{"id": "1"}`),
+			},
+		},
+	}
+
+	result := performCICheck(context.Background(), cfg, db, mockClient, nil, false, false)
+	require.Len(t, result.Endpoints, 1)
+	assert.Empty(t, result.Endpoints[0].Error)
+	assert.NotEmpty(t, result.Endpoints[0].ValidationErrors)
+	assert.Equal(t, len(result.Endpoints[0].ValidationErrors), result.ValidationErrors)
+
+	assert.Equal(t, ExitCodeSuccess, determineExitCode(result, "high", true, false))
+	assert.Equal(t, ExitCodeValidationError, determineExitCode(result, "high", true, true))
+
+	suite := convertToJUnit(result)
+	require.NotNil(t, suite.TestCases[0].Failure)
+	assert.Equal(t, "ValidationErrors", suite.TestCases[0].Failure.Type)
+
+	mockClient.responses["GET https://api.example.com/users/1"].Body = []byte(`{"id": 1, "name": "Ada"}`)
+	result = performCICheck(context.Background(), cfg, db, mockClient, nil, false, false)
+	assert.Empty(t, result.Endpoints[0].ValidationErrors)
+	assert.Zero(t, result.ValidationErrors)
+}
+
 func TestPerformCICheckWithBaseline(t *testing.T) {
 	// Create test configuration
 	cfg := &config.Config{
@@ -216,7 +297,7 @@ func TestPerformCICheckWithBaseline(t *testing.T) {
 
 	// Test CI check with baseline
 	ctx := context.Background()
-	result := performCICheck(ctx, cfg, db, mockClient, baselineData, false)
+	result := performCICheck(ctx, cfg, db, mockClient, baselineData, false, false)
 	assert.Equal(t, 1, result.EndpointsChecked)
 	assert.Greater(t, result.TotalChanges, 0)
 	assert.Len(t, result.Endpoints, 1)
@@ -227,13 +308,287 @@ func TestPerformCICheckWithBaseline(t *testing.T) {
 	assert.Greater(t, len(endpoint.Changes), 0)
 }
 
+// sequenceHTTPClient returns the configured bodies in turn, one per request
+type sequenceHTTPClient struct {
+	MockHTTPClient
+	bodies []string
+	calls  int
+}
+
+func (c *sequenceHTTPClient) Do(req *http.Request) (*httpClient.Response, error) {
+	body := c.bodies[c.calls%len(c.bodies)]
+	c.calls++
+	return &httpClient.Response{
+		StatusCode:   200,
+		Headers:      map[string][]string{"Content-Type": {"application/json"}},
+		Body:         []byte(body),
+		ResponseTime: 100 * time.Millisecond,
+	}, nil
+}
+
+func TestPerformCICheckWithSamples(t *testing.T) {
+	cfg := &config.Config{
+		Global: config.GlobalConfig{Timeout: 30 * time.Second},
+		Endpoints: []config.EndpointConfig{
+			{
+				ID:      "test-api",
+				URL:     "https://api.example.com/users/1",
+				Method:  "GET",
+				Enabled: true,
+				Samples: 2,
+			},
+		},
+	}
+
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	baselineData := map[string]*drift.Response{
+		"test-api": {
+			StatusCode: 200,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       []byte(`{"id": 1, "name": "old", "request_id": "a"}`),
+		},
+	}
+
+	client := &sequenceHTTPClient{bodies: []string{
+		`{"id": 1, "name": "new", "request_id": "b"}`,
+		`{"id": 1, "name": "new", "request_id": "c"}`,
+	}}
+
+	result := performCICheck(context.Background(), cfg, db, client, baselineData, false, false)
+	require.Len(t, result.Endpoints, 1)
+
+	endpoint := result.Endpoints[0]
+	assert.Equal(t, 2, client.calls)
+	assert.Equal(t, 2, endpoint.Samples)
+	assert.Equal(t, []string{"$.request_id"}, endpoint.VolatileFields)
+	require.Len(t, endpoint.Changes, 1)
+	assert.Equal(t, "$.name", endpoint.Changes[0].Path)
+}
+
+func TestRenderCIDiff(t *testing.T) {
+	result := &CIResult{
+		Summary: "❌ CI check failed",
+		Endpoints: []CIEndpointResult{
+			{ID: "users-api", Changes: []CIChange{
+				{Type: "field_removed", Path: "$.email", Severity: "high", OldValue: "ada@example.com", Breaking: true},
+			}},
+			{ID: "posts-api"},
+			{ID: "broken-api", Error: "request failed"},
+		},
+	}
+
+	output := string(renderCIDiff(result, false))
+	assert.Equal(t, "=== users-api ===\n@@ $ @@\n- email: ada@example.com  # field_removed, high, breaking\n\n"+
+		"=== posts-api ===\nno changes\n\n"+
+		"=== broken-api ===\nerror: request failed\n\n"+
+		"❌ CI check failed\n", output)
+}
+
+func TestGetBaselineFromStorage(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+			EndpointID:     "test-api",
+			Timestamp:      now.Add(-time.Duration(i) * time.Minute),
+			ResponseStatus: 200,
+			ResponseBody:   fmt.Sprintf(`{"run": %d}`, i),
+		}))
+	}
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{EndpointID: "other-api", Timestamp: now}))
+
+	runs, err := db.GetMonitoringHistory("test-api", time.Hour)
+	require.NoError(t, err)
+	oldestID := runs[2].ID
+	otherRuns, err := db.GetMonitoringHistory("other-api", time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		endpoint     config.EndpointConfig
+		expectedBody string
+		expectError  bool
+	}{
+		{
+			name:         "previous run by default",
+			endpoint:     config.EndpointConfig{ID: "test-api"},
+			expectedBody: `{"run": 1}`,
+		},
+		{
+			name:         "fixed run",
+			endpoint:     config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyFixed, BaselineRunID: oldestID},
+			expectedBody: `{"run": 3}`,
+		},
+		{
+			name:        "fixed run of another endpoint",
+			endpoint:    config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyFixed, BaselineRunID: otherRuns[0].ID},
+			expectError: true,
+		},
+		{
+			name:        "missing fixed run",
+			endpoint:    config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyFixed, BaselineRunID: 9999},
+			expectError: true,
+		},
+		{
+			name:         "two runs ago",
+			endpoint:     config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyNAgo, BaselineRunsAgo: 2},
+			expectedBody: `{"run": 2}`,
+		},
+		{
+			name:        "more runs ago than stored",
+			endpoint:    config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyNAgo, BaselineRunsAgo: 10},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, err := getBaselineFromStorage(db, tt.endpoint)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, baseline)
+			assert.Equal(t, tt.expectedBody, string(baseline.Body))
+		})
+	}
+
+	baseline, err := getBaselineFromStorage(db, config.EndpointConfig{ID: "unknown-api"})
+	require.NoError(t, err)
+	assert.Nil(t, baseline)
+}
+
+func TestGetBaselineFromStorageMajority(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	// Newest first: a one-off degraded response followed by the usual shape,
+	// with values that change from run to run
+	now := time.Now()
+	bodies := []string{
+		`{"error": "upstream timeout"}`,
+		`{"items": [{"id": 5}], "total": 5}`,
+		`{"items": [{"id": 4}], "total": 4}`,
+		`{"error": "upstream timeout"}`,
+		`{"items": [{"id": 2}], "total": 2}`,
+	}
+	for i, body := range bodies {
+		require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+			EndpointID:     "flaky-api",
+			Timestamp:      now.Add(-time.Duration(i) * time.Minute),
+			ResponseStatus: 200,
+			ResponseBody:   body,
+		}))
+	}
+
+	majority := config.EndpointConfig{ID: "flaky-api", BaselineStrategy: config.BaselineStrategyMajority}
+	baseline, err := getBaselineFromStorage(db, majority)
+	require.NoError(t, err)
+	require.NotNil(t, baseline)
+	assert.Equal(t, `{"items": [{"id": 5}], "total": 5}`, string(baseline.Body), "the latest run of the common shape")
+
+	// Only the last baseline_runs runs vote; ties go to the most recent shape
+	majority.BaselineRuns = 2
+	baseline, err = getBaselineFromStorage(db, majority)
+	require.NoError(t, err)
+	assert.Equal(t, `{"error": "upstream timeout"}`, string(baseline.Body))
+
+	baseline, err = getBaselineFromStorage(db, config.EndpointConfig{ID: "unknown-api", BaselineStrategy: config.BaselineStrategyMajority})
+	require.NoError(t, err)
+	assert.Nil(t, baseline)
+}
+
+func TestResponseTimeHistory(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i := 1; i <= performanceHistoryRuns+5; i++ {
+		require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+			EndpointID:     "test-api",
+			Timestamp:      now.Add(-time.Duration(i) * time.Minute),
+			ResponseStatus: 200,
+			ResponseTimeMs: int64(100 + i),
+		}))
+	}
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:      "test-api",
+		Timestamp:       now,
+		FailureCategory: "timeout",
+		ResponseTimeMs:  30000,
+	}))
+
+	history, err := responseTimeHistory(db, config.EndpointConfig{ID: "test-api", Interval: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, history, performanceHistoryRuns)
+	assert.Equal(t, 101*time.Millisecond, history[0])
+}
+
+func TestGetBaselineFromStorageSkipsFailedRuns(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "test-api",
+		Timestamp:      now.Add(-2 * time.Minute),
+		ResponseStatus: 200,
+		ResponseBody:   `{"ok": true}`,
+	}))
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:      "test-api",
+		Timestamp:       now.Add(-time.Minute),
+		FailureCategory: "timeout",
+		ErrorMessage:    "request failed: context deadline exceeded",
+	}))
+
+	baseline, err := getBaselineFromStorage(db, config.EndpointConfig{ID: "test-api"})
+	require.NoError(t, err)
+	require.NotNil(t, baseline)
+	assert.Equal(t, 200, baseline.StatusCode)
+	assert.Equal(t, `{"ok": true}`, string(baseline.Body))
+
+	twoAgo := config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyNAgo, BaselineRunsAgo: 2}
+	_, err = getBaselineFromStorage(db, twoAgo)
+	assert.ErrorContains(t, err, "only 1 are stored")
+
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "test-api",
+		Timestamp:      now,
+		ResponseStatus: 200,
+		ResponseBody:   `{"ok": false}`,
+	}))
+	baseline, err = getBaselineFromStorage(db, twoAgo)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok": true}`, string(baseline.Body))
+
+	runs, err := db.GetMonitoringHistory("test-api", time.Hour)
+	require.NoError(t, err)
+	failedRun := runs[1]
+	require.Equal(t, "timeout", failedRun.FailureCategory)
+	_, err = getBaselineFromStorage(db, config.EndpointConfig{ID: "test-api", BaselineStrategy: config.BaselineStrategyFixed, BaselineRunID: failedRun.ID})
+	assert.Error(t, err)
+
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{EndpointID: "down-api", Timestamp: now, FailureCategory: "network"}))
+	baseline, err = getBaselineFromStorage(db, config.EndpointConfig{ID: "down-api"})
+	require.NoError(t, err)
+	assert.Nil(t, baseline)
+}
+
 func TestDetermineExitCode(t *testing.T) {
 	tests := []struct {
-		name           string
-		result         *CIResult
-		failOnSeverity string
-		failOnBreaking bool
-		expectedCode   int
+		name             string
+		result           *CIResult
+		failOnSeverity   string
+		failOnBreaking   bool
+		failOnValidation bool
+		expectedCode     int
 	}{
 		{
 			name: "no changes",
@@ -296,11 +651,28 @@ func TestDetermineExitCode(t *testing.T) {
 			failOnBreaking: false,
 			expectedCode:   ExitCodeGeneralError,
 		},
+		{
+			name: "validation errors without fail-on-validation",
+			result: &CIResult{
+				ValidationErrors: 2,
+			},
+			failOnSeverity: "high",
+			expectedCode:   ExitCodeSuccess,
+		},
+		{
+			name: "validation errors with fail-on-validation",
+			result: &CIResult{
+				ValidationErrors: 2,
+			},
+			failOnSeverity:   "high",
+			failOnValidation: true,
+			expectedCode:     ExitCodeValidationError,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			code := determineExitCode(tt.result, tt.failOnSeverity, tt.failOnBreaking)
+			code := determineExitCode(tt.result, tt.failOnSeverity, tt.failOnBreaking, tt.failOnValidation)
 			assert.Equal(t, tt.expectedCode, code)
 		})
 	}
@@ -365,6 +737,73 @@ func TestConvertToJUnit(t *testing.T) {
 	assert.Equal(t, "EndpointError", failCase.Error.Type)
 }
 
+func TestConvertToJUnitChangeDetails(t *testing.T) {
+	diffResult := &drift.DiffResult{
+		StructuralChanges: []drift.StructuralChange{
+			{
+				Type:        drift.ChangeTypeFieldRemoved,
+				Path:        "$.user.id",
+				Description: "Field 'user.id' was removed",
+				OldValue:    "<42 & co>",
+				Severity:    drift.SeverityCritical,
+				Breaking:    true,
+			},
+		},
+		DataChanges: []drift.DataChange{
+			{
+				Path:        "$.user.name",
+				OldValue:    "Ada",
+				NewValue:    strings.Repeat("x", 300),
+				ChangeType:  drift.ChangeTypeValueChange,
+				Severity:    drift.SeverityLow,
+				Description: "Value changed",
+			},
+		},
+		BreakingChanges: []drift.BreakingChange{
+			{Type: drift.ChangeTypeFieldRemoved, Path: "$.user.id", Mitigation: "Restore field 'user.id'"},
+		},
+	}
+	changes := convertDriftToCIChanges(diffResult, false)
+	assert.Equal(t, "Restore field 'user.id'", changes[0].Mitigation)
+	assert.Empty(t, changes[1].Mitigation)
+
+	result := &CIResult{
+		EndpointsChecked: 1,
+		Endpoints: []CIEndpointResult{
+			{
+				ID:              "users",
+				Method:          "GET",
+				URL:             "https://api.example.com/users/1",
+				StatusCode:      200,
+				BreakingChanges: 1,
+				Changes:         changes,
+			},
+		},
+	}
+
+	testCase := convertToJUnit(result).TestCases[0]
+	require.NotNil(t, testCase.Failure)
+	content := testCase.Failure.Content
+	assert.Contains(t, content, "field_removed at $.user.id: Field 'user.id' was removed (severity: critical) [BREAKING]")
+	assert.Contains(t, content, "\n  before: <42 & co>")
+	assert.Contains(t, content, "\n  mitigation: Restore field 'user.id'")
+	assert.Contains(t, content, "\n  before: Ada\n  after: "+strings.Repeat("x", junitValueLimit-3)+"...")
+
+	assert.Contains(t, testCase.SystemErr, "endpoint: users")
+	assert.Contains(t, testCase.SystemErr, "breaking_changes: 1")
+	assert.Contains(t, testCase.SystemErr, "critical: 1")
+	assert.Contains(t, testCase.SystemErr, "low: 1")
+
+	output, err := xml.Marshal(testCase)
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "before: &lt;42 &amp; co&gt;")
+	assert.Contains(t, string(output), "<system-err>endpoint: users")
+
+	var decoded JUnitTestCase
+	require.NoError(t, xml.Unmarshal(output, &decoded))
+	assert.Equal(t, content, decoded.Failure.Content)
+}
+
 func TestOutputCIResults(t *testing.T) {
 	result := &CIResult{
 		Success:          true,
@@ -392,7 +831,7 @@ func TestOutputCIResults(t *testing.T) {
 		tmpFile.Close()
 
 		// Output to file
-		err = outputCIResults(result, "json", tmpFile.Name())
+		err = outputCIResults(&config.Config{}, result, "json", tmpFile.Name())
 		require.NoError(t, err)
 
 		// Read and verify
@@ -416,7 +855,7 @@ func TestOutputCIResults(t *testing.T) {
 		tmpFile.Close()
 
 		// Output to file
-		err = outputCIResults(result, "junit", tmpFile.Name())
+		err = outputCIResults(&config.Config{}, result, "junit", tmpFile.Name())
 		require.NoError(t, err)
 
 		// Read and verify
@@ -432,6 +871,52 @@ func TestOutputCIResults(t *testing.T) {
 		assert.Len(t, parsed.TestCases, 1)
 	})
 
+	t.Run("NDJSON output", func(t *testing.T) {
+		tmpFile, err := os.CreateTemp(".", "ci-result-*.ndjson")
+		require.NoError(t, err)
+		defer os.Remove(tmpFile.Name())
+		tmpFile.Close()
+
+		err = outputCIResults(&config.Config{}, result, "ndjson", tmpFile.Name())
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(tmpFile.Name())
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		require.Len(t, lines, 2)
+
+		var endpointLine map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &endpointLine))
+		assert.Equal(t, "endpoint", endpointLine["type"])
+		assert.Equal(t, "test-api", endpointLine["id"])
+
+		var summaryLine map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &summaryLine))
+		assert.Equal(t, "summary", summaryLine["type"])
+		assert.Equal(t, true, summaryLine["success"])
+		assert.NotContains(t, summaryLine, "endpoints")
+	})
+
+	t.Run("SARIF output", func(t *testing.T) {
+		tmpFile, err := os.CreateTemp(".", "ci-result-*.sarif")
+		require.NoError(t, err)
+		defer os.Remove(tmpFile.Name())
+		tmpFile.Close()
+
+		err = outputCIResults(&config.Config{}, result, "sarif", tmpFile.Name())
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(tmpFile.Name())
+		require.NoError(t, err)
+
+		var parsed SARIFLog
+		require.NoError(t, json.Unmarshal(data, &parsed))
+		assert.Equal(t, "2.1.0", parsed.Version)
+		require.Len(t, parsed.Runs, 1)
+		assert.Empty(t, parsed.Runs[0].Results)
+	})
+
 	t.Run("Summary output", func(t *testing.T) {
 		// Create temporary file
 		tmpFile, err := os.CreateTemp(".", "ci-result-*.txt")
@@ -440,7 +925,7 @@ func TestOutputCIResults(t *testing.T) {
 		tmpFile.Close()
 
 		// Output to file
-		err = outputCIResults(result, "summary", tmpFile.Name())
+		err = outputCIResults(&config.Config{}, result, "summary", tmpFile.Name())
 		require.NoError(t, err)
 
 		// Read and verify
@@ -452,6 +937,44 @@ func TestOutputCIResults(t *testing.T) {
 	})
 }
 
+func TestGetBaselineFromFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	endpointConfig := config.EndpointConfig{
+		ID:               "users-api",
+		BaselineStrategy: config.BaselineStrategyFile,
+		BaselineFile:     filepath.Join(dir, "baselines", "users.json"),
+	}
+	current := &drift.Response{
+		StatusCode:   200,
+		Headers:      map[string]string{"Content-Type": "application/json"},
+		Body:         []byte(`{"id": 1, "name": "Grace"}`),
+		ResponseTime: 120 * time.Millisecond,
+	}
+
+	_, err := getBaselineFromFile(endpointConfig, current)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "driftwatch accept users-api")
+
+	require.NoError(t, writeGoldenFile(endpointConfig.BaselineFile, []byte(`{"id": 1, "name": "Ada"}`), dir))
+
+	baseline, err := getBaselineFromFile(endpointConfig, current)
+	require.NoError(t, err)
+	assert.Equal(t, current.StatusCode, baseline.StatusCode)
+	assert.Equal(t, current.Headers, baseline.Headers)
+	assert.Zero(t, baseline.ResponseTime)
+
+	result, err := drift.NewDiffEngine().CompareResponses(baseline, current)
+	require.NoError(t, err)
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "$.name", result.DataChanges[0].Path)
+
+	require.NoError(t, os.WriteFile(endpointConfig.BaselineFile, []byte("not json"), 0o600))
+	_, err = getBaselineFromFile(endpointConfig, current)
+	assert.Error(t, err)
+}
+
 func TestLoadBaselineData(t *testing.T) {
 	// Create test baseline data
 	baselineData := map[string]*drift.Response{
@@ -489,6 +1012,106 @@ func TestLoadBaselineData(t *testing.T) {
 	assert.Equal(t, `{"test": "data"}`, string(response.Body))
 }
 
+func TestParseGitBaselineSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         string
+		expectedRef  string
+		expectedPath string
+		expectError  bool
+	}{
+		{"valid spec", "origin/main:baseline.json", "origin/main", "baseline.json", false},
+		{"nested path", "HEAD~1:testdata/baseline.json", "HEAD~1", "testdata/baseline.json", false},
+		{"missing separator", "origin/main", "", "", true},
+		{"empty ref", ":baseline.json", "", "", true},
+		{"empty path", "origin/main:", "", "", true},
+		{"option injection", "--output=/tmp/x:baseline.json", "", "", true},
+		{"range ref", "main..feature:baseline.json", "", "", true},
+		{"path traversal", "main:../secret.json", "", "", true},
+		{"absolute path", "main:/etc/passwd", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, path, err := parseGitBaselineSpec(tt.spec)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRef, ref)
+			assert.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    []severityThreshold
+		expectError bool
+	}{
+		{"bare severity", "high", []severityThreshold{{Severity: "high"}}, false},
+		{"counts", "high:3, Critical:1", []severityThreshold{{Severity: "high", Count: 3}, {Severity: "critical", Count: 1}}, false},
+		{"mixed", "medium,critical:2", []severityThreshold{{Severity: "medium"}, {Severity: "critical", Count: 2}}, false},
+		{"unknown severity", "severe:1", nil, true},
+		{"zero count", "high:0", nil, true},
+		{"invalid count", "high:many", nil, true},
+		{"empty", " , ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds, err := parseFailOn(tt.value)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, thresholds)
+		})
+	}
+}
+
+func TestLoadBaselineFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	tempDir := t.TempDir()
+	runGit := func(args ...string) {
+		gitCmd := exec.Command("git", args...)
+		gitCmd.Dir = tempDir
+		gitCmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := gitCmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	baselineJSON := `{"test-api": {"status_code": 201, "headers": {}, "body": "e30="}}`
+	runGit("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "baseline.json"), []byte(baselineJSON), 0o644))
+	runGit("add", "baseline.json")
+	runGit("commit", "-q", "-m", "baseline")
+	// Working tree changes must not affect the committed baseline
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "baseline.json"), []byte("not json"), 0o644))
+
+	oldDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tempDir))
+	defer os.Chdir(oldDir)
+
+	loaded, err := loadBaselineFromGit("HEAD:baseline.json")
+	require.NoError(t, err)
+	require.Contains(t, loaded, "test-api")
+	assert.Equal(t, 201, loaded["test-api"].StatusCode)
+
+	_, err = loadBaselineFromGit("HEAD:missing.json")
+	assert.Error(t, err)
+}
+
 func TestGenerateCISummary(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -536,3 +1159,80 @@ func TestGenerateCISummary(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffOptionsForEndpoint(t *testing.T) {
+	enabled := true
+	endpoint := config.EndpointConfig{
+		ID:          "products",
+		URL:         "https://api.complex.com/v2/products",
+		Method:      "GET",
+		CompareRoot: "$.products",
+		Validation: config.ValidationConfig{
+			IgnoreFields:       []string{"generated_at"},
+			RequiredFields:     []string{"meta.total"},
+			EmbeddedJSONFields: []string{"products[*].metadata"},
+			UnorderedArrays:    []string{"products[*].tags"},
+			TrackedFields:      []string{"meta.total"},
+			NullAsMissing:      &enabled,
+			ShapeOnly:          &enabled,
+			NumericTolerance:   0.05,
+		},
+		PerformanceMode:      config.PerformanceModeThreshold,
+		PerformanceThreshold: 2 * time.Second,
+	}
+
+	options, err := diffOptionsForEndpoint(endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, "$.products", options.CompareRoot)
+	assert.Equal(t, []string{"meta.total"}, options.RequiredFields)
+	assert.Equal(t, []string{"products[*].metadata"}, options.EmbeddedJSONFields)
+	assert.Equal(t, []string{"products[*].tags"}, options.UnorderedArrays)
+	assert.Equal(t, []string{"meta.total"}, options.TrackedFields)
+	assert.Equal(t, drift.PerformanceModeThreshold, options.PerformanceMode)
+	assert.Equal(t, 2*time.Second, options.ResponseTimeThreshold)
+	assert.True(t, options.NullAsMissing)
+	assert.True(t, options.ShapeOnly)
+	assert.Equal(t, []string{"generated_at"}, options.IgnoreFields)
+	assert.Equal(t, 0.05, options.NumericTolerance)
+
+	options, err = diffOptionsForEndpoint(config.ApplySensitivity(config.EndpointConfig{ID: "search"}, config.SensitivityLenient))
+	require.NoError(t, err)
+	assert.True(t, options.ShapeOnly)
+	assert.True(t, options.NullAsMissing)
+	assert.Contains(t, options.IgnoreFields, "request_id")
+	assert.Equal(t, 0.01, options.NumericTolerance)
+
+	endpoint.SpecFile = filepath.Join("..", "internal", "validator", "testdata", "complex-api.yaml")
+	options, err = diffOptionsForEndpoint(endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"meta.total"}, options.RequiredFields)
+	require.NotNil(t, options.ResponseSchema)
+	assert.Contains(t, validator.RequiredSchemaPaths(options.ResponseSchema), "$.products[*].id")
+
+	specFile := filepath.Join(t.TempDir(), "enum-api.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte(`swagger: "2.0"
+info:
+  title: Enum API
+  version: "1.0"
+paths:
+  /v2/products:
+    get:
+      responses:
+        "200":
+          description: OK
+          schema:
+            type: object
+            properties:
+              tier:
+                type: string
+                enum: [free, pro]
+`), 0o644))
+	endpoint.SpecFile = specFile
+	options, err = diffOptionsForEndpoint(endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]interface{}{"$.tier": {"free", "pro"}}, options.EnumValues)
+
+	endpoint.SpecFile = "missing-spec.yaml"
+	_, err = diffOptionsForEndpoint(endpoint)
+	assert.Error(t, err)
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/retention"
 	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/spf13/cobra"
 )
@@ -16,7 +18,11 @@ var cleanupCmd = &cobra.Command{
 	Long: `Clean up old monitoring data based on retention policies and optimize the database.
 
 This command removes old monitoring runs, drifts, and alerts according to the configured
-retention policies. It also performs database optimization to reclaim disk space.
+retention policies. Endpoints with max_runs set also keep only their newest monitoring
+runs, however recent the older ones are. With retention.compact_after or --compact set,
+consecutive runs older than that with the same status and response body are merged into
+the first of them, which records how many checks it stands for and when the last one
+was made. It also performs database optimization to reclaim disk space.
 
 Examples:
   driftwatch cleanup                    # Clean up using configured retention policies
@@ -24,6 +30,7 @@ Examples:
   driftwatch cleanup --monitoring 7d   # Clean monitoring runs older than 7 days
   driftwatch cleanup --drifts 30d      # Clean drifts older than 30 days
   driftwatch cleanup --alerts 14d      # Clean alerts older than 14 days
+  driftwatch cleanup --compact 24h     # Merge identical runs older than 24 hours
   driftwatch cleanup --vacuum          # Only perform database optimization
   driftwatch cleanup --stats           # Show database statistics`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -35,7 +42,7 @@ Examples:
 		logger := GetLogger()
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -66,6 +73,10 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("failed to get %s flag: %w", "alerts", err)
 		}
+		compactAge, err := cmd.Flags().GetDuration("compact")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "compact", err)
+		}
 
 		// Show database statistics if requested
 		if showStats {
@@ -96,6 +107,10 @@ Examples:
 			alertsCutoff = now.Add(-alertsAge)
 		}
 
+		if compactAge == 0 {
+			compactAge = cfg.Retention.CompactAfter
+		}
+
 		logger.Info("Starting cleanup process",
 			"dry_run", dryRun,
 			"monitoring_cutoff", monitoringCutoff.Format(time.RFC3339),
@@ -111,33 +126,25 @@ Examples:
 			fmt.Println()
 		}
 
-		var totalCleaned int64
-
-		// Clean up monitoring runs
+		opts := retention.CleanupOptions{DryRun: dryRun}
 		if monitoringAge > 0 || !cmd.Flags().Changed("monitoring") {
-			cleaned, err := cleanupMonitoringRuns(db, monitoringCutoff, dryRun)
-			if err != nil {
-				return fmt.Errorf("failed to cleanup monitoring runs: %w", err)
-			}
-			totalCleaned += cleaned
+			opts.MonitoringRunsOlderThan = &monitoringCutoff
+			opts.MaxRuns = cfg.MaxRunsByEndpoint()
+		}
+		if compactAge > 0 {
+			compactCutoff := now.Add(-compactAge)
+			opts.CompactRunsOlderThan = &compactCutoff
 		}
-
-		// Clean up drifts
 		if driftsAge > 0 || !cmd.Flags().Changed("drifts") {
-			cleaned, err := cleanupDrifts(db, driftsCutoff, dryRun)
-			if err != nil {
-				return fmt.Errorf("failed to cleanup drifts: %w", err)
-			}
-			totalCleaned += cleaned
+			opts.DriftsOlderThan = &driftsCutoff
 		}
-
-		// Clean up alerts
 		if alertsAge > 0 || !cmd.Flags().Changed("alerts") {
-			cleaned, err := cleanupAlerts(db, alertsCutoff, dryRun)
-			if err != nil {
-				return fmt.Errorf("failed to cleanup alerts: %w", err)
-			}
-			totalCleaned += cleaned
+			opts.AlertsOlderThan = &alertsCutoff
+		}
+
+		totalCleaned, err := cleanupWithOptions(retention.NewService(db, &cfg.Retention, logger), opts)
+		if err != nil {
+			return err
 		}
 
 		// Perform database optimization if not dry run and records were cleaned
@@ -172,6 +179,7 @@ func init() {
 	cleanupCmd.Flags().Duration("monitoring", 0, "clean monitoring runs older than this duration (e.g., 7d, 24h)")
 	cleanupCmd.Flags().Duration("drifts", 0, "clean drifts older than this duration (e.g., 30d, 720h)")
 	cleanupCmd.Flags().Duration("alerts", 0, "clean alerts older than this duration (e.g., 14d, 336h)")
+	cleanupCmd.Flags().Duration("compact", 0, "merge identical consecutive monitoring runs older than this duration (defaults to retention.compact_after)")
 }
 
 // Helper functions
@@ -194,67 +202,73 @@ func showDatabaseStats(db storage.Storage) error {
 	return nil
 }
 
-func cleanupMonitoringRuns(db storage.Storage, cutoff time.Time, dryRun bool) (int64, error) {
-	if dryRun {
-		// For dry run, we need to count records that would be deleted
-		// Since we don't have a count method, we'll use the cleanup method with a transaction rollback
-		// For now, we'll just show the cutoff date
-		fmt.Printf("📈 Would clean monitoring runs older than %s\n", cutoff.Format("2006-01-02 15:04:05"))
-		return 0, nil
-	}
-
-	cleaned, err := db.CleanupOldMonitoringRuns(cutoff)
+// cleanupWithOptions removes the records selected by opts through the
+// retention service, reports what was removed, or would be in a dry run, and
+// returns how many records were removed
+func cleanupWithOptions(service *retention.Service, opts retention.CleanupOptions) (int64, error) {
+	result, err := service.CleanupWithOptions(opts)
 	if err != nil {
 		return 0, err
 	}
 
-	if cleaned > 0 {
-		fmt.Printf("📈 Cleaned %d monitoring runs\n", cleaned)
-	} else {
-		fmt.Println("📈 No old monitoring runs to clean")
-	}
-
-	return cleaned, nil
-}
-
-func cleanupDrifts(db storage.Storage, cutoff time.Time, dryRun bool) (int64, error) {
-	if dryRun {
-		fmt.Printf("🔄 Would clean drifts older than %s\n", cutoff.Format("2006-01-02 15:04:05"))
-		return 0, nil
-	}
+	const dateFormat = "2006-01-02 15:04:05"
 
-	cleaned, err := db.CleanupOldDrifts(cutoff)
-	if err != nil {
-		return 0, err
+	if opts.MonitoringRunsOlderThan != nil {
+		switch {
+		case opts.DryRun:
+			fmt.Printf("📈 Would clean monitoring runs older than %s\n", opts.MonitoringRunsOlderThan.Format(dateFormat))
+		case result.MonitoringRunsCleaned > 0:
+			fmt.Printf("📈 Cleaned %d monitoring runs\n", result.MonitoringRunsCleaned)
+		default:
+			fmt.Println("📈 No old monitoring runs to clean")
+		}
 	}
 
-	if cleaned > 0 {
-		fmt.Printf("🔄 Cleaned %d drifts\n", cleaned)
-	} else {
-		fmt.Println("🔄 No old drifts to clean")
+	if opts.DryRun {
+		endpointIDs := make([]string, 0, len(opts.MaxRuns))
+		for endpointID := range opts.MaxRuns {
+			endpointIDs = append(endpointIDs, endpointID)
+		}
+		sort.Strings(endpointIDs)
+		for _, endpointID := range endpointIDs {
+			fmt.Printf("📈 Would keep only the newest %d monitoring runs of %s\n", opts.MaxRuns[endpointID], endpointID)
+		}
 	}
 
-	return cleaned, nil
-}
-
-func cleanupAlerts(db storage.Storage, cutoff time.Time, dryRun bool) (int64, error) {
-	if dryRun {
-		fmt.Printf("🚨 Would clean alerts older than %s\n", cutoff.Format("2006-01-02 15:04:05"))
-		return 0, nil
+	if opts.CompactRunsOlderThan != nil {
+		switch {
+		case opts.DryRun:
+			fmt.Printf("📈 Would merge identical consecutive monitoring runs older than %s\n", opts.CompactRunsOlderThan.Format(dateFormat))
+		case result.MonitoringRunsCompacted > 0:
+			fmt.Printf("📈 Merged %d identical monitoring runs into earlier runs\n", result.MonitoringRunsCompacted)
+		default:
+			fmt.Println("📈 No identical monitoring runs to merge")
+		}
 	}
 
-	cleaned, err := db.CleanupOldAlerts(cutoff)
-	if err != nil {
-		return 0, err
+	if opts.DriftsOlderThan != nil {
+		switch {
+		case opts.DryRun:
+			fmt.Printf("🔄 Would clean drifts older than %s\n", opts.DriftsOlderThan.Format(dateFormat))
+		case result.DriftsCleaned > 0:
+			fmt.Printf("🔄 Cleaned %d drifts\n", result.DriftsCleaned)
+		default:
+			fmt.Println("🔄 No old drifts to clean")
+		}
 	}
 
-	if cleaned > 0 {
-		fmt.Printf("🚨 Cleaned %d alerts\n", cleaned)
-	} else {
-		fmt.Println("🚨 No old alerts to clean")
+	if opts.AlertsOlderThan != nil {
+		switch {
+		case opts.DryRun:
+			fmt.Printf("🚨 Would clean alerts older than %s\n", opts.AlertsOlderThan.Format(dateFormat))
+		case result.AlertsCleaned > 0:
+			fmt.Printf("🚨 Cleaned %d alerts\n", result.AlertsCleaned)
+		default:
+			fmt.Println("🚨 No old alerts to clean")
+		}
 	}
 
-	return cleaned, nil
+	return result.TotalCleaned(), nil
 }
 
 func performVacuum(db storage.Storage, dryRun bool) error {
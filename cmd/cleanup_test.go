@@ -5,6 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/retention"
 	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -80,8 +83,15 @@ func TestCleanupHelperFunctions(t *testing.T) {
 	})
 
 	t.Run("CleanupMonitoringRuns", func(t *testing.T) {
+		service := retention.NewService(db, &config.RetentionConfig{}, logging.GetGlobalLogger())
+
+		// A dry run removes nothing
+		cleaned, err := cleanupWithOptions(service, retention.CleanupOptions{MonitoringRunsOlderThan: &cutoff, DryRun: true})
+		assert.NoError(t, err)
+		assert.Zero(t, cleaned)
+
 		// Test actual cleanup (not dry run)
-		cleaned, err := cleanupMonitoringRuns(db, cutoff, false)
+		cleaned, err = cleanupWithOptions(service, retention.CleanupOptions{MonitoringRunsOlderThan: &cutoff})
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), cleaned) // Should clean 1 old run
 
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// cloneCmd represents the clone command
+var cloneCmd = &cobra.Command{
+	Use:   "clone <source-id> <new-url>",
+	Short: "Copy an endpoint's configuration to a new URL",
+	Long: `Add a new endpoint that copies the configuration of an existing one.
+
+The method, headers, authentication, validation settings, interval and all other
+options of the source endpoint are applied to the new URL. This is useful for
+monitoring the same API across environments or regions. A baseline pinned to one
+of the source endpoint's runs or committed in its baseline_file is not copied; the
+clone compares against its own previous run until a new baseline is set.
+
+Examples:
+  driftwatch clone users-api https://staging.example.com/v1/users
+  driftwatch clone users-api https://eu.example.com/v1/users --id users-api-eu`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sourceID := args[0]
+		endpointURL := args[1]
+
+		if err := validateURL(endpointURL); err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
+		}
+
+		id, err := cmd.Flags().GetString("id")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "id", err)
+		}
+
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		source, err := cfg.GetEndpoint(sourceID)
+		if err != nil {
+			return err
+		}
+
+		if id == "" {
+			id = generateEndpointID(endpointURL, source.Method)
+		}
+
+		endpointConfig := cloneEndpointConfig(*source, id, endpointURL)
+		if err := registerEndpoint(cfg, endpointConfig); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Endpoint cloned successfully\n")
+		fmt.Printf("  Source: %s\n", sourceID)
+		fmt.Printf("  ID: %s\n", endpointConfig.ID)
+		fmt.Printf("  URL: %s\n", endpointConfig.URL)
+		fmt.Printf("  Method: %s\n", endpointConfig.Method)
+		fmt.Printf("  Interval: %s\n", endpointConfig.Interval)
+		if len(endpointConfig.Headers) > 0 {
+			fmt.Printf("  Headers: %d configured\n", len(endpointConfig.Headers))
+		}
+		if endpointConfig.Auth != nil && endpointConfig.Auth.Type != config.AuthTypeNone {
+			fmt.Printf("  Auth: %s\n", endpointConfig.Auth.Type)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+
+	cloneCmd.Flags().String("id", "", "ID of the new endpoint (auto-generated if not provided)")
+}
+
+// cloneEndpointConfig returns a deep copy of source with a new ID and URL. A
+// baseline pinned to one of the source's runs or committed in its baseline file
+// does not apply to the clone, so the fixed and file strategies fall back to
+// the default.
+func cloneEndpointConfig(source config.EndpointConfig, id, endpointURL string) config.EndpointConfig {
+	clone := source
+	clone.ID = id
+	clone.URL = endpointURL
+	clone.Headers = maps.Clone(source.Headers)
+
+	if source.Variants != nil {
+		clone.Variants = make([]config.EndpointVariant, len(source.Variants))
+		for i, variant := range source.Variants {
+			variant.Query = maps.Clone(variant.Query)
+			variant.Headers = maps.Clone(variant.Headers)
+			clone.Variants[i] = variant
+		}
+	}
+
+	if source.Auth != nil {
+		auth := *source.Auth
+		if source.Auth.Bearer != nil {
+			bearer := *source.Auth.Bearer
+			auth.Bearer = &bearer
+		}
+		if source.Auth.Basic != nil {
+			basic := *source.Auth.Basic
+			auth.Basic = &basic
+		}
+		if source.Auth.APIKey != nil {
+			apiKey := *source.Auth.APIKey
+			auth.APIKey = &apiKey
+		}
+		if source.Auth.OAuth2 != nil {
+			oauth2 := *source.Auth.OAuth2
+			oauth2.Scopes = slices.Clone(source.Auth.OAuth2.Scopes)
+			oauth2.ExtraParams = maps.Clone(source.Auth.OAuth2.ExtraParams)
+			auth.OAuth2 = &oauth2
+		}
+		clone.Auth = &auth
+	}
+
+	clone.Validation.IgnoreFields = slices.Clone(source.Validation.IgnoreFields)
+	clone.Validation.RequiredFields = slices.Clone(source.Validation.RequiredFields)
+	clone.Validation.VolatileCookies = slices.Clone(source.Validation.VolatileCookies)
+	clone.Validation.EmbeddedJSONFields = slices.Clone(source.Validation.EmbeddedJSONFields)
+	clone.Validation.UnorderedArrays = slices.Clone(source.Validation.UnorderedArrays)
+	clone.Validation.TrackedFields = slices.Clone(source.Validation.TrackedFields)
+	clone.Validation.HeaderPatterns = maps.Clone(source.Validation.HeaderPatterns)
+	clone.Validation.IgnoreValuePatterns = slices.Clone(source.Validation.IgnoreValuePatterns)
+	clone.Validation.BodyStatusCodes = slices.Clone(source.Validation.BodyStatusCodes)
+
+	if clone.BaselineStrategy == config.BaselineStrategyFixed || clone.BaselineStrategy == config.BaselineStrategyFile {
+		clone.BaselineStrategy = ""
+	}
+	clone.BaselineRunID = 0
+	clone.BaselineFile = ""
+
+	return clone
+}
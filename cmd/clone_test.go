@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneEndpointConfig(t *testing.T) {
+	source := config.EndpointConfig{
+		ID:       "users-api",
+		URL:      "https://api.example.com/v1/users",
+		Method:   "POST",
+		Interval: 10 * time.Minute,
+		Headers:  map[string]string{"Accept": "application/json"},
+		Auth: &config.AuthConfig{
+			Type: config.AuthTypeOAuth2,
+			OAuth2: &config.OAuth2Auth{
+				TokenURL: "https://auth.example.com/token",
+				ClientID: "client",
+				Scopes:   []string{"read"},
+			},
+		},
+		Validation: config.ValidationConfig{
+			StrictMode:     true,
+			IgnoreFields:   []string{"timestamp"},
+			HeaderPatterns: map[string]string{"ETag": `^"[a-f0-9]+"$`},
+		},
+		Variants: []config.EndpointVariant{
+			{Name: "eu", Query: map[string]string{"region": "eu"}, Headers: map[string]string{"X-Region": "eu"}},
+		},
+		RequestBodyFile:  "body.json",
+		Enabled:          true,
+		BaselineStrategy: config.BaselineStrategyFixed,
+		BaselineRunID:    42,
+	}
+
+	clone := cloneEndpointConfig(source, "users-api-eu", "https://eu.example.com/v1/users")
+
+	assert.Equal(t, "users-api-eu", clone.ID)
+	assert.Equal(t, "https://eu.example.com/v1/users", clone.URL)
+	assert.Equal(t, source.Method, clone.Method)
+	assert.Equal(t, source.Interval, clone.Interval)
+	assert.Equal(t, source.Headers, clone.Headers)
+	assert.Equal(t, source.Auth, clone.Auth)
+	assert.Equal(t, source.Validation, clone.Validation)
+	assert.Equal(t, source.Variants, clone.Variants)
+	assert.Equal(t, source.RequestBodyFile, clone.RequestBodyFile)
+	assert.True(t, clone.Enabled)
+
+	t.Run("pinned baseline is not copied", func(t *testing.T) {
+		assert.Empty(t, clone.BaselineStrategy)
+		assert.Zero(t, clone.BaselineRunID)
+	})
+
+	t.Run("committed baseline file is not copied", func(t *testing.T) {
+		fileSource := source
+		fileSource.BaselineStrategy = config.BaselineStrategyFile
+		fileSource.BaselineFile = "baselines/users-api.json"
+
+		fileClone := cloneEndpointConfig(fileSource, "users-api-eu", "https://eu.example.com/v1/users")
+		assert.Empty(t, fileClone.BaselineStrategy)
+		assert.Empty(t, fileClone.BaselineFile)
+	})
+
+	t.Run("clone does not share state with source", func(t *testing.T) {
+		clone.Headers["Accept"] = "text/plain"
+		clone.Auth.OAuth2.Scopes[0] = "write"
+		clone.Auth.OAuth2.ClientID = "other"
+		clone.Validation.IgnoreFields[0] = "id"
+		clone.Validation.HeaderPatterns["ETag"] = ".*"
+		clone.Variants[0].Name = "us"
+		clone.Variants[0].Query["region"] = "us"
+		clone.Variants[0].Headers["X-Region"] = "us"
+
+		assert.Equal(t, "application/json", source.Headers["Accept"])
+		assert.Equal(t, "read", source.Auth.OAuth2.Scopes[0])
+		assert.Equal(t, "client", source.Auth.OAuth2.ClientID)
+		assert.Equal(t, "timestamp", source.Validation.IgnoreFields[0])
+		assert.Equal(t, `^"[a-f0-9]+"$`, source.Validation.HeaderPatterns["ETag"])
+		assert.Equal(t, "eu", source.Variants[0].Name)
+		assert.Equal(t, "eu", source.Variants[0].Query["region"])
+		assert.Equal(t, "eu", source.Variants[0].Headers["X-Region"])
+	})
+}
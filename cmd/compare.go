@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:     "compare <old.json> <new.json>",
+	Aliases: []string{"diff"},
+	Short:   "Compare two JSON files with the drift engine",
+	Long: `Compare two local JSON files as if they were consecutive responses of an
+endpoint, and print the classified differences: each change's type, severity and
+whether it would break clients.
+
+This runs the same comparison as monitoring, fed from disk instead of HTTP, which
+is useful to diff captured payloads or to see how a change would be classified.
+With --endpoint, the comparison settings of that endpoint apply, such as its
+ignored fields and compare_root. With --explain, each change comes with the
+reasoning, confidence and rules behind its classification.
+
+The command exits with code 2 when breaking changes are found, so that it can be
+used in scripts.
+
+Examples:
+  driftwatch compare old.json new.json
+  driftwatch compare old.json new.json --format json
+  driftwatch compare old.json new.json --endpoint users-api  # Use the endpoint's comparison settings
+  driftwatch diff old.json new.json --explain  # Explain why each change is breaking or not`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "format", err)
+		}
+		endpointID, err := cmd.Flags().GetString("endpoint")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "endpoint", err)
+		}
+		explain, err := cmd.Flags().GetBool("explain")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "explain", err)
+		}
+
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported output format: %s (supported: table, json)", format)
+		}
+
+		var endpointConfig config.EndpointConfig
+		if cfg := GetConfig(); cfg != nil {
+			if endpointID != "" {
+				endpoint, err := cfg.GetEndpoint(endpointID)
+				if err != nil {
+					return err
+				}
+				endpointConfig = *endpoint
+			}
+			endpointConfig = config.ApplySensitivity(endpointConfig, cfg.Global.Sensitivity)
+		} else if endpointID != "" {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		result, err := compareFiles(args[0], args[1], endpointConfig, explain)
+		if err != nil {
+			return err
+		}
+
+		if err := outputCompareResult(os.Stdout, result, format); err != nil {
+			return err
+		}
+
+		if result.BreakingChanges > 0 {
+			exitWithCode(ExitCodeBreakingChanges, fmt.Sprintf("%d breaking changes between %s and %s", result.BreakingChanges, result.Old, result.New))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().String("format", "table", "output format (table, json)")
+	compareCmd.Flags().String("endpoint", "", "apply the comparison settings of this endpoint")
+	compareCmd.Flags().Bool("explain", false, "include the reasoning, confidence and heuristics behind each change's classification")
+}
+
+// CompareResult is the outcome of comparing two JSON files
+type CompareResult struct {
+	Old             string     `json:"old"`
+	New             string     `json:"new"`
+	Changes         []CIChange `json:"changes"`
+	BreakingChanges int        `json:"breaking_changes"`
+}
+
+// compareFiles compares the JSON bodies of two files as an endpoint's previous
+// and current responses, explaining the classification of each field change
+// when explain is set
+func compareFiles(oldPath, newPath string, endpointConfig config.EndpointConfig, explain bool) (*CompareResult, error) {
+	oldBody, err := readJSONFile(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newBody, err := readJSONFile(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	diffOptions, err := diffOptionsForEndpoint(endpointConfig)
+	if err != nil {
+		return nil, err
+	}
+	diffOptions.Explain = explain
+
+	previous := &drift.Response{StatusCode: 200, Body: oldBody}
+	current := &drift.Response{StatusCode: 200, Body: newBody}
+	diffResult, err := drift.NewDiffEngineWithOptions(diffOptions).CompareResponses(previous, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s and %s: %w", oldPath, newPath, err)
+	}
+
+	changes := convertDriftToCIChanges(diffResult, false)
+	if changes == nil {
+		changes = []CIChange{}
+	}
+
+	return &CompareResult{
+		Old:             oldPath,
+		New:             newPath,
+		Changes:         changes,
+		BreakingChanges: len(diffResult.BreakingChanges),
+	}, nil
+}
+
+// readJSONFile reads a file that must contain valid JSON
+func readJSONFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s does not contain valid JSON", path)
+	}
+	return data, nil
+}
+
+// outputCompareResult writes a comparison as a table or as JSON
+func outputCompareResult(w io.Writer, result *CompareResult, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	if len(result.Changes) == 0 {
+		fmt.Fprintf(w, "No differences between %s and %s.\n", result.Old, result.New)
+		return nil
+	}
+
+	fmt.Fprintf(w, "%d changes from %s to %s (%d breaking)\n\n", len(result.Changes), result.Old, result.New, result.BreakingChanges)
+	fmt.Fprintf(w, "%-10s %-20s %-8s %-30s %s\n", "SEVERITY", "TYPE", "BREAKING", "PATH", "DESCRIPTION")
+	fmt.Fprintln(w, strings.Repeat("-", 110))
+
+	for _, change := range result.Changes {
+		breaking := "no"
+		if change.Breaking {
+			breaking = "yes"
+		}
+		fmt.Fprintf(w, "%-10s %-20s %-8s %-30s %s\n",
+			change.Severity,
+			truncateString(change.Type, 20),
+			breaking,
+			truncateString(change.Path, 30),
+			change.Description)
+		if change.Explanation != nil {
+			fmt.Fprintf(w, "%-10s why: %s\n", "", formatExplanation(change.Explanation))
+		}
+	}
+
+	return nil
+}
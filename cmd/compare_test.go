@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	oldPath := write("old.json", `{"id": 1, "name": "Ada", "email": "ada@example.com"}`)
+	samePath := write("same.json", `{"email": "ada@example.com", "id": 1, "name": "Ada"}`)
+	removedPath := write("removed.json", `{"id": 1, "name": "Grace"}`)
+	invalidPath := write("invalid.json", `<html></html>`)
+
+	t.Run("identical documents", func(t *testing.T) {
+		result, err := compareFiles(oldPath, samePath, config.EndpointConfig{}, false)
+		require.NoError(t, err)
+		assert.Empty(t, result.Changes)
+		assert.Zero(t, result.BreakingChanges)
+	})
+
+	t.Run("classified changes", func(t *testing.T) {
+		result, err := compareFiles(oldPath, removedPath, config.EndpointConfig{}, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.BreakingChanges)
+
+		paths := make(map[string]CIChange)
+		for _, change := range result.Changes {
+			paths[change.Path] = change
+		}
+		assert.True(t, paths["$.email"].Breaking)
+		assert.False(t, paths["$.name"].Breaking)
+	})
+
+	t.Run("explained changes", func(t *testing.T) {
+		result, err := compareFiles(oldPath, removedPath, config.EndpointConfig{}, true)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Changes)
+		for _, change := range result.Changes {
+			require.NotNil(t, change.Explanation, change.Path)
+			assert.NotEmpty(t, change.Explanation.Reasoning)
+		}
+
+		var table bytes.Buffer
+		require.NoError(t, outputCompareResult(&table, result, "table"))
+		assert.Contains(t, table.String(), "why: ")
+	})
+
+	t.Run("endpoint comparison settings", func(t *testing.T) {
+		endpoint := config.EndpointConfig{Validation: config.ValidationConfig{IgnoreFields: []string{"$.email", "$.name"}}}
+		result, err := compareFiles(oldPath, removedPath, endpoint, false)
+		require.NoError(t, err)
+		assert.Empty(t, result.Changes)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := compareFiles(oldPath, invalidPath, config.EndpointConfig{}, false)
+		assert.ErrorContains(t, err, "does not contain valid JSON")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := compareFiles(oldPath, filepath.Join(dir, "missing.json"), config.EndpointConfig{}, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestOutputCompareResult(t *testing.T) {
+	result := &CompareResult{
+		Old: "old.json",
+		New: "new.json",
+		Changes: []CIChange{
+			{Type: "field_removed", Path: "$.email", Severity: "high", Description: "Field removed", Breaking: true},
+		},
+		BreakingChanges: 1,
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, outputCompareResult(&table, result, "table"))
+	assert.Contains(t, table.String(), "1 changes from old.json to new.json (1 breaking)")
+	assert.Contains(t, table.String(), "field_removed")
+	assert.Contains(t, table.String(), "$.email")
+
+	var out bytes.Buffer
+	require.NoError(t, outputCompareResult(&out, result, "json"))
+	var decoded CompareResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, *result, decoded)
+
+	var empty bytes.Buffer
+	require.NoError(t, outputCompareResult(&empty, &CompareResult{Old: "a.json", New: "b.json", Changes: []CIChange{}}, "table"))
+	assert.Equal(t, "No differences between a.json and b.json.\n", empty.String())
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
@@ -16,10 +17,17 @@ var configCmd = &cobra.Command{
 	Short: "Manage configuration",
 	Long: `Manage DriftWatch configuration including viewing, validating, and initializing config files.
 
+When no config file is found, the configuration is read from the DRIFTWATCH_CONFIG
+environment variable as inline YAML or JSON. With --config-from-stdin it is read
+from standard input instead, which suits container deployments.
+
 Examples:
   driftwatch config show          # Show current configuration
   driftwatch config validate     # Validate configuration
-  driftwatch config init         # Initialize default configuration file`,
+  driftwatch config lint         # Suggest best practices
+  driftwatch config init         # Initialize default configuration file
+  driftwatch config sync --check # Compare endpoints in the file and the database
+  driftwatch config validate --config-from-stdin < driftwatch.json`,
 }
 
 // configShowCmd shows the current configuration
@@ -65,11 +73,18 @@ var configValidateCmd = &cobra.Command{
 			return fmt.Errorf("failed to get %s flag: %w", "config", err)
 		}
 
-		// Load config to trigger validation
-		cfg, err := config.LoadConfig(configFile)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Configuration validation failed:\n%v\n", err)
-			return err
+		// Load config to trigger validation. Standard input was already read
+		// and validated during initialization.
+		cfg := GetConfig()
+		if !cfgFromStdin {
+			cfg, err = config.LoadConfig(configFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Configuration validation failed:\n%v\n", err)
+				return err
+			}
+		}
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
 		}
 
 		fmt.Printf("Configuration is valid ✓\n")
@@ -81,6 +96,76 @@ var configValidateCmd = &cobra.Command{
 	},
 }
 
+// configLintCmd reports best-practice warnings for the configuration
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Suggest best practices for the configuration",
+	Long: `Check the DriftWatch configuration for settings that are valid but likely to
+cause noisy or unsafe monitoring, and suggest improvements.
+
+Unlike validate, lint never fails: its warnings are advice. It reports:
+  - endpoints that ignore no fields, whose timestamps or request IDs drift on every check
+  - endpoints checked more often than every 5 minutes
+  - secrets written in the file rather than referenced from the environment
+  - alerting that is disabled or has no enabled channel
+  - monitoring data that is never cleaned up
+
+Examples:
+  driftwatch config lint
+  driftwatch config lint --output json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "config", err)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output", err)
+		}
+		if outputFormat != "table" && outputFormat != "json" {
+			return fmt.Errorf("unsupported output format: %s (supported: table, json)", outputFormat)
+		}
+
+		// Secrets are checked as written, before ${VAR} references are
+		// substituted. Standard input was already read during initialization.
+		cfg := GetConfig()
+		if !cfgFromStdin {
+			cfg, err = config.LoadRawConfig(configFile)
+			if err != nil {
+				return err
+			}
+		}
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		return outputLintWarnings(os.Stdout, config.LintConfig(cfg), outputFormat)
+	},
+}
+
+// outputLintWarnings writes configuration lint warnings as text or as JSON
+func outputLintWarnings(w io.Writer, warnings []config.LintWarning, format string) error {
+	if format == "json" {
+		if warnings == nil {
+			warnings = []config.LintWarning{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(warnings)
+	}
+
+	if len(warnings) == 0 {
+		fmt.Fprintln(w, "No warnings ✓")
+		return nil
+	}
+
+	fmt.Fprintf(w, "%d warnings:\n", len(warnings))
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "\n%s\n  %s\n  suggestion: %s\n", warning.Field, warning.Message, warning.Suggestion)
+	}
+	return nil
+}
+
 // configInitCmd initializes a default configuration file
 var configInitCmd = &cobra.Command{
 	Use:   "init",
@@ -127,8 +212,10 @@ func init() {
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configLintCmd)
 
 	// Add flags
 	configShowCmd.Flags().StringP("output", "o", "yaml", "output format (json, yaml)")
 	configInitCmd.Flags().BoolP("force", "f", false, "overwrite existing configuration file")
+	configLintCmd.Flags().StringP("output", "o", "table", "output format (table, json)")
 }
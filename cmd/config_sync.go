@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources an endpoint configuration is read from
+const (
+	syncSourceFile = "file"
+	syncSourceDB   = "db"
+)
+
+// endpointDiscrepancy describes an endpoint whose configuration differs between
+// the configuration file and the database
+type endpointDiscrepancy struct {
+	ID     string
+	File   *config.EndpointConfig // nil when the endpoint is only in the database
+	Stored *config.EndpointConfig // nil when the endpoint is only in the file
+	Row    *storage.Endpoint
+	Fields []string // Settings that differ, by their configuration file name
+}
+
+// describe returns a one-line description of the discrepancy
+func (d endpointDiscrepancy) describe() string {
+	switch {
+	case d.Stored == nil:
+		return "missing from the database"
+	case d.File == nil:
+		return "missing from the configuration file"
+	default:
+		return fmt.Sprintf("differs in %s", strings.Join(d.Fields, ", "))
+	}
+}
+
+// configSyncCmd compares endpoint configurations in the file and the database
+var configSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Compare endpoint configurations in the file and the database",
+	Long: `Compare the configuration of each endpoint in the configuration file with the
+copy stored in the database, and report endpoints that are missing from either
+or whose settings differ. The two copies can drift apart when a command such as
+'driftwatch update' fails after writing only one of them.
+
+--check, the default, only reports discrepancies and fails if there are any.
+--apply copies endpoints from the source selected by --prefer to the other one.
+Endpoints that only exist in the other source are reported but left in place.
+
+Examples:
+  driftwatch config sync --check
+  driftwatch config sync --apply                 # The configuration file wins
+  driftwatch config sync --apply --prefer db     # The database wins`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		check, err := cmd.Flags().GetBool("check")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "check", err)
+		}
+		apply, err := cmd.Flags().GetBool("apply")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "apply", err)
+		}
+		prefer, err := cmd.Flags().GetString("prefer")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "prefer", err)
+		}
+
+		if check && apply {
+			return fmt.Errorf("--check and --apply cannot be used together")
+		}
+		if prefer != syncSourceFile && prefer != syncSourceDB {
+			return fmt.Errorf("unsupported source: %s (supported: %s, %s)", prefer, syncSourceFile, syncSourceDB)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		rows, err := db.ListEndpoints()
+		if err != nil {
+			return fmt.Errorf("failed to list endpoints: %w", err)
+		}
+
+		discrepancies, err := compareEndpointSources(cfg.Endpoints, rows)
+		if err != nil {
+			return err
+		}
+
+		if len(discrepancies) == 0 {
+			fmt.Printf("✓ The configuration file and the database agree on %d endpoints\n", len(cfg.Endpoints))
+			return nil
+		}
+
+		for _, discrepancy := range discrepancies {
+			fmt.Printf("Endpoint '%s': %s\n", discrepancy.ID, discrepancy.describe())
+		}
+
+		if !apply {
+			return fmt.Errorf("%d endpoints differ between the configuration file and the database (reconcile with --apply)", len(discrepancies))
+		}
+
+		synced, configChanged, err := applyEndpointSync(cfg, db, discrepancies, prefer)
+		if err != nil {
+			return err
+		}
+
+		if configChanged {
+			if err := saveConfigToFile(cfg); err != nil {
+				return fmt.Errorf("failed to save configuration file: %w", err)
+			}
+		}
+
+		fmt.Printf("\n✓ Synced %d endpoints from the %s\n", synced, syncSourceName(prefer))
+		if skipped := len(discrepancies) - synced; skipped > 0 {
+			fmt.Printf("  %d endpoints only in the %s were left in place\n", skipped, syncSourceName(otherSyncSource(prefer)))
+		}
+
+		return nil
+	},
+}
+
+// compareEndpointSources returns the endpoints that are missing from the
+// configuration file or the database, or whose configurations differ, ordered
+// by ID
+func compareEndpointSources(endpoints []config.EndpointConfig, rows []*storage.Endpoint) ([]endpointDiscrepancy, error) {
+	discrepancies := make(map[string]*endpointDiscrepancy)
+
+	for i := range endpoints {
+		endpoint := endpoints[i]
+		discrepancies[endpoint.ID] = &endpointDiscrepancy{ID: endpoint.ID, File: &endpoint}
+	}
+
+	for _, row := range rows {
+		stored, err := storedEndpointConfig(row)
+		if err != nil {
+			return nil, err
+		}
+
+		discrepancy, exists := discrepancies[row.ID]
+		if !exists {
+			discrepancy = &endpointDiscrepancy{ID: row.ID}
+			discrepancies[row.ID] = discrepancy
+		}
+		discrepancy.Stored = stored
+		discrepancy.Row = row
+	}
+
+	ids := make([]string, 0, len(discrepancies))
+	for id := range discrepancies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var result []endpointDiscrepancy
+	for _, id := range ids {
+		discrepancy := discrepancies[id]
+		if discrepancy.File != nil && discrepancy.Stored != nil {
+			fields, err := differingEndpointFields(discrepancy.File, discrepancy.Stored)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare endpoint %s: %w", id, err)
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			discrepancy.Fields = fields
+		}
+		result = append(result, *discrepancy)
+	}
+
+	return result, nil
+}
+
+// storedEndpointConfig decodes the configuration stored with an endpoint row.
+// Rows without a stored configuration are described by their columns.
+func storedEndpointConfig(row *storage.Endpoint) (*config.EndpointConfig, error) {
+	stored := &config.EndpointConfig{
+		ID:       row.ID,
+		URL:      row.URL,
+		Method:   row.Method,
+		SpecFile: row.SpecFile,
+	}
+	if row.Config == "" {
+		return stored, nil
+	}
+
+	if err := json.Unmarshal([]byte(row.Config), stored); err != nil {
+		return nil, fmt.Errorf("failed to decode stored configuration of endpoint %s: %w", row.ID, err)
+	}
+	return stored, nil
+}
+
+// differingEndpointFields returns the configuration file names of the settings
+// that differ between two endpoint configurations
+func differingEndpointFields(a, b *config.EndpointConfig) ([]string, error) {
+	if reflect.DeepEqual(a, b) {
+		return nil, nil
+	}
+
+	aFields, err := endpointFields(a)
+	if err != nil {
+		return nil, err
+	}
+	bFields, err := endpointFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range aFields {
+		names[name] = true
+	}
+	for name := range bFields {
+		names[name] = true
+	}
+
+	var fields []string
+	for name := range names {
+		if !reflect.DeepEqual(aFields[name], bFields[name]) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+// endpointFields returns the settings of an endpoint as written to the
+// configuration file
+func endpointFields(endpoint *config.EndpointConfig) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// applyEndpointSync copies the endpoints of the preferred source over the other
+// one. It returns the number of endpoints copied and whether the configuration
+// was changed and needs to be saved.
+func applyEndpointSync(cfg *config.Config, db storage.Storage, discrepancies []endpointDiscrepancy, prefer string) (int, bool, error) {
+	synced := 0
+	configChanged := false
+
+	for _, discrepancy := range discrepancies {
+		switch prefer {
+		case syncSourceFile:
+			if discrepancy.File == nil {
+				continue
+			}
+
+			configJSON, err := json.Marshal(*discrepancy.File)
+			if err != nil {
+				return synced, configChanged, fmt.Errorf("failed to serialize endpoint config: %w", err)
+			}
+
+			now := time.Now()
+			row := &storage.Endpoint{
+				ID:        discrepancy.File.ID,
+				URL:       discrepancy.File.URL,
+				Method:    discrepancy.File.Method,
+				SpecFile:  discrepancy.File.SpecFile,
+				Config:    string(configJSON),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if discrepancy.Row != nil {
+				row.CreatedAt = discrepancy.Row.CreatedAt
+			}
+
+			if err := db.SaveEndpoint(row); err != nil {
+				return synced, configChanged, fmt.Errorf("failed to save endpoint %s to database: %w", row.ID, err)
+			}
+		case syncSourceDB:
+			if discrepancy.Stored == nil {
+				continue
+			}
+
+			var err error
+			if discrepancy.File == nil {
+				err = cfg.AddEndpoint(*discrepancy.Stored)
+			} else {
+				err = cfg.UpdateEndpoint(discrepancy.ID, *discrepancy.Stored)
+			}
+			if err != nil {
+				return synced, configChanged, fmt.Errorf("failed to update endpoint %s in config: %w", discrepancy.ID, err)
+			}
+			configChanged = true
+		}
+
+		synced++
+	}
+
+	return synced, configChanged, nil
+}
+
+// syncSourceName returns the name of a sync source for messages
+func syncSourceName(source string) string {
+	if source == syncSourceDB {
+		return "database"
+	}
+	return "configuration file"
+}
+
+// otherSyncSource returns the source that is not the given one
+func otherSyncSource(source string) string {
+	if source == syncSourceDB {
+		return syncSourceFile
+	}
+	return syncSourceDB
+}
+
+func init() {
+	configCmd.AddCommand(configSyncCmd)
+
+	configSyncCmd.Flags().Bool("check", false, "report discrepancies without changing anything (default)")
+	configSyncCmd.Flags().Bool("apply", false, "reconcile discrepancies by copying endpoints from the preferred source")
+	configSyncCmd.Flags().String("prefer", syncSourceFile, "source that wins when applying (file, db)")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedEndpoint returns the database row registered for an endpoint
+func storedEndpoint(t *testing.T, endpoint config.EndpointConfig) *storage.Endpoint {
+	configJSON, err := json.Marshal(endpoint)
+	require.NoError(t, err)
+
+	return &storage.Endpoint{
+		ID:        endpoint.ID,
+		URL:       endpoint.URL,
+		Method:    endpoint.Method,
+		Config:    string(configJSON),
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func syncTestEndpoints() (inSync, changed, fileOnly, dbOnly config.EndpointConfig) {
+	base := config.EndpointConfig{Method: "GET", Interval: 5 * time.Minute, Enabled: true}
+
+	inSync, changed, fileOnly, dbOnly = base, base, base, base
+	inSync.ID, inSync.URL = "in-sync", "https://api.example.com/in-sync"
+	changed.ID, changed.URL = "changed", "https://api.example.com/changed"
+	fileOnly.ID, fileOnly.URL = "file-only", "https://api.example.com/file-only"
+	dbOnly.ID, dbOnly.URL = "db-only", "https://api.example.com/db-only"
+	return inSync, changed, fileOnly, dbOnly
+}
+
+func TestCompareEndpointSources(t *testing.T) {
+	inSync, changed, fileOnly, dbOnly := syncTestEndpoints()
+
+	stale := changed
+	stale.Interval = time.Minute
+	stale.Headers = map[string]string{"Accept": "application/json"}
+
+	discrepancies, err := compareEndpointSources(
+		[]config.EndpointConfig{inSync, changed, fileOnly},
+		[]*storage.Endpoint{storedEndpoint(t, inSync), storedEndpoint(t, stale), storedEndpoint(t, dbOnly)},
+	)
+	require.NoError(t, err)
+	require.Len(t, discrepancies, 3)
+
+	assert.Equal(t, "changed", discrepancies[0].ID)
+	assert.Equal(t, []string{"headers", "interval"}, discrepancies[0].Fields)
+	assert.Equal(t, "differs in headers, interval", discrepancies[0].describe())
+	assert.Equal(t, "db-only", discrepancies[1].ID)
+	assert.Equal(t, "missing from the configuration file", discrepancies[1].describe())
+	assert.Equal(t, "file-only", discrepancies[2].ID)
+	assert.Equal(t, "missing from the database", discrepancies[2].describe())
+
+	t.Run("row without stored configuration", func(t *testing.T) {
+		row := &storage.Endpoint{ID: inSync.ID, URL: inSync.URL, Method: inSync.Method}
+		discrepancies, err := compareEndpointSources([]config.EndpointConfig{inSync}, []*storage.Endpoint{row})
+		require.NoError(t, err)
+		require.Len(t, discrepancies, 1)
+		assert.Equal(t, []string{"enabled", "interval"}, discrepancies[0].Fields)
+	})
+}
+
+func TestApplyEndpointSync(t *testing.T) {
+	inSync, changed, fileOnly, dbOnly := syncTestEndpoints()
+	stale := changed
+	stale.Interval = time.Minute
+
+	setup := func(t *testing.T) (*config.Config, storage.Storage, []endpointDiscrepancy) {
+		cfg := &config.Config{Endpoints: []config.EndpointConfig{inSync, changed, fileOnly}}
+
+		db, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+		for _, row := range []*storage.Endpoint{storedEndpoint(t, inSync), storedEndpoint(t, stale), storedEndpoint(t, dbOnly)} {
+			require.NoError(t, db.SaveEndpoint(row))
+		}
+
+		rows, err := db.ListEndpoints()
+		require.NoError(t, err)
+		discrepancies, err := compareEndpointSources(cfg.Endpoints, rows)
+		require.NoError(t, err)
+		return cfg, db, discrepancies
+	}
+
+	t.Run("file wins", func(t *testing.T) {
+		cfg, db, discrepancies := setup(t)
+
+		synced, configChanged, err := applyEndpointSync(cfg, db, discrepancies, syncSourceFile)
+		require.NoError(t, err)
+		assert.Equal(t, 2, synced)
+		assert.False(t, configChanged)
+
+		row, err := db.GetEndpoint("changed")
+		require.NoError(t, err)
+		stored, err := storedEndpointConfig(row)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, stored.Interval)
+		assert.Equal(t, 2026, row.CreatedAt.Year())
+
+		_, err = db.GetEndpoint("file-only")
+		assert.NoError(t, err)
+		_, err = db.GetEndpoint("db-only")
+		assert.NoError(t, err, "endpoints only in the database are left in place")
+
+		rows, err := db.ListEndpoints()
+		require.NoError(t, err)
+		remaining, err := compareEndpointSources(cfg.Endpoints, rows)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, "db-only", remaining[0].ID)
+	})
+
+	t.Run("database wins", func(t *testing.T) {
+		cfg, db, discrepancies := setup(t)
+
+		synced, configChanged, err := applyEndpointSync(cfg, db, discrepancies, syncSourceDB)
+		require.NoError(t, err)
+		assert.Equal(t, 2, synced)
+		assert.True(t, configChanged)
+
+		endpoint, err := cfg.GetEndpoint("changed")
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, endpoint.Interval)
+
+		_, err = cfg.GetEndpoint("db-only")
+		assert.NoError(t, err)
+		_, err = cfg.GetEndpoint("file-only")
+		assert.NoError(t, err, "endpoints only in the file are left in place")
+	})
+}
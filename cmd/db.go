@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and migrate the DriftWatch database schema",
+	Long: `Inspect and migrate the DriftWatch database schema.
+
+Migrations are applied automatically whenever DriftWatch opens the database.
+These commands let operators see which migrations are pending and apply them
+deliberately, for example before upgrading a production deployment.
+
+Examples:
+  driftwatch db status              # Show schema version and pending migrations
+  driftwatch db migrate --dry-run   # List migrations that would be applied
+  driftwatch db migrate             # Apply pending migrations`,
+}
+
+// dbStatusCmd shows the database schema version
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the database schema version and pending migrations",
+	Long: `Show the current schema version of the database, the migrations that have
+been applied and the migrations that are still pending. No migrations are applied.
+
+Examples:
+  driftwatch db status         # Show schema status
+  driftwatch db status --json  # Output schema status as JSON`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		jsonOutput, err := cmd.Flags().GetBool("json")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "json", err)
+		}
+
+		status, err := storage.GetMigrationStatus(cfg.Global.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to get migration status: %w", err)
+		}
+
+		if jsonOutput {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(status)
+		}
+
+		displayMigrationStatus(status)
+		return nil
+	},
+}
+
+// dbMigrateCmd applies pending migrations
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending database migrations",
+	Long: `Apply all pending migrations to the database and list each migration as it
+is applied. Take a backup first when migrating a production database.
+
+Examples:
+  driftwatch backup && driftwatch db migrate  # Back up, then migrate
+  driftwatch db migrate --dry-run             # Preview pending migrations`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "dry-run", err)
+		}
+
+		if dryRun {
+			status, err := storage.GetMigrationStatus(cfg.Global.DatabaseURL)
+			if err != nil {
+				return fmt.Errorf("failed to get migration status: %w", err)
+			}
+			if len(status.Pending) == 0 {
+				fmt.Printf("Database is up to date (schema version %d)\n", status.CurrentVersion)
+				return nil
+			}
+			fmt.Printf("Would apply %d migration(s) to schema version %d:\n", len(status.Pending), status.CurrentVersion)
+			for _, migration := range status.Pending {
+				fmt.Printf("  %d: %s\n", migration.Version, migration.Description)
+			}
+			return nil
+		}
+
+		applied, err := storage.ApplyMigrations(cfg.Global.DatabaseURL)
+		for _, migration := range applied {
+			fmt.Printf("✓ Applied migration %d: %s\n", migration.Version, migration.Description)
+		}
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date, no migrations applied")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+
+	dbStatusCmd.Flags().Bool("json", false, "output schema status as JSON")
+	dbMigrateCmd.Flags().Bool("dry-run", false, "list pending migrations without applying them")
+}
+
+// displayMigrationStatus prints the schema status in human-readable form
+func displayMigrationStatus(status *storage.MigrationStatus) {
+	fmt.Printf("Schema version: %d (latest %d)\n", status.CurrentVersion, status.LatestVersion)
+
+	if len(status.Applied) > 0 {
+		fmt.Println("\nApplied migrations:")
+		for _, migration := range status.Applied {
+			fmt.Printf("  %d: %s (applied %s)\n", migration.Version, migration.Description,
+				migration.AppliedAt.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if len(status.Pending) == 0 {
+		fmt.Println("\nNo pending migrations")
+		return
+	}
+
+	fmt.Println("\nPending migrations:")
+	for _, migration := range status.Pending {
+		fmt.Printf("  %d: %s\n", migration.Version, migration.Description)
+	}
+	fmt.Println("\nRun 'driftwatch db migrate' to apply them")
+}
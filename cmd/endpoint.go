@@ -27,7 +27,8 @@ Examples:
   driftwatch add https://api.example.com/v1/users
   driftwatch add https://api.example.com/v1/users --method POST --spec openapi.yaml
   driftwatch add https://api.example.com/v1/users --header "Authorization=Bearer token" --interval 5m
-  driftwatch add https://api.example.com/v1/users --id my-users-api --timeout 30s`,
+  driftwatch add https://api.example.com/v1/users --id my-users-api --timeout 30s
+  driftwatch add https://api.example.com/v1/search --sensitivity lenient`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		endpointURL := args[0]
@@ -82,12 +83,24 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("failed to get %s flag: %w", "required-fields", err)
 		}
+		embeddedJSONFields, err := cmd.Flags().GetStringSlice("embedded-json-fields")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "embedded-json-fields", err)
+		}
+		sensitivity, err := cmd.Flags().GetString("sensitivity")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "sensitivity", err)
+		}
 
 		// Validate method
 		if err := validateMethod(method); err != nil {
 			return fmt.Errorf("invalid HTTP method: %w", err)
 		}
 
+		if err := validateSensitivity(sensitivity); err != nil {
+			return err
+		}
+
 		// Validate interval
 		if err := validateInterval(interval); err != nil {
 			return fmt.Errorf("invalid interval: %w", err)
@@ -117,9 +130,11 @@ Examples:
 			RetryCount:      retryCount,
 			Enabled:         true,
 			Validation: config.ValidationConfig{
-				StrictMode:     strictMode,
-				IgnoreFields:   ignoreFields,
-				RequiredFields: requiredFields,
+				Sensitivity:        config.Sensitivity(sensitivity),
+				StrictMode:         strictMode,
+				IgnoreFields:       ignoreFields,
+				RequiredFields:     requiredFields,
+				EmbeddedJSONFields: embeddedJSONFields,
 			},
 		}
 
@@ -129,41 +144,8 @@ Examples:
 			return fmt.Errorf("configuration not loaded")
 		}
 
-		// Add endpoint to config using the utility function
-		if err := cfg.AddEndpoint(endpointConfig); err != nil {
-			return fmt.Errorf("failed to add endpoint: %w", err)
-		}
-
-		// Save to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
-		if err != nil {
-			return fmt.Errorf("failed to connect to database: %w", err)
-		}
-		defer db.Close()
-
-		// Convert config to JSON for storage
-		configJSON, err := json.Marshal(endpointConfig)
-		if err != nil {
-			return fmt.Errorf("failed to serialize endpoint config: %w", err)
-		}
-
-		endpoint := &storage.Endpoint{
-			ID:        id,
-			URL:       endpointURL,
-			Method:    method,
-			SpecFile:  specFile,
-			Config:    string(configJSON),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
-
-		if err := db.SaveEndpoint(endpoint); err != nil {
-			return fmt.Errorf("failed to save endpoint to database: %w", err)
-		}
-
-		// Save updated config to file
-		if err := saveConfigToFile(cfg); err != nil {
-			return fmt.Errorf("failed to save configuration file: %w", err)
+		if err := registerEndpoint(cfg, endpointConfig); err != nil {
+			return err
 		}
 
 		fmt.Printf("✓ Endpoint added successfully\n")
@@ -230,7 +212,7 @@ Examples:
 		}
 
 		// Load database to get status information
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -282,7 +264,7 @@ Examples:
 		}
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -427,6 +409,25 @@ Examples:
 			endpoint.Validation.RequiredFields = requiredFields
 		}
 
+		if cmd.Flags().Changed("embedded-json-fields") {
+			embeddedJSONFields, err := cmd.Flags().GetStringSlice("embedded-json-fields")
+			if err != nil {
+				return fmt.Errorf("failed to get %s flag: %w", "embedded-json-fields", err)
+			}
+			endpoint.Validation.EmbeddedJSONFields = embeddedJSONFields
+		}
+
+		if cmd.Flags().Changed("sensitivity") {
+			sensitivity, err := cmd.Flags().GetString("sensitivity")
+			if err != nil {
+				return fmt.Errorf("failed to get %s flag: %w", "sensitivity", err)
+			}
+			if err := validateSensitivity(sensitivity); err != nil {
+				return err
+			}
+			endpoint.Validation.Sensitivity = config.Sensitivity(sensitivity)
+		}
+
 		if cmd.Flags().Changed("disable") {
 			endpoint.Enabled = false
 		}
@@ -436,7 +437,7 @@ Examples:
 		}
 
 		// Update in database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -499,6 +500,8 @@ func init() {
 	addCmd.Flags().Bool("strict", false, "enable strict validation mode")
 	addCmd.Flags().StringSlice("ignore-fields", []string{}, "fields to ignore during validation")
 	addCmd.Flags().StringSlice("required-fields", []string{}, "fields that must be present")
+	addCmd.Flags().StringSlice("embedded-json-fields", []string{}, "string fields holding encoded JSON to diff field by field")
+	addCmd.Flags().String("sensitivity", "", "comparison preset (strict, balanced, lenient)")
 
 	listCmd.Flags().StringP("output", "o", "table", "output format (table, json, yaml)")
 	listCmd.Flags().Bool("enabled-only", false, "show only enabled endpoints")
@@ -515,6 +518,8 @@ func init() {
 	updateCmd.Flags().Bool("strict", false, "enable strict validation mode")
 	updateCmd.Flags().StringSlice("ignore-fields", []string{}, "fields to ignore during validation")
 	updateCmd.Flags().StringSlice("required-fields", []string{}, "fields that must be present")
+	updateCmd.Flags().StringSlice("embedded-json-fields", []string{}, "string fields holding encoded JSON to diff field by field")
+	updateCmd.Flags().String("sensitivity", "", "comparison preset (strict, balanced, lenient); empty uses the global preset")
 	updateCmd.Flags().Bool("disable", false, "disable monitoring for this endpoint")
 	updateCmd.Flags().Bool("enable", false, "enable monitoring for this endpoint")
 }
@@ -533,11 +538,11 @@ func validateURL(urlStr string) error {
 	}
 
 	if parsedURL.Scheme == "" {
-		return fmt.Errorf("URL must include scheme (http:// or https://)")
+		return fmt.Errorf("URL must include scheme (http://, https://, ws:// or wss://)")
 	}
 
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return fmt.Errorf("URL scheme must be http or https")
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" && parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
+		return fmt.Errorf("URL scheme must be http, https, ws or wss")
 	}
 
 	if parsedURL.Host == "" {
@@ -561,6 +566,14 @@ func validateMethod(method string) error {
 	return fmt.Errorf("unsupported method '%s' (supported: %s)", method, strings.Join(supportedMethods, ", "))
 }
 
+// validateSensitivity validates a comparison preset name; empty selects none
+func validateSensitivity(sensitivity string) error {
+	if sensitivity != "" && !config.Sensitivity(sensitivity).IsValid() {
+		return fmt.Errorf("invalid sensitivity '%s' (supported: strict, balanced, lenient)", sensitivity)
+	}
+	return nil
+}
+
 // validateInterval validates that the monitoring interval is within acceptable range
 func validateInterval(interval time.Duration) error {
 	if interval < time.Minute {
@@ -624,6 +637,49 @@ func generateEndpointID(urlStr, method string) string {
 	return id
 }
 
+// registerEndpoint adds an endpoint to the configuration, saves it to the database
+// and writes the updated configuration file
+func registerEndpoint(cfg *config.Config, endpointConfig config.EndpointConfig) error {
+	// Add endpoint to config using the utility function
+	if err := cfg.AddEndpoint(endpointConfig); err != nil {
+		return fmt.Errorf("failed to add endpoint: %w", err)
+	}
+
+	// Save to database
+	db, err := openStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	// Convert config to JSON for storage
+	configJSON, err := json.Marshal(endpointConfig)
+	if err != nil {
+		return fmt.Errorf("failed to serialize endpoint config: %w", err)
+	}
+
+	endpoint := &storage.Endpoint{
+		ID:        endpointConfig.ID,
+		URL:       endpointConfig.URL,
+		Method:    endpointConfig.Method,
+		SpecFile:  endpointConfig.SpecFile,
+		Config:    string(configJSON),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := db.SaveEndpoint(endpoint); err != nil {
+		return fmt.Errorf("failed to save endpoint to database: %w", err)
+	}
+
+	// Save updated config to file
+	if err := saveConfigToFile(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration file: %w", err)
+	}
+
+	return nil
+}
+
 // saveConfigToFile saves the configuration to the config file
 func saveConfigToFile(cfg *config.Config) error {
 	configPath := config.GetConfigFilePath(cfgFile)
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"net/http"
 	"path/filepath"
 	"testing"
 	"time"
@@ -122,6 +123,14 @@ func TestValidateMethod(t *testing.T) {
 	}
 }
 
+func TestValidateSensitivity(t *testing.T) {
+	for _, sensitivity := range []string{"", "strict", "balanced", "lenient"} {
+		assert.NoError(t, validateSensitivity(sensitivity), sensitivity)
+	}
+	assert.Error(t, validateSensitivity("relaxed"))
+	assert.Error(t, validateSensitivity("Strict"))
+}
+
 func TestValidateInterval(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -362,6 +371,8 @@ func TestAddCommand(t *testing.T) {
 			cmd.Flags().Bool("strict", false, "strict validation")
 			cmd.Flags().StringSlice("ignore-fields", []string{}, "ignore fields")
 			cmd.Flags().StringSlice("required-fields", []string{}, "required fields")
+			cmd.Flags().StringSlice("embedded-json-fields", []string{}, "embedded JSON fields")
+			cmd.Flags().String("sensitivity", "", "comparison preset")
 
 			// Set flags
 			for key, value := range tt.flags {
@@ -659,6 +670,19 @@ func TestUpdateCommand(t *testing.T) {
 				assert.False(t, ep.Enabled)
 			},
 		},
+		{
+			name: "update embedded JSON fields",
+			args: []string{"test-api"},
+			flags: map[string]string{
+				"embedded-json-fields": "config,events[*].payload",
+			},
+			wantErr: false,
+			verify: func(t *testing.T, cfg *config.Config) {
+				ep, err := cfg.GetEndpoint("test-api")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"config", "events[*].payload"}, ep.Validation.EmbeddedJSONFields)
+			},
+		},
 		{
 			name:    "update non-existent endpoint",
 			args:    []string{"non-existent"},
@@ -700,6 +724,8 @@ func TestUpdateCommand(t *testing.T) {
 			cmd.Flags().Bool("strict", false, "strict validation")
 			cmd.Flags().StringSlice("ignore-fields", []string{}, "ignore fields")
 			cmd.Flags().StringSlice("required-fields", []string{}, "required fields")
+			cmd.Flags().StringSlice("embedded-json-fields", []string{}, "embedded JSON fields")
+			cmd.Flags().String("sensitivity", "", "comparison preset")
 			cmd.Flags().Bool("disable", false, "disable endpoint")
 			cmd.Flags().Bool("enable", false, "enable endpoint")
 
@@ -728,3 +754,37 @@ func TestUpdateCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestSuggestInterval(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		responseTime time.Duration
+		expected     time.Duration
+	}{
+		{name: "default", responseTime: 100 * time.Millisecond, expected: 5 * time.Minute},
+		{name: "slow endpoint", responseTime: 3 * time.Second, expected: 15 * time.Minute},
+		{name: "cache max-age", cacheControl: "public, max-age=3600", expected: time.Hour},
+		{name: "short max-age is clamped", cacheControl: "max-age=10", expected: time.Minute},
+		{name: "long max-age is clamped", cacheControl: "max-age=604800", expected: 24 * time.Hour},
+		{name: "no-cache falls back", cacheControl: "no-cache, max-age=0", expected: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tt.cacheControl != "" {
+				headers.Set("Cache-Control", tt.cacheControl)
+			}
+			assert.Equal(t, tt.expected, suggestInterval(headers, tt.responseTime))
+		})
+	}
+}
+
+func TestIsJSONContentType(t *testing.T) {
+	assert.True(t, isJSONContentType("application/json"))
+	assert.True(t, isJSONContentType("application/json; charset=utf-8"))
+	assert.True(t, isJSONContentType("application/problem+json"))
+	assert.False(t, isJSONContentType("text/html"))
+	assert.False(t, isJSONContentType(""))
+}
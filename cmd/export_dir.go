@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/security"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// exportIndexFile is the manifest written alongside a directory export
+const exportIndexFile = "index.json"
+
+// ExportIndex lists the files of a directory export
+type ExportIndex struct {
+	ExportedAt time.Time             `json:"exported_at"`
+	Period     string                `json:"period"`
+	StartTime  time.Time             `json:"start_time"`
+	EndTime    time.Time             `json:"end_time"`
+	Format     string                `json:"format"`
+	Endpoints  []ExportIndexEndpoint `json:"endpoints"`
+}
+
+// ExportIndexEndpoint lists the files exported for one endpoint, relative to
+// the export directory
+type ExportIndexEndpoint struct {
+	ID             string `json:"id"`
+	DriftsFile     string `json:"drifts_file,omitempty"`
+	Drifts         int    `json:"drifts"`
+	RunsFile       string `json:"runs_file,omitempty"`
+	MonitoringRuns int    `json:"monitoring_runs"`
+}
+
+// Files returns the paths of the files exported for the endpoint, relative to
+// the export directory
+func (e ExportIndexEndpoint) Files() []string {
+	var files []string
+	if e.DriftsFile != "" {
+		files = append(files, e.DriftsFile)
+	}
+	if e.RunsFile != "" {
+		files = append(files, e.RunsFile)
+	}
+	return files
+}
+
+// exportToDirectory writes the drifts and monitoring runs of each endpoint to
+// their own files in dir, named <endpoint-id>.drifts.<format> and
+// <endpoint-id>.runs.<format>, followed by an index of the files written
+func exportToDirectory(db storage.Storage, format, dataType, endpointID string, window timeWindow, dir string) (*ExportIndex, error) {
+	switch format {
+	case "json", "yaml", "csv":
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	withDrifts := dataType == "drifts" || dataType == "all"
+	withRuns := dataType == "runs" || dataType == "all"
+	if !withDrifts && !withRuns {
+		return nil, fmt.Errorf("unsupported data type: %s (supported: drifts, runs, all)", dataType)
+	}
+
+	var endpointIDs []string
+	if endpointID != "" {
+		endpointIDs = []string{endpointID}
+	} else {
+		endpoints, err := db.ListEndpoints()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list endpoints: %w", err)
+		}
+		for _, ep := range endpoints {
+			endpointIDs = append(endpointIDs, ep.ID)
+		}
+	}
+
+	index := &ExportIndex{
+		ExportedAt: time.Now(),
+		Period:     window.String(),
+		StartTime:  window.Start,
+		EndTime:    window.End,
+		Format:     format,
+		Endpoints:  make([]ExportIndexEndpoint, 0, len(endpointIDs)),
+	}
+
+	for _, id := range endpointIDs {
+		// Endpoint IDs become file names, so they must not name other directories
+		if id == "" || id == "." || id == ".." || filepath.Base(id) != id {
+			return nil, fmt.Errorf("endpoint ID %q cannot be used as a file name", id)
+		}
+
+		entry := ExportIndexEndpoint{ID: id}
+
+		if withDrifts {
+			entry.DriftsFile = id + ".drifts." + format
+			counts, err := exportDrifts(db, format, id, window, filepath.Join(dir, entry.DriftsFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to export drifts of %s: %w", id, err)
+			}
+			entry.Drifts = counts["drifts"]
+		}
+
+		if withRuns {
+			entry.RunsFile = id + ".runs." + format
+			counts, err := exportMonitoringRuns(db, format, id, window, filepath.Join(dir, entry.RunsFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to export monitoring runs of %s: %w", id, err)
+			}
+			entry.MonitoringRuns = counts["monitoring_runs"]
+		}
+
+		index.Endpoints = append(index.Endpoints, entry)
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export index: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+	if err := security.SafeWriteFile(filepath.Join(dir, exportIndexFile), append(data, '\n'), cwd); err != nil {
+		return nil, fmt.Errorf("failed to write export index: %w", err)
+	}
+
+	return index, nil
+}
+
+// signDirectoryExport writes a signed manifest next to each file of a
+// directory export
+func signDirectoryExport(dir string, index *ExportIndex, signer security.Signer) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	for _, entry := range index.Endpoints {
+		for _, file := range entry.Files() {
+			counts := map[string]int{"drifts": entry.Drifts}
+			if file == entry.RunsFile {
+				counts = map[string]int{"monitoring_runs": entry.MonitoringRuns}
+			}
+			if _, err := security.SignExport(filepath.Join(dir, file), index.Format, counts, signer, cwd); err != nil {
+				return fmt.Errorf("failed to sign %s: %w", file, err)
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Signed export files in %s\n", dir)
+	return nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportToDirectory(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	for _, id := range []string{"users-api", "orders-api"} {
+		require.NoError(t, db.SaveEndpoint(&storage.Endpoint{ID: id, URL: "https://example.com/" + id, Method: "GET"}))
+		require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{EndpointID: id, Timestamp: now.Add(-time.Hour), ResponseStatus: 200}))
+	}
+	require.NoError(t, db.SaveDrift(&storage.Drift{EndpointID: "users-api", DetectedAt: now.Add(-time.Hour), DriftType: "field_removed", Severity: "high"}))
+
+	window := lastPeriod(24 * time.Hour)
+
+	t.Run("all data", func(t *testing.T) {
+		index, err := exportToDirectory(db, "json", "all", "", window, "backup")
+		require.NoError(t, err)
+		require.Len(t, index.Endpoints, 2)
+
+		var drifts []*storage.Drift
+		data, err := os.ReadFile(filepath.Join("backup", "users-api.drifts.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &drifts))
+		require.Len(t, drifts, 1)
+		assert.Equal(t, "users-api", drifts[0].EndpointID)
+
+		var runs []*storage.MonitoringRun
+		data, err = os.ReadFile(filepath.Join("backup", "orders-api.runs.json"))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &runs))
+		require.Len(t, runs, 1)
+		assert.Equal(t, "orders-api", runs[0].EndpointID)
+
+		var written ExportIndex
+		data, err = os.ReadFile(filepath.Join("backup", exportIndexFile))
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &written))
+		assert.Equal(t, "json", written.Format)
+
+		byID := make(map[string]ExportIndexEndpoint)
+		for _, entry := range written.Endpoints {
+			byID[entry.ID] = entry
+		}
+		assert.Equal(t, ExportIndexEndpoint{ID: "users-api", DriftsFile: "users-api.drifts.json", Drifts: 1, RunsFile: "users-api.runs.json", MonitoringRuns: 1}, byID["users-api"])
+		assert.Equal(t, 0, byID["orders-api"].Drifts)
+	})
+
+	t.Run("single endpoint and data type", func(t *testing.T) {
+		index, err := exportToDirectory(db, "csv", "runs", "orders-api", window, "runs-only")
+		require.NoError(t, err)
+		require.Len(t, index.Endpoints, 1)
+		assert.Equal(t, []string{"orders-api.runs.csv"}, index.Endpoints[0].Files())
+
+		assert.FileExists(t, filepath.Join("runs-only", "orders-api.runs.csv"))
+		assert.NoFileExists(t, filepath.Join("runs-only", "orders-api.drifts.csv"))
+		assert.NoFileExists(t, filepath.Join("runs-only", "users-api.runs.csv"))
+	})
+
+	t.Run("endpoint ID naming another directory", func(t *testing.T) {
+		_, err := exportToDirectory(db, "json", "all", "../escape", window, "backup")
+		assert.ErrorContains(t, err, "cannot be used as a file name")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := exportToDirectory(db, "xml", "all", "", window, "backup")
+		assert.ErrorContains(t, err, "unsupported format")
+	})
+}
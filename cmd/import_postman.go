@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/collection"
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// importPostmanCmd represents the import-postman command
+var importPostmanCmd = &cobra.Command{
+	Use:   "import-postman <collection-file>",
+	Short: "Add endpoints from a Postman collection or Insomnia export",
+	Long: `Add an endpoint for each request of a Postman collection (v2.0 or v2.1) or an
+Insomnia export (format 4).
+
+The URL, method, headers, body and authentication of each request are carried
+over. {{variables}} are resolved from the collection variables, or the base and
+folder environments of an Insomnia export; --var sets or overrides a variable.
+Requests that still use a variable without a value are skipped, as are requests
+whose endpoint ID already exists, so a collection can be imported again after
+it grows.
+
+Request bodies are written to files in --body-dir and referenced from the
+endpoint's request_body_file. Bearer, basic and API key authentication are
+imported; other authentication types are reported and left to configure by hand.
+
+Examples:
+  driftwatch import-postman collection.json
+  driftwatch import-postman collection.json --var baseUrl=https://staging.example.com
+  driftwatch import-postman insomnia.json --interval 10m --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vars, err := cmd.Flags().GetStringSlice("var")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "var", err)
+		}
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "interval", err)
+		}
+		bodyDir, err := cmd.Flags().GetString("body-dir")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "body-dir", err)
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "dry-run", err)
+		}
+
+		if err := validateInterval(interval); err != nil {
+			return fmt.Errorf("invalid interval: %w", err)
+		}
+
+		variables, err := parseVariables(vars)
+		if err != nil {
+			return fmt.Errorf("invalid variables: %w", err)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read collection: %w", err)
+		}
+		parsed, err := collection.Parse(data, variables)
+		if err != nil {
+			return err
+		}
+
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		name := parsed.Name
+		if name == "" {
+			name = args[0]
+		}
+		fmt.Printf("Importing %d requests from %s (%s)\n\n", len(parsed.Requests), name, parsed.Format)
+
+		ids := make(map[string]bool)
+		imported, skipped := 0, 0
+		for _, request := range parsed.Requests {
+			label := request.Name
+			if request.Folder != "" {
+				label = request.Folder + " / " + request.Name
+			}
+
+			if len(request.Unresolved) > 0 {
+				fmt.Printf("✗ %s: skipped, no value for %s (set with --var)\n", label, strings.Join(request.Unresolved, ", "))
+				skipped++
+				continue
+			}
+			if err := validateURL(request.URL); err != nil {
+				fmt.Printf("✗ %s: skipped, invalid URL: %v\n", label, err)
+				skipped++
+				continue
+			}
+
+			id := uniqueEndpointID(generateEndpointID(request.URL, request.Method), ids)
+			if _, err := cfg.GetEndpoint(id); err == nil {
+				fmt.Printf("- %s: skipped, endpoint %s already exists\n", label, id)
+				skipped++
+				continue
+			}
+
+			endpointConfig := importedEndpointConfig(request, id, interval)
+			if request.Body != "" {
+				endpointConfig.RequestBodyFile = filepath.Join(bodyDir, id+requestBodyExtension(request.Headers))
+			}
+
+			if !dryRun {
+				if err := writeRequestBody(endpointConfig.RequestBodyFile, request.Body); err != nil {
+					return err
+				}
+				if err := registerEndpoint(cfg, endpointConfig); err != nil {
+					if endpointConfig.RequestBodyFile != "" {
+						_ = os.Remove(endpointConfig.RequestBodyFile)
+					}
+					fmt.Printf("✗ %s: skipped, %v\n", label, err)
+					skipped++
+					continue
+				}
+			}
+
+			fmt.Printf("✓ %s: %s %s as %s\n", label, endpointConfig.Method, endpointConfig.URL, id)
+			for _, warning := range request.Warnings {
+				fmt.Printf("    warning: %s\n", warning)
+			}
+			imported++
+		}
+
+		verb := "Imported"
+		if dryRun {
+			verb = "Would import"
+		}
+		fmt.Printf("\n%s %d endpoints, skipped %d\n", verb, imported, skipped)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importPostmanCmd)
+
+	importPostmanCmd.Flags().StringSlice("var", []string{}, "collection variables (format: name=value), overriding the collection's own")
+	importPostmanCmd.Flags().DurationP("interval", "i", 5*time.Minute, "monitoring interval of the imported endpoints (1m to 24h)")
+	importPostmanCmd.Flags().String("body-dir", "request-bodies", "directory the request bodies are written to")
+	importPostmanCmd.Flags().Bool("dry-run", false, "show the endpoints that would be imported without adding them")
+}
+
+// parseVariables parses name=value variable assignments
+func parseVariables(vars []string) (map[string]string, error) {
+	variables := make(map[string]string, len(vars))
+	for _, variable := range vars {
+		name, value, ok := strings.Cut(variable, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid variable '%s' (expected name=value)", variable)
+		}
+		variables[strings.TrimSpace(name)] = value
+	}
+	return variables, nil
+}
+
+// uniqueEndpointID returns id, suffixed with a number if an earlier request of
+// the same import already took it, and records it as taken
+func uniqueEndpointID(id string, taken map[string]bool) string {
+	unique := id
+	for n := 2; taken[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", id, n)
+	}
+	taken[unique] = true
+	return unique
+}
+
+// importedEndpointConfig returns the endpoint configuration of a collection
+// request
+func importedEndpointConfig(request collection.Request, id string, interval time.Duration) config.EndpointConfig {
+	endpointConfig := config.EndpointConfig{
+		ID:       id,
+		URL:      request.URL,
+		Method:   request.Method,
+		Interval: interval,
+		Auth:     request.Auth,
+		Enabled:  true,
+	}
+	if len(request.Headers) > 0 {
+		endpointConfig.Headers = request.Headers
+	}
+	return endpointConfig
+}
+
+// requestBodyExtension returns the file extension of a request body, by the
+// content type of its request
+func requestBodyExtension(headers map[string]string) string {
+	for name, value := range headers {
+		if strings.EqualFold(name, "Content-Type") && strings.Contains(strings.ToLower(value), "json") {
+			return ".json"
+		}
+	}
+	return ".txt"
+}
+
+// writeRequestBody writes a request body to path, creating its directory
+func writeRequestBody(path, body string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create request body directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		return fmt.Errorf("failed to write request body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/collection"
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariables(t *testing.T) {
+	variables, err := parseVariables([]string{"baseUrl=https://api.example.com", " token =a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"baseUrl": "https://api.example.com", "token": "a=b"}, variables)
+
+	_, err = parseVariables([]string{"baseUrl"})
+	assert.Error(t, err)
+	_, err = parseVariables([]string{"=value"})
+	assert.Error(t, err)
+}
+
+func TestUniqueEndpointID(t *testing.T) {
+	taken := make(map[string]bool)
+
+	assert.Equal(t, "api-users-get", uniqueEndpointID("api-users-get", taken))
+	assert.Equal(t, "api-users-get-2", uniqueEndpointID("api-users-get", taken))
+	assert.Equal(t, "api-users-get-3", uniqueEndpointID("api-users-get", taken))
+	assert.Equal(t, "api-orders-get", uniqueEndpointID("api-orders-get", taken))
+}
+
+func TestImportedEndpointConfig(t *testing.T) {
+	request := collection.Request{
+		Name:    "Create user",
+		Method:  "POST",
+		URL:     "https://api.example.com/v1/users",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    `{"name": "Ada"}`,
+		Auth:    &config.AuthConfig{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "secret"}},
+	}
+
+	endpointConfig := importedEndpointConfig(request, "api-example-com-v1-users-post", 10*time.Minute)
+
+	assert.Equal(t, "api-example-com-v1-users-post", endpointConfig.ID)
+	assert.Equal(t, request.URL, endpointConfig.URL)
+	assert.Equal(t, "POST", endpointConfig.Method)
+	assert.Equal(t, 10*time.Minute, endpointConfig.Interval)
+	assert.Equal(t, request.Headers, endpointConfig.Headers)
+	assert.Equal(t, request.Auth, endpointConfig.Auth)
+	assert.True(t, endpointConfig.Enabled)
+	assert.Equal(t, ".json", requestBodyExtension(request.Headers))
+	assert.Equal(t, ".txt", requestBodyExtension(map[string]string{"content-type": "text/plain"}))
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	httpClient "github.com/k0ns0l/driftwatch/internal/http"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultSuggestedInterval = 5 * time.Minute
+	slowSuggestedInterval    = 15 * time.Minute
+	slowResponseThreshold    = 2 * time.Second
+)
+
+// initEndpointCmd represents the init-endpoint command
+var initEndpointCmd = &cobra.Command{
+	Use:   "init-endpoint <url>",
+	Short: "Probe an endpoint and suggest a monitoring configuration",
+	Long: `Probe an API endpoint with a test request and suggest a ready-to-use
+endpoint configuration.
+
+The response is inspected to detect its content type, to find fields whose values
+change on every request (timestamps, UUIDs, request identifiers) and to propose a
+monitoring interval from the Cache-Control header and response time. The suggested
+configuration is shown and added after confirmation.
+
+Examples:
+  driftwatch init-endpoint https://api.example.com/v1/users
+  driftwatch init-endpoint https://api.example.com/v1/users --header "Authorization=Bearer token"
+  driftwatch init-endpoint https://api.example.com/v1/users --id users-api --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpointURL := args[0]
+
+		if err := validateURL(endpointURL); err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
+		}
+
+		method, err := cmd.Flags().GetString("method")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "method", err)
+		}
+		headers, err := cmd.Flags().GetStringSlice("header")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "header", err)
+		}
+		id, err := cmd.Flags().GetString("id")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "id", err)
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "timeout", err)
+		}
+		assumeYes, err := cmd.Flags().GetBool("yes")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "yes", err)
+		}
+
+		method = strings.ToUpper(method)
+		if err := validateMethod(method); err != nil {
+			return fmt.Errorf("invalid HTTP method: %w", err)
+		}
+
+		headerMap, err := parseHeaders(headers)
+		if err != nil {
+			return fmt.Errorf("invalid headers: %w", err)
+		}
+
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		if id == "" {
+			id = generateEndpointID(endpointURL, method)
+		}
+
+		client := httpClient.NewClient(httpClient.ClientConfig{
+			Timeout:    timeout,
+			RetryCount: 0,
+			UserAgent:  cfg.Global.UserAgent,
+		})
+
+		fmt.Printf("Probing %s %s...\n", method, endpointURL)
+		resp, err := probeEndpoint(client, method, endpointURL, headerMap, timeout, cfg.Global.CABundleFile)
+		if err != nil {
+			return fmt.Errorf("test request failed: %w", err)
+		}
+
+		contentType := resp.Headers.Get("Content-Type")
+		fmt.Printf("  Status: %d\n", resp.StatusCode)
+		fmt.Printf("  Content-Type: %s\n", valueOrDefault(contentType, "(not set)"))
+		fmt.Printf("  Response Time: %s\n", resp.ResponseTime.Round(time.Millisecond))
+
+		if resp.StatusCode >= 400 {
+			fmt.Printf("  Warning: endpoint returned status %d; check the URL and headers before monitoring\n", resp.StatusCode)
+		}
+
+		var ignoreFields []string
+		if isJSONContentType(contentType) {
+			volatileFields, err := drift.FindVolatileFields(resp.Body)
+			if err != nil {
+				fmt.Printf("  Warning: %v\n", err)
+			}
+			if len(volatileFields) > 0 {
+				fmt.Println("\nVolatile fields (suggested for ignore_fields):")
+				for _, field := range volatileFields {
+					fmt.Printf("  %s (%s)\n", field.Path, field.Reason)
+					ignoreFields = append(ignoreFields, field.Path)
+				}
+			}
+		} else {
+			fmt.Println("  Response is not JSON; field suggestions are skipped")
+		}
+
+		endpointConfig := config.EndpointConfig{
+			ID:       id,
+			URL:      endpointURL,
+			Method:   method,
+			Interval: suggestInterval(resp.Headers, resp.ResponseTime),
+			Headers:  headerMap,
+			Enabled:  true,
+			Validation: config.ValidationConfig{
+				IgnoreFields: ignoreFields,
+			},
+		}
+
+		configYAML, err := yaml.Marshal([]config.EndpointConfig{endpointConfig})
+		if err != nil {
+			return fmt.Errorf("failed to serialize endpoint config: %w", err)
+		}
+		fmt.Printf("\nSuggested configuration:\n\n%s\n", string(configYAML))
+
+		if !assumeYes {
+			fmt.Print("Add this endpoint? (y/N): ")
+			var response string
+			if _, err := fmt.Scanln(&response); err != nil {
+				return fmt.Errorf("failed to read user input: %w", err)
+			}
+
+			if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
+				fmt.Println("Endpoint not added.")
+				return nil
+			}
+		}
+
+		if err := registerEndpoint(cfg, endpointConfig); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Endpoint '%s' added successfully\n", id)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initEndpointCmd)
+
+	initEndpointCmd.Flags().StringP("method", "m", "GET", "HTTP method (GET, POST, PUT, DELETE)")
+	initEndpointCmd.Flags().StringSliceP("header", "H", []string{}, "HTTP headers (format: key=value)")
+	initEndpointCmd.Flags().String("id", "", "endpoint ID (auto-generated if not provided)")
+	initEndpointCmd.Flags().Duration("timeout", 30*time.Second, "timeout for the test request")
+	initEndpointCmd.Flags().BoolP("yes", "y", false, "add the suggested configuration without confirmation")
+}
+
+// probeEndpoint performs a single test request against an endpoint, trusting
+// the given CA bundle, if any
+func probeEndpoint(client httpClient.Client, method, endpointURL string, headers map[string]string, timeout time.Duration, caBundleFile string) (*httpClient.Response, error) {
+	req, err := httpClient.NewRequest(method, endpointURL, nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return client.Do(req.WithContext(httpClient.WithCABundle(ctx, caBundleFile)))
+}
+
+// isJSONContentType reports whether a Content-Type header describes a JSON body
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// suggestInterval proposes a monitoring interval for an endpoint. A Cache-Control
+// max-age is used when present, slow endpoints are checked less often, and the
+// result is kept within the accepted interval range.
+func suggestInterval(headers http.Header, responseTime time.Duration) time.Duration {
+	interval := defaultSuggestedInterval
+
+	if maxAge, ok := cacheMaxAge(headers.Get("Cache-Control")); ok && maxAge > 0 {
+		interval = maxAge
+	} else if responseTime > slowResponseThreshold {
+		interval = slowSuggestedInterval
+	}
+
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	if interval > 24*time.Hour {
+		interval = 24 * time.Hour
+	}
+
+	return interval.Round(time.Minute)
+}
+
+// cacheMaxAge extracts the max-age directive from a Cache-Control header
+func cacheMaxAge(cacheControl string) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(directive), "=")
+		if !found || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return 0, false
+}
+
+// valueOrDefault returns value, or fallback when value is empty
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
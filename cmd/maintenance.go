@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// maintenanceCmd represents the maintenance command
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Manage maintenance windows that suppress drift alerts",
+	Long: `Manage maintenance windows, such as deploys, during which API changes are
+expected. Drift detected by the monitor during a window is still recorded, but
+tagged 'maintenance' and not alerted on. Windows are stored in the database, so
+they stay in effect when the monitor restarts.
+
+Examples:
+  driftwatch maintenance start --duration 30m              # All endpoints
+  driftwatch maintenance start --endpoint users-api        # A single endpoint
+  driftwatch maintenance start --auto-acknowledge          # Acknowledge drift recorded during the window
+  driftwatch maintenance stop                              # End all active windows`,
+}
+
+// maintenanceStartCmd starts a maintenance window
+var maintenanceStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start a maintenance window",
+	Long: `Start a maintenance window for all endpoints, or for a single endpoint with
+--endpoint. The window ends after --duration or when 'driftwatch maintenance stop'
+is run, whichever comes first.
+
+Examples:
+  driftwatch maintenance start --duration 1h
+  driftwatch maintenance start --endpoint users-api --auto-acknowledge`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		duration, err := cmd.Flags().GetDuration("duration")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "duration", err)
+		}
+		endpointID, err := cmd.Flags().GetString("endpoint")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "endpoint", err)
+		}
+		autoAcknowledge, err := cmd.Flags().GetBool("auto-acknowledge")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "auto-acknowledge", err)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		window, err := startMaintenanceWindow(cfg, db, endpointID, duration, autoAcknowledge, time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Started maintenance window for %s until %s\n",
+			maintenanceScope(window.EndpointID), window.EndsAt.Format("2006-01-02 15:04:05"))
+		if window.AutoAcknowledge {
+			fmt.Println("  Drift detected during the window will be acknowledged automatically")
+		}
+		return nil
+	},
+}
+
+// maintenanceStopCmd ends active maintenance windows
+var maintenanceStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "End active maintenance windows",
+	Long: `End the active maintenance windows of an endpoint with --endpoint, or every
+active window otherwise. Drift detected from then on is alerted again.
+
+Examples:
+  driftwatch maintenance stop
+  driftwatch maintenance stop --endpoint users-api`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		endpointID, err := cmd.Flags().GetString("endpoint")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "endpoint", err)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		ended, err := db.EndMaintenanceWindows(endpointID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if ended == 0 {
+			fmt.Printf("No active maintenance window for %s\n", maintenanceScope(endpointID))
+			return nil
+		}
+		fmt.Printf("✓ Ended %d maintenance window(s) for %s\n", ended, maintenanceScope(endpointID))
+		return nil
+	},
+}
+
+// startMaintenanceWindow saves a maintenance window starting at now. An empty
+// endpointID starts a window for every endpoint.
+func startMaintenanceWindow(cfg *config.Config, db storage.Storage, endpointID string, duration time.Duration, autoAcknowledge bool, now time.Time) (*storage.MaintenanceWindow, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	if endpointID != "" {
+		if _, err := cfg.GetEndpoint(endpointID); err != nil {
+			return nil, err
+		}
+	}
+
+	window := &storage.MaintenanceWindow{
+		EndpointID:      endpointID,
+		StartedAt:       now,
+		EndsAt:          now.Add(duration),
+		AutoAcknowledge: autoAcknowledge,
+	}
+	if err := db.SaveMaintenanceWindow(window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// maintenanceScope describes the endpoints a maintenance window applies to
+func maintenanceScope(endpointID string) string {
+	if endpointID == "" {
+		return "all endpoints"
+	}
+	return fmt.Sprintf("endpoint '%s'", endpointID)
+}
+
+func init() {
+	rootCmd.AddCommand(maintenanceCmd)
+	maintenanceCmd.AddCommand(maintenanceStartCmd)
+	maintenanceCmd.AddCommand(maintenanceStopCmd)
+
+	maintenanceStartCmd.Flags().Duration("duration", 30*time.Minute, "how long the window lasts")
+	maintenanceStartCmd.Flags().String("endpoint", "", "endpoint ID to limit the window to (default: all endpoints)")
+	maintenanceStartCmd.Flags().Bool("auto-acknowledge", false, "acknowledge drift detected during the window")
+	maintenanceStopCmd.Flags().String("endpoint", "", "endpoint ID whose windows to end (default: all windows)")
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartMaintenanceWindow(t *testing.T) {
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{{ID: "users", URL: "https://api.example.com/users", Method: "GET"}}}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	window, err := startMaintenanceWindow(cfg, db, "users", 30*time.Minute, true, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(30*time.Minute), window.EndsAt)
+
+	windows, err := db.GetActiveMaintenanceWindows(now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, "users", windows[0].EndpointID)
+	assert.True(t, windows[0].AutoAcknowledge)
+
+	_, err = startMaintenanceWindow(cfg, db, "unknown", 30*time.Minute, false, now)
+	assert.Error(t, err)
+
+	_, err = startMaintenanceWindow(cfg, db, "", 0, false, now)
+	assert.Error(t, err)
+}
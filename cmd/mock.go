@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/mock"
+	"github.com/k0ns0l/driftwatch/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+// mockCmd represents the mock command
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Serve example responses from an OpenAPI specification",
+	Long: `Start a local HTTP server that answers requests with example responses
+generated from an OpenAPI specification.
+
+Each request is matched to an operation by method and path. The server replies
+with the operation's success response, using the response example when one is
+defined and otherwise generating a body from the schema (example, default, enum
+or a placeholder for the type). Point an endpoint at the mock server to try out
+drift detection before monitoring a real API.
+
+Examples:
+  driftwatch mock --spec api.yaml                 # Serve on localhost:8080
+  driftwatch mock --spec api.yaml --port 9090     # Serve on a custom port`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		specFile, err := cmd.Flags().GetString("spec")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "spec", err)
+		}
+		port, err := cmd.Flags().GetInt("port")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "port", err)
+		}
+		host, err := cmd.Flags().GetString("host")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "host", err)
+		}
+
+		if specFile == "" {
+			return fmt.Errorf("--spec must be set to an OpenAPI specification file")
+		}
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid port: %d", port)
+		}
+
+		swagger, err := validator.NewValidator().LoadSpec(specFile)
+		if err != nil {
+			return fmt.Errorf("failed to load spec: %w", err)
+		}
+
+		server := &http.Server{
+			Addr:              net.JoinHostPort(host, strconv.Itoa(port)),
+			Handler:           mock.NewHandler(swagger),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		serverErr := make(chan error, 1)
+		go func() {
+			serverErr <- server.ListenAndServe()
+		}()
+
+		fmt.Printf("Serving mock responses for %s on http://%s%s\n", specFile, server.Addr, swagger.BasePath)
+		fmt.Println("Press Ctrl+C to stop")
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+		select {
+		case err := <-serverErr:
+			if !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("mock server failed: %w", err)
+			}
+		case sig := <-sigChan:
+			fmt.Printf("\nReceived signal %v, stopping mock server...\n", sig)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error stopping mock server: %w", err)
+		}
+
+		fmt.Println("Mock server stopped")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+
+	mockCmd.Flags().StringP("spec", "s", "", "OpenAPI specification file path")
+	mockCmd.Flags().IntP("port", "p", 8080, "port to listen on")
+	mockCmd.Flags().String("host", "localhost", "host to listen on")
+}
@@ -10,9 +10,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/alerting"
 	"github.com/k0ns0l/driftwatch/internal/config"
 	httpClient "github.com/k0ns0l/driftwatch/internal/http"
 	"github.com/k0ns0l/driftwatch/internal/monitor"
+	"github.com/k0ns0l/driftwatch/internal/sink"
 	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -26,11 +28,17 @@ var monitorCmd = &cobra.Command{
 
 This command starts a background process that polls all registered endpoints
 according to their configured intervals. The monitoring will continue until
-stopped with Ctrl+C or a termination signal.
+stopped with Ctrl+C or a termination signal, or until --max-runtime elapses.
+
+Monitoring shuts down gracefully on SIGINT, SIGTERM or at the end of
+--max-runtime: checks in progress are finished and the drifts they found are
+alerted on and delivered to the drift sink and OpenTelemetry collector before
+exiting. This makes it possible to run DriftWatch as a bounded job, such as a
+Kubernetes Job, rather than a permanent deployment.
 
 Examples:
   driftwatch monitor                    # Start monitoring all endpoints
-  driftwatch monitor --duration 1h     # Monitor for 1 hour then stop
+  driftwatch monitor --max-runtime 1h  # Monitor for 1 hour then stop
   driftwatch monitor --endpoints api1,api2  # Monitor specific endpoints only`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := GetConfig()
@@ -39,9 +47,19 @@ Examples:
 		}
 
 		// Get flags
-		duration, err := cmd.Flags().GetDuration("duration")
+		maxRuntime, err := cmd.Flags().GetDuration("max-runtime")
 		if err != nil {
-			return fmt.Errorf("failed to get %s flag: %w", "duration", err)
+			return fmt.Errorf("failed to get %s flag: %w", "max-runtime", err)
+		}
+		if !cmd.Flags().Changed("max-runtime") {
+			// --duration is the deprecated name of --max-runtime
+			maxRuntime, err = cmd.Flags().GetDuration("duration")
+			if err != nil {
+				return fmt.Errorf("failed to get %s flag: %w", "duration", err)
+			}
+		}
+		if maxRuntime < 0 {
+			return fmt.Errorf("--max-runtime cannot be negative")
 		}
 		endpointIDs, err := cmd.Flags().GetStringSlice("endpoints")
 		if err != nil {
@@ -53,7 +71,7 @@ Examples:
 		}
 
 		// Connect to storage
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -61,10 +79,11 @@ Examples:
 
 		// Create HTTP client
 		client := httpClient.NewClient(httpClient.ClientConfig{
-			Timeout:    cfg.Global.Timeout,
-			RetryCount: cfg.Global.RetryCount,
-			RetryDelay: cfg.Global.RetryDelay,
-			UserAgent:  cfg.Global.UserAgent,
+			Timeout:       cfg.Global.Timeout,
+			RetryCount:    cfg.Global.RetryCount,
+			RetryDelay:    cfg.Global.RetryDelay,
+			MaxRetryDelay: cfg.Global.MaxRetryDelay,
+			UserAgent:     cfg.Global.UserAgent,
 		})
 
 		// Create scheduler
@@ -77,28 +96,65 @@ Examples:
 			}
 		}
 
-		// Create context
-		ctx := context.Background()
-		if duration > 0 {
-			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(ctx, duration)
-			defer cancel()
+		// The context is not bounded by --max-runtime: the scheduler is stopped
+		// when it elapses, so that checks in progress are finished, not cancelled
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var alertManager alerting.AlertManager
+		if cfg.Alerting.Enabled {
+			alertManager, err = alerting.NewAlertManager(cfg, db)
+			if err != nil {
+				return fmt.Errorf("failed to create alert manager: %w", err)
+			}
+			scheduler.SetAlertManager(alertManager)
 		}
 
 		// Start monitoring
-		fmt.Printf("Starting monitoring of %d endpoints...\n", len(cfg.Endpoints))
+		fmt.Printf("Starting monitoring of %d endpoints...\n", len(cfg.ExpandedEndpoints()))
 		if err := scheduler.Start(ctx); err != nil {
 			return fmt.Errorf("failed to start monitoring: %w", err)
 		}
 
+		if alertManager != nil {
+			// Deliver alerts buffered during quiet hours once the window ends
+			if cfg.Alerting.QuietHours.Enabled {
+				digestScheduler := alerting.NewDigestScheduler(alertManager, alerting.DefaultDigestCheckInterval, GetLogger())
+				digestScheduler.Start(ctx)
+				defer digestScheduler.Stop()
+			}
+
+			// Alert on endpoints that stop responding successfully
+			if cfg.Alerting.Liveness.Enabled {
+				livenessScheduler := alerting.NewLivenessScheduler(alertManager, alerting.DefaultLivenessCheckInterval, GetLogger())
+				livenessScheduler.Start(ctx)
+				defer livenessScheduler.Stop()
+			}
+		}
+
+		// Stream every detected drift to the configured collectors
+		var driftSinks []*sink.DriftSink
+		if cfg.DriftSink.Enabled {
+			driftSinks = append(driftSinks, sink.NewDriftSink(db, &cfg.DriftSink, GetLogger()))
+		}
+		if cfg.OTel.Enabled {
+			driftSinks = append(driftSinks, sink.NewOTelExporter(db, &cfg.OTel, GetLogger()))
+		}
+		for _, driftSink := range driftSinks {
+			driftSink.Start(ctx)
+		}
+
 		if daemon {
+			for _, driftSink := range driftSinks {
+				driftSink.Stop()
+			}
 			fmt.Println("Monitoring started in daemon mode")
 			return nil
 		}
 
 		// Wait for completion or interruption
-		if duration > 0 {
-			fmt.Printf("Monitoring for %s... Press Ctrl+C to stop early\n", duration)
+		if maxRuntime > 0 {
+			fmt.Printf("Monitoring for %s... Press Ctrl+C to stop early\n", maxRuntime)
 		} else {
 			fmt.Println("Monitoring started... Press Ctrl+C to stop")
 		}
@@ -106,20 +162,25 @@ Examples:
 		// Set up signal handling
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
 
-		// Wait for signal or context completion
+		var deadline <-chan time.Time
+		if maxRuntime > 0 {
+			timer := time.NewTimer(maxRuntime)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+
+		// Wait for a signal or the end of the run time
 		select {
 		case sig := <-sigChan:
 			fmt.Printf("\nReceived signal %v, stopping monitoring...\n", sig)
-		case <-ctx.Done():
-			if duration > 0 {
-				fmt.Printf("\nMonitoring duration completed, stopping...\n")
-			}
+		case <-deadline:
+			fmt.Printf("\nMaximum run time of %s reached, stopping...\n", maxRuntime)
 		}
 
-		// Stop scheduler
-		if err := scheduler.Stop(); err != nil {
-			return fmt.Errorf("error stopping scheduler: %w", err)
+		if err := shutdownMonitoring(scheduler, driftSinks...); err != nil {
+			return err
 		}
 
 		fmt.Println("Monitoring stopped")
@@ -127,6 +188,35 @@ Examples:
 	},
 }
 
+// shutdownFlushTimeout bounds the final delivery of drifts to each drift sink
+// when monitoring stops
+const shutdownFlushTimeout = 30 * time.Second
+
+// shutdownMonitoring stops the scheduler, which waits for the checks in
+// progress and the alerts they send, then delivers the drifts they found to
+// the drift sinks, if any
+func shutdownMonitoring(scheduler monitor.Scheduler, driftSinks ...*sink.DriftSink) error {
+	if err := scheduler.Stop(); err != nil {
+		return fmt.Errorf("error stopping scheduler: %w", err)
+	}
+
+	for _, driftSink := range driftSinks {
+		if driftSink == nil {
+			continue
+		}
+
+		driftSink.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownFlushTimeout)
+		if _, err := driftSink.Flush(ctx); err != nil {
+			// Undelivered drifts remain queued for the next run
+			fmt.Fprintf(os.Stderr, "Warning: failed to deliver drifts to the drift sink: %v\n", err)
+		}
+		cancel()
+	}
+	return nil
+}
+
 // checkCmd represents the check command
 var checkCmd = &cobra.Command{
 	Use:   "check",
@@ -161,7 +251,7 @@ Examples:
 		}
 
 		// Connect to storage
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -169,10 +259,11 @@ Examples:
 
 		// Create HTTP client
 		client := httpClient.NewClient(httpClient.ClientConfig{
-			Timeout:    cfg.Global.Timeout,
-			RetryCount: cfg.Global.RetryCount,
-			RetryDelay: cfg.Global.RetryDelay,
-			UserAgent:  cfg.Global.UserAgent,
+			Timeout:       cfg.Global.Timeout,
+			RetryCount:    cfg.Global.RetryCount,
+			RetryDelay:    cfg.Global.RetryDelay,
+			MaxRetryDelay: cfg.Global.MaxRetryDelay,
+			UserAgent:     cfg.Global.UserAgent,
 		})
 
 		// Create scheduler
@@ -193,8 +284,16 @@ Examples:
 			defer cancel()
 		}
 
+		if cfg.Alerting.Enabled {
+			alertManager, err := alerting.NewAlertManager(cfg, db)
+			if err != nil {
+				return fmt.Errorf("failed to create alert manager: %w", err)
+			}
+			scheduler.SetAlertManager(alertManager)
+		}
+
 		// Perform one-time check
-		fmt.Printf("Checking %d endpoints...\n", len(cfg.Endpoints))
+		fmt.Printf("Checking %d endpoints...\n", len(cfg.ExpandedEndpoints()))
 		start := time.Now()
 
 		if err := scheduler.CheckOnce(ctx); err != nil {
@@ -204,6 +303,18 @@ Examples:
 		duration := time.Since(start)
 		fmt.Printf("Check completed in %s\n", duration)
 
+		// Drifts that cannot be delivered now stay queued for the next run
+		if cfg.DriftSink.Enabled {
+			if _, err := sink.NewDriftSink(db, &cfg.DriftSink, GetLogger()).Flush(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to deliver drifts to sink: %v\n", err)
+			}
+		}
+		if cfg.OTel.Enabled {
+			if _, err := sink.NewOTelExporter(db, &cfg.OTel, GetLogger()).Flush(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to export drifts to OpenTelemetry: %v\n", err)
+			}
+		}
+
 		// Display results based on output format
 		status := scheduler.GetStatus()
 		return displaySchedulerStatus(status, outputFormat)
@@ -240,7 +351,7 @@ Examples:
 		}
 
 		// Connect to storage
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -248,10 +359,11 @@ Examples:
 
 		// Create HTTP client (for status only, not used)
 		client := httpClient.NewClient(httpClient.ClientConfig{
-			Timeout:    cfg.Global.Timeout,
-			RetryCount: cfg.Global.RetryCount,
-			RetryDelay: cfg.Global.RetryDelay,
-			UserAgent:  cfg.Global.UserAgent,
+			Timeout:       cfg.Global.Timeout,
+			RetryCount:    cfg.Global.RetryCount,
+			RetryDelay:    cfg.Global.RetryDelay,
+			MaxRetryDelay: cfg.Global.MaxRetryDelay,
+			UserAgent:     cfg.Global.UserAgent,
 		})
 
 		// Create scheduler to get status
@@ -260,6 +372,12 @@ Examples:
 		// Get status
 		status := scheduler.GetStatus()
 
+		// The scheduler is usually running in another process, whose
+		// heartbeats are only visible through storage
+		if beatAt, err := db.GetLastHeartbeat(); err == nil && beatAt.After(status.LastHeartbeat) {
+			status.LastHeartbeat = beatAt
+		}
+
 		// Display status
 		if detailed {
 			return displayDetailedStatus(status, db, outputFormat)
@@ -275,7 +393,9 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 
 	// Monitor command flags
+	monitorCmd.Flags().Duration("max-runtime", 0, "stop gracefully after this long (0 for indefinite)")
 	monitorCmd.Flags().Duration("duration", 0, "monitoring duration (0 for indefinite)")
+	_ = monitorCmd.Flags().MarkDeprecated("duration", "use --max-runtime instead")
 	monitorCmd.Flags().StringSlice("endpoints", []string{}, "specific endpoints to monitor (comma-separated)")
 	monitorCmd.Flags().Bool("daemon", false, "run in daemon mode (background)")
 
@@ -303,12 +423,25 @@ func filterEndpoints(cfg *config.Config, endpointIDs []string) error {
 		idMap[id] = true
 	}
 
-	// Filter endpoints
+	// Filter endpoints; selecting a variant checks only that variant of its endpoint
 	var filteredEndpoints []config.EndpointConfig
 	for _, ep := range cfg.Endpoints {
 		if idMap[ep.ID] {
 			filteredEndpoints = append(filteredEndpoints, ep)
 			delete(idMap, ep.ID)
+			continue
+		}
+
+		var variants []config.EndpointVariant
+		for _, variant := range ep.Variants {
+			if variantID := config.VariantID(ep.ID, variant.Name); idMap[variantID] {
+				variants = append(variants, variant)
+				delete(idMap, variantID)
+			}
+		}
+		if len(variants) > 0 {
+			ep.Variants = variants
+			filteredEndpoints = append(filteredEndpoints, ep)
 		}
 	}
 
@@ -350,6 +483,9 @@ func displayStatusTable(status monitor.SchedulerStatus) error {
 	if !status.LastCheckAt.IsZero() {
 		fmt.Printf("  Last Check: %s\n", status.LastCheckAt.Format(time.RFC3339))
 	}
+	if !status.LastHeartbeat.IsZero() {
+		fmt.Printf("  Last Heartbeat: %s\n", status.LastHeartbeat.Format(time.RFC3339))
+	}
 
 	if len(status.EndpointStatuses) > 0 {
 		fmt.Printf("\nEndpoint Status:\n")
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/monitor"
+	"github.com/k0ns0l/driftwatch/internal/sink"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stoppedScheduler records whether it was stopped
+type stoppedScheduler struct {
+	monitor.Scheduler
+	stopped bool
+}
+
+func (s *stoppedScheduler) Stop() error {
+	s.stopped = true
+	return nil
+}
+
+func TestShutdownMonitoring(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	driftSink := sink.NewDriftSink(db, &config.DriftSinkConfig{
+		URL:           server.URL,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+	}, nil)
+
+	// Start the sink's cursor before the drift found by the last checks
+	_, err = driftSink.Flush(context.Background())
+	require.NoError(t, err)
+	driftSink.Start(context.Background())
+	require.NoError(t, db.SaveDrift(&storage.Drift{EndpointID: "users", DriftType: "field_removed", Severity: "high"}))
+
+	scheduler := &stoppedScheduler{}
+	require.NoError(t, shutdownMonitoring(scheduler, driftSink))
+
+	assert.True(t, scheduler.stopped)
+	assert.Equal(t, int32(1), requests.Load(), "drifts pending at shutdown are delivered")
+
+	t.Run("without drift sink", func(t *testing.T) {
+		scheduler := &stoppedScheduler{}
+		require.NoError(t, shutdownMonitoring(scheduler, nil))
+		assert.True(t, scheduler.stopped)
+	})
+}
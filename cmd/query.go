@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/jsonpath"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// queryCmd represents the query command
+var queryCmd = &cobra.Command{
+	Use:   "query <endpoint-id> <jsonpath>",
+	Short: "Extract a JSONPath value from stored responses over time",
+	Long: `Extract the value at a JSONPath from every stored response of an endpoint
+and print it as a time series, oldest first.
+
+This is useful for correlating a drift with deploys, for example by following a
+version field across monitoring runs. Runs without a JSON response body are
+skipped. Changed values are marked with '*' in table output.
+
+Examples:
+  driftwatch query my-api '$.data.version'               # Last 7 days
+  driftwatch query my-api '$.data.version' --period 24h  # Last 24 hours
+  driftwatch query my-api '$.items[0].id' --output csv   # Output as CSV`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		endpointID := args[0]
+		path, err := jsonpath.Parse(args[1])
+		if err != nil {
+			return err
+		}
+
+		period, err := cmd.Flags().GetString("period")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "period", err)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output", err)
+		}
+
+		duration, err := parsePeriod(period)
+		if err != nil {
+			return fmt.Errorf("invalid period: %w", err)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		if _, err := db.GetEndpoint(endpointID); err != nil {
+			return fmt.Errorf("failed to get endpoint %s: %w", endpointID, err)
+		}
+
+		runs, err := db.GetMonitoringHistory(endpointID, duration)
+		if err != nil {
+			return fmt.Errorf("failed to get monitoring history: %w", err)
+		}
+
+		points := queryRuns(runs, path)
+
+		switch outputFormat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(points)
+		case "csv":
+			return outputQueryCSV(points, os.Stdout)
+		case "table":
+			outputQueryTable(points, path, formatPeriod(duration))
+			return nil
+		default:
+			return fmt.Errorf("unsupported output format: %s (supported: table, json, csv)", outputFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().StringP("period", "p", "7d", "time period to query (24h, 7d, 30d)")
+	queryCmd.Flags().StringP("output", "o", "table", "output format (table, json, csv)")
+}
+
+// QueryPoint is the value of a JSONPath in a single stored response
+type QueryPoint struct {
+	Timestamp      time.Time   `json:"timestamp"`
+	ResponseStatus int         `json:"response_status"`
+	Found          bool        `json:"found"`
+	Value          interface{} `json:"value"`
+	Changed        bool        `json:"changed"` // Value differs from the previous point
+}
+
+// queryRuns evaluates path against each run's response body and returns the
+// resulting points oldest first. Runs without a JSON body are skipped.
+func queryRuns(runs []*storage.MonitoringRun, path jsonpath.Path) []QueryPoint {
+	points := make([]QueryPoint, 0, len(runs))
+	previous := ""
+
+	// Runs are returned newest first
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		if run.ResponseBody == "" {
+			continue
+		}
+
+		var body interface{}
+		if err := json.Unmarshal([]byte(run.ResponseBody), &body); err != nil {
+			continue
+		}
+
+		point := QueryPoint{Timestamp: run.Timestamp, ResponseStatus: run.ResponseStatus}
+		point.Value, point.Found = path.Lookup(body)
+
+		current := formatQueryValue(point)
+		point.Changed = len(points) > 0 && current != previous
+		previous = current
+
+		points = append(points, point)
+	}
+
+	return points
+}
+
+// formatQueryValue renders a point's value for display; strings are shown unquoted
+func formatQueryValue(point QueryPoint) string {
+	if !point.Found {
+		return "<missing>"
+	}
+	if s, ok := point.Value.(string); ok {
+		return s
+	}
+
+	data, err := json.Marshal(point.Value)
+	if err != nil {
+		return fmt.Sprintf("%v", point.Value)
+	}
+	return string(data)
+}
+
+// outputQueryTable prints the query results as a table
+func outputQueryTable(points []QueryPoint, path jsonpath.Path, period string) {
+	fmt.Printf("%s over the last %s\n\n", path.String(), period)
+
+	if len(points) == 0 {
+		fmt.Println("No stored responses found.")
+		return
+	}
+
+	fmt.Printf("%-20s %-7s %s\n", "TIMESTAMP", "STATUS", "VALUE")
+	for _, point := range points {
+		marker := " "
+		if point.Changed {
+			marker = "*"
+		}
+		fmt.Printf("%-20s %-7d %s %s\n", point.Timestamp.Format("2006-01-02 15:04:05"),
+			point.ResponseStatus, marker, formatQueryValue(point))
+	}
+}
+
+// outputQueryCSV writes the query results in CSV format
+func outputQueryCSV(points []QueryPoint, output io.Writer) error {
+	writer := csv.NewWriter(output)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Timestamp", "ResponseStatus", "Found", "Value", "Changed"}); err != nil {
+		return err
+	}
+
+	for _, point := range points {
+		record := []string{
+			point.Timestamp.Format(time.RFC3339),
+			strconv.Itoa(point.ResponseStatus),
+			strconv.FormatBool(point.Found),
+			formatQueryValue(point),
+			strconv.FormatBool(point.Changed),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
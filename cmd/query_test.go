@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/jsonpath"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRuns(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Newest first, as returned by storage
+	runs := []*storage.MonitoringRun{
+		{Timestamp: base.Add(4 * time.Hour), ResponseStatus: 200, ResponseBody: `{"data": {}}`},
+		{Timestamp: base.Add(3 * time.Hour), ResponseStatus: 200, ResponseBody: `{"data": {"version": "1.3.0"}}`},
+		{Timestamp: base.Add(2 * time.Hour), ResponseStatus: 502, ResponseBody: `<html>Bad Gateway</html>`},
+		{Timestamp: base.Add(time.Hour), ResponseStatus: 200, ResponseBody: `{"data": {"version": "1.2.0"}}`},
+		{Timestamp: base, ResponseStatus: 200, ResponseBody: `{"data": {"version": "1.2.0"}}`},
+	}
+
+	path, err := jsonpath.Parse("$.data.version")
+	require.NoError(t, err)
+
+	points := queryRuns(runs, path)
+	require.Len(t, points, 4)
+
+	assert.Equal(t, base, points[0].Timestamp)
+	assert.Equal(t, "1.2.0", points[0].Value)
+	assert.False(t, points[0].Changed)
+	assert.False(t, points[1].Changed)
+
+	assert.Equal(t, "1.3.0", points[2].Value)
+	assert.True(t, points[2].Changed)
+
+	assert.False(t, points[3].Found)
+	assert.True(t, points[3].Changed)
+
+	var buf bytes.Buffer
+	require.NoError(t, outputQueryCSV(points, &buf))
+	assert.Equal(t, "Timestamp,ResponseStatus,Found,Value,Changed\n"+
+		"2024-03-10T12:00:00Z,200,true,1.2.0,false\n"+
+		"2024-03-10T13:00:00Z,200,true,1.2.0,false\n"+
+		"2024-03-10T15:00:00Z,200,true,1.3.0,true\n"+
+		"2024-03-10T16:00:00Z,200,false,<missing>,true\n", buf.String())
+}
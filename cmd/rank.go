@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Weights of the components of the health score, which add up to 1
+const (
+	healthWeightSuccess   = 0.5
+	healthWeightDrift     = 0.3
+	healthWeightStability = 0.2
+)
+
+// Penalties, out of 100, of each recent drift by severity in the drift
+// component of the health score
+const (
+	driftPenaltyCritical = 25
+	driftPenaltyHigh     = 10
+	driftPenaltyMedium   = 3
+	driftPenaltyLow      = 1
+)
+
+// RankReport lists endpoints from the least to the most healthy
+type RankReport struct {
+	GeneratedAt time.Time        `json:"generated_at" yaml:"generated_at"`
+	Endpoints   []EndpointStatus `json:"endpoints" yaml:"endpoints"`
+	Unranked    []string         `json:"unranked,omitempty" yaml:"unranked,omitempty"` // Endpoints without recent runs
+}
+
+// rankCmd represents the rank command
+var rankCmd = &cobra.Command{
+	Use:   "rank",
+	Short: "List the least healthy endpoints",
+	Long: `List endpoints by their health score, worst first. The score ranges from 0
+to 100 and combines:
+
+  - the success rate of the runs of the last 24 hours (50%)
+  - the unacknowledged drifts of the last 7 days, weighted by severity (30%)
+  - the stability of successful response times (20%)
+
+Endpoints without runs in the last 24 hours have no meaningful score and are
+listed separately.
+
+Examples:
+  driftwatch rank                # The 10 least healthy endpoints
+  driftwatch rank --limit 3
+  driftwatch rank --output json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "limit", err)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output", err)
+		}
+
+		if limit < 0 {
+			return fmt.Errorf("limit must not be negative")
+		}
+		if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" {
+			return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml)", outputFormat)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		expanded := cfg.ExpandedEndpoints()
+		endpoints := make([]string, 0, len(expanded))
+		for _, ep := range expanded {
+			endpoints = append(endpoints, ep.ID)
+		}
+
+		report := rankEndpoints(generateStatusReport(db, endpoints, false), limit)
+
+		switch outputFormat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		case "yaml":
+			encoder := yaml.NewEncoder(os.Stdout)
+			encoder.SetIndent(2)
+			defer encoder.Close()
+			return encoder.Encode(report)
+		default:
+			outputRankTable(report)
+			return nil
+		}
+	},
+}
+
+// healthScore combines the success rate, recent drifts and response time
+// stability of an endpoint into a score from 0 (worst) to 100 (best)
+func healthScore(status EndpointStatus) float64 {
+	drifts := status.DriftsBySeverity
+	penalty := drifts.Critical*driftPenaltyCritical + drifts.High*driftPenaltyHigh +
+		drifts.Medium*driftPenaltyMedium + drifts.Low*driftPenaltyLow
+	driftScore := math.Max(0, float64(100-penalty))
+
+	stabilityScore := 100 * (1 - math.Min(status.ResponseTimeVariation, 1))
+
+	score := healthWeightSuccess*status.SuccessRate +
+		healthWeightDrift*driftScore +
+		healthWeightStability*stabilityScore
+
+	return math.Round(score*10) / 10
+}
+
+// responseTimeVariation returns the coefficient of variation, the standard
+// deviation divided by the mean, of the response times of successful runs.
+// It is 0 with fewer than two successful runs.
+func responseTimeVariation(runs []*storage.MonitoringRun) float64 {
+	var times []float64
+	for _, run := range runs {
+		if run.Succeeded() {
+			times = append(times, float64(run.ResponseTimeMs))
+		}
+	}
+	if len(times) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, t := range times {
+		sum += t
+	}
+	mean := sum / float64(len(times))
+	if mean == 0 {
+		return 0
+	}
+
+	var squares float64
+	for _, t := range times {
+		squares += (t - mean) * (t - mean)
+	}
+	stddev := math.Sqrt(squares / float64(len(times)))
+
+	return math.Round(stddev/mean*1000) / 1000
+}
+
+// rankEndpoints orders the endpoints of a status report by health score, worst
+// first, and keeps the first limit of them. A limit of 0 keeps all endpoints.
+func rankEndpoints(status *StatusReport, limit int) *RankReport {
+	report := &RankReport{
+		GeneratedAt: status.GeneratedAt,
+		Endpoints:   make([]EndpointStatus, 0, len(status.Endpoints)),
+	}
+
+	for _, ep := range status.Endpoints {
+		if ep.Status == "unknown" {
+			report.Unranked = append(report.Unranked, ep.ID)
+			continue
+		}
+		report.Endpoints = append(report.Endpoints, ep)
+	}
+
+	sort.SliceStable(report.Endpoints, func(i, j int) bool {
+		if report.Endpoints[i].HealthScore != report.Endpoints[j].HealthScore {
+			return report.Endpoints[i].HealthScore < report.Endpoints[j].HealthScore
+		}
+		return report.Endpoints[i].ID < report.Endpoints[j].ID
+	})
+
+	if limit > 0 && len(report.Endpoints) > limit {
+		report.Endpoints = report.Endpoints[:limit]
+	}
+
+	return report
+}
+
+// outputRankTable outputs a rank report in table format
+func outputRankTable(report *RankReport) {
+	fmt.Printf("DriftWatch Endpoint Ranking - %s\n",
+		report.GeneratedAt.Format("2006-01-02 15:04:05"))
+	fmt.Println(strings.Repeat("=", 80))
+
+	if len(report.Endpoints) == 0 {
+		fmt.Printf("\nNo endpoints with recent runs to rank.\n")
+	} else {
+		fmt.Printf("\n%-5s %-20s %-7s %-9s %-14s %-9s\n",
+			"RANK", "ID", "SCORE", "SUCCESS", "DRIFTS C/H/M/L", "VARIATION")
+		fmt.Println(strings.Repeat("-", 70))
+
+		for i, ep := range report.Endpoints {
+			displayID := ep.ID
+			if len(displayID) > 17 {
+				displayID = displayID[:14] + "..."
+			}
+
+			drifts := fmt.Sprintf("%d/%d/%d/%d",
+				ep.DriftsBySeverity.Critical,
+				ep.DriftsBySeverity.High,
+				ep.DriftsBySeverity.Medium,
+				ep.DriftsBySeverity.Low)
+
+			fmt.Printf("%-5d %-20s %-7.1f %-9s %-14s %-9.2f\n",
+				i+1,
+				displayID,
+				ep.HealthScore,
+				fmt.Sprintf("%.1f%%", ep.SuccessRate),
+				drifts,
+				ep.ResponseTimeVariation)
+		}
+	}
+
+	if len(report.Unranked) > 0 {
+		fmt.Printf("\nNot ranked (no runs in the last 24h): %s\n", strings.Join(report.Unranked, ", "))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(rankCmd)
+
+	rankCmd.Flags().Int("limit", 10, "maximum number of endpoints to list (0 for all)")
+	rankCmd.Flags().StringP("output", "o", "table", "output format (table, json, yaml)")
+}
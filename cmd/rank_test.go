@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   EndpointStatus
+		expected float64
+	}{
+		{
+			name:     "perfect",
+			status:   EndpointStatus{SuccessRate: 100},
+			expected: 100,
+		},
+		{
+			name:     "half the runs failed",
+			status:   EndpointStatus{SuccessRate: 50},
+			expected: 75,
+		},
+		{
+			name: "drift penalty",
+			status: EndpointStatus{
+				SuccessRate:      100,
+				DriftsBySeverity: DriftSeverityCounts{Critical: 1, High: 2, Medium: 1, Low: 2},
+			},
+			expected: 85, // 100 - 25 - 20 - 3 - 2 = 50 drift score
+		},
+		{
+			name: "drift penalty is capped",
+			status: EndpointStatus{
+				SuccessRate:      100,
+				DriftsBySeverity: DriftSeverityCounts{Critical: 10},
+			},
+			expected: 70,
+		},
+		{
+			name:     "unstable response times",
+			status:   EndpointStatus{SuccessRate: 100, ResponseTimeVariation: 0.5},
+			expected: 90,
+		},
+		{
+			name:     "variation is capped",
+			status:   EndpointStatus{SuccessRate: 100, ResponseTimeVariation: 3},
+			expected: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, healthScore(tt.status), 0.001)
+		})
+	}
+}
+
+func TestResponseTimeVariation(t *testing.T) {
+	run := func(status int, ms int64) *storage.MonitoringRun {
+		return &storage.MonitoringRun{ResponseStatus: status, ResponseTimeMs: ms}
+	}
+
+	assert.Equal(t, 0.0, responseTimeVariation(nil))
+	assert.Equal(t, 0.0, responseTimeVariation([]*storage.MonitoringRun{run(200, 100)}))
+	assert.Equal(t, 0.0, responseTimeVariation([]*storage.MonitoringRun{run(200, 100), run(200, 100)}))
+
+	// Mean 150, standard deviation 50; the failed run is ignored
+	runs := []*storage.MonitoringRun{run(200, 100), run(200, 200), run(500, 5000)}
+	assert.InDelta(t, 0.333, responseTimeVariation(runs), 0.001)
+}
+
+func TestRankEndpoints(t *testing.T) {
+	status := &StatusReport{
+		GeneratedAt: time.Now(),
+		Endpoints: []EndpointStatus{
+			{ID: "healthy", Status: "healthy", HealthScore: 98},
+			{ID: "never-checked", Status: "unknown", HealthScore: 30},
+			{ID: "flaky", Status: "unhealthy", HealthScore: 40},
+			{ID: "drifting-b", Status: "healthy", HealthScore: 70},
+			{ID: "drifting-a", Status: "healthy", HealthScore: 70},
+		},
+	}
+
+	report := rankEndpoints(status, 0)
+	var ids []string
+	for _, ep := range report.Endpoints {
+		ids = append(ids, ep.ID)
+	}
+	assert.Equal(t, []string{"flaky", "drifting-a", "drifting-b", "healthy"}, ids)
+	assert.Equal(t, []string{"never-checked"}, report.Unranked)
+
+	report = rankEndpoints(status, 2)
+	assert.Len(t, report.Endpoints, 2)
+	assert.Equal(t, "flaky", report.Endpoints[0].ID)
+}
+
+func TestGenerateStatusReportHealthScore(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.NoError(t, db.SaveEndpoint(&storage.Endpoint{ID: "api", URL: "https://api.example.com", Method: "GET"}))
+
+	now := time.Now()
+	for i, status := range []int{200, 200, 500, 200} {
+		assert.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+			EndpointID:     "api",
+			Timestamp:      now.Add(-time.Duration(i+1) * time.Minute),
+			ResponseStatus: status,
+			ResponseTimeMs: 100,
+		}))
+	}
+	assert.NoError(t, db.SaveDrift(&storage.Drift{
+		EndpointID: "api",
+		DriftType:  "field_removed",
+		Severity:   "high",
+		DetectedAt: now.Add(-time.Hour),
+	}))
+
+	report := generateStatusReport(db, []string{"api"}, false)
+
+	assert.Len(t, report.Endpoints, 1)
+	// 0.5*75 + 0.3*90 + 0.2*100
+	assert.InDelta(t, 84.5, report.Endpoints[0].HealthScore, 0.001)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/drift"
+)
+
+// ANSI escape sequences used by the diff renderer
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	ansiDim    = "\033[2m"
+)
+
+// noColor is set by the global --no-color flag
+var noColor bool
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled reports whether colors may be written to f. Every colorized
+// output checks it: colors are only written to terminals, so that redirected
+// output stays plain text, and never with --no-color or when the NO_COLOR
+// environment variable is set to a non-empty value (see https://no-color.org).
+func colorEnabled(f *os.File) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// diffPainter applies ANSI colors when enabled
+type diffPainter struct {
+	color bool
+}
+
+func (p diffPainter) paint(code, text string) string {
+	if !p.color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// renderDiff writes changes as a unified diff grouped under their parent path.
+// Added fields are shown in green, removed fields in red and modified values as
+// a removed and an added line. Changes without values, such as header or
+// performance changes, are shown in yellow with their description.
+func renderDiff(w io.Writer, changes []CIChange, color bool) {
+	p := diffPainter{color: color}
+
+	groups := make(map[string][]CIChange)
+	var parents []string
+	for _, change := range changes {
+		parent, _ := splitChangePath(change.Path)
+		if _, exists := groups[parent]; !exists {
+			parents = append(parents, parent)
+		}
+		groups[parent] = append(groups[parent], change)
+	}
+	sort.Strings(parents)
+
+	for _, parent := range parents {
+		fmt.Fprintln(w, p.paint(ansiCyan, fmt.Sprintf("@@ %s @@", parent)))
+
+		group := groups[parent]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+
+		for _, change := range group {
+			_, key := splitChangePath(change.Path)
+			annotation := p.paint(ansiDim, "  # "+changeAnnotation(change))
+
+			switch {
+			case change.Type == "field_added" && change.NewValue != "":
+				fmt.Fprintln(w, p.paint(ansiGreen, fmt.Sprintf("+ %s: %s", key, change.NewValue))+annotation)
+			case change.Type == "field_removed" && change.OldValue != "":
+				fmt.Fprintln(w, p.paint(ansiRed, fmt.Sprintf("- %s: %s", key, change.OldValue))+annotation)
+			case change.OldValue != "" || change.NewValue != "":
+				fmt.Fprintln(w, p.paint(ansiRed, fmt.Sprintf("- %s: %s", key, change.OldValue)))
+				fmt.Fprintln(w, p.paint(ansiGreen, fmt.Sprintf("+ %s: %s", key, change.NewValue))+annotation)
+			default:
+				fmt.Fprintln(w, p.paint(ansiYellow, fmt.Sprintf("~ %s: %s", key, change.Description))+annotation)
+			}
+
+			if change.Explanation != nil {
+				fmt.Fprintln(w, p.paint(ansiDim, "  # why: "+formatExplanation(change.Explanation)))
+			}
+		}
+	}
+}
+
+// changeAnnotation summarizes a change's type, severity and breaking status
+func changeAnnotation(change CIChange) string {
+	annotation := fmt.Sprintf("%s, %s", change.Type, change.Severity)
+	if change.Breaking {
+		annotation += ", breaking"
+	}
+	return annotation
+}
+
+// formatExplanation renders a change explanation on one line
+func formatExplanation(explanation *drift.ChangeExplanation) string {
+	return fmt.Sprintf("%s (rules: %s; confidence: %.0f%%)",
+		explanation.Reasoning, strings.Join(explanation.Rules, ", "), explanation.Confidence*100)
+}
+
+// splitChangePath splits a change path such as "$.data.items[0].id" into its
+// parent path and final segment
+func splitChangePath(path string) (string, string) {
+	index := strings.LastIndexAny(path, ".[")
+	if index <= 0 {
+		return "$", path
+	}
+
+	parent, key := path[:index], path[index:]
+	return parent, strings.TrimPrefix(key, ".")
+}
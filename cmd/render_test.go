@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDiff(t *testing.T) {
+	changes := []CIChange{
+		{Type: "value_change", Path: "$.data.name", Severity: "medium", OldValue: "Ada", NewValue: "Grace"},
+		{Type: "field_removed", Path: "$.data.email", Severity: "high", OldValue: "ada@example.com", Breaking: true},
+		{Type: "field_added", Path: "$.data.items[0]", Severity: "low", NewValue: "1"},
+		{Type: "header_change", Path: "$.headers.X-Version", Severity: "low", Description: "Header 'X-Version' was added"},
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		var buf bytes.Buffer
+		renderDiff(&buf, changes, false)
+
+		assert.Equal(t, "@@ $.data @@\n"+
+			"- email: ada@example.com  # field_removed, high, breaking\n"+
+			"- name: Ada\n"+
+			"+ name: Grace  # value_change, medium\n"+
+			"@@ $.data.items @@\n"+
+			"+ [0]: 1  # field_added, low\n"+
+			"@@ $.headers @@\n"+
+			"~ X-Version: Header 'X-Version' was added  # header_change, low\n", buf.String())
+	})
+
+	t.Run("colored", func(t *testing.T) {
+		var buf bytes.Buffer
+		renderDiff(&buf, changes[:1], true)
+
+		assert.Contains(t, buf.String(), ansiRed+"- name: Ada"+ansiReset)
+		assert.Contains(t, buf.String(), ansiGreen+"+ name: Grace"+ansiReset)
+	})
+
+	t.Run("explained", func(t *testing.T) {
+		explained := changes[1]
+		explained.Explanation = &drift.ChangeExplanation{
+			Reasoning:  "field removal is potentially breaking",
+			Rules:      []string{"field_removal"},
+			Confidence: 0.8,
+		}
+
+		var buf bytes.Buffer
+		renderDiff(&buf, []CIChange{explained}, false)
+
+		assert.Equal(t, "@@ $.data @@\n"+
+			"- email: ada@example.com  # field_removed, high, breaking\n"+
+			"  # why: field removal is potentially breaking (rules: field_removal; confidence: 80%)\n", buf.String())
+	})
+}
+
+func TestSplitChangePath(t *testing.T) {
+	tests := []struct {
+		path, parent, key string
+	}{
+		{"$", "$", "$"},
+		{"$.name", "$", "name"},
+		{"$.data.items[2].id", "$.data.items[2]", "id"},
+		{"$.data.items[2]", "$.data.items", "[2]"},
+	}
+
+	for _, tt := range tests {
+		parent, key := splitChangePath(tt.path)
+		assert.Equal(t, tt.parent, parent, tt.path)
+		assert.Equal(t, tt.key, key, tt.path)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, isTerminal(f))
+}
+
+func TestColorEnabled(t *testing.T) {
+	// /dev/null is a character device, so it passes for a terminal
+	f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("cannot open %s: %v", os.DevNull, err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	assert.True(t, colorEnabled(f))
+
+	t.Run("no-color flag", func(t *testing.T) {
+		noColor = true
+		defer func() { noColor = false }()
+
+		assert.False(t, colorEnabled(f))
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+
+		assert.False(t, colorEnabled(f))
+	})
+}
@@ -40,7 +40,7 @@ Examples:
 		logger := GetLogger()
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/alerting"
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/errors"
 	"github.com/k0ns0l/driftwatch/internal/security"
 	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/spf13/cobra"
@@ -28,9 +33,12 @@ Examples:
   driftwatch report                    # Generate report for last 24 hours
   driftwatch report --period 7d       # Generate report for last 7 days
   driftwatch report --period 30d      # Generate report for last 30 days
+  driftwatch report --since 2024-01-01 --until 2024-02-01  # Report on January 2024
   driftwatch report --endpoint my-api # Report for specific endpoint
   driftwatch report --severity high   # Show only high severity drifts
-  driftwatch report --output json     # Output in JSON format`,
+  driftwatch report --output json     # Output in JSON format
+  driftwatch report --explain         # Explain how each field change was classified
+  driftwatch report --group-by path   # Group drifts by top-level field`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := GetConfig()
 		if cfg == nil {
@@ -62,15 +70,26 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("failed to get %s flag: %w", "unacknowledged", err)
 		}
+		explain, err := cmd.Flags().GetBool("explain")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "explain", err)
+		}
+		groupBy, err := cmd.Flags().GetString("group-by")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "group-by", err)
+		}
+		if groupBy != "" && driftGroupKeys[groupBy] == nil {
+			return fmt.Errorf("unsupported grouping: %s (supported: endpoint, type, severity, path)", groupBy)
+		}
 
-		// Parse time period
-		duration, err := parsePeriod(period)
+		// Resolve the time window
+		window, err := timeWindowFromFlags(cmd, period)
 		if err != nil {
-			return fmt.Errorf("invalid period: %w", err)
+			return err
 		}
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -80,8 +99,8 @@ Examples:
 		filters := storage.DriftFilters{
 			EndpointID: endpointID,
 			Severity:   severity,
-			StartTime:  time.Now().Add(-duration),
-			EndTime:    time.Now(),
+			StartTime:  window.Start,
+			EndTime:    window.End,
 		}
 
 		// Handle acknowledged filter
@@ -99,8 +118,21 @@ Examples:
 			return fmt.Errorf("failed to get drifts: %w", err)
 		}
 
+		escalation, err := alerting.NewEscalationPolicy(cfg.Alerting.Escalation)
+		if err != nil {
+			return fmt.Errorf("invalid escalation policy: %w", err)
+		}
+
 		// Generate report
-		report := generateDriftReport(drifts, duration)
+		report := generateDriftReport(drifts, window, escalation)
+		if explain {
+			report.Explanations = explainDrifts(drifts, cfg.ExpandedEndpoints(), cfg.Global.Sensitivity)
+		}
+		if groupBy != "" {
+			report.GroupBy = groupBy
+			report.Groups = groupDrifts(drifts, groupBy)
+			report.Drifts = []*storage.Drift{}
+		}
 
 		// Output report based on format
 		switch outputFormat {
@@ -127,11 +159,18 @@ response times, success rates, and recent drift activity.
 This command provides a quick overview of your API monitoring setup and
 helps identify endpoints that may need attention.
 
+With --history, the period is split into 24 intervals and each endpoint gets an
+availability strip with one bar per interval, higher the more of its checks
+succeeded, and its availability over the whole period. Intervals without checks
+are shown as dots.
+
 Examples:
   driftwatch health                    # Show health for all endpoints
   driftwatch health --endpoint my-api # Show health for specific endpoint
   driftwatch health --unhealthy-only  # Show only unhealthy endpoints
-  driftwatch health --output json     # Output in JSON format`,
+  driftwatch health --output json     # Output in JSON format
+  driftwatch health --history         # Show availability over the last 24 hours
+  driftwatch health --history --period 7d`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := GetConfig()
 		if cfg == nil {
@@ -151,9 +190,28 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("failed to get %s flag: %w", "unhealthy-only", err)
 		}
+		history, err := cmd.Flags().GetBool("history")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "history", err)
+		}
+		periodStr, err := cmd.Flags().GetString("period")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "period", err)
+		}
+
+		var historyPeriod time.Duration
+		if history {
+			historyPeriod, err = parsePeriod(periodStr)
+			if err != nil {
+				return fmt.Errorf("invalid period format: %w", err)
+			}
+			if historyPeriod < availabilityBuckets*time.Minute {
+				return fmt.Errorf("history period must be at least %s", availabilityBuckets*time.Minute)
+			}
+		}
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
@@ -165,13 +223,18 @@ Examples:
 			endpoints = []string{endpointID}
 		} else {
 			// Get all endpoint IDs from config
-			for _, ep := range cfg.Endpoints {
+			for _, ep := range cfg.ExpandedEndpoints() {
 				endpoints = append(endpoints, ep.ID)
 			}
 		}
 
 		// Generate status report
 		statusReport := generateStatusReport(db, endpoints, unhealthyOnly)
+		if history {
+			if err := addAvailabilityHistory(db, statusReport, historyPeriod, statusReport.GeneratedAt); err != nil {
+				return err
+			}
+		}
 
 		// Output status based on format
 		switch outputFormat {
@@ -181,6 +244,7 @@ Examples:
 			return outputStatusYAML(statusReport)
 		case "table":
 			outputStatusTable(statusReport)
+			outputAvailabilityTable(statusReport)
 			return nil
 		default:
 			return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml)", outputFormat)
@@ -202,9 +266,17 @@ Examples:
   driftwatch export --format csv      # Export to CSV format
   driftwatch export --format json     # Export to JSON format
   driftwatch export --period 30d      # Export last 30 days of data
+  driftwatch export --since 2024-03-01T09:00:00Z --until 2024-03-01T12:00:00Z  # Export an incident window
   driftwatch export --endpoint my-api # Export data for specific endpoint
   driftwatch export --type drifts     # Export only drift data
-  driftwatch export --type runs       # Export only monitoring runs`,
+  driftwatch export --type runs       # Export only monitoring runs
+  driftwatch export -o drifts.json --sign  # Export with a signed manifest
+  driftwatch export --output-dir ./backup  # Export one file per endpoint and data type
+
+With --output-dir, the drifts and monitoring runs of each endpoint are written to
+<endpoint-id>.drifts.<format> and <endpoint-id>.runs.<format> in the directory,
+along with an index.json that lists the files and their record counts. With
+--sign, each of these files gets its own signed manifest.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg := GetConfig()
 		if cfg == nil {
@@ -232,31 +304,86 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("failed to get %s flag: %w", "output", err)
 		}
+		outputDir, err := cmd.Flags().GetString("output-dir")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output-dir", err)
+		}
+		sign, err := cmd.Flags().GetBool("sign")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "sign", err)
+		}
 
-		// Parse time period
-		duration, err := parsePeriod(period)
+		if output != "" && outputDir != "" {
+			return fmt.Errorf("--output and --output-dir cannot be used together")
+		}
+
+		// Resolve the time window
+		window, err := timeWindowFromFlags(cmd, period)
 		if err != nil {
-			return fmt.Errorf("invalid period: %w", err)
+			return err
 		}
 
 		// Connect to database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
 		defer db.Close()
 
+		if sign && output == "" && outputDir == "" {
+			return fmt.Errorf("--sign requires --output or --output-dir to be set")
+		}
+
+		var signer security.Signer
+		if sign {
+			signer, err = loadExportSigner(&cfg.Reporting.Signing, true)
+			if err != nil {
+				return fmt.Errorf("failed to load signing key: %w", err)
+			}
+		}
+
+		if outputDir != "" {
+			index, err := exportToDirectory(db, format, dataType, endpointID, window, outputDir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d endpoints to %s\n", len(index.Endpoints), outputDir)
+
+			if sign {
+				return signDirectoryExport(outputDir, index, signer)
+			}
+			return nil
+		}
+
 		// Export data based on type
+		var counts map[string]int
 		switch dataType {
 		case "drifts":
-			return exportDrifts(db, format, endpointID, duration, output)
+			counts, err = exportDrifts(db, format, endpointID, window, output)
 		case "runs":
-			return exportMonitoringRuns(db, format, endpointID, duration, output)
+			counts, err = exportMonitoringRuns(db, format, endpointID, window, output)
 		case "all":
-			return exportAllData(db, format, endpointID, duration, output)
+			counts, err = exportAllData(db, format, endpointID, window, output)
 		default:
 			return fmt.Errorf("unsupported data type: %s (supported: drifts, runs, all)", dataType)
 		}
+		if err != nil {
+			return err
+		}
+
+		if sign {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current working directory: %w", err)
+			}
+			if _, err := security.SignExport(output, format, counts, signer, cwd); err != nil {
+				return fmt.Errorf("failed to sign export: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Signed export: %s, %s\n",
+				security.ManifestPath(output), security.SignaturePath(output))
+		}
+
+		return nil
 	},
 }
 
@@ -267,23 +394,33 @@ func init() {
 
 	// Report command flags
 	reportCmd.Flags().StringP("period", "p", "24h", "time period for report (24h, 7d, 30d)")
+	reportCmd.Flags().String("since", "", "start of the report, as an RFC3339 time or a date (overrides --period)")
+	reportCmd.Flags().String("until", "", "end of the report, as an RFC3339 time or a date (default: now)")
 	reportCmd.Flags().StringP("endpoint", "e", "", "filter by specific endpoint ID")
 	reportCmd.Flags().StringP("severity", "s", "", "filter by severity (low, medium, high, critical)")
 	reportCmd.Flags().StringP("output", "o", "table", "output format (table, json, yaml)")
 	reportCmd.Flags().Bool("acknowledged", false, "show only acknowledged drifts")
 	reportCmd.Flags().Bool("unacknowledged", false, "show only unacknowledged drifts")
+	reportCmd.Flags().Bool("explain", false, "explain how each field change was classified")
+	reportCmd.Flags().String("group-by", "", "group drifts by endpoint, type, severity or path (top-level field)")
 
 	// Health command flags
 	healthCmd.Flags().StringP("endpoint", "e", "", "show health for specific endpoint ID")
 	healthCmd.Flags().StringP("output", "o", "table", "output format (table, json, yaml)")
 	healthCmd.Flags().Bool("unhealthy-only", false, "show only unhealthy endpoints")
+	healthCmd.Flags().Bool("history", false, "show each endpoint's availability over time")
+	healthCmd.Flags().StringP("period", "p", "24h", "time period covered by --history (24h, 7d, 30d)")
 
 	// Export command flags
 	exportCmd.Flags().StringP("format", "f", "json", "export format (json, csv, yaml)")
 	exportCmd.Flags().StringP("period", "p", "30d", "time period to export (24h, 7d, 30d)")
+	exportCmd.Flags().String("since", "", "start of the export, as an RFC3339 time or a date (overrides --period)")
+	exportCmd.Flags().String("until", "", "end of the export, as an RFC3339 time or a date (default: now)")
 	exportCmd.Flags().StringP("endpoint", "e", "", "filter by specific endpoint ID")
 	exportCmd.Flags().StringP("type", "t", "all", "data type to export (drifts, runs, all)")
 	exportCmd.Flags().StringP("output", "o", "", "output file (default: stdout)")
+	exportCmd.Flags().String("output-dir", "", "write one file per endpoint and data type to this directory, with an index")
+	exportCmd.Flags().Bool("sign", false, "write a signed manifest next to the output file")
 }
 
 // Data structures for reporting
@@ -294,8 +431,44 @@ type DriftReport struct {
 	StartTime time.Time        `json:"start_time" yaml:"start_time"`
 	EndTime   time.Time        `json:"end_time" yaml:"end_time"`
 	Summary   DriftSummary     `json:"summary" yaml:"summary"`
-	Drifts    []*storage.Drift `json:"drifts" yaml:"drifts"`
+	Drifts    []*storage.Drift `json:"drifts" yaml:"drifts"` // Empty when grouped
 	Trends    DriftTrends      `json:"trends" yaml:"trends"`
+
+	// Drifts organized into groups, with --group-by, in place of Drifts
+	GroupBy string       `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	Groups  []DriftGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// Unacknowledged drifts whose severity was raised by the escalation policy
+	Escalations []DriftEscalation `json:"escalations,omitempty" yaml:"escalations,omitempty"`
+
+	// Classification reasoning for field drifts, included with --explain
+	Explanations []DriftExplanation `json:"explanations,omitempty" yaml:"explanations,omitempty"`
+}
+
+// DriftGroup holds the drifts sharing an endpoint, type, severity or field path prefix
+type DriftGroup struct {
+	Key    string           `json:"key" yaml:"key"`
+	Count  int              `json:"count" yaml:"count"`
+	Severe int              `json:"severe" yaml:"severe"` // high + critical
+	Drifts []*storage.Drift `json:"drifts" yaml:"drifts"`
+}
+
+// DriftExplanation tells why a stored field drift is classified as it is
+type DriftExplanation struct {
+	DriftID   int64  `json:"drift_id" yaml:"drift_id"`
+	FieldPath string `json:"field_path" yaml:"field_path"`
+	Breaking  bool   `json:"breaking" yaml:"breaking"`
+
+	drift.ChangeExplanation `yaml:",inline"`
+}
+
+// DriftEscalation records the escalated severity of a stale drift
+type DriftEscalation struct {
+	DriftID           int64     `json:"drift_id" yaml:"drift_id"`
+	EndpointID        string    `json:"endpoint_id" yaml:"endpoint_id"`
+	DetectedAt        time.Time `json:"detected_at" yaml:"detected_at"`
+	Severity          string    `json:"severity" yaml:"severity"`
+	EscalatedSeverity string    `json:"escalated_severity" yaml:"escalated_severity"`
 }
 
 // DriftSummary provides high-level statistics about drifts
@@ -305,6 +478,7 @@ type DriftSummary struct {
 	ByEndpoint       map[string]int `json:"by_endpoint" yaml:"by_endpoint"`
 	ByType           map[string]int `json:"by_type" yaml:"by_type"`
 	AcknowledgedRate float64        `json:"acknowledged_rate" yaml:"acknowledged_rate"`
+	Escalated        int            `json:"escalated" yaml:"escalated"`
 }
 
 // DriftTrends provides trend analysis over time
@@ -332,6 +506,9 @@ type StatusReport struct {
 	GeneratedAt time.Time        `json:"generated_at" yaml:"generated_at"`
 	Summary     StatusSummary    `json:"summary" yaml:"summary"`
 	Endpoints   []EndpointStatus `json:"endpoints" yaml:"endpoints"`
+
+	// Last heartbeat recorded by the monitor, if heartbeats are enabled
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty" yaml:"last_heartbeat,omitempty"`
 }
 
 // StatusSummary provides high-level health statistics
@@ -340,8 +517,22 @@ type StatusSummary struct {
 	HealthyEndpoints   int `json:"healthy_endpoints" yaml:"healthy_endpoints"`
 	UnhealthyEndpoints int `json:"unhealthy_endpoints" yaml:"unhealthy_endpoints"`
 	UnknownEndpoints   int `json:"unknown_endpoints" yaml:"unknown_endpoints"`
+
+	// Unacknowledged drifts detected across all endpoints within statusDriftWindow
+	RecentDrifts DriftSeverityCounts `json:"recent_drifts" yaml:"recent_drifts"`
+}
+
+// DriftSeverityCounts counts drifts by severity
+type DriftSeverityCounts struct {
+	Critical int `json:"critical" yaml:"critical"`
+	High     int `json:"high" yaml:"high"`
+	Medium   int `json:"medium" yaml:"medium"`
+	Low      int `json:"low" yaml:"low"`
 }
 
+// statusDriftWindow is how far back the status report looks for drifts
+const statusDriftWindow = 7 * 24 * time.Hour
+
 // EndpointStatus represents the health status of a single endpoint
 type EndpointStatus struct {
 	ID               string    `json:"id" yaml:"id"`
@@ -353,6 +544,33 @@ type EndpointStatus struct {
 	SuccessRate      float64   `json:"success_rate" yaml:"success_rate"`
 	RecentDrifts     int       `json:"recent_drifts" yaml:"recent_drifts"`
 	Enabled          bool      `json:"enabled" yaml:"enabled"`
+
+	// Unacknowledged drifts among RecentDrifts
+	DriftsBySeverity DriftSeverityCounts `json:"drifts_by_severity" yaml:"drifts_by_severity"`
+
+	// Failure breakdown by category (network, timeout, connect_timeout, read_timeout, tls, dns, http, config)
+	Failures            map[string]int `json:"failures,omitempty" yaml:"failures,omitempty"`
+	LastFailureCategory string         `json:"last_failure_category,omitempty" yaml:"last_failure_category,omitempty"`
+
+	// Coefficient of variation of successful response times, and the composite
+	// health score from 0 (worst) to 100 (best); see healthScore
+	ResponseTimeVariation float64 `json:"response_time_variation" yaml:"response_time_variation"`
+	HealthScore           float64 `json:"health_score" yaml:"health_score"`
+
+	// Timing breakdown of the last check, when it was recorded
+	LastTiming *TimingBreakdown `json:"last_timing,omitempty" yaml:"last_timing,omitempty"`
+
+	// Availability over time, included with health --history
+	History *AvailabilityHistory `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+// TimingBreakdown splits the time to the first response byte into request
+// phases, in milliseconds
+type TimingBreakdown struct {
+	DNSMs     int64 `json:"dns_ms" yaml:"dns_ms"`
+	ConnectMs int64 `json:"connect_ms" yaml:"connect_ms"`
+	TLSMs     int64 `json:"tls_ms" yaml:"tls_ms"`
+	TTFBMs    int64 `json:"ttfb_ms" yaml:"ttfb_ms"`
 }
 
 // Helper functions
@@ -372,23 +590,259 @@ func parsePeriod(period string) (time.Duration, error) {
 	}
 }
 
-// generateDriftReport creates a comprehensive drift analysis report
-func generateDriftReport(drifts []*storage.Drift, period time.Duration) *DriftReport {
+// timeWindow is the time range covered by a report or an export
+type timeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// lastPeriod returns the window of the period up to now
+func lastPeriod(period time.Duration) timeWindow {
+	now := time.Now()
+	return timeWindow{Start: now.Add(-period), End: now}
+}
+
+// String describes the length of the window, such as "7 days"
+func (w timeWindow) String() string {
+	return formatPeriod(w.End.Sub(w.Start))
+}
+
+// timeWindowFromFlags resolves the window selected by a command's --period,
+// --since and --until flags
+func timeWindowFromFlags(cmd *cobra.Command, period string) (timeWindow, error) {
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return timeWindow{}, fmt.Errorf("failed to get %s flag: %w", "since", err)
+	}
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return timeWindow{}, fmt.Errorf("failed to get %s flag: %w", "until", err)
+	}
+
+	return resolveTimeWindow(period, since, until, time.Now())
+}
+
+// resolveTimeWindow returns the window from since to until, which take
+// precedence over the relative period. Without since, the window spans the
+// period up to until; without until, it ends now.
+func resolveTimeWindow(period, since, until string, now time.Time) (timeWindow, error) {
+	window := timeWindow{End: now}
+
+	if until != "" {
+		end, err := parseTimestamp(until)
+		if err != nil {
+			return timeWindow{}, fmt.Errorf("invalid --until: %w", err)
+		}
+		window.End = end
+	}
+
+	if since != "" {
+		start, err := parseTimestamp(since)
+		if err != nil {
+			return timeWindow{}, fmt.Errorf("invalid --since: %w", err)
+		}
+		window.Start = start
+	} else {
+		duration, err := parsePeriod(period)
+		if err != nil {
+			return timeWindow{}, fmt.Errorf("invalid period: %w", err)
+		}
+		window.Start = window.End.Add(-duration)
+	}
+
+	if !window.Start.Before(window.End) {
+		return timeWindow{}, fmt.Errorf("start time %s is not before end time %s",
+			window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339))
+	}
+
+	return window, nil
+}
+
+// parseTimestamp parses an RFC3339 time, or a date such as 2024-01-01 which
+// stands for the start of that day in local time
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 time nor a date (YYYY-MM-DD)", value)
+	}
+	return t, nil
+}
+
+// runsInWindow returns an endpoint's monitoring runs within a time window,
+// newest first
+func runsInWindow(db storage.Storage, endpointID string, window timeWindow) ([]*storage.MonitoringRun, error) {
+	runs, err := db.GetMonitoringHistory(endpointID, time.Since(window.Start))
+	if err != nil {
+		return nil, err
+	}
+
+	inWindow := runs[:0]
+	for _, run := range runs {
+		if !run.Timestamp.After(window.End) {
+			inWindow = append(inWindow, run)
+		}
+	}
+	return inWindow, nil
+}
+
+// generateDriftReport creates a comprehensive drift analysis report of the drifts
+// detected within a time window. A nil escalation policy leaves drift
+// severities unchanged.
+func generateDriftReport(drifts []*storage.Drift, window timeWindow, escalation *alerting.EscalationPolicy) *DriftReport {
 	now := time.Now()
-	startTime := now.Add(-period)
 
 	report := &DriftReport{
-		Period:    formatPeriod(period),
-		StartTime: startTime,
-		EndTime:   now,
-		Drifts:    drifts,
-		Summary:   generateDriftSummary(drifts),
-		Trends:    generateDriftTrends(drifts, startTime, now),
+		Period:      window.String(),
+		StartTime:   window.Start,
+		EndTime:     window.End,
+		Drifts:      drifts,
+		Summary:     generateDriftSummary(drifts),
+		Trends:      generateDriftTrends(drifts, window.Start, window.End),
+		Escalations: findDriftEscalations(drifts, escalation, now),
 	}
+	report.Summary.Escalated = len(report.Escalations)
 
 	return report
 }
 
+// findDriftEscalations lists the drifts whose effective severity is raised by the escalation policy
+func findDriftEscalations(drifts []*storage.Drift, escalation *alerting.EscalationPolicy, now time.Time) []DriftEscalation {
+	var escalations []DriftEscalation
+
+	for _, drift := range drifts {
+		severity := escalation.Severity(drift, now)
+		if severity == drift.Severity {
+			continue
+		}
+
+		escalations = append(escalations, DriftEscalation{
+			DriftID:           drift.ID,
+			EndpointID:        drift.EndpointID,
+			DetectedAt:        drift.DetectedAt,
+			Severity:          drift.Severity,
+			EscalatedSeverity: severity,
+		})
+	}
+
+	return escalations
+}
+
+// explainDrifts classifies stored field drifts again with the comparison options
+// of their endpoint, with its sensitivity preset or defaultSensitivity applied,
+// to explain them. Drifts that are not field changes, such as status or header
+// changes, are not explained.
+func explainDrifts(drifts []*storage.Drift, endpoints []config.EndpointConfig, defaultSensitivity config.Sensitivity) []DriftExplanation {
+	engines := make(map[string]drift.DiffEngine)
+	for _, endpointConfig := range endpoints {
+		// Options are still usable when the endpoint's spec cannot be loaded
+		options, _ := diffOptionsForEndpoint(config.ApplySensitivity(endpointConfig, defaultSensitivity))
+		engines[endpointConfig.ID] = drift.NewDiffEngineWithOptions(options)
+	}
+
+	var explanations []DriftExplanation
+	for _, stored := range drifts {
+		diffType, ok := fieldDiffTypes[stored.DriftType]
+		if !ok || stored.FieldPath == "" {
+			continue
+		}
+
+		engine, ok := engines[stored.EndpointID]
+		if !ok {
+			engine = drift.NewDiffEngine()
+		}
+
+		classification := engine.ClassifyChange(&drift.FieldDiff{
+			Path:     stored.FieldPath,
+			Type:     diffType,
+			Severity: drift.Severity(stored.Severity),
+		})
+
+		explanations = append(explanations, DriftExplanation{
+			DriftID:           stored.ID,
+			FieldPath:         stored.FieldPath,
+			Breaking:          classification.Breaking,
+			ChangeExplanation: *classification.Explain(),
+		})
+	}
+
+	return explanations
+}
+
+// driftGroupKeys returns the group of a drift for each --group-by option
+var driftGroupKeys = map[string]func(*storage.Drift) string{
+	"endpoint": func(d *storage.Drift) string { return d.EndpointID },
+	"type":     func(d *storage.Drift) string { return d.DriftType },
+	"severity": func(d *storage.Drift) string { return d.Severity },
+	"path":     func(d *storage.Drift) string { return fieldPathPrefix(d.FieldPath) },
+}
+
+// groupDrifts organizes drifts into groups by the given option, keeping the
+// order of drifts within each group. Severity groups are ordered from critical
+// to low, other groups by decreasing size.
+func groupDrifts(drifts []*storage.Drift, groupBy string) []DriftGroup {
+	groupKey := driftGroupKeys[groupBy]
+	if groupKey == nil {
+		return nil
+	}
+
+	indexes := make(map[string]int)
+	var groups []DriftGroup
+	for _, drift := range drifts {
+		key := groupKey(drift)
+		index, exists := indexes[key]
+		if !exists {
+			index = len(groups)
+			indexes[key] = index
+			groups = append(groups, DriftGroup{Key: key})
+		}
+
+		group := &groups[index]
+		group.Count++
+		group.Drifts = append(group.Drifts, drift)
+		if drift.Severity == "high" || drift.Severity == "critical" {
+			group.Severe++
+		}
+	}
+
+	severityRank := map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groupBy == "severity" {
+			return severityRank[groups[i].Key] < severityRank[groups[j].Key]
+		}
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Key < groups[j].Key
+	})
+
+	return groups
+}
+
+// fieldPathPrefix returns the top-level field of a drift path, such as "$.data"
+// for "$.data.items[0].id", or "$" for drifts of the whole response
+func fieldPathPrefix(path string) string {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if end := strings.IndexAny(rest, ".["); end >= 0 {
+		rest = rest[:end]
+	}
+	if rest == "" {
+		return "$"
+	}
+	return "$." + rest
+}
+
+// fieldDiffTypes maps the drift types of field changes to their diff type
+var fieldDiffTypes = map[string]drift.DiffType{
+	string(drift.ChangeTypeFieldAdded):    drift.DiffTypeAdded,
+	string(drift.ChangeTypeFieldRemoved):  drift.DiffTypeRemoved,
+	string(drift.ChangeTypeFieldModified): drift.DiffTypeModified,
+	string(drift.ChangeTypeTypeChange):    drift.DiffTypeTypeChanged,
+}
+
 // generateDriftSummary creates summary statistics for drifts
 func generateDriftSummary(drifts []*storage.Drift) DriftSummary {
 	summary := DriftSummary{
@@ -501,10 +955,10 @@ func generateStatusReport(db storage.Storage, endpointIDs []string, unhealthyOnl
 			continue
 		}
 
-		// Get recent drifts (last 7 days)
+		// Get recent drifts
 		drifts, err := db.GetDrifts(storage.DriftFilters{
 			EndpointID: endpointID,
-			StartTime:  time.Now().Add(-7 * 24 * time.Hour),
+			StartTime:  time.Now().Add(-statusDriftWindow),
 		})
 		if err != nil {
 			continue
@@ -514,13 +968,24 @@ func generateStatusReport(db storage.Storage, endpointIDs []string, unhealthyOnl
 		status := calculateEndpointStatus(runs)
 		successRate := calculateSuccessRate(runs)
 
+		failures, lastFailureCategory := summarizeFailures(runs)
+
 		var lastChecked time.Time
 		var lastResponseTime int64
+		var lastTiming *TimingBreakdown
 
 		if len(runs) > 0 {
 			lastRun := runs[0] // Most recent run
 			lastChecked = lastRun.Timestamp
 			lastResponseTime = lastRun.ResponseTimeMs
+			if lastRun.TTFBMs > 0 {
+				lastTiming = &TimingBreakdown{
+					DNSMs:     lastRun.DNSTimeMs,
+					ConnectMs: lastRun.ConnectTimeMs,
+					TLSMs:     lastRun.TLSTimeMs,
+					TTFBMs:    lastRun.TTFBMs,
+				}
+			}
 		}
 
 		endpointStatus := EndpointStatus{
@@ -533,7 +998,15 @@ func generateStatusReport(db storage.Storage, endpointIDs []string, unhealthyOnl
 			SuccessRate:      successRate,
 			RecentDrifts:     len(drifts),
 			Enabled:          true, // We'll need to parse the config JSON to get this
+			DriftsBySeverity: countDriftsBySeverity(drifts),
+
+			Failures:            failures,
+			LastFailureCategory: lastFailureCategory,
+
+			ResponseTimeVariation: responseTimeVariation(runs),
+			LastTiming:            lastTiming,
 		}
+		endpointStatus.HealthScore = healthScore(endpointStatus)
 
 		// Filter unhealthy only if requested
 		if unhealthyOnly && status == "healthy" {
@@ -546,6 +1019,10 @@ func generateStatusReport(db storage.Storage, endpointIDs []string, unhealthyOnl
 	// Generate summary
 	report.Summary = generateStatusSummary(report.Endpoints)
 
+	if beatAt, err := db.GetLastHeartbeat(); err == nil && !beatAt.IsZero() {
+		report.LastHeartbeat = &beatAt
+	}
+
 	return report
 }
 
@@ -576,20 +1053,56 @@ func calculateEndpointStatus(runs []*storage.MonitoringRun) string {
 	return "unhealthy"
 }
 
-// calculateSuccessRate calculates the success rate over recent runs
+// calculateSuccessRate calculates the success rate over recent runs, counting
+// each check of runs merged by compaction
 func calculateSuccessRate(runs []*storage.MonitoringRun) float64 {
 	if len(runs) == 0 {
 		return 0.0
 	}
 
-	successCount := 0
+	checks, successCount := 0, 0
 	for _, run := range runs {
-		if run.ResponseStatus >= 200 && run.ResponseStatus < 300 {
-			successCount++
+		checks += run.Checks()
+		if run.Succeeded() {
+			successCount += run.Checks()
 		}
 	}
 
-	return float64(successCount) / float64(len(runs)) * 100
+	return float64(successCount) / float64(checks) * 100
+}
+
+// summarizeFailures counts failed runs by category and returns the category of
+// the most recent failure. Runs are expected newest first.
+func summarizeFailures(runs []*storage.MonitoringRun) (map[string]int, string) {
+	var failures map[string]int
+	lastCategory := ""
+
+	for _, run := range runs {
+		category := run.FailureCategory
+		if category == "" {
+			continue
+		}
+		if failures == nil {
+			failures = make(map[string]int)
+		}
+		failures[category]++
+		if lastCategory == "" {
+			lastCategory = category
+		}
+	}
+
+	return failures, lastCategory
+}
+
+// describeFailureSource explains whether a failure category points at the API or the network
+func describeFailureSource(category string) string {
+	if errors.FailureCategory(category).IsInfrastructure() {
+		return "network issue"
+	}
+	if category == string(errors.FailureCategoryConfig) {
+		return "configuration issue"
+	}
+	return "API error"
 }
 
 // generateStatusSummary creates summary statistics for status report
@@ -607,11 +1120,38 @@ func generateStatusSummary(endpoints []EndpointStatus) StatusSummary {
 		default:
 			summary.UnknownEndpoints++
 		}
+
+		summary.RecentDrifts.Critical += ep.DriftsBySeverity.Critical
+		summary.RecentDrifts.High += ep.DriftsBySeverity.High
+		summary.RecentDrifts.Medium += ep.DriftsBySeverity.Medium
+		summary.RecentDrifts.Low += ep.DriftsBySeverity.Low
 	}
 
 	return summary
 }
 
+// countDriftsBySeverity counts unacknowledged drifts by severity, ignoring
+// unknown severities
+func countDriftsBySeverity(drifts []*storage.Drift) DriftSeverityCounts {
+	var counts DriftSeverityCounts
+	for _, drift := range drifts {
+		if drift.Acknowledged {
+			continue
+		}
+		switch drift.Severity {
+		case "critical":
+			counts.Critical++
+		case "high":
+			counts.High++
+		case "medium":
+			counts.Medium++
+		case "low":
+			counts.Low++
+		}
+	}
+	return counts
+}
+
 // formatPeriod converts duration to human-readable string
 func formatPeriod(d time.Duration) string {
 	hours := int(d.Hours())
@@ -654,6 +1194,9 @@ func outputReportTable(report *DriftReport) {
 	fmt.Printf("\nSUMMARY\n")
 	fmt.Printf("Total Drifts: %d\n", report.Summary.TotalDrifts)
 	fmt.Printf("Acknowledged Rate: %.1f%%\n", report.Summary.AcknowledgedRate)
+	if report.Summary.Escalated > 0 {
+		fmt.Printf("Escalated: %d (unacknowledged past the escalation policy age)\n", report.Summary.Escalated)
+	}
 
 	if len(report.Summary.BySeverity) > 0 {
 		fmt.Printf("\nBy Severity:\n")
@@ -669,48 +1212,28 @@ func outputReportTable(report *DriftReport) {
 		}
 	}
 
+	escalated := make(map[int64]string, len(report.Escalations))
+	for _, escalation := range report.Escalations {
+		escalated[escalation.DriftID] = escalation.EscalatedSeverity
+	}
+
+	explained := make(map[int64]DriftExplanation, len(report.Explanations))
+	for _, explanation := range report.Explanations {
+		explained[explanation.DriftID] = explanation
+	}
+
 	// Recent drifts section
 	if len(report.Drifts) > 0 {
 		fmt.Printf("\nRECENT DRIFTS\n")
-		fmt.Printf("%-20s %-10s %-15s %-30s %-10s\n",
-			"ENDPOINT", "SEVERITY", "TYPE", "DESCRIPTION", "STATUS")
-		fmt.Println(strings.Repeat("-", 95))
-
-		// Show up to 10 most recent drifts
-		displayCount := 10
-		if len(report.Drifts) < displayCount {
-			displayCount = len(report.Drifts)
-		}
-
-		for i := 0; i < displayCount; i++ {
-			drift := report.Drifts[i]
-			status := "New"
-			if drift.Acknowledged {
-				status = "Acked"
-			}
-
-			// Truncate long descriptions
-			description := drift.Description
-			if len(description) > 27 {
-				description = description[:24] + "..."
-			}
-
-			// Truncate long endpoint IDs
-			endpointID := drift.EndpointID
-			if len(endpointID) > 17 {
-				endpointID = endpointID[:14] + "..."
-			}
-
-			fmt.Printf("%-20s %-10s %-15s %-30s %-10s\n",
-				endpointID,
-				strings.ToUpper(string(drift.Severity[0]))+drift.Severity[1:],
-				drift.DriftType,
-				description,
-				status)
-		}
+		outputDriftRows(report.Drifts, escalated, explained)
+	}
 
-		if len(report.Drifts) > displayCount {
-			fmt.Printf("\n... and %d more drifts\n", len(report.Drifts)-displayCount)
+	// Grouped drifts section
+	if len(report.Groups) > 0 {
+		fmt.Printf("\nDRIFTS BY %s\n", strings.ToUpper(report.GroupBy))
+		for _, group := range report.Groups {
+			fmt.Printf("\n%s: %d drifts (%d severe)\n", group.Key, group.Count, group.Severe)
+			outputDriftRows(group.Drifts, escalated, explained)
 		}
 	}
 
@@ -724,6 +1247,78 @@ func outputReportTable(report *DriftReport) {
 	}
 }
 
+// outputDriftRows prints up to 10 drifts as table rows, marking escalated
+// severities and adding the explanation of explained drifts
+func outputDriftRows(drifts []*storage.Drift, escalated map[int64]string, explained map[int64]DriftExplanation) {
+	fmt.Printf("%-20s %-10s %-15s %-30s %-10s %-11s %-11s %-16s\n",
+		"ENDPOINT", "SEVERITY", "TYPE", "DESCRIPTION", "STATUS", "FIRST SEEN", "LAST SEEN", "FINGERPRINT")
+	fmt.Println(strings.Repeat("-", 136))
+
+	// Show up to 10 most recent drifts
+	displayCount := 10
+	if len(drifts) < displayCount {
+		displayCount = len(drifts)
+	}
+
+	for i := 0; i < displayCount; i++ {
+		drift := drifts[i]
+		status := "New"
+		if drift.Acknowledged {
+			status = "Acked"
+		}
+
+		// Truncate long descriptions
+		description := drift.Description
+		if len(description) > 27 {
+			description = description[:24] + "..."
+		}
+
+		// Truncate long endpoint IDs
+		endpointID := drift.EndpointID
+		if len(endpointID) > 17 {
+			endpointID = endpointID[:14] + "..."
+		}
+
+		// Escalated severities are marked with an asterisk
+		severity := strings.ToUpper(string(drift.Severity[0])) + drift.Severity[1:]
+		if escalatedSeverity, ok := escalated[drift.ID]; ok {
+			severity = strings.ToUpper(string(escalatedSeverity[0])) + escalatedSeverity[1:] + "*"
+		}
+
+		fingerprint := drift.Fingerprint
+		if fingerprint == "" {
+			fingerprint = "-"
+		}
+
+		fmt.Printf("%-20s %-10s %-15s %-30s %-10s %-11s %-11s %-16s\n",
+			endpointID,
+			severity,
+			drift.DriftType,
+			description,
+			status,
+			formatDetectionDate(drift.FirstDetectedAt),
+			formatDetectionDate(drift.LastDetectedAt),
+			fingerprint)
+
+		if explanation, ok := explained[drift.ID]; ok {
+			fmt.Printf("  why (%s): %s\n", explanation.FieldPath, formatExplanation(&explanation.ChangeExplanation))
+		}
+	}
+
+	if len(drifts) > displayCount {
+		fmt.Printf("\n... and %d more drifts\n", len(drifts)-displayCount)
+	}
+}
+
+// formatDetectionDate formats the first or last detection of a drift path, which
+// drifts recorded before detections were tracked do not have
+func formatDetectionDate(detectedAt time.Time) string {
+	if detectedAt.IsZero() {
+		return "-"
+	}
+	return detectedAt.Format("2006-01-02")
+}
+
 // outputStatusJSON outputs status report in JSON format
 func outputStatusJSON(report *StatusReport) error {
 	encoder := json.NewEncoder(os.Stdout)
@@ -752,6 +1347,17 @@ func outputStatusTable(report *StatusReport) {
 		report.Summary.HealthyEndpoints,
 		report.Summary.UnhealthyEndpoints,
 		report.Summary.UnknownEndpoints)
+	fmt.Printf("Unacknowledged drifts (last %s): Critical: %d | High: %d | Medium: %d | Low: %d\n",
+		formatPeriod(statusDriftWindow),
+		report.Summary.RecentDrifts.Critical,
+		report.Summary.RecentDrifts.High,
+		report.Summary.RecentDrifts.Medium,
+		report.Summary.RecentDrifts.Low)
+	if report.LastHeartbeat != nil {
+		fmt.Printf("Monitor Heartbeat: %s (%s ago)\n",
+			report.LastHeartbeat.Format("2006-01-02 15:04:05"),
+			report.GeneratedAt.Sub(*report.LastHeartbeat).Round(time.Second))
+	}
 
 	if len(report.Endpoints) == 0 {
 		fmt.Printf("\nNo endpoints found.\n")
@@ -764,6 +1370,7 @@ func outputStatusTable(report *StatusReport) {
 		"ID", "METHOD", "STATUS", "LAST CHECKED", "RESP TIME", "SUCCESS", "DRIFTS")
 	fmt.Println(strings.Repeat("-", 85))
 
+	p := diffPainter{color: colorEnabled(os.Stdout)}
 	for _, ep := range report.Endpoints {
 		// Format last checked time
 		lastChecked := "never"
@@ -790,31 +1397,99 @@ func outputStatusTable(report *StatusReport) {
 			displayID = displayID[:14] + "..."
 		}
 
-		fmt.Printf("%-20s %-8s %-10s %-12s %-8s %-8s %-6d\n",
+		// Pad the status before coloring it, as escape sequences take no width
+		status := fmt.Sprintf("%-10s", strings.ToUpper(string(ep.Status[0]))+ep.Status[1:])
+
+		fmt.Printf("%-20s %-8s %s %-12s %-8s %-8s %-6d\n",
 			displayID,
 			ep.Method,
-			strings.ToUpper(string(ep.Status[0]))+ep.Status[1:],
+			p.paint(statusColor(ep.Status), status),
 			lastChecked,
 			respTime,
 			successRate,
 			ep.RecentDrifts)
 	}
+
+	// Timing section, showing where the time of the last check was spent
+	var timed []EndpointStatus
+	for _, ep := range report.Endpoints {
+		if ep.LastTiming != nil {
+			timed = append(timed, ep)
+		}
+	}
+	if len(timed) > 0 {
+		fmt.Printf("\nTIMING BREAKDOWN (last check)\n")
+		fmt.Printf("%-20s %-8s %-8s %-8s %-8s\n", "ID", "DNS", "CONNECT", "TLS", "TTFB")
+		fmt.Println(strings.Repeat("-", 85))
+		for _, ep := range timed {
+			fmt.Printf("%-20s %-8s %-8s %-8s %-8s\n",
+				ep.ID,
+				fmt.Sprintf("%dms", ep.LastTiming.DNSMs),
+				fmt.Sprintf("%dms", ep.LastTiming.ConnectMs),
+				fmt.Sprintf("%dms", ep.LastTiming.TLSMs),
+				fmt.Sprintf("%dms", ep.LastTiming.TTFBMs))
+		}
+	}
+
+	// Failures section, separating API errors from network problems
+	var failing []EndpointStatus
+	for _, ep := range report.Endpoints {
+		if len(ep.Failures) > 0 {
+			failing = append(failing, ep)
+		}
+	}
+	if len(failing) == 0 {
+		return
+	}
+
+	fmt.Printf("\nFAILURES (last 24h)\n")
+	fmt.Println(strings.Repeat("-", 85))
+	for _, ep := range failing {
+		categories := make([]string, 0, len(ep.Failures))
+		for category := range ep.Failures {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		counts := make([]string, 0, len(categories))
+		for _, category := range categories {
+			counts = append(counts, fmt.Sprintf("%s=%d", category, ep.Failures[category]))
+		}
+
+		fmt.Printf("%-20s %-40s last: %s (%s)\n",
+			ep.ID,
+			strings.Join(counts, " "),
+			ep.LastFailureCategory,
+			describeFailureSource(ep.LastFailureCategory))
+	}
+}
+
+// statusColor returns the color an endpoint status is highlighted with
+func statusColor(status string) string {
+	switch status {
+	case "healthy":
+		return ansiGreen
+	case "unhealthy":
+		return ansiRed
+	default:
+		return ansiYellow
+	}
 }
 
 // Export functions
 
 // exportDrifts exports drift data in the specified format
-func exportDrifts(db storage.Storage, format, endpointID string, period time.Duration, outputFile string) error {
+func exportDrifts(db storage.Storage, format, endpointID string, window timeWindow, outputFile string) (map[string]int, error) {
 	// Get drifts
 	filters := storage.DriftFilters{
 		EndpointID: endpointID,
-		StartTime:  time.Now().Add(-period),
-		EndTime:    time.Now(),
+		StartTime:  window.Start,
+		EndTime:    window.End,
 	}
 
 	drifts, err := db.GetDrifts(filters)
 	if err != nil {
-		return fmt.Errorf("failed to get drifts: %w", err)
+		return nil, fmt.Errorf("failed to get drifts: %w", err)
 	}
 
 	// Determine output destination
@@ -823,11 +1498,11 @@ func exportDrifts(db storage.Storage, format, endpointID string, period time.Dur
 		// Use current working directory as allowed directory for output files
 		cwd, err := os.Getwd()
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return nil, fmt.Errorf("failed to get current working directory: %w", err)
 		}
 		file, err := security.SafeCreateFile(outputFile, cwd)
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 		defer file.Close()
 		output = file
@@ -835,26 +1510,28 @@ func exportDrifts(db storage.Storage, format, endpointID string, period time.Dur
 		output = os.Stdout
 	}
 
+	counts := map[string]int{"drifts": len(drifts)}
+
 	// Export based on format
 	switch format {
 	case "json":
 		encoder := json.NewEncoder(output)
 		encoder.SetIndent("", "  ")
-		return encoder.Encode(drifts)
+		return counts, encoder.Encode(drifts)
 	case "yaml":
 		encoder := yaml.NewEncoder(output)
 		encoder.SetIndent(2)
 		defer encoder.Close()
-		return encoder.Encode(drifts)
+		return counts, encoder.Encode(drifts)
 	case "csv":
-		return exportDriftsCSV(drifts, output)
+		return counts, exportDriftsCSV(drifts, output)
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
 // exportMonitoringRuns exports monitoring run data
-func exportMonitoringRuns(db storage.Storage, format, endpointID string, period time.Duration, outputFile string) error {
+func exportMonitoringRuns(db storage.Storage, format, endpointID string, window timeWindow, outputFile string) (map[string]int, error) {
 	// Get all endpoints if none specified
 	var endpointIDs []string
 	if endpointID != "" {
@@ -862,7 +1539,7 @@ func exportMonitoringRuns(db storage.Storage, format, endpointID string, period
 	} else {
 		endpoints, err := db.ListEndpoints()
 		if err != nil {
-			return fmt.Errorf("failed to list endpoints: %w", err)
+			return nil, fmt.Errorf("failed to list endpoints: %w", err)
 		}
 		for _, ep := range endpoints {
 			endpointIDs = append(endpointIDs, ep.ID)
@@ -872,7 +1549,7 @@ func exportMonitoringRuns(db storage.Storage, format, endpointID string, period
 	// Collect all runs
 	var allRuns []*storage.MonitoringRun
 	for _, epID := range endpointIDs {
-		runs, err := db.GetMonitoringHistory(epID, period)
+		runs, err := runsInWindow(db, epID, window)
 		if err != nil {
 			continue // Skip endpoints with errors
 		}
@@ -885,11 +1562,11 @@ func exportMonitoringRuns(db storage.Storage, format, endpointID string, period
 		// Use current working directory as allowed directory for output files
 		cwd, err := os.Getwd()
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return nil, fmt.Errorf("failed to get current working directory: %w", err)
 		}
 		file, err := security.SafeCreateFile(outputFile, cwd)
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 		defer file.Close()
 		output = file
@@ -897,36 +1574,38 @@ func exportMonitoringRuns(db storage.Storage, format, endpointID string, period
 		output = os.Stdout
 	}
 
+	counts := map[string]int{"monitoring_runs": len(allRuns)}
+
 	// Export based on format
 	switch format {
 	case "json":
 		encoder := json.NewEncoder(output)
 		encoder.SetIndent("", "  ")
-		return encoder.Encode(allRuns)
+		return counts, encoder.Encode(allRuns)
 	case "yaml":
 		encoder := yaml.NewEncoder(output)
 		encoder.SetIndent(2)
 		defer encoder.Close()
-		return encoder.Encode(allRuns)
+		return counts, encoder.Encode(allRuns)
 	case "csv":
-		return exportRunsCSV(allRuns, output)
+		return counts, exportRunsCSV(allRuns, output)
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
 // exportAllData exports both drifts and monitoring runs
-func exportAllData(db storage.Storage, format, endpointID string, period time.Duration, outputFile string) error {
+func exportAllData(db storage.Storage, format, endpointID string, window timeWindow, outputFile string) (map[string]int, error) {
 	// Get drifts
 	driftFilters := storage.DriftFilters{
 		EndpointID: endpointID,
-		StartTime:  time.Now().Add(-period),
-		EndTime:    time.Now(),
+		StartTime:  window.Start,
+		EndTime:    window.End,
 	}
 
 	drifts, err := db.GetDrifts(driftFilters)
 	if err != nil {
-		return fmt.Errorf("failed to get drifts: %w", err)
+		return nil, fmt.Errorf("failed to get drifts: %w", err)
 	}
 
 	// Get monitoring runs
@@ -936,7 +1615,7 @@ func exportAllData(db storage.Storage, format, endpointID string, period time.Du
 	} else {
 		endpoints, err := db.ListEndpoints()
 		if err != nil {
-			return fmt.Errorf("failed to list endpoints: %w", err)
+			return nil, fmt.Errorf("failed to list endpoints: %w", err)
 		}
 		for _, ep := range endpoints {
 			endpointIDs = append(endpointIDs, ep.ID)
@@ -945,7 +1624,7 @@ func exportAllData(db storage.Storage, format, endpointID string, period time.Du
 
 	var allRuns []*storage.MonitoringRun
 	for _, epID := range endpointIDs {
-		runs, err := db.GetMonitoringHistory(epID, period)
+		runs, err := runsInWindow(db, epID, window)
 		if err != nil {
 			continue
 		}
@@ -962,7 +1641,7 @@ func exportAllData(db storage.Storage, format, endpointID string, period time.Du
 		Drifts:         drifts,
 		MonitoringRuns: allRuns,
 		ExportedAt:     time.Now(),
-		Period:         formatPeriod(period),
+		Period:         window.String(),
 	}
 
 	// Determine output destination
@@ -971,11 +1650,11 @@ func exportAllData(db storage.Storage, format, endpointID string, period time.Du
 		// Use current working directory as allowed directory for output files
 		cwd, err := os.Getwd()
 		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
+			return nil, fmt.Errorf("failed to get current working directory: %w", err)
 		}
 		file, err := security.SafeCreateFile(outputFile, cwd)
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 		defer file.Close()
 		output = file
@@ -983,27 +1662,29 @@ func exportAllData(db storage.Storage, format, endpointID string, period time.Du
 		output = os.Stdout
 	}
 
+	counts := map[string]int{"drifts": len(drifts), "monitoring_runs": len(allRuns)}
+
 	// Export based on format
 	switch format {
 	case "json":
 		encoder := json.NewEncoder(output)
 		encoder.SetIndent("", "  ")
-		return encoder.Encode(exportData)
+		return counts, encoder.Encode(exportData)
 	case "yaml":
 		encoder := yaml.NewEncoder(output)
 		encoder.SetIndent(2)
 		defer encoder.Close()
-		return encoder.Encode(exportData)
+		return counts, encoder.Encode(exportData)
 	case "csv":
 		// For CSV, we'll export drifts and runs separately
 		fmt.Fprintln(output, "# DRIFTS")
 		if err := exportDriftsCSV(drifts, output); err != nil {
-			return err
+			return nil, err
 		}
 		fmt.Fprintln(output, "\n# MONITORING RUNS")
-		return exportRunsCSV(allRuns, output)
+		return counts, exportRunsCSV(allRuns, output)
 	default:
-		return fmt.Errorf("unsupported format: %s", format)
+		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 }
 
@@ -1051,7 +1732,7 @@ func exportRunsCSV(runs []*storage.MonitoringRun, output *os.File) error {
 	// Write header
 	header := []string{
 		"ID", "EndpointID", "Timestamp", "ResponseStatus", "ResponseTimeMs",
-		"ValidationResult",
+		"ValidationResult", "FailureCategory", "ErrorMessage",
 	}
 	if err := writer.Write(header); err != nil {
 		return err
@@ -1066,6 +1747,8 @@ func exportRunsCSV(runs []*storage.MonitoringRun, output *os.File) error {
 			strconv.Itoa(run.ResponseStatus),
 			strconv.FormatInt(run.ResponseTimeMs, 10),
 			run.ValidationResult,
+			run.FailureCategory,
+			run.ErrorMessage,
 		}
 		if err := writer.Write(record); err != nil {
 			return err
@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/alerting"
+	"github.com/k0ns0l/driftwatch/internal/config"
 	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,6 +85,46 @@ func TestParsePeriod(t *testing.T) {
 	}
 }
 
+func TestResolveTimeWindow(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name          string
+		period        string
+		since         string
+		until         string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedErr   string
+	}{
+		{name: "period", period: "7d", expectedStart: now.Add(-7 * 24 * time.Hour), expectedEnd: now},
+		{name: "dates override period", period: "7d", since: "2024-01-01", until: "2024-02-01", expectedStart: january, expectedEnd: february},
+		{name: "since until now", period: "7d", since: "2024-03-10T09:30:00Z", expectedStart: time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC), expectedEnd: now},
+		{name: "period before until", period: "24h", until: "2024-02-01", expectedStart: february.Add(-24 * time.Hour), expectedEnd: february},
+		{name: "invalid since", period: "7d", since: "last tuesday", expectedErr: "invalid --since"},
+		{name: "invalid until", period: "7d", until: "2024-13-01", expectedErr: "invalid --until"},
+		{name: "since after until", period: "7d", since: "2024-02-01", until: "2024-01-01", expectedErr: "is not before end time"},
+		{name: "invalid period", period: "fortnight", expectedErr: "invalid period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := resolveTimeWindow(tt.period, tt.since, tt.until, now)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.expectedStart.Equal(window.Start), "start %s", window.Start)
+			assert.True(t, tt.expectedEnd.Equal(window.End), "end %s", window.End)
+		})
+	}
+}
+
 func TestGenerateDriftSummary(t *testing.T) {
 	now := time.Now()
 	drifts := []*storage.Drift{
@@ -281,6 +323,14 @@ func TestCalculateSuccessRate(t *testing.T) {
 			},
 			expected: 50.0, // 2 out of 4 successful
 		},
+		{
+			name: "compacted runs",
+			runs: []*storage.MonitoringRun{
+				{ResponseStatus: 200, CheckCount: 3},
+				{ResponseStatus: 500},
+			},
+			expected: 75.0, // 3 out of 4 checks successful
+		},
 	}
 
 	for _, tt := range tests {
@@ -291,6 +341,23 @@ func TestCalculateSuccessRate(t *testing.T) {
 	}
 }
 
+func TestSummarizeFailures(t *testing.T) {
+	failures, last := summarizeFailures([]*storage.MonitoringRun{
+		{ResponseStatus: 200},
+		{FailureCategory: "timeout"},
+		{ResponseStatus: 503, FailureCategory: "http"},
+		{FailureCategory: "timeout"},
+	})
+	assert.Equal(t, map[string]int{"timeout": 2, "http": 1}, failures)
+	assert.Equal(t, "timeout", last)
+	assert.Equal(t, "network issue", describeFailureSource(last))
+	assert.Equal(t, "API error", describeFailureSource("http"))
+
+	failures, last = summarizeFailures([]*storage.MonitoringRun{{ResponseStatus: 200}})
+	assert.Nil(t, failures)
+	assert.Empty(t, last)
+}
+
 func TestFormatPeriod(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -357,7 +424,7 @@ func TestGenerateDriftReport(t *testing.T) {
 		},
 	}
 
-	report := generateDriftReport(drifts, period)
+	report := generateDriftReport(drifts, lastPeriod(period), nil)
 
 	assert.Equal(t, "1 day", report.Period)
 	assert.Equal(t, 2, len(report.Drifts))
@@ -367,6 +434,91 @@ func TestGenerateDriftReport(t *testing.T) {
 	assert.NotEmpty(t, report.Trends.MostActiveEndpoints)
 }
 
+func TestGenerateDriftReportWithEscalation(t *testing.T) {
+	now := time.Now()
+
+	escalation, err := alerting.NewEscalationPolicy(config.EscalationConfig{
+		Enabled: true,
+		Rules:   []config.EscalationRuleConfig{{After: 72 * time.Hour, Levels: 1}},
+	})
+	require.NoError(t, err)
+
+	drifts := []*storage.Drift{
+		{ID: 1, EndpointID: "api-1", DetectedAt: now.Add(-time.Hour), Severity: "high"},
+		{ID: 2, EndpointID: "api-1", DetectedAt: now.Add(-96 * time.Hour), Severity: "high"},
+		{ID: 3, EndpointID: "api-2", DetectedAt: now.Add(-96 * time.Hour), Severity: "medium", Acknowledged: true},
+	}
+
+	report := generateDriftReport(drifts, lastPeriod(7*24*time.Hour), escalation)
+
+	require.Len(t, report.Escalations, 1)
+	assert.Equal(t, int64(2), report.Escalations[0].DriftID)
+	assert.Equal(t, "high", report.Escalations[0].Severity)
+	assert.Equal(t, "critical", report.Escalations[0].EscalatedSeverity)
+	assert.Equal(t, 1, report.Summary.Escalated)
+	assert.Equal(t, 2, report.Summary.BySeverity["high"])
+}
+
+func TestExplainDrifts(t *testing.T) {
+	endpoints := []config.EndpointConfig{{
+		ID:         "api-1",
+		URL:        "https://api.example.com/users",
+		Method:     "GET",
+		Validation: config.ValidationConfig{RequiredFields: []string{"$.email"}},
+	}}
+
+	drifts := []*storage.Drift{
+		{ID: 1, EndpointID: "api-1", DriftType: "field_removed", FieldPath: "$.email", Severity: "high"},
+		{ID: 2, EndpointID: "api-2", DriftType: "field_modified", FieldPath: "$.user_id", Severity: "high"},
+		{ID: 3, EndpointID: "api-1", DriftType: "status_change", Severity: "critical"},
+	}
+
+	explanations := explainDrifts(drifts, endpoints, "")
+	require.Len(t, explanations, 2)
+
+	assert.Equal(t, int64(1), explanations[0].DriftID)
+	assert.True(t, explanations[0].Breaking)
+	assert.Equal(t, []string{"field_removal", "required_field"}, explanations[0].Rules)
+	assert.Contains(t, explanations[0].Reasoning, "required")
+
+	assert.Equal(t, int64(2), explanations[1].DriftID)
+	assert.Equal(t, []string{"critical_field:id"}, explanations[1].Rules)
+	assert.Contains(t, explanations[1].Reasoning, "matches 'id'")
+}
+
+func TestGroupDrifts(t *testing.T) {
+	drifts := []*storage.Drift{
+		{ID: 1, EndpointID: "api-1", DriftType: "field_removed", FieldPath: "$.data.items[0].id", Severity: "high"},
+		{ID: 2, EndpointID: "api-2", DriftType: "field_added", FieldPath: "$.meta.total", Severity: "low"},
+		{ID: 3, EndpointID: "api-1", DriftType: "field_modified", FieldPath: "$.data.name", Severity: "critical"},
+		{ID: 4, EndpointID: "api-1", DriftType: "status_change", Severity: "medium"},
+	}
+
+	groups := groupDrifts(drifts, "path")
+	require.Len(t, groups, 3)
+	assert.Equal(t, "$.data", groups[0].Key)
+	assert.Equal(t, 2, groups[0].Count)
+	assert.Equal(t, 2, groups[0].Severe)
+	assert.Equal(t, int64(1), groups[0].Drifts[0].ID)
+	assert.Equal(t, int64(3), groups[0].Drifts[1].ID)
+	assert.Equal(t, "$", groups[1].Key)
+	assert.Equal(t, "$.meta", groups[2].Key)
+
+	groups = groupDrifts(drifts, "severity")
+	keys := make([]string, 0, len(groups))
+	for _, group := range groups {
+		keys = append(keys, group.Key)
+	}
+	assert.Equal(t, []string{"critical", "high", "medium", "low"}, keys)
+
+	groups = groupDrifts(drifts, "endpoint")
+	require.Len(t, groups, 2)
+	assert.Equal(t, "api-1", groups[0].Key)
+	assert.Equal(t, 3, groups[0].Count)
+
+	assert.Nil(t, groupDrifts(drifts, "unknown"))
+}
+
 func TestOutputReportJSON(t *testing.T) {
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -403,6 +555,7 @@ func TestOutputReportJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "1 day", parsedReport.Period)
 	assert.Equal(t, 2, parsedReport.Summary.TotalDrifts)
+	assert.Contains(t, output, `"drifts": [`)
 }
 
 func TestOutputReportTable(t *testing.T) {
@@ -460,12 +613,43 @@ func TestOutputReportTable(t *testing.T) {
 	assert.Contains(t, output, "schema_change")
 }
 
+func TestOutputReportTableGrouped(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	drifts := []*storage.Drift{
+		{ID: 1, EndpointID: "api-1", DriftType: "field_removed", Severity: "high", Description: "Field removed"},
+		{ID: 2, EndpointID: "api-1", DriftType: "field_added", Severity: "low", Description: "Field added"},
+	}
+	report := &DriftReport{
+		Period:  "1 day",
+		Summary: DriftSummary{TotalDrifts: 2},
+		GroupBy: "type",
+		Groups:  groupDrifts(drifts, "type"),
+	}
+
+	outputReportTable(report)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	output := buf.String()
+
+	assert.NotContains(t, output, "RECENT DRIFTS")
+	assert.Contains(t, output, "DRIFTS BY TYPE")
+	assert.Contains(t, output, "field_added: 1 drifts (0 severe)")
+	assert.Contains(t, output, "field_removed: 1 drifts (1 severe)")
+}
+
 func TestGenerateStatusSummary(t *testing.T) {
 	endpoints := []EndpointStatus{
+		{Status: "healthy", DriftsBySeverity: DriftSeverityCounts{Low: 2}},
 		{Status: "healthy"},
-		{Status: "healthy"},
-		{Status: "unhealthy"},
-		{Status: "unknown"},
+		{Status: "unhealthy", DriftsBySeverity: DriftSeverityCounts{Critical: 2, High: 1}},
+		{Status: "unknown", DriftsBySeverity: DriftSeverityCounts{Medium: 1, Low: 1}},
 	}
 
 	summary := generateStatusSummary(endpoints)
@@ -474,6 +658,21 @@ func TestGenerateStatusSummary(t *testing.T) {
 	assert.Equal(t, 2, summary.HealthyEndpoints)
 	assert.Equal(t, 1, summary.UnhealthyEndpoints)
 	assert.Equal(t, 1, summary.UnknownEndpoints)
+	assert.Equal(t, DriftSeverityCounts{Critical: 2, High: 1, Medium: 1, Low: 3}, summary.RecentDrifts)
+}
+
+func TestCountDriftsBySeverity(t *testing.T) {
+	drifts := []*storage.Drift{
+		{Severity: "critical"},
+		{Severity: "critical"},
+		{Severity: "high"},
+		{Severity: "high", Acknowledged: true},
+		{Severity: "low"},
+		{Severity: "unknown"},
+	}
+
+	assert.Equal(t, DriftSeverityCounts{Critical: 2, High: 1, Low: 1}, countDriftsBySeverity(drifts))
+	assert.Equal(t, DriftSeverityCounts{}, countDriftsBySeverity(nil))
 }
 
 func TestOutputStatusTable(t *testing.T) {
@@ -490,6 +689,7 @@ func TestOutputStatusTable(t *testing.T) {
 			HealthyEndpoints:   1,
 			UnhealthyEndpoints: 1,
 			UnknownEndpoints:   0,
+			RecentDrifts:       DriftSeverityCounts{Critical: 2, High: 1},
 		},
 		Endpoints: []EndpointStatus{
 			{
@@ -502,6 +702,7 @@ func TestOutputStatusTable(t *testing.T) {
 				SuccessRate:      95.5,
 				RecentDrifts:     2,
 				Enabled:          true,
+				LastTiming:       &TimingBreakdown{DNSMs: 3, ConnectMs: 12, TLSMs: 25, TTFBMs: 104},
 			},
 			{
 				ID:               "api-2",
@@ -531,6 +732,7 @@ func TestOutputStatusTable(t *testing.T) {
 	assert.Contains(t, output, "DriftWatch Status Report")
 	assert.Contains(t, output, "Total Endpoints: 2")
 	assert.Contains(t, output, "Healthy: 1 | Unhealthy: 1 | Unknown: 0")
+	assert.Contains(t, output, "Unacknowledged drifts (last 7 days): Critical: 2 | High: 1 | Medium: 0 | Low: 0")
 	assert.Contains(t, output, "ENDPOINT STATUS")
 	assert.Contains(t, output, "api-1")
 	assert.Contains(t, output, "api-2")
@@ -542,6 +744,8 @@ func TestOutputStatusTable(t *testing.T) {
 	assert.Contains(t, output, "5000ms")
 	assert.Contains(t, output, "95.5%")
 	assert.Contains(t, output, "45.0%")
+	assert.Contains(t, output, "TIMING BREAKDOWN (last check)")
+	assert.Regexp(t, `api-1\s+3ms\s+12ms\s+25ms\s+104ms`, output)
 }
 
 func TestExportDriftsCSV(t *testing.T) {
@@ -7,7 +7,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -77,7 +76,7 @@ Examples:
 
 		// Connect to current database to create backup if needed
 		if !noBackup {
-			db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+			db, err := openStorage(cfg)
 			if err != nil {
 				return fmt.Errorf("failed to connect to current database: %w", err)
 			}
@@ -131,7 +130,7 @@ Examples:
 		}
 
 		// Verify the restored database
-		db, err := storage.NewStorage(cfg.Global.DatabaseURL)
+		db, err := openStorage(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to connect to restored database: %w", err)
 		}
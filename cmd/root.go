@@ -9,14 +9,16 @@ import (
 	"github.com/k0ns0l/driftwatch/internal/config"
 	"github.com/k0ns0l/driftwatch/internal/errors"
 	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/k0ns0l/driftwatch/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
-	logger  *logging.Logger
+	cfgFile      string
+	cfgFromStdin bool
+	cfg          *config.Config
+	logger       *logging.Logger
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -54,7 +56,9 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .driftwatch.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&cfgFromStdin, "config-from-stdin", false, "read YAML or JSON configuration from standard input")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also disabled by the NO_COLOR environment variable)")
 	rootCmd.PersistentFlags().StringP("output", "o", "table", "output format (table, json, yaml)")
 
 	rootCmd.Flags().BoolP("version", "", false, "show version information")
@@ -84,7 +88,16 @@ func initConfig() {
 	}
 
 	// Load configuration
-	cfg, err = config.LoadConfig(cfgFile)
+	if cfgFromStdin && cfgFile != "" {
+		fmt.Fprintf(os.Stderr, "Error loading config: --config and --config-from-stdin cannot be used together\n")
+		os.Exit(1)
+	}
+
+	if cfgFromStdin {
+		cfg, err = config.LoadConfigFromReader(os.Stdin)
+	} else {
+		cfg, err = config.LoadConfig(cfgFile)
+	}
 	if err != nil {
 		if dwe, ok := err.(*errors.DriftWatchError); ok {
 			logger.LogError(context.TODO(), dwe, "Failed to load configuration")
@@ -101,8 +114,12 @@ func initConfig() {
 	// Print config file location if verbose
 	if rootCmd.Flag("verbose").Changed {
 		configPath := config.GetConfigFilePath(cfgFile)
-		if config.ConfigExists(configPath) {
+		if cfgFromStdin {
+			logger.Info("Using configuration from standard input")
+		} else if config.ConfigExists(configPath) {
 			logger.Info("Using config file", "path", configPath)
+		} else if os.Getenv(config.ConfigEnvVar) != "" {
+			logger.Info("Using configuration from environment", "variable", config.ConfigEnvVar)
 		} else {
 			logger.Info("Using default configuration (no config file found)")
 		}
@@ -114,6 +131,17 @@ func GetConfig() *config.Config {
 	return cfg
 }
 
+// openStorage opens the database configured in cfg
+func openStorage(cfg *config.Config) (storage.Storage, error) {
+	options := storage.SQLiteOptions{
+		BusyTimeout: cfg.Global.DatabaseBusyTimeout,
+	}
+	if cfg.Global.BodyStorage == config.BodyStorageFile {
+		options.BodyDir = cfg.Global.BodyStorageDir
+	}
+	return storage.NewStorageWithOptions(cfg.Global.DatabaseURL, options)
+}
+
 // GetLogger returns the initialized logger
 func GetLogger() *logging.Logger {
 	if logger == nil {
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/security"
+	"github.com/k0ns0l/driftwatch/internal/version"
+)
+
+// SARIF 2.1.0 identifiers
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifFingerprintKey names the fingerprint code scanning uses to recognize a
+// change across runs
+const sarifFingerprintKey = "driftwatchChange/v1"
+
+// SARIFLog is the root of a SARIF 2.1.0 report
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of DriftWatch in a SARIF report
+type SARIFRun struct {
+	Tool        SARIFTool         `json:"tool"`
+	Invocations []SARIFInvocation `json:"invocations"`
+	Results     []SARIFResult     `json:"results"`
+}
+
+// SARIFTool describes DriftWatch and the rules its results refer to
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a change type
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFInvocation reports whether every endpoint could be checked, with a
+// notification for each endpoint that could not
+type SARIFInvocation struct {
+	Notifications       []SARIFNotification `json:"toolExecutionNotifications,omitempty"`
+	ExecutionSuccessful bool                `json:"executionSuccessful"`
+}
+
+// SARIFNotification reports an endpoint that could not be checked
+type SARIFNotification struct {
+	Level   string       `json:"level"`
+	Message SARIFMessage `json:"message"`
+}
+
+// SARIFResult is a detected change
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          SARIFProperties   `json:"properties"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation locates a change: logically by the changed field of the
+// endpoint, and physically by the endpoint's definition in the config file
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations"`
+}
+
+// SARIFPhysicalLocation is a location in a file
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is a file, relative to the working directory
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a line in a file
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFLogicalLocation is a field of an endpoint's response
+type SARIFLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIFProperties carries the details of a change SARIF has no field for
+type SARIFProperties struct {
+	Endpoint   string `json:"endpoint"`
+	Method     string `json:"method"`
+	URL        string `json:"url"`
+	Severity   string `json:"severity"`
+	OldValue   string `json:"oldValue,omitempty"`
+	NewValue   string `json:"newValue,omitempty"`
+	Mitigation string `json:"mitigation,omitempty"`
+	Breaking   bool   `json:"breaking"`
+}
+
+// marshalCISARIF renders CI results as a SARIF 2.1.0 report
+func marshalCISARIF(cfg *config.Config, result *CIResult) ([]byte, error) {
+	configPath, endpointLines := ciConfigLocations(cfg, result)
+	output, err := json.MarshalIndent(convertToSARIF(result, configPath, endpointLines), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(output, '\n'), nil
+}
+
+// ciConfigLocations returns the location of the config file, as SARIF reports
+// locate files, and the line each checked endpoint is defined on. Variants are
+// located at the endpoint they belong to. Both are empty when the config was
+// read from stdin.
+func ciConfigLocations(cfg *config.Config, result *CIResult) (string, map[string]int) {
+	if cfgFromStdin {
+		return "", nil
+	}
+
+	configPath, err := filepath.Abs(config.GetConfigFilePath(cfgFile))
+	if err != nil {
+		return "", nil
+	}
+	data, err := security.SafeReadFile(configPath, filepath.Dir(configPath))
+	if err != nil {
+		return "", nil
+	}
+
+	definitions := endpointDefinitionLines(data)
+	lines := make(map[string]int, len(result.Endpoints))
+	for _, ep := range result.Endpoints {
+		if line, ok := definitions[cfg.BaseEndpointID(ep.ID)]; ok {
+			lines[ep.ID] = line
+		}
+	}
+	return sarifArtifactURI(configPath), lines
+}
+
+// sarifArtifactURI returns the URI of a file in a SARIF report: its path relative
+// to the working directory, or a file URI when it is outside it. Code scanning
+// only links results to files in the repository, so results located in a config
+// outside the working directory keep their location but are not linked.
+func sarifArtifactURI(path string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		rel, err := filepath.Rel(cwd, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	uriPath := filepath.ToSlash(path)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath
+	}
+	return (&url.URL{Scheme: "file", Path: uriPath}).String()
+}
+
+// endpointIDLine matches the line an endpoint's id is set on in a YAML config
+var endpointIDLine = regexp.MustCompile(`^\s*(?:-\s+)?id:\s*["']?([^"'\s#]+)`)
+
+// endpointDefinitionLines returns the line each endpoint id is first set on
+func endpointDefinitionLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		match := endpointIDLine.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		if _, ok := lines[match[1]]; !ok {
+			lines[match[1]] = line
+		}
+	}
+	return lines
+}
+
+// convertToSARIF converts CI results to a SARIF report. Each change is a result
+// of the rule for its change type, with a level for its severity. Results are
+// located at the changed field and, when configPath is set, at the endpoint's
+// definition in the config file, which code scanning requires.
+func convertToSARIF(result *CIResult, configPath string, endpointLines map[string]int) *SARIFLog {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           "DriftWatch",
+			Version:        version.Version,
+			InformationURI: "https://github.com/k0ns0l/driftwatch",
+			Rules:          []SARIFRule{},
+		}},
+		Results: []SARIFResult{},
+	}
+
+	invocation := SARIFInvocation{ExecutionSuccessful: true}
+	ruleTypes := make(map[string]bool)
+
+	for _, ep := range result.Endpoints {
+		if ep.Error != "" {
+			invocation.ExecutionSuccessful = false
+			invocation.Notifications = append(invocation.Notifications, SARIFNotification{
+				Level:   "error",
+				Message: SARIFMessage{Text: fmt.Sprintf("Endpoint %s (%s %s) failed: %s", ep.ID, ep.Method, ep.URL, ep.Error)},
+			})
+			continue
+		}
+
+		for _, change := range ep.Changes {
+			ruleTypes[change.Type] = true
+			run.Results = append(run.Results, sarifResult(ep, change, configPath, endpointLines))
+		}
+	}
+
+	types := make([]string, 0, len(ruleTypes))
+	for changeType := range ruleTypes {
+		types = append(types, changeType)
+	}
+	sort.Strings(types)
+	for _, changeType := range types {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule(changeType))
+	}
+
+	run.Invocations = []SARIFInvocation{invocation}
+	return &SARIFLog{Schema: sarifSchema, Version: sarifVersion, Runs: []SARIFRun{run}}
+}
+
+// sarifRule returns the rule for a change type, such as field_removed
+func sarifRule(changeType string) SARIFRule {
+	words := strings.Split(changeType, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	description := strings.ReplaceAll(changeType, "_", " ")
+	if description != "" {
+		description = strings.ToUpper(description[:1]) + description[1:]
+	}
+
+	return SARIFRule{
+		ID:               changeType,
+		Name:             strings.Join(words, ""),
+		ShortDescription: SARIFMessage{Text: description},
+	}
+}
+
+// sarifResult converts a change of an endpoint to a SARIF result
+func sarifResult(ep CIEndpointResult, change CIChange, configPath string, endpointLines map[string]int) SARIFResult {
+	message := fmt.Sprintf("%s: %s", ep.ID, change.Description)
+	if change.Mitigation != "" {
+		message += " Mitigation: " + change.Mitigation
+	}
+
+	location := SARIFLocation{
+		LogicalLocations: []SARIFLogicalLocation{{
+			Name:               change.Path,
+			FullyQualifiedName: ep.ID + ":" + change.Path,
+			Kind:               "member",
+		}},
+	}
+	if configPath != "" {
+		location.PhysicalLocation = &SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: configPath},
+		}
+		if line, ok := endpointLines[ep.ID]; ok {
+			location.PhysicalLocation.Region = &SARIFRegion{StartLine: line}
+		}
+	}
+
+	fingerprint := sha256.Sum256([]byte(ep.ID + "\x00" + change.Type + "\x00" + change.Path))
+
+	return SARIFResult{
+		RuleID:              change.Type,
+		Level:               sarifLevel(change.Severity),
+		Message:             SARIFMessage{Text: message},
+		Locations:           []SARIFLocation{location},
+		PartialFingerprints: map[string]string{sarifFingerprintKey: hex.EncodeToString(fingerprint[:])},
+		Properties: SARIFProperties{
+			Endpoint:   ep.ID,
+			Method:     ep.Method,
+			URL:        ep.URL,
+			Severity:   change.Severity,
+			OldValue:   change.OldValue,
+			NewValue:   change.NewValue,
+			Mitigation: change.Mitigation,
+			Breaking:   change.Breaking,
+		},
+	}
+}
+
+// sarifLevel maps a change severity to a SARIF level: critical and high
+// changes are errors, medium changes warnings and low changes notes
+func sarifLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
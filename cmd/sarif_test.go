@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToSARIF(t *testing.T) {
+	result := &CIResult{
+		Endpoints: []CIEndpointResult{
+			{
+				ID:     "users-api",
+				Method: "GET",
+				URL:    "https://api.example.com/users",
+				Changes: []CIChange{
+					{
+						Type:        "field_removed",
+						Path:        "$.user.id",
+						Severity:    "critical",
+						Breaking:    true,
+						Description: "Field 'user.id' was removed",
+						OldValue:    "42",
+						Mitigation:  "Stop reading user.id",
+					},
+					{
+						Type:        "field_added",
+						Path:        "$.user.email",
+						Severity:    "low",
+						Description: "Field 'user.email' was added",
+					},
+				},
+			},
+			{
+				ID:     "orders-api",
+				Method: "GET",
+				URL:    "https://api.example.com/orders",
+				Error:  "connection timeout",
+			},
+		},
+	}
+
+	report := convertToSARIF(result, "driftwatch.yaml", map[string]int{"users-api": 7})
+
+	assert.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+	run := report.Runs[0]
+
+	assert.Equal(t, "DriftWatch", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, SARIFRule{ID: "field_added", Name: "FieldAdded", ShortDescription: SARIFMessage{Text: "Field added"}}, run.Tool.Driver.Rules[0])
+	assert.Equal(t, "field_removed", run.Tool.Driver.Rules[1].ID)
+
+	require.Len(t, run.Invocations, 1)
+	assert.False(t, run.Invocations[0].ExecutionSuccessful)
+	require.Len(t, run.Invocations[0].Notifications, 1)
+	assert.Contains(t, run.Invocations[0].Notifications[0].Message.Text, "orders-api")
+
+	require.Len(t, run.Results, 2)
+	removed := run.Results[0]
+	assert.Equal(t, "field_removed", removed.RuleID)
+	assert.Equal(t, "error", removed.Level)
+	assert.Equal(t, "users-api: Field 'user.id' was removed Mitigation: Stop reading user.id", removed.Message.Text)
+	require.Len(t, removed.Locations, 1)
+	assert.Equal(t, []SARIFLogicalLocation{{Name: "$.user.id", FullyQualifiedName: "users-api:$.user.id", Kind: "member"}}, removed.Locations[0].LogicalLocations)
+	assert.Equal(t, &SARIFPhysicalLocation{
+		ArtifactLocation: SARIFArtifactLocation{URI: "driftwatch.yaml"},
+		Region:           &SARIFRegion{StartLine: 7},
+	}, removed.Locations[0].PhysicalLocation)
+	assert.Len(t, removed.PartialFingerprints[sarifFingerprintKey], 64)
+	assert.True(t, removed.Properties.Breaking)
+	assert.Equal(t, "42", removed.Properties.OldValue)
+
+	added := run.Results[1]
+	assert.Equal(t, "note", added.Level)
+	assert.NotEqual(t, removed.PartialFingerprints, added.PartialFingerprints)
+}
+
+func TestConvertToSARIFWithoutConfigFile(t *testing.T) {
+	result := &CIResult{
+		Endpoints: []CIEndpointResult{{
+			ID:      "users-api",
+			Changes: []CIChange{{Type: "type_changed", Path: "$.count", Severity: "medium"}},
+		}},
+	}
+
+	report := convertToSARIF(result, "", nil)
+
+	require.Len(t, report.Runs[0].Results, 1)
+	result0 := report.Runs[0].Results[0]
+	assert.Equal(t, "warning", result0.Level)
+	assert.Nil(t, result0.Locations[0].PhysicalLocation)
+	assert.True(t, report.Runs[0].Invocations[0].ExecutionSuccessful)
+}
+
+func TestEndpointDefinitionLines(t *testing.T) {
+	data := []byte(`project:
+  name: example
+endpoints:
+  - id: users-api
+    url: https://api.example.com/users
+  - url: https://api.example.com/orders
+    id: "orders-api" # orders
+  -   id: 'users-api'
+`)
+
+	assert.Equal(t, map[string]int{"users-api": 4, "orders-api": 7}, endpointDefinitionLines(data))
+}
+
+func TestCIConfigLocations(t *testing.T) {
+	// The temporary directory is outside the working directory
+	configFile := filepath.Join(t.TempDir(), "driftwatch.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`endpoints:
+  - id: users-api
+    url: https://api.example.com/users
+  - id: billing
+    url: https://api.example.com/billing
+    variants:
+      - name: eu
+`), 0o600))
+
+	oldCfgFile := cfgFile
+	cfgFile = configFile
+	defer func() { cfgFile = oldCfgFile }()
+
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{
+		{ID: "users-api"},
+		{ID: "billing", Variants: []config.EndpointVariant{{Name: "eu"}}},
+	}}
+	result := &CIResult{Endpoints: []CIEndpointResult{{ID: "users-api"}, {ID: "billing-eu"}}}
+
+	configPath, lines := ciConfigLocations(cfg, result)
+	assert.Equal(t, "file://"+filepath.ToSlash(configFile), configPath)
+	assert.Equal(t, map[string]int{"users-api": 2, "billing-eu": 4}, lines)
+}
+
+func TestSARIFArtifactURI(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	assert.Equal(t, "configs/driftwatch.yaml", sarifArtifactURI(filepath.Join(cwd, "configs", "driftwatch.yaml")))
+	assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(filepath.Dir(cwd), "driftwatch.yaml")),
+		sarifArtifactURI(filepath.Join(filepath.Dir(cwd), "driftwatch.yaml")))
+}
+
+func TestSARIFLevel(t *testing.T) {
+	assert.Equal(t, "error", sarifLevel("critical"))
+	assert.Equal(t, "error", sarifLevel("HIGH"))
+	assert.Equal(t, "warning", sarifLevel("medium"))
+	assert.Equal(t, "note", sarifLevel("low"))
+	assert.Equal(t, "note", sarifLevel(""))
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Search stored drifts by description or field path",
+	Long: `Search every stored drift for those whose description or field path contains
+the given text, ignoring case. When the text has several words, a drift must
+contain each of them. Matches are listed newest first.
+
+Examples:
+  driftwatch search price                     # Drifts mentioning price
+  driftwatch search "items price" --limit 10  # Drifts mentioning both words
+  driftwatch search '$.data.user' -o json     # Output as JSON`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "limit", err)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output", err)
+		}
+
+		if limit < 0 {
+			return fmt.Errorf("limit must not be negative")
+		}
+		if outputFormat != "table" && outputFormat != "json" {
+			return fmt.Errorf("unsupported output format: %s (supported: table, json)", outputFormat)
+		}
+
+		text := strings.Join(args, " ")
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("search text must not be empty")
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		drifts, err := db.SearchDrifts(text, limit)
+		if err != nil {
+			return fmt.Errorf("failed to search drifts: %w", err)
+		}
+
+		if outputFormat == "json" {
+			if drifts == nil {
+				drifts = []*storage.Drift{}
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(drifts)
+		}
+
+		outputSearchTable(text, drifts)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().Int("limit", 50, "most drifts to list (0 lists every match)")
+	searchCmd.Flags().StringP("output", "o", "table", "output format (table, json)")
+}
+
+// outputSearchTable prints the drifts matching a search as a table
+func outputSearchTable(text string, drifts []*storage.Drift) {
+	if len(drifts) == 0 {
+		fmt.Printf("No drifts match %q.\n", text)
+		return
+	}
+
+	fmt.Printf("%d drifts matching %q\n\n", len(drifts), text)
+	fmt.Printf("%-6s %-19s %-20s %-10s %-25s %s\n", "ID", "DETECTED", "ENDPOINT", "SEVERITY", "FIELD", "DESCRIPTION")
+	fmt.Println(strings.Repeat("-", 120))
+
+	for _, drift := range drifts {
+		fmt.Printf("%-6d %-19s %-20s %-10s %-25s %s\n",
+			drift.ID,
+			drift.DetectedAt.Format("2006-01-02 15:04:05"),
+			truncateString(drift.EndpointID, 20),
+			drift.Severity,
+			truncateString(drift.FieldPath, 25),
+			drift.Description)
+	}
+}
@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// serveAPICmd represents the serve-api command
+var serveAPICmd = &cobra.Command{
+	Use:   "serve-api",
+	Short: "Serve monitoring data over a read-only HTTP API",
+	Long: `Start an HTTP server exposing endpoints, endpoint health, drifts and
+monitoring runs from the database as JSON, for dashboards and other tools.
+
+Routes:
+  GET /endpoints                  Monitored endpoints
+  GET /endpoints/{id}/health      Health of an endpoint, as shown by 'health'
+  GET /drifts                     Drifts, newest first, filtered by the query
+                                  parameters severity, endpoint and since (an
+                                  RFC 3339 time or a period such as 24h or 7d)
+  GET /runs/{id}                  A monitoring run, including its response
+
+Requests must be authenticated when the api section of the configuration sets
+a token (sent as "Authorization: Bearer <token>") or a username and password
+(sent as basic auth). Without credentials the API is open to anyone who can
+reach the address.
+
+Examples:
+  driftwatch serve-api                           # Serve on localhost:9090
+  driftwatch serve-api --addr :9090              # Serve on all interfaces`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "addr", err)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		server := &http.Server{
+			Addr:              addr,
+			Handler:           newAPIHandler(db, cfg.API),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		serverErr := make(chan error, 1)
+		go func() {
+			serverErr <- server.ListenAndServe()
+		}()
+
+		if cfg.API.Token == "" && cfg.API.Username == "" {
+			fmt.Fprintln(os.Stderr, "Warning: no api credentials configured; the API is not authenticated")
+		}
+		fmt.Printf("Serving the DriftWatch API on http://%s\n", server.Addr)
+		fmt.Println("Press Ctrl+C to stop")
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+		select {
+		case err := <-serverErr:
+			if !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("API server failed: %w", err)
+			}
+		case sig := <-sigChan:
+			fmt.Printf("\nReceived signal %v, stopping API server...\n", sig)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error stopping API server: %w", err)
+		}
+
+		fmt.Println("API server stopped")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveAPICmd)
+
+	serveAPICmd.Flags().String("addr", "localhost:9090", "address to listen on")
+}
+
+// APIEndpoint is an endpoint as listed by the API. The stored endpoint
+// configuration is left out as it may contain credentials.
+type APIEndpoint struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	SpecFile  string    `json:"spec_file,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// apiHandler serves the read-only API
+type apiHandler struct {
+	db   storage.Storage
+	auth config.APIConfig
+}
+
+// newAPIHandler creates the API handler, requiring the configured credentials
+func newAPIHandler(db storage.Storage, auth config.APIConfig) http.Handler {
+	h := &apiHandler{db: db, auth: auth}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /endpoints", h.listEndpoints)
+	mux.HandleFunc("GET /endpoints/{id}/health", h.endpointHealth)
+	mux.HandleFunc("GET /drifts", h.listDrifts)
+	mux.HandleFunc("GET /runs/{id}", h.getRun)
+
+	return h.requireAuth(mux)
+}
+
+// requireAuth rejects requests without the configured token or credentials
+func (h *apiHandler) requireAuth(next http.Handler) http.Handler {
+	if h.auth.Token == "" && h.auth.Username == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.authorized(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if h.auth.Username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="driftwatch"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		writeAPIError(w, http.StatusUnauthorized, "authentication required")
+	})
+}
+
+// authorized reports whether a request carries the configured token or credentials
+func (h *apiHandler) authorized(r *http.Request) bool {
+	if h.auth.Token != "" {
+		if token, ok := bearerToken(r); ok && secureEqual(token, h.auth.Token) {
+			return true
+		}
+	}
+
+	if h.auth.Username != "" {
+		if username, password, ok := r.BasicAuth(); ok &&
+			secureEqual(username, h.auth.Username) && secureEqual(password, h.auth.Password) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bearerToken returns the token of a bearer Authorization header
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return header[len(prefix):], true
+}
+
+// secureEqual compares secrets in constant time
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// listEndpoints serves GET /endpoints
+func (h *apiHandler) listEndpoints(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := h.db.ListEndpoints()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list endpoints: %v", err))
+		return
+	}
+
+	result := make([]APIEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result = append(result, APIEndpoint{
+			ID:        endpoint.ID,
+			URL:       endpoint.URL,
+			Method:    endpoint.Method,
+			SpecFile:  endpoint.SpecFile,
+			CreatedAt: endpoint.CreatedAt,
+			UpdatedAt: endpoint.UpdatedAt,
+		})
+	}
+
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+// endpointHealth serves GET /endpoints/{id}/health
+func (h *apiHandler) endpointHealth(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	report := generateStatusReport(h.db, []string{id}, false)
+	if len(report.Endpoints) == 0 {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("endpoint not found: %s", id))
+		return
+	}
+
+	writeAPIJSON(w, http.StatusOK, report.Endpoints[0])
+}
+
+// listDrifts serves GET /drifts
+func (h *apiHandler) listDrifts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := storage.DriftFilters{
+		EndpointID: query.Get("endpoint"),
+		Severity:   query.Get("severity"),
+	}
+
+	switch filters.Severity {
+	case "", "low", "medium", "high", "critical":
+	default:
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid severity %q (supported: low, medium, high, critical)", filters.Severity))
+		return
+	}
+
+	if since := query.Get("since"); since != "" {
+		startTime, err := parseSince(since, time.Now())
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filters.StartTime = startTime
+	}
+
+	drifts, err := h.db.GetDrifts(filters)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get drifts: %v", err))
+		return
+	}
+	if drifts == nil {
+		drifts = []*storage.Drift{}
+	}
+
+	writeAPIJSON(w, http.StatusOK, drifts)
+}
+
+// getRun serves GET /runs/{id}
+func (h *apiHandler) getRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid run ID: %s", r.PathValue("id")))
+		return
+	}
+
+	run, err := h.db.GetMonitoringRun(id)
+	if errors.Is(err, storage.ErrMonitoringRunNotFound) {
+		writeAPIError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get run: %v", err))
+		return
+	}
+
+	// Cookies and credentials are not served; the map may be shared with storage
+	headers := make(map[string]string, len(run.ResponseHeaders))
+	for name, value := range run.ResponseHeaders {
+		headers[name] = drift.RedactHeaderValue(name, value)
+	}
+	run.ResponseHeaders = headers
+
+	writeAPIJSON(w, http.StatusOK, run)
+}
+
+// parseSince parses the since filter: an RFC 3339 time or a period before now
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+
+	period, err := parsePeriod(since)
+	if err != nil || period <= 0 {
+		return time.Time{}, fmt.Errorf("invalid since %q: use an RFC 3339 time or a period such as 24h or 7d", since)
+	}
+
+	return now.Add(-period), nil
+}
+
+// writeAPIJSON writes a JSON response body with the given status code
+func writeAPIJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
+}
+
+// writeAPIError writes a JSON error response
+func writeAPIError(w http.ResponseWriter, statusCode int, message string) {
+	writeAPIJSON(w, statusCode, map[string]string{"error": message})
+}
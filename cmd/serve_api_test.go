@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIHandler(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	require.NoError(t, db.SaveEndpoint(&storage.Endpoint{
+		ID:     "users",
+		URL:    "https://api.example.com/users",
+		Method: "GET",
+		Config: `{"headers": {"Authorization": "Bearer secret"}}`,
+	}))
+	require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "users",
+		Timestamp:      time.Now(),
+		ResponseStatus: 200,
+		ResponseBody:   `{"users": []}`,
+		ResponseHeaders: map[string]string{
+			"Content-Type":  "application/json",
+			"Set-Cookie":    "session=secret-session; HttpOnly",
+			"Authorization": "Bearer secret-token",
+		},
+	}))
+	require.NoError(t, db.SaveDrift(&storage.Drift{EndpointID: "users", DriftType: "field_removed", Severity: "high", DetectedAt: time.Now()}))
+	require.NoError(t, db.SaveDrift(&storage.Drift{EndpointID: "users", DriftType: "field_added", Severity: "low", DetectedAt: time.Now().Add(-48 * time.Hour)}))
+
+	handler := newAPIHandler(db, config.APIConfig{Token: "token", Username: "admin", Password: "pass"})
+
+	get := func(path string, authenticate func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authenticate != nil {
+			authenticate(req)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	withToken := func(req *http.Request) { req.Header.Set("Authorization", "Bearer token") }
+
+	t.Run("requires authentication", func(t *testing.T) {
+		rec := get("/endpoints", nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Basic")
+
+		rec = get("/endpoints", func(req *http.Request) { req.Header.Set("Authorization", "Bearer wrong") })
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec = get("/endpoints", func(req *http.Request) { req.SetBasicAuth("admin", "pass") })
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("lists endpoints without their configuration", func(t *testing.T) {
+		rec := get("/endpoints", withToken)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "secret")
+
+		var endpoints []APIEndpoint
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &endpoints))
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, "users", endpoints[0].ID)
+	})
+
+	t.Run("endpoint health", func(t *testing.T) {
+		rec := get("/endpoints/users/health", withToken)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var status EndpointStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		assert.Equal(t, "healthy", status.Status)
+		assert.Equal(t, 2, status.RecentDrifts)
+
+		rec = get("/endpoints/missing/health", withToken)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("filters drifts", func(t *testing.T) {
+		var drifts []*storage.Drift
+
+		rec := get("/drifts?endpoint=users", withToken)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &drifts))
+		assert.Len(t, drifts, 2)
+
+		rec = get("/drifts?severity=high", withToken)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &drifts))
+		require.Len(t, drifts, 1)
+		assert.Equal(t, "field_removed", drifts[0].DriftType)
+
+		rec = get("/drifts?since=24h", withToken)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &drifts))
+		assert.Len(t, drifts, 1)
+
+		rec = get("/drifts?endpoint=other", withToken)
+		assert.JSONEq(t, "[]", rec.Body.String())
+
+		rec = get("/drifts?severity=urgent", withToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = get("/drifts?since=yesterday", withToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("gets runs", func(t *testing.T) {
+		rec := get("/runs/1", withToken)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var run storage.MonitoringRun
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &run))
+		assert.Equal(t, "users", run.EndpointID)
+		assert.Equal(t, "application/json", run.ResponseHeaders["Content-Type"])
+		assert.NotContains(t, rec.Body.String(), "secret")
+
+		// Stored headers are not redacted
+		stored, err := db.GetMonitoringRun(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer secret-token", stored.ResponseHeaders["Authorization"])
+
+		assert.Equal(t, http.StatusNotFound, get("/runs/99", withToken).Code)
+		assert.Equal(t, http.StatusBadRequest, get("/runs/abc", withToken).Code)
+	})
+}
+
+// failingRunStorage fails to read monitoring runs
+type failingRunStorage struct {
+	storage.Storage
+}
+
+func (failingRunStorage) GetMonitoringRun(int64) (*storage.MonitoringRun, error) {
+	return nil, fmt.Errorf("database is locked")
+}
+
+func TestAPIHandlerRunStorageError(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	handler := newAPIHandler(failingRunStorage{Storage: db}, config.APIConfig{Token: "token"})
+	req := httptest.NewRequest(http.MethodGet, "/runs/1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// timelineValueWidth is the most characters of a before or after value shown in
+// the timeline table
+const timelineValueWidth = 40
+
+// timelineCmd represents the timeline command
+var timelineCmd = &cobra.Command{
+	Use:   "timeline <endpoint-id>",
+	Short: "Show every drift of a single field over time",
+	Long: `Show the lifecycle of a single field: every drift recorded at its path,
+oldest first, with the value before and after each change.
+
+The path must match the field path of the stored drifts exactly, as shown by
+'driftwatch report' or 'driftwatch search'. Drifts below the path, such as those
+of its nested fields, are not included. To follow the value itself across every
+stored response, use 'driftwatch query'.
+
+Examples:
+  driftwatch timeline my-api --path '$.data.price'                # Last 30 days
+  driftwatch timeline my-api --path '$.data.price' --period 7d    # Last 7 days
+  driftwatch timeline my-api --path '$.items[0].status' -o json   # Output as JSON`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		endpointID := args[0]
+
+		path, err := cmd.Flags().GetString("path")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "path", err)
+		}
+		period, err := cmd.Flags().GetString("period")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "period", err)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output", err)
+		}
+
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return fmt.Errorf("--path must be set to the field path to show, such as '$.data.price'")
+		}
+		if outputFormat != "table" && outputFormat != "json" {
+			return fmt.Errorf("unsupported output format: %s (supported: table, json)", outputFormat)
+		}
+
+		duration, err := parsePeriod(period)
+		if err != nil {
+			return fmt.Errorf("invalid period: %w", err)
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		if _, err := db.GetEndpoint(endpointID); err != nil {
+			return fmt.Errorf("failed to get endpoint %s: %w", endpointID, err)
+		}
+
+		drifts, err := db.GetDrifts(storage.DriftFilters{
+			EndpointID: endpointID,
+			FieldPath:  path,
+			StartTime:  time.Now().Add(-duration),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get drifts: %w", err)
+		}
+
+		drifts = driftTimeline(drifts)
+
+		if outputFormat == "json" {
+			return outputTimelineJSON(os.Stdout, drifts)
+		}
+
+		outputTimelineTable(os.Stdout, endpointID, path, formatPeriod(duration), drifts)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timelineCmd)
+
+	timelineCmd.Flags().String("path", "", "field path of the drifts, such as '$.data.price'")
+	timelineCmd.Flags().StringP("period", "p", "30d", "time period to show (24h, 7d, 30d)")
+	timelineCmd.Flags().StringP("output", "o", "table", "output format (table, json)")
+}
+
+// driftTimeline returns the drifts of a field oldest first; drifts detected at
+// the same time keep the order in which they were saved
+func driftTimeline(drifts []*storage.Drift) []*storage.Drift {
+	timeline := make([]*storage.Drift, len(drifts))
+	copy(timeline, drifts)
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		if !timeline[i].DetectedAt.Equal(timeline[j].DetectedAt) {
+			return timeline[i].DetectedAt.Before(timeline[j].DetectedAt)
+		}
+		return timeline[i].ID < timeline[j].ID
+	})
+	return timeline
+}
+
+// outputTimelineJSON prints the drifts of a field as a JSON array, which is
+// empty rather than null when there are none
+func outputTimelineJSON(w io.Writer, drifts []*storage.Drift) error {
+	if drifts == nil {
+		drifts = []*storage.Drift{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(drifts)
+}
+
+// outputTimelineTable prints the drifts of a field as a chronological table
+func outputTimelineTable(w io.Writer, endpointID, path, period string, drifts []*storage.Drift) {
+	fmt.Fprintf(w, "%s on %s over the last %s\n\n", path, endpointID, period)
+
+	if len(drifts) == 0 {
+		fmt.Fprintln(w, "No drifts found at this path.")
+		return
+	}
+
+	fmt.Fprintf(w, "%-19s %-10s %-22s %s\n", "DETECTED", "SEVERITY", "TYPE", "BEFORE -> AFTER")
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, drift := range drifts {
+		fmt.Fprintf(w, "%-19s %-10s %-22s %s -> %s\n",
+			drift.DetectedAt.Format("2006-01-02 15:04:05"),
+			drift.Severity,
+			truncateString(drift.DriftType, 22),
+			formatTimelineValue(drift.BeforeValue),
+			formatTimelineValue(drift.AfterValue))
+	}
+
+	fmt.Fprintf(w, "\n%d drifts\n", len(drifts))
+}
+
+// formatTimelineValue renders a before or after value; an empty value means the
+// field was absent
+func formatTimelineValue(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return truncateString(value, timelineValueWidth)
+}
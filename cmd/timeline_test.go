@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftTimeline(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Newest first, as returned by storage
+	drifts := []*storage.Drift{
+		{ID: 3, DetectedAt: base.Add(2 * time.Hour), DriftType: "field_removed", Severity: "critical", BeforeValue: `"12.00"`},
+		{ID: 2, DetectedAt: base.Add(time.Hour), DriftType: "type_change", Severity: "high", BeforeValue: "12", AfterValue: `"12.00"`},
+		{ID: 1, DetectedAt: base, DriftType: "value_change", Severity: "low", BeforeValue: "10", AfterValue: "12"},
+	}
+
+	timeline := driftTimeline(drifts)
+	require.Len(t, timeline, 3)
+	assert.Equal(t, int64(1), timeline[0].ID)
+	assert.Equal(t, int64(2), timeline[1].ID)
+	assert.Equal(t, int64(3), timeline[2].ID)
+	assert.Equal(t, int64(3), drifts[0].ID, "the drifts passed in are not reordered")
+
+	var buf bytes.Buffer
+	outputTimelineTable(&buf, "shop", "$.data.price", "30 days", timeline)
+	output := buf.String()
+
+	assert.Contains(t, output, "$.data.price on shop over the last 30 days")
+	assert.Contains(t, output, "2024-03-10 12:00:00 low        value_change           10 -> 12\n")
+	assert.Contains(t, output, "2024-03-10 14:00:00 critical   field_removed          \"12.00\" -> <none>\n")
+	assert.Contains(t, output, "3 drifts")
+
+	buf.Reset()
+	outputTimelineTable(&buf, "shop", "$.data.price", "30 days", nil)
+	assert.Contains(t, buf.String(), "No drifts found at this path.")
+
+	buf.Reset()
+	require.NoError(t, outputTimelineJSON(&buf, nil))
+	assert.Equal(t, "[]\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, outputTimelineJSON(&buf, driftTimeline(nil)))
+	assert.Equal(t, "[]\n", buf.String())
+}
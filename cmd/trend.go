@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// trendCmd represents the trend command
+var trendCmd = &cobra.Command{
+	Use:   "trend <id>",
+	Short: "Analyze how often an endpoint's responses change over time",
+	Long: `Analyze the stored history of an endpoint: how often its responses change
+between runs, how stable it is and whether its response times are improving or
+degrading.
+
+Long histories are downsampled so the analysis stays fast: at most --max-samples
+evenly spaced successful runs are loaded from the database and compared, and the
+change frequency is then that between consecutive samples. Use --max-samples 0 to
+analyze every run.
+
+Examples:
+  driftwatch trend users-api
+  driftwatch trend users-api --period 30d --max-samples 200
+  driftwatch trend users-api --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		periodStr, err := cmd.Flags().GetString("period")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "period", err)
+		}
+		maxSamples, err := cmd.Flags().GetInt("max-samples")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "max-samples", err)
+		}
+		outputFormat, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "output", err)
+		}
+
+		period, err := parsePeriod(periodStr)
+		if err != nil {
+			return fmt.Errorf("invalid period: %w", err)
+		}
+		if maxSamples != 0 && maxSamples < 2 {
+			return fmt.Errorf("max samples must be at least 2, or 0 to analyze every run")
+		}
+		if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" {
+			return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml)", outputFormat)
+		}
+
+		endpointConfig, err := cfg.GetEndpoint(args[0])
+		if err != nil {
+			return err
+		}
+
+		db, err := openStorage(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		// Consecutive runs of a stable endpoint are compared the same way
+		var cache *drift.ComparisonCache
+		if cfg.Global.ComparisonCacheSize > 0 {
+			cache = drift.NewComparisonCache(cfg.Global.ComparisonCacheSize)
+		}
+
+		analysis, err := analyzeEndpointTrend(db, config.ApplySensitivity(*endpointConfig, cfg.Global.Sensitivity), period, maxSamples, cache)
+		if err != nil {
+			return err
+		}
+
+		switch outputFormat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(analysis)
+		case "yaml":
+			encoder := yaml.NewEncoder(os.Stdout)
+			encoder.SetIndent(2)
+			defer encoder.Close()
+			return encoder.Encode(analysis)
+		default:
+			outputTrendTable(endpointConfig.ID, formatPeriod(period), analysis)
+			return nil
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trendCmd)
+
+	trendCmd.Flags().String("period", "7d", "history to analyze (24h, 7d, 30d or a duration)")
+	trendCmd.Flags().Int("max-samples", drift.DefaultMaxTrendSamples, "most runs to analyze; longer histories are downsampled (0 analyzes every run)")
+	trendCmd.Flags().StringP("output", "o", "table", "output format (table, json, yaml)")
+}
+
+// analyzeEndpointTrend analyzes up to maxSamples evenly spaced successful runs
+// of an endpoint's history. The runs are sampled by the storage query rather
+// than after loading the whole history.
+// Body comparisons go through cache when it is not nil.
+func analyzeEndpointTrend(db storage.Storage, endpointConfig config.EndpointConfig, period time.Duration, maxSamples int, cache *drift.ComparisonCache) (*drift.TrendAnalysis, error) {
+	runs, err := db.GetMonitoringHistorySample(endpointConfig.ID, period, maxSamples)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitoring history: %w", err)
+	}
+
+	responses := make([]*drift.Response, 0, len(runs))
+	for _, run := range runs {
+		responses = append(responses, runResponse(run))
+	}
+	slices.Reverse(responses) // Oldest first
+
+	if len(responses) < 2 {
+		return nil, fmt.Errorf("endpoint '%s' has %d successful runs in the period, at least 2 are needed", endpointConfig.ID, len(responses))
+	}
+
+	diffOptions, err := diffOptionsForEndpoint(endpointConfig)
+	if err != nil {
+		return nil, err
+	}
+	// The runs are already sampled
+	diffOptions.MaxTrendSamples = -1
+	diffOptions.Cache = cache
+
+	analysis, err := drift.NewDiffEngineWithOptions(diffOptions).AnalyzeTrends(responses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze trends: %w", err)
+	}
+
+	return analysis, nil
+}
+
+// outputTrendTable prints a trend analysis in human-readable form
+func outputTrendTable(endpointID, period string, analysis *drift.TrendAnalysis) {
+	fmt.Printf("Trend Analysis: %s (last %s)\n", endpointID, period)
+	fmt.Println("==========================================")
+
+	fmt.Printf("Runs Analyzed: %d\n", analysis.TotalResponses)
+	fmt.Printf("Time Span: %s\n", analysis.Period.Round(time.Minute))
+	fmt.Printf("Change Frequency: %.1f%%\n", analysis.ChangeFrequency*100)
+	fmt.Printf("Stability Score: %.2f\n", analysis.StabilityScore)
+
+	if trend := analysis.PerformanceTrend; trend != nil {
+		fmt.Printf("Average Response Time: %s\n", trend.AverageResponseTime.Round(time.Millisecond))
+		fmt.Printf("Performance Trend: %s\n", trend.Trend)
+	}
+}
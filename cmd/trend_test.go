@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeEndpointTrend(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	endpointConfig := config.EndpointConfig{ID: "users-api", Method: "GET", URL: "https://api.example.com/users"}
+
+	// Forty runs an hour apart whose body changes every other run, with a failure
+	now := time.Now()
+	for i := 0; i < 40; i++ {
+		run := &storage.MonitoringRun{
+			EndpointID:     endpointConfig.ID,
+			Timestamp:      now.Add(-time.Duration(i) * time.Hour),
+			ResponseStatus: 200,
+			ResponseTimeMs: 100,
+			ResponseBody:   fmt.Sprintf(`{"version": %d}`, i/2),
+		}
+		if i == 2 {
+			run.ResponseStatus = 0
+			run.FailureCategory = "network"
+		}
+		require.NoError(t, db.SaveMonitoringRun(run))
+	}
+
+	analysis, err := analyzeEndpointTrend(db, endpointConfig, 7*24*time.Hour, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 39, analysis.TotalResponses)
+
+	// Every other successful run is sampled, so every sample has a new body;
+	// the failure does not take the place of a sample
+	analysis, err = analyzeEndpointTrend(db, endpointConfig, 7*24*time.Hour, 20, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 20, analysis.TotalResponses)
+	assert.Equal(t, 1.0, analysis.ChangeFrequency)
+	assert.Equal(t, 39*time.Hour, analysis.Period.Round(time.Hour))
+
+	_, err = analyzeEndpointTrend(db, endpointConfig, time.Minute, 0, nil)
+	assert.ErrorContains(t, err, "at least 2 are needed")
+}
+
+func TestAnalyzeEndpointTrendUsesComparisonCache(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	defer db.Close()
+
+	endpointConfig := config.EndpointConfig{ID: "users-api", Method: "GET", URL: "https://api.example.com/users"}
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db.SaveMonitoringRun(&storage.MonitoringRun{
+			EndpointID:     endpointConfig.ID,
+			Timestamp:      now.Add(-time.Duration(i) * time.Hour),
+			ResponseStatus: 200,
+			ResponseBody:   `{"version": 1}`,
+		}))
+	}
+
+	// Every pair of consecutive runs has the same bodies, so only the first is compared
+	cache := drift.NewComparisonCache(8)
+	analysis, err := analyzeEndpointTrend(db, endpointConfig, 7*24*time.Hour, 0, cache)
+	require.NoError(t, err)
+	assert.Zero(t, analysis.ChangeFrequency)
+
+	metrics := cache.GetMetrics()
+	assert.Equal(t, int64(1), metrics.Misses)
+	assert.Equal(t, int64(8), metrics.Hits)
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/security"
+	"github.com/spf13/cobra"
+)
+
+// verifyExportCmd represents the verify-export command
+var verifyExportCmd = &cobra.Command{
+	Use:   "verify-export <file>",
+	Short: "Verify the signature of an exported file",
+	Long: `Verify that an export produced with 'driftwatch export --sign' has not been
+modified since it was written.
+
+The command checks the detached signature (<file>.sig) over the manifest
+(<file>.manifest.json) using the key configured under reporting.signing, then
+checks that the export content matches the hash recorded in the manifest.
+
+Examples:
+  driftwatch verify-export drifts.json
+  driftwatch verify-export history.csv --public-key signing.pub`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		publicKeyFile, err := cmd.Flags().GetString("public-key")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "public-key", err)
+		}
+
+		signing := cfg.Reporting.Signing
+		if publicKeyFile != "" {
+			signing.Algorithm = security.SignatureAlgorithmEd25519
+			signing.KeyFile = ""
+			signing.PublicKeyFile = publicKeyFile
+		}
+
+		verifier, err := loadExportSigner(&signing, false)
+		if err != nil {
+			return fmt.Errorf("failed to load verification key: %w", err)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+
+		manifest, err := security.VerifyExport(args[0], verifier, cwd)
+		if err != nil {
+			return fmt.Errorf("export verification failed: %w", err)
+		}
+
+		fmt.Printf("✅ Export verified: %s\n", args[0])
+		fmt.Printf("Algorithm:  %s\n", manifest.Algorithm)
+		fmt.Printf("Created:    %s\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+		fmt.Printf("SHA-256:    %s\n", manifest.SHA256)
+		fmt.Printf("Size:       %d bytes\n", manifest.SizeBytes)
+
+		recordTypes := make([]string, 0, len(manifest.RecordCounts))
+		for recordType := range manifest.RecordCounts {
+			recordTypes = append(recordTypes, recordType)
+		}
+		sort.Strings(recordTypes)
+		for _, recordType := range recordTypes {
+			fmt.Printf("Records:    %s=%d\n", recordType, manifest.RecordCounts[recordType])
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyExportCmd)
+
+	verifyExportCmd.Flags().String("public-key", "", "ed25519 PEM public key to verify with (overrides configuration)")
+}
+
+// loadExportSigner creates a signer from the export signing configuration.
+// When forSigning is false, only the keys needed for verification are loaded.
+func loadExportSigner(signing *config.SigningConfig, forSigning bool) (security.Signer, error) {
+	switch signing.Algorithm {
+	case security.SignatureAlgorithmHMACSHA256:
+		key := []byte(signing.Key)
+		if len(key) == 0 && signing.KeyFile != "" {
+			data, err := security.SafeReadFile(signing.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read HMAC key file: %w", err)
+			}
+			key = []byte(strings.TrimSpace(string(data)))
+		}
+		return security.NewHMACSigner(key)
+
+	case security.SignatureAlgorithmEd25519:
+		var privateKey ed25519.PrivateKey
+		var publicKey ed25519.PublicKey
+		var err error
+
+		if signing.KeyFile != "" && (forSigning || signing.PublicKeyFile == "") {
+			privateKey, err = security.LoadEd25519PrivateKey(signing.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if signing.PublicKeyFile != "" {
+			publicKey, err = security.LoadEd25519PublicKey(signing.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if forSigning && privateKey == nil {
+			return nil, fmt.Errorf("ed25519 signing requires reporting.signing.key_file")
+		}
+		return security.NewEd25519Signer(privateKey, publicKey)
+
+	case "":
+		return nil, fmt.Errorf("no signing algorithm configured (set reporting.signing.algorithm)")
+
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", signing.Algorithm)
+	}
+}
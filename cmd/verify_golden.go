@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	httpClient "github.com/k0ns0l/driftwatch/internal/http"
+	"github.com/k0ns0l/driftwatch/internal/security"
+	"github.com/spf13/cobra"
+)
+
+// verifyGoldenCmd represents the verify-golden command
+var verifyGoldenCmd = &cobra.Command{
+	Use:   "verify-golden <id>",
+	Short: "Verify an endpoint against its golden response file",
+	Long: `Fetch an endpoint and compare the live response body against a committed
+golden response, failing when breaking changes are found.
+
+Unlike drift monitoring, which compares against the previous run or a rolling
+baseline, the golden file is a fixed contract. The golden file holds the expected
+JSON response body and is taken from the endpoint's golden_file setting unless
+--golden-file is given. Comparison options such as compare_root and required
+fields apply as they do for drift detection.
+
+With --template (or golden_template: true) the golden file is a template whose
+string values <string>, <number>, <uuid> and <iso8601> match any conforming value,
+for fields that are legitimately dynamic. All other values must match exactly, and
+a single-element array matches every element of the response array. Templates
+always describe the whole response body; compare_root does not apply.
+
+Examples:
+  driftwatch verify-golden users-api
+  driftwatch verify-golden users-api --golden-file testdata/users.golden.json
+  driftwatch verify-golden users-api --update     # Rewrite the golden file from the live response
+  driftwatch verify-golden users-api --template   # Treat the golden file as a template`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+
+		goldenFile, err := cmd.Flags().GetString("golden-file")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "golden-file", err)
+		}
+		update, err := cmd.Flags().GetBool("update")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "update", err)
+		}
+		template, err := cmd.Flags().GetBool("template")
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", "template", err)
+		}
+
+		endpointConfig, err := cfg.GetEndpoint(args[0])
+		if err != nil {
+			return err
+		}
+
+		if goldenFile == "" {
+			goldenFile = endpointConfig.GoldenFile
+		}
+		if goldenFile == "" {
+			return fmt.Errorf("endpoint '%s' has no golden_file configured (use --golden-file)", endpointConfig.ID)
+		}
+
+		template = template || endpointConfig.GoldenTemplate
+		if template && update {
+			return fmt.Errorf("--update cannot be used with a template golden file: it would replace the placeholders")
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+
+		client := httpClient.NewClient(httpClient.ClientConfig{
+			Timeout:       cfg.Global.Timeout,
+			RetryCount:    cfg.Global.RetryCount,
+			RetryDelay:    cfg.Global.RetryDelay,
+			MaxRetryDelay: cfg.Global.MaxRetryDelay,
+			UserAgent:     cfg.Global.UserAgent,
+		})
+
+		live, err := performEndpointRequest(context.Background(), cfg, client, *endpointConfig)
+		if err != nil {
+			return fmt.Errorf("failed to fetch endpoint '%s': %w", endpointConfig.ID, err)
+		}
+		if live.StatusCode < 200 || live.StatusCode >= 300 {
+			return fmt.Errorf("endpoint '%s' returned status %d", endpointConfig.ID, live.StatusCode)
+		}
+
+		if update {
+			if err := writeGoldenFile(goldenFile, live.Body, cwd); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Golden file %s updated from %s %s\n", goldenFile, endpointConfig.Method, endpointConfig.URL)
+			return nil
+		}
+
+		golden, err := security.SafeReadFile(goldenFile, cwd)
+		if err != nil {
+			return fmt.Errorf("failed to read golden file: %w", err)
+		}
+
+		var diffResult *drift.DiffResult
+		if template {
+			diffResult, err = drift.MatchTemplate(golden, live.Body)
+		} else {
+			diffResult, err = compareWithGolden(config.ApplySensitivity(*endpointConfig, cfg.Global.Sensitivity), golden, live)
+		}
+		if err != nil {
+			return err
+		}
+
+		changes := convertDriftToCIChanges(diffResult, false)
+		if len(changes) == 0 {
+			fmt.Printf("✓ %s matches golden file %s\n", endpointConfig.ID, goldenFile)
+			return nil
+		}
+
+		fmt.Printf("%s differs from golden file %s:\n", endpointConfig.ID, goldenFile)
+		renderDiff(os.Stdout, changes, colorEnabled(os.Stdout))
+
+		if len(diffResult.BreakingChanges) > 0 {
+			return fmt.Errorf("%d breaking changes against golden file %s", len(diffResult.BreakingChanges), goldenFile)
+		}
+
+		fmt.Println("No breaking changes found")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyGoldenCmd)
+
+	verifyGoldenCmd.Flags().String("golden-file", "", "golden response file (overrides the endpoint's golden_file)")
+	verifyGoldenCmd.Flags().Bool("update", false, "write the live response body to the golden file instead of comparing")
+	verifyGoldenCmd.Flags().Bool("template", false, "treat the golden file as a template with <string>, <number>, <uuid> and <iso8601> placeholders")
+}
+
+// compareWithGolden diffs a live response body against a golden response body.
+// Only the bodies are compared; status, headers and timing are not part of the golden file.
+func compareWithGolden(endpointConfig config.EndpointConfig, golden []byte, live *drift.Response) (*drift.DiffResult, error) {
+	if !json.Valid(golden) {
+		return nil, fmt.Errorf("golden file does not contain valid JSON")
+	}
+
+	diffOptions, err := diffOptionsForEndpoint(endpointConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := &drift.Response{StatusCode: live.StatusCode, Body: golden}
+	actual := &drift.Response{StatusCode: live.StatusCode, Body: live.Body}
+
+	diffResult, err := drift.NewDiffEngineWithOptions(diffOptions).CompareResponses(expected, actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare against golden file: %w", err)
+	}
+
+	return diffResult, nil
+}
+
+// writeGoldenFile stores a response body as an indented golden file
+func writeGoldenFile(path string, body []byte, allowedDir string) error {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+
+	formatted, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to format golden file: %w", err)
+	}
+
+	if err := security.SafeWriteFile(path, append(formatted, '\n'), allowedDir); err != nil {
+		return fmt.Errorf("failed to write golden file: %w", err)
+	}
+
+	return nil
+}
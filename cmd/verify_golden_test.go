@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareWithGolden(t *testing.T) {
+	endpointConfig := config.EndpointConfig{ID: "users-api", URL: "https://api.example.com/users/1", Method: "GET"}
+	golden := []byte(`{"id": 1, "name": "Ada", "email": "ada@example.com"}`)
+
+	t.Run("matching body", func(t *testing.T) {
+		live := &drift.Response{StatusCode: 200, Body: []byte(`{"id": 1, "name": "Ada", "email": "ada@example.com"}`)}
+		result, err := compareWithGolden(endpointConfig, golden, live)
+		require.NoError(t, err)
+		assert.False(t, result.HasChanges)
+	})
+
+	t.Run("value change is not breaking", func(t *testing.T) {
+		live := &drift.Response{StatusCode: 200, Body: []byte(`{"id": 1, "name": "Grace", "email": "ada@example.com"}`)}
+		result, err := compareWithGolden(endpointConfig, golden, live)
+		require.NoError(t, err)
+		assert.True(t, result.HasChanges)
+		assert.Empty(t, result.BreakingChanges)
+	})
+
+	t.Run("removed field is breaking", func(t *testing.T) {
+		live := &drift.Response{StatusCode: 200, Body: []byte(`{"id": 1, "name": "Ada"}`)}
+		result, err := compareWithGolden(endpointConfig, golden, live)
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.BreakingChanges)
+	})
+
+	t.Run("invalid golden file", func(t *testing.T) {
+		live := &drift.Response{StatusCode: 200, Body: []byte(`{}`)}
+		_, err := compareWithGolden(endpointConfig, []byte("not json"), live)
+		assert.Error(t, err)
+	})
+}
+
+func TestWriteGoldenFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.golden.json")
+
+	require.NoError(t, writeGoldenFile(path, []byte(`{"id":1,"name":"Ada"}`), dir))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"id\": 1,\n  \"name\": \"Ada\"\n}\n", string(data))
+
+	assert.Error(t, writeGoldenFile(path, []byte("<html></html>"), dir))
+}
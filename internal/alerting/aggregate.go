@@ -0,0 +1,146 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// alertSeverityRank orders drift severities from the least to the most severe
+var alertSeverityRank = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// sendAggregatedAlert sends the drifts detected by one check of an endpoint as
+// a single alert per channel. Each channel is sent the drifts its rules route to
+// it, and an alert record is kept for every drift, sharing the outcome of the
+// single delivery.
+func (am *DefaultAlertManager) sendAggregatedAlert(ctx context.Context, drifts []*storage.Drift, endpoint *storage.Endpoint, delivered deliverySet) error {
+	byChannel := make(map[string][]*storage.Drift)
+	var channelNames []string
+	for _, drift := range drifts {
+		drift = am.escalatedDrift(drift)
+
+		routed := make(map[string]bool)
+		for _, rule := range am.findApplicableRules(drift, endpoint) {
+			for _, channelName := range rule.Channels {
+				// A drift is listed once per channel, whatever the rules
+				if routed[channelName] || !delivered.claim(drift, channelName) {
+					continue
+				}
+				routed[channelName] = true
+
+				if _, exists := byChannel[channelName]; !exists {
+					channelNames = append(channelNames, channelName)
+				}
+				byChannel[channelName] = append(byChannel[channelName], drift)
+			}
+		}
+	}
+
+	for _, channelName := range channelNames {
+		channel, exists := am.channels[channelName]
+		if !exists || !channel.IsEnabled() {
+			continue
+		}
+
+		if err := am.deliverAggregatedAlert(ctx, channel, channelName, byChannel[channelName], endpoint); err != nil {
+			return fmt.Errorf("failed to send alert via %s channel '%s': %w",
+				channel.GetType(), channelName, err)
+		}
+	}
+
+	return nil
+}
+
+// deliverAggregatedAlert sends one message listing several drifts through a
+// channel, or holds it for the digest during quiet hours, and records an alert
+// for each drift
+func (am *DefaultAlertManager) deliverAggregatedAlert(ctx context.Context, channel AlertChannel, channelName string, drifts []*storage.Drift, endpoint *storage.Endpoint) error {
+	message := am.createAggregatedMessage(drifts, endpoint)
+
+	alerts := make([]*storage.Alert, 0, len(drifts))
+	for _, drift := range drifts {
+		alerts = append(alerts, &storage.Alert{
+			DriftID:     drift.ID,
+			AlertType:   channel.GetType(),
+			ChannelName: channelName,
+			SentAt:      time.Now(),
+			Status:      string(AlertStatusPending),
+		})
+	}
+
+	// Non-critical alerts are held for the digest during quiet hours
+	if am.quietHours.Suppresses(message.Severity) && am.quietHours.IsActive(am.currentTime()) {
+		for _, alert := range alerts {
+			alert.Status = string(AlertStatusBuffered)
+			if err := am.storage.SaveAlert(alert); err != nil {
+				return fmt.Errorf("failed to save alert record: %w", err)
+			}
+		}
+		return nil
+	}
+
+	delivered := alerts[0]
+	deliveryErr := am.deliverAlert(ctx, channel, message, delivered)
+
+	for _, alert := range alerts[1:] {
+		alert.Status = delivered.Status
+		alert.ErrorMessage = delivered.ErrorMessage
+		alert.RetryCount = delivered.RetryCount
+		alert.SentAt = delivered.SentAt
+		if err := am.storage.SaveAlert(alert); err != nil {
+			return fmt.Errorf("failed to save alert record: %w", err)
+		}
+	}
+
+	return deliveryErr
+}
+
+// createAggregatedMessage builds a single alert message listing the drifts of
+// one check, at the highest of their severities. A single drift gets the same
+// message as when alerts are not aggregated.
+func (am *DefaultAlertManager) createAggregatedMessage(drifts []*storage.Drift, endpoint *storage.Endpoint) *AlertMessage {
+	if len(drifts) == 1 {
+		return am.createAlertMessage(drifts[0], endpoint)
+	}
+
+	message := &AlertMessage{
+		Title:       fmt.Sprintf("API Drift Detected: %s", endpoint.URL),
+		Summary:     fmt.Sprintf("%d changes detected in one check of %s", len(drifts), endpoint.ID),
+		Severity:    "low",
+		EndpointID:  endpoint.ID,
+		EndpointURL: endpoint.URL,
+		DetectedAt:  drifts[0].DetectedAt,
+		Metadata: map[string]interface{}{
+			"endpoint_method": endpoint.Method,
+			"aggregated":      true,
+			"change_count":    len(drifts),
+		},
+	}
+
+	driftIDs := make([]int64, 0, len(drifts))
+	for _, drift := range drifts {
+		severity := drift.Severity
+		if severity == "" {
+			severity = "medium"
+		}
+		if alertSeverityRank[severity] > alertSeverityRank[message.Severity] {
+			message.Severity = severity
+		}
+
+		driftIDs = append(driftIDs, drift.ID)
+		message.Changes = append(message.Changes, ChangeDetail{
+			Type:        drift.DriftType,
+			Path:        drift.FieldPath,
+			Description: drift.Description,
+			Severity:    severity,
+			Breaking:    am.isBreakingChange(severity),
+			OldValue:    drift.BeforeValue,
+			NewValue:    drift.AfterValue,
+		})
+	}
+	message.Metadata["drift_ids"] = driftIDs
+
+	return message
+}
@@ -0,0 +1,105 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAggregateTestManager(t *testing.T, store storage.Storage, channel AlertChannel, aggregate bool) *DefaultAlertManager {
+	t.Helper()
+
+	return &DefaultAlertManager{
+		config: &config.Config{
+			Alerting: config.AlertingConfig{
+				Enabled:   true,
+				Aggregate: aggregate,
+				Rules: []config.AlertRuleConfig{
+					{Name: "all", Severity: []string{"low", "medium", "high", "critical"}, Channels: []string{"test-channel"}},
+					{Name: "severe", Severity: []string{"high", "critical"}, Channels: []string{"test-channel"}},
+				},
+				Retry: config.AlertRetryConfig{MaxAttempts: 1},
+			},
+		},
+		storage:  store,
+		channels: map[string]AlertChannel{"test-channel": channel},
+	}
+}
+
+func aggregateTestResult() *drift.DiffResult {
+	return &drift.DiffResult{
+		HasChanges: true,
+		DataChanges: []drift.DataChange{
+			{Path: "$.name", OldValue: "Ada", NewValue: "Grace", ChangeType: drift.ChangeTypeValueChange, Severity: drift.SeverityLow, Description: "name changed"},
+			{Path: "$.email", OldValue: "a@example.com", NewValue: "g@example.com", ChangeType: drift.ChangeTypeValueChange, Severity: drift.SeverityHigh, Description: "email changed"},
+			{Path: "$.age", OldValue: 36, NewValue: 37, ChangeType: drift.ChangeTypeValueChange, Severity: drift.SeverityMedium, Description: "age changed"},
+		},
+	}
+}
+
+func TestProcessDriftAggregatesAlerts(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	manager := newAggregateTestManager(t, store, mockChannel, true)
+	endpoint := &storage.Endpoint{ID: "users-api", URL: "https://api.example.com/users/1", Method: "GET"}
+
+	// One message with every change, although the high drift matches two rules
+	mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return len(msg.Changes) == 3 && msg.Severity == "high" && msg.Metadata["aggregated"] == true
+	})).Return(nil).Once()
+
+	require.NoError(t, manager.ProcessDrift(context.Background(), aggregateTestResult(), endpoint))
+	mockChannel.AssertExpectations(t)
+
+	alerts, err := store.GetAlerts(storage.AlertFilters{})
+	require.NoError(t, err)
+	require.Len(t, alerts, 3)
+	for _, alert := range alerts {
+		assert.Equal(t, string(AlertStatusSent), alert.Status)
+	}
+}
+
+func TestProcessDriftAggregatedDeliveryFailure(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	manager := newAggregateTestManager(t, store, mockChannel, true)
+	endpoint := &storage.Endpoint{ID: "users-api", URL: "https://api.example.com/users/1", Method: "GET"}
+
+	mockChannel.On("Send", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+
+	assert.Error(t, manager.ProcessDrift(context.Background(), aggregateTestResult(), endpoint))
+	mockChannel.AssertExpectations(t)
+
+	// Every drift of the check records the failed delivery
+	alerts, err := store.GetAlerts(storage.AlertFilters{Status: string(AlertStatusFailed)})
+	require.NoError(t, err)
+	assert.Len(t, alerts, 3)
+}
+
+func TestProcessDriftWithoutAggregation(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	manager := newAggregateTestManager(t, store, mockChannel, false)
+	endpoint := &storage.Endpoint{ID: "users-api", URL: "https://api.example.com/users/1", Method: "GET"}
+
+	// One message per drift, although the high drift matches two rules
+	mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return len(msg.Changes) == 1
+	})).Return(nil).Times(3)
+
+	require.NoError(t, manager.ProcessDrift(context.Background(), aggregateTestResult(), endpoint))
+	mockChannel.AssertExpectations(t)
+}
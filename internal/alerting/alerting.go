@@ -17,6 +17,8 @@ type AlertManager interface {
 	TestConfiguration(ctx context.Context) error
 	GetAlertHistory(filters AlertFilters) ([]*Alert, error)
 	ProcessDrift(ctx context.Context, driftResult *drift.DiffResult, endpoint *storage.Endpoint) error
+	FlushDigest(ctx context.Context) error
+	CheckLiveness(ctx context.Context) error
 }
 
 // AlertChannel defines the interface for different alert delivery channels
@@ -38,6 +40,10 @@ type AlertMessage struct {
 	Severity    string                 `json:"severity"`
 	EndpointID  string                 `json:"endpoint_id"`
 	EndpointURL string                 `json:"endpoint_url"`
+
+	// Resolved marks the alert sent once the drift it names is resolved; its
+	// DetectedAt is then when the drift was found resolved
+	Resolved bool `json:"resolved,omitempty"`
 }
 
 // ChangeDetail represents details about a specific change
@@ -85,9 +91,12 @@ const (
 
 // DefaultAlertManager implements the AlertManager interface
 type DefaultAlertManager struct {
-	config   *config.Config
-	storage  storage.Storage
-	channels map[string]AlertChannel
+	config     *config.Config
+	storage    storage.Storage
+	channels   map[string]AlertChannel
+	quietHours *QuietHours
+	escalation *EscalationPolicy
+	now        func() time.Time
 }
 
 // NewAlertManager creates a new alert manager instance
@@ -96,6 +105,7 @@ func NewAlertManager(cfg *config.Config, storage storage.Storage) (AlertManager,
 		config:   cfg,
 		storage:  storage,
 		channels: make(map[string]AlertChannel),
+		now:      time.Now,
 	}
 
 	// Initialize alert channels based on configuration
@@ -103,6 +113,18 @@ func NewAlertManager(cfg *config.Config, storage storage.Storage) (AlertManager,
 		return nil, fmt.Errorf("failed to initialize alert channels: %w", err)
 	}
 
+	quietHours, err := NewQuietHours(cfg.Alerting.QuietHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure quiet hours: %w", err)
+	}
+	manager.quietHours = quietHours
+
+	escalation, err := NewEscalationPolicy(cfg.Alerting.Escalation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure escalation: %w", err)
+	}
+	manager.escalation = escalation
+
 	return manager, nil
 }
 
@@ -149,26 +171,69 @@ func (am *DefaultAlertManager) ProcessDrift(ctx context.Context, driftResult *dr
 	// Convert drift result to storage drift records
 	drifts := am.convertDriftResult(driftResult, endpoint)
 
-	// Process each drift
+	// Drifts undoing an alerted drift resolve it instead of being alerted on
+	var alerted []*storage.Drift
+	if am.notifiesResolved() {
+		var err error
+		if alerted, err = am.alertedDrifts(endpoint.ID); err != nil {
+			return err
+		}
+	}
+
+	// Process each drift; with aggregation, alerts are sent once all are saved
+	var aggregated []*storage.Drift
+	delivered := am.newDeliverySet()
 	for _, drift := range drifts {
-		// Save drift to storage
+		if reverted := revertedDrift(drift, alerted); reverted != nil {
+			resolvedAt := drift.DetectedAt
+			drift.ResolvedAt = &resolvedAt
+			if err := am.storage.SaveDrift(drift); err != nil {
+				return fmt.Errorf("failed to save drift: %w", err)
+			}
+			if err := am.resolveDrift(ctx, reverted, endpoint, drift.DetectedAt); err != nil {
+				return fmt.Errorf("failed to resolve drift %d: %w", reverted.ID, err)
+			}
+			continue
+		}
+
+		// Save drift to storage, which stores drifts whose fingerprint was
+		// acknowledged forever as acknowledged
 		if err := am.storage.SaveDrift(drift); err != nil {
 			return fmt.Errorf("failed to save drift: %w", err)
 		}
 
-		// Send alerts based on rules (only if alerting is enabled)
-		if am.config.Alerting.Enabled {
-			if err := am.SendAlert(ctx, drift, endpoint); err != nil {
+		// Send alerts based on rules (only if alerting is enabled); suppressed
+		// drifts are not alerted on
+		if am.config.Alerting.Enabled && !drift.Acknowledged {
+			if am.config.Alerting.Aggregate {
+				aggregated = append(aggregated, drift)
+				continue
+			}
+			if err := am.sendAlert(ctx, drift, endpoint, delivered); err != nil {
 				return fmt.Errorf("failed to send alert for drift %d: %w", drift.ID, err)
 			}
 		}
 	}
 
+	if len(aggregated) > 0 {
+		if err := am.sendAggregatedAlert(ctx, aggregated, endpoint, delivered); err != nil {
+			return fmt.Errorf("failed to send aggregated alert: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // SendAlert sends an alert for a specific drift
 func (am *DefaultAlertManager) SendAlert(ctx context.Context, drift *storage.Drift, endpoint *storage.Endpoint) error {
+	return am.sendAlert(ctx, drift, endpoint, am.newDeliverySet())
+}
+
+// sendAlert sends an alert for a drift through the channels of its rules that
+// have not been sent it yet during the check
+func (am *DefaultAlertManager) sendAlert(ctx context.Context, drift *storage.Drift, endpoint *storage.Endpoint, delivered deliverySet) error {
+	drift = am.escalatedDrift(drift)
+
 	// Find applicable alert rules
 	applicableRules := am.findApplicableRules(drift, endpoint)
 	if len(applicableRules) == 0 {
@@ -178,11 +243,14 @@ func (am *DefaultAlertManager) SendAlert(ctx context.Context, drift *storage.Dri
 	// Create alert message
 	message := am.createAlertMessage(drift, endpoint)
 
+	// Non-critical alerts are held for the digest during quiet hours
+	buffer := am.quietHours.Suppresses(message.Severity) && am.quietHours.IsActive(am.currentTime())
+
 	// Send alerts through configured channels
 	for _, rule := range applicableRules {
 		for _, channelName := range rule.Channels {
 			channel, exists := am.channels[channelName]
-			if !exists || !channel.IsEnabled() {
+			if !exists || !channel.IsEnabled() || !delivered.claim(drift, channelName) {
 				continue
 			}
 
@@ -195,26 +263,19 @@ func (am *DefaultAlertManager) SendAlert(ctx context.Context, drift *storage.Dri
 				RetryCount:  0,
 			}
 
-			// Send the alert
-			if err := channel.Send(ctx, message); err != nil {
-				alert.Status = string(AlertStatusFailed)
-				alert.ErrorMessage = err.Error()
-
-				// Save failed alert record
-				if saveErr := am.storage.SaveAlert(alert); saveErr != nil {
-					return fmt.Errorf("failed to save alert record: %w", saveErr)
+			if buffer {
+				alert.Status = string(AlertStatusBuffered)
+				if err := am.storage.SaveAlert(alert); err != nil {
+					return fmt.Errorf("failed to save alert record: %w", err)
 				}
+				continue
+			}
 
+			// Send the alert, retrying transient failures
+			if err := am.deliverAlert(ctx, channel, message, alert); err != nil {
 				return fmt.Errorf("failed to send alert via %s channel '%s': %w",
 					channel.GetType(), channelName, err)
 			}
-
-			alert.Status = string(AlertStatusSent)
-
-			// Save successful alert record
-			if err := am.storage.SaveAlert(alert); err != nil {
-				return fmt.Errorf("failed to save alert record: %w", err)
-			}
 		}
 	}
 
@@ -288,9 +349,12 @@ func (am *DefaultAlertManager) GetAlertHistory(filters AlertFilters) ([]*Alert,
 func (am *DefaultAlertManager) findApplicableRules(drift *storage.Drift, endpoint *storage.Endpoint) []config.AlertRuleConfig {
 	var applicableRules []config.AlertRuleConfig
 
+	// Rules scoped to an endpoint also cover its variants
+	baseID := am.config.BaseEndpointID(endpoint.ID)
+
 	for _, rule := range am.config.Alerting.Rules {
-		// Check severity match
-		severityMatch := false
+		// Check severity match (path rules without severities match any severity)
+		severityMatch := len(rule.Severity) == 0 && len(rule.PathPatterns) > 0
 		for _, severity := range rule.Severity {
 			if severity == drift.Severity {
 				severityMatch = true
@@ -301,11 +365,16 @@ func (am *DefaultAlertManager) findApplicableRules(drift *storage.Drift, endpoin
 			continue
 		}
 
+		// Check field path match (empty means all paths)
+		if len(rule.PathPatterns) > 0 && !matchesAnyPathPattern(drift.FieldPath, rule.PathPatterns) {
+			continue
+		}
+
 		// Check endpoint match (empty means all endpoints)
 		if len(rule.Endpoints) > 0 {
 			endpointMatch := false
 			for _, endpointID := range rule.Endpoints {
-				if endpointID == endpoint.ID {
+				if endpointID == endpoint.ID || endpointID == baseID {
 					endpointMatch = true
 					break
 				}
@@ -321,14 +390,29 @@ func (am *DefaultAlertManager) findApplicableRules(drift *storage.Drift, endpoin
 	return applicableRules
 }
 
+// matchesAnyPathPattern reports whether a drift's field path matches one of a rule's patterns
+func matchesAnyPathPattern(fieldPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if drift.MatchesPathPattern(fieldPath, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 func (am *DefaultAlertManager) createAlertMessage(drift *storage.Drift, endpoint *storage.Endpoint) *AlertMessage {
 	severity := drift.Severity
 	if severity == "" {
 		severity = "medium"
 	}
 
+	title := fmt.Sprintf("API Drift Detected: %s", endpoint.URL)
+	if drift.DriftType == DriftTypeEndpointDown {
+		title = fmt.Sprintf("Endpoint Down: %s", endpoint.URL)
+	}
+
 	return &AlertMessage{
-		Title:       fmt.Sprintf("API Drift Detected: %s", endpoint.URL),
+		Title:       title,
 		Summary:     drift.Description,
 		Severity:    severity,
 		EndpointID:  endpoint.ID,
@@ -352,6 +436,60 @@ func (am *DefaultAlertManager) createAlertMessage(drift *storage.Drift, endpoint
 	}
 }
 
+// escalatedDrift returns the drift to alert on: a copy with the escalated
+// severity for stale, unacknowledged drifts, or the drift itself. A recurring
+// change is as stale as its earliest unacknowledged drift, so that it escalates
+// although each drift recording it is new.
+func (am *DefaultAlertManager) escalatedDrift(drift *storage.Drift) *storage.Drift {
+	if am.escalation == nil || drift.Acknowledged || drift.DetectedAt.IsZero() {
+		return drift
+	}
+
+	if severity := am.escalation.severitySince(drift.Severity, am.openSince(drift), am.currentTime()); severity != drift.Severity {
+		escalated := *drift
+		escalated.Severity = severity
+		return &escalated
+	}
+	return drift
+}
+
+// openSince returns when the change a drift records was first detected: the
+// detection time of the earliest unacknowledged drift with the same
+// fingerprint. Without that history, the drift's own detection time is used.
+func (am *DefaultAlertManager) openSince(drift *storage.Drift) time.Time {
+	since := drift.DetectedAt
+
+	fingerprint := drift.Fingerprint
+	if fingerprint == "" {
+		fingerprint = drift.ComputeFingerprint()
+	}
+
+	acknowledged := false
+	history, err := am.storage.GetDrifts(storage.DriftFilters{
+		EndpointID:   drift.EndpointID,
+		FieldPath:    drift.FieldPath,
+		Acknowledged: &acknowledged,
+	})
+	if err != nil {
+		return since
+	}
+
+	for _, earlier := range history {
+		if earlier.Fingerprint == fingerprint && earlier.DetectedAt.Before(since) {
+			since = earlier.DetectedAt
+		}
+	}
+	return since
+}
+
+// currentTime returns the current time, allowing the clock to be replaced in tests
+func (am *DefaultAlertManager) currentTime() time.Time {
+	if am.now == nil {
+		return time.Now()
+	}
+	return am.now()
+}
+
 func (am *DefaultAlertManager) isBreakingChange(severity string) bool {
 	return severity == "high" || severity == "critical"
 }
@@ -414,5 +552,29 @@ func (am *DefaultAlertManager) convertDriftResult(driftResult *drift.DiffResult,
 		drifts = append(drifts, drift)
 	}
 
-	return drifts
+	maxDrifts := 0
+	if am.config != nil {
+		maxDrifts = am.config.Global.MaxDriftsPerCheck
+	}
+	return LimitDrifts(drifts, maxDrifts)
+}
+
+// LimitDrifts enforces max_drifts_per_check on the drifts recorded for a single
+// check. More than maxDrifts drifts are collapsed into one critical summary drift
+// instead of one row per field; 0 disables the limit. Every path that stores the
+// drifts of a check passes them through here.
+func LimitDrifts(drifts []*storage.Drift, maxDrifts int) []*storage.Drift {
+	if maxDrifts <= 0 || len(drifts) <= maxDrifts {
+		return drifts
+	}
+
+	return []*storage.Drift{{
+		EndpointID:   drifts[0].EndpointID,
+		DetectedAt:   drifts[0].DetectedAt,
+		DriftType:    string(drift.ChangeTypeSchemaChange),
+		Severity:     string(drift.SeverityCritical),
+		Description:  fmt.Sprintf("response structure changed substantially: %d differences", len(drifts)),
+		FieldPath:    "$",
+		Acknowledged: false,
+	}}
 }
@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -43,6 +44,19 @@ func (m *MockStorage) GetMonitoringHistory(endpointID string, period time.Durati
 	return args.Get(0).([]*storage.MonitoringRun), args.Error(1)
 }
 
+func (m *MockStorage) GetMonitoringHistorySample(endpointID string, period time.Duration, maxSamples int) ([]*storage.MonitoringRun, error) {
+	args := m.Called(endpointID, period, maxSamples)
+	return args.Get(0).([]*storage.MonitoringRun), args.Error(1)
+}
+
+func (m *MockStorage) GetMonitoringRun(id int64) (*storage.MonitoringRun, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.MonitoringRun), args.Error(1)
+}
+
 func (m *MockStorage) SaveDrift(drift *storage.Drift) error {
 	args := m.Called(drift)
 	if args.Get(0) != nil {
@@ -52,11 +66,106 @@ func (m *MockStorage) SaveDrift(drift *storage.Drift) error {
 	return args.Error(1)
 }
 
+func (m *MockStorage) GetDrift(id int64) (*storage.Drift, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.Drift), args.Error(1)
+}
+
 func (m *MockStorage) GetDrifts(filters storage.DriftFilters) ([]*storage.Drift, error) {
 	args := m.Called(filters)
 	return args.Get(0).([]*storage.Drift), args.Error(1)
 }
 
+func (m *MockStorage) GetAlertedUnresolvedDrifts(endpointID string, limit int) ([]*storage.Drift, error) {
+	args := m.Called(endpointID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.Drift), args.Error(1)
+}
+
+func (m *MockStorage) GetDriftsAfter(afterID int64, limit int) ([]*storage.Drift, error) {
+	args := m.Called(afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.Drift), args.Error(1)
+}
+
+func (m *MockStorage) SearchDrifts(text string, limit int) ([]*storage.Drift, error) {
+	args := m.Called(text, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.Drift), args.Error(1)
+}
+
+func (m *MockStorage) AcknowledgeDrifts(endpointID string, before time.Time) (int64, error) {
+	args := m.Called(endpointID, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) AcknowledgeFingerprint(fingerprint string) (int64, error) {
+	args := m.Called(fingerprint)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) ResolveDrift(id int64, resolvedAt time.Time) error {
+	args := m.Called(id, resolvedAt)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SuppressFingerprint(fingerprint string) error {
+	args := m.Called(fingerprint)
+	return args.Error(0)
+}
+
+func (m *MockStorage) IsFingerprintSuppressed(fingerprint string) (bool, error) {
+	args := m.Called(fingerprint)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStorage) GetSinkCursor(name string) (int64, error) {
+	args := m.Called(name)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) SaveSinkCursor(name string, lastDriftID int64) error {
+	args := m.Called(name, lastDriftID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SaveHeartbeat(at time.Time) error {
+	args := m.Called(at)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetLastHeartbeat() (time.Time, error) {
+	args := m.Called()
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockStorage) SaveMaintenanceWindow(window *storage.MaintenanceWindow) error {
+	args := m.Called(window)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetActiveMaintenanceWindows(at time.Time) ([]*storage.MaintenanceWindow, error) {
+	args := m.Called(at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockStorage) EndMaintenanceWindows(endpointID string, at time.Time) (int64, error) {
+	args := m.Called(endpointID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStorage) SaveAlert(alert *storage.Alert) error {
 	args := m.Called(alert)
 	if args.Get(0) != nil {
@@ -66,6 +175,11 @@ func (m *MockStorage) SaveAlert(alert *storage.Alert) error {
 	return args.Error(1)
 }
 
+func (m *MockStorage) UpdateAlert(alert *storage.Alert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
 func (m *MockStorage) GetAlerts(filters storage.AlertFilters) ([]*storage.Alert, error) {
 	args := m.Called(filters)
 	return args.Get(0).([]*storage.Alert), args.Error(1)
@@ -77,6 +191,16 @@ func (m *MockStorage) CleanupOldMonitoringRuns(olderThan time.Time) (int64, erro
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockStorage) TrimMonitoringRuns(endpointID string, keep int) (int64, error) {
+	args := m.Called(endpointID, keep)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) CompactMonitoringRuns(olderThan time.Time) (int64, error) {
+	args := m.Called(olderThan)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStorage) CleanupOldDrifts(olderThan time.Time) (int64, error) {
 	args := m.Called(olderThan)
 	return args.Get(0).(int64), args.Error(1)
@@ -383,6 +507,9 @@ func TestTestConfiguration(t *testing.T) {
 
 func TestFindApplicableRules(t *testing.T) {
 	cfg := &config.Config{
+		Endpoints: []config.EndpointConfig{
+			{ID: "billing", Variants: []config.EndpointVariant{{Name: "eu"}, {Name: "us"}}},
+		},
 		Alerting: config.AlertingConfig{
 			Rules: []config.AlertRuleConfig{
 				{
@@ -402,6 +529,19 @@ func TestFindApplicableRules(t *testing.T) {
 					Endpoints: []string{"endpoint-2"},
 					Channels:  []string{"webhook"},
 				},
+				{
+					Name:         "pricing",
+					Endpoints:    []string{"billing"},
+					PathPatterns: []string{"$.pricing", "plans[*].price"},
+					Channels:     []string{"pager"},
+				},
+				{
+					Name:         "pricing-critical",
+					Severity:     []string{"critical"},
+					Endpoints:    []string{"billing"},
+					PathPatterns: []string{"pricing"},
+					Channels:     []string{"slack"},
+				},
 			},
 		},
 	}
@@ -446,6 +586,50 @@ func TestFindApplicableRules(t *testing.T) {
 			},
 			expectedRules: 1, // Only specific-endpoint rule matches
 		},
+		{
+			name: "low drift under a path pattern",
+			drift: &storage.Drift{
+				Severity:  "low",
+				FieldPath: "$.pricing.tiers[0].amount",
+			},
+			endpoint: &storage.Endpoint{
+				ID: "billing",
+			},
+			expectedRules: 1, // Only pricing matches; pricing-critical requires critical
+		},
+		{
+			name: "critical drift at an array path pattern",
+			drift: &storage.Drift{
+				Severity:  "critical",
+				FieldPath: "$.plans[3].price",
+			},
+			endpoint: &storage.Endpoint{
+				ID: "billing",
+			},
+			expectedRules: 1, // pricing matches; pricing-critical does not cover plans
+		},
+		{
+			name: "drift outside path patterns",
+			drift: &storage.Drift{
+				Severity:  "low",
+				FieldPath: "$.pricingNotes",
+			},
+			endpoint: &storage.Endpoint{
+				ID: "billing",
+			},
+			expectedRules: 0,
+		},
+		{
+			name: "drift on a variant of a rule's endpoint",
+			drift: &storage.Drift{
+				Severity:  "critical",
+				FieldPath: "$.pricing.currency",
+			},
+			endpoint: &storage.Endpoint{
+				ID: "billing-eu",
+			},
+			expectedRules: 2, // pricing and pricing-critical cover the variants of billing
+		},
 		{
 			name: "no matching rules",
 			drift: &storage.Drift{
@@ -550,3 +734,55 @@ func TestConvertDriftResult(t *testing.T) {
 	assert.Equal(t, "low", drifts[2].Severity)
 	assert.Equal(t, "$.response_time", drifts[2].FieldPath)
 }
+
+func TestConvertDriftResultCollapsesLargeResults(t *testing.T) {
+	manager := &DefaultAlertManager{
+		config: &config.Config{Global: config.GlobalConfig{MaxDriftsPerCheck: 5}},
+	}
+	endpoint := &storage.Endpoint{ID: "test-endpoint"}
+
+	driftResult := &drift.DiffResult{HasChanges: true, Summary: &drift.DiffSummary{}}
+	for i := 0; i < 12; i++ {
+		driftResult.StructuralChanges = append(driftResult.StructuralChanges, drift.StructuralChange{
+			Type:     drift.ChangeTypeFieldRemoved,
+			Path:     fmt.Sprintf("$.field%d", i),
+			Severity: drift.SeverityHigh,
+			Breaking: true,
+		})
+	}
+	driftResult.Summary.TotalChanges = 12
+	driftResult.Summary.BreakingChanges = 12
+
+	drifts := manager.convertDriftResult(driftResult, endpoint)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, "critical", drifts[0].Severity)
+	assert.Equal(t, "schema_change", drifts[0].DriftType)
+	assert.Equal(t, "$", drifts[0].FieldPath)
+	assert.Equal(t, "response structure changed substantially: 12 differences", drifts[0].Description)
+
+	// Results within the cap keep one drift per change
+	manager.config.Global.MaxDriftsPerCheck = 20
+	assert.Len(t, manager.convertDriftResult(driftResult, endpoint), 12)
+
+	// A cap of 0 disables collapsing
+	manager.config.Global.MaxDriftsPerCheck = 0
+	assert.Len(t, manager.convertDriftResult(driftResult, endpoint), 12)
+}
+
+func TestLimitDrifts(t *testing.T) {
+	detectedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	drifts := []*storage.Drift{
+		{EndpointID: "api", DetectedAt: detectedAt, DriftType: "tls_changed", Severity: "medium", FieldPath: "$.tls.fingerprint"},
+		{EndpointID: "api", DetectedAt: detectedAt, DriftType: "tls_expiring", Severity: "high", FieldPath: "$.tls.not_after"},
+	}
+
+	assert.Equal(t, drifts, LimitDrifts(drifts, 2))
+	assert.Equal(t, drifts, LimitDrifts(drifts, 0))
+
+	limited := LimitDrifts(drifts, 1)
+	require.Len(t, limited, 1)
+	assert.Equal(t, "api", limited[0].EndpointID)
+	assert.Equal(t, detectedAt, limited[0].DetectedAt)
+	assert.Equal(t, "critical", limited[0].Severity)
+	assert.Equal(t, "response structure changed substantially: 2 differences", limited[0].Description)
+}
@@ -0,0 +1,39 @@
+package alerting
+
+import "github.com/k0ns0l/driftwatch/internal/storage"
+
+// deliverySet records the drifts sent through each channel during one check of
+// an endpoint, so that a drift reaches a channel at most once even when several
+// rules route it there. Drifts are identified by their fingerprint, so that
+// identical drifts detected by the same check are also sent once. A nil set
+// allows duplicates.
+type deliverySet map[string]bool
+
+// newDeliverySet returns the delivery set of a check, or nil when the
+// configuration allows duplicate deliveries
+func (am *DefaultAlertManager) newDeliverySet() deliverySet {
+	if am.config.Alerting.AllowDuplicates {
+		return nil
+	}
+	return make(deliverySet)
+}
+
+// claim reports whether a drift may be sent through a channel, recording it as
+// sent if so
+func (s deliverySet) claim(drift *storage.Drift, channelName string) bool {
+	if s == nil {
+		return true
+	}
+
+	fingerprint := drift.Fingerprint
+	if fingerprint == "" {
+		fingerprint = drift.ComputeFingerprint()
+	}
+
+	key := fingerprint + "\x00" + channelName
+	if s[key] {
+		return false
+	}
+	s[key] = true
+	return true
+}
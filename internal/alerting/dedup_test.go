@@ -0,0 +1,74 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessDriftAllowDuplicates(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	manager := newAggregateTestManager(t, store, mockChannel, false)
+	manager.config.Alerting.AllowDuplicates = true
+	endpoint := &storage.Endpoint{ID: "users-api", URL: "https://api.example.com/users/1", Method: "GET"}
+
+	// One message per drift and matching rule
+	mockChannel.On("Send", mock.Anything, mock.Anything).Return(nil).Times(4)
+
+	require.NoError(t, manager.ProcessDrift(context.Background(), aggregateTestResult(), endpoint))
+	mockChannel.AssertExpectations(t)
+}
+
+func TestProcessDriftDeduplicatesPerChannel(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	slack := &MockAlertChannel{name: "slack", chanType: "test", enabled: true}
+	pager := &MockAlertChannel{name: "pager", chanType: "test", enabled: true}
+	manager := &DefaultAlertManager{
+		config: &config.Config{
+			Alerting: config.AlertingConfig{
+				Enabled: true,
+				Rules: []config.AlertRuleConfig{
+					{Name: "all", Severity: []string{"low", "high"}, Channels: []string{"slack"}},
+					{Name: "severe", Severity: []string{"high"}, Channels: []string{"slack", "pager"}},
+					{Name: "severe-again", Severity: []string{"high"}, Channels: []string{"pager"}},
+				},
+				Retry: config.AlertRetryConfig{MaxAttempts: 1},
+			},
+		},
+		storage:  store,
+		channels: map[string]AlertChannel{"slack": slack, "pager": pager},
+	}
+	endpoint := &storage.Endpoint{ID: "users-api", URL: "https://api.example.com/users/1", Method: "GET"}
+
+	// The same change twice has one fingerprint, so it is sent once as well
+	emailChange := drift.DataChange{Path: "$.email", OldValue: "a@example.com", NewValue: "g@example.com", ChangeType: drift.ChangeTypeValueChange, Severity: drift.SeverityHigh, Description: "email changed"}
+	result := &drift.DiffResult{
+		HasChanges: true,
+		DataChanges: []drift.DataChange{
+			{Path: "$.name", OldValue: "Ada", NewValue: "Grace", ChangeType: drift.ChangeTypeValueChange, Severity: drift.SeverityLow, Description: "name changed"},
+			emailChange,
+			emailChange,
+		},
+	}
+
+	slack.On("Send", mock.Anything, mock.Anything).Return(nil).Times(2)
+	pager.On("Send", mock.Anything, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, manager.ProcessDrift(context.Background(), result, endpoint))
+	slack.AssertExpectations(t)
+	pager.AssertExpectations(t)
+
+	alerts, err := store.GetAlerts(storage.AlertFilters{})
+	require.NoError(t, err)
+	require.Len(t, alerts, 3)
+}
@@ -0,0 +1,154 @@
+package alerting
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/errors"
+	"github.com/k0ns0l/driftwatch/internal/recovery"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// Defaults for alert delivery retries
+const (
+	DefaultAlertRetryAttempts     = 3
+	DefaultAlertRetryInitialDelay = 1 * time.Second
+	DefaultAlertRetryMaxDelay     = 30 * time.Second
+)
+
+// DeliveryStatusError is returned when an alert channel's service rejects a
+// delivery with an HTTP status
+type DeliveryStatusError struct {
+	Service    string
+	StatusCode int
+}
+
+// Error implements the error interface
+func (e *DeliveryStatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.Service, e.StatusCode)
+}
+
+// isRetryableDeliveryError reports whether a failed delivery may succeed when
+// retried. Client errors from HTTP services and permanent SMTP replies are not
+// retried, except for timeouts and rate limiting.
+func isRetryableDeliveryError(err error) bool {
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *DeliveryStatusError
+	if stderrors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusRequestTimeout, statusErr.StatusCode == http.StatusTooManyRequests:
+			return true
+		case statusErr.StatusCode >= 400 && statusErr.StatusCode < 500:
+			return false
+		}
+		return true
+	}
+
+	var smtpErr *textproto.Error
+	if stderrors.As(err, &smtpErr) {
+		return smtpErr.Code < 500
+	}
+
+	return true
+}
+
+// retryConfig returns the recovery configuration for alert deliveries
+func (am *DefaultAlertManager) retryConfig() recovery.RecoveryConfig {
+	retry := am.config.Alerting.Retry
+
+	recoveryConfig := recovery.DefaultRecoveryConfig()
+	recoveryConfig.Strategy = recovery.RetryStrategyExponential
+	recoveryConfig.Jitter = true
+	recoveryConfig.MaxAttempts = DefaultAlertRetryAttempts
+	recoveryConfig.InitialDelay = DefaultAlertRetryInitialDelay
+	recoveryConfig.MaxDelay = DefaultAlertRetryMaxDelay
+
+	if retry.MaxAttempts > 0 {
+		recoveryConfig.MaxAttempts = retry.MaxAttempts
+	}
+	if retry.InitialDelay > 0 {
+		recoveryConfig.InitialDelay = retry.InitialDelay
+	}
+	if retry.MaxDelay > 0 {
+		recoveryConfig.MaxDelay = retry.MaxDelay
+	}
+
+	return recoveryConfig
+}
+
+// deliverAlert sends a message through a channel, retrying transient failures.
+// The alert record is saved once delivery succeeds or on the first failure,
+// and updated after every further attempt with the retry count, status and
+// error of the latest attempt.
+func (am *DefaultAlertManager) deliverAlert(ctx context.Context, channel AlertChannel, message *AlertMessage, alert *storage.Alert) error {
+	saved := false
+	record := func() error {
+		if saved {
+			return am.storage.UpdateAlert(alert)
+		}
+		saved = true
+		return am.storage.SaveAlert(alert)
+	}
+
+	var recordErr error
+	var sendErr error
+	operation := func(ctx context.Context, attempt int) error {
+		alert.RetryCount = attempt - 1
+		alert.SentAt = time.Now()
+
+		sendErr = channel.Send(ctx, message)
+		if sendErr == nil {
+			return nil
+		}
+
+		alert.Status = string(AlertStatusRetry)
+		alert.ErrorMessage = sendErr.Error()
+		if err := record(); err != nil {
+			recordErr = err
+			return err
+		}
+
+		return errors.WrapError(sendErr, errors.ErrorTypeAlert, "ALERT_DELIVERY", "failed to deliver alert")
+	}
+
+	retryable := func(err error) bool {
+		return recordErr == nil && isRetryableDeliveryError(sendErr)
+	}
+
+	manager := recovery.NewRecoveryManager(am.retryConfig(), nil)
+	if err := manager.RetryIf(ctx, operation, retryable, "alert delivery"); err != nil {
+		if recordErr != nil {
+			return fmt.Errorf("failed to save alert record: %w", recordErr)
+		}
+
+		alert.Status = string(AlertStatusFailed)
+		if sendErr != nil {
+			alert.ErrorMessage = sendErr.Error()
+		} else {
+			alert.ErrorMessage = err.Error()
+		}
+		if saveErr := record(); saveErr != nil {
+			return fmt.Errorf("failed to save alert record: %w", saveErr)
+		}
+
+		if sendErr != nil {
+			return sendErr
+		}
+		return err
+	}
+
+	alert.Status = string(AlertStatusSent)
+	alert.ErrorMessage = ""
+	if err := record(); err != nil {
+		return fmt.Errorf("failed to save alert record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryableDeliveryError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"server error", &DeliveryStatusError{Service: "webhook endpoint", StatusCode: 503}, true},
+		{"rate limited", &DeliveryStatusError{Service: "Slack webhook", StatusCode: 429}, true},
+		{"request timeout", &DeliveryStatusError{Service: "webhook endpoint", StatusCode: 408}, true},
+		{"client error", &DeliveryStatusError{Service: "webhook endpoint", StatusCode: 404}, false},
+		{"wrapped client error", fmt.Errorf("send failed: %w", &DeliveryStatusError{Service: "Discord webhook", StatusCode: 400}), false},
+		{"transient smtp reply", &textproto.Error{Code: 421, Msg: "service not available"}, true},
+		{"permanent smtp reply", &textproto.Error{Code: 550, Msg: "mailbox unavailable"}, false},
+		{"network error", fmt.Errorf("failed to send webhook request: connection refused"), true},
+		{"cancelled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, isRetryableDeliveryError(tt.err))
+		})
+	}
+}
+
+func TestDeliverAlert(t *testing.T) {
+	newManager := func(store storage.Storage) *DefaultAlertManager {
+		return &DefaultAlertManager{
+			config: &config.Config{Alerting: config.AlertingConfig{
+				Retry: config.AlertRetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond},
+			}},
+			storage: store,
+		}
+	}
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+
+		channel := &MockAlertChannel{name: "hook", chanType: "webhook", enabled: true}
+		channel.On("Send", mock.Anything, mock.Anything).Return(&DeliveryStatusError{Service: "webhook endpoint", StatusCode: 502}).Once()
+		channel.On("Send", mock.Anything, mock.Anything).Return(nil).Once()
+
+		alert := &storage.Alert{DriftID: 1, AlertType: "webhook", ChannelName: "hook", Status: string(AlertStatusPending)}
+		require.NoError(t, newManager(store).deliverAlert(context.Background(), channel, &AlertMessage{}, alert))
+		channel.AssertExpectations(t)
+
+		alerts, err := store.GetAlerts(storage.AlertFilters{})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, string(AlertStatusSent), alerts[0].Status)
+		assert.Equal(t, 1, alerts[0].RetryCount)
+		assert.Empty(t, alerts[0].ErrorMessage)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+
+		channel := &MockAlertChannel{name: "hook", chanType: "webhook", enabled: true}
+		channel.On("Send", mock.Anything, mock.Anything).Return(&DeliveryStatusError{Service: "webhook endpoint", StatusCode: 401}).Once()
+
+		alert := &storage.Alert{DriftID: 1, AlertType: "webhook", ChannelName: "hook", Status: string(AlertStatusPending)}
+		err = newManager(store).deliverAlert(context.Background(), channel, &AlertMessage{}, alert)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 401")
+		channel.AssertExpectations(t)
+
+		alerts, err := store.GetAlerts(storage.AlertFilters{})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, string(AlertStatusFailed), alerts[0].Status)
+		assert.Equal(t, 0, alerts[0].RetryCount)
+		assert.Equal(t, "webhook endpoint returned status 401", alerts[0].ErrorMessage)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+
+		channel := &MockAlertChannel{name: "hook", chanType: "webhook", enabled: true}
+		channel.On("Send", mock.Anything, mock.Anything).Return(fmt.Errorf("connection refused")).Times(3)
+
+		alert := &storage.Alert{DriftID: 1, AlertType: "webhook", ChannelName: "hook", Status: string(AlertStatusPending)}
+		err = newManager(store).deliverAlert(context.Background(), channel, &AlertMessage{}, alert)
+		require.Error(t, err)
+		channel.AssertExpectations(t)
+
+		alerts, err := store.GetAlerts(storage.AlertFilters{})
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, string(AlertStatusFailed), alerts[0].Status)
+		assert.Equal(t, 2, alerts[0].RetryCount)
+	})
+}
@@ -6,11 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"text/template"
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
 )
 
+// discordContentLimit is the most characters Discord accepts in message content
+const discordContentLimit = 2000
+
 // DiscordChannel implements AlertChannel for Discord webhook integration
 type DiscordChannel struct {
 	name       string
@@ -19,6 +23,7 @@ type DiscordChannel struct {
 	avatarURL  string
 	enabled    bool
 	client     *http.Client
+	template   *template.Template // replaces the built-in format when set
 }
 
 // DiscordMessage represents a Discord webhook message
@@ -61,6 +66,11 @@ func NewDiscordChannel(channelConfig config.AlertChannelConfig) (AlertChannel, e
 		return nil, fmt.Errorf("webhook_url is required for Discord channel")
 	}
 
+	messageTemplate, err := channelTemplate(channelConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	channel := &DiscordChannel{
 		name:       channelConfig.Name,
 		webhookURL: webhookURL,
@@ -68,6 +78,7 @@ func NewDiscordChannel(channelConfig config.AlertChannelConfig) (AlertChannel, e
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		template: messageTemplate,
 	}
 
 	// Optional settings
@@ -87,6 +98,14 @@ func NewDiscordChannel(channelConfig config.AlertChannelConfig) (AlertChannel, e
 // Send sends an alert message to Discord
 func (dc *DiscordChannel) Send(ctx context.Context, message *AlertMessage) error {
 	discordMessage := dc.formatMessage(message)
+	if dc.template != nil {
+		text, err := renderMessage(dc.template, message)
+		if err != nil {
+			return err
+		}
+		discordMessage.Content = truncateContent(text, discordContentLimit)
+		discordMessage.Embeds = nil
+	}
 
 	payload, err := json.Marshal(discordMessage)
 	if err != nil {
@@ -107,7 +126,7 @@ func (dc *DiscordChannel) Send(ctx context.Context, message *AlertMessage) error
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Discord webhook returned status %d", resp.StatusCode)
+		return &DeliveryStatusError{Service: "Discord webhook", StatusCode: resp.StatusCode}
 	}
 
 	return nil
@@ -225,6 +244,15 @@ func (dc *DiscordChannel) formatMessage(message *AlertMessage) *DiscordMessage {
 	return discordMessage
 }
 
+// truncateContent shortens text to at most limit characters, marking the cut
+func truncateContent(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
 // getSeverityColor returns an appropriate color for the severity level
 func (dc *DiscordChannel) getSeverityColor(severity string) int {
 	switch severity {
@@ -6,6 +6,7 @@ import (
 	"net/smtp"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
@@ -22,6 +23,7 @@ type EmailChannel struct {
 	to       []string
 	enabled  bool
 	useTLS   bool
+	template *template.Template // replaces the built-in HTML body when set
 }
 
 // NewEmailChannel creates a new email alert channel
@@ -48,14 +50,20 @@ func NewEmailChannel(channelConfig config.AlertChannelConfig) (AlertChannel, err
 		return nil, err
 	}
 
+	messageTemplate, err := channelTemplate(channelConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	channel := &EmailChannel{
-		name:    channelConfig.Name,
-		host:    host,
-		port:    port,
-		from:    from,
-		to:      to,
-		enabled: channelConfig.Enabled,
-		useTLS:  true, // Default to TLS
+		name:     channelConfig.Name,
+		host:     host,
+		port:     port,
+		from:     from,
+		to:       to,
+		enabled:  channelConfig.Enabled,
+		useTLS:   true, // Default to TLS
+		template: messageTemplate,
 	}
 
 	setOptionalSettings(channel, settings)
@@ -150,9 +158,16 @@ func setOptionalSettings(channel *EmailChannel, settings map[string]interface{})
 // Send sends an alert message via email
 func (ec *EmailChannel) Send(ctx context.Context, message *AlertMessage) error {
 	subject := fmt.Sprintf("[DriftWatch] %s", message.Title)
-	body := ec.formatMessage(message)
 
-	return ec.sendEmail(ctx, subject, body)
+	if ec.template != nil {
+		body, err := renderMessage(ec.template, message)
+		if err != nil {
+			return err
+		}
+		return ec.sendEmail(ctx, subject, body, "text/plain")
+	}
+
+	return ec.sendEmail(ctx, subject, ec.formatMessage(message), "text/html")
 }
 
 // Test sends a test email to verify the configuration
@@ -197,9 +212,9 @@ func (ec *EmailChannel) IsEnabled() bool {
 }
 
 // sendEmail sends an email using SMTP
-func (ec *EmailChannel) sendEmail(ctx context.Context, subject, body string) error {
+func (ec *EmailChannel) sendEmail(ctx context.Context, subject, body, contentType string) error {
 	// Create the email message
-	msg := ec.buildEmailMessage(subject, body)
+	msg := ec.buildEmailMessage(subject, body, contentType)
 
 	// Set up authentication
 	var auth smtp.Auth
@@ -229,8 +244,9 @@ func (ec *EmailChannel) sendEmail(ctx context.Context, subject, body string) err
 	}
 }
 
-// buildEmailMessage builds the complete email message with headers
-func (ec *EmailChannel) buildEmailMessage(subject, body string) string {
+// buildEmailMessage builds the complete email message with headers; contentType
+// is the media type of the body, such as "text/html"
+func (ec *EmailChannel) buildEmailMessage(subject, body, contentType string) string {
 	var msg strings.Builder
 
 	// Headers
@@ -238,7 +254,7 @@ func (ec *EmailChannel) buildEmailMessage(subject, body string) string {
 	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(ec.to, ", ")))
 	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
 	msg.WriteString("MIME-Version: 1.0\r\n")
-	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	msg.WriteString(fmt.Sprintf("Content-Type: %s; charset=UTF-8\r\n", contentType))
 	msg.WriteString("\r\n")
 
 	// Body
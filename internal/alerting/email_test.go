@@ -193,7 +193,7 @@ func TestEmailChannelBuildMessage(t *testing.T) {
 	subject := "Test Alert"
 	body := "<html><body>Test body</body></html>"
 
-	message := emailChannel.buildEmailMessage(subject, body)
+	message := emailChannel.buildEmailMessage(subject, body, "text/html")
 
 	// Verify headers
 	assert.Contains(t, message, "From: alerts@example.com")
@@ -0,0 +1,90 @@
+package alerting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// severityLevels orders drift severities from least to most severe
+var severityLevels = []string{"low", "medium", "high", "critical"}
+
+// EscalationPolicy raises the severity of unacknowledged drifts based on their age
+type EscalationPolicy struct {
+	rules []config.EscalationRuleConfig // sorted by age, longest first
+}
+
+// NewEscalationPolicy creates an escalation policy from configuration.
+// It returns nil if escalation is disabled.
+func NewEscalationPolicy(cfg config.EscalationConfig) (*EscalationPolicy, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	if len(cfg.Rules) == 0 {
+		return nil, fmt.Errorf("escalation is enabled but no rules are configured")
+	}
+
+	rules := make([]config.EscalationRuleConfig, len(cfg.Rules))
+	copy(rules, cfg.Rules)
+	for _, rule := range rules {
+		if rule.After <= 0 {
+			return nil, fmt.Errorf("escalation age must be positive, got %s", rule.After)
+		}
+		if rule.Levels < 1 {
+			return nil, fmt.Errorf("escalation levels must be at least 1, got %d", rule.Levels)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].After > rules[j].After
+	})
+
+	return &EscalationPolicy{rules: rules}, nil
+}
+
+// Severity returns the effective severity of a drift at the given time.
+// Acknowledged drifts and drifts younger than every rule keep their recorded
+// severity. Escalation never goes beyond critical.
+func (p *EscalationPolicy) Severity(drift *storage.Drift, now time.Time) string {
+	if p == nil || drift.Acknowledged || drift.DetectedAt.IsZero() {
+		return drift.Severity
+	}
+
+	return p.severitySince(drift.Severity, drift.DetectedAt, now)
+}
+
+// severitySince returns a severity escalated for a change that has been open
+// since the given time
+func (p *EscalationPolicy) severitySince(severity string, since, now time.Time) string {
+	level := severityLevel(severity)
+	if level < 0 {
+		return severity
+	}
+
+	age := now.Sub(since)
+	for _, rule := range p.rules {
+		if age >= rule.After {
+			level += rule.Levels
+			if level >= len(severityLevels) {
+				level = len(severityLevels) - 1
+			}
+			return severityLevels[level]
+		}
+	}
+
+	return severity
+}
+
+// severityLevel returns the index of a severity in severityLevels, or -1 if unknown
+func severityLevel(severity string) int {
+	for i, level := range severityLevels {
+		if level == severity {
+			return i
+		}
+	}
+	return -1
+}
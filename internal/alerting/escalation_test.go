@@ -0,0 +1,201 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEscalationPolicy(t *testing.T) {
+	policy, err := NewEscalationPolicy(config.EscalationConfig{Enabled: false})
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	drift := &storage.Drift{Severity: "low", DetectedAt: time.Now().Add(-30 * 24 * time.Hour)}
+	assert.Equal(t, "low", policy.Severity(drift, time.Now()))
+
+	_, err = NewEscalationPolicy(config.EscalationConfig{Enabled: true})
+	assert.Error(t, err)
+
+	_, err = NewEscalationPolicy(config.EscalationConfig{
+		Enabled: true,
+		Rules:   []config.EscalationRuleConfig{{After: 0, Levels: 1}},
+	})
+	assert.Error(t, err)
+}
+
+func TestEscalationPolicySeverity(t *testing.T) {
+	policy, err := NewEscalationPolicy(config.EscalationConfig{
+		Enabled: true,
+		Rules: []config.EscalationRuleConfig{
+			{After: 7 * 24 * time.Hour, Levels: 2},
+			{After: 72 * time.Hour, Levels: 1},
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		severity     string
+		age          time.Duration
+		acknowledged bool
+		expected     string
+	}{
+		{name: "recent drift keeps severity", severity: "medium", age: time.Hour, expected: "medium"},
+		{name: "first rule raises one level", severity: "medium", age: 80 * time.Hour, expected: "high"},
+		{name: "longest matching rule applies", severity: "low", age: 8 * 24 * time.Hour, expected: "high"},
+		{name: "escalation stops at critical", severity: "high", age: 8 * 24 * time.Hour, expected: "critical"},
+		{name: "acknowledged drift is not escalated", severity: "medium", age: 8 * 24 * time.Hour, acknowledged: true, expected: "medium"},
+		{name: "unknown severity is left alone", severity: "info", age: 8 * 24 * time.Hour, expected: "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := &storage.Drift{
+				Severity:     tt.severity,
+				DetectedAt:   now.Add(-tt.age),
+				Acknowledged: tt.acknowledged,
+			}
+			assert.Equal(t, tt.expected, policy.Severity(drift, now))
+		})
+	}
+}
+
+func TestSendAlertUsesEscalatedSeverity(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	cfg := &config.Config{
+		Alerting: config.AlertingConfig{
+			Enabled: true,
+			Rules: []config.AlertRuleConfig{
+				{
+					Name:     "urgent-only",
+					Severity: []string{"high", "critical"},
+					Channels: []string{"test-channel"},
+				},
+			},
+			Escalation: config.EscalationConfig{
+				Enabled: true,
+				Rules:   []config.EscalationRuleConfig{{After: 72 * time.Hour, Levels: 1}},
+			},
+		},
+	}
+
+	escalation, err := NewEscalationPolicy(cfg.Alerting.Escalation)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	manager := &DefaultAlertManager{
+		config:     cfg,
+		storage:    store,
+		channels:   map[string]AlertChannel{"test-channel": mockChannel},
+		escalation: escalation,
+		now:        func() time.Time { return now },
+	}
+
+	endpoint := &storage.Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	drift := &storage.Drift{
+		ID:          1,
+		EndpointID:  "test-endpoint",
+		Severity:    "medium",
+		Description: "Field type changed",
+		DriftType:   "type_change",
+		DetectedAt:  now.Add(-96 * time.Hour),
+	}
+
+	mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return msg.Severity == "high"
+	})).Return(nil).Once()
+
+	require.NoError(t, manager.SendAlert(context.Background(), drift, endpoint))
+	mockChannel.AssertExpectations(t)
+	assert.Equal(t, "medium", drift.Severity)
+}
+
+func TestSendAlertEscalatesRecurringDrift(t *testing.T) {
+	cfg := &config.Config{
+		Alerting: config.AlertingConfig{
+			Enabled: true,
+			Rules: []config.AlertRuleConfig{
+				{Name: "urgent-only", Severity: []string{"high", "critical"}, Channels: []string{"test-channel"}},
+			},
+			Escalation: config.EscalationConfig{
+				Enabled: true,
+				Rules:   []config.EscalationRuleConfig{{After: 72 * time.Hour, Levels: 1}},
+			},
+		},
+	}
+	escalation, err := NewEscalationPolicy(cfg.Alerting.Escalation)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	endpoint := &storage.Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	newDrift := func(detectedAt time.Time) *storage.Drift {
+		return &storage.Drift{
+			EndpointID:  "test-endpoint",
+			Severity:    "medium",
+			Description: "Field type changed",
+			DriftType:   "type_change",
+			FieldPath:   "$.id",
+			BeforeValue: "number",
+			AfterValue:  "string",
+			DetectedAt:  detectedAt,
+		}
+	}
+
+	t.Run("repeated drift escalates", func(t *testing.T) {
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+		mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+		manager := &DefaultAlertManager{
+			config:     cfg,
+			storage:    store,
+			channels:   map[string]AlertChannel{"test-channel": mockChannel},
+			escalation: escalation,
+			now:        func() time.Time { return now },
+		}
+
+		require.NoError(t, store.SaveDrift(newDrift(now.Add(-96*time.Hour))))
+		drift := newDrift(now)
+		require.NoError(t, store.SaveDrift(drift))
+
+		mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+			return msg.Severity == "high"
+		})).Return(nil).Once()
+
+		require.NoError(t, manager.SendAlert(context.Background(), drift, endpoint))
+		mockChannel.AssertExpectations(t)
+	})
+
+	t.Run("acknowledged history does not escalate", func(t *testing.T) {
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+		mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+		manager := &DefaultAlertManager{
+			config:     cfg,
+			storage:    store,
+			channels:   map[string]AlertChannel{"test-channel": mockChannel},
+			escalation: escalation,
+			now:        func() time.Time { return now },
+		}
+
+		earlier := newDrift(now.Add(-96 * time.Hour))
+		earlier.Acknowledged = true
+		require.NoError(t, store.SaveDrift(earlier))
+		drift := newDrift(now)
+		require.NoError(t, store.SaveDrift(drift))
+
+		require.NoError(t, manager.SendAlert(context.Background(), drift, endpoint))
+		mockChannel.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	})
+}
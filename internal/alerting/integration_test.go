@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
 	"github.com/k0ns0l/driftwatch/internal/drift"
@@ -433,7 +435,9 @@ func TestAlertingFailureHandling(t *testing.T) {
 	defer store.Close()
 
 	// Create a server that returns errors
+	var requests int
 	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer failingServer.Close()
@@ -458,6 +462,7 @@ func TestAlertingFailureHandling(t *testing.T) {
 					Channels: []string{"failing-webhook"},
 				},
 			},
+			Retry: config.AlertRetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond},
 		},
 	}
 
@@ -506,7 +511,11 @@ func TestAlertingFailureHandling(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, alerts, 1)
 	assert.Equal(t, "failed", alerts[0].Status)
-	assert.NotEmpty(t, alerts[0].ErrorMessage)
+	assert.Equal(t, "webhook endpoint returned status 500", alerts[0].ErrorMessage)
+
+	// Server errors are retried up to the configured number of attempts
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 2, alerts[0].RetryCount)
 }
 
 func TestAlertingDisabled(t *testing.T) {
@@ -562,3 +571,80 @@ func TestAlertingDisabled(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, alerts, 0)
 }
+
+func TestAlertingSuppressedFingerprint(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test_suppressed.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	var received int
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhookServer.Close()
+
+	cfg := &config.Config{
+		Alerting: config.AlertingConfig{
+			Enabled: true,
+			Channels: []config.AlertChannelConfig{
+				{
+					Type:     "webhook",
+					Name:     "test-webhook",
+					Enabled:  true,
+					Settings: map[string]interface{}{"url": webhookServer.URL},
+				},
+			},
+			Rules: []config.AlertRuleConfig{
+				{Name: "all", Severity: []string{"low", "medium", "high", "critical"}, Channels: []string{"test-webhook"}},
+			},
+		},
+	}
+
+	alertManager, err := NewAlertManager(cfg, store)
+	require.NoError(t, err)
+
+	endpoint := &storage.Endpoint{ID: "test-endpoint", URL: "https://api.example.com/test", Method: "GET"}
+	require.NoError(t, store.SaveEndpoint(endpoint))
+
+	result := &drift.DiffResult{
+		HasChanges: true,
+		StructuralChanges: []drift.StructuralChange{
+			{
+				Type:        drift.ChangeTypeFieldRemoved,
+				Path:        "$.field",
+				Description: "Field removed",
+				Severity:    drift.SeverityHigh,
+				OldValue:    "a",
+			},
+		},
+	}
+
+	ctx := context.Background()
+	require.NoError(t, alertManager.ProcessDrift(ctx, result, endpoint))
+	assert.Equal(t, 1, received)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	require.NotEmpty(t, drifts[0].Fingerprint)
+	require.NoError(t, store.SuppressFingerprint(drifts[0].Fingerprint))
+
+	// The same change again is stored acknowledged without alerting
+	require.NoError(t, alertManager.ProcessDrift(ctx, result, endpoint))
+	assert.Equal(t, 1, received)
+
+	drifts, err = store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	for _, d := range drifts {
+		assert.Equal(t, drifts[0].Fingerprint, d.Fingerprint)
+	}
+	acknowledged := 0
+	for _, d := range drifts {
+		if d.Acknowledged {
+			acknowledged++
+		}
+	}
+	assert.Equal(t, 1, acknowledged)
+}
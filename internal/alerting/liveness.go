@@ -0,0 +1,240 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// DriftTypeEndpointDown marks the drift recorded when an endpoint stops responding successfully
+const DriftTypeEndpointDown = "endpoint_down"
+
+// DefaultLivenessCheckInterval is how often the liveness scheduler looks for endpoints that went down
+const DefaultLivenessCheckInterval = time.Minute
+
+// CheckLiveness fires an endpoint_down alert for every enabled endpoint that has
+// been checked within the liveness window without a single successful run.
+// Drift detection needs two successful responses to compare, so an endpoint that
+// only returns errors would otherwise go unnoticed. Each outage is alerted once:
+// no new alert is sent until the endpoint has succeeded again. With
+// notify_resolved, the recovery of an alerted endpoint sends a resolved alert.
+func (am *DefaultAlertManager) CheckLiveness(ctx context.Context) error {
+	liveness := am.config.Alerting.Liveness
+	if !am.config.Alerting.Enabled || !liveness.Enabled {
+		return nil
+	}
+
+	var errors []string
+	for _, endpointConfig := range am.config.ExpandedEndpoints() {
+		if !endpointConfig.Enabled {
+			continue
+		}
+
+		if err := am.checkEndpointLiveness(ctx, endpointConfig.ID); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", endpointConfig.ID, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("liveness check failures: %v", errors)
+	}
+
+	return nil
+}
+
+// checkEndpointLiveness alerts if a single endpoint is down and has not yet been
+// alerted. Endpoints are not checked during a maintenance window, when they are
+// expected to fail; an outage that outlasts the window is alerted once it ends.
+func (am *DefaultAlertManager) checkEndpointLiveness(ctx context.Context, endpointID string) error {
+	liveness := am.config.Alerting.Liveness
+
+	inMaintenance, err := am.inMaintenance(endpointID)
+	if err != nil {
+		return err
+	}
+	if inMaintenance {
+		return nil
+	}
+
+	runs, err := am.storage.GetMonitoringHistory(endpointID, liveness.Window)
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring history: %w", err)
+	}
+	if !isDown(runs) {
+		if am.notifiesResolved() {
+			return am.resolveOutage(ctx, endpointID, runs)
+		}
+		return nil
+	}
+
+	alerted, err := am.outageAlerted(endpointID)
+	if err != nil {
+		return err
+	}
+	if alerted {
+		return nil
+	}
+
+	endpoint, err := am.storage.GetEndpoint(endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to get endpoint: %w", err)
+	}
+
+	severity := liveness.Severity
+	if severity == "" {
+		severity = "critical"
+	}
+
+	// Runs are newest first
+	lastFailure := runs[0].ErrorMessage
+	if lastFailure == "" && runs[0].ResponseStatus != 0 {
+		lastFailure = fmt.Sprintf("status %d", runs[0].ResponseStatus)
+	}
+
+	drift := &storage.Drift{
+		EndpointID:  endpointID,
+		DetectedAt:  am.currentTime(),
+		DriftType:   DriftTypeEndpointDown,
+		Severity:    severity,
+		FieldPath:   "$",
+		Description: fmt.Sprintf("no successful responses in the last %s (%d failed checks)", liveness.Window, len(runs)),
+		AfterValue:  lastFailure,
+	}
+
+	if err := am.storage.SaveDrift(drift); err != nil {
+		return fmt.Errorf("failed to save endpoint_down drift: %w", err)
+	}
+
+	// An outage whose fingerprint was suppressed is stored acknowledged
+	if drift.Acknowledged {
+		return nil
+	}
+
+	return am.SendAlert(ctx, drift, endpoint)
+}
+
+// inMaintenance reports whether a maintenance window currently covers an endpoint
+func (am *DefaultAlertManager) inMaintenance(endpointID string) (bool, error) {
+	now := am.currentTime()
+	windows, err := am.storage.GetActiveMaintenanceWindows(now)
+	if err != nil {
+		return false, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	for _, window := range windows {
+		if window.Covers(endpointID, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// outageAlerted reports whether the current outage of an endpoint has already
+// been alerted, that is whether an endpoint_down drift was recorded and the
+// endpoint has not had a successful run since
+func (am *DefaultAlertManager) outageAlerted(endpointID string) (bool, error) {
+	drifts, err := am.storage.GetDrifts(storage.DriftFilters{EndpointID: endpointID})
+	if err != nil {
+		return false, fmt.Errorf("failed to get drifts: %w", err)
+	}
+
+	var lastAlert time.Time
+	for _, drift := range drifts {
+		if drift.DriftType == DriftTypeEndpointDown && drift.DetectedAt.After(lastAlert) {
+			lastAlert = drift.DetectedAt
+		}
+	}
+	if lastAlert.IsZero() {
+		return false, nil
+	}
+
+	runs, err := am.storage.GetMonitoringHistory(endpointID, am.currentTime().Sub(lastAlert))
+	if err != nil {
+		return false, fmt.Errorf("failed to get monitoring history: %w", err)
+	}
+
+	for _, run := range runs {
+		if run.Succeeded() {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isDown reports whether an endpoint was checked but never succeeded. An
+// endpoint without runs is not considered down: it may not have been checked yet.
+func isDown(runs []*storage.MonitoringRun) bool {
+	if len(runs) == 0 {
+		return false
+	}
+
+	for _, run := range runs {
+		if run.Succeeded() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LivenessScheduler periodically checks endpoints for outages
+type LivenessScheduler struct {
+	manager  AlertManager
+	logger   *logging.Logger
+	interval time.Duration
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewLivenessScheduler creates a scheduler that checks endpoint liveness at the given interval
+func NewLivenessScheduler(manager AlertManager, interval time.Duration, logger *logging.Logger) *LivenessScheduler {
+	if interval <= 0 {
+		interval = DefaultLivenessCheckInterval
+	}
+	if logger == nil {
+		logger = logging.GetGlobalLogger()
+	}
+
+	return &LivenessScheduler{
+		manager:  manager,
+		logger:   logger.WithComponent("alert_liveness"),
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins checking endpoint liveness in the background
+func (ls *LivenessScheduler) Start(ctx context.Context) {
+	ls.wg.Add(1)
+	go func() {
+		defer ls.wg.Done()
+
+		ticker := time.NewTicker(ls.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ls.stopChan:
+				return
+			case <-ticker.C:
+			}
+
+			if err := ls.manager.CheckLiveness(ctx); err != nil {
+				ls.logger.LogError(ctx, err, "Failed to check endpoint liveness")
+			}
+		}
+	}()
+}
+
+// Stop stops the liveness scheduler and waits for it to finish
+func (ls *LivenessScheduler) Stop() {
+	close(ls.stopChan)
+	ls.wg.Wait()
+}
@@ -0,0 +1,158 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLiveness(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	cfg := &config.Config{
+		Endpoints: []config.EndpointConfig{
+			{ID: "down", URL: "https://api.example.com/down", Enabled: true},
+			{ID: "flaky", URL: "https://api.example.com/flaky", Enabled: true},
+			{ID: "disabled", URL: "https://api.example.com/disabled", Enabled: false},
+		},
+		Alerting: config.AlertingConfig{
+			Enabled: true,
+			Rules: []config.AlertRuleConfig{
+				{Name: "all", Severity: []string{"low", "medium", "high", "critical"}, Channels: []string{"test-channel"}},
+			},
+			Liveness: config.LivenessConfig{Enabled: true, Window: time.Hour},
+		},
+	}
+
+	manager := &DefaultAlertManager{
+		config:   cfg,
+		storage:  store,
+		channels: map[string]AlertChannel{"test-channel": mockChannel},
+	}
+
+	now := time.Now()
+	for _, endpoint := range cfg.Endpoints {
+		require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: endpoint.ID, URL: endpoint.URL, Method: "GET"}))
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+				EndpointID:     endpoint.ID,
+				Timestamp:      now.Add(-time.Duration(i) * 10 * time.Minute),
+				ResponseStatus: 503,
+			}))
+		}
+	}
+	require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "flaky",
+		Timestamp:      now.Add(-5 * time.Minute),
+		ResponseStatus: 200,
+	}))
+
+	mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return msg.Title == "Endpoint Down: https://api.example.com/down" && msg.Severity == "critical"
+	})).Return(nil).Once()
+
+	require.NoError(t, manager.CheckLiveness(context.Background()))
+
+	// The outage has already been alerted, so a second check sends nothing
+	require.NoError(t, manager.CheckLiveness(context.Background()))
+	mockChannel.AssertExpectations(t)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "down"})
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, DriftTypeEndpointDown, drifts[0].DriftType)
+	assert.Equal(t, "status 503", drifts[0].AfterValue)
+
+	drifts, err = store.GetDrifts(storage.DriftFilters{EndpointID: "flaky"})
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+}
+
+func TestCheckLivenessDuringMaintenance(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	cfg := &config.Config{
+		Endpoints: []config.EndpointConfig{
+			{ID: "down", URL: "https://api.example.com/down", Enabled: true},
+		},
+		Alerting: config.AlertingConfig{
+			Enabled: true,
+			Rules: []config.AlertRuleConfig{
+				{Name: "all", Severity: []string{"critical"}, Channels: []string{"test-channel"}},
+			},
+			Liveness: config.LivenessConfig{Enabled: true, Window: time.Hour},
+		},
+	}
+
+	now := time.Now()
+	manager := &DefaultAlertManager{
+		config:   cfg,
+		storage:  store,
+		channels: map[string]AlertChannel{"test-channel": mockChannel},
+		now:      func() time.Time { return now },
+	}
+
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "down", URL: "https://api.example.com/down", Method: "GET"}))
+	require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "down",
+		Timestamp:      now.Add(-10 * time.Minute),
+		ResponseStatus: 503,
+	}))
+	require.NoError(t, store.SaveMaintenanceWindow(&storage.MaintenanceWindow{
+		EndpointID: "down",
+		StartedAt:  now.Add(-30 * time.Minute),
+		EndsAt:     now.Add(30 * time.Minute),
+	}))
+
+	// No alert is sent and no drift recorded while the endpoint is in maintenance
+	require.NoError(t, manager.CheckLiveness(context.Background()))
+	mockChannel.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "down"})
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// An outage that outlasts the window is alerted once it ends
+	now = now.Add(time.Hour)
+	require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "down",
+		Timestamp:      now.Add(-time.Minute),
+		ResponseStatus: 503,
+	}))
+	mockChannel.On("Send", mock.Anything, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, manager.CheckLiveness(context.Background()))
+	mockChannel.AssertExpectations(t)
+}
+
+func TestIsDown(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		expected bool
+	}{
+		{name: "no runs", statuses: nil, expected: false},
+		{name: "all failed", statuses: []int{500, 0, 404}, expected: true},
+		{name: "one success", statuses: []int{500, 204}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs []*storage.MonitoringRun
+			for _, status := range tt.statuses {
+				runs = append(runs, &storage.MonitoringRun{ResponseStatus: status})
+			}
+			assert.Equal(t, tt.expected, isDown(runs))
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// AlertStatusBuffered marks alerts held back during quiet hours until the digest is sent
+const AlertStatusBuffered AlertStatus = "buffered"
+
+// DefaultDigestCheckInterval is how often the digest scheduler checks whether quiet hours ended
+const DefaultDigestCheckInterval = time.Minute
+
+// QuietHours represents a daily window during which non-critical alerts are buffered
+type QuietHours struct {
+	location   *time.Location
+	severities map[string]bool
+	start      int // minutes since midnight
+	end        int // minutes since midnight
+}
+
+// NewQuietHours creates a quiet hours window from configuration.
+// It returns nil if quiet hours are disabled.
+func NewQuietHours(cfg config.QuietHoursConfig) (*QuietHours, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	start, err := parseClock(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	end, err := parseClock(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+
+	location := time.Local
+	if cfg.Timezone != "" {
+		location, err = time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet hours timezone: %w", err)
+		}
+	}
+
+	severities := map[string]bool{"low": true, "medium": true, "high": true}
+	if len(cfg.Severities) > 0 {
+		severities = make(map[string]bool)
+		for _, severity := range cfg.Severities {
+			severities[strings.ToLower(severity)] = true
+		}
+	}
+	// Critical alerts always go out immediately
+	delete(severities, "critical")
+
+	return &QuietHours{
+		location:   location,
+		severities: severities,
+		start:      start,
+		end:        end,
+	}, nil
+}
+
+// IsActive reports whether the given time falls within the quiet hours window
+func (q *QuietHours) IsActive(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+
+	local := t.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	// Window wraps past midnight
+	return minute >= q.start || minute < q.end
+}
+
+// Suppresses reports whether alerts of the given severity are buffered during quiet hours
+func (q *QuietHours) Suppresses(severity string) bool {
+	if q == nil {
+		return false
+	}
+	return q.severities[strings.ToLower(severity)]
+}
+
+// parseClock parses an HH:MM time of day into minutes since midnight
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// FlushDigest sends buffered alerts as a single digest per channel once quiet hours have ended
+func (am *DefaultAlertManager) FlushDigest(ctx context.Context) error {
+	if am.quietHours.IsActive(am.currentTime()) {
+		return nil
+	}
+
+	buffered, err := am.storage.GetAlerts(storage.AlertFilters{Status: string(AlertStatusBuffered)})
+	if err != nil {
+		return fmt.Errorf("failed to get buffered alerts: %w", err)
+	}
+	if len(buffered) == 0 {
+		return nil
+	}
+
+	// Group buffered alerts by channel, oldest first
+	byChannel := make(map[string][]*storage.Alert)
+	var channelNames []string
+	for _, alert := range buffered {
+		if _, exists := byChannel[alert.ChannelName]; !exists {
+			channelNames = append(channelNames, alert.ChannelName)
+		}
+		byChannel[alert.ChannelName] = append(byChannel[alert.ChannelName], alert)
+	}
+	sort.Strings(channelNames)
+
+	var errors []string
+	for _, channelName := range channelNames {
+		alerts := byChannel[channelName]
+		sort.Slice(alerts, func(i, j int) bool {
+			return alerts[i].SentAt.Before(alerts[j].SentAt)
+		})
+
+		if err := am.sendDigest(ctx, channelName, alerts); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("digest delivery failures: %v", errors)
+	}
+
+	return nil
+}
+
+// sendDigest delivers the buffered alerts of a single channel and records the outcome
+func (am *DefaultAlertManager) sendDigest(ctx context.Context, channelName string, alerts []*storage.Alert) error {
+	channel, exists := am.channels[channelName]
+	if !exists || !channel.IsEnabled() {
+		return am.finishBufferedAlerts(alerts, AlertStatusFailed, "channel no longer configured")
+	}
+
+	message, err := am.createDigestMessage(alerts)
+	if err != nil {
+		return err
+	}
+
+	if err := channel.Send(ctx, message); err != nil {
+		// Leave alerts buffered so the digest is retried on the next flush
+		return fmt.Errorf("failed to send digest via %s channel '%s': %w",
+			channel.GetType(), channelName, err)
+	}
+
+	return am.finishBufferedAlerts(alerts, AlertStatusSent, "")
+}
+
+// finishBufferedAlerts moves buffered alerts to their final delivery status
+func (am *DefaultAlertManager) finishBufferedAlerts(alerts []*storage.Alert, status AlertStatus, errorMessage string) error {
+	now := am.currentTime()
+	for _, alert := range alerts {
+		alert.Status = string(status)
+		alert.ErrorMessage = errorMessage
+		alert.SentAt = now
+		if err := am.storage.UpdateAlert(alert); err != nil {
+			return fmt.Errorf("failed to update alert record: %w", err)
+		}
+	}
+	return nil
+}
+
+// createDigestMessage builds a single alert message summarizing buffered drifts
+func (am *DefaultAlertManager) createDigestMessage(alerts []*storage.Alert) (*AlertMessage, error) {
+	message := &AlertMessage{
+		Severity:   "low",
+		DetectedAt: alerts[0].SentAt,
+		Metadata: map[string]interface{}{
+			"digest":      true,
+			"alert_count": len(alerts),
+		},
+	}
+
+	endpoints := make(map[string]bool)
+	for _, alert := range alerts {
+		drift, err := am.storage.GetDrift(alert.DriftID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load drift %d for digest: %w", alert.DriftID, err)
+		}
+
+		severity := drift.Severity
+		if severity == "" {
+			severity = "medium"
+		}
+		if alertSeverityRank[severity] > alertSeverityRank[message.Severity] {
+			message.Severity = severity
+		}
+
+		endpoints[drift.EndpointID] = true
+		message.Changes = append(message.Changes, ChangeDetail{
+			Type:        drift.DriftType,
+			Path:        drift.FieldPath,
+			Description: fmt.Sprintf("[%s] %s", drift.EndpointID, drift.Description),
+			Severity:    severity,
+			Breaking:    am.isBreakingChange(severity),
+			OldValue:    drift.BeforeValue,
+			NewValue:    drift.AfterValue,
+		})
+	}
+
+	if len(endpoints) == 1 {
+		for endpointID := range endpoints {
+			message.EndpointID = endpointID
+		}
+	}
+
+	message.Title = fmt.Sprintf("API Drift Digest: %d alerts during quiet hours", len(alerts))
+	message.Summary = fmt.Sprintf("%d drift alerts across %d endpoints were held during quiet hours",
+		len(alerts), len(endpoints))
+
+	return message, nil
+}
+
+// DigestScheduler periodically flushes alerts buffered during quiet hours
+type DigestScheduler struct {
+	manager  AlertManager
+	logger   *logging.Logger
+	interval time.Duration
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDigestScheduler creates a scheduler that flushes buffered alerts at the given interval
+func NewDigestScheduler(manager AlertManager, interval time.Duration, logger *logging.Logger) *DigestScheduler {
+	if interval <= 0 {
+		interval = DefaultDigestCheckInterval
+	}
+	if logger == nil {
+		logger = logging.GetGlobalLogger()
+	}
+
+	return &DigestScheduler{
+		manager:  manager,
+		logger:   logger.WithComponent("alert_digest"),
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins flushing buffered alerts in the background. Alerts buffered
+// before a restart are flushed as soon as quiet hours are over.
+func (ds *DigestScheduler) Start(ctx context.Context) {
+	ds.wg.Add(1)
+	go func() {
+		defer ds.wg.Done()
+
+		ticker := time.NewTicker(ds.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := ds.manager.FlushDigest(ctx); err != nil {
+				ds.logger.LogError(ctx, err, "Failed to flush alert digest")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ds.stopChan:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the digest scheduler and waits for it to finish
+func (ds *DigestScheduler) Stop() {
+	close(ds.stopChan)
+	ds.wg.Wait()
+}
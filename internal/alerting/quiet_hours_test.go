@@ -0,0 +1,235 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQuietHours(t *testing.T) {
+	quietHours, err := NewQuietHours(config.QuietHoursConfig{Enabled: false})
+	require.NoError(t, err)
+	assert.Nil(t, quietHours)
+	assert.False(t, quietHours.IsActive(time.Now()))
+	assert.False(t, quietHours.Suppresses("low"))
+
+	_, err = NewQuietHours(config.QuietHoursConfig{Enabled: true, Start: "bad", End: "07:00"})
+	assert.Error(t, err)
+
+	_, err = NewQuietHours(config.QuietHoursConfig{Enabled: true, Start: "22:00", End: "07:00", Timezone: "Nowhere/Nope"})
+	assert.Error(t, err)
+}
+
+func TestQuietHoursIsActive(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		at       string
+		expected bool
+	}{
+		{"overnight before start", "22:00", "07:00", "21:59", false},
+		{"overnight at start", "22:00", "07:00", "22:00", true},
+		{"overnight after midnight", "22:00", "07:00", "03:30", true},
+		{"overnight at end", "22:00", "07:00", "07:00", false},
+		{"daytime inside", "12:00", "13:00", "12:30", true},
+		{"daytime outside", "12:00", "13:00", "13:30", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quietHours, err := NewQuietHours(config.QuietHoursConfig{
+				Enabled:  true,
+				Start:    tt.start,
+				End:      tt.end,
+				Timezone: "UTC",
+			})
+			require.NoError(t, err)
+
+			at, err := time.Parse("15:04", tt.at)
+			require.NoError(t, err)
+			at = time.Date(2024, 1, 1, at.Hour(), at.Minute(), 0, 0, time.UTC)
+
+			assert.Equal(t, tt.expected, quietHours.IsActive(at))
+		})
+	}
+}
+
+func TestQuietHoursTimezone(t *testing.T) {
+	quietHours, err := NewQuietHours(config.QuietHoursConfig{
+		Enabled:  true,
+		Start:    "22:00",
+		End:      "07:00",
+		Timezone: "America/New_York",
+	})
+	require.NoError(t, err)
+
+	// 02:00 UTC is 21:00 in New York during winter
+	assert.False(t, quietHours.IsActive(time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)))
+	// 04:00 UTC is 23:00 in New York during winter
+	assert.True(t, quietHours.IsActive(time.Date(2024, 1, 15, 4, 0, 0, 0, time.UTC)))
+}
+
+func TestQuietHoursSuppresses(t *testing.T) {
+	quietHours, err := NewQuietHours(config.QuietHoursConfig{Enabled: true, Start: "22:00", End: "07:00"})
+	require.NoError(t, err)
+
+	assert.True(t, quietHours.Suppresses("low"))
+	assert.True(t, quietHours.Suppresses("medium"))
+	assert.True(t, quietHours.Suppresses("high"))
+	assert.False(t, quietHours.Suppresses("critical"))
+
+	quietHours, err = NewQuietHours(config.QuietHoursConfig{
+		Enabled:    true,
+		Start:      "22:00",
+		End:        "07:00",
+		Severities: []string{"low", "critical"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, quietHours.Suppresses("low"))
+	assert.False(t, quietHours.Suppresses("medium"))
+	assert.False(t, quietHours.Suppresses("critical"))
+}
+
+func newQuietHoursTestManager(t *testing.T, store storage.Storage, channel AlertChannel, now *time.Time) *DefaultAlertManager {
+	t.Helper()
+
+	cfg := &config.Config{
+		Alerting: config.AlertingConfig{
+			Enabled: true,
+			Rules: []config.AlertRuleConfig{
+				{
+					Name:     "all",
+					Severity: []string{"low", "medium", "high", "critical"},
+					Channels: []string{"test-channel"},
+				},
+			},
+			QuietHours: config.QuietHoursConfig{
+				Enabled:  true,
+				Start:    "22:00",
+				End:      "07:00",
+				Timezone: "UTC",
+			},
+		},
+	}
+
+	quietHours, err := NewQuietHours(cfg.Alerting.QuietHours)
+	require.NoError(t, err)
+
+	return &DefaultAlertManager{
+		config:     cfg,
+		storage:    store,
+		channels:   map[string]AlertChannel{"test-channel": channel},
+		quietHours: quietHours,
+		now:        func() time.Time { return *now },
+	}
+}
+
+func TestSendAlertDuringQuietHours(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	manager := newQuietHoursTestManager(t, store, mockChannel, &now)
+
+	endpoint := &storage.Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+
+	lowDrift := &storage.Drift{EndpointID: "test-endpoint", Severity: "low", Description: "Field added", DriftType: "field_added"}
+	require.NoError(t, store.SaveDrift(lowDrift))
+	lowDrift.ID = 1
+
+	criticalDrift := &storage.Drift{EndpointID: "test-endpoint", Severity: "critical", Description: "Field removed", DriftType: "field_removed"}
+	require.NoError(t, store.SaveDrift(criticalDrift))
+	criticalDrift.ID = 2
+
+	// Critical alerts are still delivered immediately
+	mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return msg.Severity == "critical"
+	})).Return(nil).Once()
+
+	require.NoError(t, manager.SendAlert(context.Background(), lowDrift, endpoint))
+	require.NoError(t, manager.SendAlert(context.Background(), criticalDrift, endpoint))
+	mockChannel.AssertExpectations(t)
+
+	buffered, err := store.GetAlerts(storage.AlertFilters{Status: string(AlertStatusBuffered)})
+	require.NoError(t, err)
+	require.Len(t, buffered, 1)
+	assert.Equal(t, int64(1), buffered[0].DriftID)
+
+	// Flushing while quiet hours are still active does nothing
+	require.NoError(t, manager.FlushDigest(context.Background()))
+	mockChannel.AssertExpectations(t)
+}
+
+func TestFlushDigestAfterQuietHours(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	manager := newQuietHoursTestManager(t, store, mockChannel, &now)
+
+	endpoint := &storage.Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	for i, severity := range []string{"low", "high"} {
+		drift := &storage.Drift{EndpointID: "test-endpoint", Severity: severity, Description: "Change", DriftType: "field_changed"}
+		require.NoError(t, store.SaveDrift(drift))
+		drift.ID = int64(i + 1)
+		require.NoError(t, manager.SendAlert(context.Background(), drift, endpoint))
+	}
+
+	// Simulate a restart: a fresh manager sharing the same storage picks up buffered alerts
+	now = time.Date(2024, 1, 2, 7, 30, 0, 0, time.UTC)
+	restarted := newQuietHoursTestManager(t, store, mockChannel, &now)
+
+	mockChannel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return len(msg.Changes) == 2 && msg.Severity == "high" && msg.Metadata["digest"] == true
+	})).Return(nil).Once()
+
+	require.NoError(t, restarted.FlushDigest(context.Background()))
+	mockChannel.AssertExpectations(t)
+
+	buffered, err := store.GetAlerts(storage.AlertFilters{Status: string(AlertStatusBuffered)})
+	require.NoError(t, err)
+	assert.Empty(t, buffered)
+
+	sent, err := store.GetAlerts(storage.AlertFilters{Status: string(AlertStatusSent)})
+	require.NoError(t, err)
+	assert.Len(t, sent, 2)
+
+	// A second flush has nothing left to send
+	require.NoError(t, restarted.FlushDigest(context.Background()))
+	mockChannel.AssertExpectations(t)
+}
+
+func TestFlushDigestKeepsAlertsOnFailure(t *testing.T) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	mockChannel := &MockAlertChannel{name: "test-channel", chanType: "test", enabled: true}
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	manager := newQuietHoursTestManager(t, store, mockChannel, &now)
+
+	endpoint := &storage.Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	drift := &storage.Drift{EndpointID: "test-endpoint", Severity: "medium", Description: "Change", DriftType: "field_changed"}
+	require.NoError(t, store.SaveDrift(drift))
+	drift.ID = 1
+	require.NoError(t, manager.SendAlert(context.Background(), drift, endpoint))
+
+	now = time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)
+	mockChannel.On("Send", mock.Anything, mock.Anything).Return(assert.AnError).Once()
+
+	err = manager.FlushDigest(context.Background())
+	assert.Error(t, err)
+
+	buffered, err := store.GetAlerts(storage.AlertFilters{Status: string(AlertStatusBuffered)})
+	require.NoError(t, err)
+	assert.Len(t, buffered, 1)
+}
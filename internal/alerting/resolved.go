@@ -0,0 +1,154 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// notifiesResolved reports whether resolved alerts are sent
+func (am *DefaultAlertManager) notifiesResolved() bool {
+	return am.config.Alerting.Enabled && am.config.Alerting.NotifyResolved
+}
+
+// maxAlertedDrifts bounds how many alerted, unresolved drifts of an endpoint
+// new drifts and recoveries are checked against
+const maxAlertedDrifts = 500
+
+// alertedDrifts returns the latest drifts of an endpoint that are not resolved
+// and that an alert was sent about, newest first, so that only drifts someone
+// was told about get a resolved alert
+func (am *DefaultAlertManager) alertedDrifts(endpointID string) ([]*storage.Drift, error) {
+	drifts, err := am.storage.GetAlertedUnresolvedDrifts(endpointID, maxAlertedDrifts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerted drifts: %w", err)
+	}
+	return drifts, nil
+}
+
+// revertedDrift returns the alerted, unresolved drift a new drift undoes: the
+// latest of the same field path whose change the new drift makes in reverse,
+// so that the field is back to its value before it. It returns nil if there is
+// none. Drifts without values, such as performance changes, undo nothing.
+func revertedDrift(drift *storage.Drift, alerted []*storage.Drift) *storage.Drift {
+	if drift.BeforeValue == drift.AfterValue {
+		return nil
+	}
+
+	for _, candidate := range alerted {
+		if candidate.ResolvedAt == nil &&
+			candidate.FieldPath == drift.FieldPath &&
+			candidate.BeforeValue == drift.AfterValue &&
+			candidate.AfterValue == drift.BeforeValue {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// resolveDrift marks an alerted drift resolved and sends a resolved alert
+// through the channels its rules route it to
+func (am *DefaultAlertManager) resolveDrift(ctx context.Context, drift *storage.Drift, endpoint *storage.Endpoint, resolvedAt time.Time) error {
+	if err := am.storage.ResolveDrift(drift.ID, resolvedAt); err != nil {
+		return err
+	}
+	drift.ResolvedAt = &resolvedAt
+
+	applicableRules := am.findApplicableRules(drift, endpoint)
+	message := am.createResolvedMessage(drift, endpoint, resolvedAt)
+
+	// Resolved alerts need no action, so during quiet hours they are dropped
+	// for the severities quiet hours hold back; the drift stays resolved
+	if am.quietHours.Suppresses(message.Severity) && am.quietHours.IsActive(am.currentTime()) {
+		return nil
+	}
+
+	delivered := am.newDeliverySet()
+	for _, rule := range applicableRules {
+		for _, channelName := range rule.Channels {
+			channel, exists := am.channels[channelName]
+			if !exists || !channel.IsEnabled() || !delivered.claim(drift, channelName) {
+				continue
+			}
+
+			alert := &storage.Alert{
+				DriftID:     drift.ID,
+				AlertType:   channel.GetType(),
+				ChannelName: channelName,
+				SentAt:      time.Now(),
+				Status:      string(AlertStatusPending),
+			}
+			if err := am.deliverAlert(ctx, channel, message, alert); err != nil {
+				return fmt.Errorf("failed to send resolved alert via %s channel '%s': %w",
+					channel.GetType(), channelName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createResolvedMessage creates the resolved alert of a drift
+func (am *DefaultAlertManager) createResolvedMessage(drift *storage.Drift, endpoint *storage.Endpoint, resolvedAt time.Time) *AlertMessage {
+	message := am.createAlertMessage(drift, endpoint)
+	message.Resolved = true
+	message.DetectedAt = resolvedAt
+
+	if drift.DriftType == DriftTypeEndpointDown {
+		message.Title = fmt.Sprintf("Endpoint Recovered: %s", endpoint.URL)
+		message.Summary = fmt.Sprintf("responding successfully again after being down since %s",
+			drift.DetectedAt.Format("2006-01-02 15:04:05 MST"))
+	} else {
+		message.Title = fmt.Sprintf("API Drift Resolved: %s", endpoint.URL)
+		message.Summary = fmt.Sprintf("%s is back to its value before the drift detected at %s",
+			drift.FieldPath, drift.DetectedAt.Format("2006-01-02 15:04:05 MST"))
+	}
+
+	return message
+}
+
+// resolveOutage resolves the open endpoint_down drifts of an endpoint that has
+// responded successfully since they were recorded, as of its first successful
+// run among runs
+func (am *DefaultAlertManager) resolveOutage(ctx context.Context, endpointID string, runs []*storage.MonitoringRun) error {
+	alerted, err := am.alertedDrifts(endpointID)
+	if err != nil {
+		return err
+	}
+
+	var endpoint *storage.Endpoint
+	for _, drift := range alerted {
+		if drift.DriftType != DriftTypeEndpointDown {
+			continue
+		}
+		recoveredAt := firstSuccessAfter(runs, drift.DetectedAt)
+		if recoveredAt.IsZero() {
+			continue
+		}
+
+		if endpoint == nil {
+			if endpoint, err = am.storage.GetEndpoint(endpointID); err != nil {
+				return fmt.Errorf("failed to get endpoint: %w", err)
+			}
+		}
+		if err := am.resolveDrift(ctx, drift, endpoint, recoveredAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// firstSuccessAfter returns when the first successful run after a time was
+// checked, or the zero time if none was
+func firstSuccessAfter(runs []*storage.MonitoringRun, after time.Time) time.Time {
+	var first time.Time
+	for _, run := range runs {
+		if run.Succeeded() && run.Timestamp.After(after) && (first.IsZero() || run.Timestamp.Before(first)) {
+			first = run.Timestamp
+		}
+	}
+	return first
+}
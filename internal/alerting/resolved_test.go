@@ -0,0 +1,128 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newResolvedTestManager returns a manager sending resolved alerts through a
+// single channel that every rule routes to
+func newResolvedTestManager(t *testing.T) (*DefaultAlertManager, storage.Storage, *MockAlertChannel) {
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	channel := &MockAlertChannel{name: "ops", chanType: "test", enabled: true}
+	cfg := &config.Config{
+		Endpoints: []config.EndpointConfig{{ID: "prices", URL: "https://api.example.com/prices", Enabled: true}},
+		Alerting: config.AlertingConfig{
+			Enabled:        true,
+			NotifyResolved: true,
+			Rules: []config.AlertRuleConfig{
+				{Name: "all", Severity: []string{"low", "medium", "high", "critical"}, Channels: []string{"ops"}},
+			},
+			Liveness: config.LivenessConfig{Enabled: true, Window: time.Hour},
+			Retry:    config.AlertRetryConfig{MaxAttempts: 1},
+		},
+	}
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "prices", URL: "https://api.example.com/prices", Method: "GET"}))
+
+	manager := &DefaultAlertManager{
+		config:   cfg,
+		storage:  store,
+		channels: map[string]AlertChannel{"ops": channel},
+	}
+	return manager, store, channel
+}
+
+// priceChange is a check finding the price changed from one value to another
+func priceChange(from, to string) *drift.DiffResult {
+	return &drift.DiffResult{
+		HasChanges: true,
+		DataChanges: []drift.DataChange{
+			{Path: "$.price", OldValue: from, NewValue: to, ChangeType: drift.ChangeTypeValueChange, Severity: drift.SeverityHigh, Description: "price changed"},
+		},
+	}
+}
+
+func TestProcessDriftSendsResolvedAlert(t *testing.T) {
+	manager, store, channel := newResolvedTestManager(t)
+	endpoint, err := store.GetEndpoint("prices")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	channel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return !msg.Resolved && msg.Title == "API Drift Detected: https://api.example.com/prices"
+	})).Return(nil).Once()
+	require.NoError(t, manager.ProcessDrift(ctx, priceChange("10", "12"), endpoint))
+
+	// An unrelated change back and forth resolves nothing
+	channel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return !msg.Resolved
+	})).Return(nil).Once()
+	require.NoError(t, manager.ProcessDrift(ctx, priceChange("12", "11"), endpoint))
+
+	channel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return msg.Resolved && msg.Title == "API Drift Resolved: https://api.example.com/prices" && msg.Severity == "high"
+	})).Return(nil).Once()
+	require.NoError(t, manager.ProcessDrift(ctx, priceChange("11", "12"), endpoint))
+	channel.AssertExpectations(t)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "prices"})
+	require.NoError(t, err)
+	require.Len(t, drifts, 3)
+
+	resolved := map[string]bool{}
+	for _, d := range drifts {
+		resolved[d.BeforeValue+"->"+d.AfterValue] = d.ResolvedAt != nil
+	}
+	assert.Equal(t, map[string]bool{"10->12": false, "12->11": true, "11->12": true}, resolved)
+}
+
+func TestCheckLivenessSendsRecoveredAlert(t *testing.T) {
+	manager, store, channel := newResolvedTestManager(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	for i := 3; i >= 1; i-- {
+		require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+			EndpointID:     "prices",
+			Timestamp:      now.Add(-time.Duration(i) * 10 * time.Minute),
+			ResponseStatus: 503,
+		}))
+	}
+
+	channel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return !msg.Resolved && msg.Title == "Endpoint Down: https://api.example.com/prices"
+	})).Return(nil).Once()
+	require.NoError(t, manager.CheckLiveness(ctx))
+
+	recoveredAt := time.Now().Add(time.Minute)
+	require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "prices",
+		Timestamp:      recoveredAt,
+		ResponseStatus: 200,
+	}))
+
+	channel.On("Send", mock.Anything, mock.MatchedBy(func(msg *AlertMessage) bool {
+		return msg.Resolved && msg.Title == "Endpoint Recovered: https://api.example.com/prices" && msg.DetectedAt.Equal(recoveredAt)
+	})).Return(nil).Once()
+	require.NoError(t, manager.CheckLiveness(ctx))
+
+	// The recovery is only announced once
+	require.NoError(t, manager.CheckLiveness(ctx))
+	channel.AssertExpectations(t)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "prices"})
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	require.NotNil(t, drifts[0].ResolvedAt)
+	assert.True(t, drifts[0].ResolvedAt.Equal(recoveredAt))
+}
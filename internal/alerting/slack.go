@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"text/template"
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
@@ -20,6 +21,7 @@ type SlackChannel struct {
 	iconEmoji  string
 	enabled    bool
 	client     *http.Client
+	template   *template.Template // replaces the built-in format when set
 }
 
 // SlackMessage represents a Slack webhook message
@@ -59,6 +61,11 @@ func NewSlackChannel(channelConfig config.AlertChannelConfig) (AlertChannel, err
 		return nil, fmt.Errorf("webhook_url is required for Slack channel")
 	}
 
+	messageTemplate, err := channelTemplate(channelConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	channel := &SlackChannel{
 		name:       channelConfig.Name,
 		webhookURL: webhookURL,
@@ -66,6 +73,7 @@ func NewSlackChannel(channelConfig config.AlertChannelConfig) (AlertChannel, err
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		template: messageTemplate,
 	}
 
 	// Optional settings
@@ -89,6 +97,14 @@ func NewSlackChannel(channelConfig config.AlertChannelConfig) (AlertChannel, err
 // Send sends an alert message to Slack
 func (sc *SlackChannel) Send(ctx context.Context, message *AlertMessage) error {
 	slackMessage := sc.formatMessage(message)
+	if sc.template != nil {
+		text, err := renderMessage(sc.template, message)
+		if err != nil {
+			return err
+		}
+		slackMessage.Text = text
+		slackMessage.Blocks = nil
+	}
 
 	payload, err := json.Marshal(slackMessage)
 	if err != nil {
@@ -109,7 +125,7 @@ func (sc *SlackChannel) Send(ctx context.Context, message *AlertMessage) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+		return &DeliveryStatusError{Service: "Slack webhook", StatusCode: resp.StatusCode}
 	}
 
 	return nil
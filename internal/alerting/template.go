@@ -0,0 +1,80 @@
+package alerting
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+)
+
+// DefaultMessageTemplate renders an alert as plain text. It is the text sent to
+// webhooks whose channel has no message_template, and a starting point for
+// custom templates.
+const DefaultMessageTemplate = `[{{.Severity}}] {{.Title}}
+{{.Summary}}
+Endpoint: {{.EndpointID}} ({{.EndpointURL}})
+Detected: {{.DetectedAt.Format "2006-01-02 15:04:05 MST"}}
+{{range .Changes}}
+- {{.Type}} at {{.Path}}{{if .Breaking}} (breaking){{end}}{{with .OldValue}}
+  before: {{.}}{{end}}{{with .NewValue}}
+  after: {{.}}{{end}}{{end}}`
+
+// MessageTemplateData is what a channel's message_template is executed with.
+// Besides the fields of the alert, such as .EndpointID, .EndpointURL,
+// .Severity and .Changes, it holds the type, path and values of the first
+// change, which for an alert about a single drift is the drift itself.
+type MessageTemplateData struct {
+	*AlertMessage
+	Type   string
+	Path   string
+	Before string
+	After  string
+}
+
+// parseMessageTemplate parses a message template
+func parseMessageTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// channelTemplate parses the message_template of a channel, returning nil when
+// the channel has none and keeps its built-in format
+func channelTemplate(channelConfig config.AlertChannelConfig) (*template.Template, error) {
+	if strings.TrimSpace(channelConfig.MessageTemplate) == "" {
+		return nil, nil
+	}
+	return parseMessageTemplate(channelConfig.Name, channelConfig.MessageTemplate)
+}
+
+// renderMessage executes a message template for an alert
+func renderMessage(tmpl *template.Template, message *AlertMessage) (string, error) {
+	data := MessageTemplateData{AlertMessage: message}
+	if len(message.Changes) > 0 {
+		change := message.Changes[0]
+		data.Type = change.Type
+		data.Path = change.Path
+		data.Before = formatChangeValue(change.OldValue)
+		data.After = formatChangeValue(change.NewValue)
+	}
+
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+	return text.String(), nil
+}
+
+// formatChangeValue renders the old or new value of a change; nil renders empty
+func formatChangeValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// defaultMessageTemplate is DefaultMessageTemplate, parsed once
+var defaultMessageTemplate = template.Must(parseMessageTemplate("default", DefaultMessageTemplate))
@@ -0,0 +1,134 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// templateTestMessage is an alert about a single price change
+func templateTestMessage() *AlertMessage {
+	return &AlertMessage{
+		Title:       "API Drift Detected: https://api.example.com/prices",
+		Summary:     "Value changed",
+		Severity:    "high",
+		EndpointID:  "prices",
+		EndpointURL: "https://api.example.com/prices",
+		DetectedAt:  time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+		Changes: []ChangeDetail{{
+			Type:     "value_changed",
+			Path:     "$.data.price",
+			Severity: "high",
+			Breaking: true,
+			OldValue: "10",
+			NewValue: "12",
+		}},
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	t.Run("default template", func(t *testing.T) {
+		text, err := renderMessage(defaultMessageTemplate, templateTestMessage())
+		require.NoError(t, err)
+		assert.Equal(t, "[high] API Drift Detected: https://api.example.com/prices\n"+
+			"Value changed\n"+
+			"Endpoint: prices (https://api.example.com/prices)\n"+
+			"Detected: 2024-03-10 12:00:00 UTC\n\n"+
+			"- value_changed at $.data.price (breaking)\n"+
+			"  before: 10\n"+
+			"  after: 12", text)
+	})
+
+	t.Run("first change shortcuts", func(t *testing.T) {
+		tmpl, err := parseMessageTemplate("ops", "{{.Severity}}: {{.Path}} on {{.EndpointURL}} went from {{.Before}} to {{.After}} - runbook https://runbooks.example.com/{{.EndpointID}}")
+		require.NoError(t, err)
+
+		text, err := renderMessage(tmpl, templateTestMessage())
+		require.NoError(t, err)
+		assert.Equal(t, "high: $.data.price on https://api.example.com/prices went from 10 to 12 - runbook https://runbooks.example.com/prices", text)
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		tmpl, err := parseMessageTemplate("ops", "{{.Unknown}}")
+		require.NoError(t, err)
+
+		_, err = renderMessage(tmpl, templateTestMessage())
+		assert.ErrorContains(t, err, "failed to render message template")
+	})
+}
+
+func TestChannelsRejectInvalidMessageTemplate(t *testing.T) {
+	channelConfig := config.AlertChannelConfig{
+		Name:            "ops",
+		Enabled:         true,
+		MessageTemplate: "{{.Severity",
+		Settings: map[string]interface{}{
+			"webhook_url": "https://hooks.example.com/ops",
+			"url":         "https://hooks.example.com/ops",
+		},
+	}
+
+	_, err := NewSlackChannel(channelConfig)
+	assert.ErrorContains(t, err, "invalid message template")
+	_, err = NewDiscordChannel(channelConfig)
+	assert.ErrorContains(t, err, "invalid message template")
+	_, err = NewWebhookChannel(channelConfig)
+	assert.ErrorContains(t, err, "invalid message template")
+}
+
+func TestSlackChannelSendsMessageTemplate(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	channel, err := NewSlackChannel(config.AlertChannelConfig{
+		Name:            "ops",
+		Enabled:         true,
+		MessageTemplate: "OPS-{{.EndpointID}}: {{.Path}} changed",
+		Settings:        map[string]interface{}{"webhook_url": server.URL, "channel": "#ops"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, channel.Send(context.Background(), templateTestMessage()))
+	assert.Equal(t, "OPS-prices: $.data.price changed", received.Text)
+	assert.Empty(t, received.Blocks, "the template replaces the built-in blocks")
+	assert.Equal(t, "#ops", received.Channel)
+}
+
+func TestWebhookPayloadText(t *testing.T) {
+	var received WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	channelConfig := config.AlertChannelConfig{
+		Name:     "hook",
+		Enabled:  true,
+		Settings: map[string]interface{}{"url": server.URL},
+	}
+
+	channel, err := NewWebhookChannel(channelConfig)
+	require.NoError(t, err)
+	require.NoError(t, channel.Send(context.Background(), templateTestMessage()))
+	assert.True(t, strings.HasPrefix(received.Text, "[high] API Drift Detected"), "the default template renders the text")
+	assert.Equal(t, "prices", received.Alert.EndpointID)
+
+	channelConfig.MessageTemplate = "{{.Type}} {{.Before}} -> {{.After}}"
+	channel, err = NewWebhookChannel(channelConfig)
+	require.NoError(t, err)
+	require.NoError(t, channel.Send(context.Background(), templateTestMessage()))
+	assert.Equal(t, "value_changed 10 -> 12", received.Text)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"text/template"
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
@@ -13,17 +14,19 @@ import (
 
 // WebhookChannel implements AlertChannel for generic webhook integration
 type WebhookChannel struct {
-	name    string
-	url     string
-	method  string
-	headers map[string]string
-	enabled bool
-	client  *http.Client
+	name     string
+	url      string
+	method   string
+	headers  map[string]string
+	enabled  bool
+	client   *http.Client
+	template *template.Template // renders the text of the payload
 }
 
 // WebhookPayload represents the payload sent to webhook endpoints
 type WebhookPayload struct {
 	Alert     *AlertMessage          `json:"alert"`
+	Text      string                 `json:"text"` // the alert rendered by the channel's message template
 	Timestamp time.Time              `json:"timestamp"`
 	Source    string                 `json:"source"`
 	Version   string                 `json:"version"`
@@ -39,6 +42,14 @@ func NewWebhookChannel(channelConfig config.AlertChannelConfig) (AlertChannel, e
 		return nil, fmt.Errorf("url is required for webhook channel")
 	}
 
+	messageTemplate, err := channelTemplate(channelConfig)
+	if err != nil {
+		return nil, err
+	}
+	if messageTemplate == nil {
+		messageTemplate = defaultMessageTemplate
+	}
+
 	channel := &WebhookChannel{
 		name:    channelConfig.Name,
 		url:     url,
@@ -48,6 +59,7 @@ func NewWebhookChannel(channelConfig config.AlertChannelConfig) (AlertChannel, e
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		template: messageTemplate,
 	}
 
 	// Optional settings
@@ -76,8 +88,14 @@ func NewWebhookChannel(channelConfig config.AlertChannelConfig) (AlertChannel, e
 
 // Send sends an alert message to the webhook endpoint
 func (wc *WebhookChannel) Send(ctx context.Context, message *AlertMessage) error {
+	text, err := renderMessage(wc.template, message)
+	if err != nil {
+		return err
+	}
+
 	payload := &WebhookPayload{
 		Alert:     message,
+		Text:      text,
 		Timestamp: time.Now(),
 		Source:    "driftwatch",
 		Version:   "1.0.0", // This could be made configurable
@@ -110,7 +128,7 @@ func (wc *WebhookChannel) Send(ctx context.Context, message *AlertMessage) error
 
 	// Check for successful response (2xx status codes)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+		return &DeliveryStatusError{Service: "webhook endpoint", StatusCode: resp.StatusCode}
 	}
 
 	return nil
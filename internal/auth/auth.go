@@ -61,6 +61,9 @@ func (m *Manager) CreateAuthenticator(authConfig *config.AuthConfig) (Authentica
 				WithSeverity(errors.SeverityHigh).
 				WithGuidance("Provide bearer token configuration")
 		}
+		if authConfig.Bearer.TokenFile != "" {
+			return NewBearerAuthFromFile(authConfig.Bearer.TokenFile), nil
+		}
 		return NewBearerAuth(authConfig.Bearer.Token), nil
 	case config.AuthTypeBasic:
 		if authConfig.Basic == nil {
@@ -68,6 +71,9 @@ func (m *Manager) CreateAuthenticator(authConfig *config.AuthConfig) (Authentica
 				WithSeverity(errors.SeverityHigh).
 				WithGuidance("Provide username and password for basic authentication")
 		}
+		if authConfig.Basic.PasswordFile != "" {
+			return NewBasicAuthWithPasswordFile(authConfig.Basic.Username, authConfig.Basic.PasswordFile), nil
+		}
 		return NewBasicAuth(authConfig.Basic.Username, authConfig.Basic.Password), nil
 	case config.AuthTypeAPIKey:
 		if authConfig.APIKey == nil {
@@ -75,6 +81,9 @@ func (m *Manager) CreateAuthenticator(authConfig *config.AuthConfig) (Authentica
 				WithSeverity(errors.SeverityHigh).
 				WithGuidance("Provide header name and API key value")
 		}
+		if authConfig.APIKey.ValueFile != "" {
+			return NewAPIKeyAuthFromFile(authConfig.APIKey.Header, authConfig.APIKey.ValueFile), nil
+		}
 		return NewAPIKeyAuth(authConfig.APIKey.Header, authConfig.APIKey.Value), nil
 	case config.AuthTypeOAuth2:
 		if authConfig.OAuth2 == nil {
@@ -109,27 +118,38 @@ func (a *NoneAuth) GetType() config.AuthType {
 
 // BearerAuth represents Bearer token authentication
 type BearerAuth struct {
-	token string
+	token secret
 }
 
 // NewBearerAuth creates a new Bearer token authenticator
 func NewBearerAuth(token string) *BearerAuth {
-	return &BearerAuth{token: token}
+	return &BearerAuth{token: secret{value: token}}
+}
+
+// NewBearerAuthFromFile creates a Bearer token authenticator that reads the token
+// from a file on every request
+func NewBearerAuthFromFile(tokenFile string) *BearerAuth {
+	return &BearerAuth{token: secret{file: tokenFile}}
 }
 
 func (a *BearerAuth) ApplyAuth(req *http.Request) error {
-	if a.token == "" {
+	token, err := a.token.resolve()
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
 		return errors.NewError(errors.ErrorTypeAuth, "AUTH_TOKEN_EMPTY", "bearer token is empty").
 			WithSeverity(errors.SeverityHigh).
 			WithGuidance("Provide a valid bearer token")
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.token))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	return nil
 }
 
 func (a *BearerAuth) Validate() error {
-	if a.token == "" {
+	if !a.token.isSet() {
 		return errors.NewError(errors.ErrorTypeAuth, "AUTH_TOKEN_EMPTY", "bearer token is required").
 			WithSeverity(errors.SeverityHigh).
 			WithGuidance("Provide a valid bearer token")
@@ -144,14 +164,23 @@ func (a *BearerAuth) GetType() config.AuthType {
 // BasicAuth represents HTTP Basic authentication
 type BasicAuth struct {
 	username string
-	password string
+	password secret
 }
 
 // NewBasicAuth creates a new Basic authentication authenticator
 func NewBasicAuth(username, password string) *BasicAuth {
 	return &BasicAuth{
 		username: username,
-		password: password,
+		password: secret{value: password},
+	}
+}
+
+// NewBasicAuthWithPasswordFile creates a Basic authentication authenticator that
+// reads the password from a file on every request
+func NewBasicAuthWithPasswordFile(username, passwordFile string) *BasicAuth {
+	return &BasicAuth{
+		username: username,
+		password: secret{file: passwordFile},
 	}
 }
 
@@ -162,7 +191,12 @@ func (a *BasicAuth) ApplyAuth(req *http.Request) error {
 			WithGuidance("Provide a valid username for basic authentication")
 	}
 
-	credentials := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", a.username, a.password)))
+	password, err := a.password.resolve()
+	if err != nil {
+		return err
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", a.username, password)))
 	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", credentials))
 	return nil
 }
@@ -183,14 +217,23 @@ func (a *BasicAuth) GetType() config.AuthType {
 // APIKeyAuth represents API key authentication via custom headers
 type APIKeyAuth struct {
 	header string
-	value  string
+	value  secret
 }
 
 // NewAPIKeyAuth creates a new API key authenticator
 func NewAPIKeyAuth(header, value string) *APIKeyAuth {
 	return &APIKeyAuth{
 		header: header,
-		value:  value,
+		value:  secret{value: value},
+	}
+}
+
+// NewAPIKeyAuthFromFile creates an API key authenticator that reads the key from
+// a file on every request
+func NewAPIKeyAuthFromFile(header, valueFile string) *APIKeyAuth {
+	return &APIKeyAuth{
+		header: header,
+		value:  secret{file: valueFile},
 	}
 }
 
@@ -201,13 +244,18 @@ func (a *APIKeyAuth) ApplyAuth(req *http.Request) error {
 			WithGuidance("Provide a valid header name for API key authentication")
 	}
 
-	if a.value == "" {
+	value, err := a.value.resolve()
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
 		return errors.NewError(errors.ErrorTypeAuth, "AUTH_VALUE_EMPTY", "API key value is empty").
 			WithSeverity(errors.SeverityHigh).
 			WithGuidance("Provide a valid API key value")
 	}
 
-	req.Header.Set(a.header, a.value)
+	req.Header.Set(a.header, value)
 	return nil
 }
 
@@ -218,7 +266,7 @@ func (a *APIKeyAuth) Validate() error {
 			WithGuidance("Provide a valid header name (e.g., 'X-API-Key')")
 	}
 
-	if a.value == "" {
+	if !a.value.isSet() {
 		return errors.NewError(errors.ErrorTypeAuth, "AUTH_VALUE_EMPTY", "API key value is required").
 			WithSeverity(errors.SeverityHigh).
 			WithGuidance("Provide a valid API key value")
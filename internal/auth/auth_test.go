@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -169,6 +171,55 @@ func TestBearerAuth(t *testing.T) {
 	})
 }
 
+func TestSecretFilesAreReadPerRequest(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("first-secret\n"), 0600))
+
+	t.Run("bearer token file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(secretFile, []byte("first-secret\n"), 0600))
+		auth := NewBearerAuthFromFile(secretFile)
+		require.NoError(t, auth.Validate())
+
+		req, err := http.NewRequest("GET", "https://example.com", nil)
+		require.NoError(t, err)
+		require.NoError(t, auth.ApplyAuth(req))
+		assert.Equal(t, "Bearer first-secret", req.Header.Get("Authorization"))
+
+		require.NoError(t, os.WriteFile(secretFile, []byte("rotated-secret\n"), 0600))
+		require.NoError(t, auth.ApplyAuth(req))
+		assert.Equal(t, "Bearer rotated-secret", req.Header.Get("Authorization"))
+	})
+
+	t.Run("basic password file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(secretFile, []byte("first-secret\n"), 0600))
+		auth := NewBasicAuthWithPasswordFile("testuser", secretFile)
+
+		req, err := http.NewRequest("GET", "https://example.com", nil)
+		require.NoError(t, err)
+		require.NoError(t, auth.ApplyAuth(req))
+		expected := base64.StdEncoding.EncodeToString([]byte("testuser:first-secret"))
+		assert.Equal(t, "Basic "+expected, req.Header.Get("Authorization"))
+	})
+
+	t.Run("api key value file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(secretFile, []byte("first-secret\n"), 0600))
+		auth := NewAPIKeyAuthFromFile("X-API-Key", secretFile)
+
+		req, err := http.NewRequest("GET", "https://example.com", nil)
+		require.NoError(t, err)
+		require.NoError(t, auth.ApplyAuth(req))
+		assert.Equal(t, "first-secret", req.Header.Get("X-API-Key"))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		auth := NewBearerAuthFromFile(filepath.Join(t.TempDir(), "missing"))
+
+		req, err := http.NewRequest("GET", "https://example.com", nil)
+		require.NoError(t, err)
+		assert.Error(t, auth.ApplyAuth(req))
+	})
+}
+
 func TestBasicAuth(t *testing.T) {
 	t.Run("valid credentials", func(t *testing.T) {
 		auth := NewBasicAuth("testuser", "testpass")
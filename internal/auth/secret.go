@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/errors"
+)
+
+// secret is a credential given inline or stored in a file. File-based secrets
+// are read on every use so that rotated secrets, such as mounted Kubernetes
+// secrets, take effect without a restart.
+type secret struct {
+	value string
+	file  string
+}
+
+// resolve returns the current value of the secret. Surrounding whitespace,
+// including the trailing newline most secret files end with, is trimmed.
+func (s secret) resolve() (string, error) {
+	if s.file == "" {
+		return s.value, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(s.file))
+	if err != nil {
+		return "", errors.WrapError(err, errors.ErrorTypeAuth, "AUTH_SECRET_FILE_UNREADABLE",
+			fmt.Sprintf("failed to read secret file %s", s.file)).
+			WithSeverity(errors.SeverityHigh).
+			WithGuidance("Check that the secret file exists and is readable")
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// isSet reports whether the secret has an inline value or a file to read from
+func (s secret) isSet() bool {
+	return s.value != "" || s.file != ""
+}
@@ -0,0 +1,141 @@
+// Package collection reads the requests of API client collections, such as
+// Postman collections and Insomnia exports, so that they can be monitored
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+)
+
+// Collection is the requests read from a collection file
+type Collection struct {
+	Name     string
+	Format   string // "postman" or "insomnia"
+	Requests []Request
+}
+
+// Request is a single request of a collection, with its variables resolved
+type Request struct {
+	Name    string
+	Folder  string // Folders the request is nested in, joined by " / "
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+	Auth    *config.AuthConfig
+
+	// Unresolved lists the variables used by the request that have no value;
+	// they are left in place as {{name}}
+	Unresolved []string
+
+	// Warnings describe parts of the request that could not be carried over,
+	// such as unsupported body modes or authentication types
+	Warnings []string
+}
+
+// variablePattern matches {{name}} variable references
+var variablePattern = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+
+// Parse reads a Postman collection (v2.0 or v2.1) or an Insomnia export (v4).
+// Variables are resolved from the collection, or the environments of an
+// Insomnia export, and then from vars, which take precedence.
+func Parse(data []byte, vars map[string]string) (*Collection, error) {
+	var probe struct {
+		Type   string          `json:"_type"`
+		Format int             `json:"__export_format"`
+		Info   json.RawMessage `json:"info"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse collection: %w", err)
+	}
+
+	switch {
+	case probe.Info != nil:
+		return parsePostman(data, vars)
+	case probe.Type == "export":
+		if probe.Format != 4 {
+			return nil, fmt.Errorf("unsupported Insomnia export format %d (supported: 4)", probe.Format)
+		}
+		return parseInsomnia(data, vars)
+	default:
+		return nil, fmt.Errorf("not a Postman collection or Insomnia export")
+	}
+}
+
+// resolver substitutes variable references
+type resolver struct {
+	values     map[string]string
+	unresolved map[string]bool
+
+	// prefix is an optional prefix of variable references, such as the "_."
+	// of Insomnia's {{ _.name }}
+	prefix string
+}
+
+// newResolver returns a resolver over defaults overridden by vars
+func newResolver(defaults, vars map[string]string) *resolver {
+	values := make(map[string]string, len(defaults)+len(vars))
+	for name, value := range defaults {
+		values[name] = value
+	}
+	for name, value := range vars {
+		values[name] = value
+	}
+	return &resolver{values: values}
+}
+
+// resolve substitutes the variables of s, recording those without a value.
+// Values may themselves reference variables, up to a few levels deep.
+func (r *resolver) resolve(s string) string {
+	for depth := 0; depth < 5 && strings.Contains(s, "{{"); depth++ {
+		replaced := variablePattern.ReplaceAllStringFunc(s, func(ref string) string {
+			name := strings.TrimPrefix(variablePattern.FindStringSubmatch(ref)[1], r.prefix)
+			if value, ok := r.values[name]; ok {
+				return value
+			}
+			return ref
+		})
+		if replaced == s {
+			break
+		}
+		s = replaced
+	}
+
+	for _, match := range variablePattern.FindAllStringSubmatch(s, -1) {
+		if r.unresolved == nil {
+			r.unresolved = make(map[string]bool)
+		}
+		r.unresolved[strings.TrimPrefix(match[1], r.prefix)] = true
+	}
+	return s
+}
+
+// takeUnresolved returns the variables found unresolved since the last call,
+// sorted
+func (r *resolver) takeUnresolved() []string {
+	names := make([]string, 0, len(r.unresolved))
+	for name := range r.unresolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	r.unresolved = nil
+	return names
+}
+
+// withAPIKeyQuery returns rawURL with an API key passed as a query parameter
+func withAPIKeyQuery(rawURL, name, value string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set(name, value)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
@@ -0,0 +1,227 @@
+package collection
+
+import (
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const postmanCollection21 = `{
+  "info": {"name": "Users API", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "variable": [
+    {"key": "baseUrl", "value": "https://{{host}}/v1"},
+    {"key": "host", "value": "api.example.com"},
+    {"key": "token", "value": "secret"}
+  ],
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+  "item": [
+    {
+      "name": "Users",
+      "item": [
+        {
+          "name": "List users",
+          "request": {
+            "method": "GET",
+            "header": [
+              {"key": "Accept", "value": "application/json"},
+              {"key": "X-Debug", "value": "1", "disabled": true}
+            ],
+            "url": {"raw": "{{baseUrl}}/users?page=1", "host": ["{{baseUrl}}"], "path": ["users"]}
+          }
+        },
+        {
+          "name": "Create user",
+          "request": {
+            "method": "post",
+            "auth": {"type": "basic", "basic": [
+              {"key": "username", "value": "admin"},
+              {"key": "password", "value": "{{password}}"}
+            ]},
+            "body": {"mode": "raw", "raw": "{\"name\": \"{{name}}\"}", "options": {"raw": {"language": "json"}}},
+            "url": "{{baseUrl}}/users"
+          }
+        }
+      ]
+    },
+    {
+      "name": "Login",
+      "request": {
+        "method": "POST",
+        "auth": {"type": "noauth"},
+        "body": {"mode": "urlencoded", "urlencoded": [
+          {"key": "user", "value": "admin"},
+          {"key": "skip", "value": "x", "disabled": true}
+        ]},
+        "url": "{{baseUrl}}/login"
+      }
+    },
+    {
+      "name": "Search",
+      "request": {
+        "method": "GET",
+        "auth": {"type": "apikey", "apikey": {"key": "api_key", "value": "k", "in": "query"}},
+        "url": "{{baseUrl}}/search?q=a"
+      }
+    },
+    {
+      "name": "Upload",
+      "request": {
+        "method": "PUT",
+        "auth": {"type": "oauth2"},
+        "body": {"mode": "formdata", "formdata": []},
+        "url": "{{baseUrl}}/upload"
+      }
+    }
+  ]
+}`
+
+func TestParsePostman(t *testing.T) {
+	parsed, err := Parse([]byte(postmanCollection21), map[string]string{"name": "Ada"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Users API", parsed.Name)
+	assert.Equal(t, "postman", parsed.Format)
+	require.Len(t, parsed.Requests, 5)
+
+	list := parsed.Requests[0]
+	assert.Equal(t, "List users", list.Name)
+	assert.Equal(t, "Users", list.Folder)
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "https://api.example.com/v1/users?page=1", list.URL)
+	assert.Equal(t, map[string]string{"Accept": "application/json"}, list.Headers)
+	assert.Equal(t, &config.AuthConfig{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "secret"}}, list.Auth)
+	assert.Empty(t, list.Unresolved)
+
+	create := parsed.Requests[1]
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, `{"name": "Ada"}`, create.Body)
+	assert.Equal(t, "application/json", create.Headers["Content-Type"])
+	assert.Equal(t, config.AuthTypeBasic, create.Auth.Type)
+	assert.Equal(t, "admin", create.Auth.Basic.Username)
+	assert.Equal(t, []string{"password"}, create.Unresolved)
+
+	login := parsed.Requests[2]
+	assert.Equal(t, "", login.Folder)
+	assert.Nil(t, login.Auth)
+	assert.Equal(t, "user=admin", login.Body)
+	assert.Equal(t, "application/x-www-form-urlencoded", login.Headers["Content-Type"])
+
+	search := parsed.Requests[3]
+	assert.Nil(t, search.Auth)
+	assert.Equal(t, "https://api.example.com/v1/search?api_key=k&q=a", search.URL)
+
+	upload := parsed.Requests[4]
+	assert.Empty(t, upload.Body)
+	assert.Nil(t, upload.Auth)
+	assert.Len(t, upload.Warnings, 2)
+}
+
+func TestParsePostmanVarsOverrideCollectionVariables(t *testing.T) {
+	parsed, err := Parse([]byte(postmanCollection21), map[string]string{"host": "staging.example.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://staging.example.com/v1/users?page=1", parsed.Requests[0].URL)
+}
+
+func TestParsePostmanGraphQL(t *testing.T) {
+	data := `{
+	  "info": {"name": "GraphQL"},
+	  "item": [{
+	    "name": "Query",
+	    "request": {
+	      "method": "POST",
+	      "header": "Accept: application/json\nX-Trace: on",
+	      "body": {"mode": "graphql", "graphql": {"query": "{ users { id } }", "variables": "{\"first\": 10}"}},
+	      "url": "https://api.example.com/graphql"
+	    }
+	  }]
+	}`
+
+	parsed, err := Parse([]byte(data), nil)
+	require.NoError(t, err)
+	require.Len(t, parsed.Requests, 1)
+
+	request := parsed.Requests[0]
+	assert.JSONEq(t, `{"query": "{ users { id } }", "variables": {"first": 10}}`, request.Body)
+	assert.Equal(t, map[string]string{
+		"Accept":       "application/json",
+		"X-Trace":      "on",
+		"Content-Type": "application/json",
+	}, request.Headers)
+}
+
+const insomniaExport4 = `{
+  "_type": "export",
+  "__export_format": 4,
+  "resources": [
+    {"_id": "wrk_1", "_type": "workspace", "name": "Orders API"},
+    {"_id": "env_1", "_type": "environment", "parentId": "wrk_1", "data": {"base_url": "https://api.example.com", "auth": {"token": "secret"}}},
+    {"_id": "env_2", "_type": "environment", "parentId": "env_1", "data": {"base_url": "https://staging.example.com"}},
+    {"_id": "fld_1", "_type": "request_group", "parentId": "wrk_1", "name": "Orders", "environment": {"version": "v2"}},
+    {
+      "_id": "req_1", "_type": "request", "parentId": "fld_1", "name": "List orders",
+      "method": "GET", "url": "{{ _.base_url }}/{{ _.version }}/orders",
+      "parameters": [{"name": "status", "value": "open"}, {"name": "debug", "value": "1", "disabled": true}],
+      "headers": [{"name": "Accept", "value": "application/json"}],
+      "authentication": {"type": "bearer", "token": "{{ _.auth.token }}"}
+    },
+    {
+      "_id": "req_2", "_type": "request", "parentId": "wrk_1", "name": "Create order",
+      "method": "POST", "url": "{{ base_url }}/orders",
+      "body": {"mimeType": "application/json", "text": "{\"sku\": \"{{ _.sku }}\"}"},
+      "authentication": {"type": "apikey", "key": "X-API-Key", "value": "k", "addTo": "header"}
+    }
+  ]
+}`
+
+func TestParseInsomnia(t *testing.T) {
+	parsed, err := Parse([]byte(insomniaExport4), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Orders API", parsed.Name)
+	assert.Equal(t, "insomnia", parsed.Format)
+	require.Len(t, parsed.Requests, 2)
+
+	list := parsed.Requests[0]
+	assert.Equal(t, "Orders", list.Folder)
+	assert.Equal(t, "https://api.example.com/v2/orders?status=open", list.URL)
+	assert.Equal(t, map[string]string{"Accept": "application/json"}, list.Headers)
+	assert.Equal(t, &config.AuthConfig{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "secret"}}, list.Auth)
+	assert.Empty(t, list.Unresolved)
+
+	create := parsed.Requests[1]
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "https://api.example.com/orders", create.URL)
+	assert.Equal(t, "application/json", create.Headers["Content-Type"])
+	assert.Equal(t, &config.AuthConfig{Type: config.AuthTypeAPIKey, APIKey: &config.APIKeyAuth{Header: "X-API-Key", Value: "k"}}, create.Auth)
+	assert.Equal(t, []string{"sku"}, create.Unresolved)
+}
+
+func TestParseInsomniaVars(t *testing.T) {
+	parsed, err := Parse([]byte(insomniaExport4), map[string]string{"sku": "A1", "version": "v3"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://api.example.com/v3/orders?status=open", parsed.Requests[0].URL)
+	assert.Equal(t, `{"sku": "A1"}`, parsed.Requests[1].Body)
+	assert.Empty(t, parsed.Requests[1].Unresolved)
+}
+
+func TestParseRejectsUnknownFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not JSON", `collection`},
+		{"unknown document", `{"openapi": "3.0.0"}`},
+		{"old Insomnia export", `{"_type": "export", "__export_format": 3, "resources": []}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+)
+
+// insomniaExport is an Insomnia export, format 4: a flat list of resources
+// linked to their parents by ID
+type insomniaExport struct {
+	Resources []insomniaResource `json:"resources"`
+}
+
+// insomniaResource is a workspace, environment, request group or request
+type insomniaResource struct {
+	ID       string `json:"_id"`
+	Type     string `json:"_type"`
+	ParentID string `json:"parentId"`
+	Name     string `json:"name"`
+
+	// Environments and request groups
+	Data        map[string]interface{} `json:"data"`
+	Environment map[string]interface{} `json:"environment"`
+
+	// Requests
+	Method         string              `json:"method"`
+	URL            string              `json:"url"`
+	Headers        []insomniaPair      `json:"headers"`
+	Parameters     []insomniaPair      `json:"parameters"`
+	Body           *insomniaBody       `json:"body"`
+	Authentication *insomniaAuthConfig `json:"authentication"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []insomniaPair `json:"params"`
+}
+
+type insomniaAuthConfig struct {
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+	Token    string `json:"token"`
+	Prefix   string `json:"prefix"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	AddTo    string `json:"addTo"` // "header" or "queryParams"
+}
+
+// parseInsomnia reads the requests of an Insomnia export. Variables come from
+// the base environments of the export and the environments of the folders a
+// request is in; sub-environments are not applied, so values they would supply
+// have to be passed in vars.
+func parseInsomnia(data []byte, vars map[string]string) (*Collection, error) {
+	var export insomniaExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Insomnia export: %w", err)
+	}
+
+	resources := make(map[string]*insomniaResource, len(export.Resources))
+	for i := range export.Resources {
+		resources[export.Resources[i].ID] = &export.Resources[i]
+	}
+
+	parsed := &Collection{Format: "insomnia"}
+	base := make(map[string]string)
+	for _, resource := range export.Resources {
+		switch resource.Type {
+		case "workspace":
+			if parsed.Name == "" {
+				parsed.Name = resource.Name
+			}
+		case "environment":
+			// Base environments belong to the workspace; sub-environments
+			// belong to a base environment
+			if parent, ok := resources[resource.ParentID]; !ok || parent.Type != "environment" {
+				flattenVariables(base, "", resource.Data)
+			}
+		}
+	}
+
+	for _, resource := range export.Resources {
+		if resource.Type != "request" {
+			continue
+		}
+		parsed.Requests = append(parsed.Requests, newInsomniaRequest(resource, resources, base, vars))
+	}
+
+	return parsed, nil
+}
+
+// newInsomniaRequest converts a request resource
+func newInsomniaRequest(source insomniaResource, resources map[string]*insomniaResource, base, vars map[string]string) Request {
+	// Folder environments override the base environment, inner folders
+	// overriding outer ones
+	var folders []*insomniaResource
+	for parent := resources[source.ParentID]; parent != nil && parent.Type == "request_group"; parent = resources[parent.ParentID] {
+		folders = append([]*insomniaResource{parent}, folders...)
+	}
+
+	defaults := make(map[string]string, len(base))
+	for name, value := range base {
+		defaults[name] = value
+	}
+	names := make([]string, 0, len(folders))
+	for _, folder := range folders {
+		flattenVariables(defaults, "", folder.Environment)
+		names = append(names, folder.Name)
+	}
+
+	r := newResolver(defaults, vars)
+	r.prefix = "_."
+
+	request := Request{
+		Name:    source.Name,
+		Folder:  strings.Join(names, " / "),
+		Method:  strings.ToUpper(source.Method),
+		URL:     r.resolve(source.URL),
+		Headers: make(map[string]string),
+	}
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+
+	if query := insomniaQuery(r, source.Parameters); query != "" {
+		separator := "?"
+		if strings.Contains(request.URL, "?") {
+			separator = "&"
+		}
+		request.URL += separator + query
+	}
+
+	for _, header := range source.Headers {
+		if !header.Disabled && header.Name != "" {
+			request.Headers[r.resolve(header.Name)] = r.resolve(header.Value)
+		}
+	}
+
+	if source.Body != nil {
+		body, warning := insomniaBodyText(source.Body, request.Headers)
+		request.Body = r.resolve(body)
+		if warning != "" {
+			request.Warnings = append(request.Warnings, warning)
+		}
+	}
+
+	if auth := source.Authentication; auth != nil && !auth.Disabled {
+		if warning := applyInsomniaAuth(r, &request, auth); warning != "" {
+			request.Warnings = append(request.Warnings, warning)
+		}
+	}
+
+	request.Unresolved = r.takeUnresolved()
+	return request
+}
+
+// insomniaQuery encodes the enabled query parameters of a request
+func insomniaQuery(r *resolver, parameters []insomniaPair) string {
+	query := url.Values{}
+	for _, parameter := range parameters {
+		if !parameter.Disabled && parameter.Name != "" {
+			query.Add(r.resolve(parameter.Name), r.resolve(parameter.Value))
+		}
+	}
+	return query.Encode()
+}
+
+// insomniaBodyText renders a request body, setting its content type unless a
+// header already does. Bodies that cannot be sent as text are left out with a
+// warning.
+func insomniaBodyText(body *insomniaBody, headers map[string]string) (string, string) {
+	switch body.MimeType {
+	case "application/x-www-form-urlencoded":
+		form := url.Values{}
+		for _, param := range body.Params {
+			if !param.Disabled {
+				form.Add(param.Name, param.Value)
+			}
+		}
+		setDefaultHeader(headers, "Content-Type", body.MimeType)
+		return form.Encode(), ""
+	case "multipart/form-data":
+		return "", "multipart/form-data body is not supported and was left out"
+	case "application/graphql":
+		// GraphQL bodies are stored as the JSON payload they are sent as
+		setDefaultHeader(headers, "Content-Type", "application/json")
+		return body.Text, ""
+	case "":
+		return body.Text, ""
+	default:
+		setDefaultHeader(headers, "Content-Type", body.MimeType)
+		return body.Text, ""
+	}
+}
+
+// applyInsomniaAuth sets the authentication of a request, returning a warning
+// when its type is not supported
+func applyInsomniaAuth(r *resolver, request *Request, auth *insomniaAuthConfig) string {
+	switch auth.Type {
+	case "", "none":
+		return ""
+	case "bearer":
+		// A custom prefix replaces "Bearer", which bearer auth cannot express
+		if prefix := r.resolve(auth.Prefix); prefix != "" && prefix != "Bearer" {
+			request.Headers["Authorization"] = prefix + " " + r.resolve(auth.Token)
+			return ""
+		}
+		request.Auth = &config.AuthConfig{
+			Type:   config.AuthTypeBearer,
+			Bearer: &config.BearerAuth{Token: r.resolve(auth.Token)},
+		}
+	case "basic":
+		request.Auth = &config.AuthConfig{
+			Type:  config.AuthTypeBasic,
+			Basic: &config.BasicAuth{Username: r.resolve(auth.Username), Password: r.resolve(auth.Password)},
+		}
+	case "apikey":
+		return applyAPIKey(request, r.resolve(auth.Key), r.resolve(auth.Value), auth.AddTo == "queryParams")
+	default:
+		return fmt.Sprintf("%s authentication is not supported; configure auth for the endpoint by hand", auth.Type)
+	}
+	return ""
+}
+
+// flattenVariables adds the values of an environment to vars, naming nested
+// values by their path, as in {{ _.api.host }}
+func flattenVariables(vars map[string]string, prefix string, data map[string]interface{}) {
+	for name, value := range data {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenVariables(vars, prefix+name+".", nested)
+			continue
+		}
+		vars[prefix+name] = valueString(value)
+	}
+}
@@ -0,0 +1,345 @@
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+)
+
+// postmanCollection is a Postman collection, v2.0 or v2.1
+type postmanCollection struct {
+	Info struct {
+		Name string `json:"name"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+}
+
+// postmanItem is a request or, when it has items of its own, a folder
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request json.RawMessage `json:"request"` // An object, or just the URL
+	Auth    *postmanAuth    `json:"auth"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header json.RawMessage `json:"header"` // A list of headers, or "Name: value" lines
+	URL    json.RawMessage `json:"url"`    // An object with the raw URL, or the URL
+	Body   *postmanBody    `json:"body"`
+	Auth   *postmanAuth    `json:"auth"`
+}
+
+type postmanKeyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanKeyValue `json:"urlencoded"`
+	GraphQL    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+// postmanAuth holds the attributes of one authentication type: a list of
+// key-value pairs in v2.1 and an object in v2.0
+type postmanAuth struct {
+	Type   string          `json:"type"`
+	Bearer json.RawMessage `json:"bearer"`
+	Basic  json.RawMessage `json:"basic"`
+	APIKey json.RawMessage `json:"apikey"`
+}
+
+// parsePostman reads the requests of a Postman collection
+func parsePostman(data []byte, vars map[string]string) (*Collection, error) {
+	var source postmanCollection
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, fmt.Errorf("failed to parse Postman collection: %w", err)
+	}
+
+	defaults := make(map[string]string, len(source.Variable))
+	for _, variable := range source.Variable {
+		if !variable.Disabled {
+			defaults[variable.Key] = valueString(variable.Value)
+		}
+	}
+
+	parsed := &Collection{Name: source.Info.Name, Format: "postman"}
+	r := newResolver(defaults, vars)
+	for _, item := range source.Item {
+		if err := collectPostmanItem(parsed, r, item, "", source.Auth); err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed, nil
+}
+
+// collectPostmanItem adds a request, or the requests of a folder, to the
+// collection. Requests without authentication of their own inherit auth.
+func collectPostmanItem(parsed *Collection, r *resolver, item postmanItem, folder string, auth *postmanAuth) error {
+	if item.Auth != nil && item.Auth.Type != "inherit" {
+		auth = item.Auth
+	}
+
+	if item.Request == nil {
+		path := item.Name
+		if folder != "" {
+			path = folder + " / " + item.Name
+		}
+		for _, child := range item.Item {
+			if err := collectPostmanItem(parsed, r, child, path, auth); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	request, err := newPostmanRequest(r, item, folder, auth)
+	if err != nil {
+		return fmt.Errorf("request %q: %w", item.Name, err)
+	}
+	parsed.Requests = append(parsed.Requests, *request)
+	return nil
+}
+
+// newPostmanRequest converts a request item
+func newPostmanRequest(r *resolver, item postmanItem, folder string, auth *postmanAuth) (*Request, error) {
+	var source postmanRequest
+	var rawURL string
+	if err := json.Unmarshal(item.Request, &rawURL); err != nil {
+		if err := json.Unmarshal(item.Request, &source); err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+		if rawURL, err = postmanURL(source.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	request := &Request{
+		Name:    item.Name,
+		Folder:  folder,
+		Method:  strings.ToUpper(source.Method),
+		URL:     r.resolve(rawURL),
+		Headers: make(map[string]string),
+	}
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+
+	headers, err := postmanHeaders(source.Header)
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range headers {
+		if !header.Disabled && header.Key != "" {
+			request.Headers[r.resolve(header.Key)] = r.resolve(valueString(header.Value))
+		}
+	}
+
+	if source.Body != nil && !source.Body.Disabled {
+		request.Body, request.Warnings = postmanBodyText(source.Body, request.Headers)
+		request.Body = r.resolve(request.Body)
+	}
+
+	if source.Auth != nil && source.Auth.Type != "inherit" {
+		auth = source.Auth
+	}
+	if auth != nil {
+		if warning := applyPostmanAuth(r, request, auth); warning != "" {
+			request.Warnings = append(request.Warnings, warning)
+		}
+	}
+
+	request.Unresolved = r.takeUnresolved()
+	return request, nil
+}
+
+// postmanURL returns the raw URL of a request
+func postmanURL(raw json.RawMessage) (string, error) {
+	var rawURL string
+	if err := json.Unmarshal(raw, &rawURL); err == nil {
+		return rawURL, nil
+	}
+
+	var structured struct {
+		Raw string `json:"raw"`
+	}
+	if err := json.Unmarshal(raw, &structured); err != nil || structured.Raw == "" {
+		return "", fmt.Errorf("request has no URL")
+	}
+	return structured.Raw, nil
+}
+
+// postmanHeaders reads the headers of a request, given as a list or as lines
+func postmanHeaders(raw json.RawMessage) ([]postmanKeyValue, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	var headers []postmanKeyValue
+	if err := json.Unmarshal(raw, &headers); err == nil {
+		return headers, nil
+	}
+
+	var lines string
+	if err := json.Unmarshal(raw, &lines); err != nil {
+		return nil, fmt.Errorf("invalid headers: %w", err)
+	}
+	for _, line := range strings.Split(lines, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if ok {
+			headers = append(headers, postmanKeyValue{Key: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+		}
+	}
+	return headers, nil
+}
+
+// postmanBodyText renders a request body, setting the content type of raw JSON,
+// form and GraphQL bodies unless a header already does. Body modes that cannot
+// be sent as text are left out with a warning.
+func postmanBodyText(body *postmanBody, headers map[string]string) (string, []string) {
+	switch body.Mode {
+	case "raw", "":
+		if body.Options.Raw.Language == "json" {
+			setDefaultHeader(headers, "Content-Type", "application/json")
+		}
+		return body.Raw, nil
+	case "urlencoded":
+		form := url.Values{}
+		for _, field := range body.URLEncoded {
+			if !field.Disabled {
+				form.Add(field.Key, valueString(field.Value))
+			}
+		}
+		setDefaultHeader(headers, "Content-Type", "application/x-www-form-urlencoded")
+		return form.Encode(), nil
+	case "graphql":
+		if body.GraphQL == nil {
+			return "", nil
+		}
+		payload := map[string]interface{}{"query": body.GraphQL.Query}
+		if strings.TrimSpace(body.GraphQL.Variables) != "" {
+			payload["variables"] = json.RawMessage(body.GraphQL.Variables)
+		}
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return "", []string{fmt.Sprintf("GraphQL variables are not valid JSON: %v", err)}
+		}
+		setDefaultHeader(headers, "Content-Type", "application/json")
+		return string(encoded), nil
+	default:
+		return "", []string{fmt.Sprintf("%s body is not supported and was left out", body.Mode)}
+	}
+}
+
+// applyPostmanAuth sets the authentication of a request, returning a warning
+// when its type is not supported
+func applyPostmanAuth(r *resolver, request *Request, auth *postmanAuth) string {
+	switch auth.Type {
+	case "noauth":
+		return ""
+	case "bearer":
+		attributes := postmanAuthAttributes(r, auth.Bearer)
+		request.Auth = &config.AuthConfig{
+			Type:   config.AuthTypeBearer,
+			Bearer: &config.BearerAuth{Token: attributes["token"]},
+		}
+	case "basic":
+		attributes := postmanAuthAttributes(r, auth.Basic)
+		request.Auth = &config.AuthConfig{
+			Type:  config.AuthTypeBasic,
+			Basic: &config.BasicAuth{Username: attributes["username"], Password: attributes["password"]},
+		}
+	case "apikey":
+		attributes := postmanAuthAttributes(r, auth.APIKey)
+		return applyAPIKey(request, attributes["key"], attributes["value"], attributes["in"] == "query")
+	default:
+		return fmt.Sprintf("%s authentication is not supported; configure auth for the endpoint by hand", auth.Type)
+	}
+	return ""
+}
+
+// postmanAuthAttributes reads the attributes of an authentication type, with
+// their variables resolved
+func postmanAuthAttributes(r *resolver, raw json.RawMessage) map[string]string {
+	attributes := make(map[string]string)
+
+	var list []postmanKeyValue
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, attribute := range list {
+			attributes[attribute.Key] = r.resolve(valueString(attribute.Value))
+		}
+		return attributes
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err == nil {
+		for key, value := range object {
+			attributes[key] = r.resolve(valueString(value))
+		}
+	}
+	return attributes
+}
+
+// applyAPIKey sends an API key in a header, as API key authentication, or as a
+// query parameter of the URL
+func applyAPIKey(request *Request, name, value string, inQuery bool) string {
+	if name == "" {
+		return "API key authentication has no key name and was left out"
+	}
+
+	if !inQuery {
+		request.Auth = &config.AuthConfig{
+			Type:   config.AuthTypeAPIKey,
+			APIKey: &config.APIKeyAuth{Header: name, Value: value},
+		}
+		return ""
+	}
+
+	withKey, err := withAPIKeyQuery(request.URL, name, value)
+	if err != nil {
+		return fmt.Sprintf("API key could not be added to the URL: %v", err)
+	}
+	request.URL = withKey
+	return ""
+}
+
+// setDefaultHeader sets a header unless it is already set, in any case
+func setDefaultHeader(headers map[string]string, name, value string) {
+	for existing := range headers {
+		if strings.EqualFold(existing, name) {
+			return
+		}
+	}
+	headers[name] = value
+}
+
+// valueString renders a variable or attribute value, which collections may
+// store as a string, number or boolean
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
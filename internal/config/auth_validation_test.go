@@ -1,9 +1,12 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateAuth(t *testing.T) {
@@ -260,6 +263,78 @@ func TestValidateAuth(t *testing.T) {
 	}
 }
 
+func TestValidateAuthSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cret\n"), 0600))
+	missingFile := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name        string
+		auth        *AuthConfig
+		errorFields []string
+	}{
+		{
+			name:        "bearer token file",
+			auth:        &AuthConfig{Type: AuthTypeBearer, Bearer: &BearerAuth{TokenFile: secretFile}},
+			errorFields: nil,
+		},
+		{
+			name:        "bearer token file missing",
+			auth:        &AuthConfig{Type: AuthTypeBearer, Bearer: &BearerAuth{TokenFile: missingFile}},
+			errorFields: []string{"auth.bearer.token_file"},
+		},
+		{
+			name:        "bearer token and token file",
+			auth:        &AuthConfig{Type: AuthTypeBearer, Bearer: &BearerAuth{Token: "inline", TokenFile: secretFile}},
+			errorFields: []string{"auth.bearer.token_file"},
+		},
+		{
+			name:        "basic password file",
+			auth:        &AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuth{Username: "user", PasswordFile: secretFile}},
+			errorFields: nil,
+		},
+		{
+			name:        "basic password file is a directory",
+			auth:        &AuthConfig{Type: AuthTypeBasic, Basic: &BasicAuth{Username: "user", PasswordFile: dir}},
+			errorFields: []string{"auth.basic.password_file"},
+		},
+		{
+			name:        "api key value file",
+			auth:        &AuthConfig{Type: AuthTypeAPIKey, APIKey: &APIKeyAuth{Header: "X-API-Key", ValueFile: secretFile}},
+			errorFields: nil,
+		},
+		{
+			name:        "api key value file missing",
+			auth:        &AuthConfig{Type: AuthTypeAPIKey, APIKey: &APIKeyAuth{Header: "X-API-Key", ValueFile: missingFile}},
+			errorFields: []string{"auth.api_key.value_file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAuth(tt.auth, "auth")
+			if len(tt.errorFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			validationErrs, ok := err.(ValidationErrors)
+			require.True(t, ok, "Expected ValidationErrors type")
+			for _, expectedField := range tt.errorFields {
+				found := false
+				for _, validationErr := range validationErrs {
+					if validationErr.Field == expectedField {
+						found = true
+					}
+				}
+				assert.True(t, found, "Expected error field %s not found in validation errors", expectedField)
+			}
+		})
+	}
+}
+
 func TestValidateEndpointWithAuth(t *testing.T) {
 	tests := []struct {
 		name        string
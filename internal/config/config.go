@@ -1,8 +1,11 @@
 package config
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +21,10 @@ type Config struct {
 	Alerting  AlertingConfig   `yaml:"alerting" mapstructure:"alerting"`
 	Reporting ReportingConfig  `yaml:"reporting" mapstructure:"reporting"`
 	Retention RetentionConfig  `yaml:"retention" mapstructure:"retention"`
+	DriftSink DriftSinkConfig  `yaml:"drift_sink,omitempty" mapstructure:"drift_sink"`
+	OTel      OTelConfig       `yaml:"otel,omitempty" mapstructure:"otel"`
+	Heartbeat HeartbeatConfig  `yaml:"heartbeat,omitempty" mapstructure:"heartbeat"`
+	API       APIConfig        `yaml:"api,omitempty" mapstructure:"api"`
 }
 
 // ProjectConfig contains project-level settings
@@ -35,6 +42,48 @@ type GlobalConfig struct {
 	RetryDelay  time.Duration `yaml:"retry_delay" mapstructure:"retry_delay"`
 	MaxWorkers  int           `yaml:"max_workers" mapstructure:"max_workers"`
 	DatabaseURL string        `yaml:"database_url" mapstructure:"database_url"`
+
+	// MaxRetryDelay caps the delay before retrying a request, including delays
+	// servers request through Retry-After; 0 uses 30s
+	MaxRetryDelay time.Duration `yaml:"max_retry_delay,omitempty" mapstructure:"max_retry_delay"`
+
+	// DatabaseBusyTimeout is how long a database statement waits for a lock
+	// held by another writer before failing; 0 uses 5s
+	DatabaseBusyTimeout time.Duration `yaml:"database_busy_timeout,omitempty" mapstructure:"database_busy_timeout"`
+
+	// BodyStorage selects where response bodies of monitoring runs are kept:
+	// "db", the default, or "file", which writes each body once to a file named
+	// by its SHA-256 under BodyStorageDir and keeps only the hash in the database
+	BodyStorage    BodyStorage `yaml:"body_storage,omitempty" mapstructure:"body_storage"`
+	BodyStorageDir string      `yaml:"body_storage_dir,omitempty" mapstructure:"body_storage_dir"`
+
+	// MaxDriftsPerCheck caps the drifts stored for a single comparison. Larger
+	// results are collapsed into one critical summary drift; 0 disables the cap.
+	MaxDriftsPerCheck int `yaml:"max_drifts_per_check" mapstructure:"max_drifts_per_check"`
+
+	// TLSExpiryWarning records a tls_expiring drift once an HTTPS endpoint's
+	// certificate expires within this duration; 0 disables the check.
+	TLSExpiryWarning time.Duration `yaml:"tls_expiry_warning" mapstructure:"tls_expiry_warning"`
+
+	// ComparisonCacheSize is the number of response body comparisons kept so that
+	// identical comparisons are not recomputed; 0, the default, disables the cache.
+	// It pays off where comparisons repeat: trend compares consecutive runs of an
+	// endpoint's history, the scheduler and ci compare the samples of endpoints
+	// taking several, and ci compares endpoints returning the same bodies.
+	ComparisonCacheSize int `yaml:"comparison_cache_size" mapstructure:"comparison_cache_size"`
+
+	// MinPersistSeverity is the lowest severity of drift the scheduler stores;
+	// drifts below it are counted but not saved. Empty stores every drift.
+	MinPersistSeverity string `yaml:"min_persist_severity,omitempty" mapstructure:"min_persist_severity"`
+
+	// Sensitivity is the comparison preset of endpoints that do not select
+	// one themselves; empty applies no preset
+	Sensitivity Sensitivity `yaml:"sensitivity,omitempty" mapstructure:"sensitivity"`
+
+	// CABundleFile is a PEM file of CA certificates trusted, along with the
+	// system roots, when verifying the certificates of every endpoint, such as
+	// the root of a private CA signing internal services
+	CABundleFile string `yaml:"ca_bundle_file,omitempty" mapstructure:"ca_bundle_file"`
 }
 
 // EndpointConfig represents configuration for a single API endpoint
@@ -45,14 +94,117 @@ type EndpointConfig struct {
 	SpecFile        string            `yaml:"spec_file,omitempty" mapstructure:"spec_file"`
 	Interval        time.Duration     `yaml:"interval" mapstructure:"interval"`
 	Headers         map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	ProxyURL        string            `yaml:"proxy_url,omitempty" mapstructure:"proxy_url"` // Proxy for this endpoint, or "direct"; defaults to the environment proxy
+	CAFile          string            `yaml:"ca_file,omitempty" mapstructure:"ca_file"`     // CA bundle trusted for this endpoint instead of global.ca_bundle_file
 	Auth            *AuthConfig       `yaml:"auth,omitempty" mapstructure:"auth"`
 	Validation      ValidationConfig  `yaml:"validation" mapstructure:"validation"`
+	CompareRoot     string            `yaml:"compare_root,omitempty" mapstructure:"compare_root"`       // JSONPath of the subtree to compare
+	GoldenFile      string            `yaml:"golden_file,omitempty" mapstructure:"golden_file"`         // Expected response body for verify-golden
+	GoldenTemplate  bool              `yaml:"golden_template,omitempty" mapstructure:"golden_template"` // golden_file contains placeholders such as <uuid>
 	RequestBodyFile string            `yaml:"request_body_file,omitempty" mapstructure:"request_body_file"`
 	Timeout         time.Duration     `yaml:"timeout,omitempty" mapstructure:"timeout"`
+	ConnectTimeout  time.Duration     `yaml:"connect_timeout,omitempty" mapstructure:"connect_timeout"` // Longest wait for a connection; 0 leaves it to timeout
+	ReadTimeout     time.Duration     `yaml:"read_timeout,omitempty" mapstructure:"read_timeout"`       // Longest wait for the response headers once the request is sent; 0 leaves it to timeout
 	RetryCount      int               `yaml:"retry_count,omitempty" mapstructure:"retry_count"`
+	Samples         int               `yaml:"samples,omitempty" mapstructure:"samples"`   // Requests per check; fields varying between them are ignored
+	MaxRuns         int               `yaml:"max_runs,omitempty" mapstructure:"max_runs"` // Newest monitoring runs kept by cleanup regardless of age; 0 keeps all
 	Enabled         bool              `yaml:"enabled" mapstructure:"enabled"`
+
+	// Variants check the endpoint once per variant, each with its own query
+	// parameters or headers, instead of once as configured
+	Variants []EndpointVariant `yaml:"variants,omitempty" mapstructure:"variants"`
+
+	// StreamReadLimit bounds how much of the response body is read, as a byte
+	// size such as "64KB" or a duration such as "5s", so that streaming endpoints
+	// are compared by a prefix of their response; empty reads whole bodies
+	StreamReadLimit string `yaml:"stream_read_limit,omitempty" mapstructure:"stream_read_limit"`
+
+	// FullBodyEvery samples the response bodies stored for stable endpoints: a
+	// body identical to the last one stored in full, with the same status, is
+	// stored as its hash only and read back from that run, and every
+	// FullBodyEvery-th check stores the body in full regardless. 0 stores every
+	// body in full.
+	FullBodyEvery int `yaml:"full_body_every,omitempty" mapstructure:"full_body_every"`
+
+	// BaselineStrategy selects the stored run responses are compared against
+	BaselineStrategy BaselineStrategy `yaml:"baseline_strategy,omitempty" mapstructure:"baseline_strategy"`
+	BaselineRunID    int64            `yaml:"baseline_run_id,omitempty" mapstructure:"baseline_run_id"`     // Pinned run for the fixed strategy
+	BaselineRunsAgo  int              `yaml:"baseline_runs_ago,omitempty" mapstructure:"baseline_runs_ago"` // How many runs back the n_ago strategy looks
+	BaselineFile     string           `yaml:"baseline_file,omitempty" mapstructure:"baseline_file"`         // Committed response body for the file strategy
+	BaselineRuns     int              `yaml:"baseline_runs,omitempty" mapstructure:"baseline_runs"`         // How many recent runs the majority strategy votes among; defaults to 10
+
+	// MinPersistSeverity overrides global.min_persist_severity for this endpoint
+	MinPersistSeverity string `yaml:"min_persist_severity,omitempty" mapstructure:"min_persist_severity"`
+
+	// PerformanceMode selects how slow responses are detected
+	PerformanceMode      PerformanceMode `yaml:"performance_mode,omitempty" mapstructure:"performance_mode"`
+	PerformanceThreshold time.Duration   `yaml:"performance_threshold,omitempty" mapstructure:"performance_threshold"` // Slowest acceptable response for the threshold mode
+	PerformanceZScore    float64         `yaml:"performance_zscore,omitempty" mapstructure:"performance_zscore"`       // Standard deviations above the mean flagged by the zscore mode; defaults to 3
 }
 
+// BaselineStrategy selects the stored run a response is compared against
+type BaselineStrategy string
+
+const (
+	// BaselineStrategyPrevious compares against the most recent run; this is the default
+	BaselineStrategyPrevious BaselineStrategy = "previous"
+	// BaselineStrategyFixed compares against the run pinned by baseline_run_id
+	BaselineStrategyFixed BaselineStrategy = "fixed"
+	// BaselineStrategyNAgo compares against the run baseline_runs_ago runs back, so
+	// gradual changes accumulate instead of passing one small delta at a time
+	BaselineStrategyNAgo BaselineStrategy = "n_ago"
+	// BaselineStrategyFile compares against the response body committed in
+	// baseline_file, which is only changed deliberately with driftwatch accept
+	BaselineStrategyFile BaselineStrategy = "file"
+	// BaselineStrategyMajority compares against the latest run with the most
+	// common response shape among the last baseline_runs runs, so a one-off
+	// degraded response is flagged against the norm instead of becoming the
+	// baseline of the next check
+	BaselineStrategyMajority BaselineStrategy = "majority"
+)
+
+// PerformanceMode selects how response times are compared
+type PerformanceMode string
+
+const (
+	// PerformanceModeDelta compares the response time with the baseline's; this is the default
+	PerformanceModeDelta PerformanceMode = "delta"
+	// PerformanceModeThreshold flags responses slower than performance_threshold
+	PerformanceModeThreshold PerformanceMode = "threshold"
+	// PerformanceModeZScore flags responses that are statistically slow compared
+	// with the recent runs of the endpoint
+	PerformanceModeZScore PerformanceMode = "zscore"
+)
+
+// StatusMatch selects how status codes are compared
+type StatusMatch string
+
+const (
+	// StatusMatchExact reports any change of the status code; this is the default
+	StatusMatchExact StatusMatch = "exact"
+	// StatusMatchClass reports only changes of the status class, such as from
+	// 2xx to 4xx
+	StatusMatchClass StatusMatch = "class"
+)
+
+// BodyStorage selects where response bodies are stored
+type BodyStorage string
+
+const (
+	// BodyStorageDB stores response bodies in the database; this is the default
+	BodyStorageDB BodyStorage = "db"
+	// BodyStorageFile stores response bodies as content-addressed files
+	BodyStorageFile BodyStorage = "file"
+)
+
+const (
+	// MaxEndpointSamples is the largest number of requests a single check may take
+	MaxEndpointSamples = 10
+
+	// SampleDelay is the pause between requests when an endpoint takes several samples
+	SampleDelay = 500 * time.Millisecond
+)
+
 // AuthConfig contains authentication configuration for endpoints
 type AuthConfig struct {
 	Type   AuthType    `yaml:"type" mapstructure:"type"`
@@ -75,19 +227,22 @@ const (
 
 // BearerAuth represents Bearer token authentication
 type BearerAuth struct {
-	Token string `yaml:"token" mapstructure:"token"`
+	Token     string `yaml:"token" mapstructure:"token"`
+	TokenFile string `yaml:"token_file,omitempty" mapstructure:"token_file"` // read on every request
 }
 
 // BasicAuth represents HTTP Basic authentication
 type BasicAuth struct {
-	Username string `yaml:"username" mapstructure:"username"`
-	Password string `yaml:"password" mapstructure:"password"`
+	Username     string `yaml:"username" mapstructure:"username"`
+	Password     string `yaml:"password" mapstructure:"password"`
+	PasswordFile string `yaml:"password_file,omitempty" mapstructure:"password_file"` // read on every request
 }
 
 // APIKeyAuth represents API key authentication via custom headers
 type APIKeyAuth struct {
-	Header string `yaml:"header" mapstructure:"header"`
-	Value  string `yaml:"value" mapstructure:"value"`
+	Header    string `yaml:"header" mapstructure:"header"`
+	Value     string `yaml:"value" mapstructure:"value"`
+	ValueFile string `yaml:"value_file,omitempty" mapstructure:"value_file"` // read on every request
 }
 
 // OAuth2Auth represents OAuth 2.0 client credentials flow
@@ -101,16 +256,162 @@ type OAuth2Auth struct {
 
 // ValidationConfig contains validation-specific settings
 type ValidationConfig struct {
-	StrictMode     bool     `yaml:"strict_mode" mapstructure:"strict_mode"`
-	IgnoreFields   []string `yaml:"ignore_fields,omitempty" mapstructure:"ignore_fields"`
-	RequiredFields []string `yaml:"required_fields,omitempty" mapstructure:"required_fields"`
+	// Sensitivity selects a preset of the options below: strict, balanced or
+	// lenient. Options set explicitly take precedence over the preset.
+	Sensitivity Sensitivity `yaml:"sensitivity,omitempty" mapstructure:"sensitivity"`
+
+	StrictMode      bool     `yaml:"strict_mode" mapstructure:"strict_mode"`
+	IgnoreFields    []string `yaml:"ignore_fields,omitempty" mapstructure:"ignore_fields"`
+	RequiredFields  []string `yaml:"required_fields,omitempty" mapstructure:"required_fields"`   // must be present and non-null in every response
+	VolatileCookies []string `yaml:"volatile_cookies,omitempty" mapstructure:"volatile_cookies"` // cookie values that change on every response, besides session cookies
+
+	// EmbeddedJSONFields lists string fields holding encoded JSON, which is
+	// decoded before comparison so that formatting differences are not drift
+	EmbeddedJSONFields []string `yaml:"embedded_json_fields,omitempty" mapstructure:"embedded_json_fields"`
+
+	// NullAsMissing treats explicit JSON nulls like absent fields, so that
+	// {"x": null} and {} are not reported as drift. Unset means the preset's
+	// choice, or off.
+	NullAsMissing *bool `yaml:"null_as_missing,omitempty" mapstructure:"null_as_missing"`
+
+	// ShapeOnly compares responses by field presence and types only, for
+	// endpoints whose values differ on every request. Unset means the preset's
+	// choice, or off.
+	ShapeOnly *bool `yaml:"shape_only,omitempty" mapstructure:"shape_only"`
+
+	// NumericTolerance is the relative change of a numeric value, such as 0.01
+	// for 1%, that is not reported as drift
+	NumericTolerance float64 `yaml:"numeric_tolerance,omitempty" mapstructure:"numeric_tolerance"`
+
+	// UnorderedArrays lists arrays whose order does not matter (sets returned
+	// as arrays). Their elements are matched regardless of position, so only
+	// real additions and removals are drift; arrays elsewhere keep their order.
+	UnorderedArrays []string `yaml:"unordered_arrays,omitempty" mapstructure:"unordered_arrays"`
+
+	// ArraySummaryThreshold is the change in the number of elements of an
+	// array, such as 10, above which the added or removed elements are reported
+	// as one "N elements added/removed" summary instead of one drift each.
+	// Zero reports every element.
+	ArraySummaryThreshold int `yaml:"array_summary_threshold,omitempty" mapstructure:"array_summary_threshold"`
+
+	// TrackedFields lists scalar fields, such as "$.meta.total", whose values
+	// are watched. When set, only changes to these values and to the shape of
+	// the response are drift; other value changes are ignored.
+	TrackedFields []string `yaml:"tracked_fields,omitempty" mapstructure:"tracked_fields"`
+
+	// VersionField is the field, such as "$.schema_version", holding the
+	// contract version of the response. A change of its value is a deliberate
+	// version bump, reported as version_change drift at VersionChangeSeverity
+	// (low by default). With AcknowledgeOnVersionChange, the monitor then
+	// acknowledges the earlier drift of the endpoint, as the new version is the
+	// new baseline.
+	VersionField               string `yaml:"version_field,omitempty" mapstructure:"version_field"`
+	VersionChangeSeverity      string `yaml:"version_change_severity,omitempty" mapstructure:"version_change_severity"`
+	AcknowledgeOnVersionChange bool   `yaml:"acknowledge_on_version_change,omitempty" mapstructure:"acknowledge_on_version_change"`
+
+	// HeaderPatterns maps header names to regular expressions their values are
+	// expected to match. A header with a pattern drifts only when its value
+	// stops matching, not on every value change.
+	HeaderPatterns map[string]string `yaml:"header_patterns,omitempty" mapstructure:"header_patterns"`
+
+	// IgnoreValuePatterns lists regular expressions matching whole values that
+	// change on every response, such as UUIDs or timestamps. A value changing
+	// from one match of a pattern to another is not drift, wherever it is.
+	IgnoreValuePatterns []string `yaml:"ignore_value_patterns,omitempty" mapstructure:"ignore_value_patterns"`
+
+	// BodyStatusCodes lists the status codes, such as 200, whose response
+	// bodies are compared. Responses with other codes, such as errors carrying
+	// a request ID, are compared by status and headers only. Empty compares
+	// every body.
+	BodyStatusCodes []int `yaml:"body_status_codes,omitempty" mapstructure:"body_status_codes"`
+
+	// StatusMatch selects whether status codes must stay the same (exact, the
+	// default) or only in the same class (class), for endpoints where 200 and
+	// 201 are both just success
+	StatusMatch StatusMatch `yaml:"status_match,omitempty" mapstructure:"status_match"`
+}
+
+// ComparesBody reports whether response bodies returned with a status code
+// are compared, as configured by BodyStatusCodes
+func (v ValidationConfig) ComparesBody(statusCode int) bool {
+	return len(v.BodyStatusCodes) == 0 || slices.Contains(v.BodyStatusCodes, statusCode)
+}
+
+// TreatsNullAsMissing reports whether explicit nulls are compared like absent
+// fields, as configured by NullAsMissing
+func (v ValidationConfig) TreatsNullAsMissing() bool {
+	return v.NullAsMissing != nil && *v.NullAsMissing
+}
+
+// ComparesShapeOnly reports whether responses are compared by shape only, as
+// configured by ShapeOnly
+func (v ValidationConfig) ComparesShapeOnly() bool {
+	return v.ShapeOnly != nil && *v.ShapeOnly
 }
 
 // AlertingConfig contains alerting configuration
 type AlertingConfig struct {
-	Enabled  bool                 `yaml:"enabled" mapstructure:"enabled"`
-	Channels []AlertChannelConfig `yaml:"channels" mapstructure:"channels"`
-	Rules    []AlertRuleConfig    `yaml:"rules" mapstructure:"rules"`
+	Enabled    bool                 `yaml:"enabled" mapstructure:"enabled"`
+	Channels   []AlertChannelConfig `yaml:"channels" mapstructure:"channels"`
+	Rules      []AlertRuleConfig    `yaml:"rules" mapstructure:"rules"`
+	QuietHours QuietHoursConfig     `yaml:"quiet_hours,omitempty" mapstructure:"quiet_hours"`
+	Escalation EscalationConfig     `yaml:"escalation,omitempty" mapstructure:"escalation"`
+	Liveness   LivenessConfig       `yaml:"liveness,omitempty" mapstructure:"liveness"`
+	Retry      AlertRetryConfig     `yaml:"retry,omitempty" mapstructure:"retry"`
+
+	// Aggregate sends the drifts detected by one check of an endpoint as a
+	// single alert per channel listing every change, instead of one per drift
+	Aggregate bool `yaml:"aggregate,omitempty" mapstructure:"aggregate"`
+
+	// AllowDuplicates sends a drift through a channel once for every rule that
+	// routes it there. By default a drift, identified by its fingerprint, is sent
+	// through each channel at most once per check.
+	AllowDuplicates bool `yaml:"allow_duplicates,omitempty" mapstructure:"allow_duplicates"`
+
+	// NotifyResolved sends a resolved alert through the channels of an alerted
+	// drift once a later check undoes the change, and of an endpoint_down alert
+	// once the endpoint responds successfully again
+	NotifyResolved bool `yaml:"notify_resolved,omitempty" mapstructure:"notify_resolved"`
+}
+
+// AlertRetryConfig controls how failed alert deliveries are retried, with
+// exponential backoff and jitter. Zero values use 3 attempts with delays
+// doubling from 1s up to 30s.
+type AlertRetryConfig struct {
+	MaxAttempts  int           `yaml:"max_attempts,omitempty" mapstructure:"max_attempts"` // Including the first attempt; 1 disables retries
+	InitialDelay time.Duration `yaml:"initial_delay,omitempty" mapstructure:"initial_delay"`
+	MaxDelay     time.Duration `yaml:"max_delay,omitempty" mapstructure:"max_delay"`
+}
+
+// LivenessConfig alerts when an enabled endpoint has had no successful run
+// within Window, which drift detection cannot notice on its own
+type LivenessConfig struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	Window   time.Duration `yaml:"window" mapstructure:"window"`               // e.g. 30m
+	Severity string        `yaml:"severity,omitempty" mapstructure:"severity"` // defaults to critical
+}
+
+// QuietHoursConfig defines a daily window during which non-critical alerts are
+// buffered and delivered as a digest once the window ends
+type QuietHoursConfig struct {
+	Enabled    bool     `yaml:"enabled" mapstructure:"enabled"`
+	Start      string   `yaml:"start" mapstructure:"start"`                     // HH:MM
+	End        string   `yaml:"end" mapstructure:"end"`                         // HH:MM
+	Timezone   string   `yaml:"timezone,omitempty" mapstructure:"timezone"`     // IANA name, defaults to local time
+	Severities []string `yaml:"severities,omitempty" mapstructure:"severities"` // empty means low, medium, high
+}
+
+// EscalationConfig raises the severity of unacknowledged drifts as they age.
+// The rule with the longest age that a drift has reached applies.
+type EscalationConfig struct {
+	Enabled bool                   `yaml:"enabled" mapstructure:"enabled"`
+	Rules   []EscalationRuleConfig `yaml:"rules,omitempty" mapstructure:"rules"`
+}
+
+// EscalationRuleConfig raises severity by a number of levels once a drift is older than After
+type EscalationRuleConfig struct {
+	After  time.Duration `yaml:"after" mapstructure:"after"`   // age of the unacknowledged drift, e.g. 72h
+	Levels int           `yaml:"levels" mapstructure:"levels"` // severity levels to raise (1-3)
 }
 
 // AlertChannelConfig represents a single alert channel
@@ -119,6 +420,13 @@ type AlertChannelConfig struct {
 	Name     string                 `yaml:"name" mapstructure:"name"`
 	Enabled  bool                   `yaml:"enabled" mapstructure:"enabled"`
 	Settings map[string]interface{} `yaml:"settings" mapstructure:"settings"`
+
+	// MessageTemplate is a Go text/template rendering the alert text, such as
+	// "{{.Severity}}: {{.Path}} on {{.EndpointURL}} changed from {{.Before}} to
+	// {{.After}}", for teams that add runbook links or ticket keys. Slack,
+	// Discord and email channels send the rendered text instead of their
+	// built-in format; webhooks send it in the text field of the payload.
+	MessageTemplate string `yaml:"message_template,omitempty" mapstructure:"message_template"`
 }
 
 // AlertRuleConfig defines when alerts should be triggered
@@ -127,13 +435,26 @@ type AlertRuleConfig struct {
 	Severity  []string `yaml:"severity" mapstructure:"severity"`             // low, medium, high, critical
 	Endpoints []string `yaml:"endpoints,omitempty" mapstructure:"endpoints"` // empty means all
 	Channels  []string `yaml:"channels" mapstructure:"channels"`
+
+	// PathPatterns restricts the rule to drift at or under these field paths,
+	// such as "$.pricing". Severity may then be omitted to match any severity.
+	PathPatterns []string `yaml:"path_patterns,omitempty" mapstructure:"path_patterns"`
 }
 
 // ReportingConfig contains reporting configuration
 type ReportingConfig struct {
-	RetentionDays int    `yaml:"retention_days" mapstructure:"retention_days"`
-	ExportFormat  string `yaml:"export_format" mapstructure:"export_format"` // json, csv, yaml
-	IncludeBody   bool   `yaml:"include_body" mapstructure:"include_body"`
+	RetentionDays int           `yaml:"retention_days" mapstructure:"retention_days"`
+	ExportFormat  string        `yaml:"export_format" mapstructure:"export_format"` // json, csv, yaml
+	IncludeBody   bool          `yaml:"include_body" mapstructure:"include_body"`
+	Signing       SigningConfig `yaml:"signing,omitempty" mapstructure:"signing"`
+}
+
+// SigningConfig contains keys used to sign and verify exports
+type SigningConfig struct {
+	Algorithm     string `yaml:"algorithm,omitempty" mapstructure:"algorithm"`             // hmac-sha256, ed25519
+	Key           string `yaml:"key,omitempty" mapstructure:"key"`                         // HMAC secret, supports ${VAR}
+	KeyFile       string `yaml:"key_file,omitempty" mapstructure:"key_file"`               // HMAC secret file or ed25519 PEM private key
+	PublicKeyFile string `yaml:"public_key_file,omitempty" mapstructure:"public_key_file"` // ed25519 PEM public key for verification
 }
 
 // RetentionConfig contains data retention policies
@@ -143,6 +464,51 @@ type RetentionConfig struct {
 	AlertsDays         int           `yaml:"alerts_days" mapstructure:"alerts_days"`
 	AutoCleanup        bool          `yaml:"auto_cleanup" mapstructure:"auto_cleanup"`
 	CleanupInterval    time.Duration `yaml:"cleanup_interval" mapstructure:"cleanup_interval"`
+
+	// CompactAfter is the age from which cleanup merges consecutive monitoring
+	// runs with the same status and response body into one run counting their
+	// checks, such as 24h. Zero keeps every run.
+	CompactAfter time.Duration `yaml:"compact_after,omitempty" mapstructure:"compact_after"`
+}
+
+// DriftSinkConfig configures the outbound stream of every detected drift to a
+// collector. Unlike alerting it is not filtered by severity or rules.
+type DriftSinkConfig struct {
+	Enabled       bool              `yaml:"enabled" mapstructure:"enabled"`
+	URL           string            `yaml:"url" mapstructure:"url"`
+	Headers       map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	BatchSize     int               `yaml:"batch_size" mapstructure:"batch_size"`         // drifts per request
+	FlushInterval time.Duration     `yaml:"flush_interval" mapstructure:"flush_interval"` // how often pending drifts are sent
+}
+
+// OTelConfig configures the export of every detected drift as an OpenTelemetry
+// log record, sent over OTLP/HTTP to a collector
+type OTelConfig struct {
+	Enabled       bool              `yaml:"enabled" mapstructure:"enabled"`
+	Endpoint      string            `yaml:"endpoint" mapstructure:"endpoint"` // OTLP/HTTP endpoint such as http://collector:4318; logs are sent to its /v1/logs path
+	Headers       map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	ServiceName   string            `yaml:"service_name,omitempty" mapstructure:"service_name"` // service.name of the exported records
+	BatchSize     int               `yaml:"batch_size" mapstructure:"batch_size"`               // drifts per request
+	FlushInterval time.Duration     `yaml:"flush_interval" mapstructure:"flush_interval"`       // how often pending drifts are sent
+}
+
+// HeartbeatConfig configures the heartbeat the monitor records while it runs,
+// so that external monitoring can alert when the monitor itself stops. A
+// heartbeat is recorded when a check completes, at most once per interval, so
+// heartbeats come no more often than the most frequently checked endpoint.
+type HeartbeatConfig struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`   // minimum time between heartbeats
+	File     string        `yaml:"file,omitempty" mapstructure:"file"` // also written with each heartbeat
+	URL      string        `yaml:"url,omitempty" mapstructure:"url"`   // dead man's switch pinged after each heartbeat
+}
+
+// APIConfig protects the read-only API started by serve-api. Requests must
+// carry the bearer token or the basic auth credentials, whichever are set.
+type APIConfig struct {
+	Token    string `yaml:"token,omitempty" mapstructure:"token"`       // supports ${VAR}
+	Username string `yaml:"username,omitempty" mapstructure:"username"` // basic auth
+	Password string `yaml:"password,omitempty" mapstructure:"password"` // supports ${VAR}
 }
 
 // DefaultConfig returns a configuration with sensible defaults
@@ -160,6 +526,10 @@ func DefaultConfig() *Config {
 			RetryDelay:  5 * time.Second,
 			MaxWorkers:  10,
 			DatabaseURL: "./driftwatch.db",
+
+			MaxDriftsPerCheck:   100,
+			TLSExpiryWarning:    14 * 24 * time.Hour,
+			DatabaseBusyTimeout: 5 * time.Second,
 		},
 		Endpoints: []EndpointConfig{},
 		Alerting: AlertingConfig{
@@ -179,13 +549,50 @@ func DefaultConfig() *Config {
 			AutoCleanup:        true,
 			CleanupInterval:    24 * time.Hour,
 		},
+		DriftSink: DriftSinkConfig{
+			BatchSize:     100,
+			FlushInterval: 10 * time.Second,
+		},
+		OTel: OTelConfig{
+			ServiceName:   "driftwatch",
+			BatchSize:     100,
+			FlushInterval: 10 * time.Second,
+		},
+		Heartbeat: HeartbeatConfig{
+			Interval: time.Minute,
+		},
 	}
 }
 
-// LoadConfig loads configuration from file and environment variables
+// ConfigEnvVar names the environment variable holding an inline YAML or JSON
+// configuration, used when no configuration file is found
+const ConfigEnvVar = "DRIFTWATCH_CONFIG"
+
+// LoadConfig loads configuration from file and environment variables. When no
+// file is given and .driftwatch.yaml is not found, the configuration is read from
+// the DRIFTWATCH_CONFIG environment variable if set, or defaults are used.
 func LoadConfig(configFile string) (*Config, error) {
-	// Set up Viper
-	v := viper.New()
+	v, err := readConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfig(v)
+}
+
+// LoadRawConfig loads configuration like LoadConfig, but as written: ${VAR}
+// references are left in place and the configuration is not validated
+func LoadRawConfig(configFile string) (*Config, error) {
+	v, err := readConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalConfig(v)
+}
+
+// readConfig reads the configuration file, or the inline configuration when
+// there is no file, into Viper
+func readConfig(configFile string) (*viper.Viper, error) {
+	v := newViper()
 
 	if configFile != "" {
 		v.SetConfigFile(configFile)
@@ -195,6 +602,50 @@ func LoadConfig(configFile string) (*Config, error) {
 		v.SetConfigName(".driftwatch")
 	}
 
+	// Read config file
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_READ_ERROR", "failed to read config file").
+				WithSeverity(errors.SeverityHigh).
+				WithGuidance("Check file permissions and YAML syntax")
+		}
+
+		// Config file not found is OK, we'll use inline configuration or defaults
+		if inline := os.Getenv(ConfigEnvVar); strings.TrimSpace(inline) != "" {
+			if err := readInlineConfig(v, []byte(inline)); err != nil {
+				return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_READ_ERROR", "failed to read config from "+ConfigEnvVar).
+					WithSeverity(errors.SeverityHigh).
+					WithGuidance("Check the YAML or JSON syntax of " + ConfigEnvVar)
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// LoadConfigFromReader loads YAML or JSON configuration from r, such as standard
+// input. Defaults and environment variables apply as with LoadConfig.
+func LoadConfigFromReader(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_READ_ERROR", "failed to read config").
+			WithSeverity(errors.SeverityHigh)
+	}
+
+	v := newViper()
+	if err := readInlineConfig(v, data); err != nil {
+		return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_READ_ERROR", "failed to read config").
+			WithSeverity(errors.SeverityHigh).
+			WithGuidance("Check YAML or JSON syntax")
+	}
+
+	return decodeConfig(v)
+}
+
+// newViper creates a Viper instance with defaults and environment overrides
+func newViper() *viper.Viper {
+	v := viper.New()
+
 	// Enable environment variable substitution
 	v.AutomaticEnv()
 	v.SetEnvPrefix("DRIFTWATCH")
@@ -203,23 +654,25 @@ func LoadConfig(configFile string) (*Config, error) {
 	// Set defaults
 	setDefaults(v)
 
-	// Read config file
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found is OK, we'll use defaults
-		} else {
-			return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_READ_ERROR", "failed to read config file").
-				WithSeverity(errors.SeverityHigh).
-				WithGuidance("Check file permissions and YAML syntax")
-		}
+	return v
+}
+
+// readInlineConfig reads configuration content into Viper. Content starting
+// with "{" is read as JSON, anything else as YAML.
+func readInlineConfig(v *viper.Viper, data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		v.SetConfigType("json")
+	} else {
+		v.SetConfigType("yaml")
 	}
+	return v.ReadConfig(bytes.NewReader(data))
+}
 
-	// Unmarshal into config struct
-	config := &Config{}
-	if err := v.Unmarshal(config); err != nil {
-		return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_UNMARSHAL_ERROR", "failed to unmarshal config").
-			WithSeverity(errors.SeverityHigh).
-			WithGuidance("Check configuration file structure and field types")
+// decodeConfig unmarshals, substitutes and validates the configuration read into Viper
+func decodeConfig(v *viper.Viper) (*Config, error) {
+	config, err := unmarshalConfig(v)
+	if err != nil {
+		return nil, err
 	}
 
 	// Perform environment variable substitution
@@ -238,6 +691,17 @@ func LoadConfig(configFile string) (*Config, error) {
 	return config, nil
 }
 
+// unmarshalConfig unmarshals the configuration read into Viper
+func unmarshalConfig(v *viper.Viper) (*Config, error) {
+	config := &Config{}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, errors.WrapError(err, errors.ErrorTypeConfig, "CONFIG_UNMARSHAL_ERROR", "failed to unmarshal config").
+			WithSeverity(errors.SeverityHigh).
+			WithGuidance("Check configuration file structure and field types")
+	}
+	return config, nil
+}
+
 // setDefaults sets default values in Viper
 func setDefaults(v *viper.Viper) {
 	defaults := DefaultConfig()
@@ -252,6 +716,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("global.retry_delay", defaults.Global.RetryDelay)
 	v.SetDefault("global.max_workers", defaults.Global.MaxWorkers)
 	v.SetDefault("global.database_url", defaults.Global.DatabaseURL)
+	v.SetDefault("global.max_drifts_per_check", defaults.Global.MaxDriftsPerCheck)
+	v.SetDefault("global.tls_expiry_warning", defaults.Global.TLSExpiryWarning)
+	v.SetDefault("global.database_busy_timeout", defaults.Global.DatabaseBusyTimeout)
 
 	v.SetDefault("alerting.enabled", defaults.Alerting.Enabled)
 
@@ -264,6 +731,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("retention.alerts_days", defaults.Retention.AlertsDays)
 	v.SetDefault("retention.auto_cleanup", defaults.Retention.AutoCleanup)
 	v.SetDefault("retention.cleanup_interval", defaults.Retention.CleanupInterval)
+
+	v.SetDefault("drift_sink.batch_size", defaults.DriftSink.BatchSize)
+	v.SetDefault("drift_sink.flush_interval", defaults.DriftSink.FlushInterval)
+
+	v.SetDefault("otel.service_name", defaults.OTel.ServiceName)
+	v.SetDefault("otel.batch_size", defaults.OTel.BatchSize)
+	v.SetDefault("otel.flush_interval", defaults.OTel.FlushInterval)
+
+	v.SetDefault("heartbeat.interval", defaults.Heartbeat.Interval)
 }
 
 // substituteEnvVars performs environment variable substitution in configuration values
@@ -284,6 +760,42 @@ func substituteEnvVars(config *Config) {
 		}
 	}
 
+	// Substitute in export signing key
+	config.Reporting.Signing.Key = envVarRegex.ReplaceAllStringFunc(config.Reporting.Signing.Key, func(match string) string {
+		envVar := strings.Trim(match, "${}")
+		if envValue := os.Getenv(envVar); envValue != "" {
+			return envValue
+		}
+		return match
+	})
+
+	// Substitute in drift sink headers
+	for key, value := range config.DriftSink.Headers {
+		config.DriftSink.Headers[key] = envVarRegex.ReplaceAllStringFunc(value, func(match string) string {
+			envVar := strings.Trim(match, "${}")
+			if envValue := os.Getenv(envVar); envValue != "" {
+				return envValue
+			}
+			return match
+		})
+	}
+
+	// Substitute in API credentials
+	config.API.Token = envVarRegex.ReplaceAllStringFunc(config.API.Token, func(match string) string {
+		envVar := strings.Trim(match, "${}")
+		if envValue := os.Getenv(envVar); envValue != "" {
+			return envValue
+		}
+		return match
+	})
+	config.API.Password = envVarRegex.ReplaceAllStringFunc(config.API.Password, func(match string) string {
+		envVar := strings.Trim(match, "${}")
+		if envValue := os.Getenv(envVar); envValue != "" {
+			return envValue
+		}
+		return match
+	})
+
 	// Substitute in alert channel settings
 	for i := range config.Alerting.Channels {
 		for key, value := range config.Alerting.Channels[i].Settings {
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,10 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 5*time.Second, config.Global.RetryDelay)
 	assert.Equal(t, 10, config.Global.MaxWorkers)
 	assert.Equal(t, "./driftwatch.db", config.Global.DatabaseURL)
+	assert.Equal(t, 100, config.Global.MaxDriftsPerCheck)
+	assert.Equal(t, 14*24*time.Hour, config.Global.TLSExpiryWarning)
+	assert.Equal(t, 0, config.Global.ComparisonCacheSize)
+	assert.Equal(t, 5*time.Second, config.Global.DatabaseBusyTimeout)
 	assert.False(t, config.Alerting.Enabled)
 	assert.Equal(t, 30, config.Reporting.RetentionDays)
 	assert.Equal(t, "json", config.Reporting.ExportFormat)
@@ -140,6 +145,83 @@ project:
 	assert.Contains(t, err.Error(), "failed to read config file")
 }
 
+func TestLoadConfig_FromEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	t.Run("inline YAML", func(t *testing.T) {
+		t.Setenv(ConfigEnvVar, `
+project:
+  name: "Env Project"
+endpoints:
+  - id: "users"
+    url: "https://api.example.com/users"
+    method: "GET"
+    interval: "5m"
+    enabled: true
+`)
+
+		config, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "Env Project", config.Project.Name)
+		require.Len(t, config.Endpoints, 1)
+		assert.Equal(t, 5*time.Minute, config.Endpoints[0].Interval)
+		assert.Equal(t, "driftwatch/1.0.0", config.Global.UserAgent)
+	})
+
+	t.Run("inline JSON", func(t *testing.T) {
+		t.Setenv(ConfigEnvVar, `{"project": {"name": "JSON Project"}, "global": {"max_workers": 4}}`)
+
+		config, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "JSON Project", config.Project.Name)
+		assert.Equal(t, 4, config.Global.MaxWorkers)
+	})
+
+	t.Run("config file takes precedence", func(t *testing.T) {
+		t.Setenv(ConfigEnvVar, `{"project": {"name": "JSON Project"}}`)
+		require.NoError(t, os.WriteFile(".driftwatch.yaml", []byte("project:\n  name: File Project\n"), 0o644))
+		defer os.Remove(".driftwatch.yaml")
+
+		config, err := LoadConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "File Project", config.Project.Name)
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		t.Setenv(ConfigEnvVar, `{"project": `)
+
+		_, err := LoadConfig("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read config from "+ConfigEnvVar)
+	})
+
+	t.Run("validation still applies", func(t *testing.T) {
+		t.Setenv(ConfigEnvVar, `{"global": {"max_workers": 500}}`)
+
+		_, err := LoadConfig("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max workers cannot exceed 100")
+	})
+}
+
+func TestLoadConfigFromReader(t *testing.T) {
+	config, err := LoadConfigFromReader(strings.NewReader("project:\n  name: Stdin Project\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "Stdin Project", config.Project.Name)
+	assert.Equal(t, 30*time.Second, config.Global.Timeout)
+
+	config, err = LoadConfigFromReader(strings.NewReader(`{"global": {"retry_count": 1}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, config.Global.RetryCount)
+
+	_, err = LoadConfigFromReader(strings.NewReader("project: [\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config")
+
+	_, err = LoadConfigFromReader(strings.NewReader(`{"global": {"retry_count": -1}}`))
+	require.Error(t, err)
+}
+
 func TestSubstituteEnvVars(t *testing.T) {
 	// Set test environment variables
 	os.Setenv("TEST_TOKEN", "secret-token")
@@ -311,6 +393,50 @@ func TestGetEnabledEndpoints(t *testing.T) {
 	assert.Equal(t, "enabled-2", enabled[1].ID)
 }
 
+func TestExpandedEndpoints(t *testing.T) {
+	config := DefaultConfig()
+	config.Endpoints = []EndpointConfig{
+		{ID: "health", URL: "https://api.test.com/health", Enabled: true},
+		{
+			ID:      "users",
+			URL:     "https://api.test.com/users?page=1&locale=de",
+			Headers: map[string]string{"Accept": "application/json"},
+			Enabled: true,
+			Variants: []EndpointVariant{
+				{Name: "en", Query: map[string]string{"locale": "en"}},
+				{Name: "fr", Query: map[string]string{"locale": "fr"}, Headers: map[string]string{"Accept-Language": "fr"}},
+			},
+		},
+	}
+
+	expanded := config.ExpandedEndpoints()
+	require.Len(t, expanded, 3)
+
+	assert.Equal(t, "health", expanded[0].ID)
+
+	assert.Equal(t, "users-en", expanded[1].ID)
+	assert.Equal(t, "https://api.test.com/users?locale=en&page=1", expanded[1].URL)
+	assert.Equal(t, map[string]string{"Accept": "application/json"}, expanded[1].Headers)
+	assert.Empty(t, expanded[1].Variants)
+
+	assert.Equal(t, "users-fr", expanded[2].ID)
+	assert.Equal(t, "https://api.test.com/users?locale=fr&page=1", expanded[2].URL)
+	assert.Equal(t, map[string]string{"Accept": "application/json", "Accept-Language": "fr"}, expanded[2].Headers)
+
+	// The configured endpoint is left unchanged
+	assert.Len(t, config.Endpoints[1].Headers, 1)
+
+	// Variants can be looked up by the ID they are checked under
+	variant, err := config.GetEndpoint("users-fr")
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.test.com/users?locale=fr&page=1", variant.URL)
+
+	// Variant IDs map back to the endpoint they were expanded from
+	assert.Equal(t, "users", config.BaseEndpointID("users-fr"))
+	assert.Equal(t, "health", config.BaseEndpointID("health"))
+	assert.Equal(t, "users-de", config.BaseEndpointID("users-de"))
+}
+
 func TestGetConfigFilePath(t *testing.T) {
 	// Test with provided config file
 	path := GetConfigFilePath("custom-config.yaml")
@@ -320,3 +446,47 @@ func TestGetConfigFilePath(t *testing.T) {
 	path = GetConfigFilePath("")
 	assert.Equal(t, ".driftwatch.yaml", path)
 }
+
+func TestApplySensitivity(t *testing.T) {
+	endpoint := EndpointConfig{
+		ID:         "users",
+		Validation: ValidationConfig{IgnoreFields: []string{"meta.generated", "request_id"}},
+	}
+
+	assert.Equal(t, endpoint, ApplySensitivity(endpoint, ""))
+
+	strict := ApplySensitivity(endpoint, SensitivityStrict)
+	assert.True(t, strict.Validation.StrictMode)
+	assert.Equal(t, []string{"meta.generated", "request_id"}, strict.Validation.IgnoreFields)
+	assert.False(t, strict.Validation.TreatsNullAsMissing())
+
+	balanced := ApplySensitivity(endpoint, SensitivityBalanced)
+	assert.Equal(t, []string{"meta.generated", "request_id", "timestamp", "trace_id", "correlation_id"}, balanced.Validation.IgnoreFields)
+	assert.True(t, balanced.Validation.TreatsNullAsMissing())
+	assert.False(t, balanced.Validation.ComparesShapeOnly())
+	assert.Zero(t, balanced.Validation.NumericTolerance)
+	assert.Equal(t, []string{"meta.generated", "request_id"}, endpoint.Validation.IgnoreFields, "the endpoint is not modified")
+
+	lenient := ApplySensitivity(endpoint, SensitivityLenient)
+	assert.True(t, lenient.Validation.ComparesShapeOnly())
+	assert.True(t, lenient.Validation.TreatsNullAsMissing())
+	assert.Equal(t, 0.01, lenient.Validation.NumericTolerance)
+
+	// The endpoint's own preset and options take precedence
+	endpoint.Validation.Sensitivity = SensitivityLenient
+	endpoint.Validation.NumericTolerance = 0.1
+	lenient = ApplySensitivity(endpoint, SensitivityStrict)
+	assert.False(t, lenient.Validation.StrictMode)
+	assert.True(t, lenient.Validation.ComparesShapeOnly())
+	assert.Equal(t, 0.1, lenient.Validation.NumericTolerance)
+	assert.Equal(t, lenient, ApplySensitivity(lenient, SensitivityStrict), "applying a preset again changes nothing")
+
+	// Options turned off explicitly stay off
+	disabled := false
+	endpoint.Validation.NullAsMissing = &disabled
+	endpoint.Validation.ShapeOnly = &disabled
+	lenient = ApplySensitivity(endpoint, "")
+	assert.False(t, lenient.Validation.TreatsNullAsMissing())
+	assert.False(t, lenient.Validation.ComparesShapeOnly())
+	assert.Equal(t, 0.1, lenient.Validation.NumericTolerance)
+}
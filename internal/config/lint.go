@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LintWarning is a configuration smell: valid, but likely to cause noisy or
+// unsafe monitoring
+type LintWarning struct {
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// lintMinInterval is the monitoring interval below which an endpoint is
+// reported as checked too often. Validation rejects intervals under a minute.
+const lintMinInterval = 5 * time.Minute
+
+// secretHeaders lists the lowercase names of headers that carry credentials
+var secretHeaders = map[string]bool{
+	"authorization": true,
+	"x-api-key":     true,
+	"api-key":       true,
+	"x-auth-token":  true,
+	"cookie":        true,
+}
+
+// secretSettingKeys lists substrings of alert channel setting names that carry
+// credentials or secret URLs
+var secretSettingKeys = []string{"token", "password", "secret", "webhook_url", "api_key", "routing_key"}
+
+// LintConfig reports best-practice warnings for a configuration as written,
+// before ${VAR} references are substituted (see LoadRawConfig). Unlike
+// ValidateConfig, none of them prevent the configuration from being used.
+func LintConfig(config *Config) []LintWarning {
+	var warnings []LintWarning
+
+	for i, endpoint := range config.Endpoints {
+		warnings = append(warnings, lintEndpoint(&endpoint, fmt.Sprintf("endpoints[%d]", i))...)
+	}
+
+	warnings = append(warnings, lintAlerting(&config.Alerting)...)
+	warnings = append(warnings, lintRetention(&config.Retention)...)
+
+	if isHardcodedSecret(config.API.Token) {
+		warnings = append(warnings, hardcodedSecretWarning("api.token"))
+	}
+	if isHardcodedSecret(config.API.Password) {
+		warnings = append(warnings, hardcodedSecretWarning("api.password"))
+	}
+	if isHardcodedSecret(config.Reporting.Signing.Key) {
+		warnings = append(warnings, hardcodedSecretWarning("reporting.signing.key"))
+	}
+	for _, header := range slices.Sorted(maps.Keys(config.DriftSink.Headers)) {
+		if secretHeaders[strings.ToLower(header)] && isHardcodedSecret(config.DriftSink.Headers[header]) {
+			warnings = append(warnings, hardcodedSecretWarning("drift_sink.headers."+header))
+		}
+	}
+	for _, header := range slices.Sorted(maps.Keys(config.OTel.Headers)) {
+		if secretHeaders[strings.ToLower(header)] && isHardcodedSecret(config.OTel.Headers[header]) {
+			warnings = append(warnings, hardcodedSecretWarning("otel.headers."+header))
+		}
+	}
+
+	return warnings
+}
+
+// lintEndpoint reports the warnings for one endpoint
+func lintEndpoint(endpoint *EndpointConfig, fieldPrefix string) []LintWarning {
+	var warnings []LintWarning
+
+	if endpoint.Interval > 0 && endpoint.Interval < lintMinInterval {
+		warnings = append(warnings, LintWarning{
+			Field:      fieldPrefix + ".interval",
+			Message:    fmt.Sprintf("endpoint %s is checked every %s", endpoint.ID, endpoint.Interval),
+			Suggestion: "check every 5 minutes or less often unless drift must be caught within minutes; short intervals load the API and fill the database",
+		})
+	}
+
+	validation := endpoint.Validation
+	if len(validation.IgnoreFields) == 0 && len(validation.IgnoreValuePatterns) == 0 &&
+		len(validation.TrackedFields) == 0 && !validation.ComparesShapeOnly() && validation.Sensitivity == "" {
+		warnings = append(warnings, LintWarning{
+			Field:      fieldPrefix + ".validation.ignore_fields",
+			Message:    fmt.Sprintf("endpoint %s ignores no fields", endpoint.ID),
+			Suggestion: "if responses carry timestamps or request IDs, every check reports drift; list them in ignore_fields, or run 'driftwatch init-endpoint <url>' to find them",
+		})
+	}
+
+	for _, header := range slices.Sorted(maps.Keys(endpoint.Headers)) {
+		if secretHeaders[strings.ToLower(header)] && isHardcodedSecret(endpoint.Headers[header]) {
+			warnings = append(warnings, hardcodedSecretWarning(fmt.Sprintf("%s.headers.%s", fieldPrefix, header)))
+		}
+	}
+
+	if endpoint.Auth != nil {
+		warnings = append(warnings, lintAuth(endpoint.Auth, fieldPrefix+".auth")...)
+	}
+
+	return warnings
+}
+
+// lintAuth reports credentials written in the configuration. Authentication
+// secrets are not substituted from the environment, so the suggestion is to
+// read them from a file.
+func lintAuth(auth *AuthConfig, fieldPrefix string) []LintWarning {
+	var warnings []LintWarning
+	hardcoded := func(field, fileField string) {
+		warnings = append(warnings, LintWarning{
+			Field:      fieldPrefix + "." + field,
+			Message:    "credential is written in the configuration file",
+			Suggestion: fmt.Sprintf("keep it out of the file with %s, which is read on every request and picks up rotated credentials", fileField),
+		})
+	}
+
+	if auth.Bearer != nil && auth.Bearer.Token != "" {
+		hardcoded("bearer.token", "bearer.token_file")
+	}
+	if auth.Basic != nil && auth.Basic.Password != "" {
+		hardcoded("basic.password", "basic.password_file")
+	}
+	if auth.APIKey != nil && auth.APIKey.Value != "" {
+		hardcoded("api_key.value", "api_key.value_file")
+	}
+	if auth.OAuth2 != nil && auth.OAuth2.ClientSecret != "" {
+		warnings = append(warnings, LintWarning{
+			Field:      fieldPrefix + ".oauth2.client_secret",
+			Message:    "credential is written in the configuration file",
+			Suggestion: "restrict who can read the configuration file and keep it out of version control",
+		})
+	}
+
+	return warnings
+}
+
+// lintAlerting reports a setup in which drift is never alerted on
+func lintAlerting(alerting *AlertingConfig) []LintWarning {
+	var warnings []LintWarning
+
+	enabledChannels := 0
+	for i, channel := range alerting.Channels {
+		if channel.Enabled {
+			enabledChannels++
+		}
+		for _, key := range slices.Sorted(maps.Keys(channel.Settings)) {
+			value, ok := channel.Settings[key].(string)
+			if ok && isSecretSetting(key) && isHardcodedSecret(value) {
+				warnings = append(warnings, hardcodedSecretWarning(fmt.Sprintf("alerting.channels[%d].settings.%s", i, key)))
+			}
+		}
+	}
+
+	switch {
+	case !alerting.Enabled:
+		warnings = append(warnings, LintWarning{
+			Field:      "alerting.enabled",
+			Message:    "alerting is disabled, so drift is only recorded",
+			Suggestion: "enable alerting and add a channel so that someone is told about breaking changes",
+		})
+	case enabledChannels == 0:
+		warnings = append(warnings, LintWarning{
+			Field:      "alerting.channels",
+			Message:    "alerting is enabled but no channel is enabled",
+			Suggestion: "add a slack, email or webhook channel, or enable an existing one",
+		})
+	}
+
+	return warnings
+}
+
+// lintRetention reports monitoring data that is never cleaned up
+func lintRetention(retention *RetentionConfig) []LintWarning {
+	if retention.AutoCleanup && (retention.MonitoringRunsDays > 0 || retention.DriftsDays > 0) {
+		return nil
+	}
+
+	return []LintWarning{{
+		Field:      "retention.auto_cleanup",
+		Message:    "no retention is configured, so the database grows without bound",
+		Suggestion: "enable retention.auto_cleanup with monitoring_runs_days and drifts_days, or run 'driftwatch cleanup' on a schedule",
+	}}
+}
+
+// isHardcodedSecret reports whether a secret value is written out rather than
+// referenced from the environment with ${VAR}
+func isHardcodedSecret(value string) bool {
+	return value != "" && !strings.Contains(value, "${")
+}
+
+// isSecretSetting reports whether an alert channel setting carries a secret
+func isSecretSetting(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, secret := range secretSettingKeys {
+		if strings.Contains(lowerKey, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// hardcodedSecretWarning reports a secret that should come from the environment
+func hardcodedSecretWarning(field string) LintWarning {
+	return LintWarning{
+		Field:      field,
+		Message:    "secret is written in the configuration file",
+		Suggestion: "reference an environment variable instead, such as ${API_TOKEN}, so that the file can be shared and committed",
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintConfig(t *testing.T) {
+	lintFields := func(config *Config) []string {
+		var fields []string
+		for _, warning := range LintConfig(config) {
+			fields = append(fields, warning.Field)
+		}
+		return fields
+	}
+
+	t.Run("clean configuration", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Alerting = AlertingConfig{
+			Enabled:  true,
+			Channels: []AlertChannelConfig{{Type: "slack", Name: "team", Enabled: true, Settings: map[string]interface{}{"webhook_url": "${SLACK_WEBHOOK_URL}"}}},
+		}
+		config.Endpoints = []EndpointConfig{{
+			ID:         "users",
+			Interval:   5 * time.Minute,
+			Headers:    map[string]string{"Authorization": "Bearer ${API_TOKEN}"},
+			Validation: ValidationConfig{IgnoreFields: []string{"timestamp"}},
+		}}
+
+		assert.Empty(t, LintConfig(config))
+	})
+
+	t.Run("smells", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Retention.AutoCleanup = false
+		config.API.Token = "s3cret"
+		config.Alerting = AlertingConfig{
+			Enabled:  true,
+			Channels: []AlertChannelConfig{{Type: "slack", Name: "team", Settings: map[string]interface{}{"webhook_url": "https://hooks.slack.com/services/T0/B0/x", "channel": "#api"}}},
+		}
+		config.Endpoints = []EndpointConfig{{
+			ID:       "users",
+			Interval: 2 * time.Minute,
+			Headers:  map[string]string{"X-API-Key": "abc123", "Accept": "application/json"},
+			Auth:     &AuthConfig{Type: AuthTypeBearer, Bearer: &BearerAuth{Token: "abc123"}},
+		}}
+
+		assert.ElementsMatch(t, []string{
+			"endpoints[0].interval",
+			"endpoints[0].validation.ignore_fields",
+			"endpoints[0].headers.X-API-Key",
+			"endpoints[0].auth.bearer.token",
+			"alerting.channels[0].settings.webhook_url",
+			"alerting.channels",
+			"retention.auto_cleanup",
+			"api.token",
+		}, lintFields(config))
+	})
+
+	t.Run("alerting disabled", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Alerting.Enabled = false
+		assert.Equal(t, []string{"alerting.enabled"}, lintFields(config))
+	})
+
+	t.Run("comparison presets count as ignoring fields", func(t *testing.T) {
+		shapeOnly := true
+		config := DefaultConfig()
+		config.Alerting = AlertingConfig{Enabled: true, Channels: []AlertChannelConfig{{Type: "webhook", Enabled: true}}}
+		config.Endpoints = []EndpointConfig{
+			{ID: "search", Interval: time.Hour, Validation: ValidationConfig{ShapeOnly: &shapeOnly}},
+			{ID: "orders", Interval: time.Hour, Validation: ValidationConfig{Sensitivity: SensitivityLenient}},
+		}
+		assert.Empty(t, LintConfig(config))
+	})
+}
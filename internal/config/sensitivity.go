@@ -0,0 +1,83 @@
+package config
+
+import "slices"
+
+// Sensitivity names a preset of comparison options, so that endpoints can be
+// tuned without setting each option
+type Sensitivity string
+
+const (
+	// SensitivityStrict reports every change and validates responses against
+	// their spec in strict mode
+	SensitivityStrict Sensitivity = "strict"
+	// SensitivityBalanced ignores common per-request fields and treats explicit
+	// nulls like absent fields
+	SensitivityBalanced Sensitivity = "balanced"
+	// SensitivityLenient compares responses by shape only, on top of the
+	// balanced preset, and tolerates small numeric changes at tracked fields
+	SensitivityLenient Sensitivity = "lenient"
+)
+
+// presetIgnoreFields lists the top-level fields that commonly carry a new value
+// on every request, which the balanced and lenient presets ignore
+var presetIgnoreFields = []string{"timestamp", "request_id", "trace_id", "correlation_id"}
+
+// lenientNumericTolerance is the relative change of numeric values the lenient
+// preset does not report
+const lenientNumericTolerance = 0.01
+
+// IsValid reports whether the sensitivity names a preset
+func (s Sensitivity) IsValid() bool {
+	switch s {
+	case SensitivityStrict, SensitivityBalanced, SensitivityLenient:
+		return true
+	}
+	return false
+}
+
+// WithSensitivity returns the validation settings with a sensitivity preset
+// applied. Presets only add to the settings: options the endpoint sets itself,
+// such as its numeric tolerance or shape_only: false, take precedence, and its ignored fields are
+// kept alongside the preset's. An empty or unknown preset changes nothing.
+func (v ValidationConfig) WithSensitivity(preset Sensitivity) ValidationConfig {
+	switch preset {
+	case SensitivityStrict:
+		v.StrictMode = true
+	case SensitivityBalanced, SensitivityLenient:
+		v.IgnoreFields = slices.Clone(v.IgnoreFields)
+		for _, field := range presetIgnoreFields {
+			if !slices.Contains(v.IgnoreFields, field) {
+				v.IgnoreFields = append(v.IgnoreFields, field)
+			}
+		}
+		if v.NullAsMissing == nil {
+			enabled := true
+			v.NullAsMissing = &enabled
+		}
+
+		if preset == SensitivityLenient {
+			if v.ShapeOnly == nil {
+				enabled := true
+				v.ShapeOnly = &enabled
+			}
+			if v.NumericTolerance == 0 {
+				v.NumericTolerance = lenientNumericTolerance
+			}
+		}
+	}
+
+	return v
+}
+
+// ApplySensitivity returns a copy of an endpoint with its sensitivity preset
+// applied to its validation settings. Endpoints without a preset of their own
+// use defaultSensitivity, normally global.sensitivity.
+func ApplySensitivity(endpoint EndpointConfig, defaultSensitivity Sensitivity) EndpointConfig {
+	preset := endpoint.Validation.Sensitivity
+	if preset == "" {
+		preset = defaultSensitivity
+	}
+
+	endpoint.Validation = endpoint.Validation.WithSensitivity(preset)
+	return endpoint
+}
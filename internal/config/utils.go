@@ -122,13 +122,19 @@ func (c *Config) RemoveEndpoint(id string) error {
 	return fmt.Errorf("endpoint with ID '%s' not found", id)
 }
 
-// GetEndpoint retrieves an endpoint by ID
+// GetEndpoint retrieves an endpoint by ID, or a variant of an endpoint by the
+// ID it is checked under
 func (c *Config) GetEndpoint(id string) (*EndpointConfig, error) {
 	for _, endpoint := range c.Endpoints {
 		if endpoint.ID == id {
 			return &endpoint, nil
 		}
 	}
+	for _, endpoint := range c.ExpandedEndpoints() {
+		if endpoint.ID == id {
+			return &endpoint, nil
+		}
+	}
 	return nil, fmt.Errorf("endpoint with ID '%s' not found", id)
 }
 
@@ -163,3 +169,15 @@ func (c *Config) GetEnabledEndpoints() []EndpointConfig {
 	}
 	return enabled
 }
+
+// MaxRunsByEndpoint returns the number of monitoring runs kept for each
+// endpoint that caps its runs, with variants capped like their endpoint
+func (c *Config) MaxRunsByEndpoint() map[string]int {
+	maxRuns := make(map[string]int)
+	for _, endpoint := range c.ExpandedEndpoints() {
+		if endpoint.MaxRuns > 0 {
+			maxRuns[endpoint.ID] = endpoint.MaxRuns
+		}
+	}
+	return maxRuns
+}
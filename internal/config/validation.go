@@ -3,9 +3,15 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 	"time"
+
+	httpClient "github.com/k0ns0l/driftwatch/internal/http"
+	"github.com/k0ns0l/driftwatch/internal/jsonpath"
 )
 
 // ValidationError represents a configuration validation error
@@ -90,6 +96,22 @@ func ValidateConfig(config *Config) error {
 			}
 			endpointIDs[endpoint.ID] = true
 		}
+
+		// Variants are stored under IDs of their own, which must not clash either
+		for j, variant := range endpoint.Variants {
+			if endpoint.ID == "" || variant.Name == "" {
+				continue
+			}
+			variantID := VariantID(endpoint.ID, variant.Name)
+			if endpointIDs[variantID] {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.variants[%d].name", fieldPrefix, j),
+					Value:   variant.Name,
+					Message: fmt.Sprintf("variant ID '%s' duplicates another endpoint ID", variantID),
+				})
+			}
+			endpointIDs[variantID] = true
+		}
 	}
 
 	// Validate alerting configuration
@@ -116,6 +138,14 @@ func ValidateConfig(config *Config) error {
 		}
 	}
 
+	// Validate drift sink configuration
+	errors = append(errors, validateDriftSink(&config.DriftSink)...)
+	errors = append(errors, validateOTel(&config.OTel)...)
+	errors = append(errors, validateHeartbeat(&config.Heartbeat)...)
+
+	// Validate API credentials
+	errors = append(errors, validateAPI(&config.API)...)
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -221,6 +251,14 @@ func validateGlobal(global *GlobalConfig) error {
 		})
 	}
 
+	if global.MaxRetryDelay < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "global.max_retry_delay",
+			Value:   global.MaxRetryDelay,
+			Message: "max retry delay cannot be negative",
+		})
+	}
+
 	if global.MaxWorkers <= 0 {
 		errors = append(errors, ValidationError{
 			Field:   "global.max_workers",
@@ -237,6 +275,73 @@ func validateGlobal(global *GlobalConfig) error {
 		})
 	}
 
+	if global.MaxDriftsPerCheck < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "global.max_drifts_per_check",
+			Value:   global.MaxDriftsPerCheck,
+			Message: "max drifts per check cannot be negative",
+		})
+	}
+
+	if global.TLSExpiryWarning < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "global.tls_expiry_warning",
+			Value:   global.TLSExpiryWarning,
+			Message: "TLS expiry warning cannot be negative",
+		})
+	}
+
+	if global.DatabaseBusyTimeout < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "global.database_busy_timeout",
+			Value:   global.DatabaseBusyTimeout,
+			Message: "database busy timeout cannot be negative",
+		})
+	}
+
+	if global.ComparisonCacheSize < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "global.comparison_cache_size",
+			Value:   global.ComparisonCacheSize,
+			Message: "comparison cache size cannot be negative",
+		})
+	}
+
+	validSeverities := map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+	if global.MinPersistSeverity != "" && !validSeverities[global.MinPersistSeverity] {
+		errors = append(errors, ValidationError{
+			Field:   "global.min_persist_severity",
+			Value:   global.MinPersistSeverity,
+			Message: "invalid severity level (supported: low, medium, high, critical)",
+		})
+	}
+
+	if global.Sensitivity != "" && !global.Sensitivity.IsValid() {
+		errors = append(errors, ValidationError{
+			Field:   "global.sensitivity",
+			Value:   global.Sensitivity,
+			Message: "invalid sensitivity (supported: strict, balanced, lenient)",
+		})
+	}
+
+	switch global.BodyStorage {
+	case "", BodyStorageDB:
+	case BodyStorageFile:
+		if strings.TrimSpace(global.BodyStorageDir) == "" {
+			errors = append(errors, ValidationError{
+				Field:   "global.body_storage_dir",
+				Value:   global.BodyStorageDir,
+				Message: "body storage directory is required when body_storage is file",
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "global.body_storage",
+			Value:   global.BodyStorage,
+			Message: "invalid body storage (supported: db, file)",
+		})
+	}
+
 	if strings.TrimSpace(global.DatabaseURL) == "" {
 		errors = append(errors, ValidationError{
 			Field:   "global.database_url",
@@ -245,6 +350,8 @@ func validateGlobal(global *GlobalConfig) error {
 		})
 	}
 
+	errors = append(errors, validateCABundle(global.CABundleFile, "global.ca_bundle_file")...)
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -261,12 +368,69 @@ func validateEndpoint(endpoint *EndpointConfig, fieldPrefix string) error {
 	errors = append(errors, validateEndpointURL(endpoint.URL, fieldPrefix)...)
 	errors = append(errors, validateEndpointMethod(endpoint.Method, fieldPrefix)...)
 
+	// Only the opening handshake of WebSocket endpoints is checked, which is a GET request
+	if httpClient.IsWebSocketURL(endpoint.URL) && endpoint.Method != "" && !strings.EqualFold(endpoint.Method, "GET") {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.method", fieldPrefix),
+			Value:   endpoint.Method,
+			Message: "WebSocket endpoints must use GET",
+		})
+	}
+
 	// Validate timing configuration
 	errors = append(errors, validateEndpointTiming(endpoint, fieldPrefix)...)
 
 	// Validate retry configuration
 	errors = append(errors, validateEndpointRetry(endpoint.RetryCount, fieldPrefix)...)
 
+	// Validate proxy configuration
+	if endpoint.ProxyURL != "" {
+		if _, err := httpClient.ParseProxyURL(endpoint.ProxyURL); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.proxy_url", fieldPrefix),
+				Value:   redactProxyURL(endpoint.ProxyURL),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	// Validate the CA bundle, which overrides global.ca_bundle_file
+	errors = append(errors, validateCABundle(endpoint.CAFile, fmt.Sprintf("%s.ca_file", fieldPrefix))...)
+
+	// Validate the stream read limit, which must end reading before the request times out
+	if endpoint.StreamReadLimit != "" {
+		limit, err := httpClient.ParseStreamReadLimit(endpoint.StreamReadLimit)
+		switch {
+		case err != nil:
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.stream_read_limit", fieldPrefix),
+				Value:   endpoint.StreamReadLimit,
+				Message: err.Error(),
+			})
+		case endpoint.Timeout > 0 && limit.Duration >= endpoint.Timeout:
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.stream_read_limit", fieldPrefix),
+				Value:   endpoint.StreamReadLimit,
+				Message: fmt.Sprintf("stream read duration must be shorter than the endpoint timeout (%s)", endpoint.Timeout),
+			})
+		}
+	}
+
+	validSeverities := map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+	if endpoint.MinPersistSeverity != "" && !validSeverities[endpoint.MinPersistSeverity] {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.min_persist_severity", fieldPrefix),
+			Value:   endpoint.MinPersistSeverity,
+			Message: "invalid severity level (supported: low, medium, high, critical)",
+		})
+	}
+
+	// Validate comparison configuration
+	errors = append(errors, validateEndpointComparison(endpoint, fieldPrefix)...)
+
+	// Validate variants
+	errors = append(errors, validateEndpointVariants(endpoint.Variants, fieldPrefix)...)
+
 	// Validate authentication configuration
 	if endpoint.Auth != nil {
 		if err := validateAuth(endpoint.Auth, fmt.Sprintf("%s.auth", fieldPrefix)); err != nil {
@@ -288,6 +452,269 @@ func validateEndpoint(endpoint *EndpointConfig, fieldPrefix string) error {
 	return nil
 }
 
+// validateCABundle validates that a CA bundle file, when set, holds PEM
+// certificates, so that a bad bundle is reported at startup rather than by
+// every request
+func validateCABundle(file, field string) ValidationErrors {
+	if file == "" {
+		return nil
+	}
+
+	if _, err := httpClient.LoadCABundle(file); err != nil {
+		return ValidationErrors{{
+			Field:   field,
+			Value:   file,
+			Message: err.Error(),
+		}}
+	}
+	return nil
+}
+
+// redactProxyURL hides the password of a proxy URL so it is not echoed in errors
+func redactProxyURL(raw string) string {
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return "<invalid>"
+	}
+	return proxyURL.Redacted()
+}
+
+// validateEndpointComparison validates settings that control how responses are compared
+func validateEndpointComparison(endpoint *EndpointConfig, fieldPrefix string) ValidationErrors {
+	var errors ValidationErrors
+
+	if endpoint.CompareRoot != "" {
+		if _, err := jsonpath.Parse(endpoint.CompareRoot); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.compare_root", fieldPrefix),
+				Value:   endpoint.CompareRoot,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if endpoint.Samples < 0 {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.samples", fieldPrefix),
+			Value:   endpoint.Samples,
+			Message: "samples cannot be negative",
+		})
+	}
+
+	if endpoint.Samples > MaxEndpointSamples {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.samples", fieldPrefix),
+			Value:   endpoint.Samples,
+			Message: fmt.Sprintf("samples cannot exceed %d", MaxEndpointSamples),
+		})
+	}
+
+	if endpoint.FullBodyEvery < 0 {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.full_body_every", fieldPrefix),
+			Value:   endpoint.FullBodyEvery,
+			Message: "full body interval cannot be negative",
+		})
+	}
+
+	if endpoint.MaxRuns < 0 {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.max_runs", fieldPrefix),
+			Value:   endpoint.MaxRuns,
+			Message: "max runs cannot be negative",
+		})
+	}
+
+	for header, pattern := range endpoint.Validation.HeaderPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.validation.header_patterns.%s", fieldPrefix, header),
+				Value:   pattern,
+				Message: fmt.Sprintf("invalid header pattern: %v", err),
+			})
+		}
+	}
+
+	for i, pattern := range endpoint.Validation.IgnoreValuePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.validation.ignore_value_patterns[%d]", fieldPrefix, i),
+				Value:   pattern,
+				Message: fmt.Sprintf("invalid value pattern: %v", err),
+			})
+		}
+	}
+
+	for i, code := range endpoint.Validation.BodyStatusCodes {
+		if code < 100 || code > 599 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.validation.body_status_codes[%d]", fieldPrefix, i),
+				Value:   code,
+				Message: "status code must be between 100 and 599",
+			})
+		}
+	}
+
+	switch endpoint.BaselineStrategy {
+	case "", BaselineStrategyPrevious:
+	case BaselineStrategyFixed:
+		if endpoint.BaselineRunID <= 0 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.baseline_run_id", fieldPrefix),
+				Value:   endpoint.BaselineRunID,
+				Message: "baseline run ID is required for the fixed baseline strategy",
+			})
+		}
+	case BaselineStrategyNAgo:
+		if endpoint.BaselineRunsAgo < 1 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.baseline_runs_ago", fieldPrefix),
+				Value:   endpoint.BaselineRunsAgo,
+				Message: "baseline runs ago must be at least 1 for the n_ago baseline strategy",
+			})
+		}
+	case BaselineStrategyFile:
+		if strings.TrimSpace(endpoint.BaselineFile) == "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.baseline_file", fieldPrefix),
+				Value:   endpoint.BaselineFile,
+				Message: "baseline file is required for the file baseline strategy",
+			})
+		}
+	case BaselineStrategyMajority:
+		if endpoint.BaselineRuns < 0 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.baseline_runs", fieldPrefix),
+				Value:   endpoint.BaselineRuns,
+				Message: "baseline runs cannot be negative",
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.baseline_strategy", fieldPrefix),
+			Value:   endpoint.BaselineStrategy,
+			Message: "invalid baseline strategy (supported: previous, fixed, n_ago, file, majority)",
+		})
+	}
+
+	switch endpoint.PerformanceMode {
+	case "", PerformanceModeDelta, PerformanceModeZScore:
+	case PerformanceModeThreshold:
+		if endpoint.PerformanceThreshold <= 0 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.performance_threshold", fieldPrefix),
+				Value:   endpoint.PerformanceThreshold,
+				Message: "performance threshold is required for the threshold performance mode",
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.performance_mode", fieldPrefix),
+			Value:   endpoint.PerformanceMode,
+			Message: "invalid performance mode (supported: delta, threshold, zscore)",
+		})
+	}
+
+	if strings.Contains(endpoint.Validation.VersionField, "[") {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.validation.version_field", fieldPrefix),
+			Value:   endpoint.Validation.VersionField,
+			Message: "version field must not select array elements",
+		})
+	}
+
+	validSeverities := map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+	if endpoint.Validation.VersionChangeSeverity != "" && !validSeverities[endpoint.Validation.VersionChangeSeverity] {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.validation.version_change_severity", fieldPrefix),
+			Value:   endpoint.Validation.VersionChangeSeverity,
+			Message: "invalid severity level (supported: low, medium, high, critical)",
+		})
+	}
+
+	if endpoint.PerformanceZScore < 0 {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.performance_zscore", fieldPrefix),
+			Value:   endpoint.PerformanceZScore,
+			Message: "performance z-score cannot be negative",
+		})
+	}
+
+	if endpoint.Validation.Sensitivity != "" && !endpoint.Validation.Sensitivity.IsValid() {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.validation.sensitivity", fieldPrefix),
+			Value:   endpoint.Validation.Sensitivity,
+			Message: "invalid sensitivity (supported: strict, balanced, lenient)",
+		})
+	}
+
+	if endpoint.Validation.NumericTolerance < 0 || endpoint.Validation.NumericTolerance >= 1 {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.validation.numeric_tolerance", fieldPrefix),
+			Value:   endpoint.Validation.NumericTolerance,
+			Message: "numeric tolerance must be at least 0 and less than 1",
+		})
+	}
+
+	switch endpoint.Validation.StatusMatch {
+	case "", StatusMatchExact, StatusMatchClass:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.validation.status_match", fieldPrefix),
+			Value:   endpoint.Validation.StatusMatch,
+			Message: "invalid status match (supported: exact, class)",
+		})
+	}
+
+	if endpoint.Validation.ArraySummaryThreshold < 0 {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("%s.validation.array_summary_threshold", fieldPrefix),
+			Value:   endpoint.Validation.ArraySummaryThreshold,
+			Message: "array summary threshold cannot be negative",
+		})
+	}
+
+	return errors
+}
+
+// validateEndpointVariants validates the variants of an endpoint, whose names
+// become part of endpoint IDs
+func validateEndpointVariants(variants []EndpointVariant, fieldPrefix string) ValidationErrors {
+	var errors ValidationErrors
+
+	names := make(map[string]bool, len(variants))
+	for i, variant := range variants {
+		field := fmt.Sprintf("%s.variants[%d].name", fieldPrefix, i)
+
+		switch {
+		case strings.TrimSpace(variant.Name) == "":
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   variant.Name,
+				Message: "variant name cannot be empty",
+			})
+		case !endpointIDPattern.MatchString(variant.Name):
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   variant.Name,
+				Message: "variant name can only contain letters, numbers, underscores, and hyphens",
+			})
+		case names[variant.Name]:
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Value:   variant.Name,
+				Message: "duplicate variant name",
+			})
+		}
+		names[variant.Name] = true
+	}
+
+	return errors
+}
+
+// endpointIDPattern matches valid endpoint IDs and variant names
+var endpointIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 // validateEndpointID validates endpoint ID
 func validateEndpointID(id, fieldPrefix string) ValidationErrors {
 	var errors ValidationErrors
@@ -299,8 +726,7 @@ func validateEndpointID(id, fieldPrefix string) ValidationErrors {
 			Message: "endpoint ID cannot be empty",
 		})
 	} else {
-		idRegex := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-		if !idRegex.MatchString(id) {
+		if !endpointIDPattern.MatchString(id) {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("%s.id", fieldPrefix),
 				Value:   id,
@@ -336,11 +762,11 @@ func validateEndpointURL(endpointURL, fieldPrefix string) ValidationErrors {
 				Value:   endpointURL,
 				Message: "invalid URL format: missing scheme or host",
 			})
-		} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" && parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("%s.url", fieldPrefix),
 				Value:   endpointURL,
-				Message: "URL scheme must be http or https",
+				Message: "URL scheme must be http, https, ws or wss",
 			})
 		}
 	}
@@ -415,6 +841,33 @@ func validateEndpointTiming(endpoint *EndpointConfig, fieldPrefix string) Valida
 		}
 	}
 
+	// Validate the connect and read timeouts, which only matter when shorter
+	// than the timeout of the whole request
+	phaseTimeouts := []struct {
+		field   string
+		name    string
+		timeout time.Duration
+	}{
+		{"connect_timeout", "connect timeout", endpoint.ConnectTimeout},
+		{"read_timeout", "read timeout", endpoint.ReadTimeout},
+	}
+	for _, phase := range phaseTimeouts {
+		switch {
+		case phase.timeout < 0:
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.%s", fieldPrefix, phase.field),
+				Value:   phase.timeout,
+				Message: fmt.Sprintf("%s cannot be negative", phase.name),
+			})
+		case endpoint.Timeout > 0 && phase.timeout > endpoint.Timeout:
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.%s", fieldPrefix, phase.field),
+				Value:   phase.timeout,
+				Message: fmt.Sprintf("%s cannot exceed the endpoint timeout (%s)", phase.name, endpoint.Timeout),
+			})
+		}
+	}
+
 	return errors
 }
 
@@ -505,7 +958,11 @@ func validateBearerAuth(bearer *BearerAuth, fieldPrefix string) ValidationErrors
 			Message: "bearer auth configuration is required when type is 'bearer'",
 		})
 	} else {
-		if strings.TrimSpace(bearer.Token) == "" {
+		switch {
+		case bearer.TokenFile != "":
+			errors = append(errors, validateSecretFile(bearer.Token, bearer.TokenFile,
+				fmt.Sprintf("%s.bearer", fieldPrefix), "token")...)
+		case strings.TrimSpace(bearer.Token) == "":
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("%s.bearer.token", fieldPrefix),
 				Value:   bearer.Token,
@@ -535,6 +992,10 @@ func validateBasicAuth(basic *BasicAuth, fieldPrefix string) ValidationErrors {
 			})
 		}
 		// Note: password can be empty for some basic auth scenarios
+		if basic.PasswordFile != "" {
+			errors = append(errors, validateSecretFile(basic.Password, basic.PasswordFile,
+				fmt.Sprintf("%s.basic", fieldPrefix), "password")...)
+		}
 	}
 
 	return errors
@@ -557,7 +1018,11 @@ func validateAPIKeyAuth(apiKey *APIKeyAuth, fieldPrefix string) ValidationErrors
 				Message: "header name cannot be empty for API key auth",
 			})
 		}
-		if strings.TrimSpace(apiKey.Value) == "" {
+		switch {
+		case apiKey.ValueFile != "":
+			errors = append(errors, validateSecretFile(apiKey.Value, apiKey.ValueFile,
+				fmt.Sprintf("%s.api_key", fieldPrefix), "value")...)
+		case strings.TrimSpace(apiKey.Value) == "":
 			errors = append(errors, ValidationError{
 				Field:   fmt.Sprintf("%s.api_key.value", fieldPrefix),
 				Value:   apiKey.Value,
@@ -569,6 +1034,39 @@ func validateAPIKeyAuth(apiKey *APIKeyAuth, fieldPrefix string) ValidationErrors
 	return errors
 }
 
+// validateSecretFile validates a credential that is read from a file. The file
+// must exist at load time; its contents are read on every request so they may change.
+func validateSecretFile(value, file, fieldPrefix, name string) ValidationErrors {
+	var errors ValidationErrors
+	field := fmt.Sprintf("%s.%s_file", fieldPrefix, name)
+
+	if value != "" {
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Value:   file,
+			Message: fmt.Sprintf("cannot set both %s and %s_file", name, name),
+		})
+	}
+
+	info, err := os.Stat(filepath.Clean(file))
+	switch {
+	case err != nil:
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Value:   file,
+			Message: fmt.Sprintf("cannot access %s file: %v", name, err),
+		})
+	case info.IsDir():
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Value:   file,
+			Message: fmt.Sprintf("%s file must be a regular file, not a directory", name),
+		})
+	}
+
+	return errors
+}
+
 // validateOAuth2Auth validates OAuth2 authentication configuration
 func validateOAuth2Auth(oauth2 *OAuth2Auth, fieldPrefix string) ValidationErrors {
 	var errors ValidationErrors
@@ -679,6 +1177,16 @@ func validateAlerting(alerting *AlertingConfig) error {
 			})
 		}
 
+		if channel.MessageTemplate != "" {
+			if _, err := template.New(channel.Name).Parse(channel.MessageTemplate); err != nil {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.message_template", fieldPrefix),
+					Value:   channel.MessageTemplate,
+					Message: fmt.Sprintf("invalid message template: %v", err),
+				})
+			}
+		}
+
 		// Validate channel-specific settings
 		if err := validateChannelSettings(channel.Type, channel.Settings, fieldPrefix); err != nil {
 			if validationErrs, ok := err.(ValidationErrors); ok {
@@ -710,6 +1218,16 @@ func validateAlerting(alerting *AlertingConfig) error {
 			}
 		}
 
+		for _, pattern := range rule.PathPatterns {
+			if strings.TrimSpace(pattern) == "" {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.path_patterns", fieldPrefix),
+					Value:   pattern,
+					Message: "path pattern cannot be empty",
+				})
+			}
+		}
+
 		// Validate that referenced channels exist
 		for _, channelName := range rule.Channels {
 			if !channelNames[channelName] {
@@ -722,6 +1240,11 @@ func validateAlerting(alerting *AlertingConfig) error {
 		}
 	}
 
+	errors = append(errors, validateQuietHours(&alerting.QuietHours)...)
+	errors = append(errors, validateEscalation(&alerting.Escalation)...)
+	errors = append(errors, validateLiveness(&alerting.Liveness)...)
+	errors = append(errors, validateAlertRetry(&alerting.Retry)...)
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -729,6 +1252,282 @@ func validateAlerting(alerting *AlertingConfig) error {
 	return nil
 }
 
+// validateQuietHours validates the alerting quiet hours window
+func validateQuietHours(quietHours *QuietHoursConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if !quietHours.Enabled {
+		return errors
+	}
+
+	for _, field := range []struct{ name, value string }{{"start", quietHours.Start}, {"end", quietHours.End}} {
+		if _, err := time.Parse("15:04", field.value); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("alerting.quiet_hours.%s", field.name),
+				Value:   field.value,
+				Message: "quiet hours time must be in HH:MM format",
+			})
+		}
+	}
+
+	if quietHours.Start != "" && quietHours.Start == quietHours.End {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.quiet_hours.end",
+			Value:   quietHours.End,
+			Message: "quiet hours start and end cannot be the same",
+		})
+	}
+
+	if quietHours.Timezone != "" {
+		if _, err := time.LoadLocation(quietHours.Timezone); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "alerting.quiet_hours.timezone",
+				Value:   quietHours.Timezone,
+				Message: "invalid timezone",
+			})
+		}
+	}
+
+	validSeverities := map[string]bool{"low": true, "medium": true, "high": true}
+	for _, severity := range quietHours.Severities {
+		if !validSeverities[severity] {
+			errors = append(errors, ValidationError{
+				Field:   "alerting.quiet_hours.severities",
+				Value:   severity,
+				Message: "invalid quiet hours severity (supported: low, medium, high; critical alerts are never suppressed)",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateEscalation validates the drift severity escalation policy
+func validateEscalation(escalation *EscalationConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if !escalation.Enabled {
+		return errors
+	}
+
+	if len(escalation.Rules) == 0 {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.escalation.rules",
+			Message: "at least one escalation rule is required when escalation is enabled",
+		})
+	}
+
+	for i, rule := range escalation.Rules {
+		fieldPrefix := fmt.Sprintf("alerting.escalation.rules[%d]", i)
+
+		if rule.After <= 0 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.after", fieldPrefix),
+				Value:   rule.After,
+				Message: "escalation age must be positive",
+			})
+		}
+
+		if rule.Levels < 1 || rule.Levels > 3 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("%s.levels", fieldPrefix),
+				Value:   rule.Levels,
+				Message: "escalation levels must be between 1 and 3",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateLiveness validates the endpoint liveness alert
+func validateLiveness(liveness *LivenessConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if !liveness.Enabled {
+		return errors
+	}
+
+	if liveness.Window <= 0 {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.liveness.window",
+			Value:   liveness.Window,
+			Message: "liveness window must be positive when liveness alerts are enabled",
+		})
+	}
+
+	validSeverities := map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+	if liveness.Severity != "" && !validSeverities[liveness.Severity] {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.liveness.severity",
+			Value:   liveness.Severity,
+			Message: "invalid severity level (supported: low, medium, high, critical)",
+		})
+	}
+
+	return errors
+}
+
+// validateAlertRetry validates alert delivery retries
+func validateAlertRetry(retry *AlertRetryConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if retry.MaxAttempts < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.retry.max_attempts",
+			Value:   retry.MaxAttempts,
+			Message: "max attempts cannot be negative",
+		})
+	}
+
+	if retry.InitialDelay < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.retry.initial_delay",
+			Value:   retry.InitialDelay,
+			Message: "initial delay cannot be negative",
+		})
+	}
+
+	if retry.MaxDelay < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.retry.max_delay",
+			Value:   retry.MaxDelay,
+			Message: "max delay cannot be negative",
+		})
+	} else if retry.MaxDelay > 0 && retry.MaxDelay < retry.InitialDelay {
+		errors = append(errors, ValidationError{
+			Field:   "alerting.retry.max_delay",
+			Value:   retry.MaxDelay,
+			Message: "max delay cannot be less than the initial delay",
+		})
+	}
+
+	return errors
+}
+
+// validateDriftSink validates the drift event stream
+func validateDriftSink(sink *DriftSinkConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if !sink.Enabled {
+		return errors
+	}
+
+	parsedURL, err := url.Parse(sink.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		errors = append(errors, ValidationError{
+			Field:   "drift_sink.url",
+			Value:   sink.URL,
+			Message: "drift sink URL must be an http or https URL",
+		})
+	}
+
+	if sink.BatchSize <= 0 {
+		errors = append(errors, ValidationError{
+			Field:   "drift_sink.batch_size",
+			Value:   sink.BatchSize,
+			Message: "batch size must be positive",
+		})
+	}
+
+	if sink.FlushInterval <= 0 {
+		errors = append(errors, ValidationError{
+			Field:   "drift_sink.flush_interval",
+			Value:   sink.FlushInterval,
+			Message: "flush interval must be positive",
+		})
+	}
+
+	return errors
+}
+
+// validateOTel validates the OpenTelemetry drift export
+func validateOTel(otel *OTelConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if !otel.Enabled {
+		return errors
+	}
+
+	parsedURL, err := url.Parse(otel.Endpoint)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		errors = append(errors, ValidationError{
+			Field:   "otel.endpoint",
+			Value:   otel.Endpoint,
+			Message: "OTLP endpoint must be an http or https URL",
+		})
+	}
+
+	if otel.BatchSize <= 0 {
+		errors = append(errors, ValidationError{
+			Field:   "otel.batch_size",
+			Value:   otel.BatchSize,
+			Message: "batch size must be positive",
+		})
+	}
+
+	if otel.FlushInterval <= 0 {
+		errors = append(errors, ValidationError{
+			Field:   "otel.flush_interval",
+			Value:   otel.FlushInterval,
+			Message: "flush interval must be positive",
+		})
+	}
+
+	return errors
+}
+
+// validateHeartbeat validates the monitor heartbeat
+func validateHeartbeat(heartbeat *HeartbeatConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if !heartbeat.Enabled {
+		return errors
+	}
+
+	if heartbeat.Interval < time.Second {
+		errors = append(errors, ValidationError{
+			Field:   "heartbeat.interval",
+			Value:   heartbeat.Interval,
+			Message: "heartbeat interval must be at least 1 second",
+		})
+	}
+
+	if heartbeat.URL != "" {
+		parsedURL, err := url.Parse(heartbeat.URL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			errors = append(errors, ValidationError{
+				Field:   "heartbeat.url",
+				Value:   heartbeat.URL,
+				Message: "heartbeat URL must be an http or https URL",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateAPI validates the credentials of the read-only API
+func validateAPI(api *APIConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	if api.Username != "" && api.Password == "" {
+		errors = append(errors, ValidationError{
+			Field:   "api.password",
+			Message: "password is required when username is set",
+		})
+	}
+
+	if api.Password != "" && api.Username == "" {
+		errors = append(errors, ValidationError{
+			Field:   "api.username",
+			Message: "username is required when password is set",
+		})
+	}
+
+	return errors
+}
+
 // validateChannelSettings validates channel-specific settings
 func validateChannelSettings(channelType string, settings map[string]interface{}, fieldPrefix string) error {
 	var errors ValidationErrors
@@ -835,9 +1634,43 @@ func validateReporting(reporting *ReportingConfig) error {
 		})
 	}
 
+	errors = append(errors, validateSigning(&reporting.Signing)...)
+
 	if len(errors) > 0 {
 		return errors
 	}
 
 	return nil
 }
+
+// validateSigning validates export signing configuration
+func validateSigning(signing *SigningConfig) ValidationErrors {
+	var errors ValidationErrors
+
+	switch signing.Algorithm {
+	case "":
+		return errors
+	case "hmac-sha256":
+		if signing.Key == "" && signing.KeyFile == "" {
+			errors = append(errors, ValidationError{
+				Field:   "reporting.signing.key",
+				Message: "HMAC signing requires key or key_file",
+			})
+		}
+	case "ed25519":
+		if signing.KeyFile == "" && signing.PublicKeyFile == "" {
+			errors = append(errors, ValidationError{
+				Field:   "reporting.signing.key_file",
+				Message: "ed25519 signing requires key_file or public_key_file",
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "reporting.signing.algorithm",
+			Value:   signing.Algorithm,
+			Message: "invalid signing algorithm (supported: hmac-sha256, ed25519)",
+		})
+	}
+
+	return errors
+}
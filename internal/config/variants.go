@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+)
+
+// EndpointVariant requests an endpoint with its own query parameters or headers.
+// Each variant is checked as a separate endpoint with its own history and
+// drift, identified by the endpoint ID suffixed with the variant name.
+type EndpointVariant struct {
+	Name    string            `yaml:"name" mapstructure:"name"`
+	Query   map[string]string `yaml:"query,omitempty" mapstructure:"query"`     // Set on top of the URL's query parameters
+	Headers map[string]string `yaml:"headers,omitempty" mapstructure:"headers"` // Set on top of the endpoint's headers
+}
+
+// VariantID returns the ID a variant of an endpoint is checked and stored under
+func VariantID(endpointID, variantName string) string {
+	return endpointID + "-" + variantName
+}
+
+// Expand returns the endpoints an endpoint is checked as: the endpoint itself,
+// or one endpoint per variant if it has any, in which case the endpoint without
+// a variant is not checked
+func (e EndpointConfig) Expand() ([]EndpointConfig, error) {
+	if len(e.Variants) == 0 {
+		return []EndpointConfig{e}, nil
+	}
+
+	expanded := make([]EndpointConfig, 0, len(e.Variants))
+	for _, variant := range e.Variants {
+		endpoint, err := e.withVariant(variant)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, endpoint)
+	}
+	return expanded, nil
+}
+
+// withVariant returns a copy of an endpoint with a variant applied
+func (e EndpointConfig) withVariant(variant EndpointVariant) (EndpointConfig, error) {
+	endpoint := e
+	endpoint.ID = VariantID(e.ID, variant.Name)
+	endpoint.Variants = nil
+
+	if len(variant.Query) > 0 {
+		parsed, err := url.Parse(e.URL)
+		if err != nil {
+			return EndpointConfig{}, fmt.Errorf("invalid URL for endpoint %s: %w", e.ID, err)
+		}
+		query := parsed.Query()
+		for key, value := range variant.Query {
+			query.Set(key, value)
+		}
+		parsed.RawQuery = query.Encode()
+		endpoint.URL = parsed.String()
+	}
+
+	if len(variant.Headers) > 0 {
+		endpoint.Headers = maps.Clone(e.Headers)
+		if endpoint.Headers == nil {
+			endpoint.Headers = make(map[string]string, len(variant.Headers))
+		}
+		maps.Copy(endpoint.Headers, variant.Headers)
+	}
+
+	return endpoint, nil
+}
+
+// ExpandedEndpoints returns the endpoints that are checked, with every endpoint
+// that has variants replaced by its variants. Endpoints whose variants cannot be
+// applied are left out; configuration validation reports them.
+func (c *Config) ExpandedEndpoints() []EndpointConfig {
+	expanded := make([]EndpointConfig, 0, len(c.Endpoints))
+	for _, endpoint := range c.Endpoints {
+		variants, err := endpoint.Expand()
+		if err != nil {
+			continue
+		}
+		expanded = append(expanded, variants...)
+	}
+	return expanded
+}
+
+// BaseEndpointID returns the ID of the configured endpoint a variant ID belongs
+// to, or id itself when it is not the ID of a variant
+func (c *Config) BaseEndpointID(id string) string {
+	for _, endpoint := range c.Endpoints {
+		for _, variant := range endpoint.Variants {
+			if VariantID(endpoint.ID, variant.Name) == id {
+				return endpoint.ID
+			}
+		}
+	}
+	return id
+}
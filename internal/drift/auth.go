@@ -0,0 +1,60 @@
+package drift
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ChangeTypeAuthFailure is reported when an endpoint requested with credentials
+// starts rejecting them with 401 or 403 after previously succeeding, typically
+// because the configured credentials expired or were rotated
+const ChangeTypeAuthFailure ChangeType = "auth_failure"
+
+// authFailureMitigation tells on-call what to do about an auth failure
+const authFailureMitigation = "Check whether the endpoint's credentials expired or were rotated, and update them in the configuration"
+
+// CompareAuthStatus reports an auth_failure when an endpoint requested with
+// credentials returns 401 or 403 where it previously succeeded. Any other pair
+// of status codes reports nothing.
+func CompareAuthStatus(previousStatus, currentStatus int) *DiffResult {
+	result := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+
+	engine := &DefaultDiffEngine{options: DiffOptions{AuthConfigured: true}}
+	if engine.isAuthFailure(previousStatus, currentStatus) {
+		engine.recordAuthFailure(previousStatus, currentStatus, result)
+	}
+	engine.generateSummary(result)
+	result.HasChanges = result.Summary.TotalChanges > 0
+
+	return result
+}
+
+// isAuthFailure reports whether a status code change is credentials being
+// rejected after a successful response, for an endpoint with credentials
+func (d *DefaultDiffEngine) isAuthFailure(previousStatus, currentStatus int) bool {
+	if !d.options.AuthConfigured {
+		return false
+	}
+	succeeded := previousStatus >= 200 && previousStatus < 400
+	rejected := currentStatus == http.StatusUnauthorized || currentStatus == http.StatusForbidden
+	return succeeded && rejected
+}
+
+// recordAuthFailure reports rejected credentials in place of a status change
+func (d *DefaultDiffEngine) recordAuthFailure(previousStatus, currentStatus int, result *DiffResult) {
+	d.recordStructuralChange(result, StructuralChange{
+		Type: ChangeTypeAuthFailure,
+		Path: "$.status_code",
+		Description: fmt.Sprintf("Credentials rejected with status %d after previous status %d: they may have expired or been rotated",
+			currentStatus, previousStatus),
+		OldValue: previousStatus,
+		NewValue: currentStatus,
+		Severity: SeverityCritical,
+		Breaking: true,
+	}, authFailureMitigation)
+}
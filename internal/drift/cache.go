@@ -0,0 +1,119 @@
+package drift
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+)
+
+// DefaultComparisonCacheSize is the number of body comparisons kept when no size is given
+const DefaultComparisonCacheSize = 256
+
+// comparisonKey identifies a body comparison by the engine options and both bodies
+type comparisonKey [sha256.Size]byte
+
+// ComparisonCache keeps the results of recent response body comparisons, so
+// comparing identical bodies again returns the stored changes instead of
+// decoding and diffing them. It is safe for concurrent use and may be shared by
+// engines with different options; the least recently used entry is evicted once
+// the cache is full.
+type ComparisonCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[comparisonKey]*list.Element
+	order      *list.List
+	metrics    CacheMetrics
+}
+
+// CacheMetrics holds comparison cache counters
+type CacheMetrics struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+}
+
+// comparisonEntry is a cached body comparison
+type comparisonEntry struct {
+	key    comparisonKey
+	result *DiffResult
+}
+
+// NewComparisonCache creates a cache holding at most maxEntries comparisons.
+// Zero or a negative size uses DefaultComparisonCacheSize.
+func NewComparisonCache(maxEntries int) *ComparisonCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultComparisonCacheSize
+	}
+
+	return &ComparisonCache{
+		maxEntries: maxEntries,
+		entries:    make(map[comparisonKey]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// GetMetrics returns a snapshot of the cache counters
+func (c *ComparisonCache) GetMetrics() CacheMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metrics := c.metrics
+	metrics.Entries = c.order.Len()
+	return metrics
+}
+
+// get returns the cached comparison for key and records a hit or miss
+func (c *ComparisonCache) get(key comparisonKey) (*DiffResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.metrics.Misses++
+		return nil, false
+	}
+
+	c.metrics.Hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*comparisonEntry).result, true
+}
+
+// put stores a comparison, evicting the least recently used entries when full
+func (c *ComparisonCache) put(key comparisonKey, result *DiffResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*comparisonEntry).result = result
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&comparisonEntry{key: key, result: result})
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*comparisonEntry).key)
+		c.metrics.Evictions++
+	}
+}
+
+// newComparisonKey hashes the options fingerprint, the media types both bodies
+// were served with and both bodies. Lengths are included so that different
+// splits of the same bytes produce different keys.
+func newComparisonKey(optionsKey []byte, previousType, currentType string, previous, current []byte) comparisonKey {
+	hash := sha256.New()
+	var length [8]byte
+	for _, part := range [][]byte{optionsKey, []byte(previousType), []byte(currentType), previous, current} {
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		hash.Write(length[:])
+		hash.Write(part)
+	}
+
+	var key comparisonKey
+	copy(key[:], hash.Sum(nil))
+	return key
+}
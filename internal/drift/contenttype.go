@@ -0,0 +1,125 @@
+package drift
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+const (
+	// ChangeTypeContentTypeChange is reported when the media type of the
+	// Content-Type header changes, such as from JSON to HTML
+	ChangeTypeContentTypeChange ChangeType = "content_type_change"
+
+	// ChangeTypeUnexpectedContentType is reported when the response body cannot
+	// be parsed as JSON, typically an error page served in place of the API
+	ChangeTypeUnexpectedContentType ChangeType = "unexpected_content_type"
+)
+
+const contentTypeHeader = "Content-Type"
+
+// compareContentTypes reports a change of the media type responses are served
+// with. Only the media type is compared: a change of its parameters, such as
+// the charset, is an ordinary header change. Responses without a Content-Type
+// are not compared.
+func (d *DefaultDiffEngine) compareContentTypes(previous, current *Response, result *DiffResult) {
+	previousType := mediaType(headerValue(previous.Headers, contentTypeHeader))
+	currentType := mediaType(headerValue(current.Headers, contentTypeHeader))
+	if previousType == "" || currentType == "" || previousType == currentType {
+		return
+	}
+
+	d.recordStructuralChange(result, StructuralChange{
+		Type:        ChangeTypeContentTypeChange,
+		Path:        "$.headers." + contentTypeHeader,
+		Description: fmt.Sprintf("Content type changed from %s to %s", previousType, currentType),
+		OldValue:    previousType,
+		NewValue:    currentType,
+		Severity:    SeverityHigh,
+		Breaking:    true,
+	}, "Check whether the endpoint is serving an error page or has changed its response format")
+}
+
+// recordUnexpectedContentType reports a current response body that is not
+// valid JSON, in place of comparing it
+func (d *DefaultDiffEngine) recordUnexpectedContentType(previous, current *Response, parseErr error, result *DiffResult) {
+	change := StructuralChange{
+		Type:        ChangeTypeUnexpectedContentType,
+		Path:        "$",
+		Description: fmt.Sprintf("Response body is not valid JSON (%v)", parseErr),
+		Severity:    SeverityHigh,
+		Breaking:    true,
+	}
+	if previousType := mediaType(headerValue(previous.Headers, contentTypeHeader)); previousType != "" {
+		change.OldValue = previousType
+	}
+	if currentType := mediaType(headerValue(current.Headers, contentTypeHeader)); currentType != "" {
+		change.NewValue = currentType
+		change.Description = fmt.Sprintf("Response body is %s rather than valid JSON (%v)", currentType, parseErr)
+	}
+
+	d.recordStructuralChange(result, change, "Check whether the endpoint is serving an error page or has changed its response format")
+}
+
+// servesJSON reports whether a response is JSON: served with a JSON media type
+// or with a body that parses as JSON
+func servesJSON(response *Response) bool {
+	if isJSONMediaType(mediaType(headerValue(response.Headers, contentTypeHeader))) {
+		return true
+	}
+
+	var data interface{}
+	return len(response.Body) > 0 && decodeJSON(response.Body, &data) == nil
+}
+
+// isJSONMediaType reports whether a media type is application/json or a
+// structured syntax JSON type such as application/problem+json
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// compareBodyText compares bodies that are not JSON as a whole, reporting a
+// modification of the body when they differ
+func (d *DefaultDiffEngine) compareBodyText(previous, current *Response, result *DiffResult) {
+	if bytes.Equal(previous.Body, current.Body) {
+		return
+	}
+
+	d.recordFieldDiffs([]FieldDiff{{
+		Path:     "$",
+		Type:     DiffTypeModified,
+		OldValue: string(previous.Body),
+		NewValue: string(current.Body),
+	}}, result)
+}
+
+// mediaType returns the lowercased media type of a Content-Type value without
+// its parameters, or "" if there is none
+func mediaType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Fall back on the part before the parameters of malformed values
+		parsed, _, _ = strings.Cut(contentType, ";")
+		parsed = strings.ToLower(strings.TrimSpace(parsed))
+	}
+	return parsed
+}
+
+// headerValue returns the value of a recorded header, matching its name
+// case-insensitively
+func headerValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
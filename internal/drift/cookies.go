@@ -0,0 +1,335 @@
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ChangeTypeCookieChange is reported for cookies set by the Set-Cookie header
+const ChangeTypeCookieChange ChangeType = "cookie_change"
+
+// setCookieHeader is the header whose values are compared cookie by cookie
+const setCookieHeader = "Set-Cookie"
+
+// sessionCookieNames lists cookie names that carry a new value on every login or
+// request. Names containing "session" are treated as session cookies as well.
+var sessionCookieNames = map[string]bool{
+	"sid":         true,
+	"jsessionid":  true,
+	"phpsessid":   true,
+	"connect.sid": true,
+	"csrftoken":   true,
+	"_csrf":       true,
+	"xsrf-token":  true,
+}
+
+// HeaderValue returns the value recorded for a response header with the given
+// values. Set-Cookie values are joined with newlines so that every cookie can be
+// compared; other headers keep their first value.
+//
+// Runs recorded before every cookie was kept store only the first Set-Cookie
+// value. compareCookies does not report the remaining cookies as newly set when
+// comparing against such a baseline.
+func HeaderValue(name string, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if strings.EqualFold(name, setCookieHeader) {
+		return strings.Join(values, "\n")
+	}
+	return values[0]
+}
+
+// parseSetCookies parses a recorded Set-Cookie header into cookies by name.
+// Lines that cannot be parsed are skipped.
+func parseSetCookies(value string) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, line := range setCookieLines(value) {
+		cookie, err := http.ParseSetCookie(line)
+		if err != nil {
+			continue
+		}
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+// isVolatileCookie reports whether a cookie's value is expected to change
+// between responses
+func (d *DefaultDiffEngine) isVolatileCookie(name string) bool {
+	lowerName := strings.ToLower(name)
+	if sessionCookieNames[lowerName] || strings.Contains(lowerName, "session") {
+		return true
+	}
+
+	for _, volatile := range d.options.VolatileCookies {
+		if strings.EqualFold(volatile, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compareCookies compares the cookies set by two Set-Cookie headers by name.
+// Value changes of volatile cookies and changes to Expires and Max-Age, which
+// move with every response, are not reported. Removing or changing attributes
+// that scope or protect a cookie is reported as potentially breaking.
+func (d *DefaultDiffEngine) compareCookies(previousHeader, currentHeader string, result *DiffResult) {
+	previous := parseSetCookies(previousHeader)
+	current := parseSetCookies(currentHeader)
+	legacyBaseline := isLegacyCookieBaseline(previousHeader, currentHeader)
+
+	for _, name := range sortedCookieNames(previous) {
+		oldCookie := previous[name]
+		path := fmt.Sprintf("$.headers.%s.%s", setCookieHeader, name)
+
+		newCookie, exists := current[name]
+		if !exists {
+			d.recordStructuralChange(result, StructuralChange{
+				Type:        ChangeTypeCookieChange,
+				Path:        path,
+				Description: fmt.Sprintf("Cookie '%s' is no longer set", name),
+				OldValue:    redactCookie(oldCookie),
+				Severity:    SeverityMedium,
+			}, "")
+			continue
+		}
+
+		if oldCookie.Value != newCookie.Value && !d.isVolatileCookie(name) {
+			result.HasChanges = true
+			result.DataChanges = append(result.DataChanges, DataChange{
+				Path:        path,
+				OldValue:    cookieValueDigest(oldCookie.Value),
+				NewValue:    cookieValueDigest(newCookie.Value),
+				ChangeType:  ChangeTypeCookieChange,
+				Severity:    SeverityLow,
+				Description: fmt.Sprintf("Cookie '%s' value changed", name),
+			})
+		}
+
+		d.compareCookieAttributes(name, path, oldCookie, newCookie, result)
+	}
+
+	for _, name := range sortedCookieNames(current) {
+		if _, exists := previous[name]; exists || legacyBaseline {
+			continue
+		}
+
+		d.recordStructuralChange(result, StructuralChange{
+			Type:        ChangeTypeCookieChange,
+			Path:        fmt.Sprintf("$.headers.%s.%s", setCookieHeader, name),
+			Description: fmt.Sprintf("Cookie '%s' is now set", name),
+			NewValue:    redactCookie(current[name]),
+			Severity:    SeverityLow,
+		}, "")
+	}
+}
+
+// isLegacyCookieBaseline reports whether a recorded Set-Cookie header looks like
+// it was stored when only the first value was kept: it holds a single cookie,
+// which is also the first of several cookies in the current response.
+func isLegacyCookieBaseline(previousHeader, currentHeader string) bool {
+	previousLines := setCookieLines(previousHeader)
+	currentLines := setCookieLines(currentHeader)
+	if len(previousLines) != 1 || len(currentLines) < 2 {
+		return false
+	}
+
+	previousCookie, err := http.ParseSetCookie(previousLines[0])
+	if err != nil {
+		return false
+	}
+	currentCookie, err := http.ParseSetCookie(currentLines[0])
+	if err != nil {
+		return false
+	}
+	return previousCookie.Name == currentCookie.Name
+}
+
+// setCookieLines returns the non-empty lines of a recorded Set-Cookie header
+func setCookieLines(value string) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// cookieValueDigest returns a short digest of a cookie value. Change records
+// are stored and sent to alert channels, so they never carry raw cookie values.
+func cookieValueDigest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// credentialHeaders lists the headers whose values are credentials, by lowercase name
+var credentialHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+}
+
+// RedactHeaderValue returns a recorded header value that is safe to show: the
+// cookies of a Set-Cookie header with their values replaced by digests, as in
+// change records, and credentials replaced by a digest after their scheme.
+// Other headers are returned as they are.
+func RedactHeaderValue(name, value string) string {
+	lowerName := strings.ToLower(name)
+	switch {
+	case lowerName == strings.ToLower(setCookieHeader):
+		lines := setCookieLines(value)
+		for i, line := range lines {
+			if cookie, err := http.ParseSetCookie(line); err == nil {
+				lines[i] = redactCookie(cookie)
+			} else {
+				lines[i] = cookieValueDigest(line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	case credentialHeaders[lowerName]:
+		if scheme, credentials, ok := strings.Cut(value, " "); ok && lowerName != "cookie" {
+			return scheme + " " + cookieValueDigest(credentials)
+		}
+		return cookieValueDigest(value)
+	default:
+		return value
+	}
+}
+
+// redactCookie returns the cookie in Set-Cookie form with its value replaced by
+// a digest
+func redactCookie(cookie *http.Cookie) string {
+	redacted := *cookie
+	redacted.Value = cookieValueDigest(cookie.Value)
+	return redacted.String()
+}
+
+// compareCookieAttributes reports changes to the attributes of a cookie
+func (d *DefaultDiffEngine) compareCookieAttributes(name, path string, oldCookie, newCookie *http.Cookie, result *DiffResult) {
+	flags := []struct {
+		attribute string
+		old, new  bool
+	}{
+		{"Secure", oldCookie.Secure, newCookie.Secure},
+		{"HttpOnly", oldCookie.HttpOnly, newCookie.HttpOnly},
+	}
+
+	for _, flag := range flags {
+		if flag.old == flag.new {
+			continue
+		}
+
+		attributePath := fmt.Sprintf("%s.%s", path, strings.ToLower(flag.attribute))
+		if flag.old {
+			d.recordStructuralChange(result, StructuralChange{
+				Type:        ChangeTypeCookieChange,
+				Path:        attributePath,
+				Description: fmt.Sprintf("Cookie '%s' lost the %s attribute", name, flag.attribute),
+				OldValue:    true,
+				NewValue:    false,
+				Severity:    SeverityHigh,
+				Breaking:    true,
+			}, fmt.Sprintf("Restore the %s attribute on cookie '%s'", flag.attribute, name))
+			continue
+		}
+
+		d.recordStructuralChange(result, StructuralChange{
+			Type:        ChangeTypeCookieChange,
+			Path:        attributePath,
+			Description: fmt.Sprintf("Cookie '%s' gained the %s attribute", name, flag.attribute),
+			OldValue:    false,
+			NewValue:    true,
+			Severity:    SeverityLow,
+		}, "")
+	}
+
+	scopes := []struct {
+		attribute string
+		old, new  string
+	}{
+		{"Domain", oldCookie.Domain, newCookie.Domain},
+		{"Path", oldCookie.Path, newCookie.Path},
+		{"SameSite", sameSiteName(oldCookie.SameSite), sameSiteName(newCookie.SameSite)},
+	}
+
+	for _, scope := range scopes {
+		if scope.old == scope.new {
+			continue
+		}
+
+		change := StructuralChange{
+			Type:     ChangeTypeCookieChange,
+			Path:     fmt.Sprintf("%s.%s", path, strings.ToLower(scope.attribute)),
+			OldValue: scope.old,
+			NewValue: scope.new,
+		}
+
+		switch {
+		case scope.old == "":
+			change.Description = fmt.Sprintf("Cookie '%s' %s set to '%s'", name, scope.attribute, scope.new)
+			change.Severity = SeverityMedium
+		case scope.new == "":
+			change.Description = fmt.Sprintf("Cookie '%s' %s '%s' was removed", name, scope.attribute, scope.old)
+			change.Severity = SeverityHigh
+			change.Breaking = true
+		default:
+			change.Description = fmt.Sprintf("Cookie '%s' %s changed from '%s' to '%s'", name, scope.attribute, scope.old, scope.new)
+			change.Severity = SeverityMedium
+			// A different domain or path changes which requests carry the cookie
+			if scope.attribute != "SameSite" {
+				change.Severity = SeverityHigh
+				change.Breaking = true
+			}
+		}
+
+		d.recordStructuralChange(result, change, fmt.Sprintf("Check clients that rely on the %s of cookie '%s'", scope.attribute, name))
+	}
+}
+
+// recordStructuralChange adds a change to the result, along with a breaking
+// change entry if the change is breaking
+func (d *DefaultDiffEngine) recordStructuralChange(result *DiffResult, change StructuralChange, mitigation string) {
+	result.HasChanges = true
+	result.StructuralChanges = append(result.StructuralChanges, change)
+
+	if change.Breaking {
+		result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+			Type:        change.Type,
+			Path:        change.Path,
+			Description: change.Description,
+			Impact:      d.mapSeverityToImpact(change.Severity),
+			Mitigation:  mitigation,
+		})
+	}
+}
+
+// sameSiteName returns the SameSite attribute value, or "" if it is not set
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+// sortedCookieNames returns cookie names in a stable order for reporting
+func sortedCookieNames(cookies map[string]*http.Cookie) []string {
+	names := make([]string, 0, len(cookies))
+	for name := range cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
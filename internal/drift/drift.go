@@ -2,12 +2,19 @@
 package drift
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/go-openapi/spec"
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/jsonpath"
 	"github.com/k0ns0l/driftwatch/internal/validator"
 )
 
@@ -26,6 +33,18 @@ type Response struct {
 	Timestamp    time.Time         `json:"timestamp"`
 	ResponseTime time.Duration     `json:"response_time"`
 	StatusCode   int               `json:"status_code"`
+
+	// Protocol is the negotiated HTTP version, such as "HTTP/2.0". Protocols and
+	// trailers are only compared when both responses recorded a protocol.
+	Protocol string            `json:"protocol,omitempty"`
+	Trailers map[string]string `json:"trailers,omitempty"`
+
+	// Timing breaks the response time down into request phases, when recorded
+	Timing *Timing `json:"timing,omitempty"`
+
+	// Truncated marks a body cut short by a stream read limit, which holds only
+	// a prefix of the response and is compared record by record
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // DiffResult represents the result of comparing two responses
@@ -47,6 +66,9 @@ type StructuralChange struct {
 	Type        ChangeType  `json:"type"`
 	Severity    Severity    `json:"severity"`
 	Breaking    bool        `json:"breaking"`
+
+	// Explanation is set when DiffOptions.Explain is enabled
+	Explanation *ChangeExplanation `json:"explanation,omitempty"`
 }
 
 // DataChange represents a change in data values
@@ -57,6 +79,9 @@ type DataChange struct {
 	ChangeType  ChangeType  `json:"change_type"`
 	Severity    Severity    `json:"severity"`
 	Description string      `json:"description"`
+
+	// Explanation is set when DiffOptions.Explain is enabled
+	Explanation *ChangeExplanation `json:"explanation,omitempty"`
 }
 
 // PerformanceChange represents a change in performance characteristics
@@ -64,6 +89,11 @@ type PerformanceChange struct {
 	Description       string        `json:"description"`
 	ResponseTimeDelta time.Duration `json:"response_time_delta"`
 	Severity          Severity      `json:"severity"`
+
+	// Phase is the request phase that slowed down the most, such as "ttfb",
+	// when both responses recorded a timing breakdown
+	Phase      string        `json:"phase,omitempty"`
+	PhaseDelta time.Duration `json:"phase_delta,omitempty"`
 }
 
 // BreakingChange represents a potentially breaking API change
@@ -93,6 +123,10 @@ type TrendAnalysis struct {
 	ChangeFrequency  float64           `json:"change_frequency"`
 	StabilityScore   float64           `json:"stability_score"`
 	TotalResponses   int               `json:"total_responses"`
+
+	// SampledResponses is the number of responses analyzed when the history
+	// was downsampled to DiffOptions.MaxTrendSamples; zero when it was not
+	SampledResponses int `json:"sampled_responses,omitempty"`
 }
 
 // CommonChange represents frequently occurring changes
@@ -127,6 +161,26 @@ type ChangeClassification struct {
 	Impact     ImpactLevel    `json:"impact"`
 	Confidence float64        `json:"confidence"`
 	Breaking   bool           `json:"breaking"`
+
+	// Rules lists the heuristics that shaped the classification, such as
+	// "field_removal", "type_change", "critical_field:id" or "required_field"
+	Rules []string `json:"rules,omitempty"`
+}
+
+// ChangeExplanation tells why a change was classified the way it was
+type ChangeExplanation struct {
+	Reasoning  string   `json:"reasoning" yaml:"reasoning"`
+	Rules      []string `json:"rules" yaml:"rules"`
+	Confidence float64  `json:"confidence" yaml:"confidence"`
+}
+
+// Explain returns the explanation of a classification
+func (c *ChangeClassification) Explain() *ChangeExplanation {
+	return &ChangeExplanation{
+		Reasoning:  c.Reasoning,
+		Rules:      append([]string{}, c.Rules...),
+		Confidence: c.Confidence,
+	}
 }
 
 // ChangeContext provides context for change assessment
@@ -198,9 +252,187 @@ const (
 	TrendDirectionDegrading TrendDirection = "degrading"
 )
 
+// DiffOptions controls how responses are compared
+type DiffOptions struct {
+	// CompareRoot is a JSONPath selecting the subtree of the body to compare.
+	// Everything outside the root is ignored.
+	CompareRoot string `json:"compare_root,omitempty"`
+
+	// RequiredFields lists paths that clients depend on, such as "$.data.id" or
+	// "items[*].name". Changes touching these paths are assessed with higher severity.
+	RequiredFields []string `json:"required_fields,omitempty"`
+
+	// ResponseSchema is the resolved schema of the endpoint's success response,
+	// when its OpenAPI spec is known. Fields the schema lists as required are
+	// treated like RequiredFields, and their removal, which breaks the
+	// contract, is assessed as critical.
+	ResponseSchema *spec.Schema `json:"response_schema,omitempty"`
+
+	// AssertedFields lists paths that must be present and non-null in every
+	// current response. Missing ones are reported as required_field_missing
+	// changes whether or not the previous response had them.
+	AssertedFields []string `json:"asserted_fields,omitempty"`
+
+	// EnumValues maps paths, such as "$.status" or "items[*].state", to the values
+	// the schema allows for them. A value modified to one outside its set is
+	// assessed with at least high severity.
+	EnumValues map[string][]interface{} `json:"enum_values,omitempty"`
+
+	// IgnoreFields lists paths whose changes are not reported, such as fields known
+	// to vary between requests. Changes below an ignored path are dropped as well.
+	IgnoreFields []string `json:"ignore_fields,omitempty"`
+
+	// StreamingThreshold is the body size in bytes from which top-level arrays are
+	// compared element by element while decoding, instead of decoding both bodies
+	// up front. Zero uses DefaultStreamingThreshold; a negative value disables streaming.
+	StreamingThreshold int `json:"streaming_threshold,omitempty"`
+
+	// EmbeddedJSONFields lists paths, such as "payload" or "events[*].data", of
+	// string fields holding encoded JSON. Their values are decoded before
+	// comparison, so formatting differences inside them are not reported and
+	// changes are reported at paths within the decoded value.
+	EmbeddedJSONFields []string `json:"embedded_json_fields,omitempty"`
+
+	// UnorderedArrays lists paths, such as "tags" or "items[*].roles", of arrays
+	// whose order is not meaningful. They are compared as multisets: elements
+	// are matched to equal elements wherever they are, so reordering is not
+	// reported and only elements without an equal counterpart are reported as
+	// added or removed. Arrays elsewhere are compared position by position.
+	// Ignored in shape-only mode.
+	UnorderedArrays []string `json:"unordered_arrays,omitempty"`
+
+	// ArraySummaryThreshold is the change in the number of elements of an array
+	// above which the elements added to or removed from its end are summarized
+	// in the array's length change, such as "3 elements added", instead of
+	// being reported one by one. Elements present in both arrays are still
+	// compared individually. Zero reports every element; unordered arrays are
+	// never summarized.
+	ArraySummaryThreshold int `json:"array_summary_threshold,omitempty"`
+
+	// VolatileCookies lists cookie names whose value changes are not reported, in
+	// addition to recognized session cookies. Attribute changes are still reported.
+	VolatileCookies []string `json:"volatile_cookies,omitempty"`
+
+	// NullAsMissing treats an explicit JSON null like an absent field or array
+	// element, so {"x": null} and {} are identical. Otherwise null is a value of
+	// its own and changes between null and another value are modifications.
+	NullAsMissing bool `json:"null_as_missing,omitempty"`
+
+	// ShapeOnly compares bodies by shape: the fields present and their types.
+	// Scalar values, changes between null and a value, and the number of array
+	// elements are not compared, which suits endpoints returning different data
+	// on every request. Elements present in both arrays are compared for shape.
+	ShapeOnly bool `json:"shape_only,omitempty"`
+
+	// TrackedFields lists paths, such as "$.meta.total" or "$.meta.schema_version",
+	// whose values are watched. When set, bodies are compared by shape as with
+	// ShapeOnly, except at and below the tracked paths, where values and array
+	// lengths are compared as usual. It suits endpoints, such as searches, whose
+	// results vary but whose counts or versions must not.
+	TrackedFields []string `json:"tracked_fields,omitempty"`
+
+	// VersionField is the path, such as "$.schema_version" or "apiVersion", of
+	// the field holding the contract version of the response. A change of its
+	// value is reported as a single version_change of VersionChangeSeverity,
+	// low if empty, instead of a value change, and never as breaking.
+	VersionField          string   `json:"version_field,omitempty"`
+	VersionChangeSeverity Severity `json:"version_change_severity,omitempty"`
+
+	// HeaderPatterns maps header names, matched case-insensitively, to regular
+	// expressions their values are expected to match. Value changes of these
+	// headers are reported only when the new value does not match. Invalid
+	// patterns are ignored.
+	HeaderPatterns map[string]string `json:"header_patterns,omitempty"`
+
+	// IgnoreValuePatterns lists regular expressions, such as a UUID or an
+	// ISO 8601 timestamp, matching values that are expected to change. A scalar
+	// value modified from one match of a pattern to another match of the same
+	// pattern is not reported, wherever it is in the body. Patterns must match
+	// the whole value, and numbers are matched by their JSON text. Invalid
+	// patterns are ignored.
+	IgnoreValuePatterns []string `json:"ignore_value_patterns,omitempty"`
+
+	// BodyStatusCodes lists the status codes, such as 200, for which bodies are
+	// compared. When set, bodies are compared only if both responses returned
+	// one of them; otherwise only status codes, headers and performance are, so
+	// that error bodies carrying request IDs are not reported. Empty compares
+	// bodies whatever the status.
+	BodyStatusCodes []int `json:"body_status_codes,omitempty"`
+
+	// StatusMatch selects how status codes are compared: exactly
+	// (config.StatusMatchExact, the default), or by class
+	// (config.StatusMatchClass), so that a change within a class, such as from
+	// 200 to 201, is not reported
+	StatusMatch config.StatusMatch `json:"status_match,omitempty"`
+
+	// AuthConfigured tells that the responses were requested with credentials.
+	// A change from a successful status to 401 or 403 is then reported as an
+	// auth_failure rather than a status change.
+	AuthConfigured bool `json:"auth_configured,omitempty"`
+
+	// NumericTolerance is the relative difference, such as 0.01 for 1%, up to
+	// which changes of numeric values are not reported. A change is compared
+	// with the larger of the two values. Zero reports every change.
+	NumericTolerance float64 `json:"numeric_tolerance,omitempty"`
+
+	// PerformanceMode selects how response times are compared: with the previous
+	// response (PerformanceModeDelta, the default), with ResponseTimeThreshold
+	// (PerformanceModeThreshold) or statistically with ResponseTimeHistory
+	// (PerformanceModeZScore)
+	PerformanceMode string `json:"performance_mode,omitempty"`
+
+	// ResponseTimeThreshold is the slowest acceptable response in the threshold mode
+	ResponseTimeThreshold time.Duration `json:"response_time_threshold,omitempty"`
+
+	// ZScoreThreshold is the number of standard deviations above the mean from
+	// which the zscore mode reports a response time. Zero uses DefaultZScoreThreshold.
+	ZScoreThreshold float64 `json:"zscore_threshold,omitempty"`
+
+	// ResponseTimeHistory holds the recent response times of the endpoint that
+	// the zscore mode computes the mean and standard deviation from. It changes
+	// on every check, so it is left out of comparison cache keys.
+	ResponseTimeHistory []time.Duration `json:"-"`
+
+	// Explain attaches to each field change the reasoning, confidence and
+	// heuristics behind its classification
+	Explain bool `json:"explain,omitempty"`
+
+	// Cache, when set, stores body comparisons so that comparing the same pair
+	// of bodies again skips decoding and diffing them. Status code, header and
+	// performance changes are always compared.
+	Cache *ComparisonCache `json:"-"`
+
+	// MaxTrendSamples is the largest number of responses AnalyzeTrends compares.
+	// Longer histories are downsampled to evenly spaced responses, keeping the
+	// first and the last. Zero uses DefaultMaxTrendSamples; negative compares
+	// every response.
+	MaxTrendSamples int `json:"-"`
+}
+
+// DefaultMaxTrendSamples is the number of responses trend analysis is limited to
+// by default, keeping it fast on histories of tens of thousands of runs
+const DefaultMaxTrendSamples = 1000
+
+// DefaultStreamingThreshold is the body size from which large top-level arrays are diffed as a stream
+const DefaultStreamingThreshold = 4 << 20
+
+// arrayIndexPattern matches concrete array indexes in change paths
+var arrayIndexPattern = regexp.MustCompile(`\[\d+\]`)
+
 // DefaultDiffEngine implements the DiffEngine interface
 type DefaultDiffEngine struct {
-	validator validator.Validator
+	validator      validator.Validator
+	options        DiffOptions
+	requiredPaths  []string
+	schemaRequired map[string]bool // paths the response schema requires
+	ignoredPaths   []string
+	embeddedPaths  []string
+	unorderedPaths []string
+	trackedPaths   []string
+	enumValues     map[string][]interface{}
+	headerPatterns map[string]*regexp.Regexp // by lowercase header name
+	valuePatterns  []*regexp.Regexp          // anchored to match whole values
+	optionsKey     []byte                    // fingerprint of the options, used in comparison cache keys
 }
 
 // NewDiffEngine creates a new drift detection engine
@@ -210,6 +442,100 @@ func NewDiffEngine() DiffEngine {
 	}
 }
 
+// NewDiffEngineWithOptions creates a new drift detection engine with comparison options
+func NewDiffEngineWithOptions(options DiffOptions) DiffEngine {
+	requiredPaths := make([]string, 0, len(options.RequiredFields))
+	for _, field := range options.RequiredFields {
+		if field = strings.TrimSpace(field); field != "" {
+			requiredPaths = append(requiredPaths, normalizeFieldPath(field))
+		}
+	}
+
+	schemaRequired := make(map[string]bool)
+	for _, path := range validator.RequiredSchemaPaths(options.ResponseSchema) {
+		path = normalizeFieldPath(path)
+		schemaRequired[path] = true
+		requiredPaths = append(requiredPaths, path)
+	}
+
+	ignoredPaths := make([]string, 0, len(options.IgnoreFields))
+	for _, field := range options.IgnoreFields {
+		if field = strings.TrimSpace(field); field != "" {
+			ignoredPaths = append(ignoredPaths, normalizeFieldPath(field))
+		}
+	}
+
+	embeddedPaths := make([]string, 0, len(options.EmbeddedJSONFields))
+	for _, field := range options.EmbeddedJSONFields {
+		if field = strings.TrimSpace(field); field != "" {
+			embeddedPaths = append(embeddedPaths, normalizeFieldPath(field))
+		}
+	}
+
+	unorderedPaths := make([]string, 0, len(options.UnorderedArrays))
+	for _, field := range options.UnorderedArrays {
+		if field = strings.TrimSpace(field); field != "" {
+			unorderedPaths = append(unorderedPaths, normalizeFieldPath(field))
+		}
+	}
+
+	trackedPaths := make([]string, 0, len(options.TrackedFields))
+	for _, field := range options.TrackedFields {
+		if field = strings.TrimSpace(field); field != "" {
+			trackedPaths = append(trackedPaths, normalizeFieldPath(field))
+		}
+	}
+
+	enumValues := make(map[string][]interface{}, len(options.EnumValues))
+	for field, values := range options.EnumValues {
+		if field = strings.TrimSpace(field); field != "" && len(values) > 0 {
+			enumValues[normalizeFieldPath(field)] = values
+		}
+	}
+
+	headerPatterns := make(map[string]*regexp.Regexp, len(options.HeaderPatterns))
+	for header, pattern := range options.HeaderPatterns {
+		if compiled, err := regexp.Compile(pattern); err == nil {
+			headerPatterns[strings.ToLower(header)] = compiled
+		}
+	}
+
+	valuePatterns := make([]*regexp.Regexp, 0, len(options.IgnoreValuePatterns))
+	for _, pattern := range options.IgnoreValuePatterns {
+		if compiled, err := regexp.Compile(`^(?:` + pattern + `)$`); err == nil {
+			valuePatterns = append(valuePatterns, compiled)
+		}
+	}
+
+	// Options are plain data, so marshaling cannot fail
+	optionsKey, _ := json.Marshal(options)
+
+	return &DefaultDiffEngine{
+		validator:      validator.NewValidator(),
+		options:        options,
+		requiredPaths:  requiredPaths,
+		schemaRequired: schemaRequired,
+		ignoredPaths:   ignoredPaths,
+		embeddedPaths:  embeddedPaths,
+		unorderedPaths: unorderedPaths,
+		trackedPaths:   trackedPaths,
+		enumValues:     enumValues,
+		headerPatterns: headerPatterns,
+		valuePatterns:  valuePatterns,
+		optionsKey:     optionsKey,
+	}
+}
+
+// normalizeFieldPath converts a field path into the form used for required
+// field matching: rooted at "$" with array indexes replaced by [*]
+func normalizeFieldPath(path string) string {
+	if path != "$" && !strings.HasPrefix(path, "$.") && !strings.HasPrefix(path, "$[") {
+		path = "$." + strings.TrimPrefix(path, ".")
+	}
+	path = strings.ReplaceAll(path, "[]", "[*]")
+	return arrayIndexPattern.ReplaceAllString(path, "[*]")
+}
+
 // CompareResponses compares two responses and detects drift
 func (d *DefaultDiffEngine) CompareResponses(previous, current *Response) (*DiffResult, error) {
 	if previous == nil || current == nil {
@@ -227,12 +553,34 @@ func (d *DefaultDiffEngine) CompareResponses(previous, current *Response) (*Diff
 	// Compare status codes
 	d.compareStatusCodes(previous, current, result)
 
-	// Compare headers
+	// Compare headers and the media type they declare
 	d.compareHeaders(previous, current, result)
+	d.compareContentTypes(previous, current, result)
+
+	// Compare the negotiated HTTP version and trailers
+	d.compareProtocols(previous, current, result)
+	d.compareTrailers(previous, current, result)
 
-	// Compare response bodies
-	if err := d.compareResponseBodies(previous, current, result); err != nil {
-		return nil, fmt.Errorf("failed to compare response bodies: %w", err)
+	// Compare response bodies, unless their status codes are not compared by body
+	comparesBodies := d.comparesBodies(previous.StatusCode, current.StatusCode)
+	if comparesBodies {
+		if err := d.compareCachedResponseBodies(previous, current, result); err != nil {
+			return nil, fmt.Errorf("failed to compare response bodies: %w", err)
+		}
+	}
+
+	// Drop changes to ignored fields
+	d.removeIgnoredChanges(result)
+
+	if comparesBodies {
+		// Report a changed contract version as a version change
+		d.compareVersionFields(previous.Body, current.Body, result)
+
+		// Check fields that must be present in every response; a prefix of the
+		// response may lack them only because they were not read
+		if !current.Truncated {
+			d.checkRequiredFields(current.Body, d.options.AssertedFields, result)
+		}
 	}
 
 	// Compare performance
@@ -244,9 +592,24 @@ func (d *DefaultDiffEngine) CompareResponses(previous, current *Response) (*Diff
 	return result, nil
 }
 
+// comparesBodies reports whether bodies returned with the given status codes
+// are compared, as configured by BodyStatusCodes
+func (d *DefaultDiffEngine) comparesBodies(previousStatus, currentStatus int) bool {
+	if len(d.options.BodyStatusCodes) == 0 {
+		return true
+	}
+	return slices.Contains(d.options.BodyStatusCodes, previousStatus) &&
+		slices.Contains(d.options.BodyStatusCodes, currentStatus)
+}
+
 // compareStatusCodes compares HTTP status codes
 func (d *DefaultDiffEngine) compareStatusCodes(previous, current *Response, result *DiffResult) {
-	if previous.StatusCode != current.StatusCode {
+	if d.isAuthFailure(previous.StatusCode, current.StatusCode) {
+		d.recordAuthFailure(previous.StatusCode, current.StatusCode, result)
+		return
+	}
+
+	if !d.statusCodesMatch(previous.StatusCode, current.StatusCode) {
 		result.HasChanges = true
 
 		change := StructuralChange{
@@ -277,8 +640,15 @@ func (d *DefaultDiffEngine) compareStatusCodes(previous, current *Response, resu
 
 // compareHeaders compares HTTP headers
 func (d *DefaultDiffEngine) compareHeaders(previous, current *Response, result *DiffResult) {
+	// Cookies are compared individually rather than as one header value
+	d.compareCookies(setCookieValue(previous.Headers), setCookieValue(current.Headers), result)
+
 	// Check for removed headers
 	for key, oldValue := range previous.Headers {
+		if strings.EqualFold(key, setCookieHeader) {
+			continue
+		}
+
 		if newValue, exists := current.Headers[key]; !exists {
 			result.HasChanges = true
 
@@ -303,7 +673,24 @@ func (d *DefaultDiffEngine) compareHeaders(previous, current *Response, result *
 				})
 			}
 		} else if oldValue != newValue {
+			// A changed media type is reported by compareContentTypes
+			if strings.EqualFold(key, contentTypeHeader) && mediaType(oldValue) != mediaType(newValue) {
+				continue
+			}
+
 			// Header value changed
+			description := fmt.Sprintf("Header '%s' value changed from '%s' to '%s'", key, oldValue, newValue)
+			severity := d.assessHeaderValueSeverity(key, oldValue, newValue)
+			if pattern, exists := d.headerPatterns[strings.ToLower(key)]; exists {
+				if pattern.MatchString(newValue) {
+					continue
+				}
+				description = fmt.Sprintf("Header '%s' value '%s' no longer matches the expected pattern '%s'", key, newValue, pattern)
+				if severityRank(severity) < severityRank(SeverityMedium) {
+					severity = SeverityMedium
+				}
+			}
+
 			result.HasChanges = true
 
 			change := DataChange{
@@ -311,8 +698,8 @@ func (d *DefaultDiffEngine) compareHeaders(previous, current *Response, result *
 				OldValue:    oldValue,
 				NewValue:    newValue,
 				ChangeType:  ChangeTypeHeaderChange,
-				Severity:    d.assessHeaderValueSeverity(key, oldValue, newValue),
-				Description: fmt.Sprintf("Header '%s' value changed from '%s' to '%s'", key, oldValue, newValue),
+				Severity:    severity,
+				Description: description,
 			}
 
 			result.DataChanges = append(result.DataChanges, change)
@@ -321,6 +708,10 @@ func (d *DefaultDiffEngine) compareHeaders(previous, current *Response, result *
 
 	// Check for added headers
 	for key, newValue := range current.Headers {
+		if strings.EqualFold(key, setCookieHeader) {
+			continue
+		}
+
 		if _, exists := previous.Headers[key]; !exists {
 			result.HasChanges = true
 
@@ -338,28 +729,106 @@ func (d *DefaultDiffEngine) compareHeaders(previous, current *Response, result *
 	}
 }
 
+// setCookieValue returns the Set-Cookie header of recorded headers, matching the name case-insensitively
+func setCookieValue(headers map[string]string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, setCookieHeader) {
+			return value
+		}
+	}
+	return ""
+}
+
 // compareResponseBodies compares response body content
 func (d *DefaultDiffEngine) compareResponseBodies(previous, current *Response, result *DiffResult) error {
-	// Parse JSON bodies
+	if previous.Truncated || current.Truncated {
+		d.compareBodyPrefixes(previous, current, result)
+		return nil
+	}
+
+	if d.shouldStreamBodies(previous.Body, current.Body) {
+		diffs, err := d.compareArrayStreams(previous.Body, current.Body, "$")
+		if err != nil {
+			return err
+		}
+		d.recordFieldDiffs(diffs, result)
+		return nil
+	}
+
+	// Parse JSON bodies. A current body that is not JSON, such as an error page,
+	// is reported as such rather than compared when the previous response was
+	// JSON; bodies of endpoints that do not serve JSON are compared as text.
 	var prevData, currData interface{}
 
+	if len(current.Body) > 0 {
+		if err := decodeJSON(current.Body, &currData); err != nil {
+			if servesJSON(previous) {
+				d.recordUnexpectedContentType(previous, current, err, result)
+			} else {
+				d.compareBodyText(previous, current, result)
+			}
+			return nil
+		}
+	}
+
 	if len(previous.Body) > 0 {
-		if err := json.Unmarshal(previous.Body, &prevData); err != nil {
+		if err := decodeJSON(previous.Body, &prevData); err != nil {
 			return fmt.Errorf("failed to parse previous response body: %w", err)
 		}
 	}
 
-	if len(current.Body) > 0 {
-		if err := json.Unmarshal(current.Body, &currData); err != nil {
-			return fmt.Errorf("failed to parse current response body: %w", err)
+	// Narrow both bodies to the configured compare root
+	rootPath := "$"
+	if d.options.CompareRoot != "" {
+		root, err := jsonpath.Parse(d.options.CompareRoot)
+		if err != nil {
+			return fmt.Errorf("invalid compare root: %w", err)
 		}
+		rootPath = root.String()
+		prevData, _ = root.Lookup(prevData)
+		currData, _ = root.Lookup(currData)
 	}
 
 	// Compare the data structures
 	diffs := []FieldDiff{}
-	d.compareValues(prevData, currData, "$", &diffs)
+	d.compareValues(prevData, currData, rootPath, &diffs)
+
+	d.recordFieldDiffs(diffs, result)
+	return nil
+}
+
+// compareCachedResponseBodies compares response bodies through the comparison
+// cache when one is configured. Cached results are shared, so their changes are
+// copied into result rather than referenced. Truncated bodies are compared
+// differently from complete ones with the same bytes, so they are not cached.
+func (d *DefaultDiffEngine) compareCachedResponseBodies(previous, current *Response, result *DiffResult) error {
+	if d.options.Cache == nil || previous.Truncated || current.Truncated {
+		return d.compareResponseBodies(previous, current, result)
+	}
+
+	// Non-JSON bodies are reported or compared depending on their media types
+	key := newComparisonKey(d.optionsKey,
+		mediaType(headerValue(previous.Headers, contentTypeHeader)),
+		mediaType(headerValue(current.Headers, contentTypeHeader)),
+		previous.Body, current.Body)
+	bodyResult, ok := d.options.Cache.get(key)
+	if !ok {
+		bodyResult = &DiffResult{}
+		if err := d.compareResponseBodies(previous, current, bodyResult); err != nil {
+			return err
+		}
+		d.options.Cache.put(key, bodyResult)
+	}
+
+	result.StructuralChanges = append(result.StructuralChanges, bodyResult.StructuralChanges...)
+	result.DataChanges = append(result.DataChanges, bodyResult.DataChanges...)
+	result.BreakingChanges = append(result.BreakingChanges, bodyResult.BreakingChanges...)
+	result.HasChanges = result.HasChanges || bodyResult.HasChanges
+	return nil
+}
 
-	// Process field diffs and categorize them
+// recordFieldDiffs classifies field diffs and adds them to the result
+func (d *DefaultDiffEngine) recordFieldDiffs(diffs []FieldDiff, result *DiffResult) {
 	for _, diff := range diffs {
 		result.HasChanges = true
 
@@ -376,6 +845,9 @@ func (d *DefaultDiffEngine) compareResponseBodies(previous, current *Response, r
 				OldValue:    diff.OldValue,
 				NewValue:    diff.NewValue,
 			}
+			if d.options.Explain {
+				change.Explanation = classification.Explain()
+			}
 
 			result.StructuralChanges = append(result.StructuralChanges, change)
 
@@ -398,16 +870,149 @@ func (d *DefaultDiffEngine) compareResponseBodies(previous, current *Response, r
 				Severity:    classification.Severity,
 				Description: d.generateChangeDescription(diff),
 			}
+			if d.options.Explain {
+				change.Explanation = classification.Explain()
+			}
 
 			result.DataChanges = append(result.DataChanges, change)
 		}
 	}
+}
+
+// shouldStreamBodies reports whether both bodies are top-level JSON arrays large
+// enough to be compared as a stream. Streaming is not used with a compare root,
+// which needs the whole document to resolve.
+func (d *DefaultDiffEngine) shouldStreamBodies(previous, current []byte) bool {
+	threshold := d.streamingThreshold()
+	if threshold < 0 || d.options.CompareRoot != "" || d.isUnorderedArray("$") {
+		return false
+	}
+
+	if len(previous) < threshold && len(current) < threshold {
+		return false
+	}
+
+	return isJSONArray(previous) && isJSONArray(current)
+}
 
+// shouldStreamBody reports whether a single body is a top-level JSON array large
+// enough to be checked as a stream
+func (d *DefaultDiffEngine) shouldStreamBody(body []byte) bool {
+	threshold := d.streamingThreshold()
+	return threshold >= 0 && len(body) >= threshold && isJSONArray(body)
+}
+
+// streamingThreshold returns the configured streaming threshold, with zero
+// replaced by DefaultStreamingThreshold
+func (d *DefaultDiffEngine) streamingThreshold() int {
+	if d.options.StreamingThreshold == 0 {
+		return DefaultStreamingThreshold
+	}
+	return d.options.StreamingThreshold
+}
+
+// isJSONArray reports whether a body starts with a JSON array
+func isJSONArray(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// compareArrayStreams compares two top-level JSON arrays one element at a time,
+// so only a single element of each body is decoded at once. It reports the same
+// differences as compareArrays does for the fully decoded arrays.
+func (d *DefaultDiffEngine) compareArrayStreams(previous, current []byte, path string) ([]FieldDiff, error) {
+	prevDecoder := json.NewDecoder(bytes.NewReader(previous))
+	currDecoder := json.NewDecoder(bytes.NewReader(current))
+	prevDecoder.UseNumber()
+	currDecoder.UseNumber()
+
+	if err := expectArrayStart(prevDecoder); err != nil {
+		return nil, fmt.Errorf("failed to parse previous response body: %w", err)
+	}
+	if err := expectArrayStart(currDecoder); err != nil {
+		return nil, fmt.Errorf("failed to parse current response body: %w", err)
+	}
+
+	// Diffs of the elements only one array has are kept apart, as a summarized
+	// length change stands for them
+	elementDiffs, tailDiffs := []FieldDiff{}, []FieldDiff{}
+	prevLen, currLen := 0, 0
+	for i := 0; ; i++ {
+		prevMore, currMore := prevDecoder.More(), currDecoder.More()
+		if !prevMore && !currMore {
+			break
+		}
+
+		var prevItem, currItem interface{}
+		if prevMore {
+			if err := prevDecoder.Decode(&prevItem); err != nil {
+				return nil, fmt.Errorf("failed to parse previous response body: %w", err)
+			}
+			prevLen++
+		}
+		if currMore {
+			if err := currDecoder.Decode(&currItem); err != nil {
+				return nil, fmt.Errorf("failed to parse current response body: %w", err)
+			}
+			currLen++
+		}
+
+		if prevMore && currMore {
+			d.compareArrayItem(prevItem, prevMore, currItem, currMore, fmt.Sprintf("%s[%d]", path, i), &elementDiffs)
+		} else {
+			d.compareArrayItem(prevItem, prevMore, currItem, currMore, fmt.Sprintf("%s[%d]", path, i), &tailDiffs)
+		}
+	}
+
+	if _, err := prevDecoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse previous response body: %w", err)
+	}
+	if _, err := currDecoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse current response body: %w", err)
+	}
+
+	diffs := []FieldDiff{}
+	if prevLen != currLen && d.comparesValuesAt(path) {
+		diffs = append(diffs, d.arrayLengthDiff(path, prevLen, currLen))
+	}
+
+	diffs = append(diffs, elementDiffs...)
+	if !d.summarizesArrayChange(path, prevLen, currLen) {
+		diffs = append(diffs, tailDiffs...)
+	}
+	return diffs, nil
+}
+
+// expectArrayStart consumes the opening bracket of a JSON array
+func expectArrayStart(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", token)
+	}
 	return nil
 }
 
-// comparePerformance compares response performance metrics
+// comparePerformance compares response performance metrics in the configured
+// performance mode
 func (d *DefaultDiffEngine) comparePerformance(previous, current *Response, result *DiffResult) {
+	switch d.options.PerformanceMode {
+	case PerformanceModeThreshold:
+		d.comparePerformanceThreshold(current, result)
+	case PerformanceModeZScore:
+		d.comparePerformanceZScore(current, result)
+	default:
+		d.comparePerformanceDelta(previous, current, result)
+	}
+
+	attributeSlowdown(previous, current, result.PerformanceChanges)
+}
+
+// comparePerformanceDelta reports a significant change of the response time
+// since the previous response
+func (d *DefaultDiffEngine) comparePerformanceDelta(previous, current *Response, result *DiffResult) {
 	if previous.ResponseTime == 0 || current.ResponseTime == 0 {
 		return // Skip if response times are not available
 	}
@@ -416,8 +1021,8 @@ func (d *DefaultDiffEngine) comparePerformance(previous, current *Response, resu
 
 	// Only report significant performance changes (>10% change or >100ms absolute)
 	threshold := previous.ResponseTime / 10 // 10% threshold
-	if threshold < 100*time.Millisecond {
-		threshold = 100 * time.Millisecond
+	if threshold < minPerformanceDelta {
+		threshold = minPerformanceDelta
 	}
 
 	if delta >= threshold || delta <= -threshold {
@@ -436,6 +1041,10 @@ func (d *DefaultDiffEngine) comparePerformance(previous, current *Response, resu
 
 // compareValues recursively compares two values and records differences
 func (d *DefaultDiffEngine) compareValues(prev, curr interface{}, path string, diffs *[]FieldDiff) {
+	if d.isEmbeddedJSONPath(path) {
+		prev, curr = decodeEmbeddedJSON(prev), decodeEmbeddedJSON(curr)
+	}
+
 	if d.handleNilValues(prev, curr, path, diffs) {
 		return
 	}
@@ -447,12 +1056,89 @@ func (d *DefaultDiffEngine) compareValues(prev, curr interface{}, path string, d
 	d.compareValuesByType(prev, curr, path, diffs)
 }
 
-// handleNilValues handles comparison when one or both values are nil
+// isEmbeddedJSONPath reports whether a path is configured to hold encoded JSON
+func (d *DefaultDiffEngine) isEmbeddedJSONPath(path string) bool {
+	if len(d.embeddedPaths) == 0 {
+		return false
+	}
+
+	normalized := normalizeFieldPath(path)
+	for _, embedded := range d.embeddedPaths {
+		if normalized == embedded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeEmbeddedJSON decodes a string holding JSON. Other values, and strings
+// that are not valid JSON, are returned unchanged.
+func decodeEmbeddedJSON(value interface{}) interface{} {
+	encoded, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	var decoded interface{}
+	if err := decodeJSON([]byte(encoded), &decoded); err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+// comparePresence compares a field or array element that may be missing from
+// either side. With NullAsMissing, explicit nulls count as missing.
+func (d *DefaultDiffEngine) comparePresence(prev interface{}, prevExists bool, curr interface{}, currExists bool, path string, diffs *[]FieldDiff) {
+	if d.options.NullAsMissing {
+		prevExists = prevExists && prev != nil
+		currExists = currExists && curr != nil
+	}
+
+	switch {
+	case !prevExists && !currExists:
+		return
+	case !currExists:
+		*diffs = append(*diffs, FieldDiff{
+			Path:     path,
+			Type:     DiffTypeRemoved,
+			OldValue: prev,
+			Severity: d.determineSeverity(path, DiffTypeRemoved),
+		})
+	case !prevExists:
+		*diffs = append(*diffs, FieldDiff{
+			Path:     path,
+			Type:     DiffTypeAdded,
+			NewValue: curr,
+			Severity: d.determineSeverity(path, DiffTypeAdded),
+		})
+	default:
+		d.compareValues(prev, curr, path, diffs)
+	}
+}
+
+// handleNilValues handles comparison when one or both values are nil. Unless
+// NullAsMissing is set, a change between null and another value is a modification.
 func (d *DefaultDiffEngine) handleNilValues(prev, curr interface{}, path string, diffs *[]FieldDiff) bool {
 	if prev == nil && curr == nil {
 		return true
 	}
 
+	if (prev == nil || curr == nil) && !d.options.NullAsMissing {
+		if !d.comparesValuesAt(path) {
+			return true
+		}
+		*diffs = append(*diffs, FieldDiff{
+			Path:     path,
+			Type:     DiffTypeModified,
+			OldValue: prev,
+			NewValue: curr,
+			Severity: d.determineSeverity(path, DiffTypeModified),
+		})
+		return true
+	}
+
 	if prev == nil {
 		*diffs = append(*diffs, FieldDiff{
 			Path:     path,
@@ -514,51 +1200,35 @@ func (d *DefaultDiffEngine) compareValuesByType(prev, curr interface{}, path str
 // compareObjects compares two object values
 func (d *DefaultDiffEngine) compareObjects(prevValue, currValue map[string]interface{}, path string, diffs *[]FieldDiff) {
 	// Check for removed fields
-	for key, value := range prevValue {
-		fieldPath := fmt.Sprintf("%s.%s", path, key)
+	for key, prevFieldValue := range prevValue {
 		if _, exists := currValue[key]; !exists {
-			*diffs = append(*diffs, FieldDiff{
-				Path:     fieldPath,
-				Type:     DiffTypeRemoved,
-				OldValue: value,
-				Severity: d.determineSeverity(fieldPath, DiffTypeRemoved),
-			})
+			d.comparePresence(prevFieldValue, true, nil, false, fmt.Sprintf("%s.%s", path, key), diffs)
 		}
 	}
 
 	// Check for added or modified fields
 	for key, currFieldValue := range currValue {
-		fieldPath := fmt.Sprintf("%s.%s", path, key)
-		if prevFieldValue, exists := prevValue[key]; exists {
-			d.compareValues(prevFieldValue, currFieldValue, fieldPath, diffs)
-		} else {
-			*diffs = append(*diffs, FieldDiff{
-				Path:     fieldPath,
-				Type:     DiffTypeAdded,
-				NewValue: currFieldValue,
-				Severity: d.determineSeverity(fieldPath, DiffTypeAdded),
-			})
-		}
+		prevFieldValue, exists := prevValue[key]
+		d.comparePresence(prevFieldValue, exists, currFieldValue, true, fmt.Sprintf("%s.%s", path, key), diffs)
 	}
 }
 
 // compareArrays compares two array values
 func (d *DefaultDiffEngine) compareArrays(prevValue, currValue []interface{}, path string, diffs *[]FieldDiff) {
 	// Array length change
-	if len(prevValue) != len(currValue) {
-		*diffs = append(*diffs, FieldDiff{
-			Path:     path,
-			Type:     DiffTypeModified,
-			OldValue: fmt.Sprintf("array length: %d", len(prevValue)),
-			NewValue: fmt.Sprintf("array length: %d", len(currValue)),
-			Severity: SeverityMedium,
-		})
+	if len(prevValue) != len(currValue) && d.comparesValuesAt(path) {
+		*diffs = append(*diffs, d.arrayLengthDiff(path, len(prevValue), len(currValue)))
+	}
+
+	if d.comparesValuesAt(path) && d.isUnorderedArray(path) {
+		d.compareUnorderedArrays(prevValue, currValue, path, diffs)
+		return
 	}
 
-	// Compare array elements
-	maxLen := len(prevValue)
-	if len(currValue) > maxLen {
-		maxLen = len(currValue)
+	// Compare array elements; those only one array has are left to a summary
+	maxLen := max(len(prevValue), len(currValue))
+	if d.summarizesArrayChange(path, len(prevValue), len(currValue)) {
+		maxLen = min(len(prevValue), len(currValue))
 	}
 
 	for i := 0; i < maxLen; i++ {
@@ -572,13 +1242,130 @@ func (d *DefaultDiffEngine) compareArrays(prevValue, currValue []interface{}, pa
 			currItem = currValue[i]
 		}
 
-		d.compareValues(prevItem, currItem, itemPath, diffs)
+		d.compareArrayItem(prevItem, i < len(prevValue), currItem, i < len(currValue), itemPath, diffs)
+	}
+}
+
+// arrayLengthDiff returns the change in the number of elements of an array,
+// summarizing the elements added or removed when the change is summarized
+func (d *DefaultDiffEngine) arrayLengthDiff(path string, prevLen, currLen int) FieldDiff {
+	newValue := fmt.Sprintf("array length: %d", currLen)
+	if d.summarizesArrayChange(path, prevLen, currLen) {
+		if currLen > prevLen {
+			newValue = fmt.Sprintf("array length: %d (%d elements added)", currLen, currLen-prevLen)
+		} else {
+			newValue = fmt.Sprintf("array length: %d (%d elements removed)", currLen, prevLen-currLen)
+		}
+	}
+
+	return FieldDiff{
+		Path:     path,
+		Type:     DiffTypeModified,
+		OldValue: fmt.Sprintf("array length: %d", prevLen),
+		NewValue: newValue,
+		Severity: SeverityMedium,
 	}
 }
 
+// summarizesArrayChange reports whether the elements added to or removed from
+// the array at path are summarized rather than reported one by one
+func (d *DefaultDiffEngine) summarizesArrayChange(path string, prevLen, currLen int) bool {
+	threshold := d.options.ArraySummaryThreshold
+	if threshold <= 0 || !d.comparesValuesAt(path) || d.isUnorderedArray(path) {
+		return false
+	}
+
+	change := currLen - prevLen
+	return change > threshold || -change > threshold
+}
+
+// comparesValuesAt reports whether values and array lengths are compared at a
+// path, or only shapes. With tracked fields, values are compared only at and
+// below them.
+func (d *DefaultDiffEngine) comparesValuesAt(path string) bool {
+	if len(d.trackedPaths) == 0 {
+		return !d.options.ShapeOnly
+	}
+
+	normalized := normalizeFieldPath(path)
+	for _, tracked := range d.trackedPaths {
+		if isWithinPath(normalized, tracked) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnorderedArray reports whether the order of the array at path is not meaningful
+func (d *DefaultDiffEngine) isUnorderedArray(path string) bool {
+	if len(d.unorderedPaths) == 0 {
+		return false
+	}
+
+	normalized := normalizeFieldPath(path)
+	for _, unordered := range d.unorderedPaths {
+		if normalized == unordered {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compareUnorderedArrays compares two arrays as multisets. Each element is
+// matched to an equal element of the other array regardless of position;
+// unmatched elements are reported as removed or added at their own index.
+func (d *DefaultDiffEngine) compareUnorderedArrays(prevValue, currValue []interface{}, path string, diffs *[]FieldDiff) {
+	// Decoded JSON values marshal deterministically, with object keys sorted
+	// and numbers in canonical form
+	unmatched := make(map[string][]int, len(prevValue))
+	for i, item := range prevValue {
+		key, _ := json.Marshal(canonicalNumbers(item))
+		unmatched[string(key)] = append(unmatched[string(key)], i)
+	}
+
+	var added []int
+	for j, item := range currValue {
+		key, _ := json.Marshal(canonicalNumbers(item))
+		if indexes := unmatched[string(key)]; len(indexes) > 0 {
+			unmatched[string(key)] = indexes[1:]
+			continue
+		}
+		added = append(added, j)
+	}
+
+	var removed []int
+	for _, indexes := range unmatched {
+		removed = append(removed, indexes...)
+	}
+	sort.Ints(removed)
+
+	for _, i := range removed {
+		d.comparePresence(prevValue[i], true, nil, false, fmt.Sprintf("%s[%d]", path, i), diffs)
+	}
+	for _, j := range added {
+		d.comparePresence(nil, false, currValue[j], true, fmt.Sprintf("%s[%d]", path, j), diffs)
+	}
+}
+
+// compareArrayItem compares the elements at one index of two arrays. Where
+// only shapes are compared, elements missing from either array are not reported.
+func (d *DefaultDiffEngine) compareArrayItem(prev interface{}, prevExists bool, curr interface{}, currExists bool, path string, diffs *[]FieldDiff) {
+	if !d.comparesValuesAt(path) && (!prevExists || !currExists) {
+		return
+	}
+
+	d.comparePresence(prev, prevExists, curr, currExists, path, diffs)
+}
+
 // compareScalarValues compares scalar values
 func (d *DefaultDiffEngine) compareScalarValues(prev, curr interface{}, path string, diffs *[]FieldDiff) {
-	if !reflect.DeepEqual(prev, curr) {
+	if !d.comparesValuesAt(path) {
+		return
+	}
+
+	if !valuesEqual(prev, curr) && !d.withinTolerance(prev, curr) && !d.matchesValuePattern(prev, curr) {
 		*diffs = append(*diffs, FieldDiff{
 			Path:     path,
 			Type:     DiffTypeModified,
@@ -605,21 +1392,33 @@ func (d *DefaultDiffEngine) ClassifyChange(diff *FieldDiff) *ChangeClassificatio
 	// Determine if breaking
 	classification.Breaking = d.isBreakingChange(diff)
 
-	// Determine severity
+	// Determine severity, taking required fields into account
 	classification.Severity = diff.Severity
+	if context := d.changeContext(diff); context != nil {
+		classification.Severity = d.AssessSeverity(diff, context)
+	}
+
+	// Values leaving the schema's enum break the contract whatever they were before
+	if d.leavesEnum(diff) && severityRank(classification.Severity) < severityRank(SeverityHigh) {
+		classification.Severity = SeverityHigh
+	}
 
 	// Determine impact
-	classification.Impact = d.mapSeverityToImpact(diff.Severity)
+	classification.Impact = d.mapSeverityToImpact(classification.Severity)
 
 	// Generate reasoning
 	classification.Reasoning = d.generateClassificationReasoning(diff)
+	classification.Rules = d.classificationRules(diff)
 
 	return classification
 }
 
 // AssessSeverity assesses the severity of a change with additional context
 func (d *DefaultDiffEngine) AssessSeverity(diff *FieldDiff, context *ChangeContext) Severity {
-	baseSeverity := d.determineSeverity(diff.Path, diff.Type)
+	baseSeverity := diff.Severity
+	if baseSeverity == "" {
+		baseSeverity = d.determineSeverity(diff.Path, diff.Type)
+	}
 
 	// Adjust severity based on context
 	if context != nil {
@@ -634,7 +1433,7 @@ func (d *DefaultDiffEngine) AssessSeverity(diff *FieldDiff, context *ChangeConte
 
 		// Check for critical field patterns
 		if d.isCriticalField(context.FieldPath) {
-			if baseSeverity < SeverityHigh {
+			if severityRank(baseSeverity) < severityRank(SeverityHigh) {
 				baseSeverity = SeverityHigh
 			}
 		}
@@ -643,12 +1442,253 @@ func (d *DefaultDiffEngine) AssessSeverity(diff *FieldDiff, context *ChangeConte
 	return baseSeverity
 }
 
+// changeContext builds the assessment context for a diff. It returns nil when
+// the diff does not touch a required field.
+func (d *DefaultDiffEngine) changeContext(diff *FieldDiff) *ChangeContext {
+	if !d.isRequiredPath(diff.Path) {
+		return nil
+	}
+
+	return &ChangeContext{
+		FieldPath:  diff.Path,
+		IsRequired: true,
+	}
+}
+
+// leavesEnum reports whether a diff modifies an enum-constrained field to a
+// value outside the values its schema allows
+func (d *DefaultDiffEngine) leavesEnum(diff *FieldDiff) bool {
+	if diff.Type != DiffTypeModified || len(d.enumValues) == 0 {
+		return false
+	}
+
+	allowed, ok := d.enumValues[normalizeFieldPath(diff.Path)]
+	return ok && !enumContains(allowed, diff.NewValue)
+}
+
+// enumContains reports whether value is one of the allowed enum values. Numbers
+// are compared by value, since schemas and bodies may decode them differently.
+func enumContains(allowed []interface{}, value interface{}) bool {
+	for _, candidate := range allowed {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+		if a, ok := toFloat64(candidate); ok {
+			if b, ok := toFloat64(value); ok && a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toFloat64 converts a numeric value of any integer or float kind, or a decoded
+// json.Number, to float64
+func toFloat64(value interface{}) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		return f, err == nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// isRequiredPath reports whether a path is a required field or contains one
+func (d *DefaultDiffEngine) isRequiredPath(path string) bool {
+	if len(d.requiredPaths) == 0 {
+		return false
+	}
+
+	normalized := normalizeFieldPath(path)
+	for _, required := range d.requiredPaths {
+		if required == normalized ||
+			strings.HasPrefix(required, normalized+".") ||
+			strings.HasPrefix(required, normalized+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeIgnoredChanges drops changes at or below the configured ignore paths
+func (d *DefaultDiffEngine) removeIgnoredChanges(result *DiffResult) {
+	if len(d.ignoredPaths) == 0 {
+		return
+	}
+
+	structuralChanges := result.StructuralChanges[:0]
+	for _, change := range result.StructuralChanges {
+		if !d.isIgnoredPath(change.Path) {
+			structuralChanges = append(structuralChanges, change)
+		}
+	}
+	result.StructuralChanges = structuralChanges
+
+	dataChanges := result.DataChanges[:0]
+	for _, change := range result.DataChanges {
+		if !d.isIgnoredPath(change.Path) {
+			dataChanges = append(dataChanges, change)
+		}
+	}
+	result.DataChanges = dataChanges
+
+	breakingChanges := result.BreakingChanges[:0]
+	for _, change := range result.BreakingChanges {
+		if !d.isIgnoredPath(change.Path) {
+			breakingChanges = append(breakingChanges, change)
+		}
+	}
+	result.BreakingChanges = breakingChanges
+
+	result.HasChanges = len(result.StructuralChanges) > 0 || len(result.DataChanges) > 0
+}
+
+// isIgnoredPath reports whether a change path is an ignored path or lies below one
+func (d *DefaultDiffEngine) isIgnoredPath(path string) bool {
+	normalized := normalizeFieldPath(path)
+	for _, ignored := range d.ignoredPaths {
+		if isWithinPath(normalized, ignored) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchesPathPattern reports whether a field path, such as "$.pricing.plans[2].amount",
+// is the path given by a pattern or lies under it. Patterns use the syntax of
+// ignored and required fields, such as "pricing" or "$.items[*].price".
+func MatchesPathPattern(path, pattern string) bool {
+	if strings.TrimSpace(path) == "" || strings.TrimSpace(pattern) == "" {
+		return false
+	}
+	return isWithinPath(normalizeFieldPath(path), normalizeFieldPath(strings.TrimSpace(pattern)))
+}
+
+// isWithinPath reports whether a normalized path equals or lies under another
+func isWithinPath(path, parent string) bool {
+	return path == parent ||
+		strings.HasPrefix(path, parent+".") ||
+		strings.HasPrefix(path, parent+"[")
+}
+
+// MergeResults combines the changes of several results, such as those of the
+// separate checks of one monitoring cycle, into a single result. Nil results
+// are skipped; the first performance change found is kept.
+func MergeResults(results ...*DiffResult) *DiffResult {
+	merged := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		merged.StructuralChanges = append(merged.StructuralChanges, result.StructuralChanges...)
+		merged.DataChanges = append(merged.DataChanges, result.DataChanges...)
+		merged.BreakingChanges = append(merged.BreakingChanges, result.BreakingChanges...)
+		if merged.PerformanceChanges == nil {
+			merged.PerformanceChanges = result.PerformanceChanges
+		}
+	}
+
+	(&DefaultDiffEngine{}).generateSummary(merged)
+	merged.HasChanges = merged.Summary.TotalChanges > 0
+
+	return merged
+}
+
+// FilterBySeverity returns a copy of result holding only the changes at or
+// above minSeverity, along with the number of changes left out. Breaking change
+// entries are kept for the structural changes that remain. An empty minSeverity
+// keeps every change.
+func FilterBySeverity(result *DiffResult, minSeverity Severity) (*DiffResult, int) {
+	if result == nil || minSeverity == "" {
+		return result, 0
+	}
+
+	minRank := severityRank(minSeverity)
+	filtered := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+	suppressed := 0
+
+	keptPaths := make(map[string]bool)
+	for _, change := range result.StructuralChanges {
+		if severityRank(change.Severity) < minRank {
+			suppressed++
+			continue
+		}
+		filtered.StructuralChanges = append(filtered.StructuralChanges, change)
+		keptPaths[string(change.Type)+"|"+change.Path] = true
+	}
+
+	for _, change := range result.BreakingChanges {
+		if keptPaths[string(change.Type)+"|"+change.Path] {
+			filtered.BreakingChanges = append(filtered.BreakingChanges, change)
+		}
+	}
+
+	for _, change := range result.DataChanges {
+		if severityRank(change.Severity) < minRank {
+			suppressed++
+			continue
+		}
+		filtered.DataChanges = append(filtered.DataChanges, change)
+	}
+
+	if result.PerformanceChanges != nil {
+		if severityRank(result.PerformanceChanges.Severity) < minRank {
+			suppressed++
+		} else {
+			filtered.PerformanceChanges = result.PerformanceChanges
+		}
+	}
+
+	(&DefaultDiffEngine{}).generateSummary(filtered)
+	filtered.HasChanges = filtered.Summary.TotalChanges > 0
+
+	return filtered, suppressed
+}
+
+// severityRank orders severities from least to most severe
+func severityRank(severity Severity) int {
+	switch severity {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
 // Helper methods for severity and classification assessment
 
 func (d *DefaultDiffEngine) determineSeverity(path string, diffType DiffType) Severity {
 	switch diffType {
 	case DiffTypeRemoved:
-		if d.isCriticalField(path) {
+		if d.isCriticalField(path) || d.isSchemaRequired(path) {
 			return SeverityCritical
 		}
 		return SeverityHigh
@@ -666,7 +1706,18 @@ func (d *DefaultDiffEngine) determineSeverity(path string, diffType DiffType) Se
 	}
 }
 
+// isSchemaRequired reports whether the response schema lists a path as required
+func (d *DefaultDiffEngine) isSchemaRequired(path string) bool {
+	return d.schemaRequired[normalizeFieldPath(path)]
+}
+
 func (d *DefaultDiffEngine) isCriticalField(path string) bool {
+	_, critical := d.criticalFieldPattern(path)
+	return critical
+}
+
+// criticalFieldPattern returns the critical field pattern a path matches
+func (d *DefaultDiffEngine) criticalFieldPattern(path string) (string, bool) {
 	criticalPatterns := []string{
 		"id", "uuid", "key", "token", "version", "status", "type", "error", "code",
 	}
@@ -674,11 +1725,11 @@ func (d *DefaultDiffEngine) isCriticalField(path string) bool {
 	lowerPath := strings.ToLower(path)
 	for _, pattern := range criticalPatterns {
 		if strings.Contains(lowerPath, pattern) {
-			return true
+			return pattern, true
 		}
 	}
 
-	return false
+	return "", false
 }
 
 func (d *DefaultDiffEngine) isStructuralChange(diff *FieldDiff) bool {
@@ -696,6 +1747,15 @@ func (d *DefaultDiffEngine) isBreakingChange(diff *FieldDiff) bool {
 	}
 }
 
+// statusCodesMatch reports whether two status codes are the same, or of the
+// same class when status codes are compared by class
+func (d *DefaultDiffEngine) statusCodesMatch(oldCode, newCode int) bool {
+	if d.options.StatusMatch == config.StatusMatchClass {
+		return oldCode/100 == newCode/100
+	}
+	return oldCode == newCode
+}
+
 func (d *DefaultDiffEngine) assessStatusCodeSeverity(oldCode, newCode int) Severity {
 	// 2xx -> non-2xx is critical
 	if oldCode >= 200 && oldCode < 300 && (newCode < 200 || newCode >= 300) {
@@ -811,7 +1871,7 @@ func (d *DefaultDiffEngine) generateChangeDescription(diff FieldDiff) string {
 	case DiffTypeModified:
 		return fmt.Sprintf("Field '%s' changed from %v to %v", diff.Path, diff.OldValue, diff.NewValue)
 	case DiffTypeTypeChanged:
-		return fmt.Sprintf("Field '%s' type changed from %T to %T", diff.Path, diff.OldValue, diff.NewValue)
+		return fmt.Sprintf("Field '%s' type changed from %s to %s", diff.Path, jsonTypeName(diff.OldValue), jsonTypeName(diff.NewValue))
 	default:
 		return fmt.Sprintf("Field '%s' was modified", diff.Path)
 	}
@@ -856,8 +1916,18 @@ func (d *DefaultDiffEngine) generateClassificationReasoning(diff *FieldDiff) str
 		reasons = append(reasons, "type changes are breaking")
 	}
 
-	if d.isCriticalField(diff.Path) {
-		reasons = append(reasons, "field is identified as critical")
+	if pattern, critical := d.criticalFieldPattern(diff.Path); critical {
+		reasons = append(reasons, fmt.Sprintf("field is identified as critical (path matches '%s')", pattern))
+	}
+
+	if d.isSchemaRequired(diff.Path) {
+		reasons = append(reasons, "field is required by the response schema, raising its severity")
+	} else if d.isRequiredPath(diff.Path) {
+		reasons = append(reasons, "field is listed as required, raising its severity")
+	}
+
+	if d.leavesEnum(diff) {
+		reasons = append(reasons, "new value is not among the values allowed by the schema enum")
 	}
 
 	if len(reasons) == 0 {
@@ -867,6 +1937,40 @@ func (d *DefaultDiffEngine) generateClassificationReasoning(diff *FieldDiff) str
 	return strings.Join(reasons, "; ")
 }
 
+// classificationRules lists the heuristics that apply to a change
+func (d *DefaultDiffEngine) classificationRules(diff *FieldDiff) []string {
+	var rules []string
+
+	switch diff.Type {
+	case DiffTypeRemoved:
+		rules = append(rules, "field_removal")
+	case DiffTypeTypeChanged:
+		rules = append(rules, "type_change")
+	}
+
+	if pattern, critical := d.criticalFieldPattern(diff.Path); critical {
+		rules = append(rules, "critical_field:"+pattern)
+	}
+
+	if d.isRequiredPath(diff.Path) {
+		rules = append(rules, "required_field")
+	}
+
+	if d.isSchemaRequired(diff.Path) {
+		rules = append(rules, "schema_required")
+	}
+
+	if d.leavesEnum(diff) {
+		rules = append(rules, "enum_violation")
+	}
+
+	if len(rules) == 0 {
+		rules = append(rules, "default")
+	}
+
+	return rules
+}
+
 func (d *DefaultDiffEngine) generateSummary(result *DiffResult) {
 	summary := result.Summary
 
@@ -903,7 +2007,9 @@ func (d *DefaultDiffEngine) incrementSeverityCount(summary *DiffSummary, severit
 	}
 }
 
-// AnalyzeTrends analyzes trends across multiple responses (placeholder implementation)
+// AnalyzeTrends analyzes trends across multiple responses, in chronological
+// order. Histories longer than DiffOptions.MaxTrendSamples are downsampled, in
+// which case the change frequency is that between consecutive samples.
 func (d *DefaultDiffEngine) AnalyzeTrends(responses []*Response) (*TrendAnalysis, error) {
 	if len(responses) < 2 {
 		return nil, fmt.Errorf("need at least 2 responses for trend analysis")
@@ -916,6 +2022,15 @@ func (d *DefaultDiffEngine) AnalyzeTrends(responses []*Response) (*TrendAnalysis
 		StabilityScore: 1.0, // Start with perfect stability
 	}
 
+	maxSamples := d.options.MaxTrendSamples
+	if maxSamples == 0 {
+		maxSamples = DefaultMaxTrendSamples
+	}
+	if maxSamples > 0 && len(responses) > maxSamples {
+		responses = sampleEvenly(responses, maxSamples)
+		analysis.SampledResponses = len(responses)
+	}
+
 	// Calculate period
 	if len(responses) > 1 {
 		analysis.Period = responses[len(responses)-1].Timestamp.Sub(responses[0].Timestamp)
@@ -944,6 +2059,24 @@ func (d *DefaultDiffEngine) AnalyzeTrends(responses []*Response) (*TrendAnalysis
 	return analysis, nil
 }
 
+// sampleEvenly returns at most maxSamples evenly spaced responses, always
+// keeping the first and the last. At least two responses are kept.
+func sampleEvenly(responses []*Response, maxSamples int) []*Response {
+	if maxSamples < 2 {
+		maxSamples = 2
+	}
+	if len(responses) <= maxSamples {
+		return responses
+	}
+
+	last := len(responses) - 1
+	sampled := make([]*Response, 0, maxSamples)
+	for i := 0; i < maxSamples; i++ {
+		sampled = append(sampled, responses[i*last/(maxSamples-1)])
+	}
+	return sampled
+}
+
 func (d *DefaultDiffEngine) analyzePerformanceTrend(responses []*Response) *PerformanceTrend {
 	if len(responses) < 2 {
 		return nil
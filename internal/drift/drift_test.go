@@ -2,9 +2,13 @@ package drift
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-openapi/spec"
+	"github.com/k0ns0l/driftwatch/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -91,10 +95,17 @@ func TestCompareResponses_HeaderChanges(t *testing.T) {
 		},
 		{
 			name:             "Header value changed",
+			previousHeaders:  map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			currentHeaders:   map[string]string{"Content-Type": "application/json; charset=latin1"},
+			expectedChanges:  1,
+			expectedBreaking: 0, // Header value changes are tracked as data changes
+		},
+		{
+			name:             "Content type changed",
 			previousHeaders:  map[string]string{"Content-Type": "application/json"},
 			currentHeaders:   map[string]string{"Content-Type": "application/xml"},
 			expectedChanges:  1,
-			expectedBreaking: 0, // Header value changes are tracked as data changes
+			expectedBreaking: 1, // Reported as a content_type_change rather than a header change
 		},
 	}
 
@@ -124,6 +135,435 @@ func TestCompareResponses_HeaderChanges(t *testing.T) {
 	}
 }
 
+func TestCompareResponses_HeaderPatterns(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{
+		HeaderPatterns: map[string]string{"cache-control": `max-age=\d+`},
+	})
+
+	compare := func(previousValue, currentValue string) *DiffResult {
+		previous := &Response{StatusCode: 200, Headers: map[string]string{"Cache-Control": previousValue}, Body: []byte(`{}`)}
+		current := &Response{StatusCode: 200, Headers: map[string]string{"Cache-Control": currentValue}, Body: []byte(`{}`)}
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		return result
+	}
+
+	// A value change that keeps matching the pattern is not drift
+	result := compare("public, max-age=3600", "public, max-age=600")
+	assert.False(t, result.HasChanges)
+	assert.Empty(t, result.DataChanges)
+
+	// A value that stops matching is reported
+	result = compare("public, max-age=3600", "no-store")
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "$.headers.Cache-Control", result.DataChanges[0].Path)
+	assert.Equal(t, SeverityMedium, result.DataChanges[0].Severity)
+	assert.Contains(t, result.DataChanges[0].Description, "no longer matches")
+}
+
+func TestCompareResponses_IgnoreValuePatterns(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{
+		IgnoreValuePatterns: []string{
+			`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`,
+			`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`,
+			`17\d{8}`,
+		},
+	})
+
+	previous := &Response{StatusCode: 200, Body: []byte(`{
+		"data": {"items": [{"trace": {"id": "0b7c5a8e-3d1f-4e2a-9c6b-1f2e3d4c5b6a", "at": "2024-03-01T09:00:00Z"}, "name": "a"}]},
+		"generated": 1709283600,
+		"request": "req-1",
+		"ref": "0b7c5a8e-3d1f-4e2a-9c6b-1f2e3d4c5b6a"
+	}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{
+		"data": {"items": [{"trace": {"id": "9f8e7d6c-5b4a-4c3d-8e2f-0a1b2c3d4e5f", "at": "2024-03-01T09:05:00Z"}, "name": "b"}]},
+		"generated": 1709283900,
+		"request": "req-2",
+		"ref": "2024-03-01T09:05:00Z"
+	}`)}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, change := range result.DataChanges {
+		paths = append(paths, change.Path)
+	}
+
+	// Values matching a pattern before and after are not reported, however
+	// deeply nested; values matching none or different patterns are
+	assert.ElementsMatch(t, []string{"$.data.items[0].name", "$.request", "$.ref"}, paths)
+}
+
+func TestCompareResponses_BodyStatusCodes(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{
+		BodyStatusCodes: []int{200},
+		AssertedFields:  []string{"data"},
+	})
+
+	compare := func(previousStatus int, previousBody string, currentStatus int, currentBody string) *DiffResult {
+		result, err := engine.CompareResponses(
+			&Response{StatusCode: previousStatus, Body: []byte(previousBody)},
+			&Response{StatusCode: currentStatus, Body: []byte(currentBody)},
+		)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("listed status", func(t *testing.T) {
+		result := compare(200, `{"data": 1}`, 200, `{"data": 2}`)
+		require.Len(t, result.DataChanges, 1)
+		assert.Equal(t, "$.data", result.DataChanges[0].Path)
+	})
+
+	t.Run("unlisted status", func(t *testing.T) {
+		result := compare(500, `{"error_id": "a1"}`, 500, `{"error_id": "b2"}`)
+		assert.Equal(t, 0, result.Summary.TotalChanges, "error bodies and missing asserted fields are not reported")
+	})
+
+	t.Run("status change", func(t *testing.T) {
+		result := compare(200, `{"data": 1}`, 500, `{"error_id": "a1"}`)
+		require.Len(t, result.StructuralChanges, 1)
+		assert.Equal(t, "$.status_code", result.StructuralChanges[0].Path)
+		assert.Empty(t, result.DataChanges)
+	})
+}
+
+func TestCompareResponses_StatusMatch(t *testing.T) {
+	compare := func(statusMatch config.StatusMatch, previousStatus, currentStatus int) *DiffResult {
+		previous := &Response{StatusCode: previousStatus, Body: []byte(`{}`)}
+		current := &Response{StatusCode: currentStatus, Body: []byte(`{}`)}
+		result, err := NewDiffEngineWithOptions(DiffOptions{StatusMatch: statusMatch}).CompareResponses(previous, current)
+		require.NoError(t, err)
+		return result
+	}
+
+	tests := []struct {
+		name           string
+		statusMatch    config.StatusMatch
+		previousStatus int
+		currentStatus  int
+		wantChange     bool
+		wantBreaking   bool
+	}{
+		{"exact within class", "", 200, 201, true, false},
+		{"exact explicit", config.StatusMatchExact, 404, 410, true, false},
+		{"class within class", config.StatusMatchClass, 200, 201, false, false},
+		{"class within error class", config.StatusMatchClass, 500, 503, false, false},
+		{"class to error", config.StatusMatchClass, 200, 404, true, true},
+		{"class from error", config.StatusMatchClass, 500, 200, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := compare(tt.statusMatch, tt.previousStatus, tt.currentStatus)
+			if !tt.wantChange {
+				assert.False(t, result.HasChanges)
+				assert.Empty(t, result.StructuralChanges)
+				return
+			}
+
+			require.Len(t, result.StructuralChanges, 1)
+			assert.Equal(t, ChangeTypeStatusChange, result.StructuralChanges[0].Type)
+			assert.Equal(t, tt.wantBreaking, result.StructuralChanges[0].Breaking)
+		})
+	}
+}
+
+func TestCompareResponses_AuthFailure(t *testing.T) {
+	compare := func(options DiffOptions, previousStatus, currentStatus int) *DiffResult {
+		previous := &Response{StatusCode: previousStatus, Body: []byte(`{}`)}
+		current := &Response{StatusCode: currentStatus, Body: []byte(`{}`)}
+		result, err := NewDiffEngineWithOptions(options).CompareResponses(previous, current)
+		require.NoError(t, err)
+		return result
+	}
+
+	result := compare(DiffOptions{AuthConfigured: true}, 200, 401)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeAuthFailure, result.StructuralChanges[0].Type)
+	assert.Equal(t, SeverityCritical, result.StructuralChanges[0].Severity)
+	require.Len(t, result.BreakingChanges, 1)
+	assert.Contains(t, result.BreakingChanges[0].Mitigation, "credentials")
+
+	// Without credentials, or when they were not accepted before, it is an
+	// ordinary status change
+	result = compare(DiffOptions{}, 200, 403)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeStatusChange, result.StructuralChanges[0].Type)
+
+	result = compare(DiffOptions{AuthConfigured: true}, 500, 403)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeStatusChange, result.StructuralChanges[0].Type)
+}
+
+func TestCompareAuthStatus(t *testing.T) {
+	result := CompareAuthStatus(204, 403)
+	assert.True(t, result.HasChanges)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeAuthFailure, result.StructuralChanges[0].Type)
+
+	assert.False(t, CompareAuthStatus(401, 401).HasChanges)
+	assert.False(t, CompareAuthStatus(200, 404).HasChanges)
+}
+
+func TestCheckRequiredFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		fields        []string
+		expectedPaths []string
+	}{
+		{
+			name:   "all present",
+			body:   `{"id": 1, "data": {"items": [{"name": "a"}, {"name": "b"}]}}`,
+			fields: []string{"id", "$.data.items[*].name"},
+		},
+		{
+			name:          "missing and null fields",
+			body:          `{"id": null, "data": {}}`,
+			fields:        []string{"id", "data.owner", "meta.count"},
+			expectedPaths: []string{"$.id", "$.data.owner", "$.meta.count"},
+		},
+		{
+			name:          "missing in some array elements",
+			body:          `{"items": [{"name": "a"}, {}, {"name": null}]}`,
+			fields:        []string{"items[*].name"},
+			expectedPaths: []string{"$.items[1].name", "$.items[2].name"},
+		},
+		{
+			name:          "array expected",
+			body:          `{"items": {"name": "a"}}`,
+			fields:        []string{"items[*].name"},
+			expectedPaths: []string{"$.items[*].name"},
+		},
+		{
+			name:          "body is not JSON",
+			body:          `<html></html>`,
+			fields:        []string{"id"},
+			expectedPaths: []string{"$.id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckRequiredFields([]byte(tt.body), tt.fields)
+
+			var paths []string
+			for _, change := range result.StructuralChanges {
+				assert.Equal(t, ChangeTypeRequiredFieldMissing, change.Type)
+				assert.Equal(t, SeverityHigh, change.Severity)
+				paths = append(paths, change.Path)
+			}
+			assert.ElementsMatch(t, tt.expectedPaths, paths)
+			assert.Equal(t, len(tt.expectedPaths) > 0, result.HasChanges)
+			assert.Len(t, result.BreakingChanges, len(tt.expectedPaths))
+		})
+	}
+}
+
+func TestFilterBySeverity(t *testing.T) {
+	result := &DiffResult{
+		StructuralChanges: []StructuralChange{
+			{Type: ChangeTypeFieldRemoved, Path: "$.id", Severity: SeverityHigh, Breaking: true},
+			{Type: ChangeTypeFieldAdded, Path: "$.extra", Severity: SeverityLow},
+		},
+		DataChanges: []DataChange{
+			{Path: "$.name", ChangeType: ChangeTypeValueChange, Severity: SeverityLow},
+			{Path: "$.price", ChangeType: ChangeTypeValueChange, Severity: SeverityMedium},
+		},
+		BreakingChanges:    []BreakingChange{{Type: ChangeTypeFieldRemoved, Path: "$.id"}},
+		PerformanceChanges: &PerformanceChange{Severity: SeverityLow},
+		HasChanges:         true,
+	}
+
+	filtered, suppressed := FilterBySeverity(result, SeverityMedium)
+	assert.Equal(t, 3, suppressed)
+	assert.True(t, filtered.HasChanges)
+	require.Len(t, filtered.StructuralChanges, 1)
+	assert.Equal(t, "$.id", filtered.StructuralChanges[0].Path)
+	require.Len(t, filtered.DataChanges, 1)
+	assert.Equal(t, "$.price", filtered.DataChanges[0].Path)
+	assert.Len(t, filtered.BreakingChanges, 1)
+	assert.Nil(t, filtered.PerformanceChanges)
+	assert.Equal(t, 2, filtered.Summary.TotalChanges)
+
+	filtered, suppressed = FilterBySeverity(result, SeverityCritical)
+	assert.Equal(t, 5, suppressed)
+	assert.False(t, filtered.HasChanges)
+	assert.Empty(t, filtered.BreakingChanges)
+
+	filtered, suppressed = FilterBySeverity(result, "")
+	assert.Same(t, result, filtered)
+	assert.Zero(t, suppressed)
+}
+
+func TestCompareResponses_AssertedFields(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{AssertedFields: []string{"id"}})
+
+	// The field was already missing from the previous response, so only the
+	// assertion reports it
+	previous := &Response{StatusCode: 200, Body: []byte(`{"name": "a"}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{"name": "a"}`)}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeRequiredFieldMissing, result.StructuralChanges[0].Type)
+	assert.Equal(t, "$.id", result.StructuralChanges[0].Path)
+	assert.True(t, result.HasChanges)
+}
+
+func TestCompareResponses_CookieChanges(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{VolatileCookies: []string{"cart"}})
+
+	tests := []struct {
+		name             string
+		previousCookies  string
+		currentCookies   string
+		expectedPaths    []string
+		expectedBreaking int
+	}{
+		{
+			name:            "session cookie value changed",
+			previousCookies: "sessionid=abc; Path=/; Secure; HttpOnly",
+			currentCookies:  "sessionid=def; Path=/; Secure; HttpOnly",
+		},
+		{
+			name:            "configured volatile cookie value changed",
+			previousCookies: "cart=1",
+			currentCookies:  "cart=2",
+		},
+		{
+			name:            "expiry moved",
+			previousCookies: "theme=dark; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Max-Age=3600",
+			currentCookies:  "theme=dark; Expires=Thu, 22 Oct 2026 07:28:00 GMT; Max-Age=7200",
+		},
+		{
+			name:            "other cookie value changed",
+			previousCookies: "theme=dark",
+			currentCookies:  "theme=light",
+			expectedPaths:   []string{"$.headers.Set-Cookie.theme"},
+		},
+		{
+			name:             "security attributes removed",
+			previousCookies:  "sessionid=abc; Secure; HttpOnly; SameSite=Strict",
+			currentCookies:   "sessionid=def",
+			expectedPaths:    []string{"$.headers.Set-Cookie.sessionid.secure", "$.headers.Set-Cookie.sessionid.httponly", "$.headers.Set-Cookie.sessionid.samesite"},
+			expectedBreaking: 3,
+		},
+		{
+			name:             "scope changed",
+			previousCookies:  "sessionid=abc; Domain=example.com; Path=/",
+			currentCookies:   "sessionid=abc; Domain=api.example.com",
+			expectedPaths:    []string{"$.headers.Set-Cookie.sessionid.domain", "$.headers.Set-Cookie.sessionid.path"},
+			expectedBreaking: 2,
+		},
+		{
+			name:            "cookie added and removed",
+			previousCookies: "sessionid=abc\ntheme=dark",
+			currentCookies:  "sessionid=abc\nlocale=en",
+			expectedPaths:   []string{"$.headers.Set-Cookie.theme", "$.headers.Set-Cookie.locale"},
+		},
+		{
+			name:            "legacy baseline with only the first cookie",
+			previousCookies: "sessionid=abc; Path=/",
+			currentCookies:  "sessionid=def; Path=/\ntheme=dark\nlocale=en",
+		},
+		{
+			name:            "single cookie baseline for a different cookie",
+			previousCookies: "theme=dark",
+			currentCookies:  "sessionid=abc\ntheme=dark",
+			expectedPaths:   []string{"$.headers.Set-Cookie.sessionid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := &Response{
+				StatusCode: 200,
+				Headers:    map[string]string{"Content-Type": "application/json", "Set-Cookie": tt.previousCookies},
+				Body:       []byte(`{}`),
+			}
+			current := &Response{
+				StatusCode: 200,
+				Headers:    map[string]string{"Content-Type": "application/json", "Set-Cookie": tt.currentCookies},
+				Body:       []byte(`{}`),
+			}
+
+			result, err := engine.CompareResponses(previous, current)
+			require.NoError(t, err)
+
+			var paths []string
+			for _, change := range result.StructuralChanges {
+				assert.Equal(t, ChangeTypeCookieChange, change.Type)
+				paths = append(paths, change.Path)
+			}
+			for _, change := range result.DataChanges {
+				assert.Equal(t, ChangeTypeCookieChange, change.ChangeType)
+				paths = append(paths, change.Path)
+			}
+
+			assert.ElementsMatch(t, tt.expectedPaths, paths)
+			assert.Equal(t, len(tt.expectedPaths) > 0, result.HasChanges)
+			assert.Len(t, result.BreakingChanges, tt.expectedBreaking)
+		})
+	}
+}
+
+func TestCompareResponses_CookieValuesRedacted(t *testing.T) {
+	engine := NewDiffEngine()
+	previous := &Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Set-Cookie": "token=secret-one\nold=secret-two"},
+		Body:       []byte(`{}`),
+	}
+	current := &Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Set-Cookie": "token=secret-three\nnew=secret-four"},
+		Body:       []byte(`{}`),
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.Len(t, result.DataChanges, 1)
+	require.Len(t, result.StructuralChanges, 2)
+
+	var recorded []string
+	for _, change := range result.DataChanges {
+		recorded = append(recorded, change.Description, fmt.Sprint(change.OldValue), fmt.Sprint(change.NewValue))
+	}
+	for _, change := range result.StructuralChanges {
+		recorded = append(recorded, change.Description, fmt.Sprint(change.OldValue), fmt.Sprint(change.NewValue))
+	}
+	for _, text := range recorded {
+		assert.NotContains(t, text, "secret")
+	}
+	assert.NotEqual(t, result.DataChanges[0].OldValue, result.DataChanges[0].NewValue)
+}
+
+func TestRedactHeaderValue(t *testing.T) {
+	redacted := RedactHeaderValue("set-cookie", "token=secret-one; Path=/; HttpOnly\nbroken")
+	lines := strings.Split(redacted, "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "token=sha256:"), lines[0])
+	assert.Contains(t, lines[0], "HttpOnly")
+	assert.True(t, strings.HasPrefix(lines[1], "sha256:"), lines[1])
+
+	authorization := RedactHeaderValue("Authorization", "Bearer secret-token")
+	assert.True(t, strings.HasPrefix(authorization, "Bearer sha256:"), authorization)
+	assert.NotContains(t, RedactHeaderValue("Cookie", "session=secret"), "secret")
+
+	assert.Equal(t, "application/json", RedactHeaderValue("Content-Type", "application/json"))
+}
+
+func TestHeaderValue(t *testing.T) {
+	assert.Equal(t, "a=1\nb=2", HeaderValue("Set-Cookie", []string{"a=1", "b=2"}))
+	assert.Equal(t, "no-cache", HeaderValue("Cache-Control", []string{"no-cache", "no-store"}))
+	assert.Equal(t, "", HeaderValue("Set-Cookie", nil))
+}
+
 func TestCompareResponses_BodyChanges(t *testing.T) {
 	engine := NewDiffEngine()
 
@@ -382,6 +822,70 @@ func TestClassifyChange(t *testing.T) {
 	}
 }
 
+func TestClassifyChange_Rules(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{RequiredFields: []string{"$.price"}})
+
+	classification := engine.ClassifyChange(&FieldDiff{Path: "$.price", Type: DiffTypeTypeChanged, Severity: SeverityCritical})
+	assert.Equal(t, []string{"type_change", "required_field"}, classification.Rules)
+	assert.Equal(t, "type changes are breaking; field is listed as required, raising its severity", classification.Reasoning)
+
+	classification = engine.ClassifyChange(&FieldDiff{Path: "$.order_status", Type: DiffTypeModified, Severity: SeverityHigh})
+	assert.Equal(t, []string{"critical_field:status"}, classification.Rules)
+	assert.True(t, classification.Breaking)
+
+	classification = engine.ClassifyChange(&FieldDiff{Path: "$.name", Type: DiffTypeAdded, Severity: SeverityLow})
+	assert.Equal(t, []string{"default"}, classification.Rules)
+	assert.Equal(t, "standard change classification applied", classification.Reasoning)
+}
+
+func TestClassifyChange_EnumViolation(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{EnumValues: map[string][]interface{}{
+		"items[*].tier": {"free", "pro"},
+		"$.level":       {1, 2},
+	}})
+
+	previous := &Response{StatusCode: 200, Body: []byte(`{"level": 1, "items": [{"tier": "free"}, {"tier": "free"}]}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{"level": 2, "items": [{"tier": "pro"}, {"tier": "enterprise"}]}`)}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	severities := map[string]Severity{}
+	for _, change := range result.DataChanges {
+		severities[change.Path] = change.Severity
+	}
+	assert.Equal(t, SeverityHigh, severities["$.items[1].tier"])
+	assert.NotEqual(t, SeverityHigh, severities["$.items[0].tier"])
+	assert.NotEqual(t, SeverityHigh, severities["$.level"])
+
+	classification := engine.ClassifyChange(&FieldDiff{Path: "$.items[1].tier", Type: DiffTypeModified, OldValue: "free", NewValue: "enterprise", Severity: SeverityLow})
+	assert.Equal(t, []string{"enum_violation"}, classification.Rules)
+	assert.Equal(t, "new value is not among the values allowed by the schema enum", classification.Reasoning)
+
+	classification = engine.ClassifyChange(&FieldDiff{Path: "$.level", Type: DiffTypeModified, OldValue: 1.0, NewValue: 3.0, Severity: SeverityLow})
+	assert.Equal(t, SeverityHigh, classification.Severity)
+}
+
+func TestCompareResponses_Explain(t *testing.T) {
+	previous := &Response{StatusCode: 200, Body: []byte(`{"id": 1, "name": "a"}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{"id": "1", "name": "b"}`)}
+
+	result, err := NewDiffEngine().CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Nil(t, result.StructuralChanges[0].Explanation)
+
+	result, err = NewDiffEngineWithOptions(DiffOptions{Explain: true}).CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.Len(t, result.StructuralChanges, 1)
+	require.NotNil(t, result.StructuralChanges[0].Explanation)
+	assert.Equal(t, []string{"type_change", "critical_field:id"}, result.StructuralChanges[0].Explanation.Rules)
+	require.Len(t, result.DataChanges, 1)
+	require.NotNil(t, result.DataChanges[0].Explanation)
+	assert.Equal(t, []string{"default"}, result.DataChanges[0].Explanation.Rules)
+	assert.Greater(t, result.DataChanges[0].Explanation.Confidence, 0.0)
+}
+
 func TestAssessSeverity(t *testing.T) {
 	engine := NewDiffEngine().(*DefaultDiffEngine)
 
@@ -486,6 +990,36 @@ func TestAnalyzeTrends(t *testing.T) {
 	assert.Equal(t, TrendDirectionDegrading, analysis.PerformanceTrend.Trend)
 }
 
+func TestAnalyzeTrends_MaxSamples(t *testing.T) {
+	start := time.Now().Add(-100 * time.Hour)
+	responses := make([]*Response, 101)
+	for i := range responses {
+		responses[i] = &Response{
+			StatusCode:   200,
+			Body:         []byte(fmt.Sprintf(`{"count": %d}`, i)),
+			ResponseTime: 100 * time.Millisecond,
+			Timestamp:    start.Add(time.Duration(i) * time.Hour),
+		}
+	}
+
+	analysis, err := NewDiffEngineWithOptions(DiffOptions{MaxTrendSamples: 11}).AnalyzeTrends(responses)
+	require.NoError(t, err)
+	assert.Equal(t, 101, analysis.TotalResponses)
+	assert.Equal(t, 11, analysis.SampledResponses)
+	assert.Equal(t, 100*time.Hour, analysis.Period) // First and last responses are kept
+	assert.Equal(t, 1.0, analysis.ChangeFrequency)
+
+	analysis, err = NewDiffEngineWithOptions(DiffOptions{MaxTrendSamples: -1}).AnalyzeTrends(responses)
+	require.NoError(t, err)
+	assert.Zero(t, analysis.SampledResponses)
+
+	sampled := sampleEvenly(responses, 5)
+	require.Len(t, sampled, 5)
+	for i, response := range sampled {
+		assert.Same(t, responses[i*25], response)
+	}
+}
+
 func TestAnalyzeTrends_InsufficientData(t *testing.T) {
 	engine := NewDiffEngine()
 
@@ -534,11 +1068,88 @@ func TestCompareResponses_InvalidJSON(t *testing.T) {
 		Timestamp:  time.Now(),
 	}
 
-	_, err := engine.CompareResponses(previous, current)
+	// A current body that is not JSON is reported rather than failing the comparison
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeUnexpectedContentType, result.StructuralChanges[0].Type)
+	assert.Equal(t, SeverityHigh, result.StructuralChanges[0].Severity)
+
+	// A previous body that is not JSON still fails it
+	_, err = engine.CompareResponses(current, previous)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to compare response bodies")
 }
 
+func TestCompareResponses_TextBodies(t *testing.T) {
+	engine := NewDiffEngine()
+
+	page := &Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "text/html"},
+		Body:       []byte(`<html><body>Welcome</body></html>`),
+	}
+
+	// Endpoints that do not serve JSON are not reported as such
+	result, err := engine.CompareResponses(page, page)
+	require.NoError(t, err)
+	assert.False(t, result.HasChanges)
+
+	// Their bodies are compared as text
+	changed := &Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "text/html"},
+		Body:       []byte(`<html><body>Maintenance</body></html>`),
+	}
+	result, err = engine.CompareResponses(page, changed)
+	require.NoError(t, err)
+	assert.Empty(t, result.StructuralChanges)
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "$", result.DataChanges[0].Path)
+
+	// A previous JSON body without a Content-Type still makes a text body unexpected
+	previous := &Response{StatusCode: 200, Body: []byte(`{"id": 1}`)}
+	result, err = engine.CompareResponses(previous, &Response{StatusCode: 200, Body: []byte(`Bad Gateway`)})
+	require.NoError(t, err)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, ChangeTypeUnexpectedContentType, result.StructuralChanges[0].Type)
+}
+
+func TestCompareResponses_ContentTypeChange(t *testing.T) {
+	engine := NewDiffEngine()
+
+	previous := &Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       []byte(`{"id": 1}`),
+	}
+	current := &Response{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "text/html"},
+		Body:       []byte(`<html><body>502 Bad Gateway</body></html>`),
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	types := make(map[ChangeType]StructuralChange)
+	for _, change := range result.StructuralChanges {
+		types[change.Type] = change
+	}
+	require.Len(t, types, 2)
+	assert.Empty(t, result.DataChanges, "the Content-Type header change is not reported twice")
+
+	contentType := types[ChangeTypeContentTypeChange]
+	assert.Equal(t, "Content type changed from application/json to text/html", contentType.Description)
+	assert.Equal(t, SeverityHigh, contentType.Severity)
+	assert.True(t, contentType.Breaking)
+
+	unexpected := types[ChangeTypeUnexpectedContentType]
+	assert.Contains(t, unexpected.Description, "Response body is text/html rather than valid JSON")
+	assert.Equal(t, "application/json", unexpected.OldValue)
+	assert.Equal(t, "text/html", unexpected.NewValue)
+}
+
 func TestDiffSummaryGeneration(t *testing.T) {
 	engine := NewDiffEngine()
 
@@ -757,3 +1368,1289 @@ func BenchmarkCompareResponses_ComplexJSON(b *testing.B) {
 		}
 	}
 }
+
+// collectChangePaths returns the paths of all structural and data changes in a result
+func collectChangePaths(result *DiffResult) []string {
+	var paths []string
+	for _, change := range result.StructuralChanges {
+		paths = append(paths, change.Path)
+	}
+	for _, change := range result.DataChanges {
+		paths = append(paths, change.Path)
+	}
+	return paths
+}
+
+func TestCompareResponses_CompareRoot(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{CompareRoot: "$.data.schema"})
+
+	previous := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"data": {"schema": {"version": 1, "fields": ["id", "name"]}, "results": [1, 2, 3]}, "took": 12}`),
+	}
+
+	t.Run("changes outside the root are ignored", func(t *testing.T) {
+		current := &Response{
+			StatusCode: 200,
+			Body:       []byte(`{"data": {"schema": {"version": 1, "fields": ["id", "name"]}, "results": [4]}, "took": 40}`),
+		}
+
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		assert.False(t, result.HasChanges)
+	})
+
+	t.Run("changes inside the root keep full paths", func(t *testing.T) {
+		current := &Response{
+			StatusCode: 200,
+			Body:       []byte(`{"data": {"schema": {"version": 2, "fields": ["id", "name"]}, "results": []}}`),
+		}
+
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		assert.True(t, result.HasChanges)
+		assert.Equal(t, []string{"$.data.schema.version"}, collectChangePaths(result))
+	})
+
+	t.Run("missing root is reported as removed", func(t *testing.T) {
+		current := &Response{
+			StatusCode: 200,
+			Body:       []byte(`{"data": {"results": []}}`),
+		}
+
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		assert.True(t, result.HasChanges)
+		assert.Equal(t, []string{"$.data.schema"}, collectChangePaths(result))
+	})
+
+	t.Run("invalid root", func(t *testing.T) {
+		invalid := NewDiffEngineWithOptions(DiffOptions{CompareRoot: "data.schema"})
+		_, err := invalid.CompareResponses(previous, previous)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompareResponses_RequiredFields(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{
+		RequiredFields: []string{"profile.email", "$.items[*].sku"},
+	})
+
+	previous := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"name": "a", "items": [{"qty": 1}]}`),
+	}
+	current := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"name": "a", "nickname": "b", "profile": {"email": "a@example.com"}, "items": [{"qty": 1, "sku": "x"}]}`),
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	severities := make(map[string]Severity)
+	for _, change := range result.StructuralChanges {
+		severities[change.Path] = change.Severity
+	}
+
+	// Optional additions stay low, additions touching required paths are elevated
+	assert.Equal(t, SeverityLow, severities["$.nickname"])
+	assert.Equal(t, SeverityMedium, severities["$.profile"])
+	assert.Equal(t, SeverityMedium, severities["$.items[0].sku"])
+	assert.Equal(t, 2, result.Summary.MediumChanges)
+}
+
+func TestCompareResponses_ResponseSchema(t *testing.T) {
+	schema := &spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:     spec.StringOrArray{"object"},
+		Required: []string{"name", "price"},
+		Properties: map[string]spec.Schema{
+			"name":     {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+			"price":    {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"number"}}},
+			"nickname": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+			"notes":    {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+		},
+	}}
+	engine := NewDiffEngineWithOptions(DiffOptions{ResponseSchema: schema, Explain: true})
+
+	previous := &Response{StatusCode: 200, Body: []byte(`{"name": "a", "price": 10, "nickname": "b", "notes": "x"}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{"price": 12, "notes": "y"}`)}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	severities := make(map[string]Severity)
+	for _, change := range result.StructuralChanges {
+		severities[change.Path] = change.Severity
+	}
+	for _, change := range result.DataChanges {
+		severities[change.Path] = change.Severity
+	}
+
+	// Removing a field the schema requires breaks the contract; removing an
+	// optional one is assessed by the usual heuristics
+	assert.Equal(t, SeverityCritical, severities["$.name"])
+	assert.Equal(t, SeverityHigh, severities["$.nickname"])
+
+	// Modifications of required fields are elevated
+	assert.Equal(t, SeverityHigh, severities["$.price"])
+	assert.Equal(t, SeverityMedium, severities["$.notes"])
+
+	classification := engine.ClassifyChange(&FieldDiff{Path: "$.name", Type: DiffTypeRemoved})
+	assert.Contains(t, classification.Rules, "schema_required")
+	assert.Contains(t, classification.Reasoning, "required by the response schema")
+}
+
+func TestAssessSeverity_DoesNotDowngradeCritical(t *testing.T) {
+	engine := NewDiffEngine().(*DefaultDiffEngine)
+
+	diff := &FieldDiff{Path: "$.status", Type: DiffTypeRemoved, Severity: SeverityCritical}
+	severity := engine.AssessSeverity(diff, &ChangeContext{FieldPath: "$.status", IsRequired: true})
+	assert.Equal(t, SeverityCritical, severity)
+}
+
+func TestNormalizeFieldPath(t *testing.T) {
+	assert.Equal(t, "$.user.name", normalizeFieldPath("user.name"))
+	assert.Equal(t, "$.user.name", normalizeFieldPath("$.user.name"))
+	assert.Equal(t, "$.items[*].id", normalizeFieldPath("$.items[3].id"))
+	assert.Equal(t, "$.items[*].id", normalizeFieldPath("items[].id"))
+	assert.Equal(t, "$[*]", normalizeFieldPath("$[0]"))
+}
+
+func TestMatchesPathPattern(t *testing.T) {
+	assert.True(t, MatchesPathPattern("$.pricing", "$.pricing"))
+	assert.True(t, MatchesPathPattern("$.pricing.plans[2].amount", "pricing"))
+	assert.True(t, MatchesPathPattern("$.pricing[0]", "$.pricing"))
+	assert.True(t, MatchesPathPattern("$.items[4].price", "items[*].price"))
+	assert.False(t, MatchesPathPattern("$.pricingNotes", "$.pricing"))
+	assert.False(t, MatchesPathPattern("$.price", "$.pricing"))
+	assert.False(t, MatchesPathPattern("", "$.pricing"))
+	assert.False(t, MatchesPathPattern("$.pricing", ""))
+}
+
+func TestFindVolatileFields(t *testing.T) {
+	body := []byte(`{
+		"id": 42,
+		"name": "widget",
+		"request_id": "abc123",
+		"created_at": "2024-03-01T12:30:00Z",
+		"updatedAt": 1709296200,
+		"size": 1709296200,
+		"owner": {"uuid": "3f2b6c1e-8d4a-4f6b-9c2d-1a2b3c4d5e6f"},
+		"items": [
+			{"sku": "A-1", "modified": "2024-03-01 12:30:00"},
+			{"sku": "B-2", "modified": "2024-03-02 08:00:00"}
+		]
+	}`)
+
+	fields, err := FindVolatileFields(body)
+	require.NoError(t, err)
+
+	assert.Equal(t, []VolatileField{
+		{Path: "created_at", Reason: "timestamp value"},
+		{Path: "items[*].modified", Reason: "timestamp value"},
+		{Path: "owner.uuid", Reason: "uuid value"},
+		{Path: "request_id", Reason: "per-request identifier"},
+		{Path: "updatedAt", Reason: "epoch timestamp"},
+	}, fields)
+}
+
+func TestFindVolatileFields_InvalidJSON(t *testing.T) {
+	_, err := FindVolatileFields([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestFindVaryingFields(t *testing.T) {
+	samples := []*Response{
+		{
+			StatusCode: 200,
+			Headers:    map[string]string{"Date": "Mon, 01 Jan 2024 00:00:00 GMT"},
+			Body:       []byte(`{"id": 1, "served_by": "a", "items": [{"name": "x", "rank": 1}]}`),
+		},
+		{
+			StatusCode: 200,
+			Headers:    map[string]string{"Date": "Mon, 01 Jan 2024 00:00:01 GMT"},
+			Body:       []byte(`{"id": 1, "served_by": "b", "items": [{"name": "x", "rank": 2}]}`),
+		},
+		{
+			StatusCode: 503,
+			Headers:    map[string]string{"Date": "Mon, 01 Jan 2024 00:00:02 GMT"},
+			Body:       []byte(`{"id": 1, "served_by": "a", "items": [{"name": "x", "rank": 1}]}`),
+		},
+	}
+
+	fields, err := FindVaryingFields(samples, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"$.headers.Date", "$.items[*].rank", "$.served_by"}, fields)
+
+	fields, err = FindVaryingFields(samples[:1], nil)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestCompareResponses_IgnoreFields(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{IgnoreFields: []string{"meta", "$.items[*].rank"}})
+
+	previous := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"meta": {"request_id": "a"}, "items": [{"name": "x", "rank": 1}]}`),
+	}
+	current := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"meta": {"request_id": "b", "region": "eu"}, "items": [{"name": "x", "rank": 2}]}`),
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	assert.False(t, result.HasChanges)
+	assert.Equal(t, 0, result.Summary.TotalChanges)
+
+	current.Body = []byte(`{"meta": {"request_id": "b"}, "items": [{"name": "y", "rank": 2}]}`)
+	result, err = engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	assert.True(t, result.HasChanges)
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "$.items[0].name", result.DataChanges[0].Path)
+}
+
+func TestCompareResponses_EmbeddedJSONFields(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{EmbeddedJSONFields: []string{"payload", "events[*].data", "payload.inner"}})
+
+	previous := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"payload": "{\"a\":1,\"inner\":\"{\\\"b\\\":2}\"}", "events": [{"data": "{\"x\":true}"}], "note": "not json"}`),
+	}
+	current := &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"payload": "{ \"inner\": \"{ \\\"b\\\": 2 }\", \"a\": 1 }", "events": [{"data": "{\n  \"x\": true\n}"}], "note": "not json"}`),
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	assert.False(t, result.HasChanges)
+
+	current.Body = []byte(`{"payload": "{\"a\": 2, \"inner\": \"{\\\"b\\\": 2}\"}", "events": [{"data": "{\"x\": true}"}], "note": "not json"}`)
+	result, err = engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "$.payload.a", result.DataChanges[0].Path)
+
+	// Without the option the encoded strings are compared as they are
+	result, err = NewDiffEngine().CompareResponses(previous, &Response{
+		StatusCode: 200,
+		Body:       []byte(`{"payload": "{ \"a\": 1, \"inner\": \"{\\\"b\\\":2}\" }", "events": [{"data": "{\"x\":true}"}], "note": "not json"}`),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "$.payload", result.DataChanges[0].Path)
+}
+
+func TestCompareResponses_NullAsMissing(t *testing.T) {
+	tests := []struct {
+		name          string
+		previous      string
+		current       string
+		nullAsMissing bool
+		expectedType  DiffType
+	}{
+		{name: "null to absent, distinct", previous: `{"x": null}`, current: `{}`, expectedType: DiffTypeRemoved},
+		{name: "null to absent, as missing", previous: `{"x": null}`, current: `{}`, nullAsMissing: true},
+		{name: "absent to null, distinct", previous: `{}`, current: `{"x": null}`, expectedType: DiffTypeAdded},
+		{name: "absent to null, as missing", previous: `{}`, current: `{"x": null}`, nullAsMissing: true},
+		{name: "null to value, distinct", previous: `{"x": null}`, current: `{"x": "a"}`, expectedType: DiffTypeModified},
+		{name: "null to value, as missing", previous: `{"x": null}`, current: `{"x": "a"}`, nullAsMissing: true, expectedType: DiffTypeAdded},
+		{name: "value to null, distinct", previous: `{"x": "a"}`, current: `{"x": null}`, expectedType: DiffTypeModified},
+		{name: "value to null, as missing", previous: `{"x": "a"}`, current: `{"x": null}`, nullAsMissing: true, expectedType: DiffTypeRemoved},
+		{name: "value to absent, distinct", previous: `{"x": "a"}`, current: `{}`, expectedType: DiffTypeRemoved},
+		{name: "value to absent, as missing", previous: `{"x": "a"}`, current: `{}`, nullAsMissing: true, expectedType: DiffTypeRemoved},
+		{name: "absent to value, distinct", previous: `{}`, current: `{"x": "a"}`, expectedType: DiffTypeAdded},
+		{name: "absent to value, as missing", previous: `{}`, current: `{"x": "a"}`, nullAsMissing: true, expectedType: DiffTypeAdded},
+		{name: "null to null", previous: `{"x": null}`, current: `{"x": null}`},
+		{name: "null element to missing element, as missing", previous: `[1, null]`, current: `[1]`, nullAsMissing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewDiffEngineWithOptions(DiffOptions{NullAsMissing: tt.nullAsMissing}).(*DefaultDiffEngine)
+
+			var prev, curr interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.previous), &prev))
+			require.NoError(t, json.Unmarshal([]byte(tt.current), &curr))
+
+			var diffs []FieldDiff
+			engine.compareValues(prev, curr, "$", &diffs)
+
+			var fieldDiffs []FieldDiff
+			for _, diff := range diffs {
+				if diff.Path != "$" {
+					fieldDiffs = append(fieldDiffs, diff)
+				}
+			}
+
+			if tt.expectedType == "" {
+				assert.Empty(t, fieldDiffs)
+				return
+			}
+			require.Len(t, fieldDiffs, 1)
+			assert.Equal(t, "$.x", fieldDiffs[0].Path)
+			assert.Equal(t, tt.expectedType, fieldDiffs[0].Type)
+		})
+	}
+}
+
+func TestCompareResponses_ShapeOnly(t *testing.T) {
+	previous := &Response{StatusCode: 200, Body: []byte(`{
+		"id": 1, "name": "a", "note": null,
+		"items": [{"sku": "x", "qty": 1}, {"sku": "y", "qty": 2}],
+		"owner": {"id": 7}
+	}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{
+		"id": 2, "name": "b", "note": "hello", "extra": true,
+		"items": [{"sku": "z", "qty": "3"}],
+		"owner": {}
+	}`)}
+
+	result, err := NewDiffEngineWithOptions(DiffOptions{ShapeOnly: true}).CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	changes := map[string]ChangeType{}
+	for _, change := range result.StructuralChanges {
+		changes[change.Path] = change.Type
+	}
+	for _, change := range result.DataChanges {
+		changes[change.Path] = change.ChangeType
+	}
+	assert.Equal(t, map[string]ChangeType{
+		"$.extra":        ChangeTypeFieldAdded,
+		"$.items[0].qty": ChangeTypeTypeChange,
+		"$.owner.id":     ChangeTypeFieldRemoved,
+	}, changes)
+
+	t.Run("streamed arrays", func(t *testing.T) {
+		previous := &Response{StatusCode: 200, Body: []byte(`[{"id": 1}, {"id": 2}]`)}
+		current := &Response{StatusCode: 200, Body: []byte(`[{"id": 3, "tag": "new"}]`)}
+
+		engine := NewDiffEngineWithOptions(DiffOptions{ShapeOnly: true, StreamingThreshold: 1})
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		require.Len(t, result.StructuralChanges, 1)
+		assert.Equal(t, "$[0].tag", result.StructuralChanges[0].Path)
+		assert.Empty(t, result.DataChanges)
+	})
+}
+
+func TestCompareResponses_UnorderedArrays(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  string
+		current   string
+		unordered []string
+		expected  map[string]DiffType
+	}{
+		{
+			name:      "reordered primitives",
+			previous:  `{"tags": ["a", "b", "c"]}`,
+			current:   `{"tags": ["c", "a", "b"]}`,
+			unordered: []string{"tags"},
+			expected:  map[string]DiffType{},
+		},
+		{
+			name:     "reordered primitives with order",
+			previous: `{"tags": ["a", "b", "c"]}`,
+			current:  `{"tags": ["c", "a", "b"]}`,
+			expected: map[string]DiffType{
+				"$.tags[0]": DiffTypeModified,
+				"$.tags[1]": DiffTypeModified,
+				"$.tags[2]": DiffTypeModified,
+			},
+		},
+		{
+			name:      "duplicates are counted",
+			previous:  `{"tags": ["a", "a", "b"]}`,
+			current:   `{"tags": ["b", "a", "c"]}`,
+			unordered: []string{"tags"},
+			expected: map[string]DiffType{
+				"$.tags[1]": DiffTypeRemoved,
+				"$.tags[2]": DiffTypeAdded,
+			},
+		},
+		{
+			name:      "reordered objects in nested arrays",
+			previous:  `{"items": [{"roles": [{"id": 1, "name": "x"}, {"id": 2}]}]}`,
+			current:   `{"items": [{"roles": [{"id": 2}, {"name": "x", "id": 1}, {"id": 3}]}]}`,
+			unordered: []string{"items[*].roles"},
+			expected: map[string]DiffType{
+				"$.items[0].roles":    DiffTypeModified,
+				"$.items[0].roles[2]": DiffTypeAdded,
+			},
+		},
+		{
+			name:      "other arrays keep their order",
+			previous:  `{"tags": ["a", "b"], "steps": ["x", "y"]}`,
+			current:   `{"tags": ["b", "a"], "steps": ["y", "x"]}`,
+			unordered: []string{"tags"},
+			expected: map[string]DiffType{
+				"$.steps[0]": DiffTypeModified,
+				"$.steps[1]": DiffTypeModified,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewDiffEngineWithOptions(DiffOptions{UnorderedArrays: tt.unordered}).(*DefaultDiffEngine)
+
+			var prev, curr interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.previous), &prev))
+			require.NoError(t, json.Unmarshal([]byte(tt.current), &curr))
+
+			var diffs []FieldDiff
+			engine.compareValues(prev, curr, "$", &diffs)
+
+			types := map[string]DiffType{}
+			for _, diff := range diffs {
+				types[diff.Path] = diff.Type
+			}
+			assert.Equal(t, tt.expected, types)
+		})
+	}
+
+	t.Run("top-level array is not streamed", func(t *testing.T) {
+		previous := &Response{StatusCode: 200, Body: []byte(`[1, 2, 3]`)}
+		current := &Response{StatusCode: 200, Body: []byte(`[3, 2, 1]`)}
+
+		engine := NewDiffEngineWithOptions(DiffOptions{UnorderedArrays: []string{"$"}, StreamingThreshold: 1})
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		assert.False(t, result.HasChanges)
+	})
+}
+
+func TestCompareResponses_PerformanceModes(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, 0, len(values))
+		for _, value := range values {
+			durations = append(durations, time.Duration(value)*time.Millisecond)
+		}
+		return durations
+	}
+
+	tests := []struct {
+		name             string
+		options          DiffOptions
+		previousTime     time.Duration
+		currentTime      time.Duration
+		expectedSeverity Severity // Empty when no change is expected
+	}{
+		{
+			name:         "threshold not exceeded",
+			options:      DiffOptions{PerformanceMode: PerformanceModeThreshold, ResponseTimeThreshold: time.Second},
+			previousTime: 100 * time.Millisecond,
+			currentTime:  900 * time.Millisecond,
+		},
+		{
+			name:             "threshold exceeded",
+			options:          DiffOptions{PerformanceMode: PerformanceModeThreshold, ResponseTimeThreshold: time.Second},
+			previousTime:     1100 * time.Millisecond,
+			currentTime:      1200 * time.Millisecond,
+			expectedSeverity: SeverityLow,
+		},
+		{
+			name:         "zscore without enough history",
+			options:      DiffOptions{PerformanceMode: PerformanceModeZScore, ResponseTimeHistory: ms(200, 210)},
+			previousTime: 200 * time.Millisecond,
+			currentTime:  2 * time.Second,
+		},
+		{
+			name:         "zscore within normal variation",
+			options:      DiffOptions{PerformanceMode: PerformanceModeZScore, ResponseTimeHistory: ms(200, 400, 200, 400, 200, 400)},
+			previousTime: 200 * time.Millisecond,
+			currentTime:  550 * time.Millisecond,
+		},
+		{
+			name:             "zscore anomaly",
+			options:          DiffOptions{PerformanceMode: PerformanceModeZScore, ResponseTimeHistory: ms(200, 210, 190, 205, 195)},
+			previousTime:     200 * time.Millisecond,
+			currentTime:      450 * time.Millisecond,
+			expectedSeverity: SeverityCritical,
+		},
+		{
+			name:             "zscore with a lower factor",
+			options:          DiffOptions{PerformanceMode: PerformanceModeZScore, ZScoreThreshold: 1, ResponseTimeHistory: ms(200, 400, 200, 400, 200, 400)},
+			previousTime:     200 * time.Millisecond,
+			currentTime:      550 * time.Millisecond,
+			expectedSeverity: SeverityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewDiffEngineWithOptions(tt.options)
+
+			previous := &Response{StatusCode: 200, Body: []byte(`{}`), ResponseTime: tt.previousTime}
+			current := &Response{StatusCode: 200, Body: []byte(`{}`), ResponseTime: tt.currentTime}
+
+			result, err := engine.CompareResponses(previous, current)
+			require.NoError(t, err)
+
+			if tt.expectedSeverity == "" {
+				assert.Nil(t, result.PerformanceChanges)
+				return
+			}
+			require.NotNil(t, result.PerformanceChanges)
+			assert.Equal(t, tt.expectedSeverity, result.PerformanceChanges.Severity)
+			assert.True(t, result.HasChanges)
+		})
+	}
+}
+
+func TestCompareResponses_PerformancePhase(t *testing.T) {
+	engine := NewDiffEngine()
+
+	previous := &Response{
+		StatusCode:   200,
+		Body:         []byte(`{}`),
+		ResponseTime: 200 * time.Millisecond,
+		Timing:       &Timing{DNS: 5 * time.Millisecond, Connect: 10 * time.Millisecond, TLS: 30 * time.Millisecond, TTFB: 150 * time.Millisecond},
+	}
+	current := &Response{
+		StatusCode:   200,
+		Body:         []byte(`{}`),
+		ResponseTime: 650 * time.Millisecond,
+		Timing:       &Timing{DNS: 5 * time.Millisecond, Connect: 60 * time.Millisecond, TLS: 30 * time.Millisecond, TTFB: 550 * time.Millisecond},
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.NotNil(t, result.PerformanceChanges)
+	assert.Equal(t, "ttfb", result.PerformanceChanges.Phase)
+	assert.Equal(t, 400*time.Millisecond, result.PerformanceChanges.PhaseDelta)
+	assert.Contains(t, result.PerformanceChanges.Description, "mostly in the server processing (TTFB) (+400ms)")
+
+	// Without a breakdown on both sides the slowdown is not attributed
+	current.Timing = nil
+	result, err = engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	require.NotNil(t, result.PerformanceChanges)
+	assert.Empty(t, result.PerformanceChanges.Phase)
+	assert.NotContains(t, result.PerformanceChanges.Description, "mostly in")
+}
+
+func TestCompareResponses_TrackedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		current  string
+		expected map[string]DiffType
+	}{
+		{
+			name:     "untracked values are ignored",
+			previous: `{"meta": {"total": 2, "took_ms": 10}, "results": [{"id": 1}, {"id": 2}]}`,
+			current:  `{"meta": {"total": 2, "took_ms": 12}, "results": [{"id": 3}]}`,
+			expected: map[string]DiffType{},
+		},
+		{
+			name:     "tracked value changed",
+			previous: `{"meta": {"total": 2, "schema_version": "1"}, "results": []}`,
+			current:  `{"meta": {"total": 3, "schema_version": "1"}, "results": []}`,
+			expected: map[string]DiffType{"$.meta.total": DiffTypeModified},
+		},
+		{
+			name:     "tracked value became null",
+			previous: `{"meta": {"total": 2}}`,
+			current:  `{"meta": {"total": null}}`,
+			expected: map[string]DiffType{"$.meta.total": DiffTypeModified},
+		},
+		{
+			name:     "structural changes are reported",
+			previous: `{"meta": {"total": 2}, "results": [{"id": 1, "name": "a"}]}`,
+			current:  `{"meta": {"total": 2}, "results": [{"id": "1"}]}`,
+			expected: map[string]DiffType{
+				"$.results[0].id":   DiffTypeTypeChanged,
+				"$.results[0].name": DiffTypeRemoved,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewDiffEngineWithOptions(DiffOptions{TrackedFields: []string{"meta.total", "$.meta.schema_version"}}).(*DefaultDiffEngine)
+
+			var prev, curr interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.previous), &prev))
+			require.NoError(t, json.Unmarshal([]byte(tt.current), &curr))
+
+			var diffs []FieldDiff
+			engine.compareValues(prev, curr, "$", &diffs)
+
+			types := map[string]DiffType{}
+			for _, diff := range diffs {
+				types[diff.Path] = diff.Type
+			}
+			assert.Equal(t, tt.expected, types)
+		})
+	}
+}
+
+func TestCompareResponses_VersionField(t *testing.T) {
+	tests := []struct {
+		name             string
+		options          DiffOptions
+		previous         string
+		current          string
+		expectedSeverity Severity // Empty when no version change is expected
+		expectedPaths    []string // Paths of the other changes
+	}{
+		{
+			name:             "version bump",
+			options:          DiffOptions{VersionField: "schema_version"},
+			previous:         `{"schema_version": "1.2", "name": "a"}`,
+			current:          `{"schema_version": "1.3", "name": "b"}`,
+			expectedSeverity: SeverityLow,
+			expectedPaths:    []string{"$.name"},
+		},
+		{
+			name:             "configured severity",
+			options:          DiffOptions{VersionField: "$.meta.apiVersion", VersionChangeSeverity: SeverityMedium},
+			previous:         `{"meta": {"apiVersion": 1}}`,
+			current:          `{"meta": {"apiVersion": 2}}`,
+			expectedSeverity: SeverityMedium,
+		},
+		{
+			name:             "compared in shape-only mode",
+			options:          DiffOptions{VersionField: "schema_version", ShapeOnly: true},
+			previous:         `{"schema_version": "1"}`,
+			current:          `{"schema_version": "2"}`,
+			expectedSeverity: SeverityLow,
+		},
+		{
+			name:          "unchanged version",
+			options:       DiffOptions{VersionField: "schema_version"},
+			previous:      `{"schema_version": "1", "name": "a"}`,
+			current:       `{"schema_version": "1", "name": "b"}`,
+			expectedPaths: []string{"$.name"},
+		},
+		{
+			name:     "version field introduced",
+			options:  DiffOptions{VersionField: "schema_version"},
+			previous: `{"name": "a"}`,
+			current:  `{"schema_version": "1", "name": "a"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewDiffEngineWithOptions(tt.options)
+			result, err := engine.CompareResponses(
+				&Response{StatusCode: 200, Body: []byte(tt.previous)},
+				&Response{StatusCode: 200, Body: []byte(tt.current)},
+			)
+			require.NoError(t, err)
+
+			var versionChanges []StructuralChange
+			var paths []string
+			for _, change := range result.StructuralChanges {
+				if change.Type == ChangeTypeVersionChange {
+					versionChanges = append(versionChanges, change)
+				} else {
+					paths = append(paths, change.Path)
+				}
+			}
+			for _, change := range result.DataChanges {
+				paths = append(paths, change.Path)
+			}
+
+			assert.Equal(t, tt.expectedPaths, paths)
+			assert.Empty(t, result.BreakingChanges)
+			if tt.expectedSeverity == "" {
+				assert.Empty(t, versionChanges)
+				return
+			}
+			require.Len(t, versionChanges, 1)
+			assert.Equal(t, tt.expectedSeverity, versionChanges[0].Severity)
+			assert.False(t, versionChanges[0].Breaking)
+		})
+	}
+}
+
+func TestCompareVersionFields(t *testing.T) {
+	result := CompareVersionFields([]byte(`{"v": "1"}`), []byte(`{"v": "2"}`), "v", SeverityHigh)
+	assert.True(t, result.HasChanges)
+	require.Len(t, result.StructuralChanges, 1)
+	assert.Equal(t, "$.v", result.StructuralChanges[0].Path)
+	assert.Equal(t, "API version changed from 1 to 2", result.StructuralChanges[0].Description)
+	assert.Equal(t, SeverityHigh, result.StructuralChanges[0].Severity)
+
+	assert.False(t, CompareVersionFields([]byte(`{"v": "1"}`), []byte(`{"v": "1"}`), "v", "").HasChanges)
+	assert.False(t, CompareVersionFields([]byte(`not json`), []byte(`{"v": "1"}`), "v", "").HasChanges)
+	assert.False(t, CompareVersionFields([]byte(`{"v": {"major": 1}}`), []byte(`{"v": {"major": 2}}`), "v", "").HasChanges)
+}
+
+func TestCompareResponses_NumberPrecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  DiffOptions
+		previous string
+		current  string
+		expected map[string]DiffType
+	}{
+		{
+			name:     "identical large integers",
+			previous: `{"id": 1234567890123456789}`,
+			current:  `{"id": 1234567890123456789}`,
+			expected: map[string]DiffType{},
+		},
+		{
+			name:     "large integers differing beyond float64 precision",
+			previous: `{"id": 1234567890123456789}`,
+			current:  `{"id": 1234567890123456788}`,
+			expected: map[string]DiffType{"$.id": DiffTypeModified},
+		},
+		{
+			name:     "decimals differing beyond float64 precision",
+			previous: `{"amount": 0.10000000000000000001}`,
+			current:  `{"amount": 0.10000000000000000002}`,
+			expected: map[string]DiffType{"$.amount": DiffTypeModified},
+		},
+		{
+			name:     "same value spelled differently",
+			previous: `{"amount": 1.50, "count": 100}`,
+			current:  `{"amount": 1.5, "count": 1e2}`,
+			expected: map[string]DiffType{},
+		},
+		{
+			name:     "number became string",
+			previous: `{"id": 1}`,
+			current:  `{"id": "1"}`,
+			expected: map[string]DiffType{"$.id": DiffTypeTypeChanged},
+		},
+		{
+			name:     "unordered arrays match numbers by value",
+			options:  DiffOptions{UnorderedArrays: []string{"amounts"}},
+			previous: `{"amounts": [1.50, 9007199254740993]}`,
+			current:  `{"amounts": [9007199254740993, 1.5]}`,
+			expected: map[string]DiffType{},
+		},
+		{
+			name:     "streamed arrays keep precision",
+			options:  DiffOptions{StreamingThreshold: 1},
+			previous: `[{"id": 9007199254740993}]`,
+			current:  `[{"id": 9007199254740992}]`,
+			expected: map[string]DiffType{"$[0].id": DiffTypeModified},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewDiffEngineWithOptions(tt.options)
+			result, err := engine.CompareResponses(
+				&Response{StatusCode: 200, Body: []byte(tt.previous)},
+				&Response{StatusCode: 200, Body: []byte(tt.current)},
+			)
+			require.NoError(t, err)
+
+			changes := map[string]DiffType{}
+			for _, change := range result.StructuralChanges {
+				changes[change.Path] = DiffTypeTypeChanged
+			}
+			for _, change := range result.DataChanges {
+				changes[change.Path] = DiffTypeModified
+			}
+			assert.Equal(t, tt.expected, changes)
+		})
+	}
+}
+
+func TestCompareResponses_NumberValuesKeepRepresentation(t *testing.T) {
+	engine := NewDiffEngine()
+	result, err := engine.CompareResponses(
+		&Response{StatusCode: 200, Body: []byte(`{"id": 1234567890123456789}`)},
+		&Response{StatusCode: 200, Body: []byte(`{"id": 1234567890123456788}`)},
+	)
+	require.NoError(t, err)
+	require.Len(t, result.DataChanges, 1)
+	assert.Equal(t, "Field '$.id' changed from 1234567890123456789 to 1234567890123456788", result.DataChanges[0].Description)
+}
+
+func TestCompareResponses_NumericTolerance(t *testing.T) {
+	engine := NewDiffEngineWithOptions(DiffOptions{NumericTolerance: 0.01})
+	result, err := engine.CompareResponses(
+		&Response{StatusCode: 200, Body: []byte(`{"price": 100, "stock": 40, "rating": "4.5", "active": true}`)},
+		&Response{StatusCode: 200, Body: []byte(`{"price": 100.9, "stock": 42, "rating": "4.6", "active": false}`)},
+	)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, change := range result.DataChanges {
+		paths = append(paths, change.Path)
+	}
+	// Strings and booleans are compared exactly whatever the tolerance
+	assert.ElementsMatch(t, []string{"$.stock", "$.rating", "$.active"}, paths)
+}
+
+func TestCanonicalNumber(t *testing.T) {
+	tests := map[string]string{
+		"0":       "0",
+		"-0":      "0",
+		"-0.0":    "0",
+		"1.50":    "1.5",
+		"-12.000": "-12",
+		"0.001":   "0.001",
+		"1e2":     "100",
+		"1.5E-1":  "0.15",
+		"12345678901234567890.123456789012345678900": "12345678901234567890.1234567890123456789",
+	}
+
+	for number, expected := range tests {
+		assert.Equal(t, expected, canonicalNumber(json.Number(number)), number)
+	}
+}
+
+func TestCompareResponses_ProtocolAndTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous *Response
+		current  *Response
+		expected map[string]Severity
+	}{
+		{
+			name:     "downgrade from HTTP/2",
+			previous: &Response{StatusCode: 200, Protocol: "HTTP/2.0"},
+			current:  &Response{StatusCode: 200, Protocol: "HTTP/1.1"},
+			expected: map[string]Severity{"$.protocol": SeverityMedium},
+		},
+		{
+			name:     "upgrade to HTTP/2",
+			previous: &Response{StatusCode: 200, Protocol: "HTTP/1.1"},
+			current:  &Response{StatusCode: 200, Protocol: "HTTP/2.0"},
+			expected: map[string]Severity{"$.protocol": SeverityLow},
+		},
+		{
+			name:     "trailers changed",
+			previous: &Response{StatusCode: 200, Protocol: "HTTP/2.0", Trailers: map[string]string{"Grpc-Status": "0", "X-Checksum": "abc"}},
+			current:  &Response{StatusCode: 200, Protocol: "HTTP/2.0", Trailers: map[string]string{"Grpc-Status": "13", "Server-Timing": "db;dur=5"}},
+			expected: map[string]Severity{
+				"$.trailers.Grpc-Status":   SeverityLow,
+				"$.trailers.X-Checksum":    SeverityMedium,
+				"$.trailers.Server-Timing": SeverityLow,
+			},
+		},
+		{
+			name:     "baseline recorded before protocols were captured",
+			previous: &Response{StatusCode: 200},
+			current:  &Response{StatusCode: 200, Protocol: "HTTP/2.0", Trailers: map[string]string{"Grpc-Status": "0"}},
+			expected: map[string]Severity{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewDiffEngine().CompareResponses(tt.previous, tt.current)
+			require.NoError(t, err)
+
+			severities := map[string]Severity{}
+			for _, change := range result.StructuralChanges {
+				severities[change.Path] = change.Severity
+				assert.False(t, change.Breaking)
+			}
+			for _, change := range result.DataChanges {
+				severities[change.Path] = change.Severity
+			}
+			assert.Equal(t, tt.expected, severities)
+			assert.Equal(t, len(tt.expected) > 0, result.HasChanges)
+		})
+	}
+}
+
+func TestMatchTemplate(t *testing.T) {
+	template := []byte(`{
+		"id": "<uuid>",
+		"status": "active",
+		"count": "<number>",
+		"created_at": "<iso8601>",
+		"tags": ["<string>"]
+	}`)
+
+	tests := []struct {
+		name           string
+		body           string
+		breakingPaths  []string
+		dataPaths      []string
+		nonBreakingNew []string
+	}{
+		{
+			name: "conforming response",
+			body: `{"id": "6f1c2a4e-8b1d-4c3e-9f0a-1b2c3d4e5f60", "status": "active", "count": 3, "created_at": "2024-03-10T12:00:00Z", "tags": ["a", "b"]}`,
+		},
+		{
+			name:          "placeholder mismatch",
+			body:          `{"id": "42", "status": "active", "count": "3", "created_at": "yesterday", "tags": ["a", 1]}`,
+			breakingPaths: []string{"$.count", "$.created_at", "$.id", "$.tags[1]"},
+		},
+		{
+			name:      "concrete value mismatch",
+			body:      `{"id": "6f1c2a4e-8b1d-4c3e-9f0a-1b2c3d4e5f60", "status": "disabled", "count": 3, "created_at": "2024-03-10T12:00:00Z", "tags": []}`,
+			dataPaths: []string{"$.status"},
+		},
+		{
+			name:           "missing and extra fields",
+			body:           `{"id": "6f1c2a4e-8b1d-4c3e-9f0a-1b2c3d4e5f60", "status": "active", "count": 3, "tags": [], "extra": true}`,
+			breakingPaths:  []string{"$.created_at"},
+			nonBreakingNew: []string{"$.extra"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MatchTemplate(template, []byte(tt.body))
+			require.NoError(t, err)
+
+			var breaking, added []string
+			for _, change := range result.StructuralChanges {
+				if change.Breaking {
+					breaking = append(breaking, change.Path)
+				} else {
+					added = append(added, change.Path)
+				}
+			}
+			var data []string
+			for _, change := range result.DataChanges {
+				data = append(data, change.Path)
+			}
+
+			assert.ElementsMatch(t, tt.breakingPaths, breaking)
+			assert.ElementsMatch(t, tt.dataPaths, data)
+			assert.ElementsMatch(t, tt.nonBreakingNew, added)
+			assert.Len(t, result.BreakingChanges, len(tt.breakingPaths))
+			assert.Equal(t, len(breaking)+len(added)+len(data) > 0, result.HasChanges)
+		})
+	}
+
+	_, err := MatchTemplate([]byte("not json"), []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestCompareResponses_StreamingLargeArrays(t *testing.T) {
+	previous := &Response{StatusCode: 200, Body: []byte(`[
+		{"id": 1, "name": "Ada", "tags": ["a"]},
+		{"id": 2, "name": "Grace"},
+		{"id": 3, "name": "Linus"}
+	]`)}
+	current := &Response{StatusCode: 200, Body: []byte(`[
+		{"id": 1, "name": "Ada Lovelace", "tags": ["a", "b"]},
+		{"id": "2", "name": "Grace"}
+	]`)}
+
+	changePaths := func(result *DiffResult) []string {
+		var paths []string
+		for _, change := range result.StructuralChanges {
+			paths = append(paths, string(change.Type)+" "+change.Path)
+		}
+		for _, change := range result.DataChanges {
+			paths = append(paths, string(change.ChangeType)+" "+change.Path)
+		}
+		return paths
+	}
+
+	buffered, err := NewDiffEngineWithOptions(DiffOptions{StreamingThreshold: -1}).CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	engine := NewDiffEngineWithOptions(DiffOptions{StreamingThreshold: 16})
+	require.True(t, engine.(*DefaultDiffEngine).shouldStreamBodies(previous.Body, current.Body))
+
+	streamed, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, changePaths(streamed))
+	assert.ElementsMatch(t, changePaths(buffered), changePaths(streamed))
+	assert.Equal(t, buffered.Summary, streamed.Summary)
+	assert.Len(t, streamed.BreakingChanges, len(buffered.BreakingChanges))
+
+	t.Run("objects are not streamed", func(t *testing.T) {
+		d := engine.(*DefaultDiffEngine)
+		assert.False(t, d.shouldStreamBodies([]byte(`{"items": [1, 2, 3, 4, 5, 6]}`), []byte(`{"items": [1, 2, 3, 4, 5, 6]}`)))
+		assert.False(t, d.shouldStreamBodies([]byte(`[1]`), []byte(`[2]`)))
+	})
+
+	t.Run("required fields are checked element by element", func(t *testing.T) {
+		fields := []string{"[*].tags", "[*].name", "total"}
+		buffered := &DefaultDiffEngine{options: DiffOptions{StreamingThreshold: -1}}
+		streamed := &DefaultDiffEngine{options: DiffOptions{StreamingThreshold: 16}}
+		require.True(t, streamed.shouldStreamBody(current.Body))
+
+		for _, body := range [][]byte{current.Body, []byte(`[{"id": 1}, {"id":`)} {
+			expected := &DiffResult{}
+			buffered.checkRequiredFields(body, fields, expected)
+			actual := &DiffResult{}
+			streamed.checkRequiredFields(body, fields, actual)
+
+			assert.NotEmpty(t, actual.StructuralChanges)
+			assert.Equal(t, expected.StructuralChanges, actual.StructuralChanges)
+		}
+	})
+
+	t.Run("truncated body is an error", func(t *testing.T) {
+		truncated := &Response{StatusCode: 200, Body: []byte(`[{"id": 1}, {"id": 2}, {"id":`)}
+		_, err := engine.CompareResponses(previous, truncated)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompareCertificates(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	warnBefore := 14 * 24 * time.Hour
+
+	cert := func(observedAt time.Time, validFor time.Duration, issuer, fingerprint string) *Certificate {
+		return &Certificate{ObservedAt: observedAt, NotAfter: now.Add(validFor), Issuer: issuer, Fingerprint: fingerprint}
+	}
+
+	tests := []struct {
+		name       string
+		previous   *Certificate
+		current    *Certificate
+		warnBefore time.Duration
+		expected   []ChangeType
+		severity   Severity
+	}{
+		{
+			name:       "plain HTTP",
+			warnBefore: warnBefore,
+		},
+		{
+			name:       "same valid certificate",
+			previous:   cert(now.Add(-time.Hour), 90*24*time.Hour, "CN=CA", "aa"),
+			current:    cert(now, 90*24*time.Hour, "CN=CA", "aa"),
+			warnBefore: warnBefore,
+		},
+		{
+			name:       "certificate enters expiry window",
+			previous:   cert(now.Add(-30*24*time.Hour), 10*24*time.Hour, "CN=CA", "aa"),
+			current:    cert(now, 10*24*time.Hour, "CN=CA", "aa"),
+			warnBefore: warnBefore,
+			expected:   []ChangeType{ChangeTypeTLSExpiring},
+			severity:   SeverityHigh,
+		},
+		{
+			name:       "already expiring is not repeated",
+			previous:   cert(now.Add(-time.Hour), 10*24*time.Hour, "CN=CA", "aa"),
+			current:    cert(now, 10*24*time.Hour, "CN=CA", "aa"),
+			warnBefore: warnBefore,
+		},
+		{
+			name:       "expired certificate",
+			current:    cert(now, -time.Hour, "CN=CA", "aa"),
+			warnBefore: warnBefore,
+			expected:   []ChangeType{ChangeTypeTLSExpiring},
+			severity:   SeverityCritical,
+		},
+		{
+			name:     "expiry check disabled",
+			current:  cert(now, -time.Hour, "CN=CA", "aa"),
+			expected: nil,
+		},
+		{
+			name:       "certificate rotated",
+			previous:   cert(now.Add(-time.Hour), 5*24*time.Hour, "CN=CA", "aa"),
+			current:    cert(now, 90*24*time.Hour, "CN=CA", "bb"),
+			warnBefore: warnBefore,
+			expected:   []ChangeType{ChangeTypeCertificateChange},
+			severity:   SeverityLow,
+		},
+		{
+			name:       "issuer changed",
+			previous:   cert(now.Add(-time.Hour), 90*24*time.Hour, "CN=Old CA", "aa"),
+			current:    cert(now, 90*24*time.Hour, "CN=New CA", "bb"),
+			warnBefore: warnBefore,
+			expected:   []ChangeType{ChangeTypeCertificateChange},
+			severity:   SeverityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CompareCertificates(tt.previous, tt.current, tt.warnBefore)
+
+			var types []ChangeType
+			for _, change := range result.StructuralChanges {
+				types = append(types, change.Type)
+				assert.False(t, change.Breaking)
+			}
+			assert.Equal(t, tt.expected, types)
+			assert.Equal(t, len(tt.expected) > 0, result.HasChanges)
+			if len(tt.expected) > 0 {
+				assert.Equal(t, tt.severity, result.StructuralChanges[0].Severity)
+			}
+		})
+	}
+}
+
+func TestCompareResponses_ComparisonCache(t *testing.T) {
+	cache := NewComparisonCache(2)
+	engine := NewDiffEngineWithOptions(DiffOptions{Cache: cache})
+
+	previous := &Response{StatusCode: 200, Body: []byte(`{"id": 1, "name": "a"}`)}
+	current := &Response{StatusCode: 200, Body: []byte(`{"id": 1, "name": "b"}`)}
+
+	first, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	second, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, CacheMetrics{Hits: 1, Misses: 1, Entries: 1}, cache.GetMetrics())
+
+	t.Run("status and header changes are not cached", func(t *testing.T) {
+		failed := &Response{StatusCode: 500, Headers: map[string]string{"X-Error": "1"}, Body: current.Body}
+		result, err := engine.CompareResponses(previous, failed)
+		require.NoError(t, err)
+
+		paths := []string{}
+		for _, change := range result.StructuralChanges {
+			paths = append(paths, change.Path)
+		}
+		assert.Contains(t, paths, "$.status_code")
+		assert.Contains(t, paths, "$.headers.X-Error")
+		assert.Equal(t, int64(2), cache.GetMetrics().Hits)
+	})
+
+	t.Run("options are part of the key", func(t *testing.T) {
+		ignoring := NewDiffEngineWithOptions(DiffOptions{Cache: cache, IgnoreFields: []string{"name"}})
+		result, err := ignoring.CompareResponses(previous, current)
+		require.NoError(t, err)
+
+		assert.False(t, result.HasChanges)
+		assert.Equal(t, int64(2), cache.GetMetrics().Misses)
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		other := &Response{StatusCode: 200, Body: []byte(`{"id": 2}`)}
+		_, err := engine.CompareResponses(previous, other)
+		require.NoError(t, err)
+
+		metrics := cache.GetMetrics()
+		assert.Equal(t, 2, metrics.Entries)
+		assert.Equal(t, int64(1), metrics.Evictions)
+	})
+
+	t.Run("cached changes are not shared with results", func(t *testing.T) {
+		result, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.DataChanges)
+		result.DataChanges[0].Path = "$.modified"
+
+		again, err := engine.CompareResponses(previous, current)
+		require.NoError(t, err)
+		assert.Equal(t, "$.name", again.DataChanges[0].Path)
+	})
+}
+
+func TestCompareResponses_TruncatedPrefix(t *testing.T) {
+	engine := NewDiffEngine()
+
+	// Event streams cut short at different points, within an event
+	previous := &Response{
+		StatusCode: 200,
+		Body:       []byte("id: 1\ndata: {\"price\": 10, \"sku\": \"a\"}\n\nid: 2\ndata: {\"price\": 12, \"sku\": \"b\"}\n\nid: 3\ndata: {\"pri"),
+		Truncated:  true,
+	}
+	current := &Response{
+		StatusCode: 200,
+		Body:       []byte("id: 7\ndata: {\"price\": 10, \"sku\": \"a\"}\n\nid: 8\ndata: {\"price\": \"12\", \"sku\": \"b\"}\n\n: keep-alive\nid: 9\ndata: {\"price\": 15, \"sku\": \"c\"}\n\nid: 10\nda"),
+		Truncated:  true,
+	}
+
+	result, err := engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	assert.True(t, result.HasChanges)
+
+	// Only the second event changed; the third is missing from the previous prefix
+	var paths []string
+	for _, change := range result.StructuralChanges {
+		paths = append(paths, change.Path)
+	}
+	for _, change := range result.DataChanges {
+		paths = append(paths, change.Path)
+	}
+	assert.Equal(t, []string{"$[1].price"}, paths)
+
+	// Identical records are no change, however much of the stream was read
+	current.Body = []byte("id: 9\ndata: {\"price\": 10, \"sku\": \"a\"}\n")
+	result, err = engine.CompareResponses(previous, current)
+	require.NoError(t, err)
+	assert.False(t, result.HasChanges)
+}
+
+func TestShapeFingerprint(t *testing.T) {
+	base := ShapeFingerprint([]byte(`{"items": [{"id": 1, "name": "a"}], "total": 1}`))
+	assert.Len(t, base, 64)
+
+	// Values, field order and list lengths do not change the shape
+	assert.Equal(t, base, ShapeFingerprint([]byte(`{"total": 3, "items": [{"name": "b", "id": 2}, {"id": 3, "name": "c"}, {"id": 4, "name": "d"}]}`)))
+
+	// Field names and types do
+	assert.NotEqual(t, base, ShapeFingerprint([]byte(`{"items": [{"id": 1, "name": "a"}], "count": 1}`)))
+	assert.NotEqual(t, base, ShapeFingerprint([]byte(`{"items": [{"id": "1", "name": "a"}], "total": 1}`)))
+	assert.NotEqual(t, base, ShapeFingerprint([]byte(`{"items": [], "total": 0}`)))
+	assert.NotEqual(t, base, ShapeFingerprint([]byte(`{"error": "degraded"}`)))
+
+	// Bodies that are not JSON share a shape
+	assert.Equal(t, ShapeFingerprint([]byte("<html>busy</html>")), ShapeFingerprint([]byte("service unavailable")))
+}
+
+func TestCompareResponses_ArraySummaryThreshold(t *testing.T) {
+	previous := &Response{StatusCode: 200, Body: []byte(`{"items": [{"id": 1}, {"id": 2}]}`)}
+	grown := &Response{StatusCode: 200, Body: []byte(`{"items": [{"id": 1}, {"id": 20}, {"id": 3}, {"id": 4}, {"id": 5}]}`)}
+
+	changes := func(result *DiffResult) map[string]string {
+		changes := make(map[string]string)
+		for _, change := range result.StructuralChanges {
+			changes[change.Path] = fmt.Sprint(change.NewValue)
+		}
+		for _, change := range result.DataChanges {
+			changes[change.Path] = fmt.Sprint(change.NewValue)
+		}
+		return changes
+	}
+
+	t.Run("every element without a threshold", func(t *testing.T) {
+		result, err := NewDiffEngine().CompareResponses(previous, grown)
+		require.NoError(t, err)
+		assert.Contains(t, changes(result), "$.items[2]")
+		assert.Contains(t, changes(result), "$.items[4]")
+	})
+
+	t.Run("growth above the threshold is summarized", func(t *testing.T) {
+		engine := NewDiffEngineWithOptions(DiffOptions{ArraySummaryThreshold: 2})
+		result, err := engine.CompareResponses(previous, grown)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"$.items":       "array length: 5 (3 elements added)",
+			"$.items[1].id": "20",
+		}, changes(result), "elements present in both arrays are still compared")
+
+		result, err = engine.CompareResponses(grown, previous)
+		require.NoError(t, err)
+		assert.Equal(t, "array length: 2 (3 elements removed)", changes(result)["$.items"])
+		assert.NotContains(t, changes(result), "$.items[3]")
+	})
+
+	t.Run("small changes keep per-element diffs", func(t *testing.T) {
+		engine := NewDiffEngineWithOptions(DiffOptions{ArraySummaryThreshold: 3})
+		result, err := engine.CompareResponses(previous, grown)
+		require.NoError(t, err)
+		assert.Equal(t, "array length: 5", changes(result)["$.items"])
+		assert.Contains(t, changes(result), "$.items[2]")
+	})
+
+	t.Run("streamed arrays", func(t *testing.T) {
+		previous := &Response{StatusCode: 200, Body: []byte(`[{"id": 1}, {"id": 2}]`)}
+		grown := &Response{StatusCode: 200, Body: []byte(`[{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}]`)}
+
+		engine := NewDiffEngineWithOptions(DiffOptions{ArraySummaryThreshold: 2, StreamingThreshold: 16})
+		require.True(t, engine.(*DefaultDiffEngine).shouldStreamBodies(previous.Body, grown.Body))
+
+		result, err := engine.CompareResponses(previous, grown)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"$": "array length: 5 (3 elements added)"}, changes(result))
+	})
+}
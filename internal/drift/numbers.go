@@ -0,0 +1,114 @@
+package drift
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeJSON decodes a JSON document keeping numbers as json.Number, so that
+// large integers and precise decimals are compared exactly rather than as
+// rounded float64 values
+func decodeJSON(data []byte, v *interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+// valuesEqual reports whether two decoded scalar values are equal. Numbers are
+// equal when they denote the same decimal value, such as 1.50 and 1.5.
+func valuesEqual(a, b interface{}) bool {
+	if aNumber, ok := a.(json.Number); ok {
+		if bNumber, ok := b.(json.Number); ok {
+			return canonicalNumber(aNumber) == canonicalNumber(bNumber)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// canonicalNumber returns a single representation for every spelling of a
+// decimal number: without a leading plus, leading zeros or trailing fraction
+// zeros. Numbers with an exponent are rare in responses and are compared as
+// float64, as expanding the exponent exactly could take unbounded memory.
+func canonicalNumber(number json.Number) string {
+	s := string(number)
+	if strings.ContainsAny(s, "eE") {
+		value, err := number.Float64()
+		if err != nil {
+			return s
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	integer, fraction, _ := strings.Cut(strings.TrimLeft(s, "+-"), ".")
+
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	fraction = strings.TrimRight(fraction, "0")
+
+	canonical := integer
+	if fraction != "" {
+		canonical += "." + fraction
+	}
+	if negative && canonical != "0" {
+		canonical = "-" + canonical
+	}
+	return canonical
+}
+
+// withinTolerance reports whether two numeric values differ by no more than the
+// configured numeric tolerance, relative to the larger of their magnitudes
+func (d *DefaultDiffEngine) withinTolerance(a, b interface{}) bool {
+	if d.options.NumericTolerance <= 0 {
+		return false
+	}
+
+	aValue, ok := toFloat64(a)
+	if !ok {
+		return false
+	}
+	bValue, ok := toFloat64(b)
+	if !ok {
+		return false
+	}
+
+	return math.Abs(aValue-bValue) <= d.options.NumericTolerance*math.Max(math.Abs(aValue), math.Abs(bValue))
+}
+
+// canonicalNumbers returns a copy of a decoded value with every number in its
+// canonical representation, so that equal values marshal identically
+func canonicalNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return json.Number(canonicalNumber(v))
+	case map[string]interface{}:
+		canonical := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			canonical[key] = canonicalNumbers(item)
+		}
+		return canonical
+	case []interface{}:
+		canonical := make([]interface{}, len(v))
+		for i, item := range v {
+			canonical[i] = canonicalNumbers(item)
+		}
+		return canonical
+	default:
+		return value
+	}
+}
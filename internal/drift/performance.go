@@ -0,0 +1,155 @@
+package drift
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Performance modes, selecting how response times are compared
+const (
+	// PerformanceModeDelta compares the response time with the previous
+	// response's; this is the default
+	PerformanceModeDelta = "delta"
+	// PerformanceModeThreshold reports responses slower than ResponseTimeThreshold
+	PerformanceModeThreshold = "threshold"
+	// PerformanceModeZScore reports responses slower than the mean of
+	// ResponseTimeHistory by more than ZScoreThreshold standard deviations
+	PerformanceModeZScore = "zscore"
+)
+
+// DefaultZScoreThreshold is the number of standard deviations above the mean
+// from which the zscore mode reports a response time
+const DefaultZScoreThreshold = 3.0
+
+// minZScoreSamples is the number of past response times the zscore mode needs
+// before the mean and standard deviation are meaningful
+const minZScoreSamples = 5
+
+// minPerformanceDelta is the smallest slowdown reported, below which response
+// times are considered noise
+const minPerformanceDelta = 100 * time.Millisecond
+
+// Timing is the breakdown of a response time into request phases. Phases that
+// did not take place, such as DNS on a reused connection, are zero.
+type Timing struct {
+	DNS     time.Duration `json:"dns"`
+	Connect time.Duration `json:"connect"`
+	TLS     time.Duration `json:"tls"`
+	TTFB    time.Duration `json:"ttfb"`
+}
+
+// timingPhases lists the phases of a timing breakdown in request order, with
+// the labels used in descriptions
+var timingPhases = []struct {
+	name  string
+	label string
+	value func(*Timing) time.Duration
+}{
+	{"dns", "DNS lookup", func(t *Timing) time.Duration { return t.DNS }},
+	{"connect", "TCP connect", func(t *Timing) time.Duration { return t.Connect }},
+	{"tls", "TLS handshake", func(t *Timing) time.Duration { return t.TLS }},
+	{"ttfb", "server processing (TTFB)", func(t *Timing) time.Duration { return t.TTFB }},
+}
+
+// attributeSlowdown names the request phase that grew the most between two
+// responses on a reported slowdown, so that a regression can be pinpointed to
+// the network, the TLS setup or the server. Nothing is attributed when either
+// response has no timing breakdown or no phase grew.
+func attributeSlowdown(previous, current *Response, change *PerformanceChange) {
+	if change == nil || change.ResponseTimeDelta <= 0 || previous.Timing == nil || current.Timing == nil {
+		return
+	}
+
+	var slowest string
+	var slowestLabel string
+	var slowestDelta time.Duration
+	for _, phase := range timingPhases {
+		delta := phase.value(current.Timing) - phase.value(previous.Timing)
+		if delta > slowestDelta {
+			slowest, slowestLabel, slowestDelta = phase.name, phase.label, delta
+		}
+	}
+	if slowest == "" {
+		return
+	}
+
+	change.Phase = slowest
+	change.PhaseDelta = slowestDelta
+	change.Description += fmt.Sprintf(", mostly in the %s (+%v)", slowestLabel, slowestDelta.Round(time.Millisecond))
+}
+
+// comparePerformanceThreshold reports a response slower than the configured
+// threshold, whatever the previous response time
+func (d *DefaultDiffEngine) comparePerformanceThreshold(current *Response, result *DiffResult) {
+	threshold := d.options.ResponseTimeThreshold
+	if threshold <= 0 || current.ResponseTime <= threshold {
+		return
+	}
+
+	delta := current.ResponseTime - threshold
+	result.HasChanges = true
+	result.PerformanceChanges = &PerformanceChange{
+		ResponseTimeDelta: delta,
+		Severity:          d.assessPerformanceSeverity(delta, threshold),
+		Description: fmt.Sprintf("Response time of %v exceeds the threshold of %v by %v",
+			current.ResponseTime, threshold, delta),
+	}
+}
+
+// comparePerformanceZScore reports a response time that is anomalously slow
+// compared with the recent response times of the endpoint. Nothing is reported
+// until enough history is available.
+func (d *DefaultDiffEngine) comparePerformanceZScore(current *Response, result *DiffResult) {
+	history := d.options.ResponseTimeHistory
+	if current.ResponseTime == 0 || len(history) < minZScoreSamples {
+		return
+	}
+
+	mean, stddev := responseTimeStats(history)
+	delta := current.ResponseTime - mean
+	if delta < minPerformanceDelta {
+		return
+	}
+
+	threshold := d.options.ZScoreThreshold
+	if threshold <= 0 {
+		threshold = DefaultZScoreThreshold
+	}
+
+	description := fmt.Sprintf("Response time of %v is %v above the constant response time of the last %d runs",
+		current.ResponseTime, delta, len(history))
+	if stddev > 0 {
+		zScore := float64(delta) / stddev
+		if zScore <= threshold {
+			return
+		}
+		description = fmt.Sprintf("Response time of %v is %.1f standard deviations above the mean of %v over the last %d runs",
+			current.ResponseTime, zScore, mean, len(history))
+	}
+
+	result.HasChanges = true
+	result.PerformanceChanges = &PerformanceChange{
+		ResponseTimeDelta: delta,
+		Severity:          d.assessPerformanceSeverity(delta, mean),
+		Description:       description,
+	}
+}
+
+// responseTimeStats returns the mean and the population standard deviation, in
+// nanoseconds, of response times
+func responseTimeStats(times []time.Duration) (time.Duration, float64) {
+	var sum float64
+	for _, t := range times {
+		sum += float64(t)
+	}
+	mean := sum / float64(len(times))
+
+	var variance float64
+	for _, t := range times {
+		variance += (float64(t) - mean) * (float64(t) - mean)
+	}
+	variance /= float64(len(times))
+
+	return time.Duration(mean), math.Sqrt(variance)
+}
@@ -0,0 +1,84 @@
+package drift
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// sseMetadataFields are the Server-Sent Events fields that describe an event
+// rather than carry its data; they are left out of prefix comparisons, as event
+// IDs in particular differ on every request
+var sseMetadataFields = [][]byte{[]byte("event:"), []byte("id:"), []byte("retry:")}
+
+// compareBodyPrefixes compares response bodies of which at least one was cut
+// short by a stream read limit. Such a prefix generally ends within a record, so
+// the bodies are compared record by record: each line of newline-delimited JSON,
+// or each data line of a Server-Sent Events stream, with the records at the same
+// position compared as JSON when both are valid JSON and as text otherwise. Only
+// the positions both prefixes reach are compared, as how many records fit within
+// the limit says nothing about the response.
+func (d *DefaultDiffEngine) compareBodyPrefixes(previous, current *Response, result *DiffResult) {
+	previousRecords := streamRecords(previous.Body, previous.Truncated)
+	currentRecords := streamRecords(current.Body, current.Truncated)
+
+	diffs := []FieldDiff{}
+	for i := 0; i < len(previousRecords) && i < len(currentRecords); i++ {
+		path := fmt.Sprintf("$[%d]", i)
+
+		var previousData, currentData interface{}
+		if decodeJSON(previousRecords[i], &previousData) == nil && decodeJSON(currentRecords[i], &currentData) == nil {
+			d.compareValues(previousData, currentData, path, &diffs)
+			continue
+		}
+
+		if !bytes.Equal(previousRecords[i], currentRecords[i]) {
+			diffs = append(diffs, FieldDiff{
+				Path:     path,
+				Type:     DiffTypeModified,
+				OldValue: string(previousRecords[i]),
+				NewValue: string(currentRecords[i]),
+			})
+		}
+	}
+
+	d.recordFieldDiffs(diffs, result)
+}
+
+// streamRecords splits a streamed body into its records: its non-blank lines,
+// with the "data:" prefix of Server-Sent Events removed and their other fields
+// and comments skipped. The last line of a truncated body is dropped, as it may
+// have been cut short.
+func streamRecords(body []byte, truncated bool) [][]byte {
+	if truncated {
+		end := bytes.LastIndexByte(body, '\n')
+		if end < 0 {
+			return nil
+		}
+		body = body[:end]
+	}
+
+	var records [][]byte
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == ':' || hasAnyPrefix(line, sseMetadataFields) {
+			continue
+		}
+
+		if data, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			line = bytes.TrimSpace(data)
+		}
+		records = append(records, line)
+	}
+
+	return records
+}
+
+// hasAnyPrefix reports whether b begins with any of the prefixes
+func hasAnyPrefix(b []byte, prefixes [][]byte) bool {
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(b, prefix) {
+			return true
+		}
+	}
+	return false
+}
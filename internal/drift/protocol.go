@@ -0,0 +1,109 @@
+package drift
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ChangeTypeProtocolChange is reported when the negotiated HTTP version changes
+const ChangeTypeProtocolChange ChangeType = "protocol_change"
+
+// ChangeTypeTrailerChange is reported for trailers sent after the response body
+const ChangeTypeTrailerChange ChangeType = "trailer_change"
+
+// compareProtocols reports a change of the negotiated HTTP version. A downgrade,
+// such as a service no longer offering HTTP/2, is of medium severity. Responses
+// recorded before the version was captured have none and are not compared.
+func (d *DefaultDiffEngine) compareProtocols(previous, current *Response, result *DiffResult) {
+	if previous.Protocol == "" || current.Protocol == "" || previous.Protocol == current.Protocol {
+		return
+	}
+
+	change := StructuralChange{
+		Type:        ChangeTypeProtocolChange,
+		Path:        "$.protocol",
+		Description: fmt.Sprintf("HTTP protocol changed from %s to %s", previous.Protocol, current.Protocol),
+		OldValue:    previous.Protocol,
+		NewValue:    current.Protocol,
+		Severity:    SeverityLow,
+	}
+	if protocolRank(current.Protocol) < protocolRank(previous.Protocol) {
+		change.Description = fmt.Sprintf("HTTP protocol downgraded from %s to %s", previous.Protocol, current.Protocol)
+		change.Severity = SeverityMedium
+	}
+
+	d.recordStructuralChange(result, change, "")
+}
+
+// protocolRank orders HTTP versions such as "HTTP/1.1" and "HTTP/2.0"; unknown
+// versions rank lowest
+func protocolRank(protocol string) int {
+	major, minor, ok := http.ParseHTTPVersion(protocol)
+	if !ok {
+		return 0
+	}
+	return major*10 + minor
+}
+
+// compareTrailers reports trailers that are no longer sent, newly sent, or sent
+// with a different value. Trailers are only compared when the previous response
+// recorded its protocol, as responses recorded before then kept no trailers.
+func (d *DefaultDiffEngine) compareTrailers(previous, current *Response, result *DiffResult) {
+	if previous.Protocol == "" || current.Protocol == "" {
+		return
+	}
+
+	for _, name := range sortedHeaderNames(previous.Trailers) {
+		oldValue := previous.Trailers[name]
+		path := fmt.Sprintf("$.trailers.%s", name)
+
+		newValue, exists := current.Trailers[name]
+		if !exists {
+			d.recordStructuralChange(result, StructuralChange{
+				Type:        ChangeTypeTrailerChange,
+				Path:        path,
+				Description: fmt.Sprintf("Trailer '%s' is no longer sent", name),
+				OldValue:    oldValue,
+				Severity:    SeverityMedium,
+			}, "")
+			continue
+		}
+
+		if oldValue != newValue {
+			result.HasChanges = true
+			result.DataChanges = append(result.DataChanges, DataChange{
+				Path:        path,
+				OldValue:    oldValue,
+				NewValue:    newValue,
+				ChangeType:  ChangeTypeTrailerChange,
+				Severity:    SeverityLow,
+				Description: fmt.Sprintf("Trailer '%s' value changed from '%s' to '%s'", name, oldValue, newValue),
+			})
+		}
+	}
+
+	for _, name := range sortedHeaderNames(current.Trailers) {
+		if _, exists := previous.Trailers[name]; exists {
+			continue
+		}
+
+		d.recordStructuralChange(result, StructuralChange{
+			Type:        ChangeTypeTrailerChange,
+			Path:        fmt.Sprintf("$.trailers.%s", name),
+			Description: fmt.Sprintf("Trailer '%s' is now sent", name),
+			NewValue:    current.Trailers[name],
+			Severity:    SeverityLow,
+		}, "")
+	}
+}
+
+// sortedHeaderNames returns the names of recorded headers in a stable order
+func sortedHeaderNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
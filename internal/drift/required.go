@@ -0,0 +1,209 @@
+package drift
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChangeTypeRequiredFieldMissing is reported when a field that must be present
+// in every response is absent or null
+const ChangeTypeRequiredFieldMissing ChangeType = "required_field_missing"
+
+// wildcardSegment selects every element of an array in a required field path
+const wildcardSegment = "[*]"
+
+// CheckRequiredFields reports every field of fields that is absent or null in
+// a response body as a high severity required_field_missing change. Paths such
+// as "items[*].id" require the field in every element of the array. A body that
+// is not JSON is missing every field.
+func CheckRequiredFields(body []byte, fields []string) *DiffResult {
+	result := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+
+	engine := &DefaultDiffEngine{}
+	engine.checkRequiredFields(body, fields, result)
+	engine.generateSummary(result)
+	result.HasChanges = result.Summary.TotalChanges > 0
+
+	return result
+}
+
+// checkRequiredFields adds a change to result for each missing required field.
+// Large top-level arrays are checked one element at a time, as they are compared.
+func (d *DefaultDiffEngine) checkRequiredFields(body []byte, fields []string, result *DiffResult) {
+	var paths []string
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			paths = append(paths, normalizeFieldPath(field))
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	var missing [][]string
+	if d.shouldStreamBody(body) {
+		missing = findMissingStreamedFields(body, paths)
+	} else {
+		missing = findMissingBodyFields(body, paths)
+	}
+
+	for _, fieldMissing := range missing {
+		for _, missingPath := range fieldMissing {
+			d.recordStructuralChange(result, StructuralChange{
+				Type:        ChangeTypeRequiredFieldMissing,
+				Path:        missingPath,
+				Description: fmt.Sprintf("Required field '%s' is missing or null", missingPath),
+				Severity:    SeverityHigh,
+				Breaking:    true,
+			}, fmt.Sprintf("Restore field '%s', which clients require", missingPath))
+		}
+	}
+}
+
+// findMissingBodyFields returns, for each required path, the paths at which it
+// is missing from a body. A body that is not JSON is missing every path.
+func findMissingBodyFields(body []byte, paths []string) [][]string {
+	var data interface{}
+	parsed := json.Unmarshal(body, &data) == nil
+
+	missing := make([][]string, len(paths))
+	for i, path := range paths {
+		if !parsed {
+			missing[i] = []string{path}
+			continue
+		}
+		missing[i] = findMissingFields(data, "$", requiredPathSegments(path))
+	}
+	return missing
+}
+
+// findMissingStreamedFields returns the same paths as findMissingBodyFields for
+// a top-level array body, decoding only a single element at a time
+func findMissingStreamedFields(body []byte, paths []string) [][]string {
+	segments := make([][]string, len(paths))
+	for i, path := range paths {
+		segments[i] = requiredPathSegments(path)
+	}
+
+	missing := make([][]string, len(paths))
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := expectArrayStart(decoder); err != nil {
+		return missingEverywhere(paths)
+	}
+	for index := 0; decoder.More(); index++ {
+		var element interface{}
+		if err := decoder.Decode(&element); err != nil {
+			return missingEverywhere(paths)
+		}
+
+		elementPath := fmt.Sprintf("$[%d]", index)
+		for i, fieldSegments := range segments {
+			if len(fieldSegments) > 0 && fieldSegments[0] == wildcardSegment {
+				missing[i] = append(missing[i], findMissingFields(element, elementPath, fieldSegments[1:])...)
+			}
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return missingEverywhere(paths)
+	}
+
+	// Paths not starting with [*] do not lead into the array, which is itself
+	// present at $
+	for i, fieldSegments := range segments {
+		if len(fieldSegments) > 0 && fieldSegments[0] != wildcardSegment {
+			missing[i] = []string{joinPathSegments("$", fieldSegments)}
+		}
+	}
+	return missing
+}
+
+// missingEverywhere reports every path as missing, as from a body that is not JSON
+func missingEverywhere(paths []string) [][]string {
+	missing := make([][]string, len(paths))
+	for i, path := range paths {
+		missing[i] = []string{path}
+	}
+	return missing
+}
+
+// requiredPathSegments splits a normalized field path into field names and
+// wildcard segments
+func requiredPathSegments(path string) []string {
+	var segments []string
+	rest := strings.TrimPrefix(path, "$")
+	for rest != "" {
+		if strings.HasPrefix(rest, wildcardSegment) {
+			segments = append(segments, wildcardSegment)
+			rest = rest[len(wildcardSegment):]
+			continue
+		}
+
+		rest = strings.TrimPrefix(rest, ".")
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end > 0 {
+			segments = append(segments, rest[:end])
+		}
+		rest = rest[end:]
+	}
+	return segments
+}
+
+// findMissingFields returns the paths below value, located at path, at which
+// the remaining segments do not lead to a non-null value
+func findMissingFields(value interface{}, path string, segments []string) []string {
+	if len(segments) == 0 {
+		if value == nil {
+			return []string{path}
+		}
+		return nil
+	}
+
+	segment := segments[0]
+	if segment == wildcardSegment {
+		elements, ok := value.([]interface{})
+		if !ok {
+			return []string{joinPathSegments(path, segments)}
+		}
+
+		var missing []string
+		for i, element := range elements {
+			missing = append(missing, findMissingFields(element, fmt.Sprintf("%s[%d]", path, i), segments[1:])...)
+		}
+		return missing
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{joinPathSegments(path, segments)}
+	}
+
+	child, exists := object[segment]
+	if !exists {
+		return []string{joinPathSegments(path, segments)}
+	}
+
+	return findMissingFields(child, path+"."+segment, segments[1:])
+}
+
+// joinPathSegments appends segments to a path
+func joinPathSegments(path string, segments []string) string {
+	var builder strings.Builder
+	builder.WriteString(path)
+	for _, segment := range segments {
+		if segment != wildcardSegment {
+			builder.WriteString(".")
+		}
+		builder.WriteString(segment)
+	}
+	return builder.String()
+}
@@ -0,0 +1,65 @@
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// nonJSONShape is the shape of every body that is not JSON
+const nonJSONShape = "non-json"
+
+// ShapeFingerprint returns a fingerprint of the structure of a response body:
+// its field names and JSON types, without its values. Bodies that differ only
+// in their values share a fingerprint, and the elements of an array count once
+// per distinct shape, so lists of any length share one as well. Bodies that
+// are not JSON all share a fingerprint.
+func ShapeFingerprint(body []byte) string {
+	var data interface{}
+	shape := nonJSONShape
+	if json.Unmarshal(body, &data) == nil {
+		shape = valueShape(data)
+	}
+
+	sum := sha256.Sum256([]byte(shape))
+	return hex.EncodeToString(sum[:])
+}
+
+// valueShape renders the structure of a decoded JSON value canonically
+func valueShape(v interface{}) string {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		fields := make([]string, 0, len(value))
+		for key, field := range value {
+			fields = append(fields, jsonQuote(key)+":"+valueShape(field))
+		}
+		sort.Strings(fields)
+		return "{" + strings.Join(fields, ",") + "}"
+	case []interface{}:
+		seen := make(map[string]bool)
+		var elements []string
+		for _, element := range value {
+			shape := valueShape(element)
+			if !seen[shape] {
+				seen[shape] = true
+				elements = append(elements, shape)
+			}
+		}
+		sort.Strings(elements)
+		return "[" + strings.Join(elements, "|") + "]"
+	default:
+		return jsonTypeName(value)
+	}
+}
+
+// jsonQuote quotes a field name, so that names holding separators cannot
+// collide with the shape around them
+func jsonQuote(name string) string {
+	quoted, err := json.Marshal(name)
+	if err != nil {
+		return name
+	}
+	return string(quoted)
+}
@@ -0,0 +1,243 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Template placeholders match any value of the given kind
+const (
+	PlaceholderString  = "<string>"
+	PlaceholderNumber  = "<number>"
+	PlaceholderUUID    = "<uuid>"
+	PlaceholderISO8601 = "<iso8601>"
+)
+
+// placeholderMatchers checks whether a value conforms to a placeholder
+var placeholderMatchers = map[string]func(interface{}) bool{
+	PlaceholderString: func(v interface{}) bool {
+		_, ok := v.(string)
+		return ok
+	},
+	PlaceholderNumber: func(v interface{}) bool {
+		_, ok := v.(float64)
+		return ok
+	},
+	PlaceholderUUID: func(v interface{}) bool {
+		s, ok := v.(string)
+		return ok && uuidPattern.MatchString(s)
+	},
+	PlaceholderISO8601: func(v interface{}) bool {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	},
+}
+
+// MatchTemplate compares a response body against a template. The template is a
+// JSON document in which string values of the form <string>, <number>, <uuid>
+// and <iso8601> match any value of that kind; every other value must match
+// exactly. A template array with a single element is matched against every
+// element of the response array.
+//
+// Missing fields and non-conforming placeholder values are reported as breaking
+// structural changes, concrete value mismatches as data changes, and fields that
+// are not in the template as non-breaking additions.
+func MatchTemplate(template, body []byte) (*DiffResult, error) {
+	var expected, actual interface{}
+	if err := json.Unmarshal(template, &expected); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := json.Unmarshal(body, &actual); err != nil {
+		return nil, fmt.Errorf("failed to parse response body: %w", err)
+	}
+
+	result := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+
+	matchTemplateValue("$", expected, actual, result)
+
+	for _, change := range result.StructuralChanges {
+		if change.Breaking {
+			result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+				Type:        change.Type,
+				Path:        change.Path,
+				Description: change.Description,
+				Impact:      ImpactLevelMajor,
+			})
+		}
+	}
+
+	engine := &DefaultDiffEngine{}
+	engine.generateSummary(result)
+	result.HasChanges = result.Summary.TotalChanges > 0
+
+	return result, nil
+}
+
+// matchTemplateValue matches a single template value against the actual value at path
+func matchTemplateValue(path string, expected, actual interface{}, result *DiffResult) {
+	if placeholder, ok := expected.(string); ok {
+		if matches, isPlaceholder := placeholderMatchers[placeholder]; isPlaceholder {
+			if !matches(actual) {
+				result.StructuralChanges = append(result.StructuralChanges, StructuralChange{
+					Type:        ChangeTypeTypeChange,
+					Path:        path,
+					Description: fmt.Sprintf("value does not match placeholder %s", placeholder),
+					OldValue:    placeholder,
+					NewValue:    actual,
+					Severity:    SeverityHigh,
+					Breaking:    true,
+				})
+			}
+			return
+		}
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			addTemplateTypeMismatch(path, expected, actual, result)
+			return
+		}
+		matchTemplateObject(path, exp, act, result)
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			addTemplateTypeMismatch(path, expected, actual, result)
+			return
+		}
+		matchTemplateArray(path, exp, act, result)
+
+	default:
+		if jsonTypeName(expected) != jsonTypeName(actual) {
+			addTemplateTypeMismatch(path, expected, actual, result)
+			return
+		}
+		if expected != actual {
+			result.DataChanges = append(result.DataChanges, DataChange{
+				Path:        path,
+				OldValue:    expected,
+				NewValue:    actual,
+				ChangeType:  ChangeTypeValueChange,
+				Severity:    SeverityMedium,
+				Description: fmt.Sprintf("value differs from template: expected %v, got %v", expected, actual),
+			})
+		}
+	}
+}
+
+// matchTemplateObject matches template object fields in a stable order
+func matchTemplateObject(path string, expected, actual map[string]interface{}, result *DiffResult) {
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := fmt.Sprintf("%s.%s", path, key)
+		value, exists := actual[key]
+		if !exists {
+			result.StructuralChanges = append(result.StructuralChanges, StructuralChange{
+				Type:        ChangeTypeFieldRemoved,
+				Path:        fieldPath,
+				Description: "field required by template is missing",
+				OldValue:    expected[key],
+				Severity:    SeverityHigh,
+				Breaking:    true,
+			})
+			continue
+		}
+		matchTemplateValue(fieldPath, expected[key], value, result)
+	}
+
+	extra := make([]string, 0)
+	for key := range actual {
+		if _, inTemplate := expected[key]; !inTemplate {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+
+	for _, key := range extra {
+		result.StructuralChanges = append(result.StructuralChanges, StructuralChange{
+			Type:        ChangeTypeFieldAdded,
+			Path:        fmt.Sprintf("%s.%s", path, key),
+			Description: "field is not in template",
+			NewValue:    actual[key],
+			Severity:    SeverityLow,
+			Breaking:    false,
+		})
+	}
+}
+
+// matchTemplateArray matches a template array. A single-element template applies
+// to every element; otherwise elements are matched by index.
+func matchTemplateArray(path string, expected, actual []interface{}, result *DiffResult) {
+	if len(expected) == 1 {
+		for i, item := range actual {
+			matchTemplateValue(fmt.Sprintf("%s[%d]", path, i), expected[0], item, result)
+		}
+		return
+	}
+
+	if len(expected) != len(actual) {
+		result.DataChanges = append(result.DataChanges, DataChange{
+			Path:        path,
+			OldValue:    len(expected),
+			NewValue:    len(actual),
+			ChangeType:  ChangeTypeArrayChange,
+			Severity:    SeverityMedium,
+			Description: fmt.Sprintf("array length differs from template: expected %d, got %d", len(expected), len(actual)),
+		})
+	}
+
+	for i := 0; i < len(expected) && i < len(actual); i++ {
+		matchTemplateValue(fmt.Sprintf("%s[%d]", path, i), expected[i], actual[i], result)
+	}
+}
+
+// addTemplateTypeMismatch records a value whose JSON type differs from the template
+func addTemplateTypeMismatch(path string, expected, actual interface{}, result *DiffResult) {
+	result.StructuralChanges = append(result.StructuralChanges, StructuralChange{
+		Type:        ChangeTypeTypeChange,
+		Path:        path,
+		Description: fmt.Sprintf("type differs from template: expected %s, got %s", jsonTypeName(expected), jsonTypeName(actual)),
+		OldValue:    expected,
+		NewValue:    actual,
+		Severity:    SeverityHigh,
+		Breaking:    true,
+	})
+}
+
+// jsonTypeName returns the JSON type name of a decoded value
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
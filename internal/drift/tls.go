@@ -0,0 +1,116 @@
+package drift
+
+import (
+	"fmt"
+	"time"
+)
+
+// Change types for TLS certificate drift
+const (
+	ChangeTypeTLSExpiring       ChangeType = "tls_expiring"
+	ChangeTypeCertificateChange ChangeType = "certificate_change"
+)
+
+// Certificate is a TLS server certificate observed during a check
+type Certificate struct {
+	ObservedAt  time.Time
+	NotAfter    time.Time
+	Issuer      string
+	Fingerprint string
+}
+
+// remaining returns the certificate's validity left at the time it was observed
+func (c *Certificate) remaining() time.Duration {
+	return c.NotAfter.Sub(c.ObservedAt)
+}
+
+// CompareCertificates reports changes between the certificate seen by the
+// previous check and the current one. A new fingerprint is reported as a
+// certificate_change; the change is high severity if the issuer changed too.
+// A tls_expiring change is reported when the current certificate expires
+// within warnBefore, once per certificate: it is not repeated while the same
+// certificate was already expiring at the previous check. A non-positive
+// warnBefore disables the expiry check. Either certificate may be nil.
+func CompareCertificates(previous, current *Certificate, warnBefore time.Duration) *DiffResult {
+	result := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+
+	if current != nil {
+		sameCertificate := previous != nil && previous.Fingerprint == current.Fingerprint
+
+		if previous != nil && !sameCertificate {
+			result.StructuralChanges = append(result.StructuralChanges, certificateChange(previous, current))
+		}
+
+		alreadyExpiring := sameCertificate && previous.remaining() < warnBefore
+		if warnBefore > 0 && current.remaining() < warnBefore && !alreadyExpiring {
+			result.StructuralChanges = append(result.StructuralChanges, expiryChange(current))
+		}
+	}
+
+	engine := &DefaultDiffEngine{}
+	engine.generateSummary(result)
+	result.HasChanges = result.Summary.TotalChanges > 0
+
+	return result
+}
+
+// certificateChange describes a replaced certificate
+func certificateChange(previous, current *Certificate) StructuralChange {
+	if previous.Issuer != current.Issuer {
+		return StructuralChange{
+			Type:        ChangeTypeCertificateChange,
+			Path:        "$.tls.issuer",
+			Description: fmt.Sprintf("TLS certificate issuer changed from %q to %q", previous.Issuer, current.Issuer),
+			OldValue:    previous.Issuer,
+			NewValue:    current.Issuer,
+			Severity:    SeverityHigh,
+		}
+	}
+
+	return StructuralChange{
+		Type:        ChangeTypeCertificateChange,
+		Path:        "$.tls.fingerprint",
+		Description: fmt.Sprintf("TLS certificate was replaced (expires %s)", current.NotAfter.Format(time.RFC3339)),
+		OldValue:    previous.Fingerprint,
+		NewValue:    current.Fingerprint,
+		Severity:    SeverityLow,
+	}
+}
+
+// expiryChange describes a certificate that expires soon or has expired
+func expiryChange(current *Certificate) StructuralChange {
+	remaining := current.remaining()
+	change := StructuralChange{
+		Type:     ChangeTypeTLSExpiring,
+		Path:     "$.tls.not_after",
+		NewValue: current.NotAfter.Format(time.RFC3339),
+		Severity: SeverityHigh,
+	}
+
+	if remaining <= 0 {
+		change.Description = fmt.Sprintf("TLS certificate expired at %s", current.NotAfter.Format(time.RFC3339))
+		change.Severity = SeverityCritical
+		return change
+	}
+
+	change.Description = fmt.Sprintf("TLS certificate expires in %s (%s)",
+		formatValidity(remaining), current.NotAfter.Format(time.RFC3339))
+	return change
+}
+
+// formatValidity renders a remaining validity in days, or hours below one day
+func formatValidity(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
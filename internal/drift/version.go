@@ -0,0 +1,125 @@
+package drift
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeTypeVersionChange is reported when the field holding the contract
+// version of a response, such as "$.schema_version", changes value
+const ChangeTypeVersionChange ChangeType = "version_change"
+
+// CompareVersionFields reports a change of the version field at field between
+// two response bodies as a version_change of the given severity, low if empty.
+// Nothing is reported when either body has no version at field, as when the
+// field is introduced or a body is not JSON.
+func CompareVersionFields(previousBody, currentBody []byte, field string, severity Severity) *DiffResult {
+	result := &DiffResult{
+		StructuralChanges: []StructuralChange{},
+		DataChanges:       []DataChange{},
+		BreakingChanges:   []BreakingChange{},
+		Summary:           &DiffSummary{},
+	}
+
+	engine := &DefaultDiffEngine{options: DiffOptions{VersionField: field, VersionChangeSeverity: severity}}
+	engine.compareVersionFields(previousBody, currentBody, result)
+	engine.generateSummary(result)
+	result.HasChanges = result.Summary.TotalChanges > 0
+
+	return result
+}
+
+// compareVersionFields replaces the changes reported at the configured version
+// field with a single version_change. A version bump is a deliberate change of
+// contract, so it is reported at the configured severity and never as breaking.
+func (d *DefaultDiffEngine) compareVersionFields(previousBody, currentBody []byte, result *DiffResult) {
+	field := strings.TrimSpace(d.options.VersionField)
+	if field == "" {
+		return
+	}
+	path := normalizeFieldPath(field)
+
+	d.removeChangesAt(path, result)
+
+	previous, ok := versionFieldValue(previousBody, path)
+	if !ok {
+		return
+	}
+	current, ok := versionFieldValue(currentBody, path)
+	if !ok || valuesEqual(previous, current) {
+		return
+	}
+
+	severity := d.options.VersionChangeSeverity
+	if severity == "" {
+		severity = SeverityLow
+	}
+
+	d.recordStructuralChange(result, StructuralChange{
+		Type:        ChangeTypeVersionChange,
+		Path:        path,
+		Description: fmt.Sprintf("API version changed from %v to %v", previous, current),
+		OldValue:    previous,
+		NewValue:    current,
+		Severity:    severity,
+	}, "")
+}
+
+// removeChangesAt drops the structural, data and breaking changes reported at path
+func (d *DefaultDiffEngine) removeChangesAt(path string, result *DiffResult) {
+	structuralChanges := result.StructuralChanges[:0]
+	for _, change := range result.StructuralChanges {
+		if normalizeFieldPath(change.Path) != path {
+			structuralChanges = append(structuralChanges, change)
+		}
+	}
+	result.StructuralChanges = structuralChanges
+
+	dataChanges := result.DataChanges[:0]
+	for _, change := range result.DataChanges {
+		if normalizeFieldPath(change.Path) != path {
+			dataChanges = append(dataChanges, change)
+		}
+	}
+	result.DataChanges = dataChanges
+
+	breakingChanges := result.BreakingChanges[:0]
+	for _, change := range result.BreakingChanges {
+		if normalizeFieldPath(change.Path) != path {
+			breakingChanges = append(breakingChanges, change)
+		}
+	}
+	result.BreakingChanges = breakingChanges
+
+	result.HasChanges = len(result.StructuralChanges) > 0 || len(result.DataChanges) > 0
+}
+
+// versionFieldValue returns the non-null scalar at a normalized field path of a
+// JSON body. Paths through arrays select no single value and are not found, so
+// array bodies, which may be too large to decode at once, are not decoded.
+func versionFieldValue(body []byte, path string) (interface{}, bool) {
+	if isJSONArray(body) {
+		return nil, false
+	}
+
+	var value interface{}
+	if err := decodeJSON(body, &value); err != nil {
+		return nil, false
+	}
+
+	for _, segment := range requiredPathSegments(path) {
+		object, ok := value.(map[string]interface{})
+		if !ok || segment == wildcardSegment {
+			return nil, false
+		}
+		if value, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+
+	switch value.(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return nil, false
+	}
+	return value, true
+}
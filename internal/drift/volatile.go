@@ -0,0 +1,220 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VolatileField describes a response field whose value is expected to change
+// between requests and is therefore a candidate for ignore_fields
+type VolatileField struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// timestampLayouts lists the string formats recognized as timestamps
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123,
+	time.RFC1123Z,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// volatileKeyNames lists field names that carry per-request values
+var volatileKeyNames = map[string]bool{
+	"request_id":     true,
+	"requestid":      true,
+	"trace_id":       true,
+	"traceid":        true,
+	"correlation_id": true,
+	"nonce":          true,
+	"etag":           true,
+}
+
+// Unix epoch bounds (2001-09-09 to 2286-11-20) in seconds and milliseconds
+const (
+	minEpochSeconds = 1e9
+	maxEpochSeconds = 1e10
+	minEpochMillis  = 1e12
+	maxEpochMillis  = 1e13
+)
+
+// FindVolatileFields inspects a JSON body and returns the fields whose values look
+// like timestamps, UUIDs or per-request identifiers. Paths use the same form as
+// required fields, such as "data.created_at" or "items[*].id".
+func FindVolatileFields(body []byte) ([]VolatileField, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse response body: %w", err)
+	}
+
+	found := make(map[string]string)
+	collectVolatileFields(data, "", "", found)
+
+	fields := make([]VolatileField, 0, len(found))
+	for path, reason := range found {
+		fields = append(fields, VolatileField{Path: path, Reason: reason})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Path < fields[j].Path
+	})
+
+	return fields, nil
+}
+
+// collectVolatileFields walks a decoded JSON value and records volatile fields by path
+func collectVolatileFields(value interface{}, path, key string, found map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for childKey, child := range v {
+			childPath := childKey
+			if path != "" {
+				childPath = path + "." + childKey
+			}
+			collectVolatileFields(child, childPath, childKey, found)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectVolatileFields(item, path+"[*]", key, found)
+		}
+	default:
+		if path == "" {
+			return
+		}
+		if reason := volatileReason(key, v); reason != "" {
+			if _, exists := found[path]; !exists {
+				found[path] = reason
+			}
+		}
+	}
+}
+
+// volatileReason explains why a scalar value is considered volatile, or returns
+// an empty string when it is not
+func volatileReason(key string, value interface{}) string {
+	lowerKey := strings.ToLower(key)
+	if volatileKeyNames[lowerKey] {
+		return "per-request identifier"
+	}
+
+	switch v := value.(type) {
+	case string:
+		if uuidPattern.MatchString(v) {
+			return "uuid value"
+		}
+		for _, layout := range timestampLayouts {
+			if _, err := time.Parse(layout, v); err == nil {
+				return "timestamp value"
+			}
+		}
+	case float64:
+		if isTimeKey(key) && isEpoch(v) {
+			return "epoch timestamp"
+		}
+	}
+
+	return ""
+}
+
+// isTimeKey reports whether a field name suggests a point in time
+func isTimeKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	return strings.Contains(lowerKey, "time") ||
+		strings.Contains(lowerKey, "date") ||
+		strings.HasSuffix(lowerKey, "_at") ||
+		strings.HasSuffix(key, "At") ||
+		lowerKey == "ts"
+}
+
+// isEpoch reports whether a number falls within the range of Unix timestamps
+// expressed in seconds or milliseconds
+func isEpoch(value float64) bool {
+	return (value >= minEpochSeconds && value < maxEpochSeconds) ||
+		(value >= minEpochMillis && value < maxEpochMillis)
+}
+
+// matchesValuePattern reports whether two scalar values both match the same
+// configured value pattern, so that a change between them is expected
+func (d *DefaultDiffEngine) matchesValuePattern(a, b interface{}) bool {
+	if len(d.valuePatterns) == 0 {
+		return false
+	}
+
+	aText, ok := scalarText(a)
+	if !ok {
+		return false
+	}
+	bText, ok := scalarText(b)
+	if !ok {
+		return false
+	}
+
+	for _, pattern := range d.valuePatterns {
+		if pattern.MatchString(aText) && pattern.MatchString(bText) {
+			return true
+		}
+	}
+	return false
+}
+
+// scalarText returns the text value patterns are matched against: a string
+// itself or the JSON text of a number. Other values are not matched.
+func scalarText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return string(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// FindVaryingFields compares repeated samples of the same endpoint and returns the
+// paths of fields and headers that differ between them. Such fields change on
+// their own and are ignored when comparing against a baseline. Status code
+// changes are not included since they indicate a real difference in behavior.
+// Body comparisons go through cache when it is not nil, since the samples of a
+// stable endpoint are compared the same way check after check.
+func FindVaryingFields(samples []*Response, cache *ComparisonCache) ([]string, error) {
+	if len(samples) < 2 {
+		return nil, nil
+	}
+
+	engine := NewDiffEngineWithOptions(DiffOptions{Cache: cache})
+	varying := make(map[string]bool)
+
+	for _, sample := range samples[1:] {
+		result, err := engine.CompareResponses(samples[0], sample)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare samples: %w", err)
+		}
+
+		for _, change := range result.StructuralChanges {
+			if change.Type != ChangeTypeStatusChange {
+				varying[normalizeFieldPath(change.Path)] = true
+			}
+		}
+		for _, change := range result.DataChanges {
+			varying[normalizeFieldPath(change.Path)] = true
+		}
+	}
+
+	paths := make([]string, 0, len(varying))
+	for path := range varying {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
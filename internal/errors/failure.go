@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"net"
+	"strings"
+)
+
+// FailureCategory describes why an endpoint check failed
+type FailureCategory string
+
+const (
+	FailureCategoryNetwork FailureCategory = "network"
+	FailureCategoryTimeout FailureCategory = "timeout"
+	FailureCategoryTLS     FailureCategory = "tls"
+	FailureCategoryDNS     FailureCategory = "dns"
+	FailureCategoryHTTP    FailureCategory = "http"
+	FailureCategoryConfig  FailureCategory = "config"
+
+	// FailureCategoryConnectTimeout is a request that could not connect within
+	// the connect timeout of its endpoint
+	FailureCategoryConnectTimeout FailureCategory = "connect_timeout"
+	// FailureCategoryReadTimeout is a request whose response did not start
+	// within the read timeout of its endpoint, which points at the server
+	FailureCategoryReadTimeout FailureCategory = "read_timeout"
+)
+
+// IsInfrastructure reports whether the failure points at the network path to the
+// API rather than at the API itself
+func (c FailureCategory) IsInfrastructure() bool {
+	switch c {
+	case FailureCategoryNetwork, FailureCategoryTimeout, FailureCategoryTLS, FailureCategoryDNS, FailureCategoryConnectTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClassifyStatusCode returns the failure category for an HTTP status code.
+// Only server errors are treated as failures; other codes return an empty category.
+func ClassifyStatusCode(statusCode int) FailureCategory {
+	if statusCode >= 500 {
+		return FailureCategoryHTTP
+	}
+	return ""
+}
+
+// ClassifyFailure determines the failure category of a request error. Timeouts
+// of a single phase of the request are checked first, then standard library
+// error types, then DriftWatch error codes, then the error message. Errors that
+// match nothing are treated as network failures.
+func ClassifyFailure(err error) FailureCategory {
+	if err == nil {
+		return ""
+	}
+
+	if category := classifyPhaseTimeout(err); category != "" {
+		return category
+	}
+
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return FailureCategoryTimeout
+		}
+		return FailureCategoryDNS
+	}
+
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return FailureCategoryTimeout
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return FailureCategoryTimeout
+	}
+
+	if isTLSError(err) {
+		return FailureCategoryTLS
+	}
+
+	for current := err; current != nil; current = stderrors.Unwrap(current) {
+		dwe, ok := current.(*DriftWatchError)
+		if !ok {
+			continue
+		}
+		switch dwe.Code {
+		case "HTTP_TIMEOUT", "NETWORK_TIMEOUT":
+			return FailureCategoryTimeout
+		case "HTTP_DNS_ERROR", "NETWORK_DNS":
+			return FailureCategoryDNS
+		case "HTTP_TLS_ERROR", "NETWORK_TLS":
+			return FailureCategoryTLS
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "timeout") || strings.Contains(message, "deadline exceeded"):
+		return FailureCategoryTimeout
+	case strings.Contains(message, "no such host"):
+		return FailureCategoryDNS
+	case strings.Contains(message, "certificate") || strings.Contains(message, "tls:"):
+		return FailureCategoryTLS
+	default:
+		return FailureCategoryNetwork
+	}
+}
+
+// classifyPhaseTimeout returns the category of a request that failed because
+// its connect or read timeout passed, and an empty category otherwise
+func classifyPhaseTimeout(err error) FailureCategory {
+	for current := err; current != nil; current = stderrors.Unwrap(current) {
+		dwe, ok := current.(*DriftWatchError)
+		if !ok {
+			continue
+		}
+		switch dwe.Code {
+		case "HTTP_CONNECT_TIMEOUT":
+			return FailureCategoryConnectTimeout
+		case "HTTP_READ_TIMEOUT":
+			return FailureCategoryReadTimeout
+		}
+	}
+	return ""
+}
+
+// isTLSError reports whether err is caused by a TLS handshake or certificate failure
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+
+	return stderrors.As(err, &recordErr) ||
+		stderrors.As(err, &verifyErr) ||
+		stderrors.As(err, &unknownAuthority) ||
+		stderrors.As(err, &invalidCert) ||
+		stderrors.As(err, &hostnameErr)
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected FailureCategory
+	}{
+		{"nil error", nil, ""},
+		{"dns error", &net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true}, FailureCategoryDNS},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "api.example.com", IsTimeout: true}, FailureCategoryTimeout},
+		{"context deadline", fmt.Errorf("request failed: %w", context.DeadlineExceeded), FailureCategoryTimeout},
+		{"certificate error", fmt.Errorf("get: %w", x509.UnknownAuthorityError{}), FailureCategoryTLS},
+		{
+			"wrapped driftwatch tls error",
+			WrapError(WrapError(fmt.Errorf("remote error"), ErrorTypeNetwork, "HTTP_TLS_ERROR", "TLS/SSL connection failed"),
+				ErrorTypeNetwork, "HTTP_REQUEST_EXHAUSTED", "request failed after 3 attempts"),
+			FailureCategoryTLS,
+		},
+		{"connection refused", fmt.Errorf("dial tcp 127.0.0.1:1: connect: connection refused"), FailureCategoryNetwork},
+		{
+			"connect timeout",
+			WrapError(WrapError(fmt.Errorf("connect timeout: %w", context.DeadlineExceeded), ErrorTypeNetwork, "HTTP_CONNECT_TIMEOUT", "Connecting to the server timed out"),
+				ErrorTypeNetwork, "HTTP_REQUEST_EXHAUSTED", "request failed after 1 attempts"),
+			FailureCategoryConnectTimeout,
+		},
+		{
+			"read timeout",
+			WrapError(fmt.Errorf("read timeout: no response within 1s of sending the request"), ErrorTypeNetwork, "HTTP_READ_TIMEOUT", "Server did not respond in time"),
+			FailureCategoryReadTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyFailure(tt.err))
+		})
+	}
+}
+
+func TestClassifyStatusCode(t *testing.T) {
+	assert.Equal(t, FailureCategory(""), ClassifyStatusCode(200))
+	assert.Equal(t, FailureCategory(""), ClassifyStatusCode(404))
+	assert.Equal(t, FailureCategoryHTTP, ClassifyStatusCode(503))
+}
+
+func TestFailureCategoryIsInfrastructure(t *testing.T) {
+	assert.True(t, FailureCategoryDNS.IsInfrastructure())
+	assert.True(t, FailureCategoryTimeout.IsInfrastructure())
+	assert.True(t, FailureCategoryConnectTimeout.IsInfrastructure())
+	assert.False(t, FailureCategoryReadTimeout.IsInfrastructure())
+	assert.False(t, FailureCategoryHTTP.IsInfrastructure())
+	assert.False(t, FailureCategoryConfig.IsInfrastructure())
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCABundle(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadCABundle(filepath.Join(dir, "missing.pem")); err == nil || !strings.Contains(err.Error(), "failed to read CA bundle") {
+		t.Errorf("Expected a read error for a missing bundle, got %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCABundle(invalid); err == nil || !strings.Contains(err.Error(), "contains no PEM certificates") {
+		t.Errorf("Expected a parse error for an invalid bundle, got %v", err)
+	}
+}
+
+func TestHTTPClient_DoWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The test server's certificate stands in for a private CA
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewHTTPClient(nil)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 0})
+
+	send := func(ctx context.Context) error {
+		req, err := NewRequest("GET", server.URL, nil, nil)
+		if err != nil {
+			t.Fatalf("NewRequest failed: %v", err)
+		}
+		_, err = client.Do(req.WithContext(ctx))
+		return err
+	}
+
+	if err := send(context.Background()); err == nil {
+		t.Error("Expected the private certificate to be rejected without a CA bundle")
+	}
+
+	if err := send(WithCABundle(context.Background(), bundle)); err != nil {
+		t.Errorf("Expected the request to succeed with the CA bundle, got %v", err)
+	}
+
+	// Requests without the bundle keep verifying against the system roots
+	if err := send(context.Background()); err == nil {
+		t.Error("Expected the CA bundle to apply only to requests that select it")
+	}
+
+	if err := send(WithCABundle(context.Background(), filepath.Join(t.TempDir(), "missing.pem"))); err == nil {
+		t.Error("Expected a missing CA bundle to fail the request")
+	}
+}
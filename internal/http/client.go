@@ -5,16 +5,20 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
 	"net/http"
+	"net/http/httptrace"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/errors"
 	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/recovery"
 )
 
 // Client defines the interface for HTTP operations
@@ -33,16 +37,37 @@ type Response struct {
 	ResponseTime time.Duration `json:"response_time"`
 	Timestamp    time.Time     `json:"timestamp"`
 	Attempt      int           `json:"attempt"`
+
+	// TLS is the server certificate for HTTPS responses; nil otherwise
+	TLS *TLSCertificate `json:"tls,omitempty"`
+
+	// Protocol is the negotiated HTTP version, such as "HTTP/1.1" or "HTTP/2.0"
+	Protocol string `json:"protocol"`
+
+	// Trailers holds the trailers sent after the body; nil if there were none
+	Trailers http.Header `json:"trailers,omitempty"`
+
+	// Timing breaks ResponseTime down into the phases of the request
+	Timing Timing `json:"timing"`
+
+	// Truncated is set when the stream read limit of the request cut the body
+	// short, so that Body only holds a prefix of the response
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // RetryPolicy defines retry behavior for HTTP requests
 type RetryPolicy struct {
 	MaxRetries int             `json:"max_retries"`
 	Delay      time.Duration   `json:"delay"`
+	MaxDelay   time.Duration   `json:"max_delay"`
 	Backoff    BackoffStrategy `json:"backoff"`
 	Jitter     bool            `json:"jitter"`
 }
 
+// DefaultMaxRetryDelay is the upper bound applied to retry delays, including
+// delays requested by servers through the Retry-After header
+const DefaultMaxRetryDelay = 30 * time.Second
+
 // BackoffStrategy defines the backoff strategy for retries
 type BackoffStrategy string
 
@@ -76,13 +101,19 @@ func NewHTTPClient(logger *logging.Logger) *HTTPClient {
 		logger = logging.GetGlobalLogger()
 	}
 
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyForRequest
+	transport.DialContext = dialWithConnectTimeout(transport.DialContext)
+
 	return &HTTPClient{
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: newCABundleTransport(transport),
 		},
 		retryPolicy: RetryPolicy{
 			MaxRetries: 3,
 			Delay:      1 * time.Second,
+			MaxDelay:   DefaultMaxRetryDelay,
 			Backoff:    BackoffExponential,
 			Jitter:     true,
 		},
@@ -100,30 +131,40 @@ func (c *HTTPClient) Do(req *http.Request) (*Response, error) {
 		return nil, err
 	}
 
+	// All attempts and the delays between them share the request context's
+	// deadline, so retries never extend a request beyond its overall budget.
+	ctx := req.Context()
+
 	var lastErr error
+	attempts := 0
 	for attempt := 0; attempt <= c.retryPolicy.MaxRetries; attempt++ {
+		attempts++
 		response, err := c.executeAttempt(req, bodyBytes, attempt)
 		if err != nil {
 			lastErr = err
 			if attempt < c.retryPolicy.MaxRetries {
-				c.retryAfterDelay(attempt)
+				if c.retryAfterDelay(ctx, attempt, nil) != nil {
+					break
+				}
 				continue
 			}
 			break
 		}
 
-		// Check if we should retry based on status code
+		// Check if we should retry based on status code. If the deadline leaves
+		// no time for another attempt, the retryable response is returned as is.
 		if c.shouldRetry(response.StatusCode) && attempt < c.retryPolicy.MaxRetries {
 			c.logRetryableStatus(req, response, attempt)
-			c.retryAfterDelay(attempt)
-			continue
+			if c.retryAfterDelay(ctx, attempt, response) == nil {
+				continue
+			}
 		}
 
 		c.logFinalResult(req, response, attempt)
 		return response, nil
 	}
 
-	return c.handleExhaustedRetries(req, lastErr)
+	return c.handleExhaustedRetries(req, lastErr, attempts)
 }
 
 // prepareRequestBody reads and stores the request body for potential retries
@@ -156,6 +197,21 @@ func (c *HTTPClient) executeAttempt(req *http.Request, bodyBytes []byte, attempt
 		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 	}
 
+	// The stream read limit ends reading a body by cancelling the attempt, and
+	// the read timeout ends waiting for a response with the cause of the failure
+	ctx, cancelCause := context.WithCancelCause(req.Context())
+	defer cancelCause(nil)
+	cancel := func() { cancelCause(nil) }
+
+	trace := newTimingTrace()
+	tracedCtx := httptrace.WithClientTrace(ctx, trace.clientTrace())
+	if timeout := phaseTimeoutsFromContext(ctx).read; timeout > 0 {
+		var stopReadTimeout func()
+		tracedCtx, stopReadTimeout = withReadTimeout(tracedCtx, timeout, cancelCause)
+		defer stopReadTimeout()
+	}
+	tracedReq := req.WithContext(tracedCtx)
+
 	startTime := time.Now()
 	c.logger.Debug("Making HTTP request",
 		"method", req.Method,
@@ -163,7 +219,7 @@ func (c *HTTPClient) executeAttempt(req *http.Request, bodyBytes []byte, attempt
 		"attempt", attempt+1,
 		"max_attempts", c.retryPolicy.MaxRetries+1)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.client.Do(tracedReq)
 	responseTime := time.Since(startTime)
 
 	if attempt > 0 {
@@ -171,10 +227,19 @@ func (c *HTTPClient) executeAttempt(req *http.Request, bodyBytes []byte, attempt
 	}
 
 	if err != nil {
+		if cause := context.Cause(ctx); stderrors.Is(cause, errReadTimeout) {
+			err = cause
+		}
 		return nil, c.handleRequestError(err, req, attempt, responseTime)
 	}
 
-	return c.processResponse(resp, responseTime, startTime, attempt)
+	response, err := c.processResponse(resp, cancel, responseTime, startTime, attempt)
+	if err != nil {
+		return nil, err
+	}
+	response.Timing = trace.result()
+
+	return response, nil
 }
 
 // handleRequestError handles network-level request errors
@@ -188,9 +253,18 @@ func (c *HTTPClient) handleRequestError(err error, req *http.Request, attempt in
 	return wrappedErr
 }
 
-// processResponse reads and processes the HTTP response
-func (c *HTTPClient) processResponse(resp *http.Response, responseTime time.Duration, startTime time.Time, attempt int) (*Response, error) {
-	body, err := io.ReadAll(resp.Body)
+// processResponse reads and processes the HTTP response. The body is read
+// within the stream read limit of the request, which cancel ends.
+func (c *HTTPClient) processResponse(resp *http.Response, cancel context.CancelFunc, responseTime time.Duration, startTime time.Time, attempt int) (*Response, error) {
+	var body []byte
+	var truncated bool
+	var err error
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		// Only the handshake is checked; the body is the upgraded connection
+		recordWebSocketAccept(resp)
+	} else {
+		body, truncated, err = readBody(resp.Body, streamReadLimitFromContext(resp.Request.Context()), cancel)
+	}
 	if closeErr := resp.Body.Close(); closeErr != nil {
 		c.logger.Warn("Failed to close response body", "error", closeErr)
 	}
@@ -212,6 +286,21 @@ func (c *HTTPClient) processResponse(resp *http.Response, responseTime time.Dura
 		ResponseTime: responseTime,
 		Timestamp:    startTime,
 		Attempt:      attempt + 1,
+		TLS:          newTLSCertificate(resp.TLS),
+		Protocol:     resp.Proto,
+		Truncated:    truncated,
+	}
+
+	// Trailer values are only known once the body has been read; announced
+	// trailers that were never sent are left out
+	for name, values := range resp.Trailer {
+		if len(values) == 0 {
+			continue
+		}
+		if response.Trailers == nil {
+			response.Trailers = make(http.Header)
+		}
+		response.Trailers[name] = values
 	}
 
 	// Update metrics
@@ -221,13 +310,28 @@ func (c *HTTPClient) processResponse(resp *http.Response, responseTime time.Dura
 	return response, nil
 }
 
-// retryAfterDelay waits for the calculated delay before retrying
-func (c *HTTPClient) retryAfterDelay(attempt int) {
+// retryAfterDelay waits for the calculated delay before retrying. When the
+// previous response carried a Retry-After hint, the server's delay is used instead.
+// It returns an error without retrying if the context is cancelled or its
+// deadline would pass before the next attempt could start.
+func (c *HTTPClient) retryAfterDelay(ctx context.Context, attempt int, response *Response) error {
 	delay := c.calculateDelay(attempt)
-	c.logger.Debug("Retrying request after delay",
+	if serverDelay, ok := c.serverRetryDelay(response); ok {
+		delay = serverDelay
+	}
+
+	if err := recovery.WaitForRetry(ctx, delay); err != nil {
+		c.logger.Debug("Not retrying request",
+			"delay", delay,
+			"next_attempt", attempt+2,
+			"reason", err)
+		return err
+	}
+
+	c.logger.Debug("Retried request after delay",
 		"delay", delay,
 		"next_attempt", attempt+2)
-	time.Sleep(delay)
+	return nil
 }
 
 // logRetryableStatus logs when a request returns a retryable status code
@@ -242,7 +346,8 @@ func (c *HTTPClient) logRetryableStatus(req *http.Request, response *Response, a
 
 // logFinalResult logs the final result of the request
 func (c *HTTPClient) logFinalResult(req *http.Request, response *Response, attempt int) {
-	if response.StatusCode >= 200 && response.StatusCode < 300 {
+	// A completed WebSocket handshake answers with 101 Switching Protocols
+	if (response.StatusCode >= 200 && response.StatusCode < 300) || response.StatusCode == http.StatusSwitchingProtocols {
 		c.metrics.SuccessfulReqs++
 		c.logger.Debug("HTTP request successful",
 			"method", req.Method,
@@ -262,11 +367,11 @@ func (c *HTTPClient) logFinalResult(req *http.Request, response *Response, attem
 }
 
 // handleExhaustedRetries handles the case when all retries are exhausted
-func (c *HTTPClient) handleExhaustedRetries(req *http.Request, lastErr error) (*Response, error) {
+func (c *HTTPClient) handleExhaustedRetries(req *http.Request, lastErr error, attempts int) (*Response, error) {
 	c.metrics.FailedRequests++
 	if lastErr != nil {
 		finalErr := errors.WrapError(lastErr, errors.ErrorTypeNetwork, "HTTP_REQUEST_EXHAUSTED",
-			fmt.Sprintf("request failed after %d attempts", c.retryPolicy.MaxRetries+1)).
+			fmt.Sprintf("request failed after %d attempts", attempts)).
 			WithSeverity(errors.SeverityHigh).
 			WithGuidance("Check endpoint availability and network connectivity").
 			WithContext("method", req.Method).
@@ -291,6 +396,7 @@ func (c *HTTPClient) SetRetryPolicy(policy RetryPolicy) {
 	c.logger.Debug("HTTP client retry policy updated",
 		"max_retries", policy.MaxRetries,
 		"delay", policy.Delay,
+		"max_delay", policy.MaxDelay,
 		"backoff", policy.Backoff,
 		"jitter", policy.Jitter)
 }
@@ -315,6 +421,11 @@ func (c *HTTPClient) calculateDelay(attempt int) time.Duration {
 		delay = c.retryPolicy.Delay
 	}
 
+	// Apply maximum delay limit
+	if c.retryPolicy.MaxDelay > 0 && delay > c.retryPolicy.MaxDelay {
+		delay = c.retryPolicy.MaxDelay
+	}
+
 	// Add jitter if enabled
 	if c.retryPolicy.Jitter {
 		// Use crypto/rand for secure random number generation
@@ -332,6 +443,59 @@ func (c *HTTPClient) calculateDelay(attempt int) time.Duration {
 	return delay
 }
 
+// serverRetryDelay returns the delay requested by a 429 or 503 response through
+// the Retry-After header, clamped to the maximum retry delay
+func (c *HTTPClient) serverRetryDelay(response *Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	delay, ok := parseRetryAfter(response.Headers.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+
+	if c.retryPolicy.MaxDelay > 0 && delay > c.retryPolicy.MaxDelay {
+		delay = c.retryPolicy.MaxDelay
+	}
+
+	return delay, true
+}
+
+// parseRetryAfter parses a Retry-After header value given either as a number
+// of seconds or as an HTTP-date relative to now
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if seconds > int64(math.MaxInt64/int64(time.Second)) {
+			seconds = int64(math.MaxInt64 / int64(time.Second))
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryAt, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := retryAt.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
 // shouldRetry determines if a request should be retried based on status code
 func (c *HTTPClient) shouldRetry(statusCode int) bool {
 	// Retry on server errors (5xx) and some client errors
@@ -348,9 +512,16 @@ func (c *HTTPClient) shouldRetry(statusCode int) bool {
 	}
 }
 
-// NewRequest creates a new HTTP request with common headers
+// NewRequest creates a new HTTP request with common headers. For ws and wss
+// URLs it creates a WebSocket opening handshake, ignoring the method and body.
 func NewRequest(method, url string, body io.Reader, headers map[string]string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+	var req *http.Request
+	var err error
+	if IsWebSocketURL(url) {
+		req, err = newWebSocketHandshakeRequest(url)
+	} else {
+		req, err = http.NewRequest(method, url, body)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -380,10 +551,11 @@ func NewJSONRequest(method, url string, body io.Reader, headers map[string]strin
 
 // ClientConfig holds configuration for creating HTTP clients
 type ClientConfig struct {
-	Timeout    time.Duration
-	RetryCount int
-	RetryDelay time.Duration
-	UserAgent  string
+	Timeout       time.Duration
+	RetryCount    int
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration // 0 uses DefaultMaxRetryDelay
+	UserAgent     string
 }
 
 // NewClient is a variable that holds the function to create a new HTTP client
@@ -391,10 +563,16 @@ type ClientConfig struct {
 var NewClient = func(config ClientConfig) Client {
 	client := NewHTTPClient(nil)
 
+	maxDelay := config.MaxRetryDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryDelay
+	}
+
 	client.SetTimeout(config.Timeout)
 	client.SetRetryPolicy(RetryPolicy{
 		MaxRetries: config.RetryCount,
 		Delay:      config.RetryDelay,
+		MaxDelay:   maxDelay,
 		Backoff:    BackoffExponential,
 		Jitter:     true,
 	})
@@ -433,6 +611,14 @@ func (c *HTTPClient) wrapNetworkError(err error, req *http.Request, attempt int,
 	// Categorize the error based on its type or message
 	errStr := err.Error()
 	switch {
+	case stderrors.Is(err, errConnectTimeout):
+		code = "HTTP_CONNECT_TIMEOUT"
+		message = "Connecting to the server timed out"
+		guidance = "Check network connectivity to the endpoint or increase connect_timeout"
+	case stderrors.Is(err, errReadTimeout):
+		code = "HTTP_READ_TIMEOUT"
+		message = "Server did not respond in time"
+		guidance = "Check endpoint performance or increase read_timeout"
 	case strings.Contains(errStr, "timeout") || strings.Contains(errStr, "deadline exceeded"):
 		code = "HTTP_TIMEOUT"
 		message = "HTTP request timed out"
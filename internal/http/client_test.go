@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -254,6 +255,49 @@ func TestHTTPClient_DoRetryExhausted(t *testing.T) {
 	}
 }
 
+func TestHTTPClient_DoRetriesShareDeadline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxRetries: 5,
+		Delay:      100 * time.Millisecond,
+		Backoff:    BackoffFixed,
+		Jitter:     false,
+	})
+
+	// Two attempts fit in the budget; the third would start after the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	start := time.Now()
+	response, err := client.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Request should return the last response, got: %v", err)
+	}
+
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, response.StatusCode)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests within the deadline, got %d", requests)
+	}
+	if elapsed >= 150*time.Millisecond {
+		t.Errorf("Expected retries to stop before the deadline, took %v", elapsed)
+	}
+}
+
 func TestHTTPClient_DoNetworkError(t *testing.T) {
 	client := NewHTTPClient(nil)
 	client.SetRetryPolicy(RetryPolicy{
@@ -445,6 +489,133 @@ func TestHTTPClient_ShouldRetry(t *testing.T) {
 	}
 }
 
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "5", 5 * time.Second, true},
+		{"zero seconds", "0", 0, true},
+		{"padded seconds", " 2 ", 2 * time.Second, true},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"http date in past", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"empty", "", 0, false},
+		{"negative seconds", "-3", 0, false},
+		{"invalid", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok=%v, got %v", tt.ok, ok)
+			}
+			if delay != tt.expected {
+				t.Errorf("Expected delay %v, got %v", tt.expected, delay)
+			}
+		})
+	}
+}
+
+func TestNewClient_MaxRetryDelay(t *testing.T) {
+	client := NewClient(ClientConfig{RetryCount: 3, RetryDelay: time.Second, MaxRetryDelay: 2 * time.Minute}).(*HTTPClient)
+	if client.retryPolicy.MaxDelay != 2*time.Minute {
+		t.Errorf("Expected max delay 2m, got %v", client.retryPolicy.MaxDelay)
+	}
+
+	client = NewClient(ClientConfig{RetryCount: 3, RetryDelay: time.Second}).(*HTTPClient)
+	if client.retryPolicy.MaxDelay != DefaultMaxRetryDelay {
+		t.Errorf("Expected default max delay %v, got %v", DefaultMaxRetryDelay, client.retryPolicy.MaxDelay)
+	}
+}
+
+func TestHTTPClient_ServerRetryDelay(t *testing.T) {
+	client := NewHTTPClient(nil)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxRetries: 3,
+		Delay:      10 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+		Backoff:    BackoffFixed,
+		Jitter:     false,
+	})
+
+	newResponse := func(statusCode int, retryAfter string) *Response {
+		headers := http.Header{}
+		if retryAfter != "" {
+			headers.Set("Retry-After", retryAfter)
+		}
+		return &Response{StatusCode: statusCode, Headers: headers}
+	}
+
+	if delay, ok := client.serverRetryDelay(newResponse(http.StatusTooManyRequests, "2")); !ok || delay != 2*time.Second {
+		t.Errorf("Expected 2s delay for 429, got %v (ok=%v)", delay, ok)
+	}
+
+	if delay, ok := client.serverRetryDelay(newResponse(http.StatusServiceUnavailable, "120")); !ok || delay != 5*time.Second {
+		t.Errorf("Expected delay clamped to 5s for 503, got %v (ok=%v)", delay, ok)
+	}
+
+	if _, ok := client.serverRetryDelay(newResponse(http.StatusInternalServerError, "2")); ok {
+		t.Error("Expected Retry-After to be ignored for 500 responses")
+	}
+
+	if _, ok := client.serverRetryDelay(newResponse(http.StatusTooManyRequests, "")); ok {
+		t.Error("Expected no server delay without Retry-After header")
+	}
+
+	if _, ok := client.serverRetryDelay(nil); ok {
+		t.Error("Expected no server delay without a response")
+	}
+}
+
+func TestHTTPClient_DoHonorsRetryAfter(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		if attemptCount == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	client.SetRetryPolicy(RetryPolicy{
+		MaxRetries: 1,
+		Delay:      10 * time.Millisecond,
+		MaxDelay:   200 * time.Millisecond,
+		Backoff:    BackoffFixed,
+		Jitter:     false,
+	})
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	start := time.Now()
+	response, err := client.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, response.StatusCode)
+	}
+
+	// Retry-After of 1s is clamped to the 200ms max delay, but must exceed the 10ms backoff
+	if elapsed < 200*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected retry delay of about 200ms, took %v", elapsed)
+	}
+}
+
 func TestHTTPClient_DoNonRetryableError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -551,6 +722,142 @@ func TestHTTPClient_DoWithRetryAndRequestBody(t *testing.T) {
 	}
 }
 
+func TestHTTPClient_DoCapturesTLSCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	client.client = server.Client()
+
+	req, err := NewRequest("GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+
+	if resp.TLS == nil {
+		t.Fatal("Expected TLS certificate to be captured")
+	}
+	if !resp.TLS.NotAfter.Equal(server.Certificate().NotAfter) {
+		t.Errorf("Expected NotAfter %v, got %v", server.Certificate().NotAfter, resp.TLS.NotAfter)
+	}
+	if len(resp.TLS.Fingerprint) != 64 {
+		t.Errorf("Expected SHA-256 hex fingerprint, got %q", resp.TLS.Fingerprint)
+	}
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer plain.Close()
+
+	req, err = NewRequest("GET", plain.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if resp.TLS != nil {
+		t.Errorf("Expected no TLS certificate for plain HTTP, got %+v", resp.TLS)
+	}
+}
+
+func TestHTTPClient_DoCapturesProtocolAndTrailers(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status, X-Unsent")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	client.client = server.Client()
+
+	req, err := NewRequest("GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+
+	if resp.Protocol != "HTTP/2.0" {
+		t.Errorf("Expected protocol HTTP/2.0, got %q", resp.Protocol)
+	}
+	if status := resp.Trailers.Get("Grpc-Status"); status != "0" {
+		t.Errorf("Expected Grpc-Status trailer 0, got %q", status)
+	}
+	if _, exists := resp.Trailers["X-Unsent"]; exists {
+		t.Errorf("Expected announced but unsent trailer to be left out, got %v", resp.Trailers)
+	}
+}
+
+func TestHTTPClient_DoCapturesTiming(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	client.client = server.Client()
+
+	req, err := NewRequest("GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+
+	timing := resp.Timing
+	if timing.Connect <= 0 {
+		t.Errorf("Expected connect time to be captured, got %v", timing.Connect)
+	}
+	if timing.TLS <= 0 {
+		t.Errorf("Expected TLS handshake time to be captured, got %v", timing.TLS)
+	}
+	if timing.TTFB < 20*time.Millisecond {
+		t.Errorf("Expected TTFB to include the server's processing time, got %v", timing.TTFB)
+	}
+	if timing.DNS != 0 {
+		t.Errorf("Expected no DNS lookup for an IP address, got %v", timing.DNS)
+	}
+	if total := timing.DNS + timing.Connect + timing.TLS + timing.TTFB; total > resp.ResponseTime {
+		t.Errorf("Expected phases of %v to fit in the response time of %v", total, resp.ResponseTime)
+	}
+
+	// A kept-alive connection is reused without connecting again
+	req, err = NewRequest("GET", server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if resp.Timing.Connect != 0 || resp.Timing.TLS != 0 {
+		t.Errorf("Expected no connect or TLS time on a reused connection, got %+v", resp.Timing)
+	}
+	if resp.Timing.TTFB <= 0 {
+		t.Errorf("Expected TTFB on a reused connection, got %v", resp.Timing.TTFB)
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	headers := map[string]string{
 		"Authorization": "Bearer token123",
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// StreamReadLimit bounds how much of a response body is read, so that streaming
+// responses such as Server-Sent Events or long-lived chunked responses yield a
+// prefix instead of blocking until the request times out. The zero value reads
+// the whole body.
+type StreamReadLimit struct {
+	// Bytes is the most body bytes read; zero reads any amount
+	Bytes int64
+	// Duration is how long the body is read for once the headers have arrived;
+	// zero reads until the body ends
+	Duration time.Duration
+}
+
+// streamReadLimitContextKey carries the stream read limit of a request
+type streamReadLimitContextKey struct{}
+
+// byteSizeUnits are the suffixes of byte sizes, longest first so that "KB" is
+// not taken for "B"
+var byteSizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"B", 1},
+}
+
+// ParseStreamReadLimit parses a stream read limit: either a byte size such as
+// "64KB", "1MB" or "4096", where KB and MB are multiples of 1024, or a duration
+// such as "5s"
+func ParseStreamReadLimit(raw string) (StreamReadLimit, error) {
+	raw = strings.TrimSpace(raw)
+
+	if duration, err := time.ParseDuration(raw); err == nil {
+		if duration <= 0 {
+			return StreamReadLimit{}, fmt.Errorf("stream read duration must be positive")
+		}
+		return StreamReadLimit{Duration: duration}, nil
+	}
+
+	number, multiplier := strings.ToUpper(raw), int64(1)
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(number, unit.suffix) {
+			number, multiplier = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix)), unit.bytes
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return StreamReadLimit{}, fmt.Errorf("invalid stream read limit %q: must be a byte size such as 64KB or a duration such as 5s", raw)
+	}
+	if size <= 0 {
+		return StreamReadLimit{}, fmt.Errorf("stream read size must be positive")
+	}
+
+	return StreamReadLimit{Bytes: size * multiplier}, nil
+}
+
+// WithStreamReadLimit returns a context that bounds how much of the response
+// bodies of requests made with it are read. An empty limit reads whole bodies.
+// Responses cut short by the limit are marked as truncated.
+func WithStreamReadLimit(ctx context.Context, limit string) (context.Context, error) {
+	if limit == "" {
+		return ctx, nil
+	}
+
+	parsed, err := ParseStreamReadLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return context.WithValue(ctx, streamReadLimitContextKey{}, parsed), nil
+}
+
+// streamReadLimitFromContext returns the limit set with WithStreamReadLimit
+func streamReadLimitFromContext(ctx context.Context) StreamReadLimit {
+	limit, _ := ctx.Value(streamReadLimitContextKey{}).(StreamReadLimit)
+	return limit
+}
+
+// readBody reads a response body within a stream read limit and reports whether
+// the limit cut it short. The duration limit ends the read by cancelling the
+// request, which closes the connection rather than leaving a stream open.
+func readBody(body io.Reader, limit StreamReadLimit, cancel context.CancelFunc) ([]byte, bool, error) {
+	if limit.Bytes > 0 {
+		// One byte past the limit tells a body of exactly the limit from a longer one
+		body = io.LimitReader(body, limit.Bytes+1)
+	}
+
+	var expired atomic.Bool
+	if limit.Duration > 0 {
+		timer := time.AfterFunc(limit.Duration, func() {
+			expired.Store(true)
+			cancel()
+		})
+		defer timer.Stop()
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil && expired.Load() {
+		return data, true, nil
+	}
+	if limit.Bytes > 0 && int64(len(data)) > limit.Bytes {
+		return data[:limit.Bytes], true, nil
+	}
+
+	return data, false, err
+}
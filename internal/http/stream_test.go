@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStreamReadLimit(t *testing.T) {
+	tests := []struct {
+		raw       string
+		expected  StreamReadLimit
+		expectErr bool
+	}{
+		{raw: "4096", expected: StreamReadLimit{Bytes: 4096}},
+		{raw: "512B", expected: StreamReadLimit{Bytes: 512}},
+		{raw: "64KB", expected: StreamReadLimit{Bytes: 64 << 10}},
+		{raw: "1 mb", expected: StreamReadLimit{Bytes: 1 << 20}},
+		{raw: "5s", expected: StreamReadLimit{Duration: 5 * time.Second}},
+		{raw: "1m30s", expected: StreamReadLimit{Duration: 90 * time.Second}},
+		{raw: "0", expectErr: true},
+		{raw: "-1s", expectErr: true},
+		{raw: "10GB", expectErr: true},
+		{raw: "forever", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			limit, err := ParseStreamReadLimit(tt.raw)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %+v", limit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if limit != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, limit)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_DoWithStreamReadLimit(t *testing.T) {
+	// An event stream that never ends on its own
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for i := 0; ; i++ {
+			if _, err := fmt.Fprintf(w, "data: {\"seq\": %d}\n\n", i); err != nil {
+				return
+			}
+			flusher.Flush()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		limit string
+		check func(t *testing.T, body []byte)
+	}{
+		{
+			name:  "bytes",
+			limit: "40",
+			check: func(t *testing.T, body []byte) {
+				if len(body) != 40 {
+					t.Errorf("Expected 40 bytes, got %d", len(body))
+				}
+			},
+		},
+		{
+			name:  "duration",
+			limit: "50ms",
+			check: func(t *testing.T, body []byte) {
+				if !strings.HasPrefix(string(body), "data: {\"seq\": 0}\n\n") {
+					t.Errorf("Expected the first event, got %q", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewHTTPClient(nil)
+			client.SetRetryPolicy(RetryPolicy{MaxRetries: 0})
+			client.SetTimeout(5 * time.Second)
+
+			ctx, err := WithStreamReadLimit(context.Background(), tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !resp.Truncated {
+				t.Error("Expected the response to be marked as truncated")
+			}
+			tt.check(t, resp.Body)
+		})
+	}
+
+	t.Run("complete body", func(t *testing.T) {
+		complete := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"ok": true}`)
+		}))
+		defer complete.Close()
+
+		ctx, err := WithStreamReadLimit(context.Background(), "1KB")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, complete.URL, nil)
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+
+		resp, err := NewHTTPClient(nil).Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Truncated || string(resp.Body) != `{"ok": true}` {
+			t.Errorf("Expected the complete body, got %q (truncated: %v)", resp.Body, resp.Truncated)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// errConnectTimeout and errReadTimeout mark requests that failed because one
+// phase of the request ran out of time, rather than the request as a whole
+var (
+	errConnectTimeout = stderrors.New("connect timeout")
+	errReadTimeout    = stderrors.New("read timeout")
+)
+
+// phaseTimeoutsContextKey carries the phase timeouts selected for a request
+type phaseTimeoutsContextKey struct{}
+
+// phaseTimeouts bounds the phases of a request; zero leaves a phase to the
+// timeout of the whole request
+type phaseTimeouts struct {
+	connect time.Duration
+	read    time.Duration
+}
+
+// WithPhaseTimeouts returns a context whose requests fail when connecting to
+// the server takes longer than connect, or when the response headers take
+// longer than read to arrive once the request has been sent. The failures
+// report which phase timed out, so that a server that cannot be reached is told
+// apart from one that is slow to answer. Zero leaves a phase unbounded.
+func WithPhaseTimeouts(ctx context.Context, connect, read time.Duration) context.Context {
+	if connect <= 0 && read <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, phaseTimeoutsContextKey{}, phaseTimeouts{connect: connect, read: read})
+}
+
+// phaseTimeoutsFromContext returns the timeouts set with WithPhaseTimeouts
+func phaseTimeoutsFromContext(ctx context.Context) phaseTimeouts {
+	timeouts, _ := ctx.Value(phaseTimeoutsContextKey{}).(phaseTimeouts)
+	return timeouts
+}
+
+// dialFunc is the signature of http.Transport.DialContext
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dialWithConnectTimeout bounds each connection dialed by dial with the
+// connect timeout of the request it is dialed for
+func dialWithConnectTimeout(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		timeout := phaseTimeoutsFromContext(ctx).connect
+		if timeout <= 0 {
+			return dial(ctx, network, addr)
+		}
+
+		dialCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		conn, err := dial(dialCtx, network, addr)
+		if err != nil && ctx.Err() == nil && stderrors.Is(dialCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: no connection to %s within %s: %w", errConnectTimeout, addr, timeout, err)
+		}
+		return conn, err
+	}
+}
+
+// withReadTimeout returns a context tracing the request so that cancel ends it
+// when the first response byte has not arrived within timeout of the request
+// being written. The returned function stops the timer.
+func withReadTimeout(ctx context.Context, timeout time.Duration, cancel context.CancelCauseFunc) (context.Context, func()) {
+	var mu sync.Mutex
+	var timer *time.Timer
+	stop := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+
+	trace := &httptrace.ClientTrace{
+		// A request written again on a new connection gets a full timeout
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(timeout, func() {
+				cancel(fmt.Errorf("%w: no response within %s of sending the request", errReadTimeout, timeout))
+			})
+		},
+		GotFirstResponseByte: stop,
+	}
+
+	return httptrace.WithClientTrace(ctx, trace), stop
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/errors"
+)
+
+func TestHTTPClient_DoWithReadTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+
+		// The headers go out at once and the body follows slowly, which the
+		// read timeout leaves to the timeout of the whole request
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(150 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(nil)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 0})
+	ctx := WithPhaseTimeouts(context.Background(), 0, 50*time.Millisecond)
+
+	req, err := NewRequest(http.MethodGet, server.URL+"/slow-headers", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	start := time.Now()
+	_, err = client.Do(req.WithContext(ctx))
+	if err == nil {
+		t.Fatal("Expected the read timeout to fail the request")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the request to end at the read timeout, took %s", elapsed)
+	}
+	if category := errors.ClassifyFailure(err); category != errors.FailureCategoryReadTimeout {
+		t.Errorf("Expected category %q, got %q (%v)", errors.FailureCategoryReadTimeout, category, err)
+	}
+
+	req, err = NewRequest(http.MethodGet, server.URL+"/slow-body", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	response, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Expected a slow body within the read timeout to succeed: %v", err)
+	}
+	if string(response.Body) != `{"ok": true}` {
+		t.Errorf("Unexpected body %q", response.Body)
+	}
+}
+
+func TestDialWithConnectTimeout(t *testing.T) {
+	// A dial that never connects, like a server dropping SYN packets
+	hang := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	dial := dialWithConnectTimeout(hang)
+
+	ctx := WithPhaseTimeouts(context.Background(), 20*time.Millisecond, 0)
+	_, err := dial(ctx, "tcp", "203.0.113.1:443")
+	if !stderrors.Is(err, errConnectTimeout) {
+		t.Fatalf("Expected a connect timeout, got %v", err)
+	}
+
+	// Cancelling the request is not a connect timeout
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = dial(cancelled, "tcp", "203.0.113.1:443")
+	if stderrors.Is(err, errConnectTimeout) {
+		t.Errorf("Expected the cancellation, got %v", err)
+	}
+
+	// Without a connect timeout the dial is left to the request's context
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	_, err = dial(short, "tcp", "203.0.113.1:443")
+	if !stderrors.Is(err, context.DeadlineExceeded) || stderrors.Is(err, errConnectTimeout) {
+		t.Errorf("Expected the request deadline, got %v", err)
+	}
+}
+
+func TestWrapNetworkErrorPhaseTimeouts(t *testing.T) {
+	client := NewHTTPClient(nil)
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{err: errConnectTimeout, expected: "HTTP_CONNECT_TIMEOUT"},
+		{err: errReadTimeout, expected: "HTTP_READ_TIMEOUT"},
+		{err: context.DeadlineExceeded, expected: "HTTP_TIMEOUT"},
+	}
+	for _, tt := range tests {
+		if code := client.wrapNetworkError(tt.err, req, 1, time.Second).Code; code != tt.expected {
+			t.Errorf("Expected code %s for %v, got %s", tt.expected, tt.err, code)
+		}
+	}
+}
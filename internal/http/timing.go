@@ -0,0 +1,99 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing breaks the time to the first response byte down into the phases of
+// the request. Phases that did not take place are zero, such as DNS, connect
+// and TLS when a kept-alive connection is reused.
+type Timing struct {
+	DNS     time.Duration `json:"dns"`
+	Connect time.Duration `json:"connect"`
+	TLS     time.Duration `json:"tls"`
+
+	// TTFB is the time from the request being written to the first response
+	// byte: the server's processing time plus a network round trip
+	TTFB time.Duration `json:"ttfb"`
+}
+
+// timingTrace records the phases of a request through httptrace hooks, which
+// may be called from the transport's dialing goroutines
+type timingTrace struct {
+	mu            sync.Mutex
+	dnsStart      time.Time
+	connectStarts map[string]time.Time
+	tlsStart      time.Time
+	wroteRequest  time.Time
+	timing        Timing
+}
+
+// newTimingTrace creates a trace for a single request attempt
+func newTimingTrace() *timingTrace {
+	return &timingTrace{connectStarts: make(map[string]time.Time)}
+}
+
+// clientTrace returns the hooks recording the phases of the request. When
+// several addresses are dialed, the connection that succeeded is timed.
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if !t.dnsStart.IsZero() {
+				t.timing.DNS = time.Since(t.dnsStart)
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectStarts[network+" "+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if start, ok := t.connectStarts[network+" "+addr]; ok && err == nil {
+				t.timing.Connect = time.Since(start)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if !t.tlsStart.IsZero() {
+				t.timing.TLS = time.Since(t.tlsStart)
+			}
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.wroteRequest = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if !t.wroteRequest.IsZero() {
+				t.timing.TTFB = time.Since(t.wroteRequest)
+			}
+		},
+	}
+}
+
+// result returns the phases recorded so far
+func (t *timingTrace) result() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.timing
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"time"
+)
+
+// TLSCertificate describes the leaf certificate presented by an HTTPS server
+type TLSCertificate struct {
+	NotAfter    time.Time `json:"not_after"`
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	Fingerprint string    `json:"fingerprint"` // Hex-encoded SHA-256 of the DER certificate
+}
+
+// newTLSCertificate extracts the peer's leaf certificate from a connection
+// state. It returns nil for plain HTTP responses.
+func newTLSCertificate(state *tls.ConnectionState) *TLSCertificate {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	leaf := state.PeerCertificates[0]
+	fingerprint := sha256.Sum256(leaf.Raw)
+
+	return &TLSCertificate{
+		NotAfter:    leaf.NotAfter,
+		Issuer:      leaf.Issuer.String(),
+		Subject:     leaf.Subject.String(),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+	}
+}
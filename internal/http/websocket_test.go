@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newWebSocketServer answers WebSocket handshakes, computing the accept value
+// with the given function
+func newWebSocketServer(t *testing.T, accept func(key string) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			t.Errorf("Expected a WebSocket handshake, got %s with Upgrade %q", r.Method, r.Header.Get("Upgrade"))
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Failed to hijack connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+		rw.WriteString("Upgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + accept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n")
+		rw.WriteString("Sec-WebSocket-Protocol: " + r.Header.Get("Sec-WebSocket-Protocol") + "\r\n\r\n")
+		rw.Flush()
+	}))
+}
+
+func TestHTTPClient_DoWebSocketHandshake(t *testing.T) {
+	tests := []struct {
+		name           string
+		accept         func(key string) string
+		expectedAccept string
+	}{
+		{
+			name:           "valid accept",
+			accept:         websocketAccept,
+			expectedAccept: WebSocketAcceptValid,
+		},
+		{
+			name:           "wrong accept",
+			accept:         func(string) string { return "bogus" },
+			expectedAccept: "bogus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebSocketServer(t, tt.accept)
+			defer server.Close()
+
+			wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/socket"
+			req, err := NewRequest("POST", wsURL, nil, map[string]string{"Sec-WebSocket-Protocol": "chat.v2"})
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+			if req.Method != http.MethodGet || req.URL.Scheme != "http" {
+				t.Errorf("Expected a GET over http, got %s over %s", req.Method, req.URL.Scheme)
+			}
+
+			response, err := NewHTTPClient(nil).Do(req)
+			if err != nil {
+				t.Fatalf("Do failed: %v", err)
+			}
+
+			if response.StatusCode != http.StatusSwitchingProtocols {
+				t.Errorf("Expected status 101, got %d", response.StatusCode)
+			}
+			if accept := response.Headers.Get("Sec-WebSocket-Accept"); accept != tt.expectedAccept {
+				t.Errorf("Expected Sec-WebSocket-Accept %q, got %q", tt.expectedAccept, accept)
+			}
+			if protocol := response.Headers.Get("Sec-WebSocket-Protocol"); protocol != "chat.v2" {
+				t.Errorf("Expected negotiated protocol chat.v2, got %q", protocol)
+			}
+		})
+	}
+}
+
+func TestIsWebSocketURL(t *testing.T) {
+	for rawURL, expected := range map[string]bool{
+		"ws://api.example.com/socket":  true,
+		"wss://api.example.com/socket": true,
+		"https://api.example.com":      false,
+		"://invalid":                   false,
+	} {
+		if got := IsWebSocketURL(rawURL); got != expected {
+			t.Errorf("IsWebSocketURL(%q) = %v, expected %v", rawURL, got, expected)
+		}
+	}
+}
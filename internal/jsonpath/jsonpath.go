@@ -0,0 +1,120 @@
+// Package jsonpath provides a minimal JSONPath implementation for selecting
+// values from decoded JSON documents
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Segment represents a single step in a JSONPath expression
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// Path represents a parsed JSONPath expression
+type Path []Segment
+
+// Parse parses a JSONPath expression such as "$.data.items[0]['display name']".
+// Only child member access and array indexing are supported.
+func Parse(expr string) (Path, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with '$'", expr)
+	}
+
+	var path Path
+	rest := expr[1:]
+
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", expr)
+			}
+			if key == "*" {
+				return nil, fmt.Errorf("invalid JSONPath %q: wildcards are not supported", expr)
+			}
+			path = append(path, Segment{Key: key})
+			rest = rest[end:]
+
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unterminated bracket", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path = append(path, Segment{Key: inner[1 : len(inner)-1]})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unsupported selector [%s]", expr, inner)
+			}
+			path = append(path, Segment{Index: index, IsIndex: true})
+
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected character %q", expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// String returns the canonical form of the path, matching the paths reported by drift detection
+func (p Path) String() string {
+	var builder strings.Builder
+	builder.WriteString("$")
+
+	for _, segment := range p {
+		if segment.IsIndex {
+			builder.WriteString(fmt.Sprintf("[%d]", segment.Index))
+			continue
+		}
+		builder.WriteString(".")
+		builder.WriteString(segment.Key)
+	}
+
+	return builder.String()
+}
+
+// Lookup returns the value at the path within data, reporting whether it exists
+func (p Path) Lookup(data interface{}) (interface{}, bool) {
+	current := data
+
+	for _, segment := range p {
+		if segment.IsIndex {
+			array, ok := current.([]interface{})
+			if !ok || segment.Index >= len(array) {
+				return nil, false
+			}
+			current = array[segment.Index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, exists := object[segment.Key]
+		if !exists {
+			return nil, false
+		}
+		current = value
+	}
+
+	return current, true
+}
@@ -0,0 +1,73 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		expected    string
+		expectError bool
+	}{
+		{"root", "$", "$", false},
+		{"member", "$.data", "$.data", false},
+		{"nested member", "$.data.schema", "$.data.schema", false},
+		{"array index", "$.items[2].id", "$.items[2].id", false},
+		{"quoted member", "$['display name']", "$.display name", false},
+		{"double quoted member", `$["data"].id`, "$.data.id", false},
+		{"missing root", "data.schema", "", true},
+		{"empty member", "$..data", "", true},
+		{"wildcard", "$.items.*", "", true},
+		{"unterminated bracket", "$.items[0", "", true},
+		{"negative index", "$.items[-1]", "", true},
+		{"filter expression", "$.items[?(@.id)]", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := Parse(tt.expr)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path.String())
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"data": {"items": [{"id": 1}, {"id": 2}], "name": null}}`), &data))
+
+	tests := []struct {
+		expr     string
+		expected interface{}
+		found    bool
+	}{
+		{"$", data, true},
+		{"$.data.items[1].id", float64(2), true},
+		{"$.data.name", nil, true},
+		{"$.data.items[5]", nil, false},
+		{"$.data.missing", nil, false},
+		{"$.data.items.id", nil, false},
+		{"$.data[0]", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			path, err := Parse(tt.expr)
+			require.NoError(t, err)
+
+			value, found := path.Lookup(data)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package mock serves example responses generated from an OpenAPI specification
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/spec"
+	"github.com/k0ns0l/driftwatch/internal/validator"
+)
+
+// Handler serves example responses for the operations of a specification
+type Handler struct {
+	spec *spec.Swagger
+}
+
+// NewHandler creates a mock handler for an expanded specification
+func NewHandler(swagger *spec.Swagger) *Handler {
+	return &Handler{spec: swagger}
+}
+
+// ServeHTTP responds with the example response of the matching operation
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operation := validator.FindOperation(h.spec, r.Method, r.URL.Path)
+	if operation == nil {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"error": fmt.Sprintf("no operation defined for %s %s", r.Method, r.URL.Path),
+		})
+		return
+	}
+
+	statusCode, response := validator.SuccessResponse(operation)
+	if response == nil {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	for name, header := range response.Headers {
+		if header.Default != nil {
+			w.Header().Set(name, fmt.Sprint(header.Default))
+		}
+	}
+
+	if example, ok := response.Examples["application/json"]; ok {
+		writeJSON(w, statusCode, example)
+		return
+	}
+
+	if response.Schema == nil {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	writeJSON(w, statusCode, validator.GenerateExample(response.Schema))
+}
+
+// writeJSON writes a JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode example: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/k0ns0l/driftwatch/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	swagger, err := validator.NewValidator().LoadSpec("../validator/testdata/complex-api.yaml")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(NewHandler(swagger))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandlerServesSchemaExample(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/v2/products/abc")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "string", body["id"])
+	assert.Equal(t, "2024-01-01T00:00:00Z", body["created_at"])
+	assert.Contains(t, body, "category")
+}
+
+func TestHandlerUnknownOperation(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/v2/orders")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodDelete, server.URL+"/v2/products", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// heartbeatTimeout bounds a ping of the heartbeat URL
+const heartbeatTimeout = 10 * time.Second
+
+// heartbeatAfterCheck records a heartbeat once a check has completed, at most
+// once per heartbeat interval. Heartbeats follow completed checks rather than a
+// timer of their own, so that they stop not only when the scheduler stops but
+// also when its checks hang.
+func (s *CronScheduler) heartbeatAfterCheck() {
+	if !s.config.Heartbeat.Enabled {
+		return
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	due := !now.Before(s.nextHeartbeat)
+	if due {
+		s.nextHeartbeat = now.Add(s.config.Heartbeat.Interval)
+	}
+	s.mu.Unlock()
+
+	if due {
+		s.heartbeat()
+	}
+}
+
+// heartbeat records that the scheduler is alive in storage and, when configured,
+// in the heartbeat file, then pings the heartbeat URL. The URL is only pinged
+// once the heartbeat has been recorded, so that external monitoring also
+// alerts when the monitor can no longer write its results.
+func (s *CronScheduler) heartbeat() {
+	beatAt := time.Now()
+
+	if err := s.storage.SaveHeartbeat(beatAt); err != nil {
+		s.logger.Printf("Failed to record heartbeat: %v", err)
+		return
+	}
+
+	if file := s.config.Heartbeat.File; file != "" {
+		if err := os.WriteFile(file, []byte(beatAt.Format(time.RFC3339)+"\n"), 0o644); err != nil {
+			s.logger.Printf("Failed to write heartbeat file %s: %v", file, err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.lastHeartbeat = beatAt
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if url := s.config.Heartbeat.URL; url != "" {
+		if err := pingHeartbeatURL(ctx, url); err != nil {
+			s.logger.Printf("Failed to ping heartbeat URL: %v", err)
+		}
+	}
+}
+
+// pingHeartbeatURL notifies a dead man's switch, such as a healthchecks.io
+// check, that the monitor is alive
+func pingHeartbeatURL(ctx context.Context, url string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, heartbeatTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create heartbeat request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat URL returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
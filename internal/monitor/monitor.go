@@ -6,12 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"sync"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/alerting"
 	"github.com/k0ns0l/driftwatch/internal/auth"
 	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
+	"github.com/k0ns0l/driftwatch/internal/errors"
 	httpClient "github.com/k0ns0l/driftwatch/internal/http"
 	"github.com/k0ns0l/driftwatch/internal/logging"
 	"github.com/k0ns0l/driftwatch/internal/storage"
@@ -68,6 +72,7 @@ type MonitorStatus struct {
 type SchedulerStatus struct {
 	StartedAt          time.Time                 `json:"started_at,omitempty"`
 	LastCheckAt        time.Time                 `json:"last_check_at,omitempty"`
+	LastHeartbeat      time.Time                 `json:"last_heartbeat,omitempty"`
 	EndpointStatuses   map[string]EndpointStatus `json:"endpoint_statuses"`
 	EndpointsScheduled int                       `json:"endpoints_scheduled"`
 	Running            bool                      `json:"running"`
@@ -75,30 +80,57 @@ type SchedulerStatus struct {
 
 // EndpointStatus represents the status of a single endpoint
 type EndpointStatus struct {
-	ID         string    `json:"id"`
-	LastError  string    `json:"last_error,omitempty"`
-	LastCheck  time.Time `json:"last_check,omitempty"`
-	CheckCount int64     `json:"check_count"`
-	ErrorCount int64     `json:"error_count"`
-	LastStatus int       `json:"last_status,omitempty"`
-	Enabled    bool      `json:"enabled"`
+	ID                  string    `json:"id"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailureCategory string    `json:"last_failure_category,omitempty"`
+	LastCheck           time.Time `json:"last_check,omitempty"`
+	CheckCount          int64     `json:"check_count"`
+	ErrorCount          int64     `json:"error_count"`
+	LastStatus          int       `json:"last_status,omitempty"`
+	Enabled             bool      `json:"enabled"`
+
+	// SuppressedDrifts counts drifts below the minimum persist severity that
+	// were detected but not stored
+	SuppressedDrifts int64 `json:"suppressed_drifts"`
+
+	// SkippedOverlaps counts checks that were skipped because the previous
+	// check of the endpoint was still running
+	SkippedOverlaps int64 `json:"skipped_overlaps"`
 }
 
+// certificateHistoryWindow bounds how far back the previous certificate of an
+// endpoint is looked up when checking for certificate changes
+const certificateHistoryWindow = 7 * 24 * time.Hour
+
+// versionHistoryWindow bounds how far back the previous response of an
+// endpoint is looked up when checking for version changes
+const versionHistoryWindow = 7 * 24 * time.Hour
+
+// authHistoryWindow bounds how far back the previous response status of an
+// endpoint is looked up when checking for rejected credentials
+const authHistoryWindow = 7 * 24 * time.Hour
+
 // CronScheduler implements the Scheduler interface using cron for scheduling
 type CronScheduler struct {
 	cron           *cron.Cron
 	endpoints      map[string]*config.EndpointConfig
 	endpointJobs   map[string]cron.EntryID
 	endpointStatus map[string]*EndpointStatus
+	storedBodies   map[string]storedBody // last body stored in full, by endpoint ID
+	inFlight       map[string]bool       // endpoints with a check running, by ID
+	compareCache   *drift.ComparisonCache
 	httpClient     httpClient.Client
 	storage        storage.Storage
 	config         *config.Config
 	authManager    *auth.Manager
+	alertManager   alerting.AlertManager
 	logger         *log.Logger
 	ctx            context.Context
 	cancel         context.CancelFunc
 	startedAt      time.Time
 	lastCheckAt    time.Time
+	lastHeartbeat  time.Time
+	nextHeartbeat  time.Time // completed checks record a heartbeat from then on
 	mu             sync.RWMutex
 	running        bool
 }
@@ -117,11 +149,20 @@ func NewCronScheduler(cfg *config.Config, storage storage.Storage, httpClient ht
 		loggingLogger = logging.GetGlobalLogger()
 	}
 
+	// Samples of stable endpoints are compared the same way check after check
+	var compareCache *drift.ComparisonCache
+	if cfg.Global.ComparisonCacheSize > 0 {
+		compareCache = drift.NewComparisonCache(cfg.Global.ComparisonCacheSize)
+	}
+
 	return &CronScheduler{
 		cron:           cron.New(cron.WithSeconds()),
 		endpoints:      make(map[string]*config.EndpointConfig),
 		endpointJobs:   make(map[string]cron.EntryID),
 		endpointStatus: make(map[string]*EndpointStatus),
+		storedBodies:   make(map[string]storedBody),
+		inFlight:       make(map[string]bool),
+		compareCache:   compareCache,
 		httpClient:     httpClient,
 		storage:        storage,
 		config:         cfg,
@@ -130,6 +171,14 @@ func NewCronScheduler(cfg *config.Config, storage storage.Storage, httpClient ht
 	}
 }
 
+// SetAlertManager routes drifts detected by the scheduler through an alert
+// manager, which stores them and alerts on them. Without one, drifts are only stored.
+func (s *CronScheduler) SetAlertManager(manager alerting.AlertManager) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alertManager = manager
+}
+
 // Start begins the monitoring scheduler
 func (s *CronScheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -152,6 +201,10 @@ func (s *CronScheduler) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to load endpoints: %w", err)
 	}
 
+	if s.config.Heartbeat.Enabled {
+		s.logger.Printf("Recording a heartbeat after completed checks, at most every %s", s.config.Heartbeat.Interval)
+	}
+
 	// Start the cron scheduler
 	s.cron.Start()
 	s.logger.Printf("Scheduler started with %d endpoints", len(s.endpoints))
@@ -272,6 +325,7 @@ func (s *CronScheduler) GetStatus() SchedulerStatus {
 		StartedAt:          s.startedAt,
 		EndpointsScheduled: len(s.endpoints),
 		LastCheckAt:        s.lastCheckAt,
+		LastHeartbeat:      s.lastHeartbeat,
 		EndpointStatuses:   statuses,
 	}
 }
@@ -349,11 +403,13 @@ func (s *CronScheduler) CheckOnce(ctx context.Context) error {
 
 // loadEndpoints loads endpoints from storage and configuration
 func (s *CronScheduler) loadEndpoints() error {
-	s.logger.Printf("Loading %d endpoints from configuration", len(s.config.Endpoints))
+	// Endpoints with variants are checked once per variant
+	configEndpoints := s.config.ExpandedEndpoints()
+	s.logger.Printf("Loading %d endpoints from configuration", len(configEndpoints))
 	var errors []error
 
 	// First, load from configuration and save to database if not already present
-	for _, endpointConfig := range s.config.Endpoints {
+	for _, endpointConfig := range configEndpoints {
 		// Check if endpoint IF already exists in database
 		_, err := s.storage.GetEndpoint(endpointConfig.ID)
 		if err != nil {
@@ -427,7 +483,9 @@ func (s *CronScheduler) loadEndpoints() error {
 	return nil
 }
 
-// checkEndpoint performs a single endpoint check
+// checkEndpoint performs a single endpoint check. A check started while the
+// previous check of the same endpoint is still running, because the endpoint
+// responds slower than its interval, is skipped rather than overlapped.
 func (s *CronScheduler) checkEndpoint(endpoint *config.EndpointConfig) {
 	start := time.Now()
 
@@ -440,9 +498,24 @@ func (s *CronScheduler) checkEndpoint(endpoint *config.EndpointConfig) {
 		}
 		s.endpointStatus[endpoint.ID] = status
 	}
+	if s.inFlight[endpoint.ID] {
+		status.SkippedOverlaps++
+		s.mu.Unlock()
+		s.logger.Printf("Skipped check of endpoint %s: the previous check is still running", endpoint.ID)
+		return
+	}
+	s.inFlight[endpoint.ID] = true
 	s.lastCheckAt = start
 	s.mu.Unlock()
 
+	defer func() {
+		s.mu.Lock()
+		delete(s.inFlight, endpoint.ID)
+		s.mu.Unlock()
+
+		s.heartbeatAfterCheck()
+	}()
+
 	// Update status
 	status.LastCheck = start
 	status.CheckCount++
@@ -453,22 +526,7 @@ func (s *CronScheduler) checkEndpoint(endpoint *config.EndpointConfig) {
 		var err error
 		authenticator, err = s.authManager.CreateAuthenticator(endpoint.Auth)
 		if err != nil {
-			s.handleCheckError(status, fmt.Errorf("failed to create authenticator: %w", err))
-			return
-		}
-	}
-
-	// Create HTTP request
-	req, err := httpClient.NewRequest(endpoint.Method, endpoint.URL, nil, endpoint.Headers)
-	if err != nil {
-		s.handleCheckError(status, fmt.Errorf("failed to create request: %w", err))
-		return
-	}
-
-	// Apply authentication if configured
-	if authenticator != nil {
-		if err := authenticator.ApplyAuth(req); err != nil {
-			s.handleCheckError(status, fmt.Errorf("failed to apply authentication: %w", err))
+			s.handleCheckError(endpoint, status, start, errors.FailureCategoryConfig, fmt.Errorf("failed to create authenticator: %w", err))
 			return
 		}
 	}
@@ -485,49 +543,53 @@ func (s *CronScheduler) checkEndpoint(endpoint *config.EndpointConfig) {
 		parentCtx = context.Background()
 	}
 
-	ctx, cancel := context.WithTimeout(parentCtx, timeout)
-	defer cancel()
-
-	// Perform request
-	resp, err := s.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		s.handleCheckError(status, fmt.Errorf("request failed: %w", err))
-		return
+	// Take the configured number of samples; the last response is recorded
+	// along with the fields that varied between them
+	sampleCount := endpoint.Samples
+	if sampleCount < 1 {
+		sampleCount = 1
 	}
 
-	// Update status with success
-	status.LastStatus = resp.StatusCode
-	status.LastError = ""
-
-	// Verify endpoint exists in database before saving monitoring run
-	_, err = s.storage.GetEndpoint(endpoint.ID)
-	if err != nil {
-		s.logger.Printf("Endpoint %s not found in database, attempting to save it before monitoring run", endpoint.ID)
+	var resp *httpClient.Response
+	samples := make([]*drift.Response, 0, sampleCount)
+	for sample := 0; sample < sampleCount; sample++ {
+		if sample > 0 {
+			select {
+			case <-parentCtx.Done():
+				s.logger.Printf("Check of endpoint %s cancelled after %d of %d samples", endpoint.ID, sample, sampleCount)
+				return
+			case <-time.After(config.SampleDelay):
+			}
+		}
 
-		// Try to save the endpoint to database
-		configJSON, marshalErr := json.Marshal(endpoint)
-		if marshalErr != nil {
-			s.logger.Printf("Failed to marshal config for endpoint %s: %v", endpoint.ID, marshalErr)
+		var category errors.FailureCategory
+		var err error
+		resp, category, err = s.sendRequest(parentCtx, endpoint, authenticator, timeout)
+		if err != nil {
+			s.handleCheckError(endpoint, status, start, category, err)
 			return
 		}
+		samples = append(samples, &drift.Response{
+			StatusCode: resp.StatusCode,
+			Headers:    s.convertHeaders(resp.Headers),
+			Body:       resp.Body,
+			Truncated:  resp.Truncated,
+		})
+	}
 
-		dbEndpoint := &storage.Endpoint{
-			ID:        endpoint.ID,
-			URL:       endpoint.URL,
-			Method:    endpoint.Method,
-			SpecFile:  endpoint.SpecFile,
-			Config:    string(configJSON),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		}
+	volatileFields, err := drift.FindVaryingFields(samples, s.compareCache)
+	if err != nil {
+		s.logger.Printf("Failed to compare samples of %s: %v", endpoint.ID, err)
+	}
 
-		if saveErr := s.storage.SaveEndpoint(dbEndpoint); saveErr != nil {
-			s.logger.Printf("Failed to save endpoint %s to database: %v", endpoint.ID, saveErr)
-			s.logger.Printf("Skipping monitoring run save for %s due to database constraint", endpoint.ID)
-			return
-		} else {
-			s.logger.Printf("Successfully saved endpoint %s to database", endpoint.ID)
-		}
+	// Update status with success; server errors still count as API failures
+	failureCategory := errors.ClassifyStatusCode(resp.StatusCode)
+	status.LastStatus = resp.StatusCode
+	status.LastError = ""
+	status.LastFailureCategory = string(failureCategory)
+
+	if !s.ensureEndpointSaved(endpoint) {
+		return
 	}
 
 	// Save monitoring run to storage
@@ -538,21 +600,385 @@ func (s *CronScheduler) checkEndpoint(endpoint *config.EndpointConfig) {
 		ResponseTimeMs:  resp.ResponseTime.Milliseconds(),
 		ResponseBody:    string(resp.Body),
 		ResponseHeaders: s.convertHeaders(resp.Headers),
+		FailureCategory: string(failureCategory),
+		SampleCount:     sampleCount,
+		VolatileFields:  volatileFields,
+		Protocol:        resp.Protocol,
+		DNSTimeMs:       resp.Timing.DNS.Milliseconds(),
+		ConnectTimeMs:   resp.Timing.Connect.Milliseconds(),
+		TLSTimeMs:       resp.Timing.TLS.Milliseconds(),
+		TTFBMs:          resp.Timing.TTFB.Milliseconds(),
+
+		ResponseTruncated: resp.Truncated,
+	}
+	if len(resp.Trailers) > 0 {
+		run.ResponseTrailers = s.convertHeaders(resp.Trailers)
+	}
+	if failureCategory != "" {
+		run.ErrorMessage = fmt.Sprintf("server returned status %d", resp.StatusCode)
+	}
+
+	// Look up the previous certificate before this run is stored
+	var previousCertificate *drift.Certificate
+	if resp.TLS != nil {
+		run.TLSNotAfter = &resp.TLS.NotAfter
+		run.TLSIssuer = resp.TLS.Issuer
+		run.TLSFingerprint = resp.TLS.Fingerprint
+		previousCertificate = s.previousCertificate(endpoint.ID)
 	}
 
+	// Likewise the previous response, whose version field the new one is compared
+	// with. Truncated stream bodies are not valid JSON, so their fields are not checked.
+	comparesBody := run.Succeeded() && failureCategory == "" && !resp.Truncated &&
+		endpoint.Validation.ComparesBody(resp.StatusCode)
+	checkVersion := comparesBody && endpoint.Validation.VersionField != ""
+	var previousBody []byte
+	if checkVersion {
+		previousBody = s.previousResponseBody(endpoint)
+	}
+
+	// And the previous status, when credentials may just have been rejected
+	checkAuth := endpoint.Auth != nil && endpoint.Auth.Type != config.AuthTypeNone &&
+		(resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden)
+	var previousStatus int
+	if checkAuth {
+		previousStatus = s.previousResponseStatus(endpoint.ID)
+	}
+
+	// Stable bodies are shared with the last run that stored them in full
+	bodyHash := s.sampleResponseBody(endpoint, run)
 	if err := s.storage.SaveMonitoringRun(run); err != nil {
 		s.logger.Printf("Failed to save monitoring run for %s: %v", endpoint.ID, err)
+	} else {
+		s.rememberStoredBody(endpoint, run, bodyHash)
+	}
+
+	// The drift found by the checks of this cycle is recorded together, so that
+	// it is limited and alerted on as a single check
+	var results []*drift.DiffResult
+	if resp.TLS != nil {
+		results = append(results, s.checkCertificate(previousCertificate, runCertificate(run)))
+	}
+
+	if comparesBody && len(endpoint.Validation.RequiredFields) > 0 {
+		results = append(results, s.checkRequiredFields(endpoint, resp.Body))
+	}
+
+	if checkVersion && previousBody != nil {
+		results = append(results, s.checkVersionField(endpoint, previousBody, resp.Body, start))
+	}
+
+	if checkAuth && previousStatus != 0 {
+		results = append(results, drift.CompareAuthStatus(previousStatus, resp.StatusCode))
+	}
+
+	if result := drift.MergeResults(results...); result.HasChanges {
+		s.recordDrift(parentCtx, endpoint, result, start)
+	}
+
+	s.logger.Printf("Checked endpoint %s: %d (%s, %d samples)",
+		endpoint.ID, resp.StatusCode, time.Since(start), sampleCount)
+}
+
+// previousCertificate returns the certificate recorded by the most recent run
+// within certificateHistoryWindow, or nil if there is none
+func (s *CronScheduler) previousCertificate(endpointID string) *drift.Certificate {
+	runs, err := s.storage.GetMonitoringHistory(endpointID, certificateHistoryWindow)
+	if err != nil {
+		s.logger.Printf("Failed to get monitoring history for %s: %v", endpointID, err)
+		return nil
+	}
+
+	// Runs are returned newest first
+	for _, run := range runs {
+		if certificate := runCertificate(run); certificate != nil {
+			return certificate
+		}
+	}
+	return nil
+}
+
+// checkCertificate returns the drift for certificate changes and certificates
+// nearing expiry
+func (s *CronScheduler) checkCertificate(previous, current *drift.Certificate) *drift.DiffResult {
+	return drift.CompareCertificates(previous, current, s.config.Global.TLSExpiryWarning)
+}
+
+// checkRequiredFields returns the drift for configured required fields that are
+// missing from a successful response
+func (s *CronScheduler) checkRequiredFields(endpoint *config.EndpointConfig, body []byte) *drift.DiffResult {
+	return drift.CheckRequiredFields(body, endpoint.Validation.RequiredFields)
+}
+
+// previousResponseBody returns the body of the most recent successful run
+// within versionHistoryWindow whose status code is compared by body, or nil if
+// there is none
+func (s *CronScheduler) previousResponseBody(endpoint *config.EndpointConfig) []byte {
+	runs, err := s.storage.GetMonitoringHistory(endpoint.ID, versionHistoryWindow)
+	if err != nil {
+		s.logger.Printf("Failed to get monitoring history for %s: %v", endpoint.ID, err)
+		return nil
+	}
+
+	// Runs are returned newest first
+	for _, run := range runs {
+		if run.Succeeded() && run.FailureCategory == "" && endpoint.Validation.ComparesBody(run.ResponseStatus) {
+			return []byte(run.ResponseBody)
+		}
+	}
+	return nil
+}
+
+// previousResponseStatus returns the status code of the most recent run within
+// authHistoryWindow that received a response, or 0 if there is none
+func (s *CronScheduler) previousResponseStatus(endpointID string) int {
+	runs, err := s.storage.GetMonitoringHistory(endpointID, authHistoryWindow)
+	if err != nil {
+		s.logger.Printf("Failed to get monitoring history for %s: %v", endpointID, err)
+		return 0
+	}
+
+	// Runs are returned newest first
+	for _, run := range runs {
+		if run.ResponseStatus != 0 {
+			return run.ResponseStatus
+		}
+	}
+	return 0
+}
+
+// checkVersionField returns the drift for a change of the endpoint's version
+// field. When configured, the drift recorded before the new version is
+// acknowledged first, as the new version is the new baseline.
+func (s *CronScheduler) checkVersionField(endpoint *config.EndpointConfig, previousBody, currentBody []byte, checkedAt time.Time) *drift.DiffResult {
+	result := drift.CompareVersionFields(previousBody, currentBody, endpoint.Validation.VersionField,
+		drift.Severity(endpoint.Validation.VersionChangeSeverity))
+	if !result.HasChanges {
+		return result
+	}
+
+	if endpoint.Validation.AcknowledgeOnVersionChange {
+		acknowledged, err := s.storage.AcknowledgeDrifts(endpoint.ID, checkedAt)
+		if err != nil {
+			s.logger.Printf("Failed to acknowledge drifts of %s: %v", endpoint.ID, err)
+		} else if acknowledged > 0 {
+			s.logger.Printf("Acknowledged %d drifts of %s after its version changed", acknowledged, endpoint.ID)
+		}
+	}
+
+	return result
+}
+
+// recordDrift hands the changes found by a check to the alert manager, which
+// stores and alerts on them, or stores them directly when alerting is not set up.
+// Changes below the endpoint's minimum persist severity are only counted, and
+// changes detected during a maintenance window are stored tagged without alerting.
+func (s *CronScheduler) recordDrift(ctx context.Context, endpoint *config.EndpointConfig, result *drift.DiffResult, detectedAt time.Time) {
+	result, suppressed := drift.FilterBySeverity(result, drift.Severity(s.minPersistSeverity(endpoint)))
+	if suppressed > 0 {
+		s.mu.Lock()
+		if status := s.endpointStatus[endpoint.ID]; status != nil {
+			status.SuppressedDrifts += int64(suppressed)
+		}
+		s.mu.Unlock()
+	}
+	if !result.HasChanges {
+		return
+	}
+
+	storedEndpoint, err := s.storage.GetEndpoint(endpoint.ID)
+	if err != nil {
+		s.logger.Printf("Failed to get endpoint %s: %v", endpoint.ID, err)
+		return
+	}
+
+	window := s.activeMaintenanceWindow(endpoint.ID, detectedAt)
+
+	s.mu.RLock()
+	alertManager := s.alertManager
+	s.mu.RUnlock()
+
+	if alertManager != nil && window == nil {
+		if err := alertManager.ProcessDrift(ctx, result, storedEndpoint); err != nil {
+			s.logger.Printf("Failed to process drift for %s: %v", endpoint.ID, err)
+		}
+		return
+	}
+
+	records := make([]*storage.Drift, 0, len(result.StructuralChanges))
+	for _, change := range result.StructuralChanges {
+		record := &storage.Drift{
+			EndpointID:  endpoint.ID,
+			DetectedAt:  detectedAt,
+			DriftType:   string(change.Type),
+			Severity:    string(change.Severity),
+			Description: change.Description,
+			FieldPath:   change.Path,
+		}
+		if change.OldValue != nil {
+			record.BeforeValue = fmt.Sprintf("%v", change.OldValue)
+		}
+		if change.NewValue != nil {
+			record.AfterValue = fmt.Sprintf("%v", change.NewValue)
+		}
+		if window != nil {
+			record.Tag = storage.DriftTagMaintenance
+			record.Acknowledged = window.AutoAcknowledge
+		}
+		records = append(records, record)
+	}
+
+	for _, record := range alerting.LimitDrifts(records, s.config.Global.MaxDriftsPerCheck) {
+		if err := s.storage.SaveDrift(record); err != nil {
+			s.logger.Printf("Failed to save drift for %s: %v", endpoint.ID, err)
+		}
+	}
+}
+
+// activeMaintenanceWindow returns the maintenance window covering an endpoint
+// at the given time, or nil if there is none. Windows are kept in storage so
+// that they survive a restart of the daemon.
+func (s *CronScheduler) activeMaintenanceWindow(endpointID string, at time.Time) *storage.MaintenanceWindow {
+	windows, err := s.storage.GetActiveMaintenanceWindows(at)
+	if err != nil {
+		s.logger.Printf("Failed to get maintenance windows: %v", err)
+		return nil
+	}
+
+	for _, window := range windows {
+		if window.Covers(endpointID, at) {
+			return window
+		}
+	}
+	return nil
+}
+
+// minPersistSeverity returns the lowest severity of drift stored for an
+// endpoint, or "" to store every drift
+func (s *CronScheduler) minPersistSeverity(endpoint *config.EndpointConfig) string {
+	if endpoint.MinPersistSeverity != "" {
+		return endpoint.MinPersistSeverity
+	}
+	return s.config.Global.MinPersistSeverity
+}
+
+// runCertificate returns the certificate recorded by a run, or nil if none was
+func runCertificate(run *storage.MonitoringRun) *drift.Certificate {
+	if run.TLSNotAfter == nil || run.TLSFingerprint == "" {
+		return nil
+	}
+
+	return &drift.Certificate{
+		ObservedAt:  run.Timestamp,
+		NotAfter:    *run.TLSNotAfter,
+		Issuer:      run.TLSIssuer,
+		Fingerprint: run.TLSFingerprint,
+	}
+}
+
+// sendRequest performs a single request for an endpoint. On failure it returns
+// the category of the failure along with the error.
+func (s *CronScheduler) sendRequest(ctx context.Context, endpoint *config.EndpointConfig, authenticator auth.Authenticator, timeout time.Duration) (*httpClient.Response, errors.FailureCategory, error) {
+	// Create HTTP request
+	req, err := httpClient.NewRequest(endpoint.Method, endpoint.URL, nil, endpoint.Headers)
+	if err != nil {
+		return nil, errors.FailureCategoryConfig, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Apply authentication if configured
+	if authenticator != nil {
+		if err := authenticator.ApplyAuth(req); err != nil {
+			return nil, errors.FailureCategoryConfig, fmt.Errorf("failed to apply authentication: %w", err)
+		}
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reqCtx, err = httpClient.WithProxy(reqCtx, endpoint.ProxyURL)
+	if err != nil {
+		return nil, errors.FailureCategoryConfig, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+
+	caFile := endpoint.CAFile
+	if caFile == "" {
+		caFile = s.config.Global.CABundleFile
+	}
+	reqCtx = httpClient.WithCABundle(reqCtx, caFile)
+	reqCtx = httpClient.WithPhaseTimeouts(reqCtx, endpoint.ConnectTimeout, endpoint.ReadTimeout)
+
+	reqCtx, err = httpClient.WithStreamReadLimit(reqCtx, endpoint.StreamReadLimit)
+	if err != nil {
+		return nil, errors.FailureCategoryConfig, fmt.Errorf("invalid stream read limit: %w", err)
+	}
+
+	// Perform request
+	resp, err := s.httpClient.Do(req.WithContext(reqCtx))
+	if err != nil {
+		return nil, errors.ClassifyFailure(err), fmt.Errorf("request failed: %w", err)
+	}
+
+	return resp, "", nil
+}
+
+// ensureEndpointSaved makes sure the endpoint exists in the database so that
+// monitoring runs can reference it. It reports whether runs can be saved.
+func (s *CronScheduler) ensureEndpointSaved(endpoint *config.EndpointConfig) bool {
+	if _, err := s.storage.GetEndpoint(endpoint.ID); err == nil {
+		return true
+	}
+
+	s.logger.Printf("Endpoint %s not found in database, attempting to save it before monitoring run", endpoint.ID)
+
+	// Try to save the endpoint to database
+	configJSON, marshalErr := json.Marshal(endpoint)
+	if marshalErr != nil {
+		s.logger.Printf("Failed to marshal config for endpoint %s: %v", endpoint.ID, marshalErr)
+		return false
+	}
+
+	dbEndpoint := &storage.Endpoint{
+		ID:        endpoint.ID,
+		URL:       endpoint.URL,
+		Method:    endpoint.Method,
+		SpecFile:  endpoint.SpecFile,
+		Config:    string(configJSON),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if saveErr := s.storage.SaveEndpoint(dbEndpoint); saveErr != nil {
+		s.logger.Printf("Failed to save endpoint %s to database: %v", endpoint.ID, saveErr)
+		s.logger.Printf("Skipping monitoring run save for %s due to database constraint", endpoint.ID)
+		return false
 	}
 
-	s.logger.Printf("Checked endpoint %s: %d (%s)",
-		endpoint.ID, resp.StatusCode, time.Since(start))
+	s.logger.Printf("Successfully saved endpoint %s to database", endpoint.ID)
+	return true
 }
 
-// handleCheckError handles errors during endpoint checks
-func (s *CronScheduler) handleCheckError(status *EndpointStatus, err error) {
+// handleCheckError records a failed endpoint check along with the category of the failure
+func (s *CronScheduler) handleCheckError(endpoint *config.EndpointConfig, status *EndpointStatus, start time.Time, category errors.FailureCategory, err error) {
 	status.ErrorCount++
 	status.LastError = err.Error()
-	s.logger.Printf("Error checking endpoint %s: %v", status.ID, err)
+	status.LastFailureCategory = string(category)
+	s.logger.Printf("Error checking endpoint %s (%s): %v", status.ID, category, err)
+
+	if !s.ensureEndpointSaved(endpoint) {
+		return
+	}
+
+	run := &storage.MonitoringRun{
+		EndpointID:      endpoint.ID,
+		Timestamp:       start,
+		ResponseTimeMs:  time.Since(start).Milliseconds(),
+		FailureCategory: string(category),
+		ErrorMessage:    err.Error(),
+	}
+
+	if err := s.storage.SaveMonitoringRun(run); err != nil {
+		s.logger.Printf("Failed to save monitoring run for %s: %v", endpoint.ID, err)
+	}
 }
 
 // convertHeaders converts http.Header to map[string]string
@@ -560,7 +986,7 @@ func (s *CronScheduler) convertHeaders(headers map[string][]string) map[string]s
 	result := make(map[string]string)
 	for key, values := range headers {
 		if len(values) > 0 {
-			result[key] = values[0]
+			result[key] = drift.HeaderValue(key, values)
 		}
 	}
 	return result
@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+)
+
+// storedBody is the last response body of an endpoint stored in full
+type storedBody struct {
+	runID  int64
+	status int
+	hash   string
+	shared int // runs since that share it
+}
+
+// sampleResponseBody implements the full_body_every response capture sampling
+// before a run is saved. When the run's body and status are those of the last
+// body stored in full, its body is cleared and shared with that run instead,
+// unless the interval calls for a full body. It returns the hash of the body,
+// for rememberStoredBody, or "" when sampling is disabled.
+func (s *CronScheduler) sampleResponseBody(endpoint *config.EndpointConfig, run *storage.MonitoringRun) string {
+	if endpoint.FullBodyEvery <= 0 || run.ResponseBody == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(run.ResponseBody))
+	hash := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.storedBodies[endpoint.ID]
+	if !ok || stored.hash != hash || stored.status != run.ResponseStatus || stored.shared+1 >= endpoint.FullBodyEvery {
+		return hash
+	}
+
+	stored.shared++
+	s.storedBodies[endpoint.ID] = stored
+
+	run.ResponseBody = ""
+	run.ResponseBodyHash = hash
+	run.BodyRunID = stored.runID
+	return hash
+}
+
+// rememberStoredBody records a saved run whose body was stored in full as the
+// one later runs of the endpoint share their body with
+func (s *CronScheduler) rememberStoredBody(endpoint *config.EndpointConfig, run *storage.MonitoringRun, hash string) {
+	if hash == "" || run.ID == 0 || run.BodyRunID != 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.storedBodies[endpoint.ID] = storedBody{runID: run.ID, status: run.ResponseStatus, hash: hash}
+}
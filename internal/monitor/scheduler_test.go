@@ -3,12 +3,20 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/k0ns0l/driftwatch/internal/alerting"
 	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/drift"
 	httpClient "github.com/k0ns0l/driftwatch/internal/http"
 	"github.com/k0ns0l/driftwatch/internal/storage"
 	"github.com/stretchr/testify/assert"
@@ -55,11 +63,35 @@ func (m *MockStorage) GetMonitoringHistory(endpointID string, period time.Durati
 	return args.Get(0).([]*storage.MonitoringRun), args.Error(1)
 }
 
+func (m *MockStorage) GetMonitoringHistorySample(endpointID string, period time.Duration, maxSamples int) ([]*storage.MonitoringRun, error) {
+	args := m.Called(endpointID, period, maxSamples)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.MonitoringRun), args.Error(1)
+}
+
+func (m *MockStorage) GetMonitoringRun(id int64) (*storage.MonitoringRun, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.MonitoringRun), args.Error(1)
+}
+
 func (m *MockStorage) SaveDrift(drift *storage.Drift) error {
 	args := m.Called(drift)
 	return args.Error(0)
 }
 
+func (m *MockStorage) GetDrift(id int64) (*storage.Drift, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.Drift), args.Error(1)
+}
+
 func (m *MockStorage) GetDrifts(filters storage.DriftFilters) ([]*storage.Drift, error) {
 	args := m.Called(filters)
 	if args.Get(0) == nil {
@@ -68,11 +100,103 @@ func (m *MockStorage) GetDrifts(filters storage.DriftFilters) ([]*storage.Drift,
 	return args.Get(0).([]*storage.Drift), args.Error(1)
 }
 
+func (m *MockStorage) GetAlertedUnresolvedDrifts(endpointID string, limit int) ([]*storage.Drift, error) {
+	args := m.Called(endpointID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.Drift), args.Error(1)
+}
+
+func (m *MockStorage) GetDriftsAfter(afterID int64, limit int) ([]*storage.Drift, error) {
+	args := m.Called(afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.Drift), args.Error(1)
+}
+
+func (m *MockStorage) SearchDrifts(text string, limit int) ([]*storage.Drift, error) {
+	args := m.Called(text, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.Drift), args.Error(1)
+}
+
+func (m *MockStorage) AcknowledgeDrifts(endpointID string, before time.Time) (int64, error) {
+	args := m.Called(endpointID, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) AcknowledgeFingerprint(fingerprint string) (int64, error) {
+	args := m.Called(fingerprint)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) ResolveDrift(id int64, resolvedAt time.Time) error {
+	args := m.Called(id, resolvedAt)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SuppressFingerprint(fingerprint string) error {
+	args := m.Called(fingerprint)
+	return args.Error(0)
+}
+
+func (m *MockStorage) IsFingerprintSuppressed(fingerprint string) (bool, error) {
+	args := m.Called(fingerprint)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStorage) GetSinkCursor(name string) (int64, error) {
+	args := m.Called(name)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) SaveSinkCursor(name string, lastDriftID int64) error {
+	args := m.Called(name, lastDriftID)
+	return args.Error(0)
+}
+
+func (m *MockStorage) SaveHeartbeat(at time.Time) error {
+	args := m.Called(at)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetLastHeartbeat() (time.Time, error) {
+	args := m.Called()
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockStorage) SaveMaintenanceWindow(window *storage.MaintenanceWindow) error {
+	args := m.Called(window)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetActiveMaintenanceWindows(at time.Time) ([]*storage.MaintenanceWindow, error) {
+	args := m.Called(at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*storage.MaintenanceWindow), args.Error(1)
+}
+
+func (m *MockStorage) EndMaintenanceWindows(endpointID string, at time.Time) (int64, error) {
+	args := m.Called(endpointID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStorage) SaveAlert(alert *storage.Alert) error {
 	args := m.Called(alert)
 	return args.Error(0)
 }
 
+func (m *MockStorage) UpdateAlert(alert *storage.Alert) error {
+	args := m.Called(alert)
+	return args.Error(0)
+}
+
 func (m *MockStorage) GetAlerts(filters storage.AlertFilters) ([]*storage.Alert, error) {
 	args := m.Called(filters)
 	if args.Get(0) == nil {
@@ -109,6 +233,16 @@ func (m *MockStorage) CleanupOldMonitoringRuns(cutoff time.Time) (int64, error)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockStorage) TrimMonitoringRuns(endpointID string, keep int) (int64, error) {
+	args := m.Called(endpointID, keep)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStorage) CompactMonitoringRuns(olderThan time.Time) (int64, error) {
+	args := m.Called(olderThan)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockStorage) GetDatabaseStats() (*storage.DatabaseStats, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -545,3 +679,721 @@ func TestCheckOnceWithTimeout(t *testing.T) {
 	mockStorage.AssertExpectations(t)
 	// Note: We don't assert HTTP client expectations since the timeout might prevent the call
 }
+
+func TestCheckEndpointRecordsFailureCategory(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:       "test-endpoint",
+		URL:      "https://api.example.com/test",
+		Method:   "GET",
+		Interval: 5 * time.Minute,
+		Timeout:  time.Second,
+		Enabled:  true,
+	}
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+	t.Run("network failure", func(t *testing.T) {
+		mockStorage := &MockStorage{}
+		mockHTTPClient := &MockHTTPClient{}
+
+		mockStorage.On("GetEndpoint", "test-endpoint").Return(&storage.Endpoint{ID: "test-endpoint"}, nil)
+		mockStorage.On("SaveMonitoringRun", mock.MatchedBy(func(run *storage.MonitoringRun) bool {
+			return run.FailureCategory == "dns" && run.ResponseStatus == 0 && run.ErrorMessage != ""
+		})).Return(nil).Once()
+		mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(nil,
+			&net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true})
+
+		scheduler := NewCronScheduler(cfg, mockStorage, mockHTTPClient)
+		scheduler.checkEndpoint(&endpoint)
+
+		status := scheduler.endpointStatus["test-endpoint"]
+		require.NotNil(t, status)
+		assert.Equal(t, int64(1), status.ErrorCount)
+		assert.Equal(t, "dns", status.LastFailureCategory)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		mockStorage := &MockStorage{}
+		mockHTTPClient := &MockHTTPClient{}
+
+		mockStorage.On("GetEndpoint", "test-endpoint").Return(&storage.Endpoint{ID: "test-endpoint"}, nil)
+		mockStorage.On("SaveMonitoringRun", mock.MatchedBy(func(run *storage.MonitoringRun) bool {
+			return run.FailureCategory == "http" && run.ResponseStatus == 503
+		})).Return(nil).Once()
+		mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{StatusCode: 503}, nil)
+
+		scheduler := NewCronScheduler(cfg, mockStorage, mockHTTPClient)
+		scheduler.checkEndpoint(&endpoint)
+
+		assert.Equal(t, "http", scheduler.endpointStatus["test-endpoint"].LastFailureCategory)
+		mockStorage.AssertExpectations(t)
+	})
+}
+
+func TestCheckEndpointTakesSamples(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:       "test-endpoint",
+		URL:      "https://api.example.com/test",
+		Method:   "GET",
+		Interval: 5 * time.Minute,
+		Timeout:  time.Second,
+		Samples:  3,
+		Enabled:  true,
+	}
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+	mockStorage := &MockStorage{}
+	mockHTTPClient := &MockHTTPClient{}
+
+	mockStorage.On("GetEndpoint", "test-endpoint").Return(&storage.Endpoint{ID: "test-endpoint"}, nil)
+	mockStorage.On("SaveMonitoringRun", mock.MatchedBy(func(run *storage.MonitoringRun) bool {
+		return run.SampleCount == 3 && run.ResponseStatus == 200 &&
+			assert.ObjectsAreEqual([]string{"$.request_id"}, run.VolatileFields)
+	})).Return(nil).Once()
+	for _, requestID := range []string{"a1", "b2", "c3"} {
+		mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+			StatusCode: 200,
+			Body:       []byte(`{"name": "widget", "request_id": "` + requestID + `"}`),
+		}, nil).Once()
+	}
+
+	scheduler := NewCronScheduler(cfg, mockStorage, mockHTTPClient)
+	scheduler.checkEndpoint(&endpoint)
+
+	mockHTTPClient.AssertNumberOfCalls(t, "Do", 3)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestCheckEndpointCachesSampleComparisons(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:       "test-endpoint",
+		URL:      "https://api.example.com/test",
+		Method:   "GET",
+		Interval: 5 * time.Minute,
+		Timeout:  time.Second,
+		Samples:  2,
+		Enabled:  true,
+	}
+	cfg := &config.Config{
+		Global:    config.GlobalConfig{ComparisonCacheSize: 8},
+		Endpoints: []config.EndpointConfig{endpoint},
+	}
+
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+	mockHTTPClient := &MockHTTPClient{}
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"name": "widget"}`),
+	}, nil)
+
+	scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+	scheduler.checkEndpoint(&endpoint)
+	scheduler.checkEndpoint(&endpoint)
+
+	// The samples of the second check are compared as those of the first
+	metrics := scheduler.compareCache.GetMetrics()
+	assert.Equal(t, int64(1), metrics.Misses)
+	assert.Equal(t, int64(1), metrics.Hits)
+}
+
+func TestCheckEndpointRecordsCertificateDrift(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:       "test-endpoint",
+		URL:      "https://api.example.com/test",
+		Method:   "GET",
+		Interval: 5 * time.Minute,
+		Timeout:  time.Second,
+		Enabled:  true,
+	}
+	cfg := &config.Config{
+		Global:    config.GlobalConfig{TLSExpiryWarning: 14 * 24 * time.Hour},
+		Endpoints: []config.EndpointConfig{endpoint},
+	}
+
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+	previousNotAfter := time.Now().Add(90 * 24 * time.Hour)
+	require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+		EndpointID:     "test-endpoint",
+		Timestamp:      time.Now().Add(-5 * time.Minute),
+		ResponseStatus: 200,
+		TLSNotAfter:    &previousNotAfter,
+		TLSIssuer:      "CN=Old CA",
+		TLSFingerprint: "aa",
+	}))
+
+	mockHTTPClient := &MockHTTPClient{}
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+		StatusCode: 200,
+		TLS: &httpClient.TLSCertificate{
+			NotAfter:    time.Now().Add(3 * 24 * time.Hour),
+			Issuer:      "CN=New CA",
+			Fingerprint: "bb",
+		},
+	}, nil)
+
+	scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+	scheduler.checkEndpoint(&endpoint)
+
+	history, err := store.GetMonitoringHistory("test-endpoint", time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "bb", history[0].TLSFingerprint)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+	require.NoError(t, err)
+
+	driftTypes := make([]string, 0, len(drifts))
+	for _, d := range drifts {
+		driftTypes = append(driftTypes, d.DriftType)
+	}
+	assert.ElementsMatch(t, []string{"certificate_change", "tls_expiring"}, driftTypes)
+}
+
+func TestCheckEndpointRecordsMissingRequiredFields(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:         "test-endpoint",
+		URL:        "https://api.example.com/test",
+		Method:     "GET",
+		Interval:   5 * time.Minute,
+		Timeout:    time.Second,
+		Enabled:    true,
+		Validation: config.ValidationConfig{RequiredFields: []string{"id", "items[*].name"}},
+	}
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+	mockHTTPClient := &MockHTTPClient{}
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"id": null, "items": [{"name": "a"}, {"label": "b"}]}`),
+	}, nil)
+
+	scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+	scheduler.checkEndpoint(&endpoint)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+	require.NoError(t, err)
+
+	paths := make([]string, 0, len(drifts))
+	for _, d := range drifts {
+		assert.Equal(t, "required_field_missing", d.DriftType)
+		assert.Equal(t, "high", d.Severity)
+		paths = append(paths, d.FieldPath)
+	}
+	assert.ElementsMatch(t, []string{"$.id", "$.items[1].name"}, paths)
+}
+
+func TestCheckEndpointSkipsFieldChecksOfTruncatedBodies(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:       "test-endpoint",
+		URL:      "https://api.example.com/stream",
+		Method:   "GET",
+		Interval: 5 * time.Minute,
+		Timeout:  time.Second,
+		Enabled:  true,
+		Validation: config.ValidationConfig{
+			RequiredFields: []string{"id"},
+			VersionField:   "version",
+		},
+	}
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+	mockHTTPClient := &MockHTTPClient{}
+	for _, body := range []string{`{"id": 1, "version": "1", "items": [`, `{"id": 1, "version": "2", "items": [{"n`} {
+		mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+			StatusCode: 200,
+			Body:       []byte(body),
+			Truncated:  true,
+		}, nil).Once()
+	}
+
+	scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+	scheduler.checkEndpoint(&endpoint)
+	scheduler.checkEndpoint(&endpoint)
+
+	drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+}
+
+func TestCheckEndpointSuppressesDriftBelowMinPersistSeverity(t *testing.T) {
+	tests := []struct {
+		name               string
+		globalSeverity     string
+		endpointSeverity   string
+		expectedDrifts     int
+		expectedSuppressed int64
+	}{
+		{name: "no threshold", expectedDrifts: 1},
+		{name: "global threshold above drift", globalSeverity: "critical", expectedSuppressed: 1},
+		{name: "endpoint override", globalSeverity: "critical", endpointSeverity: "high", expectedDrifts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := config.EndpointConfig{
+				ID:                 "test-endpoint",
+				URL:                "https://api.example.com/test",
+				Method:             "GET",
+				Interval:           5 * time.Minute,
+				Timeout:            time.Second,
+				Enabled:            true,
+				Validation:         config.ValidationConfig{RequiredFields: []string{"id"}},
+				MinPersistSeverity: tt.endpointSeverity,
+			}
+			cfg := &config.Config{
+				Global:    config.GlobalConfig{MinPersistSeverity: tt.globalSeverity},
+				Endpoints: []config.EndpointConfig{endpoint},
+			}
+
+			store, err := storage.NewInMemoryStorage()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+			mockHTTPClient := &MockHTTPClient{}
+			mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+				StatusCode: 200,
+				Body:       []byte(`{"name": "a"}`),
+			}, nil)
+
+			scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+			scheduler.checkEndpoint(&endpoint)
+
+			drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+			require.NoError(t, err)
+			assert.Len(t, drifts, tt.expectedDrifts)
+
+			status := scheduler.GetStatus().EndpointStatuses["test-endpoint"]
+			assert.Equal(t, tt.expectedSuppressed, status.SuppressedDrifts)
+		})
+	}
+}
+
+// MockAlertManager is a mock implementation of alerting.AlertManager
+type MockAlertManager struct {
+	mock.Mock
+}
+
+func (m *MockAlertManager) SendAlert(ctx context.Context, drift *storage.Drift, endpoint *storage.Endpoint) error {
+	args := m.Called(ctx, drift, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockAlertManager) TestConfiguration(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockAlertManager) GetAlertHistory(filters alerting.AlertFilters) ([]*alerting.Alert, error) {
+	args := m.Called(filters)
+	return nil, args.Error(1)
+}
+
+func (m *MockAlertManager) ProcessDrift(ctx context.Context, driftResult *drift.DiffResult, endpoint *storage.Endpoint) error {
+	args := m.Called(ctx, driftResult, endpoint)
+	return args.Error(0)
+}
+
+func (m *MockAlertManager) FlushDigest(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockAlertManager) CheckLiveness(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func TestCheckEndpointTagsDriftDuringMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		name            string
+		windowEndpoint  string
+		autoAcknowledge bool
+		expectAlert     bool
+	}{
+		{name: "global window", autoAcknowledge: true},
+		{name: "endpoint window", windowEndpoint: "test-endpoint"},
+		{name: "window of another endpoint", windowEndpoint: "other-endpoint", expectAlert: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := config.EndpointConfig{
+				ID:         "test-endpoint",
+				URL:        "https://api.example.com/test",
+				Method:     "GET",
+				Interval:   5 * time.Minute,
+				Timeout:    time.Second,
+				Enabled:    true,
+				Validation: config.ValidationConfig{RequiredFields: []string{"id"}},
+			}
+			cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+			store, err := storage.NewInMemoryStorage()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+			require.NoError(t, store.SaveMaintenanceWindow(&storage.MaintenanceWindow{
+				EndpointID:      tt.windowEndpoint,
+				StartedAt:       time.Now().Add(-time.Minute),
+				EndsAt:          time.Now().Add(time.Hour),
+				AutoAcknowledge: tt.autoAcknowledge,
+			}))
+
+			mockHTTPClient := &MockHTTPClient{}
+			mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+				StatusCode: 200,
+				Body:       []byte(`{"name": "a"}`),
+			}, nil)
+
+			alertManager := &MockAlertManager{}
+			if tt.expectAlert {
+				alertManager.On("ProcessDrift", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+			scheduler.SetAlertManager(alertManager)
+			scheduler.checkEndpoint(&endpoint)
+
+			alertManager.AssertExpectations(t)
+			if tt.expectAlert {
+				return
+			}
+
+			drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+			require.NoError(t, err)
+			require.Len(t, drifts, 1)
+			assert.Equal(t, storage.DriftTagMaintenance, drifts[0].Tag)
+			assert.Equal(t, tt.autoAcknowledge, drifts[0].Acknowledged)
+		})
+	}
+}
+
+func TestCheckEndpointRecordsVersionChange(t *testing.T) {
+	tests := []struct {
+		name               string
+		acknowledge        bool
+		expectAcknowledged bool
+	}{
+		{name: "earlier drift kept", acknowledge: false},
+		{name: "earlier drift acknowledged", acknowledge: true, expectAcknowledged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := config.EndpointConfig{
+				ID:       "test-endpoint",
+				URL:      "https://api.example.com/test",
+				Method:   "GET",
+				Interval: 5 * time.Minute,
+				Timeout:  time.Second,
+				Enabled:  true,
+				Validation: config.ValidationConfig{
+					VersionField:               "schema_version",
+					VersionChangeSeverity:      "medium",
+					AcknowledgeOnVersionChange: tt.acknowledge,
+				},
+			}
+			cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+			store, err := storage.NewInMemoryStorage()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+			require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+				EndpointID:     "test-endpoint",
+				Timestamp:      time.Now().Add(-time.Minute),
+				ResponseStatus: 200,
+				ResponseBody:   `{"schema_version": "1"}`,
+			}))
+			earlier := &storage.Drift{
+				EndpointID: "test-endpoint",
+				DriftType:  "field_removed",
+				Severity:   "high",
+				FieldPath:  "$.name",
+				DetectedAt: time.Now().Add(-time.Hour),
+			}
+			require.NoError(t, store.SaveDrift(earlier))
+
+			mockHTTPClient := &MockHTTPClient{}
+			mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+				StatusCode: 200,
+				Body:       []byte(`{"schema_version": "2"}`),
+			}, nil)
+
+			scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+			scheduler.checkEndpoint(&endpoint)
+
+			drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+			require.NoError(t, err)
+			require.Len(t, drifts, 2)
+
+			// Drifts are returned newest first
+			assert.Equal(t, string(drift.ChangeTypeVersionChange), drifts[0].DriftType)
+			assert.Equal(t, "medium", drifts[0].Severity)
+			assert.Equal(t, "1", drifts[0].BeforeValue)
+			assert.Equal(t, "2", drifts[0].AfterValue)
+			assert.False(t, drifts[0].Acknowledged)
+			assert.Equal(t, tt.expectAcknowledged, drifts[1].Acknowledged)
+		})
+	}
+}
+
+func TestCheckEndpointRecordsAuthFailure(t *testing.T) {
+	tests := []struct {
+		name           string
+		auth           *config.AuthConfig
+		previousStatus int
+		expectDrift    bool
+	}{
+		{name: "credentials rejected after success", auth: bearerAuth(), previousStatus: 200, expectDrift: true},
+		{name: "credentials still rejected", auth: bearerAuth(), previousStatus: 401},
+		{name: "no previous response", auth: bearerAuth()},
+		{name: "endpoint without credentials", previousStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := config.EndpointConfig{
+				ID:       "test-endpoint",
+				URL:      "https://api.example.com/test",
+				Method:   "GET",
+				Interval: 5 * time.Minute,
+				Timeout:  time.Second,
+				Enabled:  true,
+				Auth:     tt.auth,
+			}
+			cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+			store, err := storage.NewInMemoryStorage()
+			require.NoError(t, err)
+			require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+			if tt.previousStatus != 0 {
+				require.NoError(t, store.SaveMonitoringRun(&storage.MonitoringRun{
+					EndpointID:     "test-endpoint",
+					Timestamp:      time.Now().Add(-time.Minute),
+					ResponseStatus: tt.previousStatus,
+				}))
+			}
+
+			mockHTTPClient := &MockHTTPClient{}
+			mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+				StatusCode: 401,
+				Body:       []byte(`{"error": "invalid token"}`),
+			}, nil)
+
+			scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+			scheduler.checkEndpoint(&endpoint)
+
+			drifts, err := store.GetDrifts(storage.DriftFilters{EndpointID: "test-endpoint"})
+			require.NoError(t, err)
+			if !tt.expectDrift {
+				assert.Empty(t, drifts)
+				return
+			}
+
+			require.Len(t, drifts, 1)
+			assert.Equal(t, string(drift.ChangeTypeAuthFailure), drifts[0].DriftType)
+			assert.Equal(t, "critical", drifts[0].Severity)
+			assert.Contains(t, drifts[0].Description, "Credentials rejected")
+		})
+	}
+}
+
+func TestCheckEndpointSharesUnchangedBodies(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:            "test-endpoint",
+		URL:           "https://api.example.com/test",
+		Method:        "GET",
+		Interval:      5 * time.Minute,
+		Timeout:       time.Second,
+		Enabled:       true,
+		FullBodyEvery: 3,
+	}
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+	mockHTTPClient := &MockHTTPClient{}
+	for _, body := range []string{`{"v": 1}`, `{"v": 1}`, `{"v": 1}`, `{"v": 1}`, `{"v": 2}`, `{"v": 2}`} {
+		mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Return(&httpClient.Response{
+			StatusCode: 200,
+			Body:       []byte(body),
+		}, nil).Once()
+	}
+
+	scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+	for i := 0; i < 6; i++ {
+		scheduler.checkEndpoint(&endpoint)
+	}
+
+	runs, err := store.GetMonitoringHistory("test-endpoint", time.Hour)
+	require.NoError(t, err)
+	require.Len(t, runs, 6)
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ID < runs[j].ID })
+
+	var bodyRunIDs []int64
+	for _, run := range runs {
+		bodyRunIDs = append(bodyRunIDs, run.BodyRunID)
+	}
+
+	// Every third check and changed bodies are stored in full
+	assert.Equal(t, []int64{0, runs[0].ID, runs[0].ID, 0, 0, runs[4].ID}, bodyRunIDs)
+	assert.Equal(t, `{"v": 1}`, runs[2].ResponseBody, "shared bodies are read from the run storing them")
+	assert.Equal(t, `{"v": 2}`, runs[5].ResponseBody)
+	assert.NotEmpty(t, runs[5].ResponseBodyHash)
+}
+
+func TestCheckEndpointSkipsOverlappingChecks(t *testing.T) {
+	endpoint := config.EndpointConfig{
+		ID:       "test-endpoint",
+		URL:      "https://api.example.com/test",
+		Method:   "GET",
+		Interval: 5 * time.Minute,
+		Timeout:  time.Second,
+		Enabled:  true,
+	}
+	cfg := &config.Config{Endpoints: []config.EndpointConfig{endpoint}}
+
+	store, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: "test-endpoint", URL: endpoint.URL, Method: "GET"}))
+
+	release := make(chan struct{})
+	mockHTTPClient := &MockHTTPClient{}
+	mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).Run(func(mock.Arguments) {
+		<-release
+	}).Return(&httpClient.Response{StatusCode: 200, Body: []byte(`{"v": 1}`)}, nil)
+
+	scheduler := NewCronScheduler(cfg, store, mockHTTPClient)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scheduler.checkEndpoint(&endpoint)
+	}()
+	require.Eventually(t, func() bool {
+		scheduler.mu.RLock()
+		defer scheduler.mu.RUnlock()
+		return scheduler.inFlight["test-endpoint"]
+	}, time.Second, 5*time.Millisecond)
+
+	// The slow check is still running, so this one is skipped
+	scheduler.checkEndpoint(&endpoint)
+	close(release)
+	<-done
+
+	status := scheduler.GetStatus().EndpointStatuses["test-endpoint"]
+	assert.Equal(t, int64(1), status.SkippedOverlaps)
+	assert.Equal(t, int64(1), status.CheckCount)
+	mockHTTPClient.AssertNumberOfCalls(t, "Do", 1)
+
+	runs, err := store.GetMonitoringHistory("test-endpoint", time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	// Once the check has finished the endpoint is checked again
+	scheduler.checkEndpoint(&endpoint)
+	mockHTTPClient.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func bearerAuth() *config.AuthConfig {
+	return &config.AuthConfig{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "expired"}}
+}
+
+func TestHeartbeat(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "driftwatch.heartbeat")
+	cfg := &config.Config{
+		Heartbeat: config.HeartbeatConfig{Enabled: true, Interval: time.Minute, File: file, URL: server.URL},
+	}
+
+	t.Run("recorded and pinged", func(t *testing.T) {
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+
+		scheduler := NewCronScheduler(cfg, store, &MockHTTPClient{})
+		scheduler.heartbeat()
+
+		beatAt, err := store.GetLastHeartbeat()
+		require.NoError(t, err)
+		assert.False(t, beatAt.IsZero())
+		assert.Equal(t, beatAt, scheduler.GetStatus().LastHeartbeat)
+
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, beatAt.Format(time.RFC3339)+"\n", string(content))
+
+		assert.Equal(t, int32(1), pings.Load())
+	})
+
+	t.Run("recorded after completed checks", func(t *testing.T) {
+		pings.Store(0)
+
+		endpoint := config.EndpointConfig{
+			ID:       "test-endpoint",
+			URL:      "https://api.example.com/test",
+			Method:   "GET",
+			Interval: 5 * time.Minute,
+			Timeout:  time.Second,
+			Enabled:  true,
+		}
+		store, err := storage.NewInMemoryStorage()
+		require.NoError(t, err)
+		require.NoError(t, store.SaveEndpoint(&storage.Endpoint{ID: endpoint.ID, URL: endpoint.URL, Method: "GET"}))
+
+		mockHTTPClient := &MockHTTPClient{}
+		mockHTTPClient.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(&httpClient.Response{StatusCode: 200, Body: []byte(`{"v": 1}`)}, nil)
+
+		checkCfg := *cfg
+		checkCfg.Endpoints = []config.EndpointConfig{endpoint}
+		scheduler := NewCronScheduler(&checkCfg, store, mockHTTPClient)
+
+		scheduler.checkEndpoint(&endpoint)
+		beatAt, err := store.GetLastHeartbeat()
+		require.NoError(t, err)
+		assert.False(t, beatAt.IsZero())
+		assert.Equal(t, int32(1), pings.Load())
+
+		// A second check within the interval records no further heartbeat
+		scheduler.checkEndpoint(&endpoint)
+		assert.Equal(t, int32(1), pings.Load())
+		mockHTTPClient.AssertNumberOfCalls(t, "Do", 2)
+	})
+
+	t.Run("not pinged when storage fails", func(t *testing.T) {
+		pings.Store(0)
+		require.NoError(t, os.Remove(file))
+
+		store := &MockStorage{}
+		store.On("SaveHeartbeat", mock.AnythingOfType("time.Time")).Return(fmt.Errorf("database is locked"))
+
+		scheduler := NewCronScheduler(cfg, store, &MockHTTPClient{})
+		scheduler.heartbeat()
+
+		assert.True(t, scheduler.GetStatus().LastHeartbeat.IsZero())
+		assert.NoFileExists(t, file)
+		assert.Equal(t, int32(0), pings.Load())
+		store.AssertExpectations(t)
+	})
+}
@@ -44,6 +44,26 @@ func DefaultRecoveryConfig() RecoveryConfig {
 	}
 }
 
+// WaitForRetry waits for delay before the next attempt of an operation. Retries
+// share the deadline of ctx: if the deadline would pass before the delay ends,
+// it returns context.DeadlineExceeded immediately rather than waiting for an
+// attempt that has no time left to run. It returns ctx.Err() if ctx is done.
+func WaitForRetry(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // RecoveryManager handles error recovery operations
 type RecoveryManager struct {
 	config RecoveryConfig
@@ -108,11 +128,8 @@ func (rm *RecoveryManager) Retry(ctx context.Context, operation Operation, opera
 			delay := rm.calculateDelay(attempt - 1)
 			rm.logger.LogRecovery(ctx, err, attempt, rm.config.MaxAttempts, delay)
 
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
-				// Continue to next attempt
+			if err := WaitForRetry(ctx, delay); err != nil {
+				return err
 			}
 		}
 	}
@@ -161,11 +178,8 @@ func (rm *RecoveryManager) RetryWithResult(ctx context.Context, operation Operat
 			delay := rm.calculateDelay(attempt - 1)
 			rm.logger.LogRecovery(ctx, err, attempt, rm.config.MaxAttempts, delay)
 
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
-				// Continue to next attempt
+			if err := WaitForRetry(ctx, delay); err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -207,10 +221,8 @@ func (rm *RecoveryManager) RetryIf(ctx context.Context, operation Operation, con
 			delay := rm.calculateDelay(attempt - 1)
 			rm.logger.LogRecovery(ctx, err, attempt, rm.config.MaxAttempts, delay)
 
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
+			if err := WaitForRetry(ctx, delay); err != nil {
+				return err
 			}
 		}
 	}
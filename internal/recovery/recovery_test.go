@@ -136,6 +136,22 @@ func TestRecoveryManager_Retry_ContextCancellation(t *testing.T) {
 	assert.Equal(t, 1, attempts) // Should stop after context cancellation
 }
 
+func TestWaitForRetry(t *testing.T) {
+	assert.NoError(t, WaitForRetry(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := WaitForRetry(ctx, 5*time.Second)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "should not wait for a delay past the deadline")
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.Equal(t, context.Canceled, WaitForRetry(cancelled, time.Second))
+}
+
 func TestRecoveryManager_RetryWithResult_Success(t *testing.T) {
 	config := RecoveryConfig{
 		MaxAttempts:  3,
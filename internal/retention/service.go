@@ -4,6 +4,7 @@ package retention
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/k0ns0l/driftwatch/internal/config"
@@ -17,6 +18,7 @@ type Service struct {
 	storage storage.Storage
 	config  *config.RetentionConfig
 	logger  *logging.Logger
+	maxRuns map[string]int // Newest runs kept per endpoint, regardless of age
 	cron    *cron.Cron
 	ctx     context.Context
 	cancel  context.CancelFunc
@@ -36,6 +38,12 @@ func NewService(storage storage.Storage, config *config.RetentionConfig, logger
 	}
 }
 
+// SetMaxRuns caps the monitoring runs kept per endpoint ID, in addition to the
+// time-based retention
+func (s *Service) SetMaxRuns(maxRuns map[string]int) {
+	s.maxRuns = maxRuns
+}
+
 // Start begins the automatic cleanup process if enabled
 func (s *Service) Start() error {
 	if !s.config.AutoCleanup {
@@ -83,55 +91,41 @@ func (s *Service) GetStats() (*storage.DatabaseStats, error) {
 	return s.storage.GetDatabaseStats()
 }
 
-// performCleanup executes the cleanup process
+// performCleanup executes the cleanup process with the configured retention
 func (s *Service) performCleanup() error {
 	s.logger.Info("Starting automatic cleanup")
 
 	now := time.Now()
-	var totalCleaned int64
-
-	// Calculate cutoff times
-	monitoringCutoff := now.AddDate(0, 0, -s.config.MonitoringRunsDays)
-	driftsCutoff := now.AddDate(0, 0, -s.config.DriftsDays)
-	alertsCutoff := now.AddDate(0, 0, -s.config.AlertsDays)
-
-	// Clean monitoring runs
+	opts := CleanupOptions{MaxRuns: s.maxRuns}
 	if s.config.MonitoringRunsDays > 0 {
-		cleaned, err := s.storage.CleanupOldMonitoringRuns(monitoringCutoff)
-		if err != nil {
-			return fmt.Errorf("failed to cleanup monitoring runs: %w", err)
-		}
-		totalCleaned += cleaned
-		if cleaned > 0 {
-			s.logger.Info("Cleaned old monitoring runs", "count", cleaned, "cutoff", monitoringCutoff)
-		}
+		cutoff := now.AddDate(0, 0, -s.config.MonitoringRunsDays)
+		opts.MonitoringRunsOlderThan = &cutoff
+	}
+	if s.config.CompactAfter > 0 {
+		cutoff := now.Add(-s.config.CompactAfter)
+		opts.CompactRunsOlderThan = &cutoff
 	}
-
-	// Clean drifts
 	if s.config.DriftsDays > 0 {
-		cleaned, err := s.storage.CleanupOldDrifts(driftsCutoff)
-		if err != nil {
-			return fmt.Errorf("failed to cleanup drifts: %w", err)
-		}
-		totalCleaned += cleaned
-		if cleaned > 0 {
-			s.logger.Info("Cleaned old drifts", "count", cleaned, "cutoff", driftsCutoff)
-		}
+		cutoff := now.AddDate(0, 0, -s.config.DriftsDays)
+		opts.DriftsOlderThan = &cutoff
 	}
-
-	// Clean alerts
 	if s.config.AlertsDays > 0 {
-		cleaned, err := s.storage.CleanupOldAlerts(alertsCutoff)
-		if err != nil {
-			return fmt.Errorf("failed to cleanup alerts: %w", err)
-		}
-		totalCleaned += cleaned
-		if cleaned > 0 {
-			s.logger.Info("Cleaned old alerts", "count", cleaned, "cutoff", alertsCutoff)
-		}
+		cutoff := now.AddDate(0, 0, -s.config.AlertsDays)
+		opts.AlertsOlderThan = &cutoff
+	}
+
+	result, err := s.CleanupWithOptions(opts)
+	if err != nil {
+		return err
 	}
+	s.logger.Info("Cleaned old records",
+		"monitoring_runs", result.MonitoringRunsCleaned,
+		"monitoring_runs_compacted", result.MonitoringRunsCompacted,
+		"drifts", result.DriftsCleaned,
+		"alerts", result.AlertsCleaned)
 
 	// Vacuum database if records were cleaned
+	totalCleaned := result.TotalCleaned()
 	if totalCleaned > 0 {
 		if err := s.storage.VacuumDatabase(); err != nil {
 			s.logger.LogError(context.TODO(), err, "Failed to vacuum database after cleanup")
@@ -170,9 +164,32 @@ func (s *Service) intervalToCron(interval time.Duration) string {
 	}
 }
 
+// trimMonitoringRuns keeps the newest runs of each endpoint in maxRuns and
+// returns how many runs were removed
+func trimMonitoringRuns(db storage.Storage, maxRuns map[string]int) (int64, error) {
+	endpointIDs := make([]string, 0, len(maxRuns))
+	for endpointID := range maxRuns {
+		endpointIDs = append(endpointIDs, endpointID)
+	}
+	sort.Strings(endpointIDs)
+
+	var total int64
+	for _, endpointID := range endpointIDs {
+		trimmed, err := db.TrimMonitoringRuns(endpointID, maxRuns[endpointID])
+		if err != nil {
+			return total, fmt.Errorf("endpoint %s: %w", endpointID, err)
+		}
+		total += trimmed
+	}
+	return total, nil
+}
+
 // CleanupOptions provides options for manual cleanup operations
 type CleanupOptions struct {
+	// MaxRuns caps the monitoring runs kept per endpoint ID, regardless of age
+	MaxRuns                 map[string]int
 	MonitoringRunsOlderThan *time.Time
+	CompactRunsOlderThan    *time.Time
 	DriftsOlderThan         *time.Time
 	AlertsOlderThan         *time.Time
 	VacuumAfter             bool
@@ -200,6 +217,32 @@ func (s *Service) CleanupWithOptions(opts CleanupOptions) (*CleanupResult, error
 		}
 	}
 
+	// Trim monitoring runs of endpoints with a run cap
+	if len(opts.MaxRuns) > 0 {
+		if opts.DryRun {
+			result.MonitoringRunsWouldClean = true
+		} else {
+			trimmed, err := trimMonitoringRuns(s.storage, opts.MaxRuns)
+			if err != nil {
+				return nil, fmt.Errorf("failed to trim monitoring runs: %w", err)
+			}
+			result.MonitoringRunsCleaned += trimmed
+		}
+	}
+
+	// Merge identical consecutive monitoring runs
+	if opts.CompactRunsOlderThan != nil {
+		if opts.DryRun {
+			result.MonitoringRunsWouldCompact = true
+		} else {
+			compacted, err := s.storage.CompactMonitoringRuns(*opts.CompactRunsOlderThan)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compact monitoring runs: %w", err)
+			}
+			result.MonitoringRunsCompacted = compacted
+		}
+	}
+
 	// Clean drifts
 	if opts.DriftsOlderThan != nil {
 		if opts.DryRun {
@@ -239,16 +282,18 @@ func (s *Service) CleanupWithOptions(opts CleanupOptions) (*CleanupResult, error
 
 // CleanupResult contains the results of a cleanup operation
 type CleanupResult struct {
-	MonitoringRunsCleaned    int64 `json:"monitoring_runs_cleaned"`
-	DriftsCleaned            int64 `json:"drifts_cleaned"`
-	AlertsCleaned            int64 `json:"alerts_cleaned"`
-	DatabaseVacuumed         bool  `json:"database_vacuumed"`
-	MonitoringRunsWouldClean bool  `json:"monitoring_runs_would_clean,omitempty"`
-	DriftsWouldClean         bool  `json:"drifts_would_clean,omitempty"`
-	AlertsWouldClean         bool  `json:"alerts_would_clean,omitempty"`
+	MonitoringRunsCleaned      int64 `json:"monitoring_runs_cleaned"`
+	MonitoringRunsCompacted    int64 `json:"monitoring_runs_compacted"` // Runs merged into an identical earlier run
+	DriftsCleaned              int64 `json:"drifts_cleaned"`
+	AlertsCleaned              int64 `json:"alerts_cleaned"`
+	DatabaseVacuumed           bool  `json:"database_vacuumed"`
+	MonitoringRunsWouldClean   bool  `json:"monitoring_runs_would_clean,omitempty"`
+	MonitoringRunsWouldCompact bool  `json:"monitoring_runs_would_compact,omitempty"`
+	DriftsWouldClean           bool  `json:"drifts_would_clean,omitempty"`
+	AlertsWouldClean           bool  `json:"alerts_would_clean,omitempty"`
 }
 
 // TotalCleaned returns the total number of records cleaned
 func (r *CleanupResult) TotalCleaned() int64 {
-	return r.MonitoringRunsCleaned + r.DriftsCleaned + r.AlertsCleaned
+	return r.MonitoringRunsCleaned + r.MonitoringRunsCompacted + r.DriftsCleaned + r.AlertsCleaned
 }
@@ -203,6 +203,61 @@ func TestRetentionService(t *testing.T) {
 		assert.Equal(t, int64(1), result.TotalCleaned())
 	})
 
+	t.Run("CleanupWithMaxRuns", func(t *testing.T) {
+		db.Close()
+		db, err = storage.NewInMemoryStorage()
+		require.NoError(t, err)
+		service.storage = db
+
+		now := time.Now()
+		for i := 0; i < 4; i++ {
+			err = db.SaveMonitoringRun(&storage.MonitoringRun{
+				EndpointID:     "test-endpoint",
+				Timestamp:      now.Add(-time.Duration(i) * time.Minute),
+				ResponseStatus: 200,
+			})
+			require.NoError(t, err)
+		}
+
+		// Runs within the retention period are trimmed to the endpoint's cap
+		result, err := service.CleanupWithOptions(CleanupOptions{MaxRuns: map[string]int{"test-endpoint": 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.MonitoringRunsCleaned)
+
+		stats, err := service.GetStats()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), stats.MonitoringRuns)
+	})
+
+	t.Run("CleanupWithCompaction", func(t *testing.T) {
+		db.Close()
+		db, err = storage.NewInMemoryStorage()
+		require.NoError(t, err)
+		service.storage = db
+
+		now := time.Now()
+		for i := 0; i < 4; i++ {
+			err = db.SaveMonitoringRun(&storage.MonitoringRun{
+				EndpointID:     "test-endpoint",
+				Timestamp:      now.Add(-time.Duration(i) * time.Hour),
+				ResponseStatus: 200,
+				ResponseBody:   `{"ok":true}`,
+			})
+			require.NoError(t, err)
+		}
+
+		// The three runs older than the cutoff are merged into the oldest
+		cutoff := now.Add(-30 * time.Minute)
+		result, err := service.CleanupWithOptions(CleanupOptions{CompactRunsOlderThan: &cutoff})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.MonitoringRunsCompacted)
+
+		runs, err := db.GetMonitoringHistory("test-endpoint", 24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+		assert.Equal(t, 3, runs[1].CheckCount)
+	})
+
 	t.Run("StartStop", func(t *testing.T) {
 		// Test starting with auto cleanup disabled
 		disabledConfig := &config.RetentionConfig{
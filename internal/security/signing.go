@@ -0,0 +1,290 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Supported export signature algorithms
+const (
+	SignatureAlgorithmHMACSHA256 = "hmac-sha256"
+	SignatureAlgorithmEd25519    = "ed25519"
+)
+
+// ExportManifest describes a signed export file
+type ExportManifest struct {
+	CreatedAt    time.Time      `json:"created_at"`
+	RecordCounts map[string]int `json:"record_counts"`
+	File         string         `json:"file"`
+	Format       string         `json:"format"`
+	SHA256       string         `json:"sha256"`
+	Algorithm    string         `json:"algorithm"`
+	SizeBytes    int64          `json:"size_bytes"`
+}
+
+// Signer signs and verifies export manifests
+type Signer interface {
+	Algorithm() string
+	Sign(data []byte) ([]byte, error)
+	Verify(data, signature []byte) error
+}
+
+// HMACSigner signs data with HMAC-SHA256 using a shared secret
+type HMACSigner struct {
+	key []byte
+}
+
+// NewHMACSigner creates an HMAC-SHA256 signer
+func NewHMACSigner(key []byte) (*HMACSigner, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("HMAC key cannot be empty")
+	}
+	return &HMACSigner{key: key}, nil
+}
+
+// Algorithm returns the signature algorithm name
+func (s *HMACSigner) Algorithm() string {
+	return SignatureAlgorithmHMACSHA256
+}
+
+// Sign computes the HMAC of data
+func (s *HMACSigner) Sign(data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// Verify checks that signature is the HMAC of data
+func (s *HMACSigner) Verify(data, signature []byte) error {
+	expected, err := s.Sign(data)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, signature) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Ed25519Signer signs data with an ed25519 private key and verifies with the public key.
+// A signer created with only a public key can verify but not sign.
+type Ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// NewEd25519Signer creates an ed25519 signer. Either key may be nil; the public
+// key is derived from the private key when not provided.
+func NewEd25519Signer(privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey) (*Ed25519Signer, error) {
+	if privateKey == nil && publicKey == nil {
+		return nil, fmt.Errorf("ed25519 signer requires a private or public key")
+	}
+	if publicKey == nil {
+		publicKey = privateKey.Public().(ed25519.PublicKey)
+	}
+	return &Ed25519Signer{privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+// Algorithm returns the signature algorithm name
+func (s *Ed25519Signer) Algorithm() string {
+	return SignatureAlgorithmEd25519
+}
+
+// Sign signs data with the private key
+func (s *Ed25519Signer) Sign(data []byte) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, fmt.Errorf("ed25519 private key is required for signing")
+	}
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// Verify checks the signature of data with the public key
+func (s *Ed25519Signer) Verify(data, signature []byte) error {
+	if !ed25519.Verify(s.publicKey, data, signature) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// LoadEd25519PrivateKey loads a PEM encoded PKCS#8 ed25519 private key
+func LoadEd25519PrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an ed25519 key")
+	}
+	return privateKey, nil
+}
+
+// LoadEd25519PublicKey loads a PEM encoded PKIX ed25519 public key
+func LoadEd25519PublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an ed25519 key")
+	}
+	return publicKey, nil
+}
+
+// readPEMBlock reads the first PEM block from a file
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := SafeReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in key file: %s", path)
+	}
+	return block, nil
+}
+
+// ManifestPath returns the manifest path for an export file
+func ManifestPath(exportPath string) string {
+	return exportPath + ".manifest.json"
+}
+
+// SignaturePath returns the detached signature path for an export file
+func SignaturePath(exportPath string) string {
+	return exportPath + ".sig"
+}
+
+// HashFile returns the hex encoded SHA-256 digest and size of a file
+func HashFile(path string, allowedDirs ...string) (string, int64, error) {
+	if err := ValidateFilePath(path, allowedDirs...); err != nil {
+		return "", 0, err
+	}
+
+	// #nosec G304 - path is validated by ValidateFilePath above
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash file: %w", err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+// SignExport writes a manifest and detached signature next to an export file
+func SignExport(exportPath, format string, recordCounts map[string]int, signer Signer, allowedDirs ...string) (*ExportManifest, error) {
+	digest, size, err := HashFile(exportPath, allowedDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &ExportManifest{
+		CreatedAt:    time.Now().UTC(),
+		RecordCounts: recordCounts,
+		File:         filepath.Base(exportPath),
+		Format:       format,
+		SHA256:       digest,
+		Algorithm:    signer.Algorithm(),
+		SizeBytes:    size,
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	signature, err := signer.Sign(manifestData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign manifest: %w", err)
+	}
+
+	if err := SafeWriteFile(ManifestPath(exportPath), manifestData, allowedDirs...); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(signature) + "\n"
+	if err := SafeWriteFile(SignaturePath(exportPath), []byte(encoded), allowedDirs...); err != nil {
+		return nil, fmt.Errorf("failed to write signature: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// VerifyExport checks the manifest signature and that the export file matches the manifest
+func VerifyExport(exportPath string, verifier Signer, allowedDirs ...string) (*ExportManifest, error) {
+	manifestData, err := SafeReadFile(ManifestPath(exportPath), allowedDirs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	encoded, err := SafeReadFile(SignaturePath(exportPath), allowedDirs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	var manifest ExportManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if manifest.Algorithm != verifier.Algorithm() {
+		return nil, fmt.Errorf("manifest was signed with %s, but verification key is %s",
+			manifest.Algorithm, verifier.Algorithm())
+	}
+
+	if err := verifier.Verify(manifestData, signature); err != nil {
+		return nil, fmt.Errorf("invalid manifest signature: %w", err)
+	}
+
+	if manifest.File != filepath.Base(exportPath) {
+		return nil, fmt.Errorf("manifest describes %s, not %s", manifest.File, filepath.Base(exportPath))
+	}
+
+	digest, size, err := HashFile(exportPath, allowedDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if size != manifest.SizeBytes || digest != manifest.SHA256 {
+		return nil, fmt.Errorf("export content does not match manifest (expected sha256 %s, got %s)",
+			manifest.SHA256, digest)
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,117 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestExport(t *testing.T, dir string) string {
+	t.Helper()
+
+	exportPath := filepath.Join(dir, "drifts.json")
+	require.NoError(t, os.WriteFile(exportPath, []byte(`[{"id":1},{"id":2}]`), 0600))
+	return exportPath
+}
+
+func TestSignExportHMAC(t *testing.T) {
+	dir := t.TempDir()
+	exportPath := writeTestExport(t, dir)
+
+	signer, err := NewHMACSigner([]byte("secret"))
+	require.NoError(t, err)
+
+	manifest, err := SignExport(exportPath, "json", map[string]int{"drifts": 2}, signer, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "drifts.json", manifest.File)
+	assert.Equal(t, SignatureAlgorithmHMACSHA256, manifest.Algorithm)
+	assert.Equal(t, 2, manifest.RecordCounts["drifts"])
+	assert.FileExists(t, ManifestPath(exportPath))
+	assert.FileExists(t, SignaturePath(exportPath))
+
+	verified, err := VerifyExport(exportPath, signer, dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.SHA256, verified.SHA256)
+
+	wrongKey, err := NewHMACSigner([]byte("other"))
+	require.NoError(t, err)
+	_, err = VerifyExport(exportPath, wrongKey, dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid manifest signature")
+}
+
+func TestVerifyExportDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	exportPath := writeTestExport(t, dir)
+
+	signer, err := NewHMACSigner([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = SignExport(exportPath, "json", map[string]int{"drifts": 2}, signer, dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(exportPath, []byte(`[{"id":1}]`), 0600))
+
+	_, err = VerifyExport(exportPath, signer, dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match manifest")
+}
+
+func TestSignExportEd25519(t *testing.T) {
+	dir := t.TempDir()
+	exportPath := writeTestExport(t, dir)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	privatePath := filepath.Join(dir, "signing.key")
+	publicPath := filepath.Join(dir, "signing.pub")
+	require.NoError(t, os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600))
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600))
+
+	loadedPrivate, err := LoadEd25519PrivateKey(privatePath)
+	require.NoError(t, err)
+	loadedPublic, err := LoadEd25519PublicKey(publicPath)
+	require.NoError(t, err)
+
+	signer, err := NewEd25519Signer(loadedPrivate, nil)
+	require.NoError(t, err)
+	_, err = SignExport(exportPath, "json", map[string]int{"drifts": 2}, signer, dir)
+	require.NoError(t, err)
+
+	// Verification only needs the public key
+	verifier, err := NewEd25519Signer(nil, loadedPublic)
+	require.NoError(t, err)
+	_, err = VerifyExport(exportPath, verifier, dir)
+	assert.NoError(t, err)
+
+	_, err = verifier.Sign([]byte("data"))
+	assert.Error(t, err)
+
+	// A verifier for a different algorithm is rejected before checking the signature
+	hmacSigner, err := NewHMACSigner([]byte("secret"))
+	require.NoError(t, err)
+	_, err = VerifyExport(exportPath, hmacSigner, dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "signed with ed25519")
+}
+
+func TestNewSignerValidation(t *testing.T) {
+	_, err := NewHMACSigner(nil)
+	assert.Error(t, err)
+
+	_, err = NewEd25519Signer(nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/k0ns0l/driftwatch/internal/version"
+)
+
+// otlpLogsPath is where OTLP/HTTP collectors receive log records
+const otlpLogsPath = "/v1/logs"
+
+// NewOTelExporter creates a drift sink that exports drifts as OpenTelemetry log
+// records to the OTLP/HTTP logs endpoint of a collector, using the JSON
+// encoding of OTLP. Each record carries the endpoint, field path, severity and
+// change type of its drift as attributes. Delivery works as for the drift sink,
+// with its own cursor.
+func NewOTelExporter(db storage.Storage, cfg *config.OTelConfig, logger *logging.Logger) *DriftSink {
+	logsURL := OTLPLogsURL(cfg.Endpoint)
+	sink := NewDriftSink(db, &config.DriftSinkConfig{
+		Enabled:       cfg.Enabled,
+		URL:           logsURL,
+		Headers:       cfg.Headers,
+		BatchSize:     cfg.BatchSize,
+		FlushInterval: cfg.FlushInterval,
+	}, logger)
+
+	if logger == nil {
+		logger = logging.GetGlobalLogger()
+	}
+	sink.logger = logger.WithComponent("otel_exporter")
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "driftwatch"
+	}
+	sink.encode = func(drifts []*storage.Drift) ([]byte, error) {
+		return encodeOTLPLogs(serviceName, drifts)
+	}
+	sink.cursor = "otel:" + logsURL
+
+	return sink
+}
+
+// OTLPLogsURL returns the URL log records are posted to for an OTLP/HTTP
+// endpoint: the endpoint itself when it already names the logs path, and its
+// logs path otherwise
+func OTLPLogsURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if strings.HasSuffix(endpoint, otlpLogsPath) {
+		return endpoint
+	}
+	return endpoint + otlpLogsPath
+}
+
+// otlpLogsRequest is the JSON encoding of an OTLP ExportLogsServiceRequest
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber"`
+	SeverityText         string          `json:"severityText"`
+	Body                 otlpValue       `json:"body"`
+	Attributes           []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is an AnyValue holding a string or an integer; 64-bit integers are
+// strings in the JSON encoding
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+// stringAttribute returns a string attribute
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+// intAttribute returns an integer attribute
+func intAttribute(key string, value int64) otlpAttribute {
+	encoded := strconv.FormatInt(value, 10)
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &encoded}}
+}
+
+// otlpSeverityNumbers maps drift severities to OpenTelemetry severity numbers:
+// INFO, WARN, ERROR and FATAL
+var otlpSeverityNumbers = map[string]int{
+	"low":      9,
+	"medium":   13,
+	"high":     17,
+	"critical": 21,
+}
+
+// encodeOTLPLogs encodes drifts as an OTLP logs request, one log record each
+func encodeOTLPLogs(serviceName string, drifts []*storage.Drift) ([]byte, error) {
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	records := make([]otlpLogRecord, 0, len(drifts))
+	for _, drift := range drifts {
+		records = append(records, newOTLPLogRecord(drift, observed))
+	}
+
+	request := otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: otlpResource{Attributes: []otlpAttribute{
+				stringAttribute("service.name", serviceName),
+				stringAttribute("service.version", version.Version),
+			}},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: "driftwatch", Version: version.Version},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OTLP logs: %w", err)
+	}
+	return body, nil
+}
+
+// newOTLPLogRecord converts a stored drift to a log record whose body is the
+// drift's description
+func newOTLPLogRecord(drift *storage.Drift, observed string) otlpLogRecord {
+	attributes := []otlpAttribute{
+		intAttribute("driftwatch.drift.id", drift.ID),
+		stringAttribute("driftwatch.endpoint.id", drift.EndpointID),
+		stringAttribute("driftwatch.drift.type", drift.DriftType),
+		stringAttribute("driftwatch.drift.severity", drift.Severity),
+	}
+	if drift.FieldPath != "" {
+		attributes = append(attributes, stringAttribute("driftwatch.drift.path", drift.FieldPath))
+	}
+	if drift.BeforeValue != "" {
+		attributes = append(attributes, stringAttribute("driftwatch.drift.before", drift.BeforeValue))
+	}
+	if drift.AfterValue != "" {
+		attributes = append(attributes, stringAttribute("driftwatch.drift.after", drift.AfterValue))
+	}
+
+	description := drift.Description
+	return otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(drift.DetectedAt.UnixNano(), 10),
+		ObservedTimeUnixNano: observed,
+		SeverityNumber:       otlpSeverityNumbers[drift.Severity],
+		SeverityText:         drift.Severity,
+		Body:                 otlpValue{StringValue: &description},
+		Attributes:           attributes,
+	}
+}
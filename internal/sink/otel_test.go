@@ -0,0 +1,97 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPLogsURL(t *testing.T) {
+	assert.Equal(t, "http://collector:4318/v1/logs", OTLPLogsURL("http://collector:4318"))
+	assert.Equal(t, "http://collector:4318/v1/logs", OTLPLogsURL("http://collector:4318/"))
+	assert.Equal(t, "https://otlp.example.com/v1/logs", OTLPLogsURL("https://otlp.example.com/v1/logs"))
+}
+
+func TestOTelExporter_Flush(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	var path string
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		var request map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests = append(requests, request)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := NewOTelExporter(db, &config.OTelConfig{
+		Enabled:       true,
+		Endpoint:      server.URL,
+		Headers:       map[string]string{"X-Api-Key": "secret"},
+		ServiceName:   "api-contracts",
+		BatchSize:     10,
+		FlushInterval: time.Second,
+	}, nil)
+
+	// Start the exporter's cursor, then detect a drift
+	_, err = exporter.Flush(context.Background())
+	require.NoError(t, err)
+
+	detectedAt := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, db.SaveDrift(&storage.Drift{
+		EndpointID:  "prices",
+		DriftType:   "type_change",
+		Severity:    "high",
+		Description: "Field type changed from number to string",
+		FieldPath:   "$.data.price",
+		BeforeValue: "12",
+		AfterValue:  `"12.00"`,
+		DetectedAt:  detectedAt,
+	}))
+
+	delivered, err := exporter.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, "/v1/logs", path)
+	require.Len(t, requests, 1)
+
+	resourceLogs := requests[0]["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	resource := resourceLogs["resource"].(map[string]interface{})
+	assert.Contains(t, resource["attributes"], map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "api-contracts"}})
+
+	scopeLogs := resourceLogs["scopeLogs"].([]interface{})[0].(map[string]interface{})
+	record := scopeLogs["logRecords"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "1710072000000000000", record["timeUnixNano"])
+	assert.Equal(t, float64(17), record["severityNumber"])
+	assert.Equal(t, "high", record["severityText"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "Field type changed from number to string"}, record["body"])
+
+	attributes := make(map[string]interface{})
+	for _, attribute := range record["attributes"].([]interface{}) {
+		attribute := attribute.(map[string]interface{})
+		attributes[attribute["key"].(string)] = attribute["value"]
+	}
+	assert.Equal(t, map[string]interface{}{"stringValue": "prices"}, attributes["driftwatch.endpoint.id"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "$.data.price"}, attributes["driftwatch.drift.path"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "high"}, attributes["driftwatch.drift.severity"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "type_change"}, attributes["driftwatch.drift.type"])
+	assert.Equal(t, map[string]interface{}{"intValue": "1"}, attributes["driftwatch.drift.id"])
+
+	// The exporter keeps its own cursor, apart from a drift sink at the same URL
+	driftSink := NewDriftSink(db, &config.DriftSinkConfig{URL: OTLPLogsURL(server.URL), BatchSize: 10}, nil)
+	assert.NotEqual(t, exporter.cursorName(), driftSink.cursorName())
+}
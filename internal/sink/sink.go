@@ -0,0 +1,212 @@
+// Package sink streams detected drifts to an external event collector, either
+// as batches of driftwatch events or as OpenTelemetry log records
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/logging"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/k0ns0l/driftwatch/internal/version"
+)
+
+// Event is a drift as delivered to the collector. The ID is stable across
+// redeliveries, so collectors can use it to discard duplicates.
+type Event struct {
+	DetectedAt  time.Time `json:"detected_at"`
+	EndpointID  string    `json:"endpoint_id"`
+	DriftType   string    `json:"drift_type"`
+	Severity    string    `json:"severity"`
+	Description string    `json:"description"`
+	BeforeValue string    `json:"before_value,omitempty"`
+	AfterValue  string    `json:"after_value,omitempty"`
+	FieldPath   string    `json:"field_path,omitempty"`
+	ID          int64     `json:"id"`
+}
+
+// Batch is the body of a delivery request
+type Batch struct {
+	SentAt  time.Time `json:"sent_at"`
+	Source  string    `json:"source"`
+	Version string    `json:"version"`
+	Events  []Event   `json:"events"`
+}
+
+// DriftSink delivers every drift saved to storage to a collector URL.
+//
+// Storage is the queue: the sink keeps a cursor of the last drift the collector
+// accepted and only advances it after a successful response, so drifts saved
+// while the collector is unavailable, or while driftwatch is not running, are
+// delivered later. A batch may be delivered more than once if driftwatch stops
+// between a successful request and saving the cursor. Drifts removed by
+// retention cleanup before they are delivered are not sent.
+type DriftSink struct {
+	storage  storage.Storage
+	config   *config.DriftSinkConfig
+	client   *http.Client
+	logger   *logging.Logger
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+
+	encode func(drifts []*storage.Drift) ([]byte, error) // request body for a batch
+	cursor string                                        // name of the delivery cursor in storage
+}
+
+// NewDriftSink creates a drift sink for the given configuration
+func NewDriftSink(storage storage.Storage, cfg *config.DriftSinkConfig, logger *logging.Logger) *DriftSink {
+	if logger == nil {
+		logger = logging.GetGlobalLogger()
+	}
+
+	return &DriftSink{
+		storage: storage,
+		config:  cfg,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger:   logger.WithComponent("drift_sink"),
+		stopChan: make(chan struct{}),
+
+		encode: encodeBatch,
+		cursor: cfg.URL,
+	}
+}
+
+// Start begins delivering drifts in the background every flush interval
+func (s *DriftSink) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.config.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+			}
+
+			if _, err := s.Flush(ctx); err != nil {
+				s.logger.LogError(ctx, err, "Failed to deliver drifts to sink")
+			}
+		}
+	}()
+}
+
+// Stop stops the background delivery and waits for it to finish. Drifts not
+// yet delivered remain queued in storage.
+func (s *DriftSink) Stop() {
+	close(s.stopChan)
+	s.wg.Wait()
+}
+
+// Flush delivers pending drifts in batches until none are left. It returns the
+// number of drifts delivered; on error, undelivered drifts remain queued.
+func (s *DriftSink) Flush(ctx context.Context) (int, error) {
+	cursor, err := s.storage.GetSinkCursor(s.cursorName())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sink cursor: %w", err)
+	}
+
+	delivered := 0
+	for {
+		drifts, err := s.storage.GetDriftsAfter(cursor, s.config.BatchSize)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to get pending drifts: %w", err)
+		}
+		if len(drifts) == 0 {
+			return delivered, nil
+		}
+
+		if err := s.send(ctx, drifts); err != nil {
+			return delivered, err
+		}
+
+		cursor = drifts[len(drifts)-1].ID
+		if err := s.storage.SaveSinkCursor(s.cursorName(), cursor); err != nil {
+			return delivered, fmt.Errorf("failed to save sink cursor: %w", err)
+		}
+		delivered += len(drifts)
+	}
+}
+
+// cursorName identifies the collector in storage. A new URL starts a new
+// cursor, so switching collectors does not replay past drifts.
+func (s *DriftSink) cursorName() string {
+	return s.cursor
+}
+
+// send posts a batch of drifts to the collector
+func (s *DriftSink) send(ctx context.Context, drifts []*storage.Drift) error {
+	body, err := s.encode(drifts)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sink request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send drifts to sink: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("drift sink returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// encodeBatch encodes drifts as a Batch of events
+func encodeBatch(drifts []*storage.Drift) ([]byte, error) {
+	batch := Batch{
+		SentAt:  time.Now(),
+		Source:  "driftwatch",
+		Version: version.Version,
+		Events:  make([]Event, 0, len(drifts)),
+	}
+	for _, drift := range drifts {
+		batch.Events = append(batch.Events, newEvent(drift))
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal drift batch: %w", err)
+	}
+	return body, nil
+}
+
+// newEvent converts a stored drift to an event
+func newEvent(drift *storage.Drift) Event {
+	return Event{
+		ID:          drift.ID,
+		DetectedAt:  drift.DetectedAt,
+		EndpointID:  drift.EndpointID,
+		DriftType:   drift.DriftType,
+		Severity:    drift.Severity,
+		Description: drift.Description,
+		BeforeValue: drift.BeforeValue,
+		AfterValue:  drift.AfterValue,
+		FieldPath:   drift.FieldPath,
+	}
+}
@@ -0,0 +1,85 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/k0ns0l/driftwatch/internal/config"
+	"github.com/k0ns0l/driftwatch/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftSink_Flush(t *testing.T) {
+	db, err := storage.NewInMemoryStorage()
+	require.NoError(t, err)
+
+	failing := true
+	var batches []Batch
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var batch Batch
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		batches = append(batches, batch)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	// Drifts saved before the sink was first used are not replayed
+	require.NoError(t, db.SaveDrift(&storage.Drift{EndpointID: "users", DriftType: "field_added", Severity: "low"}))
+
+	sink := NewDriftSink(db, &config.DriftSinkConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer token"},
+		BatchSize:     2,
+		FlushInterval: time.Second,
+	}, nil)
+
+	delivered, err := sink.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, db.SaveDrift(&storage.Drift{
+			EndpointID: "users",
+			DriftType:  "field_removed",
+			Severity:   "high",
+			FieldPath:  "$.name",
+			DetectedAt: time.Now().Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	// Drifts stay queued while the collector is unavailable
+	delivered, err = sink.Flush(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, delivered)
+
+	failing = false
+	delivered, err = sink.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, delivered)
+
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0].Events, 2)
+	require.Len(t, batches[1].Events, 1)
+	assert.Equal(t, int64(2), batches[0].Events[0].ID)
+	assert.Equal(t, int64(4), batches[1].Events[0].ID)
+	assert.Equal(t, "field_removed", batches[0].Events[0].DriftType)
+	assert.Equal(t, "$.name", batches[0].Events[0].FieldPath)
+	assert.Equal(t, "driftwatch", batches[0].Source)
+
+	// Delivered drifts are not sent again
+	delivered, err = sink.Flush(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Len(t, batches, 2)
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// bodyPruneGrace is how recently a body file must have been stored for prune
+// to keep it regardless, so that a body another process has just stored for a
+// run it has yet to save is not removed
+const bodyPruneGrace = time.Hour
+
+// bodyStore keeps response bodies as files named by the SHA-256 of their
+// content, so that identical bodies are stored once however many runs share
+// them. Files are spread over subdirectories named by the first two characters
+// of the hash.
+type bodyStore struct {
+	dir string
+}
+
+// newBodyStore returns a body store in dir, creating the directory if needed
+func newBodyStore(dir string) (*bodyStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create body storage directory: %w", err)
+	}
+	return &bodyStore{dir: dir}, nil
+}
+
+// path returns the file a body with the given hash is stored in
+func (b *bodyStore) path(hash string) string {
+	return filepath.Join(b.dir, hash[:2], hash)
+}
+
+// put stores a body unless an identical one already is, and returns its hash
+func (b *bodyStore) put(body string) (string, error) {
+	sum := sha256.Sum256([]byte(body))
+	hash := hex.EncodeToString(sum[:])
+
+	path := b.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		// Mark the body as recently stored for prune
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return "", fmt.Errorf("failed to touch body file: %w", err)
+		}
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create body directory: %w", err)
+	}
+
+	// Write to a temporary file first so that readers never see a partial body
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".tmp*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create body file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	if _, err := tmp.WriteString(body); err != nil {
+		tmp.Close() // nolint:errcheck
+		return "", fmt.Errorf("failed to write body file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write body file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store body file: %w", err)
+	}
+
+	return hash, nil
+}
+
+// get returns the body stored under a hash
+func (b *bodyStore) get(hash string) (string, error) {
+	if !isBodyHash(hash) {
+		return "", fmt.Errorf("invalid body hash %q", hash)
+	}
+
+	data, err := os.ReadFile(b.path(hash))
+	if err != nil {
+		return "", fmt.Errorf("failed to read body file: %w", err)
+	}
+	return string(data), nil
+}
+
+// prune removes the bodies whose hash is not in keep, other than those stored
+// within bodyPruneGrace, and returns how many
+func (b *bodyStore) prune(keep map[string]bool) (int, error) {
+	cutoff := time.Now().Add(-bodyPruneGrace)
+	removed := 0
+	err := filepath.WalkDir(b.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isBodyHash(entry.Name()) || keep[entry.Name()] {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune body files: %w", err)
+	}
+	return removed, nil
+}
+
+// isBodyHash reports whether s is a hex-encoded SHA-256, which keeps hashes
+// read from the database from naming files outside the store
+func isBodyHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,259 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// compactionKey identifies the runs compaction merges: runs of the same
+// endpoint with the same status, failure and response body
+type compactionKey struct {
+	endpointID      string
+	status          int
+	failureCategory string
+	errorMessage    string
+	bodyHash        string
+}
+
+// compactionKeyOf returns the compaction key of a run. The body is identified
+// by the hash it is stored or shared under, or else by hashing it.
+func compactionKeyOf(run *MonitoringRun) compactionKey {
+	bodyHash := run.ResponseBodyHash
+	if bodyHash == "" {
+		sum := sha256.Sum256([]byte(run.ResponseBody))
+		bodyHash = hex.EncodeToString(sum[:])
+	}
+
+	return compactionKey{
+		endpointID:      run.EndpointID,
+		status:          run.ResponseStatus,
+		failureCategory: run.FailureCategory,
+		errorMessage:    run.ErrorMessage,
+		bodyHash:        bodyHash,
+	}
+}
+
+// compactionGroup is a run and the identical runs that followed it, which are
+// merged into it
+type compactionGroup struct {
+	head   *MonitoringRun
+	merged []int64
+}
+
+// compactor groups consecutive identical runs. Runs are added ordered by
+// endpoint and then by time; the head of each group is updated in place to
+// count the checks of the runs merged into it.
+type compactor struct {
+	head    *MonitoringRun
+	headKey compactionKey
+	merged  []int64
+	groups  []compactionGroup
+}
+
+// add adds the next run
+func (c *compactor) add(run *MonitoringRun) {
+	key := compactionKeyOf(run)
+	if c.head != nil && key == c.headKey {
+		c.head.CheckCount = c.head.Checks() + run.Checks()
+		if run.LastTimestamp.After(c.head.LastTimestamp) {
+			c.head.LastTimestamp = run.LastTimestamp
+		}
+		c.merged = append(c.merged, run.ID)
+		return
+	}
+
+	c.flush()
+	c.head, c.headKey = run, key
+	// The body is no longer needed once the key is known
+	c.head.ResponseBody = ""
+}
+
+// flush ends the current group
+func (c *compactor) flush() {
+	if c.head != nil && len(c.merged) > 0 {
+		c.groups = append(c.groups, compactionGroup{head: c.head, merged: c.merged})
+	}
+	c.head, c.merged = nil, nil
+}
+
+// finish returns the groups with at least one run to merge
+func (c *compactor) finish() []compactionGroup {
+	c.flush()
+	return c.groups
+}
+
+// CompactMonitoringRuns merges each run recorded before olderThan into the run
+// before it when both have the same status, failure and response body, so that
+// an endpoint that answers the same way check after check keeps one run for
+// the whole streak. The run kept counts the checks merged into it and the time
+// of the last of them, but keeps its own response time and headers. Runs that
+// shared the body of a merged run share it from the kept run instead. It
+// returns the number of runs merged away.
+func (s *SQLiteStorage) CompactMonitoringRuns(olderThan time.Time) (int64, error) {
+	query := `
+		SELECT id, endpoint_id, timestamp, response_status, failure_category, error_message,
+			response_body, response_body_hash, body_run_id, check_count, last_timestamp
+		FROM monitoring_runs
+		WHERE timestamp < ?
+		ORDER BY endpoint_id, timestamp, id
+	`
+
+	rows, err := s.db.Query(query, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query monitoring runs to compact: %w", err)
+	}
+
+	var c compactor
+	for rows.Next() {
+		var run MonitoringRun
+		var failureCategory, errorMessage, bodyHash sql.NullString
+		var bodyRunID sql.NullInt64
+		var lastTimestamp sql.NullTime
+		if err := rows.Scan(&run.ID, &run.EndpointID, &run.Timestamp, &run.ResponseStatus,
+			&failureCategory, &errorMessage, &run.ResponseBody, &bodyHash, &bodyRunID,
+			&run.CheckCount, &lastTimestamp); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan monitoring run: %w", err)
+		}
+		run.FailureCategory = failureCategory.String
+		run.ErrorMessage = errorMessage.String
+		run.ResponseBodyHash = bodyHash.String
+		run.BodyRunID = bodyRunID.Int64
+		run.LastTimestamp = run.Timestamp
+		if lastTimestamp.Valid {
+			run.LastTimestamp = lastTimestamp.Time
+		}
+		c.add(&run)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, fmt.Errorf("failed to read monitoring runs: %w", err)
+	}
+	rows.Close()
+
+	groups := c.finish()
+	if len(groups) == 0 {
+		return 0, nil
+	}
+
+	var compacted int64
+	err = s.withWriteLock(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback() // nolint:errcheck
+
+		compacted = 0
+		for source, target := range bodySources(groups) {
+			if _, err := tx.Exec(`UPDATE monitoring_runs SET body_run_id = ? WHERE body_run_id = ?`, target, source); err != nil {
+				return err
+			}
+		}
+		for _, group := range groups {
+			if _, err := tx.Exec(`UPDATE monitoring_runs SET check_count = ?, last_timestamp = ? WHERE id = ?`,
+				group.head.CheckCount, group.head.LastTimestamp, group.head.ID); err != nil {
+				return err
+			}
+			for _, id := range group.merged {
+				result, err := tx.Exec(`DELETE FROM monitoring_runs WHERE id = ?`, id)
+				if err != nil {
+					return err
+				}
+				deleted, err := result.RowsAffected()
+				if err != nil {
+					return err
+				}
+				compacted += deleted
+			}
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to compact monitoring runs: %w", err)
+	}
+
+	if s.bodies != nil {
+		if err := s.pruneResponseBodies(); err != nil {
+			return compacted, err
+		}
+	}
+
+	return compacted, nil
+}
+
+// bodySources maps each run merged away to the run that runs sharing its body
+// share it from instead: the run it was merged into, or the run that one
+// shares its body from. Groups are expected in the order of their runs.
+func bodySources(groups []compactionGroup) map[int64]int64 {
+	targets := make(map[int64]int64)
+	for _, group := range groups {
+		target := group.head.ID
+		if group.head.BodyRunID != 0 {
+			target = group.head.BodyRunID
+			if redirected, ok := targets[target]; ok {
+				target = redirected
+			}
+		}
+		for _, id := range group.merged {
+			targets[id] = target
+		}
+	}
+	return targets
+}
+
+// CompactMonitoringRuns merges consecutive identical runs recorded before
+// olderThan, as SQLiteStorage does
+func (m *InMemoryStorage) CompactMonitoringRuns(olderThan time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var compacted int64
+	for endpointID, runs := range m.monitoringRuns {
+		// Runs are stored newest first
+		var c compactor
+		for i := len(runs) - 1; i >= 0; i-- {
+			if runs[i].Timestamp.Before(olderThan) {
+				candidate := *runs[i]
+				c.add(&candidate)
+			}
+		}
+		groups := c.finish()
+		if len(groups) == 0 {
+			continue
+		}
+
+		heads := make(map[int64]*MonitoringRun, len(groups))
+		merged := make(map[int64]bool)
+		for _, group := range groups {
+			heads[group.head.ID] = group.head
+			for _, id := range group.merged {
+				merged[id] = true
+			}
+		}
+		sources := bodySources(groups)
+
+		var kept []*MonitoringRun
+		for _, run := range runs {
+			if merged[run.ID] {
+				compacted++
+				continue
+			}
+			if head, ok := heads[run.ID]; ok {
+				run.CheckCount = head.CheckCount
+				run.LastTimestamp = head.LastTimestamp
+			}
+			if target, ok := sources[run.BodyRunID]; ok {
+				run.BodyRunID = target
+			}
+			kept = append(kept, run)
+		}
+		m.monitoringRuns[endpointID] = kept
+	}
+
+	return compacted, nil
+}
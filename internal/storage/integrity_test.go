@@ -156,7 +156,7 @@ func TestSQLiteHealthStatus(t *testing.T) {
 	assert.True(t, health.Healthy)
 	assert.Equal(t, "excellent", health.Status)
 	assert.Equal(t, 0, health.IntegrityIssues)
-	assert.Equal(t, 1, health.SchemaVersion)
+	assert.Equal(t, len(getMigrations()), health.SchemaVersion)
 	assert.True(t, health.FragmentationLevel >= 0)
 
 	// Check recommendations (may vary based on database size and state)
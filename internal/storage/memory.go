@@ -4,6 +4,7 @@ package storage
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -15,9 +16,14 @@ type InMemoryStorage struct {
 	monitoringRuns map[string][]*MonitoringRun // keyed by endpoint ID
 	drifts         []*Drift
 	alerts         []*Alert
+	sinkCursors    map[string]int64 // last drift ID delivered, keyed by sink name
+	windows        []*MaintenanceWindow
+	suppressions   map[string]bool // suppressed drift fingerprints
+	lastHeartbeat  time.Time
 	nextDriftID    int64
 	nextAlertID    int64
 	nextRunID      int64
+	nextWindowID   int64
 	mu             sync.RWMutex
 }
 
@@ -28,9 +34,12 @@ func NewInMemoryStorage() (Storage, error) {
 		monitoringRuns: make(map[string][]*MonitoringRun),
 		drifts:         make([]*Drift, 0),
 		alerts:         make([]*Alert, 0),
+		sinkCursors:    make(map[string]int64),
+		suppressions:   make(map[string]bool),
 		nextDriftID:    1,
 		nextAlertID:    1,
 		nextRunID:      1,
+		nextWindowID:   1,
 	}, nil
 }
 
@@ -115,9 +124,17 @@ func (m *InMemoryStorage) SaveMonitoringRun(run *MonitoringRun) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if run.CheckCount == 0 {
+		run.CheckCount = 1
+	}
+	if run.LastTimestamp.IsZero() {
+		run.LastTimestamp = run.Timestamp
+	}
+
 	// Create a copy and assign ID
 	runCopy := *run
 	runCopy.ID = m.nextRunID
+	run.ID = runCopy.ID
 	m.nextRunID++
 
 	// Add to the endpoint's runs
@@ -154,6 +171,7 @@ func (m *InMemoryStorage) GetMonitoringHistory(endpointID string, period time.Du
 		if run.Timestamp.After(cutoff) {
 			// Create a copy to prevent external modifications
 			runCopy := *run
+			m.loadSharedBody(&runCopy)
 			filteredRuns = append(filteredRuns, &runCopy)
 		}
 	}
@@ -161,6 +179,64 @@ func (m *InMemoryStorage) GetMonitoringHistory(endpointID string, period time.Du
 	return filteredRuns, nil
 }
 
+// GetMonitoringHistorySample retrieves at most maxSamples evenly spaced
+// successful runs of an endpoint's monitoring history, selected as by
+// SQLiteStorage
+func (m *InMemoryStorage) GetMonitoringHistorySample(endpointID string, period time.Duration, maxSamples int) ([]*MonitoringRun, error) {
+	runs, err := m.GetMonitoringHistory(endpointID, period)
+	if err != nil {
+		return nil, err
+	}
+	runs = successfulRuns(runs)
+	if maxSamples <= 0 {
+		return runs, nil
+	}
+
+	var sampled []*MonitoringRun
+	for i, run := range runs {
+		if i*maxSamples%len(runs) < maxSamples {
+			sampled = append(sampled, run)
+		}
+	}
+
+	return sampled, nil
+}
+
+// GetMonitoringRun retrieves a monitoring run by ID
+func (m *InMemoryStorage) GetMonitoringRun(id int64) (*MonitoringRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, runs := range m.monitoringRuns {
+		for _, run := range runs {
+			if run.ID == id {
+				// Return a copy to prevent external modifications
+				runCopy := *run
+				m.loadSharedBody(&runCopy)
+				return &runCopy, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrMonitoringRunNotFound, id)
+}
+
+// loadSharedBody reads the body of a run sharing the body of an earlier run
+// from that run, as SQLiteStorage does. The caller must hold the lock.
+func (m *InMemoryStorage) loadSharedBody(run *MonitoringRun) {
+	if run.BodyRunID == 0 {
+		return
+	}
+
+	run.ResponseBody = ""
+	for _, source := range m.monitoringRuns[run.EndpointID] {
+		if source.ID == run.BodyRunID {
+			run.ResponseBody = source.ResponseBody
+			return
+		}
+	}
+}
+
 // SaveDrift saves a drift to memory
 func (m *InMemoryStorage) SaveDrift(drift *Drift) error {
 	if drift == nil {
@@ -171,6 +247,10 @@ func (m *InMemoryStorage) SaveDrift(drift *Drift) error {
 		return fmt.Errorf("endpoint ID cannot be empty")
 	}
 
+	if drift.Fingerprint == "" {
+		drift.Fingerprint = drift.ComputeFingerprint()
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -179,11 +259,35 @@ func (m *InMemoryStorage) SaveDrift(drift *Drift) error {
 	driftCopy.ID = m.nextDriftID
 	m.nextDriftID++
 
+	// Drifts whose fingerprint was suppressed are stored acknowledged
+	if m.suppressions[driftCopy.Fingerprint] {
+		driftCopy.Acknowledged = true
+	}
+
 	if driftCopy.DetectedAt.IsZero() {
 		driftCopy.DetectedAt = time.Now()
 	}
 
+	// Carry over the first detection of the field path and move the last
+	// detection of earlier drifts of the path to this one
+	driftCopy.FirstDetectedAt = driftCopy.DetectedAt
+	driftCopy.LastDetectedAt = driftCopy.DetectedAt
+	for _, existing := range m.drifts {
+		if existing.EndpointID != driftCopy.EndpointID || existing.FieldPath != driftCopy.FieldPath {
+			continue
+		}
+		if existing.FirstDetectedAt.Before(driftCopy.FirstDetectedAt) {
+			driftCopy.FirstDetectedAt = existing.FirstDetectedAt
+		}
+		existing.LastDetectedAt = driftCopy.DetectedAt
+	}
+
 	m.drifts = append(m.drifts, &driftCopy)
+	drift.ID = driftCopy.ID
+	drift.Acknowledged = driftCopy.Acknowledged
+	drift.DetectedAt = driftCopy.DetectedAt
+	drift.FirstDetectedAt = driftCopy.FirstDetectedAt
+	drift.LastDetectedAt = driftCopy.LastDetectedAt
 
 	// Sort drifts by detection time (most recent first)
 	sort.Slice(m.drifts, func(i, j int) bool {
@@ -193,6 +297,22 @@ func (m *InMemoryStorage) SaveDrift(drift *Drift) error {
 	return nil
 }
 
+// GetDrift retrieves a single drift by ID
+func (m *InMemoryStorage) GetDrift(id int64) (*Drift, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, drift := range m.drifts {
+		if drift.ID == id {
+			// Return a copy to prevent external modifications
+			driftCopy := *drift
+			return &driftCopy, nil
+		}
+	}
+
+	return nil, fmt.Errorf("drift not found: %d", id)
+}
+
 // GetDrifts retrieves drifts based on filters
 func (m *InMemoryStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 	m.mu.RLock()
@@ -206,6 +326,10 @@ func (m *InMemoryStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 			continue
 		}
 
+		if filters.FieldPath != "" && drift.FieldPath != filters.FieldPath {
+			continue
+		}
+
 		if filters.Severity != "" && drift.Severity != filters.Severity {
 			continue
 		}
@@ -230,6 +354,266 @@ func (m *InMemoryStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 	return filteredDrifts, nil
 }
 
+// GetDriftsAfter retrieves up to limit drifts with an ID greater than afterID,
+// in the order they were saved
+func (m *InMemoryStorage) GetDriftsAfter(afterID int64, limit int) ([]*Drift, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var drifts []*Drift
+	for _, drift := range m.drifts {
+		if drift.ID > afterID {
+			driftCopy := *drift
+			drifts = append(drifts, &driftCopy)
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].ID < drifts[j].ID
+	})
+
+	if limit > 0 && len(drifts) > limit {
+		drifts = drifts[:limit]
+	}
+
+	return drifts, nil
+}
+
+// GetAlertedUnresolvedDrifts retrieves up to limit drifts of an endpoint, newest
+// first, that are not resolved and that an alert was sent about
+func (m *InMemoryStorage) GetAlertedUnresolvedDrifts(endpointID string, limit int) ([]*Drift, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alerted := make(map[int64]bool)
+	for _, alert := range m.alerts {
+		if alert.Status == "sent" {
+			alerted[alert.DriftID] = true
+		}
+	}
+
+	var drifts []*Drift
+	for _, drift := range m.drifts {
+		if drift.EndpointID != endpointID || drift.ResolvedAt != nil || !alerted[drift.ID] {
+			continue
+		}
+		driftCopy := *drift
+		drifts = append(drifts, &driftCopy)
+		if len(drifts) == limit {
+			break
+		}
+	}
+
+	return drifts, nil
+}
+
+// SearchDrifts retrieves up to limit drifts, newest first, whose description or
+// field path contains every whitespace-separated term of text, ignoring case.
+// A limit of zero returns every match.
+func (m *InMemoryStorage) SearchDrifts(text string, limit int) ([]*Drift, error) {
+	terms := strings.Fields(strings.ToLower(text))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search text is empty")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var drifts []*Drift
+	for _, drift := range m.drifts {
+		description := strings.ToLower(drift.Description)
+		fieldPath := strings.ToLower(drift.FieldPath)
+
+		matches := true
+		for _, term := range terms {
+			if !strings.Contains(description, term) && !strings.Contains(fieldPath, term) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			driftCopy := *drift
+			drifts = append(drifts, &driftCopy)
+		}
+	}
+
+	sort.SliceStable(drifts, func(i, j int) bool {
+		if !drifts[i].DetectedAt.Equal(drifts[j].DetectedAt) {
+			return drifts[i].DetectedAt.After(drifts[j].DetectedAt)
+		}
+		return drifts[i].ID > drifts[j].ID
+	})
+
+	if limit > 0 && len(drifts) > limit {
+		drifts = drifts[:limit]
+	}
+
+	return drifts, nil
+}
+
+// AcknowledgeDrifts acknowledges the unacknowledged drifts of an endpoint
+// detected before the given time, returning how many were acknowledged
+func (m *InMemoryStorage) AcknowledgeDrifts(endpointID string, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var acknowledged int64
+	for _, drift := range m.drifts {
+		if drift.EndpointID == endpointID && drift.DetectedAt.Before(before) && !drift.Acknowledged {
+			drift.Acknowledged = true
+			acknowledged++
+		}
+	}
+
+	return acknowledged, nil
+}
+
+// AcknowledgeFingerprint acknowledges the unacknowledged drifts with a
+// fingerprint, returning how many were acknowledged
+func (m *InMemoryStorage) AcknowledgeFingerprint(fingerprint string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var acknowledged int64
+	for _, drift := range m.drifts {
+		if drift.Fingerprint == fingerprint && !drift.Acknowledged {
+			drift.Acknowledged = true
+			acknowledged++
+		}
+	}
+
+	return acknowledged, nil
+}
+
+// ResolveDrift records when a drift was resolved
+func (m *InMemoryStorage) ResolveDrift(id int64, resolvedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, drift := range m.drifts {
+		if drift.ID == id {
+			drift.ResolvedAt = &resolvedAt
+			return nil
+		}
+	}
+
+	return fmt.Errorf("drift not found: %d", id)
+}
+
+// SuppressFingerprint records a fingerprint whose future drifts are
+// acknowledged and not alerted on
+func (m *InMemoryStorage) SuppressFingerprint(fingerprint string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.suppressions[fingerprint] = true
+	return nil
+}
+
+// IsFingerprintSuppressed reports whether a fingerprint has been suppressed
+func (m *InMemoryStorage) IsFingerprintSuppressed(fingerprint string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.suppressions[fingerprint], nil
+}
+
+// GetSinkCursor returns the ID of the last drift delivered to the named sink.
+// A sink seen for the first time starts after the latest drift.
+func (m *InMemoryStorage) GetSinkCursor(name string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.sinkCursors[name]; !exists {
+		m.sinkCursors[name] = m.nextDriftID - 1
+	}
+
+	return m.sinkCursors[name], nil
+}
+
+// SaveSinkCursor records the ID of the last drift delivered to the named sink
+func (m *InMemoryStorage) SaveSinkCursor(name string, lastDriftID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sinkCursors[name] = lastDriftID
+	return nil
+}
+
+// SaveHeartbeat records a heartbeat of the monitor, replacing the previous one
+func (m *InMemoryStorage) SaveHeartbeat(at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastHeartbeat = at
+	return nil
+}
+
+// GetLastHeartbeat returns the time of the last heartbeat of the monitor, or
+// the zero time if none has been recorded
+func (m *InMemoryStorage) GetLastHeartbeat() (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.lastHeartbeat, nil
+}
+
+// SaveMaintenanceWindow saves a new maintenance window to memory
+func (m *InMemoryStorage) SaveMaintenanceWindow(window *MaintenanceWindow) error {
+	if window == nil {
+		return fmt.Errorf("maintenance window cannot be nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	windowCopy := *window
+	windowCopy.ID = m.nextWindowID
+	window.ID = windowCopy.ID
+	m.nextWindowID++
+
+	m.windows = append(m.windows, &windowCopy)
+	return nil
+}
+
+// GetActiveMaintenanceWindows returns the maintenance windows in effect at the
+// given time, oldest first
+func (m *InMemoryStorage) GetActiveMaintenanceWindows(at time.Time) ([]*MaintenanceWindow, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var windows []*MaintenanceWindow
+	for _, window := range m.windows {
+		if !at.Before(window.StartedAt) && at.Before(window.EndsAt) {
+			windowCopy := *window
+			windows = append(windows, &windowCopy)
+		}
+	}
+
+	return windows, nil
+}
+
+// EndMaintenanceWindows ends the maintenance windows of an endpoint that are
+// in effect at the given time, or every active window when endpointID is empty
+func (m *InMemoryStorage) EndMaintenanceWindows(endpointID string, at time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ended int64
+	for _, window := range m.windows {
+		if endpointID != "" && window.EndpointID != endpointID {
+			continue
+		}
+		if !at.Before(window.StartedAt) && at.Before(window.EndsAt) {
+			window.EndsAt = at
+			ended++
+		}
+	}
+
+	return ended, nil
+}
+
 // SaveAlert saves an alert to memory
 func (m *InMemoryStorage) SaveAlert(alert *Alert) error {
 	if alert == nil {
@@ -242,6 +626,7 @@ func (m *InMemoryStorage) SaveAlert(alert *Alert) error {
 	// Create a copy and assign ID
 	alertCopy := *alert
 	alertCopy.ID = m.nextAlertID
+	alert.ID = alertCopy.ID
 	m.nextAlertID++
 
 	if alertCopy.SentAt.IsZero() {
@@ -258,6 +643,33 @@ func (m *InMemoryStorage) SaveAlert(alert *Alert) error {
 	return nil
 }
 
+// UpdateAlert updates the delivery state of an existing alert
+func (m *InMemoryStorage) UpdateAlert(alert *Alert) error {
+	if alert == nil {
+		return fmt.Errorf("alert cannot be nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.alerts {
+		if existing.ID == alert.ID {
+			existing.SentAt = alert.SentAt
+			existing.Status = alert.Status
+			existing.ErrorMessage = alert.ErrorMessage
+			existing.RetryCount = alert.RetryCount
+
+			// Keep alerts sorted by sent time (most recent first)
+			sort.Slice(m.alerts, func(i, j int) bool {
+				return m.alerts[i].SentAt.After(m.alerts[j].SentAt)
+			})
+			return nil
+		}
+	}
+
+	return fmt.Errorf("alert not found: %d", alert.ID)
+}
+
 // GetAlerts retrieves alerts based on filters
 func (m *InMemoryStorage) GetAlerts(filters AlertFilters) ([]*Alert, error) {
 	m.mu.RLock()
@@ -315,12 +727,82 @@ func (m *InMemoryStorage) CleanupOldMonitoringRuns(olderThan time.Time) (int64,
 				totalCleaned++
 			}
 		}
+		reanchorSharedBodies(runs, filteredRuns)
 		m.monitoringRuns[endpointID] = filteredRuns
 	}
 
 	return totalCleaned, nil
 }
 
+// reanchorSharedBodies moves the body of each run removed from runs that kept
+// runs share to the oldest of them, and makes the others share it from that
+// run instead, as SQLiteStorage does. Runs are stored newest first.
+func reanchorSharedBodies(runs, kept []*MonitoringRun) {
+	removed := make(map[int64]*MonitoringRun, len(runs)-len(kept))
+	for _, run := range runs {
+		removed[run.ID] = run
+	}
+	for _, run := range kept {
+		delete(removed, run.ID)
+	}
+
+	newAnchors := make(map[int64]*MonitoringRun)
+	for i := len(kept) - 1; i >= 0; i-- {
+		run := kept[i]
+		anchor, ok := removed[run.BodyRunID]
+		if run.BodyRunID == 0 || !ok {
+			continue
+		}
+		if newAnchor, ok := newAnchors[anchor.ID]; ok {
+			run.BodyRunID = newAnchor.ID
+			continue
+		}
+		run.ResponseBody = anchor.ResponseBody
+		run.BodyRunID = 0
+		newAnchors[anchor.ID] = run
+	}
+}
+
+// TrimMonitoringRuns removes all but the newest keep monitoring runs of an endpoint
+func (m *InMemoryStorage) TrimMonitoringRuns(endpointID string, keep int) (int64, error) {
+	if keep < 0 {
+		return 0, fmt.Errorf("cannot keep a negative number of monitoring runs")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runs := m.monitoringRuns[endpointID]
+	if len(runs) <= keep {
+		return 0, nil
+	}
+
+	// Keep the stored order of the runs that remain
+	newest := make([]*MonitoringRun, len(runs))
+	copy(newest, runs)
+	sort.SliceStable(newest, func(i, j int) bool {
+		if !newest[i].Timestamp.Equal(newest[j].Timestamp) {
+			return newest[i].Timestamp.After(newest[j].Timestamp)
+		}
+		return newest[i].ID > newest[j].ID
+	})
+	kept := make(map[*MonitoringRun]bool, keep)
+	for _, run := range newest[:keep] {
+		kept[run] = true
+	}
+
+	var filteredRuns []*MonitoringRun
+	for _, run := range runs {
+		if kept[run] {
+			filteredRuns = append(filteredRuns, run)
+		}
+	}
+	reanchorSharedBodies(runs, filteredRuns)
+	m.monitoringRuns[endpointID] = filteredRuns
+
+	return int64(len(runs) - len(filteredRuns)), nil
+}
+
 // CleanupOldDrifts removes drifts older than the specified time
 func (m *InMemoryStorage) CleanupOldDrifts(olderThan time.Time) (int64, error) {
 	m.mu.Lock()
@@ -225,6 +225,19 @@ func TestInMemoryStorage_MonitoringRuns(t *testing.T) {
 		assert.Len(t, runs, 0)
 	})
 
+	t.Run("get monitoring run by ID", func(t *testing.T) {
+		runs, err := storage.GetMonitoringHistory("test-api", 24*time.Hour)
+		require.NoError(t, err)
+		require.NotEmpty(t, runs)
+
+		run, err := storage.GetMonitoringRun(runs[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "test-api", run.EndpointID)
+
+		_, err = storage.GetMonitoringRun(9999)
+		assert.Error(t, err)
+	})
+
 	t.Run("save nil monitoring run", func(t *testing.T) {
 		err := storage.SaveMonitoringRun(nil)
 		assert.Error(t, err)
@@ -297,6 +310,7 @@ func TestInMemoryStorage_Drifts(t *testing.T) {
 				DriftType:    "field_added",
 				Severity:     "low",
 				Description:  "Field added",
+				FieldPath:    "$.data.price",
 				Acknowledged: false,
 			},
 			{
@@ -305,6 +319,7 @@ func TestInMemoryStorage_Drifts(t *testing.T) {
 				DriftType:    "field_removed",
 				Severity:     "high",
 				Description:  "Field removed",
+				FieldPath:    "$.data.price.currency",
 				Acknowledged: true,
 			},
 			{
@@ -327,6 +342,12 @@ func TestInMemoryStorage_Drifts(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, api1Drifts, 2)
 
+		// Filter by field path, which must match exactly
+		priceDrifts, err := storage.GetDrifts(DriftFilters{EndpointID: "api-1", FieldPath: "$.data.price"})
+		require.NoError(t, err)
+		require.Len(t, priceDrifts, 1)
+		assert.Equal(t, "field_added", priceDrifts[0].DriftType)
+
 		// Filter by severity
 		highDrifts, err := storage.GetDrifts(DriftFilters{Severity: "high"})
 		require.NoError(t, err)
@@ -374,6 +395,58 @@ func TestInMemoryStorage_Drifts(t *testing.T) {
 	})
 }
 
+func TestInMemoryStorage_SearchDrifts(t *testing.T) {
+	storage, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	for _, drift := range []*Drift{
+		{EndpointID: "api-1", DetectedAt: now.Add(-time.Hour), DriftType: "field_added", Severity: "low", Description: "Field added", FieldPath: "$.price"},
+		{EndpointID: "api-1", DetectedAt: now, DriftType: "field_removed", Severity: "high", Description: "Field removed", FieldPath: "$.total"},
+	} {
+		require.NoError(t, storage.SaveDrift(drift))
+	}
+
+	drifts, err := storage.SearchDrifts("field", 0)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, "field_removed", drifts[0].DriftType) // Newest first
+
+	drifts, err = storage.SearchDrifts("FIELD price", 0)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, "field_added", drifts[0].DriftType)
+
+	drifts, err = storage.SearchDrifts("field", 1)
+	require.NoError(t, err)
+	assert.Len(t, drifts, 1)
+
+	drifts, err = storage.SearchDrifts("currency", 0)
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+}
+
+func TestInMemoryStorage_SuppressedFingerprints(t *testing.T) {
+	storage, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	defer storage.Close()
+
+	first := &Drift{EndpointID: "api-1", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+	require.NoError(t, storage.SaveDrift(first))
+	assert.False(t, first.Acknowledged)
+
+	require.NoError(t, storage.SuppressFingerprint(first.Fingerprint))
+
+	recurring := &Drift{EndpointID: "api-1", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+	require.NoError(t, storage.SaveDrift(recurring))
+	assert.True(t, recurring.Acknowledged)
+
+	retrieved, err := storage.GetDrift(recurring.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.Acknowledged)
+}
+
 func TestInMemoryStorage_Alerts(t *testing.T) {
 	storage, err := NewInMemoryStorage()
 	require.NoError(t, err)
@@ -477,6 +550,30 @@ func TestInMemoryStorage_Alerts(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot be nil")
 	})
+
+	t.Run("update alert", func(t *testing.T) {
+		storage.Close()
+		storage, _ = NewInMemoryStorage()
+		defer storage.Close()
+
+		err := storage.SaveAlert(&Alert{DriftID: 1, AlertType: "slack", ChannelName: "alerts", Status: "buffered"})
+		require.NoError(t, err)
+
+		buffered, err := storage.GetAlerts(AlertFilters{Status: "buffered"})
+		require.NoError(t, err)
+		require.Len(t, buffered, 1)
+
+		alert := buffered[0]
+		alert.Status = "sent"
+		require.NoError(t, storage.UpdateAlert(alert))
+
+		sent, err := storage.GetAlerts(AlertFilters{Status: "sent"})
+		require.NoError(t, err)
+		assert.Len(t, sent, 1)
+
+		assert.Error(t, storage.UpdateAlert(&Alert{ID: 999}))
+		assert.Error(t, storage.UpdateAlert(nil))
+	})
 }
 
 func TestInMemoryStorage_Close(t *testing.T) {
@@ -540,6 +637,39 @@ func TestInMemoryStorage_GetDatabaseStats(t *testing.T) {
 	assert.Equal(t, int64(0), stats.DatabaseSizeBytes) // Not applicable for in-memory
 }
 
+func TestInMemoryStorage_GetAlertedUnresolvedDrifts(t *testing.T) {
+	storage, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	now := time.Now()
+
+	save := func(endpointID string, age time.Duration, alertStatus string) *Drift {
+		drift := &Drift{EndpointID: endpointID, DetectedAt: now.Add(-age), DriftType: "value_change", Severity: "low", FieldPath: "$.a"}
+		require.NoError(t, storage.SaveDrift(drift))
+		if alertStatus != "" {
+			require.NoError(t, storage.SaveAlert(&Alert{DriftID: drift.ID, AlertType: "slack", ChannelName: "team", SentAt: now, Status: alertStatus}))
+		}
+		return drift
+	}
+
+	older := save("users", 2*time.Hour, "sent")
+	newer := save("users", time.Hour, "sent")
+	save("users", 3*time.Hour, "failed")
+	save("orders", time.Hour, "sent")
+	resolved := save("users", 30*time.Minute, "sent")
+	require.NoError(t, storage.ResolveDrift(resolved.ID, now))
+
+	drifts, err := storage.GetAlertedUnresolvedDrifts("users", 10)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, newer.ID, drifts[0].ID)
+	assert.Equal(t, older.ID, drifts[1].ID)
+
+	drifts, err = storage.GetAlertedUnresolvedDrifts("users", 1)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, newer.ID, drifts[0].ID)
+}
+
 func TestInMemoryStorage_ConcurrentAccess(t *testing.T) {
 	storage, err := NewInMemoryStorage()
 	require.NoError(t, err)
@@ -696,3 +826,99 @@ func TestInMemoryStorage_Cleanup(t *testing.T) {
 	err = storage.VacuumDatabase()
 	assert.NoError(t, err)
 }
+
+func TestInMemoryStorage_TrimMonitoringRuns(t *testing.T) {
+	storage, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, storage.SaveMonitoringRun(&MonitoringRun{
+			EndpointID:     "busy",
+			Timestamp:      now.Add(-time.Duration(i) * time.Minute),
+			ResponseStatus: 200,
+		}))
+	}
+	require.NoError(t, storage.SaveMonitoringRun(&MonitoringRun{EndpointID: "quiet", Timestamp: now, ResponseStatus: 200}))
+
+	trimmed, err := storage.TrimMonitoringRuns("busy", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), trimmed)
+
+	runs, err := storage.GetMonitoringHistory("busy", time.Hour)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	for _, run := range runs {
+		assert.True(t, run.Timestamp.After(now.Add(-2*time.Minute)), "only the newest runs are kept")
+	}
+
+	// Other endpoints are not affected
+	runs, err = storage.GetMonitoringHistory("quiet", time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	trimmed, err = storage.TrimMonitoringRuns("busy", 2)
+	require.NoError(t, err)
+	assert.Zero(t, trimmed)
+}
+
+func TestInMemoryStorage_TrimKeepsSharedBodies(t *testing.T) {
+	storage, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	full := &MonitoringRun{EndpointID: "stable", ResponseStatus: 200, ResponseBody: `{"v": 1}`, Timestamp: now.Add(-3 * time.Minute)}
+	require.NoError(t, storage.SaveMonitoringRun(full))
+	var sharers []*MonitoringRun
+	for i := 2; i >= 1; i-- {
+		run := &MonitoringRun{EndpointID: "stable", ResponseStatus: 200, BodyRunID: full.ID, Timestamp: now.Add(-time.Duration(i) * time.Minute)}
+		require.NoError(t, storage.SaveMonitoringRun(run))
+		sharers = append(sharers, run)
+	}
+
+	trimmed, err := storage.TrimMonitoringRuns("stable", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), trimmed)
+
+	anchor, err := storage.GetMonitoringRun(sharers[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, `{"v": 1}`, anchor.ResponseBody)
+	assert.Zero(t, anchor.BodyRunID)
+
+	sharer, err := storage.GetMonitoringRun(sharers[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, `{"v": 1}`, sharer.ResponseBody)
+	assert.Equal(t, sharers[0].ID, sharer.BodyRunID)
+}
+
+func TestInMemoryStorage_CompactMonitoringRuns(t *testing.T) {
+	storage, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	defer storage.Close()
+
+	now := time.Now()
+	for i, status := range []int{200, 200, 200, 503, 200} {
+		require.NoError(t, storage.SaveMonitoringRun(&MonitoringRun{
+			EndpointID:     "stable",
+			Timestamp:      now.Add(-time.Duration(50-10*i) * time.Minute),
+			ResponseStatus: status,
+			ResponseBody:   `{"ok":true}`,
+		}))
+	}
+
+	compacted, err := storage.CompactMonitoringRuns(now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), compacted)
+
+	runs, err := storage.GetMonitoringHistory("stable", time.Hour)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+
+	oldest := runs[len(runs)-1]
+	assert.Equal(t, 3, oldest.CheckCount)
+	assert.Equal(t, now.Add(-50*time.Minute), oldest.Timestamp)
+	assert.Equal(t, now.Add(-30*time.Minute), oldest.LastTimestamp)
+	assert.Equal(t, 1, runs[0].CheckCount)
+}
@@ -3,13 +3,15 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"os"
+	"time"
 )
 
 // Migration represents a database migration
 type Migration struct {
-	SQL         string
-	Description string
-	Version     int
+	SQL         string `json:"-"`
+	Description string `json:"description"`
+	Version     int    `json:"version"`
 }
 
 // migrationManager handles database schema migrations
@@ -45,8 +47,17 @@ func (m *migrationManager) getCurrentVersion() (int, error) {
 	return version, nil
 }
 
-// applyMigration applies a single migration
+// applyMigration applies a single migration. Migrations run before a storage
+// accepts writes, so there is no write lock to take, but the migration is retried
+// while another process holds the database lock.
 func (m *migrationManager) applyMigration(migration Migration) error {
+	return retryOnLock(func() error {
+		return m.applyMigrationOnce(migration)
+	})
+}
+
+// applyMigrationOnce applies a single migration in a transaction
+func (m *migrationManager) applyMigrationOnce(migration Migration) error {
 	// Ensure schema_version table exists
 	if _, err := m.getCurrentVersion(); err != nil {
 		return fmt.Errorf("failed to initialize schema version: %w", err)
@@ -78,22 +89,159 @@ func (m *migrationManager) applyMigration(migration Migration) error {
 
 // runMigrations applies all pending migrations
 func (m *migrationManager) runMigrations() error {
+	_, err := m.applyPending()
+	return err
+}
+
+// applyPending applies all pending migrations and returns the ones that were applied
+func (m *migrationManager) applyPending() ([]Migration, error) {
+	pending, err := m.pendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range pending {
+		if err := m.applyMigration(migration); err != nil {
+			return applied, fmt.Errorf("failed to apply migration: %w", err)
+		}
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// pendingMigrations returns the migrations newer than the current schema version
+func (m *migrationManager) pendingMigrations() ([]Migration, error) {
 	currentVersion, err := m.getCurrentVersion()
 	if err != nil {
-		return fmt.Errorf("failed to get current version: %w", err)
+		return nil, fmt.Errorf("failed to get current version: %w", err)
 	}
 
-	migrations := getMigrations()
+	var pending []Migration
+	for _, migration := range getMigrations() {
+		if migration.Version > currentVersion {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// queryAppliedMigrations reads the migrations recorded in the schema_version table
+func queryAppliedMigrations(db *sql.DB) ([]AppliedMigration, error) {
+	rows, err := db.Query("SELECT version, applied_at FROM schema_version ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	descriptions := make(map[int]string)
+	for _, migration := range getMigrations() {
+		descriptions[migration.Version] = migration.Description
+	}
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var migration AppliedMigration
+		if err := rows.Scan(&migration.Version, &migration.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		migration.Description = descriptions[migration.Version]
+		applied = append(applied, migration)
+	}
+
+	return applied, rows.Err()
+}
+
+// MigrationStatus describes the schema version of a database and the migrations
+// that have not yet been applied to it
+type MigrationStatus struct {
+	Applied        []AppliedMigration `json:"applied"`
+	Pending        []Migration        `json:"pending"`
+	CurrentVersion int                `json:"current_version"`
+	LatestVersion  int                `json:"latest_version"`
+}
+
+// AppliedMigration is a migration recorded in the database
+type AppliedMigration struct {
+	AppliedAt   time.Time `json:"applied_at"`
+	Description string    `json:"description"`
+	Version     int       `json:"version"`
+}
+
+// GetMigrationStatus reports the schema version of the database at dbPath
+// without applying any migrations. The database is opened read-only, so neither
+// the database file nor the schema_version table is created if missing.
+func GetMigrationStatus(dbPath string) (*MigrationStatus, error) {
+	if !isMemoryDSN(dbPath) {
+		if _, err := os.Stat(sqliteFilePath(dbPath)); err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("database %s does not exist: %w", sqliteFilePath(dbPath), err)
+			}
+			return nil, fmt.Errorf("failed to check database file: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", readOnlySQLiteDSN(dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	applied, err := readAppliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
 
+	currentVersion := 0
+	for _, migration := range applied {
+		if migration.Version > currentVersion {
+			currentVersion = migration.Version
+		}
+	}
+
+	migrations := getMigrations()
+	var pending []Migration
 	for _, migration := range migrations {
 		if migration.Version > currentVersion {
-			if err := m.applyMigration(migration); err != nil {
-				return fmt.Errorf("failed to apply migration: %w", err)
-			}
+			pending = append(pending, migration)
 		}
 	}
 
-	return nil
+	return &MigrationStatus{
+		Applied:        applied,
+		Pending:        pending,
+		CurrentVersion: currentVersion,
+		LatestVersion:  migrations[len(migrations)-1].Version,
+	}, nil
+}
+
+// readAppliedMigrations returns the migrations recorded in the schema_version
+// table, or none if the table does not exist
+func readAppliedMigrations(db *sql.DB) ([]AppliedMigration, error) {
+	var tables int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'").Scan(&tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	if tables == 0 {
+		return nil, nil
+	}
+
+	return queryAppliedMigrations(db)
+}
+
+// ApplyMigrations applies all pending migrations to the database at dbPath and
+// returns the migrations that were applied
+func ApplyMigrations(dbPath string) ([]Migration, error) {
+	db, err := openSQLiteDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return newMigrationManager(db).applyPending()
 }
 
 // getMigrations returns all available migrations in order
@@ -178,11 +326,158 @@ func getMigrations() []Migration {
 				CREATE INDEX IF NOT EXISTS idx_alerts_channel_name ON alerts(channel_name);
 			`,
 		},
+		{
+			Version:     2,
+			Description: "Add failure classification to monitoring runs",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN failure_category TEXT;
+				ALTER TABLE monitoring_runs ADD COLUMN error_message TEXT;
+
+				CREATE INDEX IF NOT EXISTS idx_monitoring_runs_failure_category ON monitoring_runs(failure_category);
+			`,
+		},
+		{
+			Version:     3,
+			Description: "Record the number of samples taken per monitoring run",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN sample_count INTEGER NOT NULL DEFAULT 1;
+			`,
+		},
+		{
+			Version:     4,
+			Description: "Record the TLS certificate of monitoring runs",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN tls_not_after DATETIME;
+				ALTER TABLE monitoring_runs ADD COLUMN tls_issuer TEXT;
+				ALTER TABLE monitoring_runs ADD COLUMN tls_fingerprint TEXT;
+			`,
+		},
+		{
+			Version:     5,
+			Description: "Track drift delivery to event sinks",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS sink_cursors (
+					name TEXT PRIMARY KEY,
+					last_drift_id INTEGER NOT NULL DEFAULT 0,
+					updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+				);
+			`,
+		},
+		{
+			Version:     6,
+			Description: "Record fields that varied between the samples of a monitoring run",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN volatile_fields TEXT;
+			`,
+		},
+		{
+			Version:     7,
+			Description: "Track when each drifting field path was first and last detected",
+			SQL: `
+				ALTER TABLE drifts ADD COLUMN first_detected_at DATETIME;
+				ALTER TABLE drifts ADD COLUMN last_detected_at DATETIME;
+				CREATE INDEX IF NOT EXISTS idx_drifts_endpoint_path ON drifts(endpoint_id, field_path);
+				UPDATE drifts SET
+					first_detected_at = (SELECT earlier.detected_at FROM drifts AS earlier
+						WHERE earlier.endpoint_id = drifts.endpoint_id AND earlier.field_path IS drifts.field_path
+						ORDER BY earlier.id ASC LIMIT 1),
+					last_detected_at = (SELECT later.detected_at FROM drifts AS later
+						WHERE later.endpoint_id = drifts.endpoint_id AND later.field_path IS drifts.field_path
+						ORDER BY later.id DESC LIMIT 1);
+			`,
+		},
+		{
+			Version:     8,
+			Description: "Record the HTTP protocol version and trailers of monitoring runs",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN protocol TEXT;
+				ALTER TABLE monitoring_runs ADD COLUMN response_trailers TEXT;
+			`,
+		},
+		{
+			Version:     9,
+			Description: "Add maintenance windows and tag the drift detected during them",
+			SQL: `
+				ALTER TABLE drifts ADD COLUMN tag TEXT;
+				CREATE TABLE IF NOT EXISTS maintenance_windows (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					endpoint_id TEXT NOT NULL DEFAULT '',
+					started_at DATETIME NOT NULL,
+					ends_at DATETIME NOT NULL,
+					auto_acknowledge BOOLEAN DEFAULT FALSE
+				);
+				CREATE INDEX IF NOT EXISTS idx_maintenance_windows_ends_at ON maintenance_windows(ends_at);
+			`,
+		},
+		{
+			Version:     10,
+			Description: "Fingerprint drifts and suppress fingerprints acknowledged forever",
+			SQL: `
+				ALTER TABLE drifts ADD COLUMN fingerprint TEXT;
+				CREATE INDEX IF NOT EXISTS idx_drifts_fingerprint ON drifts(fingerprint);
+				CREATE TABLE IF NOT EXISTS drift_suppressions (
+					fingerprint TEXT PRIMARY KEY,
+					created_at DATETIME NOT NULL
+				);
+			`,
+		},
+		{
+			Version:     11,
+			Description: "Record the last heartbeat of the monitor",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS heartbeats (
+					id INTEGER PRIMARY KEY CHECK (id = 1),
+					beat_at DATETIME NOT NULL
+				);
+			`,
+		},
+		{
+			Version:     12,
+			Description: "Record the timing breakdown of monitoring runs",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN dns_time_ms INTEGER NOT NULL DEFAULT 0;
+				ALTER TABLE monitoring_runs ADD COLUMN connect_time_ms INTEGER NOT NULL DEFAULT 0;
+				ALTER TABLE monitoring_runs ADD COLUMN tls_time_ms INTEGER NOT NULL DEFAULT 0;
+				ALTER TABLE monitoring_runs ADD COLUMN ttfb_ms INTEGER NOT NULL DEFAULT 0;
+			`,
+		},
+		{
+			Version:     13,
+			Description: "Mark monitoring runs whose response body was cut short by a stream read limit",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN response_truncated BOOLEAN NOT NULL DEFAULT FALSE;
+			`,
+		},
+		{
+			Version:     14,
+			Description: "Reference response bodies stored as files by their hash",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN response_body_hash TEXT;
+				CREATE INDEX IF NOT EXISTS idx_monitoring_runs_response_body_hash ON monitoring_runs(response_body_hash);
+			`,
+		},
+		{
+			Version:     15,
+			Description: "Share the response body of unchanged monitoring runs with an earlier run",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN body_run_id INTEGER;
+			`,
+		},
+		{
+			Version:     16,
+			Description: "Record when drifts were resolved",
+			SQL: `
+				ALTER TABLE drifts ADD COLUMN resolved_at DATETIME;
+			`,
+		},
+		{
+			Version:     17,
+			Description: "Count the checks of monitoring runs merged by compaction",
+			SQL: `
+				ALTER TABLE monitoring_runs ADD COLUMN check_count INTEGER NOT NULL DEFAULT 1;
+				ALTER TABLE monitoring_runs ADD COLUMN last_timestamp DATETIME;
+			`,
+		},
 		// Future migrations can be added here
-		// {
-		//     Version:     2,
-		//     Description: "Add new column to endpoints table",
-		//     SQL: `ALTER TABLE endpoints ADD COLUMN new_field TEXT;`,
-		// },
 	}
 }
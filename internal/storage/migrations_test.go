@@ -196,7 +196,7 @@ func TestMigrationManager_PartialMigrations(t *testing.T) {
 	// Verify we have the correct version
 	version, err := mgr.getCurrentVersion()
 	require.NoError(t, err)
-	assert.Equal(t, 1, version) // Should be 1 from our migration system
+	assert.Equal(t, len(getMigrations()), version) // Should be the latest migration
 }
 
 func TestGetMigrations(t *testing.T) {
@@ -252,3 +252,77 @@ func TestSQLiteStorageWithMigrations(t *testing.T) {
 	require.NoError(t, err)
 	assert.Greater(t, version, 0)
 }
+
+func TestMigrationStatusAndApply(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "status.db")
+
+	// Bring the database to version 1 only
+	db, err := openSQLiteDB(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, newMigrationManager(db).applyMigration(getMigrations()[0]))
+	require.NoError(t, db.Close())
+
+	migrations := getMigrations()
+	latest := migrations[len(migrations)-1].Version
+
+	status, err := GetMigrationStatus(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.CurrentVersion)
+	assert.Equal(t, latest, status.LatestVersion)
+	require.Len(t, status.Applied, 1)
+	assert.Equal(t, migrations[0].Description, status.Applied[0].Description)
+	assert.Len(t, status.Pending, len(migrations)-1)
+
+	// Status must not apply anything
+	status, err = GetMigrationStatus(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.CurrentVersion)
+
+	applied, err := ApplyMigrations(dbPath)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations)-1)
+
+	status, err = GetMigrationStatus(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, latest, status.CurrentVersion)
+	assert.Empty(t, status.Pending)
+	assert.Len(t, status.Applied, len(migrations))
+
+	applied, err = ApplyMigrations(dbPath)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestMigrationStatusDoesNotInitialize(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A missing database is reported, not created
+	missingPath := filepath.Join(tmpDir, "missing.db")
+	_, err := GetMigrationStatus(missingPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+	_, err = os.Stat(missingPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// A database without a schema_version table is at version 0 and stays untouched
+	dbPath := filepath.Join(tmpDir, "empty.db")
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE other (id INTEGER)")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	status, err := GetMigrationStatus(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.CurrentVersion)
+	assert.Empty(t, status.Applied)
+	assert.Len(t, status.Pending, len(getMigrations()))
+
+	db, err = sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	var tables int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_version'").Scan(&tables))
+	assert.Equal(t, 0, tables)
+}
@@ -3,42 +3,240 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
+const (
+	// DefaultBusyTimeout is how long a statement waits for a lock held by another
+	// connection or process before failing with "database is locked"
+	DefaultBusyTimeout = 5 * time.Second
+
+	// sqliteMaxOpenConns bounds the connection pool. Reads run in parallel under
+	// WAL; writes are serialized by SQLiteStorage.
+	sqliteMaxOpenConns = 8
+	sqliteMaxIdleConns = 4
+
+	// writeRetryAttempts and writeRetryDelay control how writes failing with a
+	// lock error are retried once the busy timeout has passed
+	writeRetryAttempts = 3
+	writeRetryDelay    = 50 * time.Millisecond
+)
+
+// SQLiteOptions holds connection settings for SQLite storage
+type SQLiteOptions struct {
+	BusyTimeout time.Duration // Zero uses DefaultBusyTimeout
+
+	// BodyDir is the directory response bodies of monitoring runs are stored
+	// in, as files named by their SHA-256; empty stores them in the database
+	BodyDir string
+}
+
 // SQLiteStorage implements the Storage interface using SQLite
 type SQLiteStorage struct {
 	db *sql.DB
+
+	// writeMu serializes writes from this process, since SQLite allows a
+	// single writer at a time
+	writeMu sync.Mutex
+
+	// bodies stores response bodies outside the database; nil stores them in it
+	bodies *bodyStore
 }
 
 // NewSQLiteStorage creates a new SQLite storage instance
 func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
-	db, err := sql.Open("sqlite", dbPath)
+	return NewSQLiteStorageWithOptions(dbPath, SQLiteOptions{})
+}
+
+// NewSQLiteStorageWithOptions creates a new SQLite storage instance with connection settings
+func NewSQLiteStorageWithOptions(dbPath string, options SQLiteOptions) (*SQLiteStorage, error) {
+	db, err := openSQLiteDBWithOptions(dbPath, options)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := &SQLiteStorage{db: db}
+
+	if options.BodyDir != "" {
+		storage.bodies, err = newBodyStore(options.BodyDir)
+		if err != nil {
+			db.Close() // nolint:errcheck
+			return nil, err
+		}
+	}
+
+	// Run database migrations
+	migrationMgr := newMigrationManager(db)
+	if err := migrationMgr.runMigrations(); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	if err := storage.backfillDriftFingerprints(); err != nil {
+		return nil, fmt.Errorf("failed to fingerprint drifts: %w", err)
+	}
+
+	return storage, nil
+}
+
+// backfillDriftFingerprints fingerprints drifts saved before fingerprints were
+// introduced. It only finds drifts to update once, after the migration adding them.
+func (s *SQLiteStorage) backfillDriftFingerprints() error {
+	drifts, err := s.queryDrifts(`SELECT ` + driftColumns + ` FROM drifts WHERE fingerprint IS NULL`)
+	if err != nil || len(drifts) == 0 {
+		return err
+	}
+
+	return s.withWriteLock(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback() // nolint:errcheck
+
+		for _, drift := range drifts {
+			if _, err := tx.Exec(`UPDATE drifts SET fingerprint = ? WHERE id = ?`, drift.ComputeFingerprint(), drift.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
+
+// openSQLiteDB opens a SQLite database with the connection settings used by DriftWatch
+func openSQLiteDB(dbPath string) (*sql.DB, error) {
+	return openSQLiteDBWithOptions(dbPath, SQLiteOptions{})
+}
+
+// openSQLiteDBWithOptions opens a SQLite database with the given connection settings
+func openSQLiteDBWithOptions(dbPath string, options SQLiteOptions) (*sql.DB, error) {
+	busyTimeout := options.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = DefaultBusyTimeout
+	}
+
+	db, err := sql.Open("sqlite", sqliteDSN(dbPath, busyTimeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Enable foreign keys and WAL mode for better performance
-	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+	// Every connection of an in-memory database is a separate database
+	if isMemoryDSN(dbPath) {
+		db.SetMaxOpenConns(1)
+	} else {
+		db.SetMaxOpenConns(sqliteMaxOpenConns)
+		db.SetMaxIdleConns(sqliteMaxIdleConns)
+	}
+
+	// Enable foreign keys, checking that the connection settings were applied
+	var foreignKeys int
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys); err != nil || foreignKeys != 1 {
+		db.Close()
+		if err == nil {
+			err = fmt.Errorf("foreign_keys is off")
+		}
 		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 
+	// WAL mode is stored in the database file, so it applies to all connections
 	if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
 	}
 
-	storage := &SQLiteStorage{db: db}
+	return db, nil
+}
 
-	// Run database migrations
-	migrationMgr := newMigrationManager(db)
-	if err := migrationMgr.runMigrations(); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+// sqliteDSN adds the pragmas applied to every pooled connection to a database
+// path. Pragmas run with db.Exec would only reach one connection of the pool.
+func sqliteDSN(dbPath string, busyTimeout time.Duration) string {
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
 	}
 
-	return storage, nil
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)&_pragma=foreign_keys(1)", dbPath, separator, busyTimeout.Milliseconds())
+}
+
+// readOnlySQLiteDSN returns a DSN that opens the database file at dbPath
+// read-only. Opening it fails instead of creating the file if it is missing.
+func readOnlySQLiteDSN(dbPath string) string {
+	if isMemoryDSN(dbPath) {
+		return sqliteDSN(dbPath, DefaultBusyTimeout)
+	}
+	return sqliteDSN("file:"+sqliteFilePath(dbPath)+"?mode=ro", DefaultBusyTimeout)
+}
+
+// sqliteFilePath returns the file a database path refers to, without a file:
+// prefix or query parameters
+func sqliteFilePath(dbPath string) string {
+	path := strings.TrimPrefix(dbPath, "file:")
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// isMemoryDSN reports whether a database path refers to an in-memory database
+func isMemoryDSN(dbPath string) bool {
+	return strings.Contains(dbPath, ":memory:") || strings.Contains(dbPath, "mode=memory")
+}
+
+// isLockError reports whether an error is SQLite failing to get a lock
+func isLockError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !stderrors.As(err, &sqliteErr) {
+		return false
+	}
+
+	// Extended result codes keep the primary code in the low byte
+	code := sqliteErr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+// execWrite runs a write statement. Writes are serialized within the process
+// and retried with increasing delays when the database stays locked by another
+// process beyond the busy timeout.
+func (s *SQLiteStorage) execWrite(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := s.withWriteLock(func() error {
+		var err error
+		result, err = s.db.Exec(query, args...)
+		return err
+	})
+	return result, err
+}
+
+// withWriteLock runs a write that cannot go through execWrite, such as a
+// transaction or maintenance statement, serialized and retried like execWrite.
+// The operation must be safe to run again after failing.
+func (s *SQLiteStorage) withWriteLock(op func() error) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return retryOnLock(op)
+}
+
+// retryOnLock runs an operation, retrying it with increasing delays while it
+// fails because the database is locked
+func retryOnLock(op func() error) error {
+	delay := writeRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || !isLockError(err) || attempt == writeRetryAttempts {
+			return err
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
 }
 
 // SaveEndpoint saves an endpoint configuration
@@ -54,7 +252,7 @@ func (s *SQLiteStorage) SaveEndpoint(endpoint *Endpoint) error {
 	}
 	endpoint.UpdatedAt = now
 
-	_, err := s.db.Exec(query, endpoint.ID, endpoint.URL, endpoint.Method,
+	_, err := s.execWrite(query, endpoint.ID, endpoint.URL, endpoint.Method,
 		endpoint.SpecFile, endpoint.Config, endpoint.CreatedAt, endpoint.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to save endpoint: %w", err)
@@ -137,8 +335,11 @@ func (s *SQLiteStorage) ListEndpoints() ([]*Endpoint, error) {
 func (s *SQLiteStorage) SaveMonitoringRun(run *MonitoringRun) error {
 	query := `
 		INSERT INTO monitoring_runs (endpoint_id, timestamp, response_status, response_time_ms, 
-			response_body, response_headers, validation_result)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+			response_body, response_headers, validation_result, failure_category, error_message, sample_count,
+			tls_not_after, tls_issuer, tls_fingerprint, volatile_fields, protocol, response_trailers,
+			dns_time_ms, connect_time_ms, tls_time_ms, ttfb_ms, response_truncated, response_body_hash,
+			body_run_id, check_count, last_timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	// Convert headers map to JSON
@@ -147,12 +348,68 @@ func (s *SQLiteStorage) SaveMonitoringRun(run *MonitoringRun) error {
 		return fmt.Errorf("failed to marshal response headers: %w", err)
 	}
 
+	var volatileFields sql.NullString
+	if len(run.VolatileFields) > 0 {
+		fieldsJSON, err := json.Marshal(run.VolatileFields)
+		if err != nil {
+			return fmt.Errorf("failed to marshal volatile fields: %w", err)
+		}
+		volatileFields = sql.NullString{String: string(fieldsJSON), Valid: true}
+	}
+
+	var trailers sql.NullString
+	if len(run.ResponseTrailers) > 0 {
+		trailersJSON, err := json.Marshal(run.ResponseTrailers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response trailers: %w", err)
+		}
+		trailers = sql.NullString{String: string(trailersJSON), Valid: true}
+	}
+
 	if run.Timestamp.IsZero() {
 		run.Timestamp = time.Now()
 	}
+	if run.SampleCount == 0 {
+		run.SampleCount = 1
+	}
+	if run.CheckCount == 0 {
+		run.CheckCount = 1
+	}
+	if run.LastTimestamp.IsZero() {
+		run.LastTimestamp = run.Timestamp
+	}
+
+	// The body file is stored under the write lock so that cleaning up old runs
+	// cannot remove it before the run referencing it is saved
+	var result sql.Result
+	err = s.withWriteLock(func() error {
+		body, bodyHash, bodyRunID := run.ResponseBody, sql.NullString{}, sql.NullInt64{}
+		if run.BodyRunID != 0 {
+			// The body is shared with an earlier run and not stored again
+			body = ""
+			bodyHash = sql.NullString{String: run.ResponseBodyHash, Valid: run.ResponseBodyHash != ""}
+			bodyRunID = sql.NullInt64{Int64: run.BodyRunID, Valid: true}
+		} else if s.bodies != nil && run.ResponseBody != "" {
+			hash, err := s.bodies.put(run.ResponseBody)
+			if err != nil {
+				return err
+			}
+			body, bodyHash = "", sql.NullString{String: hash, Valid: true}
+		}
 
-	result, err := s.db.Exec(query, run.EndpointID, run.Timestamp, run.ResponseStatus,
-		run.ResponseTimeMs, run.ResponseBody, string(headersJSON), run.ValidationResult)
+		var err error
+		result, err = s.db.Exec(query, run.EndpointID, run.Timestamp, run.ResponseStatus,
+			run.ResponseTimeMs, body, string(headersJSON), run.ValidationResult,
+			run.FailureCategory, run.ErrorMessage, run.SampleCount,
+			run.TLSNotAfter, run.TLSIssuer, run.TLSFingerprint, volatileFields,
+			run.Protocol, trailers,
+			run.DNSTimeMs, run.ConnectTimeMs, run.TLSTimeMs, run.TTFBMs, run.ResponseTruncated,
+			bodyHash, bodyRunID, run.CheckCount, run.LastTimestamp)
+		if err == nil {
+			run.ResponseBodyHash = bodyHash.String
+		}
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to save monitoring run: %w", err)
 	}
@@ -167,11 +424,82 @@ func (s *SQLiteStorage) SaveMonitoringRun(run *MonitoringRun) error {
 	return nil
 }
 
+// monitoringRunColumns lists the monitoring_runs columns read by scanMonitoringRun
+const monitoringRunColumns = `id, endpoint_id, timestamp, response_status, response_time_ms,
+	response_body, response_headers, validation_result, failure_category, error_message,
+	sample_count, tls_not_after, tls_issuer, tls_fingerprint, volatile_fields,
+	protocol, response_trailers, dns_time_ms, connect_time_ms, tls_time_ms, ttfb_ms,
+	response_truncated, response_body_hash, body_run_id, check_count, last_timestamp`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanMonitoringRun reads a monitoring run selected with monitoringRunColumns
+func scanMonitoringRun(row rowScanner) (*MonitoringRun, error) {
+	var run MonitoringRun
+	var headersJSON string
+	var validationResult, failureCategory, errorMessage sql.NullString
+	var tlsNotAfter sql.NullTime
+	var tlsIssuer, tlsFingerprint, volatileFields sql.NullString
+	var protocol, trailers, bodyHash sql.NullString
+	var bodyRunID sql.NullInt64
+	var lastTimestamp sql.NullTime
+
+	err := row.Scan(
+		&run.ID, &run.EndpointID, &run.Timestamp, &run.ResponseStatus,
+		&run.ResponseTimeMs, &run.ResponseBody, &headersJSON, &validationResult,
+		&failureCategory, &errorMessage, &run.SampleCount,
+		&tlsNotAfter, &tlsIssuer, &tlsFingerprint, &volatileFields,
+		&protocol, &trailers,
+		&run.DNSTimeMs, &run.ConnectTimeMs, &run.TLSTimeMs, &run.TTFBMs,
+		&run.ResponseTruncated, &bodyHash, &bodyRunID, &run.CheckCount, &lastTimestamp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse headers JSON
+	if err := json.Unmarshal([]byte(headersJSON), &run.ResponseHeaders); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response headers: %w", err)
+	}
+
+	if validationResult.Valid {
+		run.ValidationResult = validationResult.String
+	}
+	run.FailureCategory = failureCategory.String
+	run.ErrorMessage = errorMessage.String
+	if tlsNotAfter.Valid {
+		run.TLSNotAfter = &tlsNotAfter.Time
+	}
+	run.TLSIssuer = tlsIssuer.String
+	run.TLSFingerprint = tlsFingerprint.String
+	if volatileFields.Valid && volatileFields.String != "" {
+		if err := json.Unmarshal([]byte(volatileFields.String), &run.VolatileFields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal volatile fields: %w", err)
+		}
+	}
+	run.Protocol = protocol.String
+	run.ResponseBodyHash = bodyHash.String
+	run.BodyRunID = bodyRunID.Int64
+	run.LastTimestamp = run.Timestamp
+	if lastTimestamp.Valid {
+		run.LastTimestamp = lastTimestamp.Time
+	}
+	if trailers.Valid && trailers.String != "" {
+		if err := json.Unmarshal([]byte(trailers.String), &run.ResponseTrailers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response trailers: %w", err)
+		}
+	}
+
+	return &run, nil
+}
+
 // GetMonitoringHistory retrieves monitoring history for an endpoint
 func (s *SQLiteStorage) GetMonitoringHistory(endpointID string, period time.Duration) ([]*MonitoringRun, error) {
 	query := `
-		SELECT id, endpoint_id, timestamp, response_status, response_time_ms,
-			response_body, response_headers, validation_result
+		SELECT ` + monitoringRunColumns + `
 		FROM monitoring_runs
 		WHERE endpoint_id = ? AND timestamp >= ?
 		ORDER BY timestamp DESC
@@ -186,74 +514,319 @@ func (s *SQLiteStorage) GetMonitoringHistory(endpointID string, period time.Dura
 
 	var runs []*MonitoringRun
 	for rows.Next() {
-		var run MonitoringRun
-		var headersJSON string
-		var validationResult sql.NullString
-
-		err := rows.Scan(
-			&run.ID, &run.EndpointID, &run.Timestamp, &run.ResponseStatus,
-			&run.ResponseTimeMs, &run.ResponseBody, &headersJSON, &validationResult,
-		)
+		run, err := scanMonitoringRun(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan monitoring run: %w", err)
 		}
-
-		// Parse headers JSON
-		if err := json.Unmarshal([]byte(headersJSON), &run.ResponseHeaders); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response headers: %w", err)
+		if err := s.loadResponseBody(run); err != nil {
+			return nil, err
 		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating monitoring runs: %w", err)
+	}
+	if err := s.loadSharedBodies(runs); err != nil {
+		return nil, err
+	}
 
-		if validationResult.Valid {
-			run.ValidationResult = validationResult.String
+	return runs, nil
+}
+
+// GetMonitoringHistorySample retrieves at most maxSamples evenly spaced
+// successful runs of an endpoint's monitoring history, newest first, so that
+// long histories can be analyzed without loading every run. Failed runs, which
+// have no response to analyze, are left out before sampling. The sample is
+// selected by the database: the i-th newest of n successful runs is kept when
+// i*maxSamples mod n < maxSamples, which keeps the newest run and one run per
+// n/maxSamples. A maxSamples of 0 or less returns every successful run.
+func (s *SQLiteStorage) GetMonitoringHistorySample(endpointID string, period time.Duration, maxSamples int) ([]*MonitoringRun, error) {
+	if maxSamples <= 0 {
+		runs, err := s.GetMonitoringHistory(endpointID, period)
+		if err != nil {
+			return nil, err
 		}
+		return successfulRuns(runs), nil
+	}
+
+	// Successful as by MonitoringRun.Succeeded, with no failure recorded
+	query := `
+		SELECT ` + monitoringRunColumns + `
+		FROM (
+			SELECT *,
+				ROW_NUMBER() OVER (ORDER BY timestamp DESC) - 1 AS sample_index,
+				COUNT(*) OVER () AS sample_total
+			FROM monitoring_runs
+			WHERE endpoint_id = ? AND timestamp >= ?
+				AND ((response_status >= 200 AND response_status < 300) OR response_status = 101)
+				AND COALESCE(failure_category, '') = ''
+		)
+		WHERE (sample_index * ?) % sample_total < ?
+		ORDER BY timestamp DESC
+	`
+
+	since := time.Now().Add(-period)
+	rows, err := s.db.Query(query, endpointID, since, maxSamples, maxSamples)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitoring history sample: %w", err)
+	}
+	defer rows.Close()
 
-		runs = append(runs, &run)
+	var runs []*MonitoringRun
+	for rows.Next() {
+		run, err := scanMonitoringRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan monitoring run: %w", err)
+		}
+		if err := s.loadResponseBody(run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating monitoring runs: %w", err)
 	}
+	if err := s.loadSharedBodies(runs); err != nil {
+		return nil, err
+	}
 
 	return runs, nil
 }
 
+// GetMonitoringRun retrieves a monitoring run by ID
+func (s *SQLiteStorage) GetMonitoringRun(id int64) (*MonitoringRun, error) {
+	query := `SELECT ` + monitoringRunColumns + ` FROM monitoring_runs WHERE id = ?`
+
+	run, err := scanMonitoringRun(s.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %d", ErrMonitoringRunNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get monitoring run: %w", err)
+	}
+	if err := s.loadResponseBody(run); err != nil {
+		return nil, err
+	}
+	if err := s.loadSharedBodies([]*MonitoringRun{run}); err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// loadResponseBody reads the body of a run stored as a file into ResponseBody
+func (s *SQLiteStorage) loadResponseBody(run *MonitoringRun) error {
+	if run.ResponseBodyHash == "" || run.BodyRunID != 0 {
+		return nil
+	}
+	if s.bodies == nil {
+		return fmt.Errorf("response body of monitoring run %d is stored as a file, but no body storage directory is configured", run.ID)
+	}
+
+	body, err := s.bodies.get(run.ResponseBodyHash)
+	if err != nil {
+		return fmt.Errorf("failed to load response body of monitoring run %d: %w", run.ID, err)
+	}
+	run.ResponseBody = body
+	return nil
+}
+
+// loadSharedBodies reads the body of runs sharing the body of an earlier run
+// from that run, which is often among the runs themselves. It queries the
+// database, so it must not be called while rows are being read.
+func (s *SQLiteStorage) loadSharedBodies(runs []*MonitoringRun) error {
+	bodies := make(map[int64]string)
+	for _, run := range runs {
+		if run.BodyRunID == 0 {
+			bodies[run.ID] = run.ResponseBody
+		}
+	}
+
+	for _, run := range runs {
+		if run.BodyRunID == 0 {
+			continue
+		}
+
+		body, ok := bodies[run.BodyRunID]
+		if !ok {
+			var err error
+			if body, err = s.storedResponseBody(run.BodyRunID); err != nil {
+				return fmt.Errorf("failed to load response body of monitoring run %d: %w", run.ID, err)
+			}
+			bodies[run.BodyRunID] = body
+		}
+		run.ResponseBody = body
+	}
+
+	return nil
+}
+
+// storedResponseBody returns the body stored for a run, or an empty body if
+// the run has been cleaned up
+func (s *SQLiteStorage) storedResponseBody(id int64) (string, error) {
+	query := `SELECT ` + monitoringRunColumns + ` FROM monitoring_runs WHERE id = ?`
+
+	run, err := scanMonitoringRun(s.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := s.loadResponseBody(run); err != nil {
+		return "", err
+	}
+	return run.ResponseBody, nil
+}
+
 // SaveDrift saves a detected drift
 func (s *SQLiteStorage) SaveDrift(drift *Drift) error {
-	query := `
-		INSERT INTO drifts (endpoint_id, detected_at, drift_type, severity, description,
-			before_value, after_value, field_path, acknowledged)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
 	if drift.DetectedAt.IsZero() {
 		drift.DetectedAt = time.Now()
 	}
+	if drift.Fingerprint == "" {
+		drift.Fingerprint = drift.ComputeFingerprint()
+	}
 
-	result, err := s.db.Exec(query, drift.EndpointID, drift.DetectedAt, drift.DriftType,
-		drift.Severity, drift.Description, drift.BeforeValue, drift.AfterValue,
-		drift.FieldPath, drift.Acknowledged)
+	err := s.withWriteLock(func() error {
+		return s.insertDrift(drift)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to save drift: %w", err)
 	}
 
+	return nil
+}
+
+// insertDrift inserts a drift in a transaction that also carries over the first
+// detection of its field path and moves the last detection of earlier drifts
+// of the path to this one. A drift whose fingerprint was suppressed is
+// inserted acknowledged.
+func (s *SQLiteStorage) insertDrift(drift *Drift) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	firstDetectedAt := drift.DetectedAt
+	var earlier sql.NullTime
+	err = tx.QueryRow(`
+		SELECT first_detected_at FROM drifts
+		WHERE endpoint_id = ? AND field_path = ? AND first_detected_at IS NOT NULL
+		ORDER BY id ASC LIMIT 1
+	`, drift.EndpointID, drift.FieldPath).Scan(&earlier)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if earlier.Valid {
+		firstDetectedAt = earlier.Time
+	}
+
+	// Drifts whose fingerprint was suppressed are stored acknowledged, whichever
+	// path records them
+	acknowledged := drift.Acknowledged
+	if !acknowledged {
+		err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM drift_suppressions WHERE fingerprint = ?)`,
+			drift.Fingerprint).Scan(&acknowledged)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO drifts (endpoint_id, detected_at, drift_type, severity, description,
+			before_value, after_value, field_path, acknowledged, first_detected_at, last_detected_at, tag, fingerprint,
+			resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, drift.EndpointID, drift.DetectedAt, drift.DriftType,
+		drift.Severity, drift.Description, drift.BeforeValue, drift.AfterValue,
+		drift.FieldPath, acknowledged, firstDetectedAt, drift.DetectedAt, drift.Tag, drift.Fingerprint,
+		drift.ResolvedAt)
+	if err != nil {
+		return err
+	}
+
 	// Get the generated ID
 	id, err := result.LastInsertId()
 	if err != nil {
 		return fmt.Errorf("failed to get drift ID: %w", err)
 	}
-	drift.ID = id
 
+	_, err = tx.Exec(`UPDATE drifts SET last_detected_at = ? WHERE endpoint_id = ? AND field_path = ? AND id <> ?`,
+		drift.DetectedAt, drift.EndpointID, drift.FieldPath, id)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	drift.ID = id
+	drift.Acknowledged = acknowledged
+	drift.FirstDetectedAt = firstDetectedAt
+	drift.LastDetectedAt = drift.DetectedAt
 	return nil
 }
 
+// driftColumns lists the drift columns in the order read by scanDrift
+const driftColumns = `id, endpoint_id, detected_at, drift_type, severity, description,
+	before_value, after_value, field_path, acknowledged, first_detected_at, last_detected_at, tag, fingerprint,
+	resolved_at`
+
+// scanDrift reads a drift selected with driftColumns
+func scanDrift(row rowScanner) (*Drift, error) {
+	var drift Drift
+	var description, beforeValue, afterValue, fieldPath, tag, fingerprint sql.NullString
+	var firstDetectedAt, lastDetectedAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&drift.ID, &drift.EndpointID, &drift.DetectedAt, &drift.DriftType,
+		&drift.Severity, &description, &beforeValue, &afterValue,
+		&fieldPath, &drift.Acknowledged, &firstDetectedAt, &lastDetectedAt, &tag, &fingerprint,
+		&resolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	drift.FirstDetectedAt = firstDetectedAt.Time
+	drift.LastDetectedAt = lastDetectedAt.Time
+
+	drift.Description = description.String
+	drift.BeforeValue = beforeValue.String
+	drift.AfterValue = afterValue.String
+	drift.FieldPath = fieldPath.String
+	drift.Tag = tag.String
+	drift.Fingerprint = fingerprint.String
+	if resolvedAt.Valid {
+		drift.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &drift, nil
+}
+
+// GetDrift retrieves a single drift by ID
+func (s *SQLiteStorage) GetDrift(id int64) (*Drift, error) {
+	query := `SELECT ` + driftColumns + ` FROM drifts WHERE id = ?`
+
+	drift, err := scanDrift(s.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("drift not found: %d", id)
+		}
+		return nil, fmt.Errorf("failed to get drift: %w", err)
+	}
+
+	return drift, nil
+}
+
 // GetDrifts retrieves drifts based on filters
 func (s *SQLiteStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
-	query := `
-		SELECT id, endpoint_id, detected_at, drift_type, severity, description,
-			before_value, after_value, field_path, acknowledged
-		FROM drifts
-		WHERE 1=1
-	`
+	query := `SELECT ` + driftColumns + ` FROM drifts WHERE 1=1`
 
 	var args []interface{}
 
@@ -263,6 +836,11 @@ func (s *SQLiteStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 		args = append(args, filters.EndpointID)
 	}
 
+	if filters.FieldPath != "" {
+		query += " AND field_path = ?"
+		args = append(args, filters.FieldPath)
+	}
+
 	if filters.Severity != "" {
 		query += " AND severity = ?"
 		args = append(args, filters.Severity)
@@ -285,6 +863,126 @@ func (s *SQLiteStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 
 	query += " ORDER BY detected_at DESC"
 
+	return s.queryDrifts(query, args...)
+}
+
+// GetDriftsAfter retrieves up to limit drifts with an ID greater than afterID,
+// in the order they were saved
+func (s *SQLiteStorage) GetDriftsAfter(afterID int64, limit int) ([]*Drift, error) {
+	query := `SELECT ` + driftColumns + ` FROM drifts WHERE id > ? ORDER BY id ASC LIMIT ?`
+	return s.queryDrifts(query, afterID, limit)
+}
+
+// GetAlertedUnresolvedDrifts retrieves up to limit drifts of an endpoint, newest
+// first, that are not resolved and that an alert was sent about
+func (s *SQLiteStorage) GetAlertedUnresolvedDrifts(endpointID string, limit int) ([]*Drift, error) {
+	query := `SELECT ` + driftColumns + ` FROM drifts
+		WHERE endpoint_id = ? AND resolved_at IS NULL
+		AND EXISTS (SELECT 1 FROM alerts WHERE alerts.drift_id = drifts.id AND alerts.status = 'sent')
+		ORDER BY detected_at DESC, id DESC LIMIT ?`
+	return s.queryDrifts(query, endpointID, limit)
+}
+
+// SearchDrifts retrieves up to limit drifts, newest first, whose description or
+// field path contains every whitespace-separated term of text, ignoring case.
+// A limit of zero returns every match.
+func (s *SQLiteStorage) SearchDrifts(text string, limit int) ([]*Drift, error) {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search text is empty")
+	}
+
+	query := `SELECT ` + driftColumns + ` FROM drifts WHERE 1=1`
+
+	var args []interface{}
+	for _, term := range terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		query += ` AND (description LIKE ? ESCAPE '\' OR field_path LIKE ? ESCAPE '\')`
+		args = append(args, pattern, pattern)
+	}
+
+	query += " ORDER BY detected_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	return s.queryDrifts(query, args...)
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, so that search terms
+// match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// AcknowledgeDrifts acknowledges the unacknowledged drifts of an endpoint
+// detected before the given time, returning how many were acknowledged
+func (s *SQLiteStorage) AcknowledgeDrifts(endpointID string, before time.Time) (int64, error) {
+	query := `UPDATE drifts SET acknowledged = TRUE WHERE endpoint_id = ? AND detected_at < ? AND acknowledged = FALSE`
+
+	result, err := s.execWrite(query, endpointID, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acknowledge drifts: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// AcknowledgeFingerprint acknowledges the unacknowledged drifts with a
+// fingerprint, returning how many were acknowledged
+func (s *SQLiteStorage) AcknowledgeFingerprint(fingerprint string) (int64, error) {
+	query := `UPDATE drifts SET acknowledged = TRUE WHERE fingerprint = ? AND acknowledged = FALSE`
+
+	result, err := s.execWrite(query, fingerprint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acknowledge drifts: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ResolveDrift records when a drift was resolved
+func (s *SQLiteStorage) ResolveDrift(id int64, resolvedAt time.Time) error {
+	result, err := s.execWrite(`UPDATE drifts SET resolved_at = ? WHERE id = ?`, resolvedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to resolve drift: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to resolve drift: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("drift not found: %d", id)
+	}
+
+	return nil
+}
+
+// SuppressFingerprint records a fingerprint whose future drifts are
+// acknowledged and not alerted on
+func (s *SQLiteStorage) SuppressFingerprint(fingerprint string) error {
+	query := `INSERT OR IGNORE INTO drift_suppressions (fingerprint, created_at) VALUES (?, ?)`
+
+	if _, err := s.execWrite(query, fingerprint, time.Now()); err != nil {
+		return fmt.Errorf("failed to suppress fingerprint: %w", err)
+	}
+
+	return nil
+}
+
+// IsFingerprintSuppressed reports whether a fingerprint has been suppressed
+func (s *SQLiteStorage) IsFingerprintSuppressed(fingerprint string) (bool, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM drift_suppressions WHERE fingerprint = ?`, fingerprint).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check fingerprint suppression: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// queryDrifts runs a query selecting driftColumns
+func (s *SQLiteStorage) queryDrifts(query string, args ...interface{}) ([]*Drift, error) {
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get drifts: %w", err)
@@ -293,32 +991,11 @@ func (s *SQLiteStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 
 	var drifts []*Drift
 	for rows.Next() {
-		var drift Drift
-		var description, beforeValue, afterValue, fieldPath sql.NullString
-
-		err := rows.Scan(
-			&drift.ID, &drift.EndpointID, &drift.DetectedAt, &drift.DriftType,
-			&drift.Severity, &description, &beforeValue, &afterValue,
-			&fieldPath, &drift.Acknowledged,
-		)
+		drift, err := scanDrift(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan drift: %w", err)
 		}
-
-		if description.Valid {
-			drift.Description = description.String
-		}
-		if beforeValue.Valid {
-			drift.BeforeValue = beforeValue.String
-		}
-		if afterValue.Valid {
-			drift.AfterValue = afterValue.String
-		}
-		if fieldPath.Valid {
-			drift.FieldPath = fieldPath.String
-		}
-
-		drifts = append(drifts, &drift)
+		drifts = append(drifts, drift)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -328,6 +1005,137 @@ func (s *SQLiteStorage) GetDrifts(filters DriftFilters) ([]*Drift, error) {
 	return drifts, nil
 }
 
+// GetSinkCursor returns the ID of the last drift delivered to the named sink.
+// A sink seen for the first time starts after the latest drift, so that it only
+// receives drifts detected from then on.
+func (s *SQLiteStorage) GetSinkCursor(name string) (int64, error) {
+	_, err := s.execWrite(`
+		INSERT OR IGNORE INTO sink_cursors (name, last_drift_id)
+		SELECT ?, COALESCE(MAX(id), 0) FROM drifts
+	`, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize sink cursor: %w", err)
+	}
+
+	var lastDriftID int64
+	err = s.db.QueryRow("SELECT last_drift_id FROM sink_cursors WHERE name = ?", name).Scan(&lastDriftID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sink cursor: %w", err)
+	}
+
+	return lastDriftID, nil
+}
+
+// SaveSinkCursor records the ID of the last drift delivered to the named sink
+func (s *SQLiteStorage) SaveSinkCursor(name string, lastDriftID int64) error {
+	_, err := s.execWrite(`
+		INSERT INTO sink_cursors (name, last_drift_id, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET last_drift_id = excluded.last_drift_id, updated_at = excluded.updated_at
+	`, name, lastDriftID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save sink cursor: %w", err)
+	}
+
+	return nil
+}
+
+// SaveHeartbeat records a heartbeat of the monitor, replacing the previous one
+func (s *SQLiteStorage) SaveHeartbeat(at time.Time) error {
+	_, err := s.execWrite(`
+		INSERT INTO heartbeats (id, beat_at) VALUES (1, ?)
+		ON CONFLICT(id) DO UPDATE SET beat_at = excluded.beat_at
+	`, at)
+	if err != nil {
+		return fmt.Errorf("failed to save heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastHeartbeat returns the time of the last heartbeat of the monitor, or
+// the zero time if none has been recorded
+func (s *SQLiteStorage) GetLastHeartbeat() (time.Time, error) {
+	var beatAt time.Time
+	err := s.db.QueryRow("SELECT beat_at FROM heartbeats WHERE id = 1").Scan(&beatAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last heartbeat: %w", err)
+	}
+
+	return beatAt, nil
+}
+
+// SaveMaintenanceWindow saves a new maintenance window
+func (s *SQLiteStorage) SaveMaintenanceWindow(window *MaintenanceWindow) error {
+	result, err := s.execWrite(`
+		INSERT INTO maintenance_windows (endpoint_id, started_at, ends_at, auto_acknowledge)
+		VALUES (?, ?, ?, ?)
+	`, window.EndpointID, window.StartedAt, window.EndsAt, window.AutoAcknowledge)
+	if err != nil {
+		return fmt.Errorf("failed to save maintenance window: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get maintenance window ID: %w", err)
+	}
+	window.ID = id
+
+	return nil
+}
+
+// GetActiveMaintenanceWindows returns the maintenance windows in effect at the
+// given time, oldest first
+func (s *SQLiteStorage) GetActiveMaintenanceWindows(at time.Time) ([]*MaintenanceWindow, error) {
+	rows, err := s.db.Query(`
+		SELECT id, endpoint_id, started_at, ends_at, auto_acknowledge
+		FROM maintenance_windows
+		WHERE started_at <= ? AND ends_at > ?
+		ORDER BY id ASC
+	`, at, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*MaintenanceWindow
+	for rows.Next() {
+		var window MaintenanceWindow
+		if err := rows.Scan(&window.ID, &window.EndpointID, &window.StartedAt, &window.EndsAt, &window.AutoAcknowledge); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, &window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating maintenance windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// EndMaintenanceWindows ends the maintenance windows of an endpoint that are
+// in effect at the given time, or every active window when endpointID is empty.
+// It returns the number of windows ended.
+func (s *SQLiteStorage) EndMaintenanceWindows(endpointID string, at time.Time) (int64, error) {
+	query := `UPDATE maintenance_windows SET ends_at = ? WHERE started_at <= ? AND ends_at > ?`
+	args := []interface{}{at, at, at}
+	if endpointID != "" {
+		query += " AND endpoint_id = ?"
+		args = append(args, endpointID)
+	}
+
+	result, err := s.execWrite(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to end maintenance windows: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
 // SaveAlert saves an alert record
 func (s *SQLiteStorage) SaveAlert(alert *Alert) error {
 	query := `
@@ -339,7 +1147,7 @@ func (s *SQLiteStorage) SaveAlert(alert *Alert) error {
 		alert.SentAt = time.Now()
 	}
 
-	result, err := s.db.Exec(query, alert.DriftID, alert.AlertType, alert.ChannelName,
+	result, err := s.execWrite(query, alert.DriftID, alert.AlertType, alert.ChannelName,
 		alert.SentAt, alert.Status, alert.ErrorMessage, alert.RetryCount)
 	if err != nil {
 		return fmt.Errorf("failed to save alert: %w", err)
@@ -355,6 +1163,31 @@ func (s *SQLiteStorage) SaveAlert(alert *Alert) error {
 	return nil
 }
 
+// UpdateAlert updates the delivery state of an existing alert
+func (s *SQLiteStorage) UpdateAlert(alert *Alert) error {
+	query := `
+		UPDATE alerts
+		SET sent_at = ?, status = ?, error_message = ?, retry_count = ?
+		WHERE id = ?
+	`
+
+	result, err := s.execWrite(query, alert.SentAt, alert.Status, alert.ErrorMessage,
+		alert.RetryCount, alert.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert not found: %d", alert.ID)
+	}
+
+	return nil
+}
+
 // GetAlerts retrieves alerts based on filters
 func (s *SQLiteStorage) GetAlerts(filters AlertFilters) ([]*Alert, error) {
 	query := `
@@ -433,26 +1266,151 @@ func (s *SQLiteStorage) GetAlerts(filters AlertFilters) ([]*Alert, error) {
 
 // CleanupOldMonitoringRuns removes monitoring runs older than the specified time
 func (s *SQLiteStorage) CleanupOldMonitoringRuns(olderThan time.Time) (int64, error) {
-	query := `DELETE FROM monitoring_runs WHERE timestamp < ?`
-
-	result, err := s.db.Exec(query, olderThan)
+	rowsAffected, err := s.deleteMonitoringRuns(`timestamp < ?`, olderThan)
 	if err != nil {
 		return 0, fmt.Errorf("failed to cleanup old monitoring runs: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if s.bodies != nil && rowsAffected > 0 {
+		if err := s.pruneResponseBodies(); err != nil {
+			return rowsAffected, err
+		}
+	}
+
+	return rowsAffected, nil
+}
+
+// TrimMonitoringRuns removes all but the newest keep monitoring runs of an
+// endpoint, whatever their age
+func (s *SQLiteStorage) TrimMonitoringRuns(endpointID string, keep int) (int64, error) {
+	if keep < 0 {
+		return 0, fmt.Errorf("cannot keep a negative number of monitoring runs")
+	}
+
+	where := `
+		endpoint_id = ? AND id NOT IN (
+			SELECT id FROM monitoring_runs
+			WHERE endpoint_id = ?
+			ORDER BY timestamp DESC, id DESC
+			LIMIT ?
+		)
+	`
+
+	rowsAffected, err := s.deleteMonitoringRuns(where, endpointID, endpointID, keep)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to trim monitoring runs: %w", err)
+	}
+
+	if s.bodies != nil && rowsAffected > 0 {
+		if err := s.pruneResponseBodies(); err != nil {
+			return rowsAffected, err
+		}
 	}
 
 	return rowsAffected, nil
 }
 
+// deleteMonitoringRuns deletes the monitoring runs matching a condition in a
+// transaction. The body of a deleted run shared by runs that are kept moves to
+// the oldest of them first, and the others share it from that run instead, so
+// that their bodies still read back.
+func (s *SQLiteStorage) deleteMonitoringRuns(where string, args ...interface{}) (int64, error) {
+	var deleted int64
+	err := s.withWriteLock(func() error {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback() // nolint:errcheck
+
+		// Kept runs sharing the body of a deleted run, oldest first
+		sharersQuery := `
+			SELECT id, body_run_id FROM monitoring_runs
+			WHERE body_run_id IN (SELECT id FROM monitoring_runs WHERE ` + where + `)
+				AND id NOT IN (SELECT id FROM monitoring_runs WHERE ` + where + `)
+			ORDER BY timestamp, id
+		`
+		rows, err := tx.Query(sharersQuery, append(append([]interface{}{}, args...), args...)...)
+		if err != nil {
+			return err
+		}
+		var anchors []int64
+		newAnchors := make(map[int64]int64)
+		for rows.Next() {
+			var id, bodyRunID int64
+			if err := rows.Scan(&id, &bodyRunID); err != nil {
+				rows.Close()
+				return err
+			}
+			if _, ok := newAnchors[bodyRunID]; !ok {
+				anchors = append(anchors, bodyRunID)
+				newAnchors[bodyRunID] = id
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+
+		for _, anchor := range anchors {
+			newAnchor := newAnchors[anchor]
+			if _, err := tx.Exec(`
+				UPDATE monitoring_runs
+				SET (response_body, response_body_hash, body_run_id) =
+					(SELECT response_body, response_body_hash, NULL FROM monitoring_runs WHERE id = ?)
+				WHERE id = ?`, anchor, newAnchor); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`UPDATE monitoring_runs SET body_run_id = ? WHERE body_run_id = ?`, newAnchor, anchor); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.Exec(`DELETE FROM monitoring_runs WHERE `+where, args...)
+		if err != nil {
+			return err
+		}
+		if deleted, err = result.RowsAffected(); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	return deleted, err
+}
+
+// pruneResponseBodies removes the body files no monitoring run refers to
+func (s *SQLiteStorage) pruneResponseBodies() error {
+	return s.withWriteLock(func() error {
+		rows, err := s.db.Query(`SELECT DISTINCT response_body_hash FROM monitoring_runs WHERE response_body_hash IS NOT NULL`)
+		if err != nil {
+			return fmt.Errorf("failed to list stored response bodies: %w", err)
+		}
+		defer rows.Close()
+
+		keep := make(map[string]bool)
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				return fmt.Errorf("failed to scan response body hash: %w", err)
+			}
+			keep[hash] = true
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating response body hashes: %w", err)
+		}
+
+		_, err = s.bodies.prune(keep)
+		return err
+	})
+}
+
 // CleanupOldDrifts removes drifts older than the specified time
 func (s *SQLiteStorage) CleanupOldDrifts(olderThan time.Time) (int64, error) {
 	query := `DELETE FROM drifts WHERE detected_at < ?`
 
-	result, err := s.db.Exec(query, olderThan)
+	result, err := s.execWrite(query, olderThan)
 	if err != nil {
 		return 0, fmt.Errorf("failed to cleanup old drifts: %w", err)
 	}
@@ -469,7 +1427,7 @@ func (s *SQLiteStorage) CleanupOldDrifts(olderThan time.Time) (int64, error) {
 func (s *SQLiteStorage) CleanupOldAlerts(olderThan time.Time) (int64, error) {
 	query := `DELETE FROM alerts WHERE sent_at < ?`
 
-	result, err := s.db.Exec(query, olderThan)
+	result, err := s.execWrite(query, olderThan)
 	if err != nil {
 		return 0, fmt.Errorf("failed to cleanup old alerts: %w", err)
 	}
@@ -517,13 +1475,13 @@ func (s *SQLiteStorage) GetDatabaseStats() (*DatabaseStats, error) {
 // VacuumDatabase performs database optimization and cleanup
 func (s *SQLiteStorage) VacuumDatabase() error {
 	// Run VACUUM to reclaim space and optimize database
-	_, err := s.db.Exec("VACUUM")
+	_, err := s.execWrite("VACUUM")
 	if err != nil {
 		return fmt.Errorf("failed to vacuum database: %w", err)
 	}
 
 	// Analyze tables for query optimization
-	_, err = s.db.Exec("ANALYZE")
+	_, err = s.execWrite("ANALYZE")
 	if err != nil {
 		return fmt.Errorf("failed to analyze database: %w", err)
 	}
@@ -685,6 +1643,25 @@ func (s *SQLiteStorage) createRepairBackup() (string, error) {
 
 // performRepairOperations executes all repair operations in a transaction
 func (s *SQLiteStorage) performRepairOperations(integrityResult *IntegrityResult, result *RepairResult) error {
+	actions, issuesRepaired := len(result.Actions), result.IssuesRepaired
+
+	err := s.withWriteLock(func() error {
+		// Drop the actions of an attempt that was rolled back
+		result.Actions = result.Actions[:actions]
+		result.IssuesRepaired = issuesRepaired
+
+		return s.repairInTransaction(integrityResult, result)
+	})
+	if err != nil {
+		result.Success = false
+		return err
+	}
+
+	return nil
+}
+
+// repairInTransaction runs the repair operations in a single transaction
+func (s *SQLiteStorage) repairInTransaction(integrityResult *IntegrityResult, result *RepairResult) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin repair transaction: %w", err)
@@ -692,17 +1669,14 @@ func (s *SQLiteStorage) performRepairOperations(integrityResult *IntegrityResult
 	defer tx.Rollback() // nolint:errcheck
 
 	if err := s.repairOrphanedRecords(tx, integrityResult, result); err != nil {
-		result.Success = false
 		return err
 	}
 
 	if err := s.rebuildIndexes(tx, result); err != nil {
-		result.Success = false
 		return err
 	}
 
 	if err := tx.Commit(); err != nil {
-		result.Success = false
 		return fmt.Errorf("failed to commit repair transaction: %w", err)
 	}
 
@@ -796,11 +1770,11 @@ func (s *SQLiteStorage) updateRemainingIssues(result *RepairResult) {
 
 // BackupDatabase creates a backup of the database
 func (s *SQLiteStorage) BackupDatabase(backupPath string) error {
-	_, err := s.db.Exec("PRAGMA wal_checkpoint(FULL)")
+	_, err := s.execWrite("PRAGMA wal_checkpoint(FULL)")
 	if err != nil {
 		return fmt.Errorf("failed to checkpoint WAL: %w", err)
 	}
-	_, err = s.db.Exec("VACUUM INTO ?", backupPath)
+	_, err = s.execWrite("VACUUM INTO ?", backupPath)
 	if err != nil {
 		return fmt.Errorf("failed to create backup: %w", err)
 	}
@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -217,6 +221,131 @@ func TestSaveAndGetMonitoringRun(t *testing.T) {
 	assert.Equal(t, run.ValidationResult, retrieved.ValidationResult)
 	assert.Equal(t, headers, retrieved.ResponseHeaders)
 	assert.WithinDuration(t, run.Timestamp, retrieved.Timestamp, time.Second)
+
+	// Get monitoring run by ID
+	byID, err := storage.GetMonitoringRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, retrieved, byID)
+
+	_, err = storage.GetMonitoringRun(run.ID + 1)
+	assert.Error(t, err)
+}
+
+func TestSaveFailedMonitoringRun(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	err := storage.SaveEndpoint(&Endpoint{
+		ID:     "test-endpoint",
+		URL:    "https://api.example.com/users",
+		Method: "GET",
+		Config: `{}`,
+	})
+	require.NoError(t, err)
+
+	run := &MonitoringRun{
+		EndpointID:      "test-endpoint",
+		ResponseTimeMs:  30000,
+		FailureCategory: "timeout",
+		ErrorMessage:    "request failed: context deadline exceeded",
+	}
+	require.NoError(t, storage.SaveMonitoringRun(run))
+
+	history, err := storage.GetMonitoringHistory("test-endpoint", 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 0, history[0].ResponseStatus)
+	assert.Equal(t, "timeout", history[0].FailureCategory)
+	assert.Equal(t, run.ErrorMessage, history[0].ErrorMessage)
+	assert.Equal(t, 1, history[0].SampleCount)
+	assert.Nil(t, history[0].TLSNotAfter)
+	assert.Nil(t, history[0].VolatileFields)
+
+	sampled := &MonitoringRun{
+		EndpointID:     "test-endpoint",
+		ResponseStatus: 200,
+		ResponseBody:   `{"request_id": "a1"}`,
+		SampleCount:    3,
+		VolatileFields: []string{"$.headers.Date", "$.request_id"},
+	}
+	require.NoError(t, storage.SaveMonitoringRun(sampled))
+
+	stored, err := storage.GetMonitoringRun(sampled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.SampleCount)
+	assert.Equal(t, sampled.VolatileFields, stored.VolatileFields)
+}
+
+func TestSaveMonitoringRunWithTLSCertificate(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	err := storage.SaveEndpoint(&Endpoint{
+		ID:     "test-endpoint",
+		URL:    "https://api.example.com/users",
+		Method: "GET",
+		Config: `{}`,
+	})
+	require.NoError(t, err)
+
+	notAfter := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	run := &MonitoringRun{
+		EndpointID:     "test-endpoint",
+		ResponseStatus: 200,
+		TLSNotAfter:    &notAfter,
+		TLSIssuer:      "CN=Example CA",
+		TLSFingerprint: "ab12",
+	}
+	require.NoError(t, storage.SaveMonitoringRun(run))
+
+	history, err := storage.GetMonitoringHistory("test-endpoint", 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.NotNil(t, history[0].TLSNotAfter)
+	assert.True(t, notAfter.Equal(*history[0].TLSNotAfter))
+	assert.Equal(t, "CN=Example CA", history[0].TLSIssuer)
+	assert.Equal(t, "ab12", history[0].TLSFingerprint)
+}
+
+func TestSaveMonitoringRunWithProtocolAndTrailers(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	err := storage.SaveEndpoint(&Endpoint{
+		ID:     "test-endpoint",
+		URL:    "https://api.example.com/users",
+		Method: "GET",
+		Config: `{}`,
+	})
+	require.NoError(t, err)
+
+	run := &MonitoringRun{
+		EndpointID:        "test-endpoint",
+		ResponseStatus:    200,
+		Protocol:          "HTTP/2.0",
+		ResponseTrailers:  map[string]string{"Grpc-Status": "0"},
+		DNSTimeMs:         3,
+		ConnectTimeMs:     12,
+		TLSTimeMs:         25,
+		TTFBMs:            140,
+		ResponseTruncated: true,
+	}
+	require.NoError(t, storage.SaveMonitoringRun(run))
+	require.NoError(t, storage.SaveMonitoringRun(&MonitoringRun{EndpointID: "test-endpoint", ResponseStatus: 200}))
+
+	saved, err := storage.GetMonitoringRun(run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", saved.Protocol)
+	assert.Equal(t, map[string]string{"Grpc-Status": "0"}, saved.ResponseTrailers)
+	assert.Equal(t, []int64{3, 12, 25, 140}, []int64{saved.DNSTimeMs, saved.ConnectTimeMs, saved.TLSTimeMs, saved.TTFBMs})
+	assert.True(t, saved.ResponseTruncated)
+
+	saved, err = storage.GetMonitoringRun(run.ID + 1)
+	require.NoError(t, err)
+	assert.Empty(t, saved.Protocol)
+	assert.Nil(t, saved.ResponseTrailers)
+	assert.Zero(t, saved.TTFBMs)
+	assert.False(t, saved.ResponseTruncated)
 }
 
 func TestGetMonitoringHistoryWithPeriod(t *testing.T) {
@@ -278,6 +407,65 @@ func TestGetMonitoringHistoryWithPeriod(t *testing.T) {
 	assert.Equal(t, 200, history[1].ResponseStatus) // 2 hours ago
 }
 
+func TestGetMonitoringHistorySample(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}))
+
+	// Successful runs one minute apart, run i being i minutes old, between
+	// failed runs, which are not sampled
+	now := time.Now()
+	saveRuns := func(store Storage) {
+		for i := 0; i < 10; i++ {
+			require.NoError(t, store.SaveMonitoringRun(&MonitoringRun{
+				EndpointID:     "test-endpoint",
+				Timestamp:      now.Add(-time.Duration(i) * time.Minute),
+				ResponseStatus: 200,
+				ResponseTimeMs: int64(i),
+			}))
+			require.NoError(t, store.SaveMonitoringRun(&MonitoringRun{
+				EndpointID:     "test-endpoint",
+				Timestamp:      now.Add(-time.Duration(i)*time.Minute - 20*time.Second),
+				ResponseStatus: 503,
+				ResponseTimeMs: int64(100 + i),
+			}))
+			require.NoError(t, store.SaveMonitoringRun(&MonitoringRun{
+				EndpointID:      "test-endpoint",
+				Timestamp:       now.Add(-time.Duration(i)*time.Minute - 40*time.Second),
+				FailureCategory: "timeout",
+				ErrorMessage:    "request timed out",
+				ResponseTimeMs:  int64(200 + i),
+			}))
+		}
+	}
+	saveRuns(storage)
+
+	sampleAges := func(maxSamples int) []int64 {
+		runs, err := storage.GetMonitoringHistorySample("test-endpoint", time.Hour, maxSamples)
+		require.NoError(t, err)
+		ages := make([]int64, 0, len(runs))
+		for _, run := range runs {
+			ages = append(ages, run.ResponseTimeMs)
+		}
+		return ages
+	}
+
+	assert.Equal(t, []int64{0, 2, 4, 6, 8}, sampleAges(5))
+	assert.Equal(t, []int64{0, 4, 7}, sampleAges(3))
+	assert.Len(t, sampleAges(20), 10)
+	assert.Len(t, sampleAges(0), 10)
+
+	// The in-memory storage selects the same runs
+	memory, err := NewInMemoryStorage()
+	require.NoError(t, err)
+	saveRuns(memory)
+	runs, err := memory.GetMonitoringHistorySample("test-endpoint", time.Hour, 3)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, []int64{0, 4, 7}, []int64{runs[0].ResponseTimeMs, runs[1].ResponseTimeMs, runs[2].ResponseTimeMs})
+}
+
 func TestSaveAndGetDrift(t *testing.T) {
 	storage, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -328,6 +516,451 @@ func TestSaveAndGetDrift(t *testing.T) {
 	assert.WithinDuration(t, drift.DetectedAt, retrieved.DetectedAt, time.Second)
 }
 
+func TestGetDriftByID(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	endpoint := &Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	require.NoError(t, storage.SaveEndpoint(endpoint))
+
+	drift := &Drift{
+		EndpointID:  "test-endpoint",
+		DriftType:   "field_added",
+		Severity:    "low",
+		Description: "Field 'nickname' was added",
+		FieldPath:   "nickname",
+	}
+	require.NoError(t, storage.SaveDrift(drift))
+
+	retrieved, err := storage.GetDrift(drift.ID)
+	require.NoError(t, err)
+	assert.Equal(t, drift.ID, retrieved.ID)
+	assert.Equal(t, drift.EndpointID, retrieved.EndpointID)
+	assert.Equal(t, drift.Description, retrieved.Description)
+	assert.Equal(t, drift.FieldPath, retrieved.FieldPath)
+
+	_, err = storage.GetDrift(drift.ID + 100)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "drift not found")
+}
+
+func TestDriftDetectionSpan(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}))
+
+	first := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	second := first.Add(24 * time.Hour)
+	third := second.Add(time.Hour)
+
+	older := &Drift{EndpointID: "test-endpoint", DriftType: "field_removed", Severity: "high", FieldPath: "$.email", DetectedAt: first}
+	newer := &Drift{EndpointID: "test-endpoint", DriftType: "field_added", Severity: "low", FieldPath: "$.email", DetectedAt: second}
+	other := &Drift{EndpointID: "test-endpoint", DriftType: "field_added", Severity: "low", FieldPath: "$.name", DetectedAt: third}
+	for _, drift := range []*Drift{older, newer, other} {
+		require.NoError(t, storage.SaveDrift(drift))
+	}
+
+	retrieved, err := storage.GetDrift(older.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.FirstDetectedAt.Equal(first))
+	assert.True(t, retrieved.LastDetectedAt.Equal(second))
+
+	retrieved, err = storage.GetDrift(newer.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.FirstDetectedAt.Equal(first))
+	assert.True(t, retrieved.LastDetectedAt.Equal(second))
+
+	retrieved, err = storage.GetDrift(other.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.FirstDetectedAt.Equal(third))
+	assert.True(t, retrieved.LastDetectedAt.Equal(third))
+}
+
+func TestSinkCursorAndDriftsAfter(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	endpoint := &Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	require.NoError(t, storage.SaveEndpoint(endpoint))
+
+	existing := &Drift{EndpointID: "test-endpoint", DriftType: "field_added", Severity: "low"}
+	require.NoError(t, storage.SaveDrift(existing))
+
+	// A new sink starts after the latest drift
+	cursor, err := storage.GetSinkCursor("https://collector.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, cursor)
+
+	var saved []*Drift
+	for i := 0; i < 3; i++ {
+		drift := &Drift{
+			EndpointID: "test-endpoint",
+			DriftType:  "field_removed",
+			Severity:   "high",
+			// Detection order does not affect delivery order
+			DetectedAt: time.Now().Add(-time.Duration(i) * time.Hour),
+		}
+		require.NoError(t, storage.SaveDrift(drift))
+		saved = append(saved, drift)
+	}
+
+	drifts, err := storage.GetDriftsAfter(cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, saved[0].ID, drifts[0].ID)
+	assert.Equal(t, saved[1].ID, drifts[1].ID)
+
+	require.NoError(t, storage.SaveSinkCursor("https://collector.example.com", drifts[1].ID))
+
+	cursor, err = storage.GetSinkCursor("https://collector.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, saved[1].ID, cursor)
+
+	drifts, err = storage.GetDriftsAfter(cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, saved[2].ID, drifts[0].ID)
+}
+
+func TestHeartbeat(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	beatAt, err := storage.GetLastHeartbeat()
+	require.NoError(t, err)
+	assert.True(t, beatAt.IsZero())
+
+	first := time.Now().Add(-time.Minute)
+	require.NoError(t, storage.SaveHeartbeat(first))
+	second := time.Now()
+	require.NoError(t, storage.SaveHeartbeat(second))
+
+	beatAt, err = storage.GetLastHeartbeat()
+	require.NoError(t, err)
+	assert.True(t, beatAt.Equal(second))
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	global := &MaintenanceWindow{StartedAt: now.Add(-time.Minute), EndsAt: now.Add(30 * time.Minute)}
+	endpoint := &MaintenanceWindow{EndpointID: "users", StartedAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour), AutoAcknowledge: true}
+	expired := &MaintenanceWindow{StartedAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}
+	for _, window := range []*MaintenanceWindow{global, endpoint, expired} {
+		require.NoError(t, storage.SaveMaintenanceWindow(window))
+		assert.NotZero(t, window.ID)
+	}
+
+	windows, err := storage.GetActiveMaintenanceWindows(now)
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	assert.Equal(t, global.ID, windows[0].ID)
+	assert.Equal(t, "users", windows[1].EndpointID)
+	assert.True(t, windows[1].AutoAcknowledge)
+	assert.True(t, windows[1].Covers("users", now))
+	assert.False(t, windows[1].Covers("orders", now))
+
+	ended, err := storage.EndMaintenanceWindows("users", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), ended)
+
+	windows, err = storage.GetActiveMaintenanceWindows(now)
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, global.ID, windows[0].ID)
+
+	ended, err = storage.EndMaintenanceWindows("", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), ended)
+
+	windows, err = storage.GetActiveMaintenanceWindows(now)
+	require.NoError(t, err)
+	assert.Empty(t, windows)
+}
+
+func TestSaveDriftWithTag(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}))
+
+	drift := &Drift{EndpointID: "test-endpoint", DriftType: "field_removed", Severity: "high", Tag: DriftTagMaintenance, Acknowledged: true}
+	require.NoError(t, storage.SaveDrift(drift))
+
+	retrieved, err := storage.GetDrift(drift.ID)
+	require.NoError(t, err)
+	assert.Equal(t, DriftTagMaintenance, retrieved.Tag)
+	assert.True(t, retrieved.Acknowledged)
+}
+
+func TestAcknowledgeDrifts(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, id := range []string{"users", "orders"} {
+		require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: id, URL: "https://api.example.com/" + id, Method: "GET"}))
+	}
+
+	now := time.Now()
+	old := &Drift{EndpointID: "users", DriftType: "field_removed", Severity: "high", FieldPath: "$.a", DetectedAt: now.Add(-time.Hour)}
+	recent := &Drift{EndpointID: "users", DriftType: "field_removed", Severity: "high", FieldPath: "$.b", DetectedAt: now}
+	other := &Drift{EndpointID: "orders", DriftType: "field_removed", Severity: "high", FieldPath: "$.a", DetectedAt: now.Add(-time.Hour)}
+	for _, drift := range []*Drift{old, recent, other} {
+		require.NoError(t, storage.SaveDrift(drift))
+	}
+
+	acknowledged, err := storage.AcknowledgeDrifts("users", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), acknowledged)
+
+	for drift, expected := range map[*Drift]bool{old: true, recent: false, other: false} {
+		retrieved, err := storage.GetDrift(drift.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved.Acknowledged, drift.FieldPath)
+	}
+
+	acknowledged, err = storage.AcknowledgeDrifts("users", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), acknowledged)
+}
+
+func TestSearchDrifts(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "shop", URL: "https://api.example.com/shop", Method: "GET"}))
+
+	now := time.Now()
+	price := &Drift{EndpointID: "shop", DriftType: "value_change", Severity: "low", Description: "Value changed", FieldPath: "$.items[0].price", DetectedAt: now.Add(-time.Hour)}
+	removed := &Drift{EndpointID: "shop", DriftType: "field_removed", Severity: "high", Description: "Field 'Price' was removed", FieldPath: "$.total", DetectedAt: now}
+	discount := &Drift{EndpointID: "shop", DriftType: "field_added", Severity: "low", Description: "Field 'discount_100%' was added", FieldPath: "$.discount", DetectedAt: now}
+	for _, drift := range []*Drift{price, removed, discount} {
+		require.NoError(t, storage.SaveDrift(drift))
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		expected []*Drift
+	}{
+		{name: "description or field path, ignoring case", text: "price", expected: []*Drift{removed, price}},
+		{name: "every term", text: "price removed", expected: []*Drift{removed}},
+		{name: "limit", text: "price", limit: 1, expected: []*Drift{removed}},
+		{name: "wildcards match literally", text: "100%", expected: []*Drift{discount}},
+		{name: "underscore matches literally", text: "items_0", expected: nil},
+		{name: "no match", text: "currency", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drifts, err := storage.SearchDrifts(tt.text, tt.limit)
+			require.NoError(t, err)
+
+			var ids []int64
+			for _, drift := range drifts {
+				ids = append(ids, drift.ID)
+			}
+			var expectedIDs []int64
+			for _, drift := range tt.expected {
+				expectedIDs = append(expectedIDs, drift.ID)
+			}
+			assert.Equal(t, expectedIDs, ids)
+		})
+	}
+
+	_, err := storage.SearchDrifts("  ", 0)
+	assert.Error(t, err)
+}
+
+func TestGetDriftsByFieldPath(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "shop", URL: "https://api.example.com/shop", Method: "GET"}))
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "cart", URL: "https://api.example.com/cart", Method: "GET"}))
+
+	now := time.Now()
+	first := &Drift{EndpointID: "shop", DriftType: "value_change", Severity: "low", FieldPath: "$.data.price", BeforeValue: "10", AfterValue: "12", DetectedAt: now.Add(-2 * time.Hour)}
+	second := &Drift{EndpointID: "shop", DriftType: "type_change", Severity: "high", FieldPath: "$.data.price", BeforeValue: "12", AfterValue: `"12.00"`, DetectedAt: now.Add(-time.Hour)}
+	nested := &Drift{EndpointID: "shop", DriftType: "field_added", Severity: "low", FieldPath: "$.data.price.currency", DetectedAt: now}
+	other := &Drift{EndpointID: "cart", DriftType: "value_change", Severity: "low", FieldPath: "$.data.price", DetectedAt: now}
+	for _, drift := range []*Drift{first, second, nested, other} {
+		require.NoError(t, storage.SaveDrift(drift))
+	}
+
+	drifts, err := storage.GetDrifts(DriftFilters{EndpointID: "shop", FieldPath: "$.data.price"})
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, second.ID, drifts[0].ID)
+	assert.Equal(t, first.ID, drifts[1].ID)
+}
+
+func TestDriftFingerprints(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "users", URL: "https://api.example.com/users", Method: "GET"}))
+
+	first := &Drift{EndpointID: "users", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+	again := &Drift{EndpointID: "users", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+	other := &Drift{EndpointID: "users", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "2", AfterValue: "3"}
+	for _, drift := range []*Drift{first, again, other} {
+		require.NoError(t, storage.SaveDrift(drift))
+	}
+
+	assert.Len(t, first.Fingerprint, 16)
+	assert.Equal(t, first.Fingerprint, again.Fingerprint)
+	assert.NotEqual(t, first.Fingerprint, other.Fingerprint)
+
+	retrieved, err := storage.GetDrift(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.Fingerprint, retrieved.Fingerprint)
+
+	acknowledged, err := storage.AcknowledgeFingerprint(first.Fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), acknowledged)
+
+	retrieved, err = storage.GetDrift(other.ID)
+	require.NoError(t, err)
+	assert.False(t, retrieved.Acknowledged)
+
+	suppressed, err := storage.IsFingerprintSuppressed(first.Fingerprint)
+	require.NoError(t, err)
+	assert.False(t, suppressed)
+
+	require.NoError(t, storage.SuppressFingerprint(first.Fingerprint))
+	require.NoError(t, storage.SuppressFingerprint(first.Fingerprint))
+
+	suppressed, err = storage.IsFingerprintSuppressed(first.Fingerprint)
+	require.NoError(t, err)
+	assert.True(t, suppressed)
+
+	// Later drifts with the fingerprint are stored acknowledged
+	recurring := &Drift{EndpointID: "users", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+	require.NoError(t, storage.SaveDrift(recurring))
+	assert.True(t, recurring.Acknowledged)
+
+	retrieved, err = storage.GetDrift(recurring.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.Acknowledged)
+}
+
+func TestResolveDrift(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "users", URL: "https://api.example.com/users", Method: "GET"}))
+
+	drift := &Drift{EndpointID: "users", DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+	require.NoError(t, storage.SaveDrift(drift))
+
+	retrieved, err := storage.GetDrift(drift.ID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.ResolvedAt)
+
+	resolvedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, storage.ResolveDrift(drift.ID, resolvedAt))
+
+	retrieved, err = storage.GetDrift(drift.ID)
+	require.NoError(t, err)
+	require.NotNil(t, retrieved.ResolvedAt)
+	assert.True(t, retrieved.ResolvedAt.Equal(resolvedAt))
+
+	assert.ErrorContains(t, storage.ResolveDrift(drift.ID+1, resolvedAt), "drift not found")
+}
+
+func TestGetAlertedUnresolvedDrifts(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "users", URL: "https://api.example.com/users", Method: "GET"}))
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "orders", URL: "https://api.example.com/orders", Method: "GET"}))
+
+	now := time.Now().Truncate(time.Second)
+	save := func(endpointID string, age time.Duration, alertStatus string) *Drift {
+		drift := &Drift{EndpointID: endpointID, DetectedAt: now.Add(-age), DriftType: "value_change", Severity: "low", FieldPath: "$.a", BeforeValue: "1", AfterValue: "2"}
+		require.NoError(t, storage.SaveDrift(drift))
+		if alertStatus != "" {
+			require.NoError(t, storage.SaveAlert(&Alert{DriftID: drift.ID, AlertType: "slack", ChannelName: "team", SentAt: now, Status: alertStatus}))
+		}
+		return drift
+	}
+
+	older := save("users", 2*time.Hour, "sent")
+	newer := save("users", time.Hour, "sent")
+	save("users", 3*time.Hour, "failed")
+	save("users", 4*time.Hour, "")
+	save("orders", time.Hour, "sent")
+	resolved := save("users", 30*time.Minute, "sent")
+	require.NoError(t, storage.ResolveDrift(resolved.ID, now))
+
+	drifts, err := storage.GetAlertedUnresolvedDrifts("users", 10)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, newer.ID, drifts[0].ID)
+	assert.Equal(t, older.ID, drifts[1].ID)
+
+	drifts, err = storage.GetAlertedUnresolvedDrifts("users", 1)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, newer.ID, drifts[0].ID)
+}
+
+func TestBackfillDriftFingerprints(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	storage, err := NewSQLiteStorage(dbPath)
+	require.NoError(t, err)
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "users", URL: "https://api.example.com/users", Method: "GET"}))
+	drift := &Drift{EndpointID: "users", DriftType: "field_removed", Severity: "high", FieldPath: "$.a", BeforeValue: "1"}
+	require.NoError(t, storage.SaveDrift(drift))
+
+	// Drifts saved before fingerprints were introduced have none
+	_, err = storage.db.Exec(`UPDATE drifts SET fingerprint = NULL`)
+	require.NoError(t, err)
+	require.NoError(t, storage.Close())
+
+	storage, err = NewSQLiteStorage(dbPath)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	retrieved, err := storage.GetDrift(drift.ID)
+	require.NoError(t, err)
+	assert.Equal(t, drift.Fingerprint, retrieved.Fingerprint)
+}
+
+func TestUpdateAlert(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	endpoint := &Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET"}
+	require.NoError(t, storage.SaveEndpoint(endpoint))
+
+	drift := &Drift{EndpointID: "test-endpoint", DriftType: "field_added", Severity: "low", Description: "Field added"}
+	require.NoError(t, storage.SaveDrift(drift))
+
+	alert := &Alert{DriftID: drift.ID, AlertType: "slack", ChannelName: "alerts", Status: "buffered"}
+	require.NoError(t, storage.SaveAlert(alert))
+
+	alert.Status = "sent"
+	alert.RetryCount = 1
+	alert.SentAt = time.Now()
+	require.NoError(t, storage.UpdateAlert(alert))
+
+	alerts, err := storage.GetAlerts(AlertFilters{Status: "sent"})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alert.ID, alerts[0].ID)
+	assert.Equal(t, 1, alerts[0].RetryCount)
+
+	missing := &Alert{ID: alert.ID + 100, Status: "sent"}
+	assert.Error(t, storage.UpdateAlert(missing))
+}
+
 func TestGetDriftsWithFilters(t *testing.T) {
 	storage, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -482,6 +1115,387 @@ func TestForeignKeyConstraints(t *testing.T) {
 	assert.Contains(t, err.Error(), "FOREIGN KEY constraint failed")
 }
 
+func TestSQLiteConnectionSettings(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	storage, err := NewSQLiteStorageWithOptions(dbPath, SQLiteOptions{BusyTimeout: 2 * time.Second})
+	require.NoError(t, err)
+	defer storage.Close()
+
+	// Settings must hold on every pooled connection, not just the first
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		conn, err := storage.db.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		var foreignKeys, busyTimeout int
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys))
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout))
+		assert.Equal(t, 1, foreignKeys)
+		assert.Equal(t, 2000, busyTimeout)
+	}
+
+	assert.Equal(t, "test.db?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", sqliteDSN("test.db", DefaultBusyTimeout))
+	assert.Equal(t, "file:test.db?cache=shared&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", sqliteDSN("file:test.db?cache=shared", DefaultBusyTimeout))
+}
+
+func TestFileBodyStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	bodyDir := filepath.Join(tmpDir, "bodies")
+	storage, err := NewSQLiteStorageWithOptions(filepath.Join(tmpDir, "test.db"), SQLiteOptions{BodyDir: bodyDir})
+	require.NoError(t, err)
+	defer storage.Close()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "test-endpoint", URL: "https://api.example.com/users", Method: "GET", Config: `{}`}))
+
+	old := &MonitoringRun{EndpointID: "test-endpoint", ResponseStatus: 200, ResponseBody: `{"id": 1}`, Timestamp: time.Now().Add(-48 * time.Hour)}
+	same := &MonitoringRun{EndpointID: "test-endpoint", ResponseStatus: 200, ResponseBody: `{"id": 1}`}
+	other := &MonitoringRun{EndpointID: "test-endpoint", ResponseStatus: 200, ResponseBody: `{"id": 2}`}
+	for _, run := range []*MonitoringRun{old, same, other} {
+		require.NoError(t, storage.SaveMonitoringRun(run))
+		assert.Len(t, run.ResponseBodyHash, 64)
+	}
+	assert.Equal(t, old.ResponseBodyHash, same.ResponseBodyHash)
+
+	// Only the hash is kept in the database, and identical bodies are stored once
+	var body string
+	require.NoError(t, storage.db.QueryRow(`SELECT response_body FROM monitoring_runs WHERE id = ?`, old.ID).Scan(&body))
+	assert.Empty(t, body)
+	files, err := filepath.Glob(filepath.Join(bodyDir, "*", "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	saved, err := storage.GetMonitoringRun(old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id": 1}`, saved.ResponseBody)
+
+	history, err := storage.GetMonitoringHistory("test-endpoint", 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.ElementsMatch(t, []string{`{"id": 1}`, `{"id": 2}`}, []string{history[0].ResponseBody, history[1].ResponseBody})
+
+	// Cleaning up runs removes the bodies no remaining run refers to, unless
+	// they were stored within the grace period
+	expired := &MonitoringRun{EndpointID: "test-endpoint", ResponseStatus: 200, ResponseBody: `{"id": 3}`, Timestamp: time.Now().Add(-48 * time.Hour)}
+	require.NoError(t, storage.SaveMonitoringRun(expired))
+	files, err = filepath.Glob(filepath.Join(bodyDir, "*", "*"))
+	require.NoError(t, err)
+	longAgo := time.Now().Add(-2 * bodyPruneGrace)
+	for _, file := range files {
+		require.NoError(t, os.Chtimes(file, longAgo, longAgo))
+	}
+	recent := &MonitoringRun{EndpointID: "test-endpoint", ResponseStatus: 200, ResponseBody: `{"id": 4}`, Timestamp: time.Now().Add(-48 * time.Hour)}
+	require.NoError(t, storage.SaveMonitoringRun(recent))
+
+	deleted, err := storage.CleanupOldMonitoringRuns(time.Now().Add(-24 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	remaining, err := filepath.Glob(filepath.Join(bodyDir, "*", "*"))
+	require.NoError(t, err)
+	hashes := make([]string, 0, len(remaining))
+	for _, file := range remaining {
+		hashes = append(hashes, filepath.Base(file))
+	}
+	assert.ElementsMatch(t, []string{same.ResponseBodyHash, other.ResponseBodyHash, recent.ResponseBodyHash}, hashes)
+}
+
+func TestSharedResponseBodies(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "stable", URL: "https://api.example.com/stable", Method: "GET", Config: `{}`}))
+
+	now := time.Now()
+	full := &MonitoringRun{EndpointID: "stable", ResponseStatus: 200, ResponseBody: `{"v": 1}`, Timestamp: now.Add(-time.Minute)}
+	require.NoError(t, storage.SaveMonitoringRun(full))
+
+	hash := strings.Repeat("a", 64)
+	shared := &MonitoringRun{EndpointID: "stable", ResponseStatus: 200, ResponseBodyHash: hash, BodyRunID: full.ID, Timestamp: now}
+	require.NoError(t, storage.SaveMonitoringRun(shared))
+
+	// The shared run stores no body of its own
+	var body string
+	require.NoError(t, storage.db.QueryRow(`SELECT response_body FROM monitoring_runs WHERE id = ?`, shared.ID).Scan(&body))
+	assert.Empty(t, body)
+
+	history, err := storage.GetMonitoringHistory("stable", time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for _, run := range history {
+		assert.Equal(t, `{"v": 1}`, run.ResponseBody)
+	}
+
+	saved, err := storage.GetMonitoringRun(shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, `{"v": 1}`, saved.ResponseBody)
+	assert.Equal(t, full.ID, saved.BodyRunID)
+	assert.Equal(t, hash, saved.ResponseBodyHash)
+
+	// Trimming the run holding the body moves the body to the run sharing it
+	_, err = storage.TrimMonitoringRuns("stable", 1)
+	require.NoError(t, err)
+
+	saved, err = storage.GetMonitoringRun(shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, `{"v": 1}`, saved.ResponseBody)
+	assert.Zero(t, saved.BodyRunID)
+}
+
+func TestCleanupKeepsSharedBodies(t *testing.T) {
+	for _, bodyDir := range []string{"", "bodies"} {
+		t.Run("body dir "+bodyDir, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			options := SQLiteOptions{}
+			if bodyDir != "" {
+				options.BodyDir = filepath.Join(tmpDir, bodyDir)
+			}
+			storage, err := NewSQLiteStorageWithOptions(filepath.Join(tmpDir, "test.db"), options)
+			require.NoError(t, err)
+			defer storage.Close()
+
+			require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "stable", URL: "https://api.example.com/stable", Method: "GET", Config: `{}`}))
+
+			now := time.Now()
+			full := &MonitoringRun{EndpointID: "stable", ResponseStatus: 200, ResponseBody: `{"v": 1}`, Timestamp: now.Add(-4 * time.Minute)}
+			require.NoError(t, storage.SaveMonitoringRun(full))
+			hash := full.ResponseBodyHash
+			if hash == "" {
+				hash = strings.Repeat("a", 64)
+			}
+
+			var sharers []*MonitoringRun
+			for i := 3; i >= 1; i-- {
+				run := &MonitoringRun{EndpointID: "stable", ResponseStatus: 200, ResponseBodyHash: hash, BodyRunID: full.ID, Timestamp: now.Add(-time.Duration(i) * time.Minute)}
+				require.NoError(t, storage.SaveMonitoringRun(run))
+				sharers = append(sharers, run)
+			}
+
+			// The run holding the body and the oldest run sharing it are removed
+			cleaned, err := storage.CleanupOldMonitoringRuns(now.Add(-150 * time.Second))
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), cleaned)
+
+			anchor, err := storage.GetMonitoringRun(sharers[1].ID)
+			require.NoError(t, err)
+			assert.Equal(t, `{"v": 1}`, anchor.ResponseBody)
+			assert.Zero(t, anchor.BodyRunID)
+
+			sharer, err := storage.GetMonitoringRun(sharers[2].ID)
+			require.NoError(t, err)
+			assert.Equal(t, `{"v": 1}`, sharer.ResponseBody)
+			assert.Equal(t, sharers[1].ID, sharer.BodyRunID)
+
+			// Trimming the new holder of the body moves it again
+			_, err = storage.TrimMonitoringRuns("stable", 1)
+			require.NoError(t, err)
+
+			sharer, err = storage.GetMonitoringRun(sharers[2].ID)
+			require.NoError(t, err)
+			assert.Equal(t, `{"v": 1}`, sharer.ResponseBody)
+			assert.Zero(t, sharer.BodyRunID)
+		})
+	}
+}
+
+func TestTrimMonitoringRuns(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for _, id := range []string{"busy", "quiet"} {
+		require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: id, URL: "https://api.example.com/" + id, Method: "GET", Config: `{}`}))
+	}
+
+	now := time.Now()
+	var runs []*MonitoringRun
+	for i := 0; i < 5; i++ {
+		run := &MonitoringRun{EndpointID: "busy", ResponseStatus: 200, Timestamp: now.Add(-time.Duration(i) * time.Minute)}
+		require.NoError(t, storage.SaveMonitoringRun(run))
+		runs = append(runs, run)
+	}
+	require.NoError(t, storage.SaveMonitoringRun(&MonitoringRun{EndpointID: "quiet", ResponseStatus: 200, Timestamp: now.Add(-time.Hour)}))
+
+	trimmed, err := storage.TrimMonitoringRuns("busy", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), trimmed)
+
+	history, err := storage.GetMonitoringHistory("busy", 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, runs[0].ID, history[0].ID)
+	assert.Equal(t, runs[1].ID, history[1].ID)
+
+	history, err = storage.GetMonitoringHistory("quiet", 24*time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, history, 1, "other endpoints keep their runs")
+
+	_, err = storage.TrimMonitoringRuns("busy", -1)
+	assert.Error(t, err)
+}
+
+func TestCompactMonitoringRuns(t *testing.T) {
+	storage, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, storage.SaveEndpoint(&Endpoint{ID: "stable", URL: "https://api.example.com/stable", Method: "GET", Config: `{}`}))
+
+	now := time.Now()
+	save := func(minutesAgo int, status int, body string) *MonitoringRun {
+		run := &MonitoringRun{
+			EndpointID:     "stable",
+			ResponseStatus: status,
+			ResponseBody:   body,
+			Timestamp:      now.Add(-time.Duration(minutesAgo) * time.Minute),
+		}
+		require.NoError(t, storage.SaveMonitoringRun(run))
+		return run
+	}
+
+	first := save(60, 200, `{"ok":true}`)
+	save(50, 200, `{"ok":true}`)
+	third := save(40, 200, `{"ok":true}`)
+	save(35, 500, `{"error":"internal"}`)
+	save(30, 200, `{"ok":true}`)
+	save(5, 200, `{"ok":true}`)
+
+	// A recent run sharing the body of a run that is merged away
+	shared := &MonitoringRun{
+		EndpointID:       "stable",
+		ResponseStatus:   200,
+		ResponseBody:     `{"ok":true}`,
+		ResponseBodyHash: "shared-hash",
+		BodyRunID:        third.ID,
+		Timestamp:        now.Add(-time.Minute),
+	}
+	require.NoError(t, storage.SaveMonitoringRun(shared))
+
+	compacted, err := storage.CompactMonitoringRuns(now.Add(-10 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), compacted)
+
+	history, err := storage.GetMonitoringHistory("stable", 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+
+	merged := history[len(history)-1]
+	assert.Equal(t, first.ID, merged.ID)
+	assert.Equal(t, 3, merged.CheckCount)
+	assert.WithinDuration(t, now.Add(-40*time.Minute), merged.LastTimestamp, time.Second)
+	for _, run := range history[:len(history)-1] {
+		assert.Equal(t, 1, run.CheckCount, "runs after a different response are kept")
+	}
+
+	reloaded, err := storage.GetMonitoringRun(shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, reloaded.BodyRunID)
+	assert.Equal(t, `{"ok":true}`, reloaded.ResponseBody)
+
+	compacted, err = storage.CompactMonitoringRuns(now.Add(-10 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), compacted, "compacting again merges nothing")
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	first, err := NewSQLiteStorage(dbPath)
+	require.NoError(t, err)
+	defer first.Close()
+
+	// A second instance has its own pool and write lock, like another process
+	second, err := NewSQLiteStorage(dbPath)
+	require.NoError(t, err)
+	defer second.Close()
+
+	require.NoError(t, first.SaveEndpoint(&Endpoint{ID: "test-endpoint", URL: "https://api.example.com", Method: "GET", Config: "{}"}))
+
+	const writesPerStorage = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*writesPerStorage)
+	for i := 0; i < writesPerStorage; i++ {
+		for _, storage := range []*SQLiteStorage{first, second} {
+			wg.Add(1)
+			go func(storage *SQLiteStorage) {
+				defer wg.Done()
+				errs <- storage.SaveMonitoringRun(&MonitoringRun{
+					EndpointID:      "test-endpoint",
+					Timestamp:       time.Now(),
+					ResponseStatus:  200,
+					ResponseBody:    `{"ok": true}`,
+					ResponseHeaders: map[string]string{},
+				})
+			}(storage)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	runs, err := first.GetMonitoringHistory("test-endpoint", time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, runs, 2*writesPerStorage)
+}
+
+func TestWriteRetriesWhileLocked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	holder, err := NewSQLiteStorage(dbPath)
+	require.NoError(t, err)
+	defer holder.Close()
+
+	writer, err := NewSQLiteStorageWithOptions(dbPath, SQLiteOptions{BusyTimeout: time.Millisecond})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	// Hold the write lock from another connection
+	ctx := context.Background()
+	conn, err := holder.db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+
+	endpoint := &Endpoint{ID: "test-endpoint", URL: "https://api.example.com", Method: "GET", Config: "{}"}
+	err = writer.SaveEndpoint(endpoint)
+	require.Error(t, err)
+	assert.True(t, isLockError(err))
+
+	// The write succeeds once the lock is released during the retries
+	go func() {
+		time.Sleep(writeRetryDelay / 2)
+		_, _ = conn.ExecContext(ctx, "ROLLBACK")
+	}()
+	require.NoError(t, writer.SaveEndpoint(endpoint))
+
+	assert.False(t, isLockError(errors.New("database is locked")))
+}
+
+func TestMaintenanceRetriesWhileLocked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	holder, err := NewSQLiteStorage(dbPath)
+	require.NoError(t, err)
+	defer holder.Close()
+
+	writer, err := NewSQLiteStorageWithOptions(dbPath, SQLiteOptions{BusyTimeout: time.Millisecond})
+	require.NoError(t, err)
+	defer writer.Close()
+
+	ctx := context.Background()
+	conn, err := holder.db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+
+	// Vacuuming waits for the lock like any other write
+	go func() {
+		time.Sleep(writeRetryDelay / 2)
+		_, _ = conn.ExecContext(ctx, "ROLLBACK")
+	}()
+	require.NoError(t, writer.VacuumDatabase())
+}
+
 func TestJSONSerialization(t *testing.T) {
 	storage, cleanup := setupTestDB(t)
 	defer cleanup()
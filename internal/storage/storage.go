@@ -2,9 +2,16 @@
 package storage
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"time"
 )
 
+// ErrMonitoringRunNotFound is returned, wrapped, by GetMonitoringRun when no run
+// has the requested ID
+var ErrMonitoringRunNotFound = errors.New("monitoring run not found")
+
 // Storage defines the interface for data persistence operations
 type Storage interface {
 	SaveEndpoint(endpoint *Endpoint) error
@@ -12,13 +19,34 @@ type Storage interface {
 	ListEndpoints() ([]*Endpoint, error)
 	SaveMonitoringRun(run *MonitoringRun) error
 	GetMonitoringHistory(endpointID string, period time.Duration) ([]*MonitoringRun, error)
+	GetMonitoringHistorySample(endpointID string, period time.Duration, maxSamples int) ([]*MonitoringRun, error)
+	GetMonitoringRun(id int64) (*MonitoringRun, error)
 	SaveDrift(drift *Drift) error
+	GetDrift(id int64) (*Drift, error)
 	GetDrifts(filters DriftFilters) ([]*Drift, error)
+	GetDriftsAfter(afterID int64, limit int) ([]*Drift, error)
+	GetAlertedUnresolvedDrifts(endpointID string, limit int) ([]*Drift, error)
+	SearchDrifts(text string, limit int) ([]*Drift, error)
+	AcknowledgeDrifts(endpointID string, before time.Time) (int64, error)
+	AcknowledgeFingerprint(fingerprint string) (int64, error)
+	ResolveDrift(id int64, resolvedAt time.Time) error
+	SuppressFingerprint(fingerprint string) error
+	IsFingerprintSuppressed(fingerprint string) (bool, error)
+	GetSinkCursor(name string) (int64, error)
+	SaveSinkCursor(name string, lastDriftID int64) error
+	SaveHeartbeat(at time.Time) error
+	GetLastHeartbeat() (time.Time, error)
+	SaveMaintenanceWindow(window *MaintenanceWindow) error
+	GetActiveMaintenanceWindows(at time.Time) ([]*MaintenanceWindow, error)
+	EndMaintenanceWindows(endpointID string, at time.Time) (int64, error)
 	SaveAlert(alert *Alert) error
+	UpdateAlert(alert *Alert) error
 	GetAlerts(filters AlertFilters) ([]*Alert, error)
 
 	// Data retention and cleanup methods
 	CleanupOldMonitoringRuns(olderThan time.Time) (int64, error)
+	TrimMonitoringRuns(endpointID string, keep int) (int64, error)
+	CompactMonitoringRuns(olderThan time.Time) (int64, error)
 	CleanupOldDrifts(olderThan time.Time) (int64, error)
 	CleanupOldAlerts(olderThan time.Time) (int64, error)
 	GetDatabaseStats() (*DatabaseStats, error)
@@ -116,12 +144,83 @@ type Endpoint struct {
 type MonitoringRun struct {
 	EndpointID       string            `json:"endpoint_id"`
 	ResponseBody     string            `json:"response_body"`
-	ValidationResult string            `json:"validation_result"` // JSON-encoded ValidationResult
+	ValidationResult string            `json:"validation_result"`          // JSON-encoded ValidationResult
+	FailureCategory  string            `json:"failure_category,omitempty"` // network, timeout, connect_timeout, read_timeout, tls, dns, http or config; empty on success
+	ErrorMessage     string            `json:"error_message,omitempty"`
 	ResponseHeaders  map[string]string `json:"response_headers"`
 	Timestamp        time.Time         `json:"timestamp"`
 	ID               int64             `json:"id"`
 	ResponseTimeMs   int64             `json:"response_time_ms"`
 	ResponseStatus   int               `json:"response_status"`
+	SampleCount      int               `json:"sample_count"` // Requests taken for the check; defaults to 1
+
+	// VolatileFields lists the field and header paths that differed between the
+	// samples of the check, which change on their own rather than drift
+	VolatileFields []string `json:"volatile_fields,omitempty"`
+
+	// Server certificate of HTTPS endpoints; empty for plain HTTP or failed requests
+	TLSNotAfter    *time.Time `json:"tls_not_after,omitempty"`
+	TLSIssuer      string     `json:"tls_issuer,omitempty"`
+	TLSFingerprint string     `json:"tls_fingerprint,omitempty"` // Hex-encoded SHA-256 of the certificate
+
+	// Protocol is the negotiated HTTP version, such as "HTTP/2.0"; empty for
+	// failed requests and runs recorded before it was captured
+	Protocol         string            `json:"protocol,omitempty"`
+	ResponseTrailers map[string]string `json:"response_trailers,omitempty"`
+
+	// Phases of the request: DNS lookup, TCP connect, TLS handshake and the time
+	// from sending the request to the first response byte. Phases that did not
+	// take place, such as connecting on a reused connection, are zero.
+	DNSTimeMs     int64 `json:"dns_time_ms,omitempty"`
+	ConnectTimeMs int64 `json:"connect_time_ms,omitempty"`
+	TLSTimeMs     int64 `json:"tls_time_ms,omitempty"`
+	TTFBMs        int64 `json:"ttfb_ms,omitempty"`
+
+	// ResponseTruncated is set when the endpoint's stream read limit cut the
+	// response short, so that ResponseBody only holds a prefix of it
+	ResponseTruncated bool `json:"response_truncated,omitempty"`
+
+	// ResponseBodyHash is the SHA-256 of the response body when it is stored
+	// as a file rather than in the database; ResponseBody is loaded from it
+	ResponseBodyHash string `json:"response_body_hash,omitempty"`
+
+	// BodyRunID is the earlier run whose identical body this run shares, when
+	// its own was not stored; ResponseBodyHash then holds the hash of the body
+	// and ResponseBody is loaded from that run. Cleaning up that run moves its
+	// body to the oldest run still sharing it.
+	BodyRunID int64 `json:"body_run_id,omitempty"`
+
+	// CheckCount is the number of checks the run records: 1, or more once
+	// compaction has merged the identical runs that followed it into it.
+	// Timestamp is then the time of the first of them and LastTimestamp of
+	// the last; otherwise both are the time of the check.
+	CheckCount    int       `json:"check_count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
+// Checks returns the number of checks the run records, at least 1
+func (r *MonitoringRun) Checks() int {
+	if r.CheckCount < 1 {
+		return 1
+	}
+	return r.CheckCount
+}
+
+// Succeeded reports whether the run received a 2xx response, or completed the
+// handshake of a WebSocket endpoint with 101 Switching Protocols
+func (r *MonitoringRun) Succeeded() bool {
+	return (r.ResponseStatus >= 200 && r.ResponseStatus < 300) || r.ResponseStatus == 101
+}
+
+// successfulRuns returns the runs that succeeded and recorded no failure
+func successfulRuns(runs []*MonitoringRun) []*MonitoringRun {
+	successful := make([]*MonitoringRun, 0, len(runs))
+	for _, run := range runs {
+		if run.Succeeded() && run.FailureCategory == "" {
+			successful = append(successful, run)
+		}
+	}
+	return successful
 }
 
 // Drift represents a detected API drift
@@ -136,11 +235,64 @@ type Drift struct {
 	DetectedAt   time.Time `json:"detected_at"`
 	ID           int64     `json:"id"`
 	Acknowledged bool      `json:"acknowledged"`
+
+	// FirstDetectedAt and LastDetectedAt span the drifts recorded for the same
+	// endpoint and field path, telling a new regression from a recurring one.
+	// They are set when the drift is saved.
+	FirstDetectedAt time.Time `json:"first_detected_at"`
+	LastDetectedAt  time.Time `json:"last_detected_at"`
+
+	// Tag marks drift recorded under special circumstances, such as
+	// DriftTagMaintenance; empty for regular drift
+	Tag string `json:"tag,omitempty"`
+
+	// Fingerprint identifies the exact change: the same endpoint, field path,
+	// type and values. It is computed when the drift is saved.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// ResolvedAt is when the drift was found to be resolved, by a later check
+	// undoing the change or by the endpoint recovering; nil while it is open
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// DriftTagMaintenance tags drift detected during a maintenance window
+const DriftTagMaintenance = "maintenance"
+
+// ComputeFingerprint returns the fingerprint of the drift: the first 16 hex
+// digits of a SHA-256 hash of its endpoint, field path, type and values
+func (d *Drift) ComputeFingerprint() string {
+	hash := sha256.New()
+	for _, part := range []string{d.EndpointID, d.FieldPath, d.DriftType, d.BeforeValue, d.AfterValue} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// MaintenanceWindow is a period, such as a deploy, during which drift is still
+// recorded but tagged DriftTagMaintenance and not alerted on
+type MaintenanceWindow struct {
+	StartedAt  time.Time `json:"started_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	EndpointID string    `json:"endpoint_id,omitempty"` // Empty for a window covering every endpoint
+	ID         int64     `json:"id"`
+
+	// AutoAcknowledge records the drift detected during the window as acknowledged
+	AutoAcknowledge bool `json:"auto_acknowledge"`
+}
+
+// Covers reports whether the window applies to an endpoint at the given time
+func (w *MaintenanceWindow) Covers(endpointID string, at time.Time) bool {
+	if w.EndpointID != "" && w.EndpointID != endpointID {
+		return false
+	}
+	return !at.Before(w.StartedAt) && at.Before(w.EndsAt)
 }
 
 // DriftFilters represents filters for querying drifts
 type DriftFilters struct {
 	EndpointID   string
+	FieldPath    string // exact field path, such as "$.data.price"
 	Severity     string
 	StartTime    time.Time
 	EndTime      time.Time
@@ -174,3 +326,8 @@ type AlertFilters struct {
 func NewStorage(dbPath string) (Storage, error) {
 	return NewSQLiteStorage(dbPath)
 }
+
+// NewStorageWithOptions creates a new SQLite storage instance with connection settings
+func NewStorageWithOptions(dbPath string, options SQLiteOptions) (Storage, error) {
+	return NewSQLiteStorageWithOptions(dbPath, options)
+}
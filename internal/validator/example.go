@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"github.com/go-openapi/spec"
+)
+
+// maxExampleDepth limits example generation for recursive schemas
+const maxExampleDepth = 10
+
+// GenerateExample builds an example value for a schema. Explicit examples are
+// preferred, then defaults and enum values, falling back to a placeholder for
+// the schema type.
+func GenerateExample(schema *spec.Schema) interface{} {
+	return generateExample(schema, 0)
+}
+
+// generateExample builds an example value for a schema at the given nesting depth
+func generateExample(schema *spec.Schema, depth int) interface{} {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for i := range schema.AllOf {
+			if part, ok := generateExample(&schema.AllOf[i], depth+1).(map[string]interface{}); ok {
+				for key, value := range part {
+					merged[key] = value
+				}
+			}
+		}
+		for key, value := range generateObjectExample(schema, depth) {
+			merged[key] = value
+		}
+		return merged
+	}
+
+	switch {
+	case schema.Type.Contains("object") || (len(schema.Type) == 0 && len(schema.Properties) > 0):
+		return generateObjectExample(schema, depth)
+	case schema.Type.Contains("array"):
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return []interface{}{}
+		}
+		return []interface{}{generateExample(schema.Items.Schema, depth+1)}
+	case schema.Type.Contains("string"):
+		return stringExample(schema.Format)
+	case schema.Type.Contains("integer"):
+		if schema.Minimum != nil {
+			return int64(*schema.Minimum)
+		}
+		return 0
+	case schema.Type.Contains("number"):
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+		return 0.0
+	case schema.Type.Contains("boolean"):
+		return false
+	default:
+		return nil
+	}
+}
+
+// generateObjectExample builds an example object from schema properties
+func generateObjectExample(schema *spec.Schema, depth int) map[string]interface{} {
+	object := make(map[string]interface{}, len(schema.Properties))
+	for name, property := range schema.Properties {
+		property := property
+		object[name] = generateExample(&property, depth+1)
+	}
+	return object
+}
+
+// stringExample returns a placeholder string for a string format
+func stringExample(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,219 @@
+package validator
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// maxRequiredPathDepth limits schema traversal for recursive definitions
+const maxRequiredPathDepth = 16
+
+// FindOperation finds the operation in a specification matching an HTTP method
+// and request path. Path templates such as /users/{id} match any single segment.
+// When several paths match, literal segments take precedence over templated
+// ones, so /users/me is preferred over /users/{id}.
+func FindOperation(swagger *spec.Swagger, method, requestPath string) *spec.Operation {
+	if swagger == nil || swagger.Paths == nil {
+		return nil
+	}
+
+	requestPath = "/" + strings.Trim(requestPath, "/")
+	if basePath := strings.TrimRight(swagger.BasePath, "/"); basePath != "" && strings.HasPrefix(requestPath, basePath) {
+		requestPath = "/" + strings.Trim(strings.TrimPrefix(requestPath, basePath), "/")
+	}
+
+	var templates []string
+	for template := range swagger.Paths.Paths {
+		if matchPathTemplate(template, requestPath) {
+			templates = append(templates, template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return precedesPathTemplate(templates[i], templates[j])
+	})
+
+	for _, template := range templates {
+		if operation := operationForMethod(swagger.Paths.Paths[template], method); operation != nil {
+			return operation
+		}
+	}
+
+	return nil
+}
+
+// precedesPathTemplate orders path templates matching the same request path: at
+// the first segment where one is literal and the other templated, the literal
+// one comes first. Templates that do not differ so are ordered by name.
+func precedesPathTemplate(a, b string) bool {
+	aParts := strings.Split(strings.Trim(a, "/"), "/")
+	bParts := strings.Split(strings.Trim(b, "/"), "/")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aTemplated, bTemplated := isPathParameter(aParts[i]), isPathParameter(bParts[i])
+		if aTemplated != bTemplated {
+			return bTemplated
+		}
+	}
+
+	return a < b
+}
+
+// isPathParameter reports whether a path template segment is a parameter such as {id}
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// matchPathTemplate reports whether a request path matches an OpenAPI path template
+func matchPathTemplate(template, requestPath string) bool {
+	templateParts := strings.Split(strings.Trim(template, "/"), "/")
+	requestParts := strings.Split(strings.Trim(requestPath, "/"), "/")
+
+	if len(templateParts) != len(requestParts) {
+		return false
+	}
+
+	for i, part := range templateParts {
+		if isPathParameter(part) {
+			continue
+		}
+		if part != requestParts[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// operationForMethod returns the operation of a path item for an HTTP method
+func operationForMethod(pathItem spec.PathItem, method string) *spec.Operation {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return pathItem.Get
+	case http.MethodPost:
+		return pathItem.Post
+	case http.MethodPut:
+		return pathItem.Put
+	case http.MethodPatch:
+		return pathItem.Patch
+	case http.MethodDelete:
+		return pathItem.Delete
+	case http.MethodHead:
+		return pathItem.Head
+	case http.MethodOptions:
+		return pathItem.Options
+	default:
+		return nil
+	}
+}
+
+// SuccessResponse selects the response an operation returns on success: the
+// lowest 2xx response, otherwise the default response
+func SuccessResponse(operation *spec.Operation) (int, *spec.Response) {
+	if operation == nil || operation.Responses == nil {
+		return http.StatusOK, nil
+	}
+
+	var statusCodes []int
+	for statusCode := range operation.Responses.StatusCodeResponses {
+		if statusCode >= 200 && statusCode < 300 {
+			statusCodes = append(statusCodes, statusCode)
+		}
+	}
+	sort.Ints(statusCodes)
+
+	if len(statusCodes) > 0 {
+		response := operation.Responses.StatusCodeResponses[statusCodes[0]]
+		return statusCodes[0], &response
+	}
+
+	return http.StatusOK, operation.Responses.Default
+}
+
+// RequiredResponsePaths returns the JSONPath of every required field in the
+// success response schema of an operation. Array items are written as [*].
+func RequiredResponsePaths(operation *spec.Operation) []string {
+	_, response := SuccessResponse(operation)
+	if response == nil {
+		return nil
+	}
+	return RequiredSchemaPaths(response.Schema)
+}
+
+// ResponseSchema returns the schema of the success response of an operation,
+// or nil if it has none
+func ResponseSchema(operation *spec.Operation) *spec.Schema {
+	_, response := SuccessResponse(operation)
+	if response == nil {
+		return nil
+	}
+	return response.Schema
+}
+
+// RequiredSchemaPaths returns the JSONPath of every required field in a
+// response schema. Array items are written as [*].
+func RequiredSchemaPaths(schema *spec.Schema) []string {
+	var paths []string
+	collectRequiredPaths(schema, "$", 0, &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+// collectRequiredPaths walks a schema and records the paths of required properties
+func collectRequiredPaths(schema *spec.Schema, path string, depth int, paths *[]string) {
+	if schema == nil || depth > maxRequiredPathDepth {
+		return
+	}
+
+	for _, name := range schema.Required {
+		*paths = append(*paths, path+"."+name)
+	}
+
+	for name, property := range schema.Properties {
+		property := property
+		collectRequiredPaths(&property, path+"."+name, depth+1, paths)
+	}
+
+	if schema.Items != nil && schema.Items.Schema != nil {
+		collectRequiredPaths(schema.Items.Schema, path+"[*]", depth+1, paths)
+	}
+}
+
+// EnumResponseValues returns the allowed values of every enum-constrained field
+// in the success response schema of an operation, keyed by JSONPath. Array items
+// are written as [*].
+func EnumResponseValues(operation *spec.Operation) map[string][]interface{} {
+	_, response := SuccessResponse(operation)
+	if response == nil {
+		return nil
+	}
+
+	values := make(map[string][]interface{})
+	collectEnumValues(response.Schema, "$", 0, values)
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// collectEnumValues walks a schema and records the enum of each constrained field
+func collectEnumValues(schema *spec.Schema, path string, depth int, values map[string][]interface{}) {
+	if schema == nil || depth > maxRequiredPathDepth {
+		return
+	}
+
+	if len(schema.Enum) > 0 {
+		values[path] = schema.Enum
+	}
+
+	for name, property := range schema.Properties {
+		property := property
+		collectEnumValues(&property, path+"."+name, depth+1, values)
+	}
+
+	if schema.Items != nil && schema.Items.Schema != nil {
+		collectEnumValues(schema.Items.Schema, path+"[*]", depth+1, values)
+	}
+}
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOperation(t *testing.T) {
+	swagger, err := NewValidator().LoadSpec("testdata/complex-api.yaml")
+	require.NoError(t, err)
+
+	assert.NotNil(t, FindOperation(swagger, "GET", "/v2/products"))
+	assert.NotNil(t, FindOperation(swagger, "get", "/v2/products/abc-123/"))
+	assert.Nil(t, FindOperation(swagger, "DELETE", "/v2/products"))
+	assert.Nil(t, FindOperation(swagger, "GET", "/v2/orders"))
+	assert.Nil(t, FindOperation(nil, "GET", "/v2/products"))
+}
+
+func TestFindOperationPrefersLiteralSegments(t *testing.T) {
+	operation := func(id string) *spec.PathItem {
+		return &spec.PathItem{PathItemProps: spec.PathItemProps{
+			Get: &spec.Operation{OperationProps: spec.OperationProps{ID: id}},
+		}}
+	}
+	swagger := &spec.Swagger{SwaggerProps: spec.SwaggerProps{Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+		"/users/{id}":          *operation("getUser"),
+		"/users/me":            *operation("getMe"),
+		"/users/{id}/settings": *operation("getSettings"),
+		"/{tenant}/settings":   *operation("getTenantSettings"),
+		"/users/{id}/{page}":   *operation("getUserPage"),
+	}}}}
+
+	// Map iteration order varies, so look each path up repeatedly
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, "getMe", FindOperation(swagger, "GET", "/users/me").ID)
+		assert.Equal(t, "getUser", FindOperation(swagger, "GET", "/users/42").ID)
+		assert.Equal(t, "getSettings", FindOperation(swagger, "GET", "/users/42/settings").ID)
+		assert.Equal(t, "getUserPage", FindOperation(swagger, "GET", "/users/42/profile").ID)
+		assert.Equal(t, "getTenantSettings", FindOperation(swagger, "GET", "/acme/settings").ID)
+	}
+}
+
+func TestRequiredResponsePaths(t *testing.T) {
+	swagger, err := NewValidator().LoadSpec("testdata/complex-api.yaml")
+	require.NoError(t, err)
+
+	paths := RequiredResponsePaths(FindOperation(swagger, "GET", "/v2/products"))
+	assert.Contains(t, paths, "$.products")
+	assert.Contains(t, paths, "$.pagination")
+	assert.Contains(t, paths, "$.products[*].id")
+
+	assert.Empty(t, RequiredResponsePaths(nil))
+}
+
+func TestEnumResponseValues(t *testing.T) {
+	itemSchema := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: spec.StringOrArray{"object"},
+		Properties: map[string]spec.Schema{
+			"state": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Enum: []interface{}{"open", "closed"}}},
+		},
+	}}
+	operation := &spec.Operation{OperationProps: spec.OperationProps{
+		Responses: &spec.Responses{ResponsesProps: spec.ResponsesProps{
+			StatusCodeResponses: map[int]spec.Response{
+				200: {ResponseProps: spec.ResponseProps{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"object"},
+					Properties: map[string]spec.Schema{
+						"status": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Enum: []interface{}{"active", "inactive"}}},
+						"name":   {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}},
+						"items": {SchemaProps: spec.SchemaProps{
+							Type:  spec.StringOrArray{"array"},
+							Items: &spec.SchemaOrArray{Schema: &itemSchema},
+						}},
+					},
+				}}}},
+			},
+		}},
+	}}
+
+	assert.Equal(t, map[string][]interface{}{
+		"$.status":         {"active", "inactive"},
+		"$.items[*].state": {"open", "closed"},
+	}, EnumResponseValues(operation))
+
+	swagger, err := NewValidator().LoadSpec("testdata/complex-api.yaml")
+	require.NoError(t, err)
+	assert.Nil(t, EnumResponseValues(FindOperation(swagger, "GET", "/v2/products")))
+	assert.Nil(t, EnumResponseValues(nil))
+}
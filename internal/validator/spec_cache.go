@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// specCache holds loaded and expanded specifications by absolute file path, so
+// that a spec is read, expanded and validated once rather than on every check.
+// An entry is reloaded when the modification time or size of its file changes.
+// It is safe for concurrent use; concurrent loads of the same file share a
+// single load.
+type specCache struct {
+	entries map[string]*specCacheEntry
+	mu      sync.Mutex
+}
+
+// specCacheEntry is a specification loaded, or being loaded, from one version
+// of a file
+type specCacheEntry struct {
+	modTime time.Time
+	swagger *spec.Swagger
+	err     error
+	done    chan struct{} // Closed once swagger and err are set
+	size    int64
+}
+
+// loadedSpecs caches the specifications loaded by every validator
+var loadedSpecs = &specCache{entries: make(map[string]*specCacheEntry)}
+
+// get returns the specification cached for the file at path, calling load to
+// read it if the file is new or has changed. Failed loads are not cached, so
+// they are retried on the next call.
+func (c *specCache) get(path string, info os.FileInfo, load func() (*spec.Swagger, error)) (*spec.Swagger, error) {
+	c.mu.Lock()
+	entry, exists := c.entries[path]
+	if exists && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		c.mu.Unlock()
+		<-entry.done
+		return entry.swagger, entry.err
+	}
+
+	entry = &specCacheEntry{modTime: info.ModTime(), size: info.Size(), done: make(chan struct{})}
+	c.entries[path] = entry
+	c.mu.Unlock()
+
+	entry.swagger, entry.err = load()
+	close(entry.done)
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[path] == entry {
+			delete(c.entries, path)
+		}
+		c.mu.Unlock()
+	}
+
+	return entry.swagger, entry.err
+}
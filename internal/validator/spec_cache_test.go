@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSpecReloadsChangedFiles(t *testing.T) {
+	source, err := os.ReadFile("testdata/simple-api.json")
+	require.NoError(t, err)
+
+	specFile := filepath.Join(t.TempDir(), "api.json")
+	require.NoError(t, os.WriteFile(specFile, source, 0o600))
+
+	first, err := NewValidator().LoadSpec(specFile)
+	require.NoError(t, err)
+	second, err := NewValidator().LoadSpec(specFile)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	// Rewriting the file, even with the same content, reloads it
+	require.NoError(t, os.WriteFile(specFile, source, 0o600))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(specFile, later, later))
+
+	reloaded, err := NewValidator().LoadSpec(specFile)
+	require.NoError(t, err)
+	assert.NotSame(t, first, reloaded)
+}
+
+func TestSpecCacheSharesConcurrentLoads(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "api.json")
+	require.NoError(t, os.WriteFile(specFile, []byte(`{}`), 0o600))
+	info, err := os.Stat(specFile)
+	require.NoError(t, err)
+
+	cache := &specCache{entries: make(map[string]*specCacheEntry)}
+	var loads atomic.Int32
+	load := func() (*spec.Swagger, error) {
+		loads.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &spec.Swagger{}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*spec.Swagger, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = cache.get(specFile, info, load)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+	for _, result := range results {
+		assert.Same(t, results[0], result)
+	}
+}
+
+func TestSpecCacheRetriesFailedLoads(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "api.json")
+	require.NoError(t, os.WriteFile(specFile, []byte(`{}`), 0o600))
+	info, err := os.Stat(specFile)
+	require.NoError(t, err)
+
+	cache := &specCache{entries: make(map[string]*specCacheEntry)}
+	_, err = cache.get(specFile, info, func() (*spec.Swagger, error) {
+		return nil, fmt.Errorf("remote reference unavailable")
+	})
+	require.Error(t, err)
+
+	swagger, err := cache.get(specFile, info, func() (*spec.Swagger, error) {
+		return &spec.Swagger{}, nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, swagger)
+}
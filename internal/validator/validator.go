@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/go-openapi/errors"
@@ -23,6 +25,7 @@ type Validator interface {
 	LoadSpec(specFile string) (*spec.Swagger, error)
 	SetValidationMode(mode ValidationMode)
 	GetValidationMode() ValidationMode
+	SetHeaderPatterns(patterns map[string]string) error
 	CompareResponses(previous, current *Response) ([]FieldDiff, error)
 }
 
@@ -96,7 +99,8 @@ const (
 
 // OpenAPIValidator implements the Validator interface
 type OpenAPIValidator struct {
-	mode ValidationMode
+	mode           ValidationMode
+	headerPatterns map[string]*regexp.Regexp // by header name
 }
 
 // NewValidator creates a new OpenAPI validator
@@ -116,16 +120,39 @@ func (v *OpenAPIValidator) GetValidationMode() ValidationMode {
 	return v.mode
 }
 
-// LoadSpec loads an OpenAPI specification from a file
+// SetHeaderPatterns sets regular expressions that response header values must
+// match, by header name. Headers that are missing are left to the spec's header
+// checks.
+func (v *OpenAPIValidator) SetHeaderPatterns(patterns map[string]string) error {
+	compiled := make(map[string]*regexp.Regexp, len(patterns))
+	for header, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for header '%s': %w", header, err)
+		}
+		compiled[header] = re
+	}
+
+	v.headerPatterns = compiled
+	return nil
+}
+
+// LoadSpec loads an OpenAPI specification from a file. Specifications are
+// cached by file, so a file is only loaded again once it changes; the returned
+// specification is shared and must not be modified.
 func (v *OpenAPIValidator) LoadSpec(specFile string) (*spec.Swagger, error) {
 	if specFile == "" {
 		return nil, fmt.Errorf("spec file path cannot be empty")
 	}
 
 	// Check if file exists
-	if _, err := os.Stat(specFile); os.IsNotExist(err) {
+	info, err := os.Stat(specFile)
+	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("spec file does not exist: %s", specFile)
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file %s: %w", specFile, err)
+	}
 
 	// Get absolute path
 	absPath, err := filepath.Abs(specFile)
@@ -133,6 +160,13 @@ func (v *OpenAPIValidator) LoadSpec(specFile string) (*spec.Swagger, error) {
 		return nil, fmt.Errorf("failed to get absolute path for spec file: %w", err)
 	}
 
+	return loadedSpecs.get(absPath, info, func() (*spec.Swagger, error) {
+		return loadSpecFile(specFile, absPath)
+	})
+}
+
+// loadSpecFile loads, expands and validates the specification at absPath
+func loadSpecFile(specFile, absPath string) (*spec.Swagger, error) {
 	// Load the specification
 	doc, err := loads.Spec(absPath)
 	if err != nil {
@@ -199,6 +233,7 @@ func (v *OpenAPIValidator) ValidateResponse(response *Response, operation *spec.
 
 	// Validate response headers
 	v.validateResponseHeaders(response.Headers, responseSpec.Headers, result)
+	v.validateHeaderPatterns(response.Headers, result)
 
 	return result, nil
 }
@@ -245,10 +280,15 @@ func (v *OpenAPIValidator) validateResponseBody(body []byte, schema *spec.Schema
 		result.Valid = false
 		for _, err := range validationResult.Errors {
 			if validationErr, ok := err.(*errors.Validation); ok {
+				errorType := "schema_validation"
+				if validationErr.Code() == errors.EnumFailCode {
+					// A value outside the declared enum is a contract violation of its own
+					errorType = "enum_violation"
+				}
 				result.Errors = append(result.Errors, ValidationError{
 					Field:   extractFieldFromError(validationErr),
 					Message: err.Error(),
-					Type:    "schema_validation",
+					Type:    errorType,
 					Path:    extractPathFromError(validationErr),
 				})
 			} else {
@@ -302,6 +342,31 @@ func (v *OpenAPIValidator) validateResponseHeaders(headers http.Header, expected
 	}
 }
 
+// validateHeaderPatterns checks present headers against the configured patterns
+func (v *OpenAPIValidator) validateHeaderPatterns(headers http.Header, result *ValidationResult) {
+	names := make([]string, 0, len(v.headerPatterns))
+	for name := range v.headerPatterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, headerName := range names {
+		pattern := v.headerPatterns[headerName]
+		headerValue := headers.Get(headerName)
+		if headerValue == "" || pattern.MatchString(headerValue) {
+			continue
+		}
+
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   headerName,
+			Message: fmt.Sprintf("header '%s' value '%s' does not match pattern '%s'", headerName, headerValue, pattern),
+			Type:    "header_pattern_mismatch",
+			Path:    fmt.Sprintf("$.headers.%s", headerName),
+		})
+	}
+}
+
 // validateHeaderValue validates a header value against its specification
 func (v *OpenAPIValidator) validateHeaderValue(headerValue string, headerSpec *spec.Header, headerName string, result *ValidationResult) {
 	// Basic type validation
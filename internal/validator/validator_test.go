@@ -202,6 +202,101 @@ func TestValidateResponse_InvalidResponse(t *testing.T) {
 	assert.NotEmpty(t, result.Errors)
 }
 
+func TestValidateResponse_EnumViolation(t *testing.T) {
+	validator := NewValidator()
+
+	operation := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Responses: &spec.Responses{
+				ResponsesProps: spec.ResponsesProps{
+					StatusCodeResponses: map[int]spec.Response{
+						200: {
+							ResponseProps: spec.ResponseProps{
+								Description: "Success",
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: spec.StringOrArray{"object"},
+										Properties: map[string]spec.Schema{
+											"id": {
+												SchemaProps: spec.SchemaProps{
+													Type: spec.StringOrArray{"integer"},
+												},
+											},
+											"status": {
+												SchemaProps: spec.SchemaProps{
+													Type: spec.StringOrArray{"string"},
+													Enum: []interface{}{"active", "inactive"},
+												},
+											},
+										},
+										Required: []string{"id"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := validator.ValidateResponse(&Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		Body:       []byte(`{"id": "1", "status": "archived"}`),
+	}, operation)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+
+	errorTypes := map[string]string{}
+	for _, validationErr := range result.Errors {
+		errorTypes[validationErr.Path] = validationErr.Type
+	}
+	assert.Equal(t, "enum_violation", errorTypes["$.status"])
+	assert.Equal(t, "schema_validation", errorTypes["$.id"])
+
+	result, err = validator.ValidateResponse(&Response{
+		StatusCode: 200,
+		Headers:    http.Header{},
+		Body:       []byte(`{"id": 1, "status": "inactive"}`),
+	}, operation)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+}
+
+func TestValidateResponse_HeaderPatterns(t *testing.T) {
+	validator := NewValidator()
+	require.NoError(t, validator.SetHeaderPatterns(map[string]string{"cache-control": `max-age=\d+`}))
+
+	operation := &spec.Operation{
+		OperationProps: spec.OperationProps{
+			Responses: &spec.Responses{
+				ResponsesProps: spec.ResponsesProps{
+					StatusCodeResponses: map[int]spec.Response{
+						200: {ResponseProps: spec.ResponseProps{Description: "Success"}},
+					},
+				},
+			},
+		},
+	}
+
+	validate := func(headers http.Header) *ValidationResult {
+		result, err := validator.ValidateResponse(&Response{StatusCode: 200, Headers: headers}, operation)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.True(t, validate(http.Header{"Cache-Control": {"public, max-age=60"}}).Valid)
+	assert.True(t, validate(http.Header{}).Valid)
+
+	result := validate(http.Header{"Cache-Control": {"no-store"}})
+	assert.False(t, result.Valid)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "header_pattern_mismatch", result.Errors[0].Type)
+
+	assert.Error(t, validator.SetHeaderPatterns(map[string]string{"etag": "("}))
+}
+
 func TestValidateResponse_UndefinedStatusCode(t *testing.T) {
 	validator := NewValidator()
 
@@ -589,3 +684,35 @@ func BenchmarkCompareResponses(b *testing.B) {
 		}
 	}
 }
+
+func TestGenerateExample(t *testing.T) {
+	minimum := 5.0
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"id":      {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Format: "uuid"}},
+				"count":   {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}, Minimum: &minimum}},
+				"status":  {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Enum: []interface{}{"active", "inactive"}}},
+				"enabled": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"boolean"}, Default: true}},
+				"name":    {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}, SwaggerSchemaProps: spec.SwaggerSchemaProps{Example: "Widget"}},
+				"tags": {SchemaProps: spec.SchemaProps{
+					Type:  spec.StringOrArray{"array"},
+					Items: &spec.SchemaOrArray{Schema: &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}}}},
+				}},
+			},
+		},
+	}
+
+	example := GenerateExample(schema)
+	assert.Equal(t, map[string]interface{}{
+		"id":      "00000000-0000-0000-0000-000000000000",
+		"count":   int64(5),
+		"status":  "active",
+		"enabled": true,
+		"name":    "Widget",
+		"tags":    []interface{}{"string"},
+	}, example)
+
+	assert.Nil(t, GenerateExample(nil))
+}